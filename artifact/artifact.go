@@ -0,0 +1,306 @@
+// Package artifact downloads the proving artifacts of a circuit (proving and
+// verifying keys, SRS, constraint systems) from HTTPS or IPFS URLs, verifies
+// them against their expected hash and optional signature, and caches them in
+// a local directory, so that deployments don't need to ship them.
+//
+// Artifacts are stored in the cache under their SHA-256 digest, once
+// verified: a cached file is never downloaded nor hashed again.
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDigestMismatch is returned when the downloaded artifact doesn't have the
+	// expected digest.
+	ErrDigestMismatch = errors.New("artifact digest mismatch")
+
+	// ErrInvalidSignature is returned when the signature of the artifact digest
+	// is missing or invalid.
+	ErrInvalidSignature = errors.New("invalid artifact signature")
+
+	// ErrTooLarge is returned when the artifact is larger than the maximum size.
+	ErrTooLarge = errors.New("artifact is too large")
+)
+
+// Artifact describes a remote file and its expected content.
+type Artifact struct {
+	// URL of the artifact, https://host/path or ipfs://CID[/path].
+	URL string
+
+	// SHA256 is the hex encoded SHA-256 digest of the artifact.
+	SHA256 string
+
+	// Signature is the ed25519 signature of the SHA-256 digest (raw bytes), by
+	// the key set with WithPublicKey.
+	Signature []byte
+}
+
+// Option defines option for altering the behavior of the Manager.
+type Option func(*Config) error
+
+// Config is the configuration of a Manager, with the options applied.
+type Config struct {
+	MaxSize      int64             // maximum size of an artifact, in bytes
+	MaxCacheSize int64             // maximum size of the cache, in bytes, 0 for no limit
+	IPFSGateway  string            // base URL of the IPFS gateway
+	PublicKey    ed25519.PublicKey // key signing the artifacts, if any
+	Client       *http.Client
+}
+
+// WithMaxSize sets the maximum size of an artifact. The default is 8GiB.
+func WithMaxSize(n int64) Option {
+	return func(cfg *Config) error {
+		if n < 1 {
+			return fmt.Errorf("invalid maximum size %d", n)
+		}
+		cfg.MaxSize = n
+		return nil
+	}
+}
+
+// WithMaxCacheSize sets the maximum size of the cache; the least recently used
+// artifacts are removed when it is exceeded. The cache isn't limited by
+// default.
+func WithMaxCacheSize(n int64) Option {
+	return func(cfg *Config) error {
+		if n < 1 {
+			return fmt.Errorf("invalid maximum cache size %d", n)
+		}
+		cfg.MaxCacheSize = n
+		return nil
+	}
+}
+
+// WithIPFSGateway sets the gateway used to download the ipfs:// URLs, for
+// example a local node at "http://127.0.0.1:8080/ipfs/". The default is
+// "https://ipfs.io/ipfs/".
+func WithIPFSGateway(gateway string) Option {
+	return func(cfg *Config) error {
+		if _, err := url.Parse(gateway); err != nil {
+			return err
+		}
+		cfg.IPFSGateway = strings.TrimSuffix(gateway, "/") + "/"
+		return nil
+	}
+}
+
+// WithPublicKey requires the artifacts to be signed by the ed25519 key pk.
+func WithPublicKey(pk ed25519.PublicKey) Option {
+	return func(cfg *Config) error {
+		if len(pk) != ed25519.PublicKeySize {
+			return errors.New("invalid ed25519 public key")
+		}
+		cfg.PublicKey = pk
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the downloads. The default is
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *Config) error {
+		cfg.Client = client
+		return nil
+	}
+}
+
+// Manager downloads and caches artifacts.
+type Manager struct {
+	dir  string
+	cfg  Config
+	lock sync.Mutex
+}
+
+// NewManager returns a Manager caching the artifacts in dir, which is created
+// if needed.
+func NewManager(dir string, opts ...Option) (*Manager, error) {
+	cfg := Config{
+		MaxSize:     8 << 30,
+		IPFSGateway: "https://ipfs.io/ipfs/",
+		Client:      http.DefaultClient,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Manager{dir: dir, cfg: cfg}, nil
+}
+
+// Fetch returns the path of the artifact in the cache, downloading and
+// verifying it if it isn't cached.
+func (m *Manager) Fetch(ctx context.Context, a Artifact) (string, error) {
+	digest, err := hex.DecodeString(a.SHA256)
+	if err != nil || len(digest) != sha256.Size {
+		return "", fmt.Errorf("invalid sha256 digest %q", a.SHA256)
+	}
+	if m.cfg.PublicKey != nil && !ed25519.Verify(m.cfg.PublicKey, digest, a.Signature) {
+		return "", ErrInvalidSignature
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	path := filepath.Join(m.dir, hex.EncodeToString(digest))
+	if _, err := os.Stat(path); err == nil {
+		// mark as recently used
+		now := time.Now()
+		return path, os.Chtimes(path, now, now)
+	}
+
+	if err := m.download(ctx, a.URL, digest, path); err != nil {
+		return "", err
+	}
+	if err := m.evict(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Open returns the artifact opened for reading, see Fetch.
+func (m *Manager) Open(ctx context.Context, a Artifact) (*os.File, error) {
+	path, err := m.Fetch(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// ReadFrom fetches the artifact and reads it into v, for example a
+// groth16.ProvingKey.
+func (m *Manager) ReadFrom(ctx context.Context, a Artifact, v io.ReaderFrom) error {
+	f, err := m.Open(ctx, a)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = v.ReadFrom(f)
+	return err
+}
+
+// download writes the artifact at rawURL to path, if its digest matches.
+func (m *Manager) download(ctx context.Context, rawURL string, digest []byte, path string) error {
+	u, err := m.resolve(rawURL)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	res, err := m.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", rawURL, res.Status)
+	}
+	if res.ContentLength > m.cfg.MaxSize {
+		return ErrTooLarge
+	}
+
+	// the artifact is written next to its final path, and renamed once verified
+	tmp, err := os.CreateTemp(m.dir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(res.Body, m.cfg.MaxSize+1))
+	if err != nil {
+		return err
+	}
+	if n > m.cfg.MaxSize {
+		return ErrTooLarge
+	}
+	if !bytes.Equal(h.Sum(nil), digest) {
+		return ErrDigestMismatch
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// resolve returns the HTTP URL of rawURL.
+func (m *Manager) resolve(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "https":
+		return rawURL, nil
+	case "ipfs":
+		return m.cfg.IPFSGateway + u.Host + u.Path, nil
+	default:
+		return "", fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+}
+
+// evict removes the least recently used artifacts until the cache fits its
+// maximum size. The artifact at keep is never removed.
+func (m *Manager) evict(keep string) error {
+	if m.cfg.MaxCacheSize == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return err
+	}
+	var (
+		files []os.FileInfo
+		size  int64
+	)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, info)
+		size += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, f := range files {
+		if size <= m.cfg.MaxCacheSize {
+			break
+		}
+		path := filepath.Join(m.dir, f.Name())
+		if path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		size -= f.Size()
+	}
+	return nil
+}
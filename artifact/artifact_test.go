@@ -0,0 +1,145 @@
+package artifact_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark/artifact"
+	"github.com/stretchr/testify/require"
+)
+
+func newArtifact(url string, content []byte) artifact.Artifact {
+	h := sha256.Sum256(content)
+	return artifact.Artifact{URL: url, SHA256: hex.EncodeToString(h[:])}
+}
+
+func TestFetch(t *testing.T) {
+	assert := require.New(t)
+
+	files := map[string][]byte{
+		"/pk":                []byte("proving key"),
+		"/vk":                []byte("verifying key"),
+		"/ipfs/bafyCID/srs":  []byte("structured reference string"),
+		"/ipfs/bafyCID/none": nil,
+	}
+	var nbRequests int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&nbRequests, 1)
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	m, err := artifact.NewManager(dir,
+		artifact.WithHTTPClient(srv.Client()),
+		artifact.WithIPFSGateway(srv.URL+"/ipfs"),
+		artifact.WithMaxSize(20),
+	)
+	assert.NoError(err)
+	ctx := context.Background()
+
+	// download, then cache hit
+	pk := newArtifact(srv.URL+"/pk", files["/pk"])
+	for i := 0; i < 2; i++ {
+		path, err := m.Fetch(ctx, pk)
+		assert.NoError(err)
+		content, err := os.ReadFile(path)
+		assert.NoError(err)
+		assert.Equal(files["/pk"], content)
+	}
+	assert.EqualValues(1, atomic.LoadInt32(&nbRequests))
+
+	// digest mismatch, nothing is cached
+	_, err = m.Fetch(ctx, newArtifact(srv.URL+"/vk", []byte("other key")))
+	assert.ErrorIs(err, artifact.ErrDigestMismatch)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+
+	// size limit
+	_, err = m.Fetch(ctx, newArtifact("ipfs://bafyCID/srs", files["/ipfs/bafyCID/srs"]))
+	assert.ErrorIs(err, artifact.ErrTooLarge)
+
+	// ipfs
+	_, err = m.Fetch(ctx, newArtifact("ipfs://bafyCID/none", nil))
+	assert.NoError(err)
+
+	// errors
+	_, err = m.Fetch(ctx, newArtifact(srv.URL+"/missing", []byte("missing")))
+	assert.Error(err)
+	_, err = m.Fetch(ctx, newArtifact(strings.Replace(srv.URL, "https", "ftp", 1)+"/vk", files["/vk"]))
+	assert.Error(err)
+	_, err = m.Fetch(ctx, artifact.Artifact{URL: srv.URL + "/vk", SHA256: "00"})
+	assert.Error(err)
+}
+
+func TestSignature(t *testing.T) {
+	assert := require.New(t)
+
+	content := []byte("verifying key")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NoError(err)
+	m, err := artifact.NewManager(t.TempDir(), artifact.WithHTTPClient(srv.Client()), artifact.WithPublicKey(pub))
+	assert.NoError(err)
+
+	a := newArtifact(srv.URL+"/vk", content)
+	_, err = m.Fetch(context.Background(), a)
+	assert.ErrorIs(err, artifact.ErrInvalidSignature)
+
+	digest, err := hex.DecodeString(a.SHA256)
+	assert.NoError(err)
+	a.Signature = ed25519.Sign(priv, digest)
+	_, err = m.Fetch(context.Background(), a)
+	assert.NoError(err)
+}
+
+func TestEviction(t *testing.T) {
+	assert := require.New(t)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	m, err := artifact.NewManager(dir, artifact.WithHTTPClient(srv.Client()), artifact.WithMaxCacheSize(4))
+	assert.NoError(err)
+
+	a := newArtifact(srv.URL+"/a", []byte("/a"))
+	b := newArtifact(srv.URL+"/b", []byte("/b"))
+	c := newArtifact(srv.URL+"/c", []byte("/c"))
+	for i, x := range []artifact.Artifact{a, b, c} {
+		path, err := m.Fetch(context.Background(), x)
+		assert.NoError(err)
+		used := time.Now().Add(time.Duration(i-3) * time.Hour)
+		assert.NoError(os.Chtimes(path, used, used))
+	}
+
+	// a is the least recently used
+	_, err = os.Stat(filepath.Join(dir, a.SHA256))
+	assert.True(os.IsNotExist(err))
+	for _, x := range []artifact.Artifact{b, c} {
+		_, err = os.Stat(filepath.Join(dir, x.SHA256))
+		assert.NoError(err)
+	}
+}
@@ -16,7 +16,12 @@
 package backend
 
 import (
+	"context"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/constraint/solver"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ID represent a unique ID for a proving scheme
@@ -48,6 +53,19 @@ func (id ID) String() string {
 	}
 }
 
+// ErrCircuitMismatch is returned when proving with a key generated for another
+// constraint system (see constraint.Digest).
+var ErrCircuitMismatch = errors.New("proving key doesn't match the constraint system")
+
+// ErrUnsupportedCurve is returned by the entry points of the proof systems for
+// a curve they are not implemented for.
+var ErrUnsupportedCurve = errors.New("unsupported curve")
+
+// ErrBackendMismatch is returned by the entry points of the proof systems for
+// objects of another proof system or curve, for instance a R1CS given to PLONK
+// or a PLONK proof given to the Groth16 verifier.
+var ErrBackendMismatch = errors.New("backend mismatch")
+
 // ProverOption defines option for altering the behavior of the prover in
 // Prove, ReadAndProve and IsSolved methods. See the descriptions of functions
 // returning instances of this type for implemented options.
@@ -56,12 +74,23 @@ type ProverOption func(*ProverConfig) error
 // ProverConfig is the configuration for the prover with the options applied.
 type ProverConfig struct {
 	SolverOpts []solver.Option
+
+	// PoolBuffers is set by WithBufferPooling.
+	PoolBuffers bool
+
+	// Tuning is set by WithTuningProfile, and defaults to the profile set by
+	// Calibrate, LoadTuningProfile or SetTuningProfile.
+	Tuning TuningProfile
+
+	// TraceContext and TracerProvider are set by WithTracing.
+	TraceContext   context.Context
+	TracerProvider trace.TracerProvider
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
 // applied.
 func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
-	opt := ProverConfig{}
+	opt := ProverConfig{Tuning: tuningProfile}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
 			return ProverConfig{}, err
@@ -77,3 +106,97 @@ func WithSolverOptions(solverOpts ...solver.Option) ProverOption {
 		return nil
 	}
 }
+
+// WithBufferPooling reuses the large scratch vectors of the prover across proofs,
+// instead of allocating them for each proof. Repeated proofs of the same circuit
+// then stop triggering large garbage collection cycles between proofs, at the cost
+// of keeping the vectors in memory while the pool isn't collected.
+func WithBufferPooling() ProverOption {
+	return func(opt *ProverConfig) error {
+		opt.PoolBuffers = true
+		return nil
+	}
+}
+
+// WithTracing traces the prover phases (solve, FFT, MSM, ...) with OpenTelemetry
+// spans created from tp, children of the span in ctx (if any). If tp is nil, the
+// tracer provider set with tracing.SetTracerProvider is used.
+func WithTracing(ctx context.Context, tp trace.TracerProvider) ProverOption {
+	return func(opt *ProverConfig) error {
+		opt.TraceContext = ctx
+		opt.TracerProvider = tp
+		return nil
+	}
+}
+
+// SetupOption defines option for altering the behavior of the Setup of a backend.
+type SetupOption func(*SetupConfig) error
+
+// SetupConfig is the configuration for the setup with the options applied.
+type SetupConfig struct {
+	// LagrangeSRS is set by WithLagrangeSRS.
+	LagrangeSRS kzg.SRS
+}
+
+// NewSetupConfig returns a default SetupConfig with given setup options opts
+// applied.
+func NewSetupConfig(opts ...SetupOption) (SetupConfig, error) {
+	var cfg SetupConfig
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return SetupConfig{}, err
+		}
+	}
+	return cfg, nil
+}
+
+// WithLagrangeSRS gives the PLONK setup the KZG SRS in Lagrange basis of the size
+// of the circuit (see plonk.NewLagrangeSRS), which must derive from the same SRS as
+// the canonical one. The commitments to the circuit polynomials are then computed
+// from their evaluations, before the polynomials are converted to canonical basis.
+func WithLagrangeSRS(srs kzg.SRS) SetupOption {
+	return func(cfg *SetupConfig) error {
+		if srs == nil {
+			return errors.New("nil lagrange srs")
+		}
+		cfg.LagrangeSRS = srs
+		return nil
+	}
+}
+
+// VerifierOption defines option for altering the behavior of the verifier. See
+// the descriptions of functions returning instances of this type for
+// implemented options.
+type VerifierOption func(*VerifierConfig) error
+
+// VerifierConfig is the configuration for the verifier with the options applied.
+type VerifierConfig struct {
+	// SubgroupChecks is set by WithSubgroupChecks, true by default.
+	SubgroupChecks bool
+}
+
+// NewVerifierConfig returns a default VerifierConfig with given verifier options
+// opts applied.
+func NewVerifierConfig(opts ...VerifierOption) (VerifierConfig, error) {
+	cfg := VerifierConfig{SubgroupChecks: true}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return VerifierConfig{}, err
+		}
+	}
+	return cfg, nil
+}
+
+// WithSubgroupChecks enforces (the default) or skips the check that the points
+// of the proof are in the correct subgroup when verifying it.
+//
+// Proofs decoded with ReadFrom are already checked: the checks may then be
+// skipped to save time. Proofs decoded with UnsafeReadFrom, or built from
+// untrusted coordinates, must be verified with the checks, as crafted points out
+// of the subgroup can make an invalid proof pass.
+func WithSubgroupChecks(enforce bool) VerifierOption {
+	return func(cfg *VerifierConfig) error {
+		cfg.SubgroupChecks = enforce
+		return nil
+	}
+}
@@ -0,0 +1,334 @@
+// Package envelope defines a self-describing container of a proof, for systems
+// passing proofs around (queues, storage, RPCs) apart from their verifying key.
+//
+// An [Envelope] holds the serialized proof and public witness, with the curve,
+// the backend and the digest of the circuit they were produced for, and the time
+// the proof was produced. It is encoded with [Envelope.MarshalBinary] and decoded
+// with [Envelope.UnmarshalBinary], and [Envelope.Verify] checks it against a
+// verifying key. A [Registry] dispatches the envelopes to the verifying keys of
+// several circuits and versions of circuits.
+package envelope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// An envelope is encoded as
+//
+//	magic [8]byte | formatVersion uint16 | curve uint16 | backend uint16 | digest [32]byte | timestamp int64 |
+//	len(proof) uint32 | proof | len(publicWitness) uint32 | publicWitness | checksum uint32
+//
+// where timestamp is in nanoseconds since the Unix epoch (0 if unset) and
+// checksum is the CRC-32 (Castagnoli) of all the preceding bytes. All integers
+// are big-endian encoded.
+const formatVersion = 1
+
+const (
+	fixedSize    = 8 + 3*2 + len(constraint.Digest{}) + 8
+	checksumSize = 4
+)
+
+var (
+	magic      = [8]byte{0x89, 'G', 'N', 'A', 'R', 'K', 'E', 'V'}
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// ErrInvalidEnvelope is returned when decoding malformed data.
+var ErrInvalidEnvelope = errors.New("invalid envelope")
+
+// Proof is a proof of any of the supported backends (groth16.Proof,
+// plonk.Proof).
+type Proof interface {
+	io.WriterTo
+}
+
+// VerifyingKey is a verifying key of any of the supported backends
+// (groth16.VerifyingKey, plonk.VerifyingKey).
+type VerifyingKey interface {
+	io.WriterTo
+	NbPublicWitness() int
+	CircuitDigest() constraint.Digest
+}
+
+// Envelope is a proof with the public witness and the description of the circuit
+// it was produced for.
+type Envelope struct {
+	Curve   ecc.ID
+	Backend backend.ID
+	// CircuitDigest is the fingerprint of the constraint system of the proof
+	// (see constraint.Digest), zero if unknown.
+	CircuitDigest constraint.Digest
+	// Timestamp is the time the proof was produced, zero if unset.
+	Timestamp time.Time
+	// Proof is the serialized proof, as written by its WriteTo method.
+	Proof []byte
+	// PublicWitness is the serialized public witness, as written by its WriteTo
+	// method.
+	PublicWitness []byte
+}
+
+// New returns the envelope of proof, produced with the proving key matching vk,
+// and of its public witness, timestamped with the current time. publicWitness
+// may also be a full witness, of which only the public part is kept.
+func New(proof Proof, vk VerifyingKey, publicWitness witness.Witness) (*Envelope, error) {
+	curve, b, err := keyType(vk)
+	if err != nil {
+		return nil, err
+	}
+	if proofCurve, proofBackend, err := proofType(proof); err != nil {
+		return nil, err
+	} else if proofCurve != curve || proofBackend != b {
+		return nil, fmt.Errorf("%s proof on %s for a %s key on %s", proofBackend, proofCurve, b, curve)
+	}
+
+	w, err := publicWitness.Public()
+	if err != nil {
+		return nil, err
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return nil, err
+	}
+	if ins.NbPublic() != vk.NbPublicWitness() {
+		return nil, fmt.Errorf("%w: %d public elements, the key expects %d", witness.ErrInvalidWitness, ins.NbPublic(), vk.NbPublicWitness())
+	}
+
+	e := &Envelope{
+		Curve:         curve,
+		Backend:       b,
+		CircuitDigest: vk.CircuitDigest(),
+		Timestamp:     time.Now(),
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("write proof: %w", err)
+	}
+	e.Proof = buf.Bytes()
+	if e.PublicWitness, err = w.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("write public witness: %w", err)
+	}
+	return e, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if uint64(len(e.Proof)) > 1<<32-1 || uint64(len(e.PublicWitness)) > 1<<32-1 {
+		return nil, errors.New("envelope too large")
+	}
+	data := make([]byte, fixedSize, fixedSize+4+len(e.Proof)+4+len(e.PublicWitness)+checksumSize)
+	copy(data, magic[:])
+	binary.BigEndian.PutUint16(data[8:], formatVersion)
+	binary.BigEndian.PutUint16(data[10:], uint16(e.Curve))
+	binary.BigEndian.PutUint16(data[12:], uint16(e.Backend))
+	copy(data[14:], e.CircuitDigest[:])
+	var timestamp int64
+	if !e.Timestamp.IsZero() {
+		timestamp = e.Timestamp.UnixNano()
+	}
+	binary.BigEndian.PutUint64(data[fixedSize-8:], uint64(timestamp))
+
+	data = appendUint32(data, uint32(len(e.Proof)))
+	data = append(data, e.Proof...)
+	data = appendUint32(data, uint32(len(e.PublicWitness)))
+	data = append(data, e.PublicWitness...)
+	return appendUint32(data, crc32.Checksum(data, castagnoli)), nil
+}
+
+func appendUint32(data []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(data, buf[:]...)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. data must hold a single
+// envelope. The proof and the public witness are not decoded, see
+// [Envelope.Verify].
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) < fixedSize+checksumSize || !bytes.Equal(data[:8], magic[:]) {
+		return ErrInvalidEnvelope
+	}
+	if v := binary.BigEndian.Uint16(data[8:]); v != formatVersion {
+		return fmt.Errorf("%w: unsupported format version %d", ErrInvalidEnvelope, v)
+	}
+	body, sum := data[:len(data)-checksumSize], data[len(data)-checksumSize:]
+	if crc32.Checksum(body, castagnoli) != binary.BigEndian.Uint32(sum) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidEnvelope)
+	}
+
+	var res Envelope
+	res.Curve = ecc.ID(binary.BigEndian.Uint16(data[10:]))
+	res.Backend = backend.ID(binary.BigEndian.Uint16(data[12:]))
+	if !contains(ecc.Implemented(), res.Curve) {
+		return fmt.Errorf("%w: unknown curve %d", ErrInvalidEnvelope, uint16(res.Curve))
+	}
+	if !contains(backend.Implemented(), res.Backend) {
+		return fmt.Errorf("%w: unknown backend %d", ErrInvalidEnvelope, uint16(res.Backend))
+	}
+	copy(res.CircuitDigest[:], data[14:])
+	if timestamp := int64(binary.BigEndian.Uint64(data[fixedSize-8:])); timestamp != 0 {
+		res.Timestamp = time.Unix(0, timestamp)
+	}
+
+	rest := body[fixedSize:]
+	var ok bool
+	if res.Proof, rest, ok = readBytes(rest); !ok {
+		return fmt.Errorf("%w: truncated proof", ErrInvalidEnvelope)
+	}
+	if res.PublicWitness, rest, ok = readBytes(rest); !ok {
+		return fmt.Errorf("%w: truncated public witness", ErrInvalidEnvelope)
+	}
+	if len(rest) != 0 {
+		return fmt.Errorf("%w: %d unexpected bytes", ErrInvalidEnvelope, len(rest))
+	}
+	*e = res
+	return nil
+}
+
+// readBytes reads a length-prefixed byte slice from data, and returns it with the
+// remaining data.
+func readBytes(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(n) > uint64(len(data)) {
+		return nil, nil, false
+	}
+	return append([]byte(nil), data[:n]...), data[n:], true
+}
+
+func contains[T comparable](s []T, v T) bool {
+	for i := range s {
+		if s[i] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Witness decodes the public witness of the envelope.
+func (e *Envelope) Witness() (witness.Witness, error) {
+	w, err := witness.New(e.Curve.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := readExactly(w.ReadFrom, e.PublicWitness); err != nil {
+		return nil, fmt.Errorf("read public witness: %w", err)
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return nil, err
+	}
+	if ins.NbSecret() != 0 {
+		return nil, fmt.Errorf("%w: the witness has secret elements", witness.ErrInvalidWitness)
+	}
+	return w, nil
+}
+
+// Verify verifies the proof of the envelope with vk. The curve and the backend
+// of the envelope must be those of vk, and its circuit digest that of vk unless
+// either is zero; otherwise the error wraps backend.ErrCircuitMismatch.
+//
+// The points of the proof are checked to be in the correct subgroup, as Verify
+// is meant for envelopes from untrusted sources.
+func (e *Envelope) Verify(vk VerifyingKey) error {
+	curve, b, err := keyType(vk)
+	if err != nil {
+		return err
+	}
+	if e.Curve != curve || e.Backend != b {
+		return fmt.Errorf("%w: %s envelope on %s for a %s key on %s", backend.ErrCircuitMismatch, e.Backend, e.Curve, b, curve)
+	}
+	if digest := vk.CircuitDigest(); !digest.IsZero() && !e.CircuitDigest.IsZero() && digest != e.CircuitDigest {
+		return fmt.Errorf("%w: envelope for circuit %s, key for circuit %s", backend.ErrCircuitMismatch, e.CircuitDigest, digest)
+	}
+
+	w, err := e.Witness()
+	if err != nil {
+		return err
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return err
+	}
+	if ins.NbPublic() != vk.NbPublicWitness() {
+		return fmt.Errorf("%w: %d public elements, the key expects %d", witness.ErrInvalidWitness, ins.NbPublic(), vk.NbPublicWitness())
+	}
+
+	switch b {
+	case backend.GROTH16:
+		proof, err := groth16.NewProofE(curve)
+		if err != nil {
+			return err
+		}
+		// ReadFrom checks the points of the proof
+		if err := readExactly(proof.ReadFrom, e.Proof); err != nil {
+			return fmt.Errorf("read proof: %w", err)
+		}
+		return groth16.Verify(proof, vk.(groth16.VerifyingKey), w, backend.WithSubgroupChecks(false))
+	case backend.PLONK:
+		proof, err := plonk.NewProofE(curve)
+		if err != nil {
+			return err
+		}
+		if err := readExactly(proof.ReadFrom, e.Proof); err != nil {
+			return fmt.Errorf("read proof: %w", err)
+		}
+		return plonk.Verify(proof, vk.(plonk.VerifyingKey), w)
+	default:
+		panic("unreachable")
+	}
+}
+
+// readExactly reads an object from data with readFrom. data must hold the object
+// only.
+func readExactly(readFrom func(io.Reader) (int64, error), data []byte) error {
+	n, err := readFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return fmt.Errorf("%d unexpected bytes after the object", int64(len(data))-n)
+	}
+	return nil
+}
+
+// keyType returns the curve and the backend of vk.
+func keyType(vk VerifyingKey) (ecc.ID, backend.ID, error) {
+	switch vk.(type) {
+	case *groth16_bn254.VerifyingKey:
+		return ecc.BN254, backend.GROTH16, nil
+	case *plonk_bn254.VerifyingKey:
+		return ecc.BN254, backend.PLONK, nil
+	default:
+		return ecc.UNKNOWN, backend.UNKNOWN, fmt.Errorf("unsupported verifying key type %T", vk)
+	}
+}
+
+// proofType returns the curve and the backend of proof.
+func proofType(proof Proof) (ecc.ID, backend.ID, error) {
+	switch proof.(type) {
+	case *groth16_bn254.Proof:
+		return ecc.BN254, backend.GROTH16, nil
+	case *plonk_bn254.Proof:
+		return ecc.BN254, backend.PLONK, nil
+	default:
+		return ecc.UNKNOWN, backend.UNKNOWN, fmt.Errorf("unsupported proof type %T", proof)
+	}
+}
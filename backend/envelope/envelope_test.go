@@ -0,0 +1,146 @@
+package envelope_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/envelope"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type envelopeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *envelopeCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type otherCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *otherCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &envelopeCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&envelopeCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// the full witness is reduced to its public part
+	e, err := envelope.New(proof, vk, w)
+	assert.NoError(err)
+	assert.Equal(ecc.BN254, e.Curve)
+	assert.Equal(backend.GROTH16, e.Backend)
+	assert.Equal(ccs.Digest(), e.CircuitDigest)
+	assert.False(e.Timestamp.IsZero())
+
+	data, err := e.MarshalBinary()
+	assert.NoError(err)
+	var decoded envelope.Envelope
+	assert.NoError(decoded.UnmarshalBinary(data))
+	assert.True(e.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(e.Proof, decoded.Proof)
+	assert.Equal(e.PublicWitness, decoded.PublicWitness)
+	assert.NoError(decoded.Verify(vk))
+
+	public, err := decoded.Witness()
+	assert.NoError(err)
+	ins, err := witness.Inspect(public)
+	assert.NoError(err)
+	assert.Equal(1, ins.NbPublic())
+	assert.Equal(0, ins.NbSecret())
+
+	// wrong public input
+	wrong, err := frontend.NewWitness(&envelopeCircuit{Y: 4}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	e2, err := envelope.New(proof, vk, wrong)
+	assert.NoError(err)
+	assert.Error(e2.Verify(vk))
+
+	// key of another circuit
+	ccsOther, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &otherCircuit{})
+	assert.NoError(err)
+	_, vkOther, err := groth16.Setup(ccsOther)
+	assert.NoError(err)
+	assert.ErrorIs(decoded.Verify(vkOther), backend.ErrCircuitMismatch)
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &envelopeCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&envelopeCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	e, err := envelope.New(proof, vk, w)
+	assert.NoError(err)
+	assert.Equal(backend.PLONK, e.Backend)
+	data, err := e.MarshalBinary()
+	assert.NoError(err)
+	var decoded envelope.Envelope
+	assert.NoError(decoded.UnmarshalBinary(data))
+	assert.NoError(decoded.Verify(vk))
+
+	// a groth16 key doesn't verify a plonk envelope
+	ccsR1CS, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &envelopeCircuit{})
+	assert.NoError(err)
+	_, vkGroth16, err := groth16.Setup(ccsR1CS)
+	assert.NoError(err)
+	assert.ErrorIs(decoded.Verify(vkGroth16), backend.ErrCircuitMismatch)
+	_, err = envelope.New(proof, vkGroth16, w)
+	assert.Error(err)
+}
+
+func TestUnmarshal(t *testing.T) {
+	assert := require.New(t)
+
+	e := envelope.Envelope{
+		Curve:         ecc.BN254,
+		Backend:       backend.GROTH16,
+		Proof:         []byte{1, 2, 3},
+		PublicWitness: []byte{4, 5},
+	}
+	data, err := e.MarshalBinary()
+	assert.NoError(err)
+	var decoded envelope.Envelope
+	assert.NoError(decoded.UnmarshalBinary(data))
+	assert.Equal(e, decoded)
+
+	// every truncation and every corrupted byte is detected
+	for i := range data {
+		assert.ErrorIs(decoded.UnmarshalBinary(data[:i]), envelope.ErrInvalidEnvelope, "truncated at %d", i)
+		corrupted := append([]byte(nil), data...)
+		corrupted[i] ^= 1
+		assert.ErrorIs(decoded.UnmarshalBinary(corrupted), envelope.ErrInvalidEnvelope, "corrupted at %d", i)
+	}
+	assert.ErrorIs(decoded.UnmarshalBinary(append(data, 0)), envelope.ErrInvalidEnvelope)
+}
@@ -0,0 +1,121 @@
+package envelope
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+)
+
+// ErrUnknownKey is returned by Registry.Verify when no verifying key of the
+// registry matches the circuit digest of an envelope.
+var ErrUnknownKey = errors.New("no verifying key for the circuit digest")
+
+// Registry holds the verifying keys of the circuits a verifier accepts proofs
+// for, several per circuit, and verifies an envelope with the key of its
+// circuit digest. During a rolling upgrade of a circuit, the keys of the old
+// and the new versions are both registered, so that the proofs of the provers
+// not yet upgraded are still accepted, until the old key is removed.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	keys     map[constraint.Digest]registryEntry
+	versions map[string][]constraint.Digest // digests per circuit, in the order they were added
+}
+
+type registryEntry struct {
+	circuit string
+	vk      VerifyingKey
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		keys:     make(map[constraint.Digest]registryEntry),
+		versions: make(map[string][]constraint.Digest),
+	}
+}
+
+// Add registers vk as a version of the circuit named circuit, identified by
+// its circuit digest. The digest must be set, and not already registered.
+func (r *Registry) Add(circuit string, vk VerifyingKey) error {
+	if _, _, err := keyType(vk); err != nil {
+		return err
+	}
+	digest := vk.CircuitDigest()
+	if digest.IsZero() {
+		return errors.New("the verifying key has no circuit digest")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.keys[digest]; ok {
+		return fmt.Errorf("circuit digest %s is already registered for %q", digest, e.circuit)
+	}
+	r.keys[digest] = registryEntry{circuit: circuit, vk: vk}
+	r.versions[circuit] = append(r.versions[circuit], digest)
+	return nil
+}
+
+// Remove unregisters the key of digest, typically once the provers of an old
+// version of its circuit are upgraded. It returns false if digest isn't
+// registered.
+func (r *Registry) Remove(digest constraint.Digest) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.keys[digest]
+	if !ok {
+		return false
+	}
+	delete(r.keys, digest)
+	versions := r.versions[e.circuit]
+	for i := range versions {
+		if versions[i] == digest {
+			versions = append(versions[:i:i], versions[i+1:]...)
+			break
+		}
+	}
+	if len(versions) == 0 {
+		delete(r.versions, e.circuit)
+	} else {
+		r.versions[e.circuit] = versions
+	}
+	return true
+}
+
+// Key returns the verifying key of digest and the name of its circuit.
+func (r *Registry) Key(digest constraint.Digest) (vk VerifyingKey, circuit string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.keys[digest]
+	return e.vk, e.circuit, ok
+}
+
+// Versions returns the digests of the keys registered for circuit, in the
+// order they were added.
+func (r *Registry) Versions(circuit string) []constraint.Digest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]constraint.Digest(nil), r.versions[circuit]...)
+}
+
+// Verify verifies the envelope e, which must be a proof of circuit, with the
+// key registered for its circuit digest (see Envelope.Verify). The error wraps
+// ErrUnknownKey if no key is registered for the digest, and
+// backend.ErrCircuitMismatch if the key is registered for another circuit, so
+// that a valid proof of a circuit isn't accepted for another one.
+func (r *Registry) Verify(circuit string, e *Envelope) error {
+	if e.CircuitDigest.IsZero() {
+		return fmt.Errorf("%w: the envelope has no circuit digest", ErrUnknownKey)
+	}
+	vk, registered, ok := r.Key(e.CircuitDigest)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, e.CircuitDigest)
+	}
+	if registered != circuit {
+		return fmt.Errorf("%w: envelope for circuit %q, expected %q", backend.ErrCircuitMismatch, registered, circuit)
+	}
+	return e.Verify(vk)
+}
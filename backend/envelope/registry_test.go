@@ -0,0 +1,61 @@
+package envelope_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/envelope"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := require.New(t)
+
+	// two versions of the circuit "square", the second one computing X³
+	newEnvelope := func(circuit, assignment frontend.Circuit) (*envelope.Envelope, groth16.VerifyingKey) {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+		e, err := envelope.New(proof, vk, w)
+		assert.NoError(err)
+		return e, vk
+	}
+	e1, vk1 := newEnvelope(&envelopeCircuit{}, &envelopeCircuit{X: 3, Y: 9})
+	e2, vk2 := newEnvelope(&otherCircuit{}, &otherCircuit{X: 3, Y: 27})
+
+	r := envelope.NewRegistry()
+	assert.NoError(r.Add("square", vk1))
+	assert.ErrorIs(r.Verify("square", e2), envelope.ErrUnknownKey)
+	assert.NoError(r.Add("square", vk2))
+	assert.Error(r.Add("cube", vk2), "digest registered twice")
+	assert.Equal([]constraint.Digest{vk1.CircuitDigest(), vk2.CircuitDigest()}, r.Versions("square"))
+
+	assert.NoError(r.Verify("square", e1))
+	assert.NoError(r.Verify("square", e2))
+	assert.ErrorIs(r.Verify("cube", e1), backend.ErrCircuitMismatch)
+
+	// the old version is retired
+	assert.True(r.Remove(vk1.CircuitDigest()))
+	assert.False(r.Remove(vk1.CircuitDigest()))
+	assert.ErrorIs(r.Verify("square", e1), envelope.ErrUnknownKey)
+	assert.NoError(r.Verify("square", e2))
+	assert.Equal([]constraint.Digest{vk2.CircuitDigest()}, r.Versions("square"))
+	vk, circuit, ok := r.Key(vk2.CircuitDigest())
+	assert.True(ok)
+	assert.Equal("square", circuit)
+	assert.Equal(vk2, vk)
+
+	// an envelope without digest can't be dispatched
+	e2.CircuitDigest = constraint.Digest{}
+	assert.ErrorIs(r.Verify("square", e2), envelope.ErrUnknownKey)
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !gnark_no_bn254
+
+package groth16
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+)
+
+func init() {
+	Register(ecc.BN254, bn254Backend{})
+}
+
+// bn254Backend is the implementation of Groth16 over BN254.
+type bn254Backend struct{}
+
+func (bn254Backend) NewCS() constraint.ConstraintSystem {
+	return &cs_bn254.R1CS{}
+}
+
+func (bn254Backend) NewProvingKey() ProvingKey {
+	return &groth16_bn254.ProvingKey{}
+}
+
+func (bn254Backend) NewVerifyingKey() VerifyingKey {
+	return &groth16_bn254.VerifyingKey{}
+}
+
+func (bn254Backend) NewProof() Proof {
+	return &groth16_bn254.Proof{}
+}
+
+func (bn254Backend) Setup(r1cs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	_r1cs, ok := r1cs.(*cs_bn254.R1CS)
+	if !ok {
+		return nil, nil, errMismatch("constraint system", r1cs)
+	}
+	var pk groth16_bn254.ProvingKey
+	var vk groth16_bn254.VerifyingKey
+	if err := groth16_bn254.Setup(_r1cs, &pk, &vk); err != nil {
+		return nil, nil, err
+	}
+	return &pk, &vk, nil
+}
+
+func (bn254Backend) DevSetup(r1cs constraint.ConstraintSystem, seed []byte) (ProvingKey, VerifyingKey, error) {
+	_r1cs, ok := r1cs.(*cs_bn254.R1CS)
+	if !ok {
+		return nil, nil, errMismatch("constraint system", r1cs)
+	}
+	var pk groth16_bn254.ProvingKey
+	var vk groth16_bn254.VerifyingKey
+	if err := groth16_bn254.DevSetup(_r1cs, &pk, &vk, seed); err != nil {
+		return nil, nil, err
+	}
+	return &pk, &vk, nil
+}
+
+func (bn254Backend) DummySetup(r1cs constraint.ConstraintSystem) (ProvingKey, error) {
+	_r1cs, ok := r1cs.(*cs_bn254.R1CS)
+	if !ok {
+		return nil, errMismatch("constraint system", r1cs)
+	}
+	var pk groth16_bn254.ProvingKey
+	if err := groth16_bn254.DummySetup(_r1cs, &pk); err != nil {
+		return nil, err
+	}
+	return &pk, nil
+}
+
+func (bn254Backend) Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	_r1cs, ok := r1cs.(*cs_bn254.R1CS)
+	if !ok {
+		return nil, errMismatch("constraint system", r1cs)
+	}
+	_pk, ok := pk.(*groth16_bn254.ProvingKey)
+	if !ok {
+		return nil, errMismatch("proving key", pk)
+	}
+	return groth16_bn254.Prove(_r1cs, _pk, fullWitness, opts...)
+}
+
+func (bn254Backend) Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+	_proof, ok := proof.(*groth16_bn254.Proof)
+	if !ok {
+		return errMismatch("proof", proof)
+	}
+	_vk, ok := vk.(*groth16_bn254.VerifyingKey)
+	if !ok {
+		return errMismatch("verifying key", vk)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return witness.ErrInvalidWitness
+	}
+	return groth16_bn254.Verify(_proof, _vk, w, opts...)
+}
+
+func (bn254Backend) EncodeCalldata(proof Proof, publicWitness witness.Witness) ([]byte, error) {
+	_proof, ok := proof.(*groth16_bn254.Proof)
+	if !ok {
+		return nil, errMismatch("proof", proof)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	return _proof.EncodeCalldata(w)
+}
+
+func (bn254Backend) DecodeCalldata(calldata []byte) (Proof, witness.Witness, error) {
+	proof := &groth16_bn254.Proof{}
+	public, err := proof.DecodeCalldata(calldata)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err := newPublicWitnessBN254(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+func (bn254Backend) EncodeCairoCalldata(proof Proof, publicWitness witness.Witness) ([]*big.Int, error) {
+	_proof, ok := proof.(*groth16_bn254.Proof)
+	if !ok {
+		return nil, errMismatch("proof", proof)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	return _proof.EncodeCairoCalldata(w)
+}
+
+func (bn254Backend) DecodeCairoCalldata(calldata []*big.Int) (Proof, witness.Witness, error) {
+	proof := &groth16_bn254.Proof{}
+	public, err := proof.DecodeCairoCalldata(calldata)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err := newPublicWitnessBN254(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+func (bn254Backend) ReadShardedProvingKey(dir string) (ShardedProvingKey, error) {
+	pk, err := groth16_bn254.ReadShardedProvingKey(dir)
+	if err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+func (bn254Backend) ProveSharded(r1cs constraint.ConstraintSystem, pk ShardedProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	_r1cs, ok := r1cs.(*cs_bn254.R1CS)
+	if !ok {
+		return nil, errMismatch("constraint system", r1cs)
+	}
+	_pk, ok := pk.(*groth16_bn254.ShardedProvingKey)
+	if !ok {
+		return nil, errMismatch("proving key", pk)
+	}
+	return groth16_bn254.ProveSharded(_r1cs, _pk, fullWitness, opts...)
+}
+
+// newPublicWitnessBN254 returns the public witness of the values of public.
+func newPublicWitnessBN254(public fr_bn254.Vector) (witness.Witness, error) {
+	values := make(chan any)
+	go func() {
+		defer close(values)
+		for i := range public {
+			values <- public[i]
+		}
+	}()
+	return newPublicWitness(ecc.BN254, len(public), values)
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// loopCounter is the NAF decomposition of 6x₀+2, the scalar of the optimal ate
+// Miller loop of bn254, as in curve.MillerLoop
+var loopCounter [66]int8
+
+func init() {
+	optimalAteLoop, _ := new(big.Int).SetString("29793968203157093288", 10)
+	ecc.NafDecomposition(optimalAteLoop, loopCounter[:])
+}
+
+// pairingLines are the lines of the Miller loop of a fixed point Q of G2. They only
+// depend on Q, so a Miller loop with Q only has to evaluate them at the point of G1
+// instead of computing the multiples of Q again (see millerLoopLines).
+//
+// A line is stored as the sparse element of GT it multiplies the accumulator of the
+// loop by: its coefficients r0, r1 and r2 are in C0.B0, C1.B0 and C1.B1, r0 and r1
+// being multiplied by the coordinates of the point of G1 on evaluation.
+type pairingLines struct {
+	double []curve.GT // line of the doubling step of each iteration
+	add    []curve.GT // lines of the addition steps of the non-zero digits, then of π(Q) and -π²(Q)
+}
+
+// newPairingLines returns the lines of the Miller loop of q, which mustn't be the
+// point at infinity.
+func newPairingLines(q *curve.G2Affine) *pairingLines {
+	nbAdd := 2
+	for i := len(loopCounter) - 3; i >= 0; i-- {
+		if loopCounter[i] != 0 {
+			nbAdd++
+		}
+	}
+	lines := &pairingLines{
+		double: make([]curve.GT, len(loopCounter)-1),
+		add:    make([]curve.GT, 0, nbAdd),
+	}
+
+	var qNeg curve.G2Affine
+	qNeg.Neg(q)
+	p := g2Proj{X: q.X, Y: q.Y}
+	p.Z.SetOne()
+
+	var l curve.GT
+	for i := len(loopCounter) - 2; i >= 0; i-- {
+		p.doubleStep(&lines.double[len(loopCounter)-2-i])
+		if i == len(loopCounter)-2 {
+			// the first iteration only doubles, see curve.MillerLoop
+			continue
+		}
+		switch loopCounter[i] {
+		case 1:
+			p.addMixedStep(&l, q)
+			lines.add = append(lines.add, l)
+		case -1:
+			p.addMixedStep(&l, &qNeg)
+			lines.add = append(lines.add, l)
+		}
+	}
+
+	// Q1 = π(Q), Q2 = -π²(Q)
+	var q1, q2 curve.G2Affine
+	q1.X.Conjugate(&q.X).MulByNonResidue1Power2(&q1.X)
+	q1.Y.Conjugate(&q.Y).MulByNonResidue1Power3(&q1.Y)
+	q2.X.MulByNonResidue2Power2(&q.X)
+	q2.Y.MulByNonResidue2Power3(&q.Y).Neg(&q2.Y)
+
+	p.addMixedStep(&l, &q1)
+	lines.add = append(lines.add, l)
+	p.addMixedStep(&l, &q2)
+	lines.add = append(lines.add, l)
+
+	return lines
+}
+
+// millerLoopLines computes ∏ᵢ MillerLoop(Pᵢ, Qᵢ) like curve.MillerLoop, Qᵢ being the
+// point lines[i] were computed for.
+func millerLoopLines(P []curve.G1Affine, lines []*pairingLines) curve.GT {
+	// filter infinity points
+	p := make([]curve.G1Affine, 0, len(P))
+	l := make([]*pairingLines, 0, len(P))
+	for k := range P {
+		if !P[k].IsInfinity() {
+			p = append(p, P[k])
+			l = append(l, lines[k])
+		}
+	}
+
+	var result, tmp, l0, l1 curve.GT
+	result.SetOne()
+
+	// i == len(loopCounter) - 2
+	for k := range p {
+		evaluateLine(&l0, &l[k].double[0], &p[k])
+		result.MulBy034(&l0.C0.B0, &l0.C1.B0, &l0.C1.B1)
+	}
+
+	j := 0 // index of the next addition line
+	for i := len(loopCounter) - 3; i >= 0; i-- {
+		// (∏ᵢfᵢ)²
+		result.Square(&result)
+
+		for k := range p {
+			evaluateLine(&l0, &l[k].double[len(loopCounter)-2-i], &p[k])
+			if loopCounter[i] == 0 {
+				result.MulBy034(&l0.C0.B0, &l0.C1.B0, &l0.C1.B1)
+				continue
+			}
+			evaluateLine(&l1, &l[k].add[j], &p[k])
+			tmp.Mul034by034(&l0.C0.B0, &l0.C1.B0, &l0.C1.B1, &l1.C0.B0, &l1.C1.B0, &l1.C1.B1)
+			result.Mul(&result, &tmp)
+		}
+		if loopCounter[i] != 0 {
+			j++
+		}
+	}
+
+	for k := range p {
+		evaluateLine(&l0, &l[k].add[j], &p[k])
+		evaluateLine(&l1, &l[k].add[j+1], &p[k])
+		tmp.Mul034by034(&l1.C0.B0, &l1.C1.B0, &l1.C1.B1, &l0.C0.B0, &l0.C1.B0, &l0.C1.B1)
+		result.Mul(&result, &tmp)
+	}
+
+	return result
+}
+
+// evaluateLine sets e to the line l evaluated at p.
+func evaluateLine(e, l *curve.GT, p *curve.G1Affine) {
+	e.C0.B0.MulByElement(&l.C0.B0, &p.Y)
+	e.C1.B0.MulByElement(&l.C1.B0, &p.X)
+	e.C1.B1 = l.C1.B1
+}
+
+// g2Proj is a point of G2 in homogeneous projective coordinates (X, Y, Z). The
+// coordinates are of a type internal to gnark-crypto, hence the G2Jac underneath.
+type g2Proj curve.G2Jac
+
+// doubleStep doubles p and sets l to the line of the doubling
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+func (p *g2Proj) doubleStep(l *curve.GT) {
+	// the temporaries are the coordinates of a scratch array, for the same reason
+	var s [5]curve.G2Jac
+	t1, A, B, C, D := &s[0].X, &s[0].Y, &s[0].Z, &s[1].X, &s[1].Y
+	E, EE, F, G, H := &s[1].Z, &s[2].X, &s[2].Y, &s[2].Z, &s[3].X
+	I, J, K := &s[3].Y, &s[3].Z, &s[4].X
+
+	A.Mul(&p.X, &p.Y)
+	A.Halve()
+	B.Square(&p.Y)
+	C.Square(&p.Z)
+	D.Double(C).
+		Add(D, C)
+	E.MulBybTwistCurveCoeff(D)
+	F.Double(E).
+		Add(F, E)
+	G.Add(B, F)
+	G.Halve()
+	H.Add(&p.Y, &p.Z).
+		Square(H)
+	t1.Add(B, C)
+	H.Sub(H, t1)
+	I.Sub(E, B)
+	J.Square(&p.X)
+	EE.Square(E)
+	K.Double(EE).
+		Add(K, EE)
+
+	// X, Y, Z
+	p.X.Sub(B, F).
+		Mul(&p.X, A)
+	p.Y.Square(G).
+		Sub(&p.Y, K)
+	p.Z.Mul(B, H)
+
+	// line
+	l.C0.B0.Neg(H)
+	l.C1.B0.Double(J).
+		Add(&l.C1.B0, J)
+	l.C1.B1.Set(I)
+}
+
+// addMixedStep adds a to p and sets l to the line of the addition
+// https://eprint.iacr.org/2013/722.pdf (Section 4.3)
+func (p *g2Proj) addMixedStep(l *curve.GT, a *curve.G2Affine) {
+	var s [5]curve.G2Jac
+	Y2Z1, X2Z1, O, L, C := &s[0].X, &s[0].Y, &s[0].Z, &s[1].X, &s[1].Y
+	D, E, F, G, H := &s[1].Z, &s[2].X, &s[2].Y, &s[2].Z, &s[3].X
+	t0, t1, t2, J := &s[3].Y, &s[3].Z, &s[4].X, &s[4].Y
+
+	Y2Z1.Mul(&a.Y, &p.Z)
+	O.Sub(&p.Y, Y2Z1)
+	X2Z1.Mul(&a.X, &p.Z)
+	L.Sub(&p.X, X2Z1)
+	C.Square(O)
+	D.Square(L)
+	E.Mul(L, D)
+	F.Mul(&p.Z, C)
+	G.Mul(&p.X, D)
+	t0.Double(G)
+	H.Add(E, F).
+		Sub(H, t0)
+	t1.Mul(&p.Y, E)
+
+	// X, Y, Z
+	p.X.Mul(L, H)
+	p.Y.Sub(G, H).
+		Mul(&p.Y, O).
+		Sub(&p.Y, t1)
+	p.Z.Mul(E, &p.Z)
+
+	t2.Mul(L, &a.Y)
+	J.Mul(&a.X, O).
+		Sub(J, t2)
+
+	// line
+	l.C0.B0.Set(L)
+	l.C1.B0.Neg(O)
+	l.C1.B1.Set(J)
+}
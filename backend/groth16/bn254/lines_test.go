@@ -0,0 +1,52 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMillerLoopLines(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := curve.Generators()
+	var P [3]curve.G1Affine
+	var Q [3]curve.G2Affine
+	for i := range P {
+		P[i].ScalarMultiplication(&g1, big.NewInt(int64(3*i+2)))
+		Q[i].ScalarMultiplication(&g2, big.NewInt(int64(5*i+7)))
+	}
+	lines := make([]*pairingLines, len(Q))
+	for i := range Q {
+		lines[i] = newPairingLines(&Q[i])
+	}
+
+	expected, err := curve.MillerLoop(P[:], Q[:])
+	assert.NoError(err)
+	actual := millerLoopLines(P[:], lines)
+	assert.True(expected.Equal(&actual))
+
+	// the pairs with the point at infinity are skipped
+	P[1].X.SetZero()
+	P[1].Y.SetZero()
+	expected, err = curve.MillerLoop(P[:], Q[:])
+	assert.NoError(err)
+	actual = millerLoopLines(P[:], lines)
+	assert.True(expected.Equal(&actual))
+}
@@ -17,23 +17,59 @@
 package groth16
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/utils"
+	gnarkio "github.com/consensys/gnark/io"
 	"io"
-	"fmt"
+	"strings"
+	"sync/atomic"
 )
 
+// serialized objects are framed by a header identifying the object, the curve,
+// the backend and the gnark version (see gnarkio.WriteWithHeader)
+var (
+	proofHeader = gnarkio.NewHeader(gnarkio.Proof, curve.ID, backend.GROTH16)
+	vkHeader    = gnarkio.NewHeader(gnarkio.VerifyingKey, curve.ID, backend.GROTH16)
+	pkHeader    = gnarkio.NewHeader(gnarkio.ProvingKey, curve.ID, backend.GROTH16)
+)
+
+func (proof *Proof) writeWithHeader(w io.Writer, raw bool) (int64, error) {
+	return gnarkio.WriteWithHeader(w, proofHeader, func(w io.Writer) error {
+		_, err := proof.writeTo(w, raw)
+		return err
+	})
+}
+
+func (vk *VerifyingKey) writeWithHeader(w io.Writer, raw bool) (int64, error) {
+	return gnarkio.WriteWithHeader(w, vkHeader, func(w io.Writer) error {
+		_, err := vk.writeTo(w, raw)
+		return err
+	})
+}
+
+func (pk *ProvingKey) writeWithHeader(w io.Writer, raw bool) (int64, error) {
+	return gnarkio.WriteWithHeader(w, pkHeader, func(w io.Writer) error {
+		_, err := pk.writeTo(w, raw)
+		return err
+	})
+}
+
 // WriteTo writes binary encoding of the Proof elements to writer
 // points are stored in compressed form Ar | Krs | Bs
 // use WriteRawTo(...) to encode the proof without point compression
 func (proof *Proof) WriteTo(w io.Writer) (n int64, err error) {
-	return proof.writeTo(w, false)
+	return proof.writeWithHeader(w, false)
 }
 
 // WriteRawTo writes binary encoding of the Proof elements to writer
 // points are stored in uncompressed form Ar | Krs | Bs
 // use WriteTo(...) to encode the proof with point compression
 func (proof *Proof) WriteRawTo(w io.Writer) (n int64, err error) {
-	return proof.writeTo(w, true)
+	return proof.writeWithHeader(w, true)
 }
 
 func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
@@ -58,9 +94,26 @@ func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
 
 // ReadFrom attempts to decode a Proof from reader
 // Proof must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
+// the decoded points are checked to be on the curve and in the correct subgroup
 func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
+	return gnarkio.ReadWithHeader(r, proofHeader, func(r io.Reader) error {
+		_, err := proof.readFrom(r)
+		return err
+	})
+}
 
-	dec := curve.NewDecoder(r)
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are in the correct subgroup. Verify still checks them, unless disabled with backend.WithSubgroupChecks.
+func (proof *Proof) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return gnarkio.ReadWithHeader(r, proofHeader, func(r io.Reader) error {
+		_, err := proof.readFrom(r, curve.NoSubgroupChecks())
+		return err
+	})
+}
+
+func (proof *Proof) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+
+	dec := curve.NewDecoder(r, decOptions...)
 
 	if err := dec.Decode(&proof.Ar); err != nil {
 		return dec.BytesRead(), err
@@ -79,14 +132,14 @@ func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
-	return vk.writeTo(w, false)
+	return vk.writeWithHeader(w, false)
 }
 
 // WriteRawTo writes binary encoding of the key elements to writer
 // points are not compressed
 // use WriteTo(...) to encode the key with point compression
 func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
-	return vk.writeTo(w, true)
+	return vk.writeWithHeader(w, true)
 }
 
 // writeTo serialization format:
@@ -125,7 +178,23 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(vk.G1.K); err != nil {
 		return enc.BytesWritten(), err
 	}
-	return enc.BytesWritten(), nil
+
+	// digest of the R1CS
+	n, err := vk.circuitDigest.WriteTo(w)
+	if err != nil {
+		return enc.BytesWritten() + n, err
+	}
+
+	// names of the public inputs
+	n2, err := writeStrings(w, vk.publicInputNames)
+	n += n2
+	if err != nil {
+		return enc.BytesWritten() + n, err
+	}
+
+	// metadata of the R1CS
+	n2, err = vk.metadata.WriteTo(w)
+	return enc.BytesWritten() + n + n2, err
 }
 
 // ReadFrom attempts to decode a VerifyingKey from reader
@@ -134,13 +203,19 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
-	return vk.readFrom(r)
+	return gnarkio.ReadWithHeader(r, vkHeader, func(r io.Reader) error {
+		_, err := vk.readFrom(r)
+		return err
+	})
 }
 
 // UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
 // are on the curve and in the correct subgroup.
 func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
-	return vk.readFrom(r, curve.NoSubgroupChecks())
+	return gnarkio.ReadWithHeader(r, vkHeader, func(r io.Reader) error {
+		_, err := vk.readFrom(r, curve.NoSubgroupChecks())
+		return err
+	})
 }
 
 func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
@@ -171,26 +246,46 @@ func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)
 		return dec.BytesRead(), err
 	}
 
+	// digest of the R1CS (zero if the key was written without it)
+	n, err := vk.circuitDigest.ReadFrom(r)
+	if err != nil {
+		return dec.BytesRead() + n, err
+	}
+
+	// names of the public inputs (nil if the key was written without them)
+	n2, err := readStrings(r, &vk.publicInputNames)
+	n += n2
+	if err != nil {
+		return dec.BytesRead() + n, err
+	}
+
+	// metadata of the R1CS (nil if the key was written without them)
+	n2, err = vk.metadata.ReadFrom(r)
+	n += n2
+	if err != nil {
+		return dec.BytesRead() + n, err
+	}
+
 	// recompute vk.e (e(α, β)) and  -[δ]2, -[γ]2
-	if err := vk.Precompute(); err != nil {
-		return dec.BytesRead(), err
+	if err := vk.precompute(); err != nil {
+		return dec.BytesRead() + n, err
 	}
 
-	return dec.BytesRead(), nil
+	return dec.BytesRead() + n, nil
 }
 
 // WriteTo writes binary encoding of the key elements to writer
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
 func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
-	return pk.writeTo(w, false)
+	return pk.writeWithHeader(w, false)
 }
 
 // WriteRawTo writes binary encoding of the key elements to writer
 // points are not compressed
 // use WriteTo(...) to encode the key with point compression
 func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
-	return pk.writeTo(w, true)
+	return pk.writeWithHeader(w, true)
 }
 
 func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
@@ -231,7 +326,12 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 		}
 	}
 
-	return n + enc.BytesWritten(), nil
+	n2, err := pk.circuitDigest.WriteTo(w)
+	if err != nil {
+		return n + enc.BytesWritten() + n2, err
+	}
+	n3, err := pk.metadata.WriteTo(w)
+	return n + enc.BytesWritten() + n2 + n3, err
 
 }
 
@@ -239,13 +339,19 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 // ProvingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 // note that we don't check that the points are on the curve or in the correct subgroup at this point
 func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
-	return pk.readFrom(r)
+	return gnarkio.ReadWithHeader(r, pkHeader, func(r io.Reader) error {
+		_, err := pk.readFrom(r)
+		return err
+	})
 }
 
 // UnsafeReadFrom behaves like ReadFrom excepts it doesn't check if the decoded points are on the curve
 // or in the correct subgroup
 func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
-	return pk.readFrom(r, curve.NoSubgroupChecks())
+	return gnarkio.ReadWithHeader(r, pkHeader, func(r io.Reader) error {
+		_, err := pk.readFrom(r, curve.NoSubgroupChecks())
+		return err
+	})
 }
 
 func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
@@ -253,7 +359,7 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 	if err != nil {
 		return n, err
 	}
-	fmt.Println("reading proving key")
+
 	dec := curve.NewDecoder(r, decOptions...)
 
 	var nbWires uint64
@@ -274,8 +380,7 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 		&pk.NbInfinityB,
 	}
 
-	for i, v := range toDecode {
-		fmt.Println("decoding", fmt.Sprintf("%d/%d", i, len(toDecode)), fmt.Sprintf("%T", v))
+	for _, v := range toDecode {
 		if err := dec.Decode(v); err != nil {
 			return n + dec.BytesRead(), err
 		}
@@ -283,14 +388,153 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 	pk.InfinityA = make([]bool, nbWires)
 	pk.InfinityB = make([]bool, nbWires)
 
-	fmt.Println("decoding infinityA")
 	if err := dec.Decode(&pk.InfinityA); err != nil {
 		return n + dec.BytesRead(), err
 	}
-	fmt.Println("decoding infinityB")
 	if err := dec.Decode(&pk.InfinityB); err != nil {
 		return n + dec.BytesRead(), err
 	}
 
-	return n + dec.BytesRead(), nil
+	n2, err := pk.circuitDigest.ReadFrom(r)
+	if err != nil {
+		return n + dec.BytesRead() + n2, err
+	}
+	n3, err := pk.metadata.ReadFrom(r)
+	return n + dec.BytesRead() + n2 + n3, err
+}
+
+var errInvalidPoint = errors.New("point not on the curve or not in the correct subgroup")
+
+// Validate checks that the points of the key are on the curve and in the correct
+// subgroup. ReadFrom already does it; Validate is meant for keys read with
+// UnsafeReadFrom, which skips these checks to load faster.
+func (vk *VerifyingKey) Validate() error {
+	if !vk.G1.Alpha.IsInSubGroup() || !vk.G1.Beta.IsInSubGroup() || !vk.G1.Delta.IsInSubGroup() || !g1InSubGroup(vk.G1.K) {
+		return fmt.Errorf("verifying key: %w", errInvalidPoint)
+	}
+	if !vk.G2.Beta.IsInSubGroup() || !vk.G2.Gamma.IsInSubGroup() || !vk.G2.Delta.IsInSubGroup() {
+		return fmt.Errorf("verifying key: %w", errInvalidPoint)
+	}
+	return nil
+}
+
+// Validate checks that the sizes of the elements of the key are consistent, and
+// that its points are on the curve and in the correct subgroup. ReadFrom already
+// checks the points; Validate is meant for keys read with UnsafeReadFrom, which
+// skips these checks to load faster.
+func (pk *ProvingKey) Validate() error {
+	nbWires := len(pk.InfinityA)
+	if len(pk.InfinityB) != nbWires ||
+		countTrue(pk.InfinityA) != pk.NbInfinityA || countTrue(pk.InfinityB) != pk.NbInfinityB ||
+		len(pk.G1.A) != nbWires-int(pk.NbInfinityA) ||
+		len(pk.G1.B) != nbWires-int(pk.NbInfinityB) || len(pk.G2.B) != len(pk.G1.B) ||
+		len(pk.G1.K) > nbWires || len(pk.G1.Z) > int(pk.Domain.Cardinality) {
+		return errors.New("proving key: inconsistent sizes")
+	}
+	if !pk.G1.Alpha.IsInSubGroup() || !pk.G1.Beta.IsInSubGroup() || !pk.G1.Delta.IsInSubGroup() ||
+		!g1InSubGroup(pk.G1.A) || !g1InSubGroup(pk.G1.B) || !g1InSubGroup(pk.G1.Z) || !g1InSubGroup(pk.G1.K) {
+		return fmt.Errorf("proving key: %w", errInvalidPoint)
+	}
+	if !pk.G2.Beta.IsInSubGroup() || !pk.G2.Delta.IsInSubGroup() || !g2InSubGroup(pk.G2.B) {
+		return fmt.Errorf("proving key: %w", errInvalidPoint)
+	}
+	return nil
+}
+
+func countTrue(b []bool) uint64 {
+	var n uint64
+	for _, v := range b {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// g1InSubGroup checks the points in parallel
+func g1InSubGroup(points []curve.G1Affine) bool {
+	var invalid uint32
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end && atomic.LoadUint32(&invalid) == 0; i++ {
+			if !points[i].IsInSubGroup() {
+				atomic.StoreUint32(&invalid, 1)
+			}
+		}
+	})
+	return invalid == 0
+}
+
+// g2InSubGroup checks the points in parallel
+func g2InSubGroup(points []curve.G2Affine) bool {
+	var invalid uint32
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end && atomic.LoadUint32(&invalid) == 0; i++ {
+			if !points[i].IsInSubGroup() {
+				atomic.StoreUint32(&invalid, 1)
+			}
+		}
+	})
+	return invalid == 0
+}
+
+// writeStrings writes uint32(len(s)) followed by uint32(len(s[i])) and the bytes of
+// each string.
+func writeStrings(w io.Writer, s []string) (int64, error) {
+	var n int64
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(s)))
+	m, err := w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+	for i := range s {
+		binary.BigEndian.PutUint32(buf[:], uint32(len(s[i])))
+		m, err = w.Write(buf[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		m, err = io.WriteString(w, s[i])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// readStrings reads strings written by writeStrings in s, which is set to nil if r
+// is at EOF.
+func readStrings(r io.Reader, s *[]string) (int64, error) {
+	var n int64
+	var buf [4]byte
+	m, err := io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err == io.EOF {
+		*s = nil
+		return n, nil
+	}
+	if err != nil {
+		return n, err
+	}
+	nbStrings := binary.BigEndian.Uint32(buf[:])
+	*s = nil
+	var sb strings.Builder
+	for i := uint32(0); i < nbStrings; i++ {
+		m, err = io.ReadFull(r, buf[:])
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		sb.Reset()
+		// copy instead of allocating the length read, which may be corrupted
+		m64, err := io.CopyN(&sb, r, int64(binary.BigEndian.Uint32(buf[:])))
+		n += m64
+		if err != nil {
+			return n, err
+		}
+		*s = append(*s, sb.String())
+	}
+	return n, nil
 }
@@ -1,3 +1,6 @@
+//go:build !gnark_verifier_only
+// +build !gnark_verifier_only
+
 // Copyright 2020 ConsenSys Software Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -28,47 +31,54 @@ import (
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/metrics"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
 	"time"
 )
 
-// Proof represents a Groth16 proof that was encoded with a ProvingKey and can be verified
-// with a valid statement and a VerifyingKey
-// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
-type Proof struct {
-	Ar, Krs                   curve.G1Affine
-	Bs                        curve.G2Affine
-	Commitment, CommitmentPok curve.G1Affine
-}
-
-// isValid ensures proof elements are in the correct subgroup
-func (proof *Proof) isValid() bool {
-	return proof.Ar.IsInSubGroup() && proof.Krs.IsInSubGroup() && proof.Bs.IsInSubGroup()
-}
-
-// CurveID returns the curveID
-func (proof *Proof) CurveID() ecc.ID {
-	return curve.ID
-}
+// vectorPool holds the scratch vectors of the prover, see backend.WithBufferPooling
+var vectorPool utils.SlicePool[fr.Element]
 
 // Prove generates the proof of knowledge of a r1cs with full witness (secret + public part).
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+	return prove(r1cs, pk, pk, fullWitness, opts...)
+}
+
+// prove is Prove with the points of the proving key provided by points, the other
+// fields of the key being read from pk.
+func prove(r1cs *cs.R1CS, pk *ProvingKey, points keyPoints, fullWitness witness.Witness, opts ...backend.ProverOption) (_ *Proof, err error) {
 	opt, err := backend.NewProverConfig(opts...)
 	if err != nil {
 		return nil, err
 	}
+	tracer := tracing.Tracer(opt.TracerProvider)
+	ctx, span := tracing.Start(opt.TraceContext, tracer, "groth16.prove", attribute.String("curve", curve.ID.String()), attribute.Int("nbConstraints", len(r1cs.Constraints)))
+	defer func() { tracing.End(span, err) }()
+
+	digest := r1cs.Digest()
+	if !pk.circuitDigest.IsZero() && pk.circuitDigest != digest {
+		return nil, fmt.Errorf("%w: key digest %s, constraint system digest %s", backend.ErrCircuitMismatch, pk.circuitDigest, digest)
+	}
+	defer func(start time.Time) {
+		if err == nil {
+			metrics.ObserveMemory()
+			metrics.ObserveProof("groth16", curve.ID.String(), digest.String(), time.Since(start))
+		}
+	}(time.Now())
 
 	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", len(r1cs.Constraints)).Str("backend", "groth16").Logger()
 
 	proof := &Proof{}
 
-	fmt.Println("setting solver opts")
+	// the large scratch vectors are reused across proofs if opt.PoolBuffers is set
+	scratch := utils.NewScratch(&vectorPool, opt.PoolBuffers)
+
 	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
 
 	if r1cs.CommitmentInfo.Is() {
-		fmt.Println("overriding commitment info hint")
-		solverOpts = append(solverOpts, solver.OverrideHint(r1cs.CommitmentInfo.HintID,
-			func(_ *big.Int, in []*big.Int, out []*big.Int) error {
+		solverOpts = append(solverOpts, solver.OverrideHint(r1cs.CommitmentInfo.HintID, func(_ *big.Int, in []*big.Int, out []*big.Int) error {
 			// Perf-TODO: Converting these values to big.Int and back may be a performance bottleneck.
 			// If that is the case, figure out a way to feed the solution vector into this function
 			if len(in) != r1cs.CommitmentInfo.NbCommitted() { // TODO: Remove
@@ -98,17 +108,15 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	log.Debug().Msg("solving r1cs")
-	data, err := fullWitness.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println("fullwitness data size is ", len(data))
-	fmt.Println("solving witness")
+	_, solveSpan := tracing.Start(ctx, tracer, "groth16.prove.solve")
 	_solution, err := r1cs.Solve(fullWitness, solverOpts...)
+	tracing.End(solveSpan, err)
 	if err != nil {
+		metrics.ObserveSolverError("groth16", curve.ID.String())
 		return nil, err
 	}
-	fmt.Println("solved witness!")
+	metrics.ObserveMemory()
+
 	solution := _solution.(*cs.R1CSSolution)
 	wireValues := []fr.Element(solution.W)
 
@@ -117,11 +125,13 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	// H (witness reduction / FFT part)
 	log.Debug().Msg("computing witness reduction")
 	var h []fr.Element
+	_, fftSpan := tracing.Start(ctx, tracer, "groth16.prove.fft")
 	func() {
-		h = computeH(solution.A, solution.B, solution.C, &pk.Domain)
+		h = computeH(solution.A, solution.B, solution.C, &pk.Domain, opt.Tuning.FFTTasks, scratch)
 		solution.A = nil
 		solution.B = nil
 		solution.C = nil
+		fftSpan.End()
 	}()
 
 	// we need to copy and filter the wireValues for each multi exp
@@ -129,7 +139,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	var wireValuesA, wireValuesB []fr.Element
 
 	func() {
-		wireValuesA = make([]fr.Element, len(wireValues)-int(pk.NbInfinityA))
+		wireValuesA = scratch.Make(len(wireValues)-int(pk.NbInfinityA), len(wireValues)-int(pk.NbInfinityA))
 		for i, j := 0, 0; j < len(wireValuesA); i++ {
 			if pk.InfinityA[i] {
 				continue
@@ -139,7 +149,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		}
 	}()
 	func() {
-		wireValuesB = make([]fr.Element, len(wireValues)-int(pk.NbInfinityB))
+		wireValuesB = scratch.Make(len(wireValues)-int(pk.NbInfinityB), len(wireValues)-int(pk.NbInfinityB))
 		for i, j := 0, 0; j < len(wireValuesB); i++ {
 			if pk.InfinityB[i] {
 				continue
@@ -169,59 +179,59 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	var bs1, ar curve.G1Jac
 
-	n := 1
+	n := opt.Tuning.MSMG1Tasks
 
-	computeBS1 := func() {
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n }); err != nil {
-			panic(err)
+	computeBS1 := func() error {
+		if err := points.multiExpG1(&bs1, sectionG1B, wireValuesB, ecc.MultiExpConfig{NbTasks: n}); err != nil {
+			return err
 		}
 		bs1.AddMixed(&pk.G1.Beta)
 		bs1.AddMixed(&deltas[1])
+		return nil
 	}
 
-	computeAR1 := func() {
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n }); err != nil {
-			panic(err)
+	computeAR1 := func() error {
+		if err := points.multiExpG1(&ar, sectionG1A, wireValuesA, ecc.MultiExpConfig{NbTasks: n}); err != nil {
+			return err
 		}
 		ar.AddMixed(&pk.G1.Alpha)
 		ar.AddMixed(&deltas[0])
 		proof.Ar.FromJacobian(&ar)
+		return nil
 	}
 
-	computeKRS := func() {
+	computeKRS := func() error {
 		// we could NOT split the Krs multiExp in 2, and just append pk.G1.K and pk.G1.Z
 		// however, having similar lengths for our tasks helps with parallelism
 
 		var krs, krs2, p1 curve.G1Jac
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
-		func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n })
-			if err != nil {
-				panic(err)
-			}
-		}()
+		if err := points.multiExpG1(&krs2, sectionG1Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n}); err != nil {
+			return err
+		}
 
 		// filter the wire values if needed;
 		_wireValues := filter(wireValues, r1cs.CommitmentInfo.PrivateToPublic())
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues[r1cs.GetNbPublicVariables():], ecc.MultiExpConfig{NbTasks: n }); err != nil {
-			panic(err)
+		if err := points.multiExpG1(&krs, sectionG1K, _wireValues[r1cs.GetNbPublicVariables():], ecc.MultiExpConfig{NbTasks: n}); err != nil {
+			return err
 		}
 		krs.AddMixed(&deltas[2])
-				krs.AddAssign(&krs2)
+		krs.AddAssign(&krs2)
 		p1.ScalarMultiplication(&ar, &s)
-				krs.AddAssign(&p1)
+		krs.AddAssign(&p1)
 		p1.ScalarMultiplication(&bs1, &r)
-				krs.AddAssign(&p1)
+		krs.AddAssign(&p1)
 		proof.Krs.FromJacobian(&krs)
+		return nil
 	}
 
 	computeBS2 := func() error {
 		// Bs2 (1 multi exp G2 - size = len(wires))
 		var Bs, deltaS curve.G2Jac
 
-		nbTasks := n
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		nbTasks := opt.Tuning.MSMG2Tasks
+		if err := points.multiExpG2(&Bs, sectionG2B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
 			return err
 		}
 
@@ -237,12 +247,21 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	// wait for FFT to end, as it uses all our CPUs
 
 	// schedule our proof part computations
-	log.Debug().Msg("computing KRS")
-	computeKRS()
+	_, msmSpan := tracing.Start(ctx, tracer, "groth16.prove.msm")
+	defer msmSpan.End()
+	// KRS uses ar and bs1, so they are computed first
 	log.Debug().Msg("computing AR1")
-	 computeAR1()
+	if err := computeAR1(); err != nil {
+		return nil, err
+	}
 	log.Debug().Msg("computing BS1")
-	computeBS1()
+	if err := computeBS1(); err != nil {
+		return nil, err
+	}
+	log.Debug().Msg("computing KRS")
+	if err := computeKRS(); err != nil {
+		return nil, err
+	}
 	log.Debug().Msg("computing BS2")
 	if err := computeBS2(); err != nil {
 		return nil, err
@@ -250,6 +269,9 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
 
+	// on errors, the vectors may still be used by the goroutines and aren't released
+	scratch.Release()
+
 	return proof, nil
 }
 
@@ -276,7 +298,7 @@ func filter(slice []fr.Element, toRemove []int) (r []fr.Element) {
 	return r
 }
 
-func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
+func computeH(a, b, c []fr.Element, domain *fft.Domain, nbTasks int, scratch *utils.Scratch[fr.Element]) []fr.Element {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
 	// 	1 - _a = ifft(a), _b = ifft(b), _c = ifft(c)
@@ -286,19 +308,22 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	n := len(a)
 
 	// add padding to ensure input length is domain cardinality
-	padding := make([]fr.Element, int(domain.Cardinality)-n)
-	a = append(a, padding...)
-	b = append(b, padding...)
-	c = append(c, padding...)
+	pad := func(v []fr.Element) []fr.Element {
+		res := scratch.Make(int(domain.Cardinality), int(domain.Cardinality))
+		copy(res, v)
+		return res
+	}
+	a, b, c = pad(a), pad(b), pad(c)
 	n = len(a)
+	fftNbTasks := fft.WithNbTasks(nbTasks)
 
-	domain.FFTInverse(a, fft.DIF)
-	domain.FFTInverse(b, fft.DIF)
-	domain.FFTInverse(c, fft.DIF)
+	domain.FFTInverse(a, fft.DIF, fftNbTasks)
+	domain.FFTInverse(b, fft.DIF, fftNbTasks)
+	domain.FFTInverse(c, fft.DIF, fftNbTasks)
 
-	domain.FFT(a, fft.DIT, fft.OnCoset())
-	domain.FFT(b, fft.DIT, fft.OnCoset())
-	domain.FFT(c, fft.DIT, fft.OnCoset())
+	domain.FFT(a, fft.DIT, fft.OnCoset(), fftNbTasks)
+	domain.FFT(b, fft.DIT, fft.OnCoset(), fftNbTasks)
+	domain.FFT(c, fft.DIT, fft.OnCoset(), fftNbTasks)
 
 	var den, one fr.Element
 	one.SetOne()
@@ -316,7 +341,7 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	})
 
 	// ifft_coset
-	domain.FFTInverse(a, fft.DIF, fft.OnCoset())
+	domain.FFTInverse(a, fft.DIF, fft.OnCoset(), fftNbTasks)
 
 	return a
 }
@@ -1,3 +1,6 @@
+//go:build !gnark_verifier_only
+// +build !gnark_verifier_only
+
 // Copyright 2020 ConsenSys Software Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -17,70 +20,49 @@
 package groth16
 
 import (
-	"github.com/consensys/gnark-crypto/ecc"
+	"context"
+	"errors"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/pedersen"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
 	"math/bits"
 )
 
-// ProvingKey is used by a Groth16 prover to encode a proof of a statement
-// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
-type ProvingKey struct {
-	// domain
-	Domain fft.Domain
-
-	// [α]1, [β]1, [δ]1
-	// [A(t)]1, [B(t)]1, [Kpk(t)]1, [Z(t)]1
-	G1 struct {
-		Alpha, Beta, Delta curve.G1Affine
-		A, B, Z            []curve.G1Affine
-		K                  []curve.G1Affine // the indexes correspond to the private wires
-	}
-
-	// [β]2, [δ]2, [B(t)]2
-	G2 struct {
-		Beta, Delta curve.G2Affine
-		B           []curve.G2Affine
+// Setup constructs the SRS
+func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
+	// samples toxic waste
+	toxicWaste, err := sampleToxicWaste()
+	if err != nil {
+		return err
 	}
-
-	// if InfinityA[i] == true, the point G1.A[i] == infinity
-	InfinityA, InfinityB     []bool
-	NbInfinityA, NbInfinityB uint64
-
-	CommitmentKey pedersen.Key
+	return setup(r1cs, pk, vk, toxicWaste)
 }
 
-// VerifyingKey is used by a Groth16 verifier to verify the validity of a proof and a statement
-// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
-type VerifyingKey struct {
-	// [α]1, [Kvk]1
-	G1 struct {
-		Alpha       curve.G1Affine
-		Beta, Delta curve.G1Affine   // unused, here for compatibility purposes
-		K           []curve.G1Affine // The indexes correspond to the public wires
-	}
-
-	// [β]2, [δ]2, [γ]2,
-	// -[δ]2, -[γ]2: see proof.Verify() for more details
-	G2 struct {
-		Beta, Delta, Gamma curve.G2Affine
-		deltaNeg, gammaNeg curve.G2Affine // not serialized
+// DevSetup constructs the SRS from toxic waste derived from seed, so that the
+// same seed always gives the same keys.
+//
+// This is UNSAFE: anyone knowing the seed can forge proofs. DevSetup is meant
+// for development and tests only, where it allows to share keys without
+// shipping them. Circuits with commitments are not supported, their Pedersen
+// key being sampled at random.
+func DevSetup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, seed []byte) error {
+	if r1cs.CommitmentInfo.Is() {
+		return errors.New("dev setup of circuits with commitments is not supported")
+	}
+	toxicWaste, err := deriveToxicWaste(seed)
+	if err != nil {
+		return err
 	}
-
-	// e(α, β)
-	e curve.GT // not serialized
-
-	CommitmentKey  pedersen.Key
-	CommitmentInfo constraint.Commitment // since the verifier doesn't input a constraint system, this needs to be provided here
+	return setup(r1cs, pk, vk, toxicWaste)
 }
 
-// Setup constructs the SRS
-func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
+func setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, toxicWaste toxicWaste) (err error) {
 	/*
 		Setup
 		-----
@@ -90,6 +72,8 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 		- loop through the inpure structural constraints, eValuate A(X), B(X), C(X) with simple formula, the gate number is len(gateOrdering)+ current iterator
 		- loop through the pure structural constraints, eValuate A(X), B(X), C(X) with simple formula, the gate number is len(gateOrdering)+len(InpureStructuralConstraints)+current iterator
 	*/
+	_, span := tracing.Start(context.Background(), tracing.Tracer(nil), "groth16.setup", attribute.String("curve", curve.ID.String()), attribute.Int("nbConstraints", len(r1cs.Constraints)))
+	defer func() { tracing.End(span, err) }()
 
 	// get R1CS nb constraints, wires and public/private inputs
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
@@ -105,12 +89,6 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// Setting group for fft
 	domain := fft.NewDomain(uint64(len(r1cs.Constraints)))
 
-	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
-	if err != nil {
-		return err
-	}
-
 	// Setup coeffs to compute pk.G1.A, pk.G1.B, pk.G1.K
 	A, B, C := setupABC(r1cs, domain, toxicWaste)
 
@@ -265,6 +243,14 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 
 	vk.CommitmentInfo = r1cs.CommitmentInfo // unfortunate but necessary
 
+	vk.circuitDigest = r1cs.Digest()
+	pk.circuitDigest = vk.circuitDigest
+	vk.metadata = r1cs.Metadata.Clone()
+	pk.metadata = r1cs.Metadata.Clone()
+	if len(r1cs.Public) > 1 {
+		vk.publicInputNames = append([]string(nil), r1cs.Public[1:]...) // without the constant wire "1"
+	}
+
 	// ---------------------------------------------------------------------------------------------
 	// G2 scalars
 
@@ -297,7 +283,7 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	vk.G1.Beta = pk.G1.Beta
 	vk.G1.Delta = pk.G1.Delta
 
-	if err := vk.Precompute(); err != nil {
+	if err := vk.precompute(); err != nil {
 		return err
 	}
 
@@ -307,19 +293,6 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	return nil
 }
 
-// Precompute sets e, -[δ]2, -[γ]2
-// This is meant to be called internally during setup or deserialization.
-func (vk *VerifyingKey) Precompute() error {
-	var err error
-	vk.e, err = curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
-	if err != nil {
-		return err
-	}
-	vk.G2.deltaNeg.Neg(&vk.G2.Delta)
-	vk.G2.gammaNeg.Neg(&vk.G2.Gamma)
-	return nil
-}
-
 func setupABC(r1cs *cs.R1CS, domain *fft.Domain, toxicWaste toxicWaste) (A []fr.Element, B []fr.Element, C []fr.Element) {
 
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
@@ -443,6 +416,31 @@ func sampleToxicWaste() (toxicWaste, error) {
 	return res, nil
 }
 
+// devSetupDst is the domain separation tag of the toxic waste derived by DevSetup
+const devSetupDst = "GNARK-GROTH16-DEV-SETUP"
+
+// deriveToxicWaste hashes seed to the toxic waste, see DevSetup.
+func deriveToxicWaste(seed []byte) (toxicWaste, error) {
+
+	res := toxicWaste{}
+
+	e, err := fr.Hash(seed, []byte(devSetupDst), 5)
+	if err != nil {
+		return res, err
+	}
+	for i := range e {
+		if e[i].IsZero() {
+			return res, errors.New("the seed derives a zero toxic waste element")
+		}
+	}
+	res.t, res.alpha, res.beta, res.gamma, res.delta = e[0], e[1], e[2], e[3], e[4]
+
+	res.gammaInv.Inverse(&res.gamma)
+	res.deltaInv.Inverse(&res.delta)
+
+	return res, nil
+}
+
 // DummySetup fills a random ProvingKey
 // used for test or benchmarking purposes
 func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
@@ -458,6 +456,8 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	nbZeroesA, nbZeroesB := dummyInfinityCount(r1cs)
 
 	// initialize proving key
+	pk.circuitDigest = r1cs.Digest()
+	pk.metadata = r1cs.Metadata.Clone()
 	pk.G1.A = make([]curve.G1Affine, nbWires-nbZeroesA)
 	pk.G1.B = make([]curve.G1Affine, nbWires-nbZeroesB)
 	pk.G1.K = make([]curve.G1Affine, nbWires-r1cs.GetNbPublicVariables())
@@ -546,78 +546,6 @@ func dummyInfinityCount(r1cs *cs.R1CS) (nbZeroesA, nbZeroesB int) {
 
 }
 
-// IsDifferent returns true if provided vk is different than self
-// this is used by groth16.Assert to ensure random sampling
-func (vk *VerifyingKey) IsDifferent(_other interface{}) bool {
-	vk2 := _other.(*VerifyingKey)
-	for i := 0; i < len(vk.G1.K); i++ {
-		if !vk.G1.K[i].IsInfinity() {
-			if vk.G1.K[i].Equal(&vk2.G1.K[i]) {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
-// IsDifferent returns true if provided pk is different than self
-// this is used by groth16.Assert to ensure random sampling
-func (pk *ProvingKey) IsDifferent(_other interface{}) bool {
-	pk2 := _other.(*ProvingKey)
-
-	if pk.G1.Alpha.Equal(&pk2.G1.Alpha) ||
-		pk.G1.Beta.Equal(&pk2.G1.Beta) ||
-		pk.G1.Delta.Equal(&pk2.G1.Delta) {
-		return false
-	}
-
-	for i := 0; i < len(pk.G1.K); i++ {
-		if !pk.G1.K[i].IsInfinity() {
-			if pk.G1.K[i].Equal(&pk2.G1.K[i]) {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
-// CurveID returns the curveID
-func (pk *ProvingKey) CurveID() ecc.ID {
-	return curve.ID
-}
-
-// CurveID returns the curveID
-func (vk *VerifyingKey) CurveID() ecc.ID {
-	return curve.ID
-}
-
-// NbPublicWitness returns the number of elements in the expected public witness
-func (vk *VerifyingKey) NbPublicWitness() int {
-	return (len(vk.G1.K) - 1)
-}
-
-// NbG1 returns the number of G1 elements in the VerifyingKey
-func (vk *VerifyingKey) NbG1() int {
-	return 3 + len(vk.G1.K)
-}
-
-// NbG2 returns the number of G2 elements in the VerifyingKey
-func (vk *VerifyingKey) NbG2() int {
-	return 3
-}
-
-// NbG1 returns the number of G1 elements in the ProvingKey
-func (pk *ProvingKey) NbG1() int {
-	return 3 + len(pk.G1.A) + len(pk.G1.B) + len(pk.G1.Z) + len(pk.G1.K)
-}
-
-// NbG2 returns the number of G2 elements in the ProvingKey
-func (pk *ProvingKey) NbG2() int {
-	return 2 + len(pk.G2.B)
-}
-
 // bitRerverse permutation as in fft.BitReverse , but with []curve.G1Affine
 func bitReverse(a []curve.G1Affine) {
 	n := uint(len(a))
@@ -17,48 +17,83 @@
 package groth16
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"io"
 	"math/big"
 	"text/template"
 	"time"
 )
 
+// Proof represents a Groth16 proof that was encoded with a ProvingKey and can be verified
+// with a valid statement and a VerifyingKey
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+type Proof struct {
+	Ar, Krs                   curve.G1Affine
+	Bs                        curve.G2Affine
+	Commitment, CommitmentPok curve.G1Affine
+}
+
+// isValid ensures proof elements are in the correct subgroup
+func (proof *Proof) isValid() bool {
+	return proof.Ar.IsInSubGroup() && proof.Krs.IsInSubGroup() && proof.Bs.IsInSubGroup() &&
+		proof.Commitment.IsInSubGroup() && proof.CommitmentPok.IsInSubGroup()
+}
+
+// CurveID returns the curveID
+func (proof *Proof) CurveID() ecc.ID {
+	return curve.ID
+}
+
 var (
 	errPairingCheckFailed         = errors.New("pairing doesn't match")
 	errCorrectSubgroupCheckFailed = errors.New("points in the proof are not in the correct subgroup")
 )
 
 // Verify verifies a proof with given VerifyingKey and publicWitness
-func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
-
-	nbPublicVars := len(vk.G1.K)
-	if vk.CommitmentInfo.Is() {
-		nbPublicVars--
-	}
-	if len(publicWitness) != nbPublicVars-1 {
-		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
+//
+// The points of the proof are checked to be in the correct subgroup, unless
+// disabled with backend.WithSubgroupChecks(false).
+func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...backend.VerifierOption) (err error) {
+	ctx, span := tracing.Start(context.Background(), tracing.Tracer(nil), "groth16.verify", attribute.String("curve", curve.ID.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if len(publicWitness) != vk.NbPublicWitness() {
+		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), vk.NbPublicWitness())
 	}
 	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Logger()
 	start := time.Now()
 
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+
 	// check that the points in the proof are in the correct subgroup
-	if !proof.isValid() {
+	if cfg.SubgroupChecks && !proof.isValid() {
 		return errCorrectSubgroupCheckFailed
 	}
 
 	var doubleML curve.GT
 	chDone := make(chan error, 1)
 
-	// compute (eKrsδ, eArBs)
+	// compute (eKrsδ, eArBs), or only eArBs if the lines of -[δ]2 are precomputed
 	go func() {
 		var errML error
-		doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Krs, proof.Ar}, []curve.G2Affine{vk.G2.deltaNeg, proof.Bs})
+		if vk.lines[0] != nil {
+			doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Ar}, []curve.G2Affine{proof.Bs})
+		} else {
+			doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Krs, proof.Ar}, []curve.G2Affine{vk.G2.deltaNeg, proof.Bs})
+		}
 		chDone <- errML
 		close(chDone)
 	}()
@@ -95,8 +130,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
-	if err != nil {
+	_, pairingSpan := tracing.Start(ctx, tracing.Tracer(nil), "groth16.verify.pairing")
+	defer pairingSpan.End()
+	var right curve.GT
+	if vk.lines[0] != nil {
+		// (eKrsδ, e(Σx.[Kvk(t)]1, -[γ]2)) from the precomputed lines
+		right = millerLoopLines([]curve.G1Affine{proof.Krs, kSumAff}, vk.lines[:])
+	} else if right, err = curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg}); err != nil {
 		return err
 	}
 
@@ -119,15 +159,48 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 // audit report https://github.com/appliedzkp/semaphore/blob/master/audit/Audit%20Report%20Summary%20for%20Semaphore%20and%20MicroMix.pdf
 // this is an experimental feature and gnark solidity generator as not been thoroughly tested.
 //
+// The generated code can be customized with options, see package backend/solidity.
+//
 // See https://github.com/ConsenSys/gnark-tests for example usage.
-func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+func (vk *VerifyingKey) ExportSolidity(w io.Writer, opts ...solidity.ExportOption) error {
+	cfg, err := solidity.NewExportConfig(opts...)
+	if err != nil {
+		return err
+	}
+	nbInputs, err := cfg.NbPublicInputs(len(vk.G1.K) - 1)
+	if err != nil {
+		return err
+	}
+	helpers := cfg.TemplateFuncs()
+	helpers["sub"] = func(a, b int) int {
+		return a - b
+	}
+	helpers["nbInputs"] = func() int {
+		return nbInputs
+	}
+
+	tmpl, err := template.New("").Funcs(helpers).Parse(solidityTemplate)
+	if err != nil {
+		return err
+	}
+
+	// execute template
+	return tmpl.Execute(w, vk)
+}
+
+// ExportCairo writes a Cairo (StarkNet) Verifier contract on provided writer.
+// The contract delegates the BN254 arithmetic to a contract implementing the
+// IBN254 interface declared in the generated code, whose address is given at
+// deployment.
+// this is an experimental feature and gnark cairo generator as not been thoroughly tested.
+func (vk *VerifyingKey) ExportCairo(w io.Writer) error {
 	helpers := template.FuncMap{
 		"sub": func(a, b int) int {
 			return a - b
 		},
 	}
 
-	tmpl, err := template.New("").Funcs(helpers).Parse(solidityTemplate)
+	tmpl, err := template.New("").Funcs(helpers).Parse(cairoTemplate)
 	if err != nil {
 		return err
 	}
@@ -20,19 +20,18 @@
 package groth16
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
+	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
-	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
-
-	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 
 	gnarkio "github.com/consensys/gnark/io"
-
-	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
 )
 
 type groth16Object interface {
@@ -45,8 +44,17 @@ type groth16Object interface {
 // Proof represents a Groth16 proof generated by groth16.Prove
 //
 // it's underlying implementation is curve specific (see gnark/internal/backend)
+//
+// ReadFrom checks that the points of the proof are in the correct subgroup,
+// UnsafeReadFrom doesn't; Verify checks them in both cases, unless disabled with
+// backend.WithSubgroupChecks(false).
 type Proof interface {
 	groth16Object
+	gnarkio.UnsafeReaderFrom
+
+	// ExportSnarkJS writes the proof in the JSON format of snarkjs
+	// this will return an error if not supported on the CurveID()
+	ExportSnarkJS(w io.Writer) error
 }
 
 // ProvingKey represents a Groth16 ProvingKey
@@ -62,6 +70,23 @@ type ProvingKey interface {
 	// NbG2 returns the number of G2 elements in the ProvingKey
 	NbG2() int
 
+	// CircuitDigest returns the fingerprint of the constraint system the key was
+	// generated for, zero if the key was serialized without it
+	CircuitDigest() constraint.Digest
+
+	// Metadata returns the metadata of the constraint system the key was generated
+	// for (see frontend.WithMetadata), nil if the key was serialized without them
+	Metadata() constraint.Metadata
+
+	// Validate checks that the points of the key are on the curve and in the
+	// correct subgroup, and that its sizes are consistent; it is meant for keys
+	// read with UnsafeReadFrom
+	Validate() error
+
+	// WriteShards writes the key in dir for ProveSharded, the points of each of
+	// its sections in files of at most shardSize points (see ReadShardedProvingKey)
+	WriteShards(dir string, shardSize int) error
+
 	IsDifferent(interface{}) bool
 }
 
@@ -69,40 +94,80 @@ type ProvingKey interface {
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 //
-// ExportSolidity is implemented for BN254 and will return an error with other curves
+// ExportSolidity, ExportSnarkJS and ExportCairo are implemented for BN254 and will return an error with other curves
 type VerifyingKey interface {
 	groth16Object
 	gnarkio.UnsafeReaderFrom
 
+	// json.Marshaler and json.Unmarshaler encode the key in a human-readable
+	// form, with named fields and hex encoded points
+	json.Marshaler
+	json.Unmarshaler
+
 	// NbPublicWitness returns number of elements expected in the public witness
 	NbPublicWitness() int
 
+	// PublicInputNames returns the names of the public inputs, in the order of the
+	// public witness; it is nil if the key was serialized without them
+	PublicInputNames() []string
+
+	// Commitment returns the description of the commitment of the circuit, if any
+	// (see constraint.Commitment.Is)
+	Commitment() *constraint.Commitment
+
 	// NbG1 returns the number of G1 elements in the VerifyingKey
 	NbG1() int
 
 	// NbG2 returns the number of G2 elements in the VerifyingKey
 	NbG2() int
 
+	// Precompute stores the lines of the pairings with the fixed points of the key,
+	// which speeds up Verify for keys verifying many proofs
+	Precompute() error
+
+	// CircuitDigest returns the fingerprint of the constraint system the key was
+	// generated for, zero if the key was serialized without it
+	CircuitDigest() constraint.Digest
+
+	// Metadata returns the metadata of the constraint system the key was generated
+	// for (see frontend.WithMetadata), nil if the key was serialized without them
+	Metadata() constraint.Metadata
+
+	// Validate checks that the points of the key are on the curve and in the
+	// correct subgroup, and that its sizes are consistent; it is meant for keys
+	// read with UnsafeReadFrom
+	Validate() error
+
 	// ExportSolidity writes a solidity Verifier contract from the VerifyingKey
 	// this will return an error if not supported on the CurveID()
-	ExportSolidity(w io.Writer) error
+	ExportSolidity(w io.Writer, opts ...solidity.ExportOption) error
+
+	// EstimateGas estimates the gas of a call to the verifier exported with
+	// ExportSolidity and the same options, see solidity.GasEstimate
+	EstimateGas(opts ...solidity.ExportOption) (solidity.GasEstimate, error)
+
+	// ExportSnarkJS writes the VerifyingKey in the JSON format of snarkjs
+	// this will return an error if not supported on the CurveID()
+	ExportSnarkJS(w io.Writer) error
+
+	// ExportCairo writes a Cairo (StarkNet) Verifier contract from the VerifyingKey
+	// this will return an error if not supported on the CurveID()
+	ExportCairo(w io.Writer) error
 
 	IsDifferent(interface{}) bool
 }
 
 // Verify runs the groth16.Verify algorithm on provided proof with given witness
-func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
-
-	switch _proof := proof.(type) {
-	case *groth16_bn254.Proof:
-		w, ok := publicWitness.Vector().(fr_bn254.Vector)
-		if !ok {
-			return witness.ErrInvalidWitness
-		}
-		return groth16_bn254.Verify(_proof, vk.(*groth16_bn254.VerifyingKey), w)
-	default:
-		panic("unrecognized R1CS curve type")
+//
+// The points of the proof are checked to be in the correct subgroup, which proofs
+// from untrusted sources require; backend.WithSubgroupChecks(false) skips the
+// checks for proofs decoded with ReadFrom, which already did them.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+	b, err := getBackend(proof.CurveID())
+	if err != nil {
+		return err
 	}
+	return b.Verify(proof, vk, publicWitness, opts...)
 }
 
 // Prove runs the groth16.Prove algorithm.
@@ -113,13 +178,11 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
 //	 will produce an invalid proof
 //		internally, the solution vector to the R1CS will be filled with random values which may impact benchmarking
 func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
-
-	switch _r1cs := r1cs.(type) {
-	case *cs_bn254.R1CS:
-		return groth16_bn254.Prove(_r1cs, pk.(*groth16_bn254.ProvingKey), fullWitness, opts...)
-	default:
-		panic("unrecognized R1CS curve type")
+	b, err := backendOf(r1cs)
+	if err != nil {
+		return nil, err
 	}
+	return b.Prove(r1cs, pk, fullWitness, opts...)
 }
 
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
@@ -131,85 +194,188 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 // Two main solutions to this deployment issues are: running the Setup through a MPC (multi party computation)
 // or using a ZKP backend like PLONK where the per-circuit Setup is deterministic.
 func Setup(r1cs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+	b, err := backendOf(r1cs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.Setup(r1cs)
+}
 
-	switch _r1cs := r1cs.(type) {
-	case *cs_bn254.R1CS:
-		var pk groth16_bn254.ProvingKey
-		var vk groth16_bn254.VerifyingKey
-		if err := groth16_bn254.Setup(_r1cs, &pk, &vk); err != nil {
-			return nil, nil, err
-		}
-		return &pk, &vk, nil
-	default:
-		panic("unrecognized R1CS curve type")
+// DevSetup runs groth16.Setup with toxic waste derived from seed: the same circuit
+// and seed always give the same key pair, so that CI environments and developers
+// can share keys without exchanging them.
+//
+// This is UNSAFE: anyone knowing the seed can forge proofs. The keys must never
+// be used in production, see Setup.
+func DevSetup(r1cs constraint.ConstraintSystem, seed []byte) (ProvingKey, VerifyingKey, error) {
+	b, err := backendOf(r1cs)
+	if err != nil {
+		return nil, nil, err
 	}
+	return b.DevSetup(r1cs, seed)
 }
 
 // DummySetup create a random ProvingKey with provided R1CS
 // it doesn't return a VerifyingKey and is use for benchmarking or test purposes only.
 func DummySetup(r1cs constraint.ConstraintSystem) (ProvingKey, error) {
-	switch _r1cs := r1cs.(type) {
-	case *cs_bn254.R1CS:
-		var pk groth16_bn254.ProvingKey
-		if err := groth16_bn254.DummySetup(_r1cs, &pk); err != nil {
-			return nil, err
-		}
-		return &pk, nil
-	default:
-		panic("unrecognized R1CS curve type")
+	b, err := backendOf(r1cs)
+	if err != nil {
+		return nil, err
 	}
+	return b.DummySetup(r1cs)
 }
 
 // NewProvingKey instantiates a curve-typed ProvingKey and returns an interface object
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewProvingKeyE returns an error
 func NewProvingKey(curveID ecc.ID) ProvingKey {
-	var pk ProvingKey
-	switch curveID {
-	case ecc.BN254:
-		pk = &groth16_bn254.ProvingKey{}
-	default:
-		panic("not implemented")
+	res, err := NewProvingKeyE(curveID)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// NewProvingKeyE is NewProvingKey returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewProvingKeyE(curveID ecc.ID) (ProvingKey, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
 	}
-	return pk
+	return b.NewProvingKey(), nil
 }
 
 // NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewVerifyingKeyE returns an error
 func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
-	var vk VerifyingKey
-	switch curveID {
-	case ecc.BN254:
-		vk = &groth16_bn254.VerifyingKey{}
-	default:
-		panic("not implemented")
+	res, err := NewVerifyingKeyE(curveID)
+	if err != nil {
+		panic(err)
 	}
+	return res
+}
 
-	return vk
+// NewVerifyingKeyE is NewVerifyingKey returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewVerifyingKeyE(curveID ecc.ID) (VerifyingKey, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewVerifyingKey(), nil
 }
 
 // NewProof instantiates a curve-typed Proof and returns an interface
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewProofE returns an error
 func NewProof(curveID ecc.ID) Proof {
-	var proof Proof
-	switch curveID {
-	case ecc.BN254:
-		proof = &groth16_bn254.Proof{}
-	default:
-		panic("not implemented")
+	res, err := NewProofE(curveID)
+	if err != nil {
+		panic(err)
 	}
+	return res
+}
+
+// NewProofE is NewProof returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewProofE(curveID ecc.ID) (Proof, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewProof(), nil
+}
 
-	return proof
+// NewSolidityDescriptor returns the descriptor of the public inputs of the verifyProof
+// function of the Solidity verifier exported with vk.ExportSolidity and the same
+// options, so that web frontends can build its calldata. The public inputs are
+// laid out as in layout, see witness.NewPublicLayout; it is an error if vk doesn't
+// expect them.
+func NewSolidityDescriptor(vk VerifyingKey, layout *witness.PublicLayout, opts ...solidity.ExportOption) (*solidity.Descriptor, error) {
+	if err := layout.CheckVerifyingKey(vk); err != nil {
+		return nil, err
+	}
+	function := fmt.Sprintf("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[%d])", vk.NbPublicWitness())
+	return solidity.NewDescriptor(layout, vk.CurveID().ScalarField(), "Verifier", function, 3, opts...)
+}
+
+// EncodeCalldata returns the ABI encoded calldata of a call to the verifyProof
+// function of the Solidity verifier exported with VerifyingKey.ExportSolidity,
+// for the proof and the public witness.
+func EncodeCalldata(proof Proof, publicWitness witness.Witness) ([]byte, error) {
+	b, err := getBackend(proof.CurveID())
+	if err != nil {
+		return nil, err
+	}
+	return b.EncodeCalldata(proof, publicWitness)
+}
+
+// DecodeCalldata returns the proof and the public witness encoded in calldata by
+// EncodeCalldata.
+func DecodeCalldata(curveID ecc.ID, calldata []byte) (Proof, witness.Witness, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.DecodeCalldata(calldata)
+}
+
+// EncodeCairoCalldata returns the calldata, as felt252, of a call to the verify_proof
+// function of the Cairo verifier exported with VerifyingKey.ExportCairo,
+// for the proof and the public witness.
+func EncodeCairoCalldata(proof Proof, publicWitness witness.Witness) ([]*big.Int, error) {
+	b, err := getBackend(proof.CurveID())
+	if err != nil {
+		return nil, err
+	}
+	return b.EncodeCairoCalldata(proof, publicWitness)
+}
+
+// DecodeCairoCalldata returns the proof and the public witness encoded in
+// calldata by EncodeCairoCalldata.
+func DecodeCairoCalldata(curveID ecc.ID, calldata []*big.Int) (Proof, witness.Witness, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.DecodeCairoCalldata(calldata)
+}
+
+// newPublicWitness returns the public witness filled with the nbPublic values.
+func newPublicWitness(curveID ecc.ID, nbPublic int, values chan any) (witness.Witness, error) {
+	publicWitness, err := witness.New(curveID.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err = publicWitness.Fill(nbPublic, 0, values); err != nil {
+		return nil, err
+	}
+	return publicWitness, nil
 }
 
 // NewCS instantiate a concrete curved-typed R1CS and return a R1CS interface
-// This method exists for (de)serialization purposes
+// This method exists for (de)serialization purposes; it panics where
+// NewCSE returns an error
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
-	var r1cs constraint.ConstraintSystem
-	switch curveID {
-	case ecc.BN254:
-		r1cs = &cs_bn254.R1CS{}
-	default:
-		panic("not implemented")
-	}
-	return r1cs
+	res, err := NewCSE(curveID)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// NewCSE is NewCS returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewCSE(curveID ecc.ID) (constraint.ConstraintSystem, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewCS(), nil
 }
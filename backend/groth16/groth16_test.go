@@ -1,15 +1,32 @@
 package groth16_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/stretchr/testify/require"
 )
 
 //--------------------//
@@ -122,3 +139,902 @@ func getCurves() []ecc.ID {
 	}
 	return gnark.Curves()
 }
+
+type snarkJSCircuit struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *snarkJSCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsDifferent(c.X, c.Z)
+	return nil
+}
+
+func TestExportSnarkJS(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &snarkJSCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&snarkJSCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	var bProof, bVK bytes.Buffer
+	assert.NoError(proof.ExportSnarkJS(&bProof))
+	assert.NoError(vk.ExportSnarkJS(&bVK))
+
+	var sProof struct {
+		A        [3]string    `json:"pi_a"`
+		B        [3][2]string `json:"pi_b"`
+		C        [3]string    `json:"pi_c"`
+		Protocol string       `json:"protocol"`
+		Curve    string       `json:"curve"`
+	}
+	var sVK struct {
+		Protocol  string          `json:"protocol"`
+		Curve     string          `json:"curve"`
+		NbPublic  int             `json:"nPublic"`
+		Alpha     [3]string       `json:"vk_alpha_1"`
+		Beta      [3][2]string    `json:"vk_beta_2"`
+		Gamma     [3][2]string    `json:"vk_gamma_2"`
+		Delta     [3][2]string    `json:"vk_delta_2"`
+		AlphaBeta [2][3][2]string `json:"vk_alphabeta_12"`
+		IC        [][3]string     `json:"IC"`
+	}
+	dec := json.NewDecoder(&bProof)
+	dec.DisallowUnknownFields()
+	assert.NoError(dec.Decode(&sProof))
+	dec = json.NewDecoder(&bVK)
+	dec.DisallowUnknownFields()
+	assert.NoError(dec.Decode(&sVK))
+
+	assert.Equal("groth16", sProof.Protocol)
+	assert.Equal("bn128", sProof.Curve)
+	assert.Equal("groth16", sVK.Protocol)
+	assert.Equal("bn128", sVK.Curve)
+	assert.Equal(2, sVK.NbPublic)
+	assert.Len(sVK.IC, 3)
+
+	// run the verifier of snarkjs on the exported values:
+	// e(-A, B)⋅e(α, β)⋅e(Σ ICᵢ⋅xᵢ, γ)⋅e(C, δ) == 1
+	g1 := func(p [3]string) curve.G1Affine {
+		assert.Equal("1", p[2])
+		var res curve.G1Affine
+		_, err := res.X.SetString(p[0])
+		assert.NoError(err)
+		_, err = res.Y.SetString(p[1])
+		assert.NoError(err)
+		assert.True(res.IsOnCurve())
+		return res
+	}
+	e2 := func(a0, a1 *fp.Element, e [2]string) {
+		_, err := a0.SetString(e[0])
+		assert.NoError(err)
+		_, err = a1.SetString(e[1])
+		assert.NoError(err)
+	}
+	g2 := func(p [3][2]string) curve.G2Affine {
+		assert.Equal([2]string{"1", "0"}, p[2])
+		var res curve.G2Affine
+		e2(&res.X.A0, &res.X.A1, p[0])
+		e2(&res.Y.A0, &res.Y.A1, p[1])
+		assert.True(res.IsOnCurve())
+		return res
+	}
+
+	a, c := g1(sProof.A), g1(sProof.C)
+	a.Neg(&a)
+	vkX := g1(sVK.IC[0])
+	for i, x := range []int64{9, 4} {
+		ic := g1(sVK.IC[i+1])
+		ic.ScalarMultiplication(&ic, big.NewInt(x))
+		vkX.Add(&vkX, &ic)
+	}
+	ok, err := curve.PairingCheck(
+		[]curve.G1Affine{a, g1(sVK.Alpha), vkX, c},
+		[]curve.G2Affine{g2(sProof.B), g2(sVK.Beta), g2(sVK.Gamma), g2(sVK.Delta)},
+	)
+	assert.NoError(err)
+	assert.True(ok)
+
+	alphaBeta, err := curve.Pair([]curve.G1Affine{g1(sVK.Alpha)}, []curve.G2Affine{g2(sVK.Beta)})
+	assert.NoError(err)
+	var expected curve.GT
+	e2(&expected.C0.B0.A0, &expected.C0.B0.A1, sVK.AlphaBeta[0][0])
+	e2(&expected.C0.B1.A0, &expected.C0.B1.A1, sVK.AlphaBeta[0][1])
+	e2(&expected.C0.B2.A0, &expected.C0.B2.A1, sVK.AlphaBeta[0][2])
+	e2(&expected.C1.B0.A0, &expected.C1.B0.A1, sVK.AlphaBeta[1][0])
+	e2(&expected.C1.B1.A0, &expected.C1.B1.A1, sVK.AlphaBeta[1][1])
+	e2(&expected.C1.B2.A0, &expected.C1.B2.A1, sVK.AlphaBeta[1][2])
+	assert.True(alphaBeta.Equal(&expected))
+}
+
+func TestCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &snarkJSCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&snarkJSCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	calldata, err := groth16.EncodeCalldata(proof, public)
+	assert.NoError(err)
+	// selector, 8 words of proof and 2 public inputs
+	assert.Len(calldata, 4+(8+2)*32)
+	assert.Equal([]byte{9}, calldata[len(calldata)-2*32:][31:32])
+
+	decodedProof, decodedPublic, err := groth16.DecodeCalldata(ecc.BN254, calldata)
+	assert.NoError(err)
+	assert.Equal(public.Vector(), decodedPublic.Vector())
+	assert.NoError(groth16.Verify(decodedProof, vk, decodedPublic))
+
+	// the selector depends on the number of public inputs
+	calldata = append(calldata, make([]byte, 32)...)
+	_, _, err = groth16.DecodeCalldata(ecc.BN254, calldata)
+	assert.Error(err)
+}
+
+func TestExportCairo(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &snarkJSCircuit{})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportCairo(&buf))
+	code := buf.String()
+	assert.Contains(code, "pub mod Groth16Verifier {")
+	assert.Contains(code, "assert(input.len() == 2, 'verifier-bad-input-length');")
+	assert.Equal(2, strings.Count(code, "bn254.ec_mul(k, "))
+	assert.Equal(1, strings.Count(code, ".pairing_check("))
+	assert.Equal(strings.Count(code, "{"), strings.Count(code, "}"))
+}
+
+func TestCairoCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &snarkJSCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&snarkJSCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	calldata, err := groth16.EncodeCairoCalldata(proof, public)
+	assert.NoError(err)
+	// 8 u256 of proof, the length of the inputs and 2 u256 inputs
+	assert.Len(calldata, 2*8+1+2*2)
+	assert.Equal(big.NewInt(2), calldata[16])
+	assert.Equal(big.NewInt(9), calldata[17])
+	assert.Equal(0, calldata[18].Sign())
+
+	decodedProof, decodedPublic, err := groth16.DecodeCairoCalldata(ecc.BN254, calldata)
+	assert.NoError(err)
+	assert.Equal(public.Vector(), decodedPublic.Vector())
+	assert.NoError(groth16.Verify(decodedProof, vk, decodedPublic))
+
+	// limbs larger than 128 bits
+	calldata[17] = new(big.Int).Lsh(big.NewInt(1), 128)
+	_, _, err = groth16.DecodeCairoCalldata(ecc.BN254, calldata)
+	assert.Error(err)
+}
+
+type digestCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *digestCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type otherDigestCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *otherDigestCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func TestCircuitDigest(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &otherDigestCircuit{})
+	assert.NoError(err)
+	recompiled, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), recompiled.Digest())
+	assert.NotEqual(ccs.Digest(), other.Digest())
+
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), pk.CircuitDigest())
+	assert.Equal(ccs.Digest(), vk.CircuitDigest())
+
+	// the digest is serialized with the keys
+	var buf bytes.Buffer
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk = groth16.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), vk.CircuitDigest())
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	pk = groth16.NewProvingKey(ecc.BN254)
+	_, err = pk.UnsafeReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), pk.CircuitDigest())
+
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	_, err = groth16.Prove(other, pk, w)
+	assert.ErrorIs(err, backend.ErrCircuitMismatch)
+}
+
+func TestUnsafeReadFromValidate(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.NoError(pk.Validate())
+	assert.NoError(vk.Validate())
+
+	var bPK, bVK bytes.Buffer
+	_, err = pk.WriteRawTo(&bPK)
+	assert.NoError(err)
+	_, err = vk.WriteRawTo(&bVK)
+	assert.NoError(err)
+	read := func() (*groth16_bn254.ProvingKey, *groth16_bn254.VerifyingKey) {
+		pk := groth16.NewProvingKey(ecc.BN254)
+		_, err := pk.UnsafeReadFrom(bytes.NewReader(bPK.Bytes()))
+		assert.NoError(err)
+		vk := groth16.NewVerifyingKey(ecc.BN254)
+		_, err = vk.UnsafeReadFrom(bytes.NewReader(bVK.Bytes()))
+		assert.NoError(err)
+		return pk.(*groth16_bn254.ProvingKey), vk.(*groth16_bn254.VerifyingKey)
+	}
+	_pk, _vk := read()
+	assert.NoError(_pk.Validate())
+	assert.NoError(_vk.Validate())
+
+	for _, c := range []struct {
+		name    string
+		corrupt func(pk *groth16_bn254.ProvingKey, vk *groth16_bn254.VerifyingKey)
+		pk, vk  bool // whether the corrupted pk and vk are valid
+	}{
+		{"pk point not on the curve", func(pk *groth16_bn254.ProvingKey, _ *groth16_bn254.VerifyingKey) { pk.G1.A[0].Y.SetOne() }, false, true},
+		{"vk point not on the curve", func(_ *groth16_bn254.ProvingKey, vk *groth16_bn254.VerifyingKey) { vk.G2.Gamma.Y.A0.SetOne() }, true, false},
+		{"inconsistent sizes", func(pk *groth16_bn254.ProvingKey, _ *groth16_bn254.VerifyingKey) {
+			pk.G1.A[0] = pk.G1.A[1]
+			pk.NbInfinityA++
+		}, false, true},
+	} {
+		_pk, _vk := read()
+		c.corrupt(_pk, _vk)
+		assert.Equal(c.pk, _pk.Validate() == nil, c.name)
+		assert.Equal(c.vk, _vk.Validate() == nil, c.name)
+	}
+}
+
+func TestBufferPooling(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	// the second and third proofs reuse the vectors of the previous ones
+	for i := 2; i < 5; i++ {
+		w, err := frontend.NewWitness(&digestCircuit{X: i, Y: i * i}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		public, err := w.Public()
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, w, backend.WithBufferPooling())
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, public))
+	}
+}
+
+func TestProveStream(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	const nbProofs = 6
+	witnesses := make(chan witness.Witness)
+	go func() {
+		defer close(witnesses)
+		for i := 0; i < nbProofs; i++ {
+			y := i * i
+			if i == 3 {
+				y++ // not satisfied
+			}
+			w, err := frontend.NewWitness(&digestCircuit{X: i, Y: y}, ecc.BN254.ScalarField())
+			if err != nil {
+				panic(err)
+			}
+			witnesses <- w
+		}
+	}()
+
+	prover := groth16.NewProver(ccs, pk, backend.WithBufferPooling())
+	i := 0
+	for res := range prover.ProveStream(witnesses) {
+		if i == 3 {
+			assert.Error(res.Err)
+			i++
+			continue
+		}
+		assert.NoError(res.Err, "proof %d", i)
+		public, err := frontend.NewWitness(&digestCircuit{Y: i * i}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(res.Proof, vk, public), "proof %d", i)
+		i++
+	}
+	assert.Equal(nbProofs, i)
+}
+
+type committedDigestCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *committedDigestCircuit) Define(api frontend.API) error {
+	commitment, err := api.Compiler().(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commitment, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestPrecomputedVerifyingKey(t *testing.T) {
+	assert := require.New(t)
+
+	for _, c := range []struct {
+		circuit, valid, wrong frontend.Circuit
+	}{
+		{&digestCircuit{}, &digestCircuit{X: 3, Y: 9}, &digestCircuit{Y: 10}},
+		{&committedDigestCircuit{}, &committedDigestCircuit{X: 3, Y: 9}, &committedDigestCircuit{Y: 10}},
+	} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, c.circuit)
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(c.valid, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		publicW, err := w.Public()
+		assert.NoError(err)
+		wrong, err := frontend.NewWitness(c.wrong, ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+
+		assert.NoError(vk.Precompute())
+		assert.NoError(groth16.Verify(proof, vk, publicW))
+		assert.Error(groth16.Verify(proof, vk, wrong))
+	}
+}
+
+func TestProveSharded(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicW, err := w.Public()
+	assert.NoError(err)
+
+	dir := t.TempDir()
+	assert.NoError(pk.WriteShards(dir, 1))
+	spk, err := groth16.ReadShardedProvingKey(ecc.BN254, dir)
+	assert.NoError(err)
+	assert.Greater(len(spk.(*groth16_bn254.ShardedProvingKey).Index.Sections["G1.A"]), 1)
+
+	proof, err := groth16.ProveSharded(ccs, spk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicW))
+
+	// a shard moved elsewhere, with its absolute path in the index
+	index := spk.(*groth16_bn254.ShardedProvingKey).Index
+	moved := filepath.Join(t.TempDir(), "moved")
+	assert.NoError(os.Rename(filepath.Join(dir, index.Sections["G2.B"][0].File), moved))
+	index.Sections["G2.B"][0].File = moved
+	data, err := json.Marshal(index)
+	assert.NoError(err)
+	assert.NoError(os.WriteFile(filepath.Join(dir, "index.json"), data, 0o644))
+	spk, err = groth16.ReadShardedProvingKey(ecc.BN254, dir)
+	assert.NoError(err)
+	proof, err = groth16.ProveSharded(ccs, spk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicW))
+
+	// a missing shard
+	assert.NoError(os.Remove(moved))
+	_, err = groth16.ProveSharded(ccs, spk, w)
+	assert.Error(err)
+}
+
+type namedInputsCircuit struct {
+	A     frontend.Variable `gnark:",public"`
+	Inner innerInputs
+	X     frontend.Variable
+}
+
+type innerInputs struct {
+	B [2]frontend.Variable `gnark:",public"`
+}
+
+func (c *namedInputsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.Inner.B[0], c.Inner.B[1], c.X), c.A)
+	return nil
+}
+
+func TestVerifyingKeyIntrospection(t *testing.T) {
+	assert := require.New(t)
+
+	for _, c := range []struct {
+		circuit, assignment frontend.Circuit
+		names               []string
+		committed           bool
+	}{
+		{&digestCircuit{}, &digestCircuit{X: 3, Y: 9}, []string{"Y"}, false},
+		{&committedDigestCircuit{}, &committedDigestCircuit{X: 3, Y: 9}, []string{"Y"}, true},
+		{&namedInputsCircuit{}, &namedInputsCircuit{A: 6, Inner: innerInputs{B: [2]frontend.Variable{1, 2}}, X: 3}, []string{"A", "Inner_B_0", "Inner_B_1"}, false},
+	} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, c.circuit)
+		assert.NoError(err)
+		_, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(c.assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+		assert.Equal(len(w.Vector().(fr.Vector)), vk.NbPublicWitness())
+		assert.Equal(ecc.BN254, vk.CurveID())
+		assert.Equal(c.committed, vk.Commitment().Is())
+		assert.Equal(c.names, vk.PublicInputNames())
+
+		// the names are serialized with the key
+		var buf bytes.Buffer
+		_, err = vk.WriteTo(&buf)
+		assert.NoError(err)
+		vk2 := groth16.NewVerifyingKey(ecc.BN254)
+		_, err = vk2.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(c.names, vk2.PublicInputNames())
+	}
+}
+
+func TestProofSubgroupChecks(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+
+	// both readers decode a valid proof
+	var buf bytes.Buffer
+	_, err = proof.WriteRawTo(&buf)
+	assert.NoError(err)
+	raw := buf.Bytes()
+	for _, read := range []func(groth16.Proof) error{
+		func(p groth16.Proof) error { _, err := p.ReadFrom(bytes.NewReader(raw)); return err },
+		func(p groth16.Proof) error { _, err := p.UnsafeReadFrom(bytes.NewReader(raw)); return err },
+	} {
+		p := groth16.NewProof(ecc.BN254)
+		assert.NoError(read(p))
+		assert.NoError(groth16.Verify(p, vk, publicWitness))
+		assert.NoError(groth16.Verify(p, vk, publicWitness, backend.WithSubgroupChecks(false)))
+	}
+
+	// a point on the curve out of the subgroup
+	_proof := proof.(*groth16_bn254.Proof)
+	_proof.Bs = g2OutOfSubgroup()
+	buf.Reset()
+	_, err = proof.WriteRawTo(&buf)
+	assert.NoError(err)
+	raw = buf.Bytes()
+
+	_, err = groth16.NewProof(ecc.BN254).ReadFrom(bytes.NewReader(raw))
+	assert.Error(err)
+	p := groth16.NewProof(ecc.BN254)
+	_, err = p.UnsafeReadFrom(bytes.NewReader(raw))
+	assert.NoError(err)
+	assert.ErrorContains(groth16.Verify(p, vk, publicWitness), "subgroup")
+	err = groth16.Verify(p, vk, publicWitness, backend.WithSubgroupChecks(false))
+	assert.Error(err)
+	assert.NotContains(err.Error(), "subgroup")
+}
+
+// g2OutOfSubgroup returns a point of the twist which is not in G2.
+func g2OutOfSubgroup() curve.G2Affine {
+	var p curve.G2Affine
+	// y² = x³ + 3/(9+u)
+	b := p.X
+	b.A0.SetUint64(9)
+	b.A1.SetOne()
+	b.Inverse(&b)
+	b.MulByElement(&b, new(fp.Element).SetUint64(3))
+	for i := uint64(1); ; i++ {
+		p.X.A0.SetUint64(i)
+		p.Y.Square(&p.X).Mul(&p.Y, &p.X).Add(&p.Y, &b)
+		if p.Y.Legendre() != 1 {
+			continue
+		}
+		p.Y.Sqrt(&p.Y)
+		if p.IsOnCurve() && !p.IsInSubGroup() {
+			return p
+		}
+	}
+}
+
+func TestUntrustedVerifier(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+
+	var bProof, bPublic, bFull bytes.Buffer
+	_, err = proof.WriteTo(&bProof)
+	assert.NoError(err)
+	_, err = publicWitness.WriteTo(&bPublic)
+	assert.NoError(err)
+	_, err = fullWitness.WriteTo(&bFull)
+	assert.NoError(err)
+
+	v, err := groth16.NewUntrustedVerifier(vk)
+	assert.NoError(err)
+	ctx := context.Background()
+	assert.NoError(v.Verify(ctx, bytes.NewReader(bProof.Bytes()), bytes.NewReader(bPublic.Bytes())))
+	var armored bytes.Buffer
+	_, err = gnarkio.WriteArmored(&armored, proof)
+	assert.NoError(err)
+	assert.NoError(v.Verify(ctx, &armored, bytes.NewReader(bPublic.Bytes())))
+
+	// rejected inputs
+	var malformed [12]byte // a public witness announcing a huge vector
+	binary.BigEndian.PutUint32(malformed[0:], 1<<31)
+	binary.BigEndian.PutUint32(malformed[8:], 1<<31)
+	for _, c := range []struct {
+		name          string
+		opts          []groth16.UntrustedVerifierOption
+		proof, public []byte
+		err           error // the expected error, or nil for any
+	}{
+		{"full witness", nil, bProof.Bytes(), bFull.Bytes(), nil},
+		{"proof too large", nil, append(bProof.Bytes(), make([]byte, 1<<13)...), bPublic.Bytes(), groth16.ErrInputTooLarge},
+		{"public witness too large", []groth16.UntrustedVerifierOption{groth16.WithMaxPublicWitnessSize(16)}, bProof.Bytes(), bPublic.Bytes(), groth16.ErrInputTooLarge},
+		{"malformed public witness", nil, bProof.Bytes(), malformed[:], nil},
+	} {
+		v, err := groth16.NewUntrustedVerifier(vk, c.opts...)
+		assert.NoError(err)
+		err = v.Verify(ctx, bytes.NewReader(c.proof), bytes.NewReader(c.public))
+		if c.err != nil {
+			assert.ErrorIs(err, c.err, c.name)
+		} else {
+			assert.Error(err, c.name)
+		}
+	}
+
+	// the context is checked
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(v.Verify(canceled, bytes.NewReader(bProof.Bytes()), bytes.NewReader(bPublic.Bytes())), context.Canceled)
+
+	// the verifications in excess wait for a slot
+	v, err = groth16.NewUntrustedVerifier(vk, groth16.WithMaxConcurrency(1))
+	assert.NoError(err)
+	pr, pw := io.Pipe()
+	done := make(chan error)
+	go func() { done <- v.Verify(ctx, pr, bytes.NewReader(bPublic.Bytes())) }()
+	time.Sleep(10 * time.Millisecond)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(v.Verify(timeout, bytes.NewReader(bProof.Bytes()), bytes.NewReader(bPublic.Bytes())), context.DeadlineExceeded)
+	_, err = pw.Write(bProof.Bytes())
+	assert.NoError(err)
+	assert.NoError(pw.Close())
+	assert.NoError(<-done)
+}
+
+func TestReadWitnessMalformedLength(t *testing.T) {
+	assert := require.New(t)
+
+	// the announced length must match the number of elements, and doesn't trigger
+	// allocations larger than the data
+	var malformed [12]byte
+	binary.BigEndian.PutUint32(malformed[0:], 1<<30)
+	binary.BigEndian.PutUint32(malformed[8:], 1<<30)
+	w, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = w.ReadFrom(bytes.NewReader(malformed[:]))
+	assert.Error(err)
+
+	binary.BigEndian.PutUint32(malformed[8:], 2)
+	_, err = w.ReadFrom(bytes.NewReader(malformed[:]))
+	assert.ErrorIs(err, witness.ErrInvalidWitness)
+}
+
+func TestMetadata(t *testing.T) {
+	assert := require.New(t)
+
+	metadata := constraint.Metadata{"version": "v1.2.0", "source": "abcdef"}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{},
+		frontend.WithMetadata("version", "v1.2.0"), frontend.WithMetadata("source", "abcdef"), frontend.WithBuildMetadata())
+	assert.NoError(err)
+	for k, v := range metadata {
+		assert.Equal(v, ccs.GetMetadata()[k])
+	}
+	metadata = ccs.GetMetadata()
+
+	// the metadata don't change the digest
+	plain, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	assert.Nil(plain.GetMetadata())
+	assert.Equal(plain.Digest(), ccs.Digest())
+
+	// the metadata are serialized with the constraint system
+	var bCCS bytes.Buffer
+	_, err = ccs.WriteTo(&bCCS)
+	assert.NoError(err)
+	decoded := groth16.NewCS(ecc.BN254)
+	_, err = decoded.ReadFrom(&bCCS)
+	assert.NoError(err)
+	assert.Equal(metadata, decoded.GetMetadata())
+
+	// and with the keys
+	pk, vk, err := groth16.Setup(decoded)
+	assert.NoError(err)
+	assert.Equal(metadata, pk.Metadata())
+	assert.Equal(metadata, vk.Metadata())
+	var bPK, bVK bytes.Buffer
+	_, err = pk.WriteTo(&bPK)
+	assert.NoError(err)
+	_, err = vk.WriteTo(&bVK)
+	assert.NoError(err)
+	pk = groth16.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&bPK)
+	assert.NoError(err)
+	vk = groth16.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&bVK)
+	assert.NoError(err)
+	assert.Equal(metadata, pk.Metadata())
+	assert.Equal(metadata, vk.Metadata())
+}
+
+func TestDevSetup(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+
+	serialize := func(pk groth16.ProvingKey, vk groth16.VerifyingKey) []byte {
+		var buf bytes.Buffer
+		_, err := pk.WriteRawTo(&buf)
+		assert.NoError(err)
+		_, err = vk.WriteRawTo(&buf)
+		assert.NoError(err)
+		return buf.Bytes()
+	}
+
+	pk, vk, err := groth16.DevSetup(ccs, []byte("ci"))
+	assert.NoError(err)
+	pk2, vk2, err := groth16.DevSetup(ccs, []byte("ci"))
+	assert.NoError(err)
+	assert.Equal(serialize(pk, vk), serialize(pk2, vk2), "the same seed must give the same keys")
+	pk2, vk2, err = groth16.DevSetup(ccs, []byte("other"))
+	assert.NoError(err)
+	assert.NotEqual(serialize(pk, vk), serialize(pk2, vk2))
+
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pw))
+	assert.Error(groth16.Verify(proof, vk2, pw))
+}
+
+func TestVerifyingKeyJSON(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{},
+		frontend.WithMetadata("version", "v1.2.0"))
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	data, err := json.MarshalIndent(vk, "", "  ")
+	assert.NoError(err)
+	assert.Contains(string(data), `"circuitDigest": "`+ccs.Digest().String()+`"`)
+	assert.Contains(string(data), `"publicInputNames": [`)
+
+	decoded := groth16.NewVerifyingKey(ecc.BN254)
+	assert.NoError(json.Unmarshal(data, decoded))
+	assert.Equal(vk.CircuitDigest(), decoded.CircuitDigest())
+	assert.Equal(vk.PublicInputNames(), decoded.PublicInputNames())
+	assert.Equal(vk.Metadata(), decoded.Metadata())
+
+	// the decoded key is the same as the original one
+	var expected, actual bytes.Buffer
+	_, err = vk.WriteTo(&expected)
+	assert.NoError(err)
+	_, err = decoded.WriteTo(&actual)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), actual.Bytes())
+
+	// and verifies the proofs of the original one
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, decoded, pw))
+
+	// keys of another scheme or curve are rejected
+	assert.Error(json.Unmarshal([]byte(strings.Replace(string(data), `"groth16"`, `"plonk"`, 1)), decoded))
+	assert.Error(json.Unmarshal([]byte(strings.Replace(string(data), `"bn254"`, `"bls12_381"`, 1)), decoded))
+}
+
+func TestRegistry(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Contains(groth16.Curves(), ecc.BN254)
+	assert.NotContains(groth16.Curves(), ecc.BLS12_381)
+
+	// a curve is registered once
+	assert.Panics(func() { groth16.Register(ecc.BN254, nil) })
+
+	// curves without implementation
+	assert.Panics(func() { groth16.NewProvingKey(ecc.BLS12_381) })
+	for _, c := range []struct {
+		name string
+		f    func() error
+	}{
+		{"DecodeCalldata", func() error { _, _, err := groth16.DecodeCalldata(ecc.BLS12_381, nil); return err }},
+		{"ReadShardedProvingKey", func() error { _, err := groth16.ReadShardedProvingKey(ecc.BLS12_381, t.TempDir()); return err }},
+		{"NewCSE", func() error { _, err := groth16.NewCSE(ecc.BLS12_381); return err }},
+		{"NewProvingKeyE", func() error { _, err := groth16.NewProvingKeyE(ecc.BLS12_381); return err }},
+		{"NewVerifyingKeyE", func() error { _, err := groth16.NewVerifyingKeyE(ecc.BLS12_381); return err }},
+		{"NewProofE", func() error { _, err := groth16.NewProofE(ecc.BLS12_381); return err }},
+	} {
+		assert.ErrorIs(c.f(), backend.ErrUnsupportedCurve, c.name)
+	}
+}
+
+func TestVerifyBytes(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	bProof := append([]byte(nil), buf.Bytes()...)
+	buf.Reset()
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	bVK := append([]byte(nil), buf.Bytes()...)
+	bPW, err := pw.MarshalBinary()
+	assert.NoError(err)
+	bW, err := w.MarshalBinary()
+	assert.NoError(err)
+	assert.NoError(groth16.VerifyBytes(ecc.BN254, bProof, bVK, bPW))
+
+	// rejected inputs
+	wrong, err := frontend.NewWitness(&digestCircuit{Y: 10}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	bWrong, err := wrong.MarshalBinary()
+	assert.NoError(err)
+	for _, c := range []struct {
+		name               string
+		curve              ecc.ID
+		proof, vk, witness []byte
+	}{
+		{"wrong public input", ecc.BN254, bProof, bVK, bWrong},
+		{"full witness", ecc.BN254, bProof, bVK, bW},
+		{"trailing bytes", ecc.BN254, append(append([]byte(nil), bProof...), 0), bVK, bPW},
+		{"truncated proof", ecc.BN254, bProof[:len(bProof)-1], bVK, bPW},
+		{"other curve", ecc.BLS12_381, bProof, bVK, bPW},
+	} {
+		assert.Error(groth16.VerifyBytes(c.curve, c.proof, c.vk, c.witness), c.name)
+	}
+}
+
+func TestReadOtherMinorVersion(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &digestCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// gnark is at 0.x, where minor releases may change the formats: the header
+	// holds the major, minor and patch versions after the magic, the format
+	// version and the object type.
+	const minorOffset = 8 + 2*3
+	for _, c := range []struct {
+		name string
+		w    io.WriterTo
+		r    io.ReaderFrom
+	}{
+		{"R1CS", ccs, groth16.NewCS(ecc.BN254)},
+		{"proving key", pk, groth16.NewProvingKey(ecc.BN254)},
+		{"verifying key", vk, groth16.NewVerifyingKey(ecc.BN254)},
+		{"proof", proof, groth16.NewProof(ecc.BN254)},
+	} {
+		var buf bytes.Buffer
+		_, err := c.w.WriteTo(&buf)
+		assert.NoError(err, c.name)
+		data := buf.Bytes()
+		minor := binary.BigEndian.Uint16(data[minorOffset:])
+		binary.BigEndian.PutUint16(data[minorOffset:], minor+1)
+		_, err = c.r.ReadFrom(bytes.NewReader(data))
+		assert.ErrorIs(err, gnarkio.ErrIncompatibleVersion, c.name)
+	}
+}
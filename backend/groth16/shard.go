@@ -0,0 +1,53 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// ShardedProvingKey is a proving key written by ProvingKey.WriteShards, of which only
+// the index of the shards and the fields other than the points are held in memory.
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type ShardedProvingKey interface {
+	CurveID() ecc.ID
+}
+
+// ReadShardedProvingKey reads the proving key written by ProvingKey.WriteShards in dir.
+// The shard files listed in its index.json may be moved elsewhere, for instance on a
+// storage local to the NUMA node running the prover, if their paths are updated.
+func ReadShardedProvingKey(curveID ecc.ID, dir string) (ShardedProvingKey, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.ReadShardedProvingKey(dir)
+}
+
+// ProveSharded is Prove with a proving key read by ReadShardedProvingKey: each section
+// of points is streamed from its shards, the next shard being read while the
+// multi-exponentiation runs on the current one, so the key doesn't need to fit in
+// memory.
+func ProveSharded(r1cs constraint.ConstraintSystem, pk ShardedProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	b, err := backendOf(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	return b.ProveSharded(r1cs, pk, fullWitness, opts...)
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// streamDepth is the number of proofs ProveStream computes at once
+const streamDepth = 2
+
+// Prover produces the Groth16 proofs of a circuit with a proving key.
+type Prover struct {
+	r1cs constraint.ConstraintSystem
+	pk   ProvingKey
+	opts []backend.ProverOption
+}
+
+// ProofResult is a proof produced by Prover.ProveStream, or the error which
+// prevented it.
+type ProofResult struct {
+	Proof Proof
+	Err   error
+}
+
+// NewProver returns a Prover of the circuit r1cs with the proving key pk. The
+// options are applied to all its proofs.
+func NewProver(r1cs constraint.ConstraintSystem, pk ProvingKey, opts ...backend.ProverOption) *Prover {
+	return &Prover{r1cs: r1cs, pk: pk, opts: opts}
+}
+
+// Prove runs the groth16.Prove algorithm with the full witness.
+func (p *Prover) Prove(fullWitness witness.Witness) (Proof, error) {
+	return Prove(p.r1cs, p.pk, fullWitness, p.opts...)
+}
+
+// ProveStream proves the full witnesses received from witnesses, and sends the
+// results on the returned channel in the same order. The channel is closed once
+// witnesses is closed and all the proofs are sent.
+//
+// The successive proofs are pipelined: the witness of a proof is solved while the
+// FFTs and MSMs of the previous one run, which keeps both the memory bandwidth and
+// the cores busy. The memory used is thus the one of two proofs; the scratch
+// vectors are reused across the proofs with backend.WithBufferPooling.
+func (p *Prover) ProveStream(witnesses <-chan witness.Witness) <-chan ProofResult {
+	return utils.Pipeline(witnesses, streamDepth, func(fullWitness witness.Witness) ProofResult {
+		proof, err := p.Prove(fullWitness)
+		return ProofResult{Proof: proof, Err: err}
+	})
+}
@@ -0,0 +1,206 @@
+package groth16
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// ErrInputTooLarge is returned by UntrustedVerifier.Verify when a proof or a
+// public witness exceeds its size limit.
+var ErrInputTooLarge = errors.New("input exceeds the size limit")
+
+// UntrustedVerifierOption configures an UntrustedVerifier.
+type UntrustedVerifierOption func(*UntrustedVerifierConfig) error
+
+// UntrustedVerifierConfig is the configuration of an UntrustedVerifier with the
+// options applied.
+type UntrustedVerifierConfig struct {
+	// MaxProofSize is the maximum size in bytes of a serialized proof, set by
+	// WithMaxProofSize.
+	MaxProofSize int64
+	// MaxPublicWitnessSize is the maximum size in bytes of a serialized public
+	// witness, set by WithMaxPublicWitnessSize.
+	MaxPublicWitnessSize int64
+	// MaxConcurrency is the maximum number of concurrent verifications, set by
+	// WithMaxConcurrency; 0 for no limit.
+	MaxConcurrency int
+}
+
+// WithMaxProofSize sets the maximum size in bytes of a serialized proof. The
+// default allows the proofs of the key in any of the encodings of gnark (raw,
+// compressed or armored).
+func WithMaxProofSize(size int64) UntrustedVerifierOption {
+	return func(cfg *UntrustedVerifierConfig) error {
+		if size <= 0 {
+			return errors.New("the maximum proof size must be positive")
+		}
+		cfg.MaxProofSize = size
+		return nil
+	}
+}
+
+// WithMaxPublicWitnessSize sets the maximum size in bytes of a serialized public
+// witness. The default allows the public witnesses of the key in any of the
+// encodings of gnark.
+func WithMaxPublicWitnessSize(size int64) UntrustedVerifierOption {
+	return func(cfg *UntrustedVerifierConfig) error {
+		if size <= 0 {
+			return errors.New("the maximum public witness size must be positive")
+		}
+		cfg.MaxPublicWitnessSize = size
+		return nil
+	}
+}
+
+// WithMaxConcurrency bounds the number of concurrent verifications: the calls in
+// excess wait for a slot, until their context is done. A verification whose
+// context is done keeps its slot until it completes, as it can't be
+// interrupted.
+func WithMaxConcurrency(n int) UntrustedVerifierOption {
+	return func(cfg *UntrustedVerifierConfig) error {
+		if n < 0 {
+			return errors.New("the maximum concurrency must not be negative")
+		}
+		cfg.MaxConcurrency = n
+		return nil
+	}
+}
+
+// UntrustedVerifier verifies serialized proofs and public witnesses from
+// untrusted sources, such as the requests to a public endpoint, for a verifying
+// key.
+//
+// The inputs are read up to the size limits of the verifier, the allocations
+// are bounded by the size of the data read and not by the lengths it announces,
+// and the points of the proof are checked to be in the correct subgroup. The
+// verifications can be bounded in number and in time. Rate limiting the calls,
+// overall or per client, is left to the caller: WithMaxConcurrency bounds the
+// verifications in progress, not how often they are requested.
+type UntrustedVerifier struct {
+	vk  VerifyingKey
+	cfg UntrustedVerifierConfig
+	sem chan struct{}
+}
+
+// NewUntrustedVerifier returns an UntrustedVerifier of proofs for vk.
+func NewUntrustedVerifier(vk VerifyingKey, opts ...UntrustedVerifierOption) (*UntrustedVerifier, error) {
+	// an element of the public witness, or a coordinate of a point, is at most 48
+	// bytes with the supported curves; an armored object is at most twice as
+	// large as the object
+	elementSize := int64((vk.CurveID().ScalarField().BitLen() + 7) / 8)
+	cfg := UntrustedVerifierConfig{
+		MaxProofSize:         1 << 12,
+		MaxPublicWitnessSize: 1<<10 + 2*(12+elementSize*int64(vk.NbPublicWitness())),
+	}
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	v := &UntrustedVerifier{vk: vk, cfg: cfg}
+	if cfg.MaxConcurrency > 0 {
+		v.sem = make(chan struct{}, cfg.MaxConcurrency)
+	}
+	return v, nil
+}
+
+// Verify reads a proof and a public witness from the untrusted readers and
+// verifies them. It returns ctx.Err() if ctx is done before the verification
+// completes.
+//
+// Each reader must hold a single object, read with ReadFrom (which checks the
+// points of the proof), and is read up to the size limits of the verifier plus a
+// byte to detect larger inputs.
+func (v *UntrustedVerifier) Verify(ctx context.Context, proof, publicWitness io.Reader) error {
+	if v.sem != nil {
+		select {
+		case v.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	_proof, w, err := v.read(ctx, proof, publicWitness)
+	if err != nil {
+		v.release()
+		return err
+	}
+
+	// the verification itself can't be interrupted: it runs until completion in
+	// its own goroutine, which holds the concurrency slot until then, and its
+	// result is discarded if ctx is done first
+	done := make(chan error, 1)
+	go func() {
+		defer v.release()
+		done <- Verify(_proof, v.vk, w, backend.WithSubgroupChecks(false))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// read reads a proof and a public witness from the untrusted readers, see
+// Verify.
+func (v *UntrustedVerifier) read(ctx context.Context, proof, publicWitness io.Reader) (Proof, witness.Witness, error) {
+	_proof := NewProof(v.vk.CurveID())
+	if err := readLimited(_proof.ReadFrom, proof, v.cfg.MaxProofSize); err != nil {
+		return nil, nil, fmt.Errorf("read proof: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	w, err := witness.New(v.vk.CurveID().ScalarField())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := readLimited(w.ReadFrom, publicWitness, v.cfg.MaxPublicWitnessSize); err != nil {
+		return nil, nil, fmt.Errorf("read public witness: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return _proof, w, nil
+}
+
+// release frees the concurrency slot taken by a verification, if the
+// concurrency is bounded.
+func (v *UntrustedVerifier) release() {
+	if v.sem != nil {
+		<-v.sem
+	}
+}
+
+// readLimited reads an object from r with readFrom. r must hold the object only,
+// in at most limit bytes.
+func readLimited(readFrom func(io.Reader) (int64, error), r io.Reader, limit int64) error {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > limit {
+		return ErrInputTooLarge
+	}
+	return readBytes(readFrom, data)
+}
+
+// readBytes reads an object from data with readFrom. data must hold the object
+// only.
+func readBytes(readFrom func(io.Reader) (int64, error), data []byte) error {
+	n, err := readFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return fmt.Errorf("%d unexpected bytes after the object", int64(len(data))-n)
+	}
+	return nil
+}
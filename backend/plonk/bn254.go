@@ -0,0 +1,188 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !gnark_no_bn254
+
+package plonk
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+)
+
+func init() {
+	Register(ecc.BN254, bn254Backend{})
+}
+
+// bn254Backend is the implementation of PLONK over BN254.
+type bn254Backend struct{}
+
+func (bn254Backend) NewCS() constraint.ConstraintSystem {
+	return &cs_bn254.SparseR1CS{}
+}
+
+func (bn254Backend) NewProvingKey() ProvingKey {
+	return &plonk_bn254.ProvingKey{}
+}
+
+func (bn254Backend) NewVerifyingKey() VerifyingKey {
+	return &plonk_bn254.VerifyingKey{}
+}
+
+func (bn254Backend) NewProof() Proof {
+	return &plonk_bn254.Proof{}
+}
+
+func (bn254Backend) Setup(spr constraint.ConstraintSystem, srs kzg.SRS, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error) {
+	_spr, ok := spr.(*cs_bn254.SparseR1CS)
+	if !ok {
+		return nil, nil, errMismatch("constraint system", spr)
+	}
+	_srs, ok := srs.(*kzg_bn254.SRS)
+	if !ok {
+		return nil, nil, errMismatch("SRS", srs)
+	}
+	return plonk_bn254.Setup(_spr, _srs, opts...)
+}
+
+func (bn254Backend) NewLagrangeSRS(spr constraint.ConstraintSystem, srs kzg.SRS) (kzg.SRS, error) {
+	_spr, ok := spr.(*cs_bn254.SparseR1CS)
+	if !ok {
+		return nil, errMismatch("constraint system", spr)
+	}
+	_srs, ok := srs.(*kzg_bn254.SRS)
+	if !ok {
+		return nil, errMismatch("SRS", srs)
+	}
+	lagrangeSRS, err := plonk_bn254.NewLagrangeSRS(_srs, plonk_bn254.DomainSize(_spr))
+	if err != nil {
+		return nil, err
+	}
+	return lagrangeSRS, nil
+}
+
+func (bn254Backend) DomainSize(spr constraint.ConstraintSystem) (uint64, error) {
+	_spr, ok := spr.(*cs_bn254.SparseR1CS)
+	if !ok {
+		return 0, errMismatch("constraint system", spr)
+	}
+	return plonk_bn254.DomainSize(_spr), nil
+}
+
+func (bn254Backend) Prove(spr constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	_spr, ok := spr.(*cs_bn254.SparseR1CS)
+	if !ok {
+		return nil, errMismatch("constraint system", spr)
+	}
+	_pk, ok := pk.(*plonk_bn254.ProvingKey)
+	if !ok {
+		return nil, errMismatch("proving key", pk)
+	}
+	return plonk_bn254.Prove(_spr, _pk, fullWitness, opts...)
+}
+
+func (bn254Backend) Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
+	_proof, ok := proof.(*plonk_bn254.Proof)
+	if !ok {
+		return errMismatch("proof", proof)
+	}
+	_vk, ok := vk.(*plonk_bn254.VerifyingKey)
+	if !ok {
+		return errMismatch("verifying key", vk)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return witness.ErrInvalidWitness
+	}
+	return plonk_bn254.Verify(_proof, _vk, w)
+}
+
+func (bn254Backend) NewSolidityDescriptor(vk VerifyingKey, layout *witness.PublicLayout, opts ...solidity.ExportOption) (*solidity.Descriptor, error) {
+	if _, ok := vk.(*plonk_bn254.VerifyingKey); !ok {
+		return nil, errMismatch("verifying key", vk)
+	}
+	return solidity.NewDescriptor(layout, ecc.BN254.ScalarField(), "KeyedPlonkVerifier", "verify_serialized_proof(uint256[],uint256[])", 0, opts...)
+}
+
+func (bn254Backend) EncodeCalldata(proof Proof, publicWitness witness.Witness) ([]byte, error) {
+	_proof, ok := proof.(*plonk_bn254.Proof)
+	if !ok {
+		return nil, errMismatch("proof", proof)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	return _proof.EncodeCalldata(w)
+}
+
+func (bn254Backend) DecodeCalldata(calldata []byte) (Proof, witness.Witness, error) {
+	proof := &plonk_bn254.Proof{}
+	public, err := proof.DecodeCalldata(calldata)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err := newPublicWitnessBN254(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+func (bn254Backend) EncodeCairoCalldata(proof Proof, publicWitness witness.Witness) ([]*big.Int, error) {
+	_proof, ok := proof.(*plonk_bn254.Proof)
+	if !ok {
+		return nil, errMismatch("proof", proof)
+	}
+	w, ok := publicWitness.Vector().(fr_bn254.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	return _proof.EncodeCairoCalldata(w)
+}
+
+func (bn254Backend) DecodeCairoCalldata(calldata []*big.Int) (Proof, witness.Witness, error) {
+	proof := &plonk_bn254.Proof{}
+	public, err := proof.DecodeCairoCalldata(calldata)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicWitness, err := newPublicWitnessBN254(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, publicWitness, nil
+}
+
+// newPublicWitnessBN254 returns the public witness of the values of public.
+func newPublicWitnessBN254(public fr_bn254.Vector) (witness.Witness, error) {
+	values := make(chan any)
+	go func() {
+		defer close(values)
+		for i := range public {
+			values <- public[i]
+		}
+	}()
+	return newPublicWitness(ecc.BN254, len(public), values)
+}
@@ -19,20 +19,38 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 
-	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
 	"io"
+	"math/big"
+
+	"errors"
+	"github.com/consensys/gnark/backend"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// serialized objects are framed by a header identifying the object, the curve,
+// the backend and the gnark version (see gnarkio.WriteWithHeader)
+var (
+	proofHeader = gnarkio.NewHeader(gnarkio.Proof, curve.ID, backend.PLONK)
+	vkHeader    = gnarkio.NewHeader(gnarkio.VerifyingKey, curve.ID, backend.PLONK)
+	pkHeader    = gnarkio.NewHeader(gnarkio.ProvingKey, curve.ID, backend.PLONK)
 )
 
-// WriteTo writes binary encoding of Proof to w without point compression
+// WriteRawTo writes binary encoding of Proof to w without point compression
 func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
-	return proof.writeTo(w, curve.RawEncoding())
+	return gnarkio.WriteWithHeader(w, proofHeader, func(w io.Writer) error {
+		_, err := proof.writeTo(w, curve.RawEncoding())
+		return err
+	})
 }
 
 // WriteTo writes binary encoding of Proof to w with point compression
 func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
-	return proof.writeTo(w)
+	return gnarkio.WriteWithHeader(w, proofHeader, func(w io.Writer) error {
+		_, err := proof.writeTo(w)
+		return err
+	})
 }
 
 func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64, error) {
@@ -50,6 +68,7 @@ func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64
 		proof.BatchedProof.ClaimedValues,
 		&proof.ZShiftedOpening.H,
 		&proof.ZShiftedOpening.ClaimedValue,
+		proof.Bsb22Commitments,
 	}
 
 	for _, v := range toEncode {
@@ -63,6 +82,13 @@ func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64
 
 // ReadFrom reads binary representation of Proof from r
 func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	return gnarkio.ReadWithHeader(r, proofHeader, func(r io.Reader) error {
+		_, err := proof.readFrom(r)
+		return err
+	})
+}
+
+func (proof *Proof) readFrom(r io.Reader) (int64, error) {
 	dec := curve.NewDecoder(r)
 	toDecode := []interface{}{
 		&proof.LRO[0],
@@ -76,6 +102,7 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 		&proof.BatchedProof.ClaimedValues,
 		&proof.ZShiftedOpening.H,
 		&proof.ZShiftedOpening.ClaimedValue,
+		&proof.Bsb22Commitments,
 	}
 
 	for _, v := range toDecode {
@@ -89,8 +116,15 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 
 // WriteTo writes binary encoding of ProvingKey to w
 func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return gnarkio.WriteWithHeader(w, pkHeader, func(w io.Writer) error {
+		_, err := pk.writeTo(w)
+		return err
+	})
+}
+
+func (pk *ProvingKey) writeTo(w io.Writer) (n int64, err error) {
 	// encode the verifying key
-	n, err = pk.Vk.WriteTo(w)
+	n, err = pk.Vk.writeTo(w)
 	if err != nil {
 		return
 	}
@@ -117,17 +151,28 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 	// note: type Polynomial, which is handled by default binary.Write(...) op and doesn't
 	// encode the size (nor does it convert from Montgomery to Regular form)
 	// so we explicitly transmit []fr.Element
+	// the polynomials dropped by Trim are written empty
+	coefficients := func(p *iop.Polynomial) []fr.Element {
+		if p == nil {
+			return nil
+		}
+		return p.Coefficients()
+	}
 	toEncode := []interface{}{
 		([]fr.Element)(pk.trace.Ql.Coefficients()),
 		([]fr.Element)(pk.trace.Qr.Coefficients()),
 		([]fr.Element)(pk.trace.Qm.Coefficients()),
 		([]fr.Element)(pk.trace.Qo.Coefficients()),
 		([]fr.Element)(pk.trace.Qk.Coefficients()),
-		([]fr.Element)(pk.lQk.Coefficients()),
-		([]fr.Element)(pk.trace.S1.Coefficients()),
-		([]fr.Element)(pk.trace.S2.Coefficients()),
-		([]fr.Element)(pk.trace.S3.Coefficients()),
+		coefficients(pk.lQk),
+		coefficients(pk.trace.S1),
+		coefficients(pk.trace.S2),
+		coefficients(pk.trace.S3),
 		pk.trace.S,
+		uint64(len(pk.trace.Qcp)),
+	}
+	for _, qcp := range pk.trace.Qcp {
+		toEncode = append(toEncode, ([]fr.Element)(qcp.Coefficients()))
 	}
 
 	for _, v := range toEncode {
@@ -136,13 +181,36 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 		}
 	}
 
-	return n + enc.BytesWritten(), nil
+	// the digest and the metadata of the verifying key are written last, so that
+	// keys written without them can still be read
+	n2, err = pk.Vk.circuitDigest.WriteTo(w)
+	if err != nil {
+		return n + enc.BytesWritten() + n2, err
+	}
+	n3, err := pk.Vk.metadata.WriteTo(w)
+	return n + enc.BytesWritten() + n2 + n3, err
 }
 
 // ReadFrom reads from binary representation in r into ProvingKey
 func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return gnarkio.ReadWithHeader(r, pkHeader, func(r io.Reader) error {
+		_, err := pk.readFrom(r)
+		return err
+	})
+}
+
+// UnsafeReadFrom behaves like ReadFrom except that it doesn't check that the decoded
+// points are on the curve and in the correct subgroup. See Validate.
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return gnarkio.ReadWithHeader(r, pkHeader, func(r io.Reader) error {
+		_, err := pk.readFrom(r, curve.NoSubgroupChecks())
+		return err
+	})
+}
+
+func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	pk.Vk = &VerifyingKey{}
-	n, err := pk.Vk.ReadFrom(r)
+	n, err := pk.Vk.readFrom(r, decOptions...)
 	if err != nil {
 		return n, err
 	}
@@ -161,9 +229,10 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 
 	pk.trace.S = make([]int64, 3*pk.Domain[0].Cardinality)
 
-	dec := curve.NewDecoder(r)
+	dec := curve.NewDecoder(r, decOptions...)
 
 	var ql, qr, qm, qo, qk, lqk, s1, s2, s3 []fr.Element
+	var nbQcp uint64
 	toDecode := []interface{}{
 		&ql,
 		&qr,
@@ -175,6 +244,7 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		&s2,
 		&s3,
 		&pk.trace.S,
+		&nbQcp,
 	}
 
 	for _, v := range toDecode {
@@ -189,21 +259,54 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 	pk.trace.Qm = iop.NewPolynomial(&qm, canReg)
 	pk.trace.Qo = iop.NewPolynomial(&qo, canReg)
 	pk.trace.Qk = iop.NewPolynomial(&qk, canReg)
-	pk.trace.S1 = iop.NewPolynomial(&s1, canReg)
-	pk.trace.S2 = iop.NewPolynomial(&s2, canReg)
-	pk.trace.S3 = iop.NewPolynomial(&s3, canReg)
+	pk.trace.S1, pk.trace.S2, pk.trace.S3 = nil, nil, nil
+	if len(lqk) != 0 { // else the key was trimmed, see Trim
+		pk.trace.S1 = iop.NewPolynomial(&s1, canReg)
+		pk.trace.S2 = iop.NewPolynomial(&s2, canReg)
+		pk.trace.S3 = iop.NewPolynomial(&s3, canReg)
+	}
+
+	pk.trace.Qcp = nil
+	for i := uint64(0); i < nbQcp; i++ {
+		var qcp []fr.Element
+		if err := dec.Decode(&qcp); err != nil {
+			return n + dec.BytesRead(), err
+		}
+		pk.trace.Qcp = append(pk.trace.Qcp, iop.NewPolynomial(&qcp, canReg))
+	}
 
 	lagReg := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
-	pk.lQk = iop.NewPolynomial(&lqk, lagReg)
+	pk.lQk = nil
+	if len(lqk) != 0 {
+		pk.lQk = iop.NewPolynomial(&lqk, lagReg)
+	}
 
 	pk.computeLagrangeCosetPolys()
 
-	return n + dec.BytesRead(), nil
+	n2, err = pk.Vk.circuitDigest.ReadFrom(r)
+	if err != nil {
+		return n + dec.BytesRead() + n2, err
+	}
+	n3, err := pk.Vk.metadata.ReadFrom(r)
+	return n + dec.BytesRead() + n2 + n3, err
 
 }
 
 // WriteTo writes binary encoding of VerifyingKey to w
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return gnarkio.WriteWithHeader(w, vkHeader, func(w io.Writer) error {
+		if _, err := vk.writeTo(w); err != nil {
+			return err
+		}
+		if _, err := vk.circuitDigest.WriteTo(w); err != nil {
+			return err
+		}
+		_, err := vk.metadata.WriteTo(w)
+		return err
+	})
+}
+
+func (vk *VerifyingKey) writeTo(w io.Writer) (n int64, err error) {
 	enc := curve.NewEncoder(w)
 
 	toEncode := []interface{}{
@@ -220,6 +323,7 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 		&vk.Qm,
 		&vk.Qo,
 		&vk.Qk,
+		vk.Qcp,
 	}
 
 	for _, v := range toEncode {
@@ -233,7 +337,36 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 
 // ReadFrom reads from binary representation in r into VerifyingKey
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
-	dec := curve.NewDecoder(r)
+	return gnarkio.ReadWithHeader(r, vkHeader, func(r io.Reader) error {
+		if _, err := vk.readFrom(r); err != nil {
+			return err
+		}
+		// zero and nil if the key was written without them
+		if _, err := vk.circuitDigest.ReadFrom(r); err != nil {
+			return err
+		}
+		_, err := vk.metadata.ReadFrom(r)
+		return err
+	})
+}
+
+// UnsafeReadFrom behaves like ReadFrom except that it doesn't check that the decoded
+// points are on the curve and in the correct subgroup. See Validate.
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return gnarkio.ReadWithHeader(r, vkHeader, func(r io.Reader) error {
+		if _, err := vk.readFrom(r, curve.NoSubgroupChecks()); err != nil {
+			return err
+		}
+		if _, err := vk.circuitDigest.ReadFrom(r); err != nil {
+			return err
+		}
+		_, err := vk.metadata.ReadFrom(r)
+		return err
+	})
+}
+
+func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
 	toDecode := []interface{}{
 		&vk.Size,
 		&vk.SizeInv,
@@ -248,6 +381,7 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		&vk.Qm,
 		&vk.Qo,
 		&vk.Qk,
+		&vk.Qcp,
 	}
 
 	for _, v := range toDecode {
@@ -258,3 +392,66 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 
 	return dec.BytesRead(), nil
 }
+
+// Validate checks that the points of the key are on the curve and in the correct
+// subgroup, and that the domain parameters are consistent. ReadFrom already checks
+// the points; Validate is meant for keys read with UnsafeReadFrom, which skips
+// these checks to load faster.
+func (vk *VerifyingKey) Validate() error {
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 || vk.NbPublicVariables > vk.Size {
+		return errors.New("verifying key: invalid size")
+	}
+	var one, check fr.Element
+	one.SetOne()
+	check.SetUint64(vk.Size).Mul(&check, &vk.SizeInv)
+	if !check.Equal(&one) {
+		return errors.New("verifying key: invalid inverse of the size")
+	}
+	check.Exp(vk.Generator, new(big.Int).SetUint64(vk.Size))
+	if !check.Equal(&one) {
+		return errors.New("verifying key: invalid generator")
+	}
+	points := append([]curve.G1Affine{vk.S[0], vk.S[1], vk.S[2], vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk}, vk.Qcp...)
+	for i := range points {
+		if !points[i].IsInSubGroup() {
+			return errors.New("verifying key: point not on the curve or not in the correct subgroup")
+		}
+	}
+	return nil
+}
+
+// Validate checks the verifying key (see VerifyingKey.Validate) and that the sizes
+// of the polynomials and of the permutation match its domain.
+func (pk *ProvingKey) Validate() error {
+	if pk.Vk == nil {
+		return errors.New("proving key: missing verifying key")
+	}
+	if err := pk.Vk.Validate(); err != nil {
+		return err
+	}
+	n := pk.Vk.Size
+	if pk.Domain[0].Cardinality != n || pk.Domain[1].Cardinality < n {
+		return errors.New("proving key: inconsistent domains")
+	}
+	polys := append([]*iop.Polynomial{pk.trace.Ql, pk.trace.Qr, pk.trace.Qm, pk.trace.Qo, pk.trace.Qk}, pk.trace.Qcp...)
+	if !pk.isTrimmed() {
+		polys = append(polys, pk.lQk, pk.trace.S1, pk.trace.S2, pk.trace.S3)
+	}
+	for _, p := range polys {
+		if p == nil || uint64(len(p.Coefficients())) != n {
+			return errors.New("proving key: invalid polynomial size")
+		}
+	}
+	if len(pk.trace.Qcp) != len(pk.Vk.Qcp) {
+		return errors.New("proving key: inconsistent number of commitments")
+	}
+	if uint64(len(pk.trace.S)) != 3*n {
+		return errors.New("proving key: invalid permutation size")
+	}
+	for _, s := range pk.trace.S {
+		if s < 0 || uint64(s) >= 3*n {
+			return errors.New("proving key: invalid permutation")
+		}
+	}
+	return nil
+}
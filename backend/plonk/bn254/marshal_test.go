@@ -21,9 +21,11 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 
-	"bytes"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+
+	"bytes"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
 	gnarkio "github.com/consensys/gnark/io"
 	"io"
 	"math/big"
@@ -134,6 +136,8 @@ func (pk *ProvingKey) randomize() {
 	pk.trace.S1 = iop.NewPolynomial(&s1, canReg)
 	pk.trace.S2 = iop.NewPolynomial(&s2, canReg)
 	pk.trace.S3 = iop.NewPolynomial(&s3, canReg)
+	qcp := randomScalars(n)
+	pk.trace.Qcp = []*iop.Polynomial{iop.NewPolynomial(&qcp, canReg)}
 
 	pk.trace.S = make([]int64, 3*pk.Domain[0].Cardinality)
 	pk.trace.S[0] = -12
@@ -160,6 +164,7 @@ func (vk *VerifyingKey) randomize() {
 	vk.Qm = randomPoint()
 	vk.Qo = randomPoint()
 	vk.Qk = randomPoint()
+	vk.Qcp = []curve.G1Affine{randomPoint()}
 }
 
 func (proof *Proof) randomize() {
@@ -174,6 +179,7 @@ func (proof *Proof) randomize() {
 	proof.BatchedProof.ClaimedValues = randomScalars(2)
 	proof.ZShiftedOpening.H = randomPoint()
 	proof.ZShiftedOpening.ClaimedValue.SetRandom()
+	proof.Bsb22Commitments = []kzg.Digest{randomPoint()}
 }
 
 func randomPoint() curve.G1Affine {
@@ -1,3 +1,6 @@
+//go:build !gnark_verifier_only
+// +build !gnark_verifier_only
+
 // Copyright 2020 ConsenSys Software Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -18,8 +21,8 @@ package plonk
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"math/big"
-	"runtime"
 	"time"
 
 	"github.com/consensys/gnark/backend/witness"
@@ -35,31 +38,21 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
 	"github.com/consensys/gnark/constraint/bn254"
 
+	"errors"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/metrics"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-type Proof struct {
-
-	// Commitments to the solution vectors
-	LRO [3]kzg.Digest
-
-	// Commitment to Z, the permutation polynomial
-	Z kzg.Digest
+// vectorPool holds the scratch vectors of the prover, see backend.WithBufferPooling
+var vectorPool utils.SlicePool[fr.Element]
 
-	// Commitments to h1, h2, h3 such that h = h1 + Xh2 + X**2h3 is the quotient polynomial
-	H [3]kzg.Digest
-
-	// Batch opening proof of h1 + zeta*h2 + zeta**2h3, linearizedPolynomial, l, r, o, s1, s2
-	BatchedProof kzg.BatchOpeningProof
-
-	// Opening proof of Z at zeta*mu
-	ZShiftedOpening kzg.OpeningProof
-}
-
-func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ *Proof, err error) {
 
 	log := logger.Logger().With().Str("curve", spr.CurveID().String()).Int("nbConstraints", len(spr.Constraints)).Str("backend", "plonk").Logger()
 
@@ -67,6 +60,20 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, err
 	}
+	tracer := tracing.Tracer(opt.TracerProvider)
+	ctx, span := tracing.Start(opt.TraceContext, tracer, "plonk.prove", attribute.String("curve", curve.ID.String()), attribute.Int("nbConstraints", len(spr.Constraints)))
+	defer func() { tracing.End(span, err) }()
+	digest := spr.Digest()
+	if !pk.Vk.circuitDigest.IsZero() && pk.Vk.circuitDigest != digest {
+		return nil, fmt.Errorf("%w: key digest %s, constraint system digest %s", backend.ErrCircuitMismatch, pk.Vk.circuitDigest, digest)
+	}
+	pk = pk.untrimmed()
+	defer func(start time.Time) {
+		if err == nil {
+			metrics.ObserveMemory()
+			metrics.ObserveProof("plonk", curve.ID.String(), digest.String(), time.Since(start))
+		}
+	}(time.Now())
 
 	start := time.Now()
 	// pick a hash function that will be used to derive the challenges
@@ -78,18 +85,58 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// result
 	proof := &Proof{}
 
+	// the commitment is computed while solving, from the values of the committed wires
+	lagReg := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	canReg := iop.Form{Basis: iop.Canonical, Layout: iop.Regular}
+	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
+	var pi2 []*iop.Polynomial         // blinded committed polynomials, in canonical basis
+	var commitmentValues []fr.Element // values of the commitment wires
+	if spr.CommitmentInfo.Is() {
+		solverOpts = append(solverOpts, solver.OverrideHint(spr.CommitmentInfo.HintID, func(_ *big.Int, ins, outs []*big.Int) error {
+			if len(ins) != spr.CommitmentInfo.NbCommitted() {
+				return errors.New("unexpected number of committed variables")
+			}
+			// the committed wires are in the placeholder constraints following the commitment one
+			offset := len(spr.Public) + 1
+			values := make([]fr.Element, pk.Domain[0].Cardinality)
+			for i := range ins {
+				values[offset+i].SetBigInt(ins[i])
+			}
+			p := iop.NewPolynomial(&values, lagReg)
+			p.ToCanonical(&pk.Domain[0]).ToRegular()
+			p = p.Clone(int(pk.Domain[1].Cardinality)).Blind(1)
+
+			digest, err := kzg.Commit(p.Coefficients(), pk.Vk.KZGSRS)
+			if err != nil {
+				return err
+			}
+			commitmentValue, err := solveCommitmentWire(&digest)
+			if err != nil {
+				return err
+			}
+			pi2 = []*iop.Polynomial{p}
+			commitmentValues = []fr.Element{commitmentValue}
+			proof.Bsb22Commitments = []kzg.Digest{digest}
+			commitmentValue.BigInt(outs[0])
+			return nil
+		}))
+	}
+
 	// query l, r, o in Lagrange basis, not blinded
 	log.Debug().Msg("Querying l, r, o")
-	_solution, err := spr.Solve(fullWitness, opt.SolverOpts...)
+	_, solveSpan := tracing.Start(ctx, tracer, "plonk.prove.solve")
+	_solution, err := spr.Solve(fullWitness, solverOpts...)
+	tracing.End(solveSpan, err)
 	if err != nil {
+		metrics.ObserveSolverError("plonk", curve.ID.String())
 		return nil, err
 	}
+	metrics.ObserveMemory()
 	solution := _solution.(*cs.SparseR1CSSolution)
 	evaluationLDomainSmall := []fr.Element(solution.L)
 	evaluationRDomainSmall := []fr.Element(solution.R)
 	evaluationODomainSmall := []fr.Element(solution.O)
 
-	lagReg := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
 	liop := iop.NewPolynomial(&evaluationLDomainSmall, lagReg)
 	riop := iop.NewPolynomial(&evaluationRDomainSmall, lagReg)
 	oiop := iop.NewPolynomial(&evaluationODomainSmall, lagReg)
@@ -103,10 +150,21 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// Blind l, r, o before committing
 	// we set the underlying slice capacity to domain[1].Cardinality to minimize mem moves.
 	log.Debug().Msg("Blinding")
-	bwliop := wliop.Clone(int(pk.Domain[1].Cardinality)).Blind(1)
-	bwriop := wriop.Clone(int(pk.Domain[1].Cardinality)).Blind(1)
-	bwoiop := woiop.Clone(int(pk.Domain[1].Cardinality)).Blind(1)
-	if err := commitToLRO(bwliop.Coefficients(), bwriop.Coefficients(), bwoiop.Coefficients(), proof, pk.Vk.KZGSRS); err != nil {
+	// the large scratch vectors are reused across proofs if opt.PoolBuffers is set
+	scratch := utils.NewScratch(&vectorPool, opt.PoolBuffers)
+	clone := func(p *iop.Polynomial) *iop.Polynomial {
+		// same as p.Clone(int(pk.Domain[1].Cardinality)), p isn't blinded nor shifted
+		coeffs := scratch.Make(len(p.Coefficients()), int(pk.Domain[1].Cardinality))
+		copy(coeffs, p.Coefficients())
+		return iop.NewPolynomial(&coeffs, canReg)
+	}
+	bwliop := clone(wliop).Blind(1)
+	bwriop := clone(wriop).Blind(1)
+	bwoiop := clone(woiop).Blind(1)
+	_, commitSpan := tracing.Start(ctx, tracer, "plonk.prove.msm", attribute.String("commitment", "lro"))
+	err = commitToLRO(bwliop.Coefficients(), bwriop.Coefficients(), bwoiop.Coefficients(), proof, pk.Vk.KZGSRS, opt.Tuning.MSMG1Tasks)
+	tracing.End(commitSpan, err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -121,7 +179,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err := bindPublicData(&fs, "gamma", *pk.Vk, fw[:len(spr.Public)]); err != nil {
 		return nil, err
 	}
-	gamma, err := deriveRandomness(&fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	gamma, err := deriveRandomness(&fs, "gamma", append([]*curve.G1Affine{&proof.LRO[0], &proof.LRO[1], &proof.LRO[2]}, toPointers(proof.Bsb22Commitments)...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +216,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// commit to the blinded version of z
 	bwziop := ziop // iop.NewWrappedPolynomial(&ziop)
 	bwziop.Blind(2)
-	proof.Z, err = kzg.Commit(bwziop.Coefficients(), pk.Vk.KZGSRS, runtime.NumCPU()*2)
+	proof.Z, err = kzg.Commit(bwziop.Coefficients(), pk.Vk.KZGSRS, opt.Tuning.MSMG1Tasks)
 	if err != nil {
 		return proof, err
 	}
@@ -173,9 +231,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// compute qk in canonical basis, completed with the public inputs
 	// We copy the coeffs of qk to pk is not mutated
 	lqkcoef := pk.lQk.Coefficients()
-	qkCompletedCanonical := make([]fr.Element, len(lqkcoef))
+	qkCompletedCanonical := scratch.Make(len(lqkcoef), len(lqkcoef))
 	copy(qkCompletedCanonical, lqkcoef)
 	copy(qkCompletedCanonical, fw[:len(spr.Public)])
+	if spr.CommitmentInfo.Is() {
+		// the commitment placeholder follows the public inputs ones, the verifier derives its value from the proof.
+		// evaluationLDomainSmall can't be used here, l was converted to canonical basis in place.
+		qkCompletedCanonical[len(spr.Public)] = commitmentValues[0]
+	}
 	pk.Domain[0].FFTInverse(qkCompletedCanonical, fft.DIF)
 	fft.BitReverse(qkCompletedCanonical)
 
@@ -185,12 +248,11 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	bwriop.ToLagrangeCoset(&pk.Domain[1])
 	bwoiop.ToLagrangeCoset(&pk.Domain[1])
 
-	canReg := iop.Form{Basis: iop.Canonical, Layout: iop.Regular}
 	lcqk := iop.NewPolynomial(&qkCompletedCanonical, canReg)
 	lcqk.ToLagrangeCoset(&pk.Domain[1])
 
 	// storing Id
-	id := make([]fr.Element, pk.Domain[1].Cardinality)
+	id := scratch.Make(int(pk.Domain[1].Cardinality), int(pk.Domain[1].Cardinality))
 	id[1].SetOne()
 	widiop := iop.NewPolynomial(&id, canReg)
 	widiop.ToLagrangeCoset(&pk.Domain[1])
@@ -204,15 +266,22 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if cap < pk.Domain[0].Cardinality {
 		cap = pk.Domain[0].Cardinality // sanity check
 	}
-	lone := make([]fr.Element, pk.Domain[0].Cardinality, cap)
+	lone := scratch.Make(int(pk.Domain[0].Cardinality), int(cap))
 	lone[0].SetOne()
 	loneiop := iop.NewPolynomial(&lone, lagReg)
 	wloneiop := loneiop.ToCanonical(&pk.Domain[0]).
 		ToRegular().
 		ToLagrangeCoset(&pk.Domain[1])
 
+	// committed polynomials and their selectors, in Lagrange coset basis
+	lcPi2 := make([]*iop.Polynomial, len(pi2))
+	for i := range pi2 {
+		lcPi2[i] = pi2[i].Clone(int(pk.Domain[1].Cardinality)).ToLagrangeCoset(&pk.Domain[1])
+	}
+
 	// Full capture using latest gnark crypto...
-	fic := func(fql, fqr, fqm, fqo, fqk, l, r, o fr.Element) fr.Element {
+	// qcpPi2 holds the evaluations of qcp₀, pi2₀, qcp₁, pi2₁, ...
+	fic := func(fql, fqr, fqm, fqo, fqk, l, r, o fr.Element, qcpPi2 []fr.Element) fr.Element {
 
 		var ic, tmp fr.Element
 
@@ -223,6 +292,10 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		ic.Add(&ic, &tmp)
 		tmp.Mul(&fqo, &o)
 		ic.Add(&ic, &tmp).Add(&ic, &fqk)
+		for i := 0; i+1 < len(qcpPi2); i += 2 {
+			tmp.Mul(&qcpPi2[i], &qcpPi2[i+1])
+			ic.Add(&ic, &tmp)
+		}
 
 		return ic
 	}
@@ -256,11 +329,11 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		return one
 	}
 
-	// 0 , 1,  2,  3,  4,  5,  6, 7,  8,  9, 10, 11, 12, 13, 14
-	// l , r , o, id, s1, s2, s3, z, zs, ql, qr, qm, qo, qk,lone
+	// 0 , 1,  2,  3,  4,  5,  6, 7,  8,  9, 10, 11, 12, 13, 14,   15...
+	// l , r , o, id, s1, s2, s3, z, zs, ql, qr, qm, qo, qk,lone, qcp₀, pi2₀, ...
 	fm := func(x ...fr.Element) fr.Element {
 
-		a := fic(x[9], x[10], x[11], x[12], x[13], x[0], x[1], x[2])
+		a := fic(x[9], x[10], x[11], x[12], x[13], x[0], x[1], x[2], x[15:])
 		b := fo(x[0], x[1], x[2], x[3], x[4], x[5], x[6], x[7], x[8])
 		c := fone(x[7], x[14])
 
@@ -269,7 +342,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		return c
 	}
 	log.Debug().Msg("system evaluation")
-	systemEvaluation, err := iop.Evaluate(fm, iop.Form{Basis: iop.LagrangeCoset, Layout: iop.BitReverse},
+	polys := []*iop.Polynomial{
 		bwliop,
 		bwriop,
 		bwoiop,
@@ -285,22 +358,31 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		pk.lcQo,
 		lcqk,
 		wloneiop,
-	)
+	}
+	for i := range lcPi2 {
+		polys = append(polys, pk.lcQcp[i], lcPi2[i])
+	}
+	_, quotientSpan := tracing.Start(ctx, tracer, "plonk.prove.fft")
+	systemEvaluation, err := iop.Evaluate(fm, iop.Form{Basis: iop.LagrangeCoset, Layout: iop.BitReverse}, polys...)
 	if err != nil {
 		return nil, err
 	}
 	h, err := iop.DivideByXMinusOne(systemEvaluation, [2]*fft.Domain{&pk.Domain[0], &pk.Domain[1]})
+	tracing.End(quotientSpan, err)
 	if err != nil {
 		return nil, err
 	}
 
 	// compute kzg commitments of h1, h2 and h3
 	log.Debug().Msg("computing kzg commitments of h1, h2 and h3")
-	if err := commitToQuotient(
+	_, commitSpan = tracing.Start(ctx, tracer, "plonk.prove.msm", attribute.String("commitment", "h"))
+	err = commitToQuotient(
 		h.Coefficients()[:pk.Domain[0].Cardinality+2],
 		h.Coefficients()[pk.Domain[0].Cardinality+2:2*(pk.Domain[0].Cardinality+2)],
 		h.Coefficients()[2*(pk.Domain[0].Cardinality+2):3*(pk.Domain[0].Cardinality+2)],
-		proof, pk.Vk.KZGSRS); err != nil {
+		proof, pk.Vk.KZGSRS, opt.Tuning.MSMG1Tasks)
+	tracing.End(commitSpan, err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -339,6 +421,12 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// blinded z evaluated at u*zeta
 	bzuzeta := proof.ZShiftedOpening.ClaimedValue
 
+	// committed polynomials evaluated at zeta
+	pi2zeta := make([]fr.Element, len(pi2))
+	for i := range pi2 {
+		pi2zeta[i] = pi2[i].Evaluate(zeta)
+	}
+
 	var (
 		linearizedPolynomialCanonical []fr.Element
 		linearizedPolynomialDigest    curve.G1Affine
@@ -357,6 +445,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 		gamma,
 		zeta,
 		bzuzeta,
+		pi2zeta,
 		bwziop.Coefficients()[:bwziop.BlindedSize()],
 		pk,
 	)
@@ -398,43 +487,52 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 
 	// Batch open the first list of polynomials
 	log.Debug().Msg("batch opening")
+	openedPolys := [][]fr.Element{
+		foldedH,
+		linearizedPolynomialCanonical,
+		bwliop.Coefficients()[:bwliop.BlindedSize()],
+		bwriop.Coefficients()[:bwriop.BlindedSize()],
+		bwoiop.Coefficients()[:bwoiop.BlindedSize()],
+		pk.trace.S1.Coefficients(),
+		pk.trace.S2.Coefficients(),
+	}
+	openedDigests := []kzg.Digest{
+		foldedHDigest,
+		linearizedPolynomialDigest,
+		proof.LRO[0],
+		proof.LRO[1],
+		proof.LRO[2],
+		pk.Vk.S[0],
+		pk.Vk.S[1],
+	}
+	for i := range pi2 {
+		openedPolys = append(openedPolys, pi2[i].Coefficients()[:pi2[i].BlindedSize()])
+		openedDigests = append(openedDigests, proof.Bsb22Commitments[i])
+	}
+	_, openSpan := tracing.Start(ctx, tracer, "plonk.prove.open")
 	proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
-		[][]fr.Element{
-			foldedH,
-			linearizedPolynomialCanonical,
-			bwliop.Coefficients()[:bwliop.BlindedSize()],
-			bwriop.Coefficients()[:bwriop.BlindedSize()],
-			bwoiop.Coefficients()[:bwoiop.BlindedSize()],
-			pk.trace.S1.Coefficients(),
-			pk.trace.S2.Coefficients(),
-		},
-		[]kzg.Digest{
-			foldedHDigest,
-			linearizedPolynomialDigest,
-			proof.LRO[0],
-			proof.LRO[1],
-			proof.LRO[2],
-			pk.Vk.S[0],
-			pk.Vk.S[1],
-		},
+		openedPolys,
+		openedDigests,
 		zeta,
 		hFunc,
 		pk.Vk.KZGSRS,
 	)
+	tracing.End(openSpan, err)
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
 
 	if err != nil {
 		return nil, err
 	}
+	scratch.Release()
 
 	return proof, nil
 
 }
 
-// fills proof.LRO with kzg commits of bcl, bcr and bco
-func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+// fills proof.LRO with kzg commits of bcl, bcr and bco, splitting nbTasks between them
+func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := (nbTasks + 1) / 2
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
@@ -446,12 +544,13 @@ func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS) error {
 		proof.LRO[1], err1 = kzg.Commit(bcr, srs, n)
 		close(chCommit1)
 	}()
-	if proof.LRO[2], err2 = kzg.Commit(bco, srs, n); err2 != nil {
-		return err2
-	}
+	proof.LRO[2], err2 = kzg.Commit(bco, srs, n)
 	<-chCommit0
 	<-chCommit1
 
+	if err2 != nil {
+		return err2
+	}
 	if err0 != nil {
 		return err0
 	}
@@ -459,8 +558,9 @@ func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS) error {
 	return err1
 }
 
-func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+// fills proof.H with kzg commits of h1, h2 and h3, splitting nbTasks between them
+func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := (nbTasks + 1) / 2
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
@@ -472,12 +572,13 @@ func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS) error
 		proof.H[1], err1 = kzg.Commit(h2, srs, n)
 		close(chCommit1)
 	}()
-	if proof.H[2], err2 = kzg.Commit(h3, srs, n); err2 != nil {
-		return err2
-	}
+	proof.H[2], err2 = kzg.Commit(h3, srs, n)
 	<-chCommit0
 	<-chCommit1
 
+	if err2 != nil {
+		return err2
+	}
 	if err0 != nil {
 		return err0
 	}
@@ -495,8 +596,8 @@ func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS) error
 //
 // α²*L₁(ζ)*Z(X)
 // + α*( (l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*Z(μζ)*s3(X) - Z(X)*(l(ζ)+β*id1(ζ)+γ)*(r(ζ)+β*id2(ζ)+γ)*(o(ζ)+β*id3(ζ)+γ))
-// + l(ζ)*Ql(X) + l(ζ)r(ζ)*Qm(X) + r(ζ)*Qr(X) + o(ζ)*Qo(X) + Qk(X)
-func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey) []fr.Element {
+// + l(ζ)*Ql(X) + l(ζ)r(ζ)*Qm(X) + r(ζ)*Qr(X) + o(ζ)*Qo(X) + Qk(X) + ∑ᵢ pi2ᵢ(ζ)*Qcpᵢ(X)
+func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, pi2Zeta []fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey) []fr.Element {
 
 	// first part: individual constraints
 	var rl fr.Element
@@ -581,6 +682,11 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 
 				t0.Mul(&cqo[i], &oZeta).Add(&t0, &cqk[i])
 				linPol[i].Add(&linPol[i], &t0) // linPol = linPol + o(ζ)*Qo(X) + Qk(X)
+
+				for j := range pi2Zeta {
+					t0.Mul(&pk.trace.Qcp[j].Coefficients()[i], &pi2Zeta[j])
+					linPol[i].Add(&linPol[i], &t0) // linPol = linPol + pi2ⱼ(ζ)*Qcpⱼ(X)
+				}
 			}
 
 			t0.Mul(&blindedZCanonical[i], &lagrangeZeta)
@@ -590,74 +696,78 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 	return linPol
 }
 
-
 // Optimized for WebAssembly, prioritizing memory savings and avoiding parallelization
-func computeLinearizedPolynomialTinygo(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey) []fr.Element {
+func computeLinearizedPolynomialTinygo(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, pi2Zeta []fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey) []fr.Element {
 	var rl, s1, s2, tmp fr.Element
-rl.Mul(&rZeta, &lZeta)
-
-s1 = pk.trace.S1.Evaluate(zeta)
-s1.Mul(&s1, &beta).Add(&s1, &lZeta).Add(&s1, &gamma)
-
-tmp = pk.trace.S2.Evaluate(zeta)
-tmp.Mul(&tmp, &beta).Add(&tmp, &rZeta).Add(&tmp, &gamma)
-
-s1.Mul(&s1, &tmp).Mul(&s1, &zu).Mul(&s1, &beta)
-
-var uzeta, uuzeta fr.Element
-uzeta.Mul(&zeta, &pk.Vk.CosetShift)
-uuzeta.Mul(&uzeta, &pk.Vk.CosetShift)
-
-s2.Mul(&beta, &zeta).Add(&s2, &lZeta).Add(&s2, &gamma)
-tmp.Mul(&beta, &uzeta).Add(&tmp, &rZeta).Add(&tmp, &gamma)
-s2.Mul(&s2, &tmp)
-tmp.Mul(&beta, &uuzeta).Add(&tmp, &oZeta).Add(&tmp, &gamma)
-s2.Mul(&s2, &tmp)
-s2.Neg(&s2)
-
-var lagrangeZeta, one, den, frNbElmt fr.Element
-one.SetOne()
-nbElmt := int64(pk.Domain[0].Cardinality)
-lagrangeZeta.Set(&zeta).
-	Exp(lagrangeZeta, big.NewInt(nbElmt)).
-	Sub(&lagrangeZeta, &one)
-frNbElmt.SetUint64(uint64(nbElmt))
-den.Sub(&zeta, &one).
-	Inverse(&den)
-lagrangeZeta.Mul(&lagrangeZeta, &den).
-	Mul(&lagrangeZeta, &alpha).
-	Mul(&lagrangeZeta, &alpha).
-	Mul(&lagrangeZeta, &pk.Domain[0].CardinalityInv)
-
-linPol := make([]fr.Element, len(blindedZCanonical))
-copy(linPol, blindedZCanonical)
-
-var t0, t1 fr.Element
-for i := 0; i < len(linPol); i++ {
-	linPol[i].Mul(&linPol[i], &s2)
-
-	if i < len(pk.trace.S3.Coefficients()) {
-		t0.Mul(&pk.trace.S3.Coefficients()[i], &s1)
-		linPol[i].Add(&linPol[i], &t0)
-	}
+	rl.Mul(&rZeta, &lZeta)
 
-	linPol[i].Mul(&linPol[i], &alpha)
+	s1 = pk.trace.S1.Evaluate(zeta)
+	s1.Mul(&s1, &beta).Add(&s1, &lZeta).Add(&s1, &gamma)
 
-	if i < len(pk.trace.Qm.Coefficients()) {
-		t1.Mul(&pk.trace.Qm.Coefficients()[i], &rl)
-		t0.Mul(&pk.trace.Ql.Coefficients()[i], &lZeta)
-		t0.Add(&t0, &t1)
-		linPol[i].Add(&linPol[i], &t0)
+	tmp = pk.trace.S2.Evaluate(zeta)
+	tmp.Mul(&tmp, &beta).Add(&tmp, &rZeta).Add(&tmp, &gamma)
 
-		t0.Mul(&pk.trace.Qr.Coefficients()[i], &rZeta)
-		linPol[i].Add(&linPol[i], &t0)
+	s1.Mul(&s1, &tmp).Mul(&s1, &zu).Mul(&s1, &beta)
 
-		t0.Mul(&pk.trace.Qo.Coefficients()[i], &oZeta).Add(&t0, &pk.trace.Qk.Coefficients()[i])
-linPol[i].Add(&linPol[i], &t0)
-}
+	var uzeta, uuzeta fr.Element
+	uzeta.Mul(&zeta, &pk.Vk.CosetShift)
+	uuzeta.Mul(&uzeta, &pk.Vk.CosetShift)
 
-	t0.Mul(&blindedZCanonical[i], &lagrangeZeta)
-	linPol[i].Add(&linPol[i], &t0)
-}
-return linPol
+	s2.Mul(&beta, &zeta).Add(&s2, &lZeta).Add(&s2, &gamma)
+	tmp.Mul(&beta, &uzeta).Add(&tmp, &rZeta).Add(&tmp, &gamma)
+	s2.Mul(&s2, &tmp)
+	tmp.Mul(&beta, &uuzeta).Add(&tmp, &oZeta).Add(&tmp, &gamma)
+	s2.Mul(&s2, &tmp)
+	s2.Neg(&s2)
+
+	var lagrangeZeta, one, den, frNbElmt fr.Element
+	one.SetOne()
+	nbElmt := int64(pk.Domain[0].Cardinality)
+	lagrangeZeta.Set(&zeta).
+		Exp(lagrangeZeta, big.NewInt(nbElmt)).
+		Sub(&lagrangeZeta, &one)
+	frNbElmt.SetUint64(uint64(nbElmt))
+	den.Sub(&zeta, &one).
+		Inverse(&den)
+	lagrangeZeta.Mul(&lagrangeZeta, &den).
+		Mul(&lagrangeZeta, &alpha).
+		Mul(&lagrangeZeta, &alpha).
+		Mul(&lagrangeZeta, &pk.Domain[0].CardinalityInv)
+
+	linPol := make([]fr.Element, len(blindedZCanonical))
+	copy(linPol, blindedZCanonical)
+
+	var t0, t1 fr.Element
+	for i := 0; i < len(linPol); i++ {
+		linPol[i].Mul(&linPol[i], &s2)
+
+		if i < len(pk.trace.S3.Coefficients()) {
+			t0.Mul(&pk.trace.S3.Coefficients()[i], &s1)
+			linPol[i].Add(&linPol[i], &t0)
+		}
+
+		linPol[i].Mul(&linPol[i], &alpha)
+
+		if i < len(pk.trace.Qm.Coefficients()) {
+			t1.Mul(&pk.trace.Qm.Coefficients()[i], &rl)
+			t0.Mul(&pk.trace.Ql.Coefficients()[i], &lZeta)
+			t0.Add(&t0, &t1)
+			linPol[i].Add(&linPol[i], &t0)
+
+			t0.Mul(&pk.trace.Qr.Coefficients()[i], &rZeta)
+			linPol[i].Add(&linPol[i], &t0)
+
+			t0.Mul(&pk.trace.Qo.Coefficients()[i], &oZeta).Add(&t0, &pk.trace.Qk.Coefficients()[i])
+			linPol[i].Add(&linPol[i], &t0)
+
+			for j := range pi2Zeta {
+				t0.Mul(&pk.trace.Qcp[j].Coefficients()[i], &pi2Zeta[j])
+				linPol[i].Add(&linPol[i], &t0)
+			}
+		}
+
+		t0.Mul(&blindedZCanonical[i], &lagrangeZeta)
+		linPol[i].Add(&linPol[i], &t0)
+	}
+	return linPol
 }
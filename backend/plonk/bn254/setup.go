@@ -1,3 +1,6 @@
+//go:build !gnark_verifier_only
+// +build !gnark_verifier_only
+
 // Copyright 2020 ConsenSys Software Inc.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -17,101 +20,42 @@
 package plonk
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
 	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/iop"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint/bn254"
-
-	kzgg "github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// Trace stores a plonk trace as columns
-type Trace struct {
-
-	// Constants describing a plonk circuit. The first entries
-	// of LQk (whose index correspond to the public inputs) are set to 0, and are to be
-	// completed by the prover. At those indices i (so from 0 to nb_public_variables), LQl[i]=-1
-	// so the first nb_public_variables constraints look like this:
-	// -1*Wire[i] + 0* + 0 . It is zero when the constant coefficient is replaced by Wire[i].
-	Ql, Qr, Qm, Qo, Qk *iop.Polynomial
-
-	// Polynomials representing the splitted permutation. The full permutation's support is 3*N where N=nb wires.
-	// The set of interpolation is <g> of size N, so to represent the permutation S we let S acts on the
-	// set A=(<g>, u*<g>, u^{2}*<g>) of size 3*N, where u is outside <g> (its use is to shift the set <g>).
-	// We obtain a permutation of A, A'. We split A' in 3 (A'_{1}, A'_{2}, A'_{3}), and S1, S2, S3 are
-	// respectively the interpolation of A'_{1}, A'_{2}, A'_{3} on <g>.
-	S1, S2, S3 *iop.Polynomial
-
-	// S full permutation, i -> S[i]
-	S []int64
-}
-
-// VerifyingKey stores the data needed to verify a proof:
-// * The commitment scheme
-// * Commitments of ql prepended with as many ones as there are public inputs
-// * Commitments of qr, qm, qo, qk prepended with as many zeroes as there are public inputs
-// * Commitments to S1, S2, S3
-type VerifyingKey struct {
-
-	// Size circuit
-	Size              uint64
-	SizeInv           fr.Element
-	Generator         fr.Element
-	NbPublicVariables uint64
-
-	// Commitment scheme that is used for an instantiation of PLONK
-	KZGSRS *kzg.SRS
-
-	// cosetShift generator of the coset on the small domain
-	CosetShift fr.Element
-
-	// S commitments to S1, S2, S3
-	S [3]kzg.Digest
-
-	// Commitments to ql, qr, qm, qo prepended with as many zeroes (ones for l) as there are public inputs.
-	// In particular Qk is not complete.
-	Ql, Qr, Qm, Qo, Qk kzg.Digest
-}
-
-// ProvingKey stores the data needed to generate a proof:
-// * the commitment scheme
-// * ql, prepended with as many ones as they are public inputs
-// * qr, qm, qo prepended with as many zeroes as there are public inputs.
-// * qk, prepended with as many zeroes as public inputs, to be completed by the prover
-// with the list of public inputs.
-// * sigma_1, sigma_2, sigma_3 in both basis
-// * the copy constraint permutation
-type ProvingKey struct {
-
-	// stores ql, qr, qm, qo, qk (-> to be completed by the prover)
-	// and s1, s2, s3. They are set in canonical basis before generating the proof, they will be used
-	// for computing the opening proofs (hence the canonical form). The canonical version
-	// of qk incompleted is used in the linearisation polynomial.
-	// The polynomials in trace are in canonical basis.
-	trace Trace
-
-	// Verifying Key is embedded into the proving key (needed by Prove)
-	Vk *VerifyingKey
-
-	// qr,ql,qm,qo in LagrangeCoset --> these are not serialized, but computed from Ql, Qr, Qm, Qo once.
-	lcQl, lcQr, lcQm, lcQo *iop.Polynomial
-
-	// LQk qk in Lagrange form -> to be completed by the prover. After being completed,
-	lQk *iop.Polynomial
-
-	// Domains used for the FFTs.
-	// Domain[0] = small Domain
-	// Domain[1] = big Domain
-	Domain [2]fft.Domain
-
-	// in lagrange coset basis --> these are not serialized, but computed from S1Canonical, S2Canonical, S3Canonical once.
-	lcS1, lcS2, lcS3 *iop.Polynomial
-}
+// Setup returns the proving and verifying keys of the circuit spr, from the KZG SRS
+// srs in canonical basis.
+func Setup(spr *cs.SparseR1CS, srs *kzg.SRS, opts ...backend.SetupOption) (_ *ProvingKey, _ *VerifyingKey, err error) {
+	_, span := tracing.Start(context.Background(), tracing.Tracer(nil), "plonk.setup", attribute.String("curve", spr.CurveID().String()), attribute.Int("nbConstraints", len(spr.Constraints)))
+	defer func() { tracing.End(span, err) }()
 
-func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lagrangeSRS *kzg.SRS
+	if cfg.LagrangeSRS != nil {
+		var ok bool
+		if lagrangeSRS, ok = cfg.LagrangeSRS.(*kzg.SRS); !ok {
+			return nil, nil, errors.New("lagrange srs of the wrong curve")
+		}
+	}
 
 	var pk ProvingKey
 	var vk VerifyingKey
@@ -127,6 +71,8 @@ func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error)
 	vk.SizeInv.SetUint64(vk.Size).Inverse(&vk.SizeInv)
 	vk.Generator.Set(&pk.Domain[0].Generator)
 	vk.NbPublicVariables = uint64(len(spr.Public))
+	vk.circuitDigest = spr.Digest()
+	vk.metadata = spr.Metadata.Clone()
 	if err := pk.InitKZG(srs); err != nil {
 		return nil, nil, err
 	}
@@ -148,7 +94,7 @@ func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error)
 	// we save lqk before, because the prover needs to complete it in Lagrange form, and
 	// then express it on the Lagrange coset basis.
 	pk.lQk = pk.trace.Qk.Clone() // it will be completed by the prover, and the evaluated on the coset
-	err := commitTrace(&pk.trace, &pk)
+	err = commitTrace(&pk.trace, &pk, lagrangeSRS)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -161,59 +107,13 @@ func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error)
 	return &pk, &vk, nil
 }
 
-// computeLagrangeCosetPolys computes each polynomial except qk in Lagrange coset
-// basis. Qk will be evaluated in Lagrange coset basis once it is completed by the prover.
-func (pk *ProvingKey) computeLagrangeCosetPolys() {
-	pk.lcQl = pk.trace.Ql.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcQr = pk.trace.Qr.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcQm = pk.trace.Qm.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcQo = pk.trace.Qo.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcS1 = pk.trace.S1.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcS2 = pk.trace.S2.Clone().ToLagrangeCoset(&pk.Domain[1])
-	pk.lcS3 = pk.trace.S3.Clone().ToLagrangeCoset(&pk.Domain[1])
-}
-
-// NbPublicWitness returns the expected public witness size (number of field elements)
-func (vk *VerifyingKey) NbPublicWitness() int {
-	return int(vk.NbPublicVariables)
-}
-
-// VerifyingKey returns pk.Vk
-func (pk *ProvingKey) VerifyingKey() interface{} {
-	return pk.Vk
-}
-
-// InitKZG inits pk.Vk.KZG using pk.Domain[0] cardinality and provided SRS
-//
-// This should be used after deserializing a ProvingKey
-// as pk.Vk.KZG is NOT serialized
-func (pk *ProvingKey) InitKZG(srs kzgg.SRS) error {
-	return pk.Vk.InitKZG(srs)
-}
-
-// InitKZG inits vk.KZG using provided SRS
-//
-// This should be used after deserializing a VerifyingKey
-// as vk.KZG is NOT serialized
-//
-// Note that this instantiate a new FFT domain using vk.Size
-func (vk *VerifyingKey) InitKZG(srs kzgg.SRS) error {
-	_srs := srs.(*kzg.SRS)
-
-	if len(_srs.G1) < int(vk.Size) {
-		return errors.New("kzg srs is too small")
-	}
-	vk.KZGSRS = _srs
-
-	return nil
-}
-
 // BuildTrace fills the constatn columns ql, qr, qm, qo, qk from the sparser1cs.
 // Size is the size of the system that is nb_constraints+nb_public_variables
 func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 
 	nbConstraints := len(spr.Constraints)
-	sizeSystem := uint64(nbConstraints + len(spr.Public))
+	nbPlaceholders := len(spr.Public) + spr.CommitmentInfo.NbPlaceholderConstraints()
+	sizeSystem := uint64(nbConstraints + nbPlaceholders)
 	size := ecc.NextPowerOfTwo(uint64(sizeSystem))
 
 	ql := make([]fr.Element, size)
@@ -229,7 +129,20 @@ func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 		qo[i].SetZero()
 		qk[i].SetZero() // → to be completed by the prover
 	}
-	offset := len(spr.Public)
+	var qcp []fr.Element
+	if spr.CommitmentInfo.Is() {
+		// placeholders -COMMITMENT + qk = 0, where qk is completed by the prover with the
+		// commitment, and -COMMITTED_i + PI2 = 0, where PI2 is the polynomial committed by the prover.
+		qcp = make([]fr.Element, size)
+		for i := len(spr.Public); i < nbPlaceholders; i++ {
+			ql[i].SetOne().Neg(&ql[i])
+		}
+		for i := len(spr.Public) + 1; i < nbPlaceholders; i++ {
+			qcp[i].SetOne()
+		}
+	}
+
+	offset := nbPlaceholders
 	for i := 0; i < nbConstraints; i++ { // constraints
 
 		ql[offset+i].Set(&spr.Coefficients[spr.Constraints[i].L.CoeffID()])
@@ -247,12 +160,29 @@ func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 	pt.Qm = iop.NewPolynomial(&qm, lagReg)
 	pt.Qo = iop.NewPolynomial(&qo, lagReg)
 	pt.Qk = iop.NewPolynomial(&qk, lagReg)
+	pt.Qcp = nil
+	if qcp != nil {
+		pt.Qcp = []*iop.Polynomial{iop.NewPolynomial(&qcp, lagReg)}
+	}
 
 }
 
 // commitTrace commits to every polynomials in the trace, and put
-// the commitments int the verifying key.
-func commitTrace(trace *Trace, pk *ProvingKey) error {
+// the commitments int the verifying key. If lagrangeSRS is set, the
+// polynomials are committed in Lagrange basis with it.
+func commitTrace(trace *Trace, pk *ProvingKey, lagrangeSRS *kzg.SRS) error {
+
+	if lagrangeSRS != nil {
+		if uint64(len(lagrangeSRS.G1)) != pk.Domain[0].Cardinality {
+			return fmt.Errorf("lagrange srs of size %d, the circuit domain has size %d", len(lagrangeSRS.G1), pk.Domain[0].Cardinality)
+		}
+		if lagrangeSRS.G2 != pk.Vk.KZGSRS.G2 {
+			return errors.New("the lagrange srs doesn't derive from the kzg srs")
+		}
+		if err := commitTracePolynomials(trace, pk.Vk, lagrangeSRS); err != nil {
+			return err
+		}
+	}
 
 	trace.Ql.ToCanonical(&pk.Domain[0]).ToRegular()
 	trace.Qr.ToCanonical(&pk.Domain[0]).ToRegular()
@@ -262,39 +192,135 @@ func commitTrace(trace *Trace, pk *ProvingKey) error {
 	trace.S1.ToCanonical(&pk.Domain[0]).ToRegular()
 	trace.S2.ToCanonical(&pk.Domain[0]).ToRegular()
 	trace.S3.ToCanonical(&pk.Domain[0]).ToRegular()
+	for _, qcp := range trace.Qcp {
+		qcp.ToCanonical(&pk.Domain[0]).ToRegular()
+	}
 
+	if lagrangeSRS != nil {
+		return nil
+	}
+	return commitTracePolynomials(trace, pk.Vk, pk.Vk.KZGSRS)
+}
+
+// commitTracePolynomials puts the commitments to the polynomials of the trace with
+// srs in vk. srs must be in the basis of the polynomials.
+func commitTracePolynomials(trace *Trace, vk *VerifyingKey, srs *kzg.SRS) error {
 	var err error
-	if pk.Vk.Ql, err = kzg.Commit(pk.trace.Ql.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.Ql, err = kzg.Commit(trace.Ql.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.Qr, err = kzg.Commit(pk.trace.Qr.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.Qr, err = kzg.Commit(trace.Qr.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.Qm, err = kzg.Commit(pk.trace.Qm.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.Qm, err = kzg.Commit(trace.Qm.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.Qo, err = kzg.Commit(pk.trace.Qo.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.Qo, err = kzg.Commit(trace.Qo.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.Qk, err = kzg.Commit(pk.trace.Qk.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.Qk, err = kzg.Commit(trace.Qk.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.S[0], err = kzg.Commit(pk.trace.S1.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.S[0], err = kzg.Commit(trace.S1.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.S[1], err = kzg.Commit(pk.trace.S2.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.S[1], err = kzg.Commit(trace.S2.Coefficients(), srs); err != nil {
 		return err
 	}
-	if pk.Vk.S[2], err = kzg.Commit(pk.trace.S3.Coefficients(), pk.Vk.KZGSRS); err != nil {
+	if vk.S[2], err = kzg.Commit(trace.S3.Coefficients(), srs); err != nil {
 		return err
 	}
+	vk.Qcp = make([]kzg.Digest, len(trace.Qcp))
+	for i, qcp := range trace.Qcp {
+		if vk.Qcp[i], err = kzg.Commit(qcp.Coefficients(), srs); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// DomainSize returns the cardinality of the evaluation domain of the circuit spr,
+// which is the size of its SRS in Lagrange basis.
+func DomainSize(spr *cs.SparseR1CS) uint64 {
+	return ecc.NextPowerOfTwo(sizeSystem(spr))
+}
+
+// sizeSystem returns the number of constraints of spr, including the placeholder
+// constraints of the public inputs and of the commitment
+func sizeSystem(spr *cs.SparseR1CS) uint64 {
+	return uint64(len(spr.Constraints) + len(spr.Public) + spr.CommitmentInfo.NbPlaceholderConstraints())
+}
+
+// NewLagrangeSRS returns the KZG SRS in Lagrange basis of the domain of cardinality
+// size: its points in G1 are [Lᵢ(τ)]₁ for the Lagrange polynomials Lᵢ of the domain,
+// computed from the first size points [τʲ]₁ of srs with an inverse FFT in G1.
+//
+// The inverse FFT is expensive, the SRS should be computed once per circuit size
+// (see DomainSize) and persisted with its WriteTo method. It is given to Setup with
+// backend.WithLagrangeSRS.
+func NewLagrangeSRS(srs *kzg.SRS, size uint64) (*kzg.SRS, error) {
+	if size < 2 || size != ecc.NextPowerOfTwo(size) {
+		return nil, fmt.Errorf("invalid lagrange srs size %d, must be a power of two", size)
+	}
+	if uint64(len(srs.G1)) < size {
+		return nil, errors.New("kzg srs is too small")
+	}
+	domain := fft.NewDomain(size)
+
+	points := make([]curve.G1Jac, size)
+	for i := range points {
+		points[i].FromAffine(&srs.G1[i])
+	}
+	ifftG1(points, &domain.GeneratorInv)
+
+	var cardinalityInv big.Int
+	domain.CardinalityInv.BigInt(&cardinalityInv)
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			points[i].ScalarMultiplication(&points[i], &cardinalityInv)
+		}
+	})
+
+	return &kzg.SRS{G1: curve.BatchJacobianToAffineG1(points), G2: srs.G2}, nil
+}
+
+// ifftG1 computes in place the FFT of the points a, of size a power of two, with the
+// root of unity generatorInv, without the division by the size.
+func ifftG1(a []curve.G1Jac, generatorInv *fr.Element) {
+	n := len(a)
+	shift := 64 - bits.TrailingZeros64(uint64(n))
+	for i := 0; i < n; i++ {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for m := 1; m < n; m <<= 1 {
+		// twiddles[j] = ωʲ, where ω is a primitive 2m-th root of unity
+		var w, t fr.Element
+		w.Exp(*generatorInv, big.NewInt(int64(n/(2*m))))
+		twiddles := make([]big.Int, m)
+		t.SetOne()
+		for j := range twiddles {
+			t.BigInt(&twiddles[j])
+			t.Mul(&t, &w)
+		}
+
+		utils.Parallelize(n/2, func(start, end int) {
+			var tmp curve.G1Jac
+			for b := start; b < end; b++ {
+				k, j := (b/m)*2*m, b%m
+				tmp.ScalarMultiplication(&a[k+j+m], &twiddles[j])
+				a[k+j+m].Set(&a[k+j]).SubAssign(&tmp)
+				a[k+j].AddAssign(&tmp)
+			}
+		})
+	}
+}
+
 func (pk *ProvingKey) initDomains(spr *cs.SparseR1CS) {
 
-	nbConstraints := len(spr.Constraints)
-	sizeSystem := uint64(nbConstraints + len(spr.Public)) // len(spr.Public) is for the placeholder constraints
+	sizeSystem := sizeSystem(spr)
 	pk.Domain[0] = *fft.NewDomain(sizeSystem)
 
 	// h, the quotient polynomial is of degree 3(n+1)+2, so it's in a 3(n+2) dim vector space,
@@ -339,6 +365,14 @@ func buildPermutation(spr *cs.SparseR1CS, pt *Trace, nbVariables int) {
 	}
 
 	offset := len(spr.Public)
+	if spr.CommitmentInfo.Is() { // commitment placeholders
+		lro[offset] = spr.CommitmentInfo.CommitmentIndex
+		for i, wireID := range spr.CommitmentInfo.Committed {
+			lro[offset+1+i] = wireID
+		}
+		offset += spr.CommitmentInfo.NbPlaceholderConstraints()
+	}
+
 	for i := 0; i < len(spr.Constraints); i++ { // IDs of LRO associated to constraints
 		lro[offset+i] = spr.Constraints[i].L.WireID()
 		lro[sizeSolution+offset+i] = spr.Constraints[i].R.WireID()
@@ -370,52 +404,3 @@ func buildPermutation(spr *cs.SparseR1CS, pt *Trace, nbVariables int) {
 
 	pt.S = permutation
 }
-
-// computePermutationPolynomials computes the LDE (Lagrange basis) of the permutation.
-// We let the permutation act on <g> || u<g> || u^{2}<g>, split the result in 3 parts,
-// and interpolate each of the 3 parts on <g>.
-func computePermutationPolynomials(pt *Trace, domain *fft.Domain) [3]*iop.Polynomial {
-
-	nbElmts := int(domain.Cardinality)
-
-	var res [3]*iop.Polynomial
-
-	// Lagrange form of ID
-	evaluationIDSmallDomain := getSupportPermutation(domain)
-
-	// Lagrange form of S1, S2, S3
-	s1Canonical := make([]fr.Element, nbElmts)
-	s2Canonical := make([]fr.Element, nbElmts)
-	s3Canonical := make([]fr.Element, nbElmts)
-	for i := 0; i < nbElmts; i++ {
-		s1Canonical[i].Set(&evaluationIDSmallDomain[pt.S[i]])
-		s2Canonical[i].Set(&evaluationIDSmallDomain[pt.S[nbElmts+i]])
-		s3Canonical[i].Set(&evaluationIDSmallDomain[pt.S[2*nbElmts+i]])
-	}
-
-	lagReg := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
-	res[0] = iop.NewPolynomial(&s1Canonical, lagReg)
-	res[1] = iop.NewPolynomial(&s2Canonical, lagReg)
-	res[2] = iop.NewPolynomial(&s3Canonical, lagReg)
-
-	return res
-}
-
-// getSupportPermutation returns the support on which the permutation acts, it is
-// <g> || u<g> || u^{2}<g>
-func getSupportPermutation(domain *fft.Domain) []fr.Element {
-
-	res := make([]fr.Element, 3*domain.Cardinality)
-
-	res[0].SetOne()
-	res[domain.Cardinality].Set(&domain.FrMultiplicativeGen)
-	res[2*domain.Cardinality].Square(&domain.FrMultiplicativeGen)
-
-	for i := uint64(1); i < domain.Cardinality; i++ {
-		res[i].Mul(&res[i-1], &domain.Generator)
-		res[domain.Cardinality+i].Mul(&res[domain.Cardinality+i-1], &domain.Generator)
-		res[2*domain.Cardinality+i].Mul(&res[2*domain.Cardinality+i-1], &domain.Generator)
-	}
-
-	return res
-}
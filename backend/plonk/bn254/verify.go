@@ -17,6 +17,7 @@
 package plonk
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"io"
@@ -33,17 +34,51 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+type Proof struct {
+
+	// Commitments to the solution vectors
+	LRO [3]kzg.Digest
+
+	// Commitment to Z, the permutation polynomial
+	Z kzg.Digest
+
+	// Commitments to h1, h2, h3 such that h = h1 + Xh2 + X**2h3 is the quotient polynomial
+	H [3]kzg.Digest
+
+	// Batch opening proof of h1 + zeta*h2 + zeta**2h3, linearizedPolynomial, l, r, o, s1, s2, committed polynomials
+	BatchedProof kzg.BatchOpeningProof
+
+	// Opening proof of Z at zeta*mu
+	ZShiftedOpening kzg.OpeningProof
+
+	// Commitments to the committed wires (see api.Commit), one per commitment. The
+	// batch opening proof also opens them at zeta.
+	Bsb22Commitments []kzg.Digest
+}
+
 var (
 	errWrongClaimedQuotient = errors.New("claimed quotient is not as expected")
+	errInvalidCommitments   = errors.New("invalid number of commitments in proof")
 )
 
-func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
+func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) (err error) {
+	_, span := tracing.Start(context.Background(), tracing.Tracer(nil), "plonk.verify", attribute.String("curve", curve.ID.String()))
+	defer func() { tracing.End(span, err) }()
+
 	log := logger.Logger().With().Str("curve", "bn254").Str("backend", "plonk").Logger()
 	start := time.Now()
 
+	if len(proof.Bsb22Commitments) != len(vk.Qcp) || len(proof.BatchedProof.ClaimedValues) != 7+len(vk.Qcp) {
+		return errInvalidCommitments
+	}
+
 	// pick a hash function to derive the challenge (the same as in the prover)
 	hFunc := sha256.New()
 
@@ -56,7 +91,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	if err := bindPublicData(&fs, "gamma", *vk, publicWitness); err != nil {
 		return err
 	}
-	gamma, err := deriveRandomness(&fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	gamma, err := deriveRandomness(&fs, "gamma", append([]*curve.G1Affine{&proof.LRO[0], &proof.LRO[1], &proof.LRO[2]}, toPointers(proof.Bsb22Commitments)...)...)
 	if err != nil {
 		return err
 	}
@@ -108,6 +143,22 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		lagrange.Div(&lagrange, &den)
 	}
 
+	// the commitments follow the public inputs, their values are derived from the proof
+	for i := range proof.Bsb22Commitments {
+		commitmentValue, err := solveCommitmentWire(&proof.Bsb22Commitments[i])
+		if err != nil {
+			return err
+		}
+		xiLi.Mul(&lagrange, &commitmentValue)
+		pi.Add(&pi, &xiLi)
+
+		lagrange.Mul(&lagrange, &vk.Generator).
+			Mul(&lagrange, &den)
+		acc.Mul(&acc, &vk.Generator)
+		den.Sub(&zeta, &acc)
+		lagrange.Div(&lagrange, &den)
+	}
+
 	// linearizedpolynomial + pi(ζ) + α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ) - α²*L₁(ζ)
 	var _s1, _s2, _o, alphaSquareLagrange fr.Element
 
@@ -197,12 +248,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		l, r, rl, o, one, // first part
 		_s1, _s2, // second & third part
 	}
+
+	// committed polynomials: pi2ᵢ(ζ)*Qcpᵢ
+	points = append(points, vk.Qcp...)
+	scalars = append(scalars, proof.BatchedProof.ClaimedValues[7:]...)
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
 		return err
 	}
 
 	// Fold the first proof
-	foldedProof, foldedDigest, err := kzg.FoldProof([]kzg.Digest{
+	foldedProof, foldedDigest, err := kzg.FoldProof(append([]kzg.Digest{
 		foldedH,
 		linearizedPolynomialDigest,
 		proof.LRO[0],
@@ -210,7 +265,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		proof.LRO[2],
 		vk.S[0],
 		vk.S[1],
-	},
+	}, proof.Bsb22Commitments...),
 		&proof.BatchedProof,
 		zeta,
 		hFunc,
@@ -222,6 +277,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
+	_, pairingSpan := tracing.Start(context.Background(), tracing.Tracer(nil), "plonk.verify.pairing")
 	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
 		foldedDigest,
 		proof.Z,
@@ -236,6 +292,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector) error {
 		},
 		vk.KZGSRS,
 	)
+	pairingSpan.End()
 
 	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
 
@@ -271,6 +328,11 @@ func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk VerifyingKey
 	if err := fs.Bind(challenge, vk.Qk.Marshal()); err != nil {
 		return err
 	}
+	for i := range vk.Qcp {
+		if err := fs.Bind(challenge, vk.Qcp[i].Marshal()); err != nil {
+			return err
+		}
+	}
 
 	// public inputs
 	for i := 0; i < len(publicInputs); i++ {
@@ -303,13 +365,52 @@ func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*cu
 	return r, nil
 }
 
+// solveCommitmentWire returns the value of the commitment wire for the given
+// commitment to the committed wires.
+func solveCommitmentWire(commitment *kzg.Digest) (fr.Element, error) {
+	res, err := fr.Hash(commitment.Marshal(), []byte(constraint.CommitmentDst), 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return res[0], nil
+}
+
+func toPointers(digests []kzg.Digest) []*curve.G1Affine {
+	res := make([]*curve.G1Affine, len(digests))
+	for i := range digests {
+		res[i] = &digests[i]
+	}
+	return res
+}
+
 // ExportSolidity exports the verifying key to a solidity smart contract.
 //
 // See https://github.com/ConsenSys/gnark-tests for example usage.
 //
 // Code has not been audited and is provided as-is, we make no guarantees or warranties to its safety and reliability.
-func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
-	tmpl, err := template.New("").Parse(solidityTemplate)
+//
+// The generated code can be customized with options, see package backend/solidity.
+// The PLONK verifier can't be exported as a library.
+func (vk *VerifyingKey) ExportSolidity(w io.Writer, opts ...solidity.ExportOption) error {
+	if len(vk.Qcp) != 0 {
+		return errors.New("solidity verifier does not support commitments")
+	}
+	cfg, err := solidity.NewExportConfig(opts...)
+	if err != nil {
+		return err
+	}
+	if cfg.Library {
+		return errors.New("the PLONK solidity verifier can't be exported as a library")
+	}
+	nbInputs, err := cfg.NbPublicInputs(int(vk.NbPublicVariables))
+	if err != nil {
+		return err
+	}
+	helpers := cfg.TemplateFuncs()
+	helpers["nbInputs"] = func() int {
+		return nbInputs
+	}
+	tmpl, err := template.New("").Funcs(helpers).Parse(solidityTemplate)
 	if err != nil {
 		return err
 	}
@@ -20,19 +20,17 @@
 package plonk
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
+	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
-	"github.com/consensys/gnark/constraint"
-
-	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
-	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/backend/witness"
-	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
-
-	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/constraint"
 
 	gnarkio "github.com/consensys/gnark/io"
 )
@@ -52,8 +50,26 @@ type Proof interface {
 type ProvingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.UnsafeReaderFrom
 	InitKZG(srs kzg.SRS) error
 	VerifyingKey() interface{}
+
+	// CircuitDigest returns the fingerprint of the constraint system the key was
+	// generated for, zero if the key was serialized without it
+	CircuitDigest() constraint.Digest
+
+	// Metadata returns the metadata of the constraint system the key was generated
+	// for (see frontend.WithMetadata), nil if the key was serialized without them
+	Metadata() constraint.Metadata
+
+	// Validate checks that the points of the key are on the curve and in the
+	// correct subgroup, and that its sizes are consistent; it is meant for keys
+	// read with UnsafeReadFrom
+	Validate() error
+
+	// Trim drops the polynomials of the key derived from the others, which are
+	// then recomputed by Prove, to shrink the key in memory and serialized
+	Trim()
 }
 
 // VerifyingKey represents a plonk VerifyingKey
@@ -62,21 +78,77 @@ type ProvingKey interface {
 type VerifyingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.UnsafeReaderFrom
+
+	// json.Marshaler and json.Unmarshaler encode the key in a human-readable
+	// form, with named fields and hex encoded points
+	json.Marshaler
+	json.Unmarshaler
+
 	InitKZG(srs kzg.SRS) error
 	NbPublicWitness() int // number of elements expected in the public witness
-	ExportSolidity(w io.Writer) error
+	ExportSolidity(w io.Writer, opts ...solidity.ExportOption) error
+
+	// EstimateGas estimates the gas of a call to the verifier exported with
+	// ExportSolidity and the same options, see solidity.GasEstimate
+	EstimateGas(opts ...solidity.ExportOption) (solidity.GasEstimate, error)
+
+	// CircuitDigest returns the fingerprint of the constraint system the key was
+	// generated for, zero if the key was serialized without it
+	CircuitDigest() constraint.Digest
+
+	// Metadata returns the metadata of the constraint system the key was generated
+	// for (see frontend.WithMetadata), nil if the key was serialized without them
+	Metadata() constraint.Metadata
+
+	// Validate checks that the points of the key are on the curve and in the
+	// correct subgroup, and that its sizes are consistent; it is meant for keys
+	// read with UnsafeReadFrom
+	Validate() error
 }
 
 // Setup prepares the public data associated to a circuit + public inputs.
-func Setup(ccs constraint.ConstraintSystem, kzgSRS kzg.SRS) (ProvingKey, VerifyingKey, error) {
+//
+// The setup is faster when the SRS in Lagrange basis of the circuit size is given
+// with backend.WithLagrangeSRS, see NewLagrangeSRS.
+func Setup(ccs constraint.ConstraintSystem, kzgSRS kzg.SRS, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error) {
+	b, err := backendOf(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.Setup(ccs, kzgSRS, opts...)
+}
 
-	switch tccs := ccs.(type) {
-	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs, kzgSRS.(*kzg_bn254.SRS))
-	default:
-		panic("unrecognized SparseR1CS curve type")
+// NewLagrangeSRS returns the KZG SRS in Lagrange basis of the evaluation domain of
+// ccs, computed from kzgSRS in canonical basis. Computing it is expensive: it is
+// meant to be computed once per circuit size, persisted with its WriteTo method,
+// and given to the subsequent setups with backend.WithLagrangeSRS.
+func NewLagrangeSRS(ccs constraint.ConstraintSystem, kzgSRS kzg.SRS) (kzg.SRS, error) {
+	b, err := backendOf(ccs)
+	if err != nil {
+		return nil, err
 	}
+	return b.NewLagrangeSRS(ccs, kzgSRS)
+}
 
+// DomainSize returns the cardinality of the evaluation domain of ccs, which is the
+// size of its SRS in Lagrange basis. It panics where DomainSizeE returns an error.
+func DomainSize(ccs constraint.ConstraintSystem) uint64 {
+	size, err := DomainSizeE(ccs)
+	if err != nil {
+		panic(err)
+	}
+	return size
+}
+
+// DomainSizeE is DomainSize returning the error Setup would return for a
+// constraint system other than a SparseR1CS of an implemented curve.
+func DomainSizeE(ccs constraint.ConstraintSystem) (uint64, error) {
+	b, err := backendOf(ccs)
+	if err != nil {
+		return 0, err
+	}
+	return b.DomainSize(ccs)
 }
 
 // Prove generates PLONK proof from a circuit, associated preprocessed public data, and the witness
@@ -86,83 +158,183 @@ func Setup(ccs constraint.ConstraintSystem, kzgSRS kzg.SRS) (ProvingKey, Verifyi
 //	 will produce an invalid proof
 //		internally, the solution vector to the SparseR1CS will be filled with random values which may impact benchmarking
 func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
-
-	switch tccs := ccs.(type) {
-	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Prove(tccs, pk.(*plonk_bn254.ProvingKey), fullWitness, opts...)
-	default:
-		panic("unrecognized SparseR1CS curve type")
+	b, err := backendOf(ccs)
+	if err != nil {
+		return nil, err
 	}
+	return b.Prove(ccs, pk, fullWitness, opts...)
 }
 
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
 func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
+	b, err := backendOfWitness(publicWitness)
+	if err != nil {
+		return err
+	}
+	return b.Verify(proof, vk, publicWitness)
+}
 
-	switch _proof := proof.(type) {
-
-	case *plonk_bn254.Proof:
-		w, ok := publicWitness.Vector().(fr_bn254.Vector)
-		if !ok {
-			return witness.ErrInvalidWitness
+// NewSolidityDescriptor returns the descriptor of the public inputs of the
+// verify_serialized_proof function of the Solidity verifier exported with
+// vk.ExportSolidity and the same options, so that web frontends can build its
+// calldata. The public inputs are laid out as in layout, see
+// witness.NewPublicLayout; it is an error if vk doesn't expect them.
+func NewSolidityDescriptor(vk VerifyingKey, layout *witness.PublicLayout, opts ...solidity.ExportOption) (*solidity.Descriptor, error) {
+	if err := layout.CheckVerifyingKey(vk); err != nil {
+		return nil, err
+	}
+	// the verifying keys don't expose their curve: the descriptor is the one of
+	// the first implementation accepting vk
+	for _, curve := range Curves() {
+		b, err := getBackend(curve)
+		if err != nil {
+			return nil, err
+		}
+		d, err := b.NewSolidityDescriptor(vk, layout, opts...)
+		if !errors.Is(err, backend.ErrBackendMismatch) {
+			return d, err
 		}
-		return plonk_bn254.Verify(_proof, vk.(*plonk_bn254.VerifyingKey), w)
+	}
+	return nil, errMismatch("verifying key", vk)
+}
+
+// EncodeCalldata returns the ABI encoded calldata of a call to the verify_serialized_proof
+// function of the Solidity verifier exported with VerifyingKey.ExportSolidity,
+// for the proof and the public witness.
+func EncodeCalldata(proof Proof, publicWitness witness.Witness) ([]byte, error) {
+	b, err := backendOfWitness(publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	return b.EncodeCalldata(proof, publicWitness)
+}
+
+// DecodeCalldata returns the proof and the public witness encoded in calldata by
+// EncodeCalldata.
+func DecodeCalldata(curveID ecc.ID, calldata []byte) (Proof, witness.Witness, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.DecodeCalldata(calldata)
+}
+
+// EncodeCairoCalldata returns the calldata, as felt252, of a call to the verify_serialized_proof
+// function of a Cairo port of the Solidity verifier, for the proof and the public witness.
+func EncodeCairoCalldata(proof Proof, publicWitness witness.Witness) ([]*big.Int, error) {
+	b, err := backendOfWitness(publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	return b.EncodeCairoCalldata(proof, publicWitness)
+}
 
-	default:
-		panic("unrecognized proof type")
+// DecodeCairoCalldata returns the proof and the public witness encoded in
+// calldata by EncodeCairoCalldata.
+func DecodeCairoCalldata(curveID ecc.ID, calldata []*big.Int) (Proof, witness.Witness, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return b.DecodeCairoCalldata(calldata)
+}
+
+// newPublicWitness returns the public witness filled with the nbPublic values.
+func newPublicWitness(curveID ecc.ID, nbPublic int, values chan any) (witness.Witness, error) {
+	publicWitness, err := witness.New(curveID.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err = publicWitness.Fill(nbPublic, 0, values); err != nil {
+		return nil, err
+	}
+	return publicWitness, nil
 }
 
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
-// This method exists for (de)serialization purposes
+// This method exists for (de)serialization purposes; it panics where
+// NewCSE returns an error
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
-	var r1cs constraint.ConstraintSystem
-	switch curveID {
-	case ecc.BN254:
-		r1cs = &cs_bn254.SparseR1CS{}
-	default:
-		panic("not implemented")
+	res, err := NewCSE(curveID)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// NewCSE is NewCS returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewCSE(curveID ecc.ID) (constraint.ConstraintSystem, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
 	}
-	return r1cs
+	return b.NewCS(), nil
 }
 
 // NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewProvingKeyE returns an error
 func NewProvingKey(curveID ecc.ID) ProvingKey {
-	var pk ProvingKey
-	switch curveID {
-	case ecc.BN254:
-		pk = &plonk_bn254.ProvingKey{}
-	default:
-		panic("not implemented")
+	res, err := NewProvingKeyE(curveID)
+	if err != nil {
+		panic(err)
 	}
+	return res
+}
 
-	return pk
+// NewProvingKeyE is NewProvingKey returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewProvingKeyE(curveID ecc.ID) (ProvingKey, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewProvingKey(), nil
 }
 
 // NewProof instantiates a curve-typed ProvingKey and returns an interface
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewProofE returns an error
 func NewProof(curveID ecc.ID) Proof {
-	var proof Proof
-	switch curveID {
-	case ecc.BN254:
-		proof = &plonk_bn254.Proof{}
-	default:
-		panic("not implemented")
+	res, err := NewProofE(curveID)
+	if err != nil {
+		panic(err)
 	}
+	return res
+}
 
-	return proof
+// NewProofE is NewProof returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewProofE(curveID ecc.ID) (Proof, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewProof(), nil
 }
 
 // NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
-// This function exists for serialization purposes
+// This function exists for serialization purposes; it panics where
+// NewVerifyingKeyE returns an error
 func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
-	var vk VerifyingKey
-	switch curveID {
-	case ecc.BN254:
-		vk = &plonk_bn254.VerifyingKey{}
-	default:
-		panic("not implemented")
+	res, err := NewVerifyingKeyE(curveID)
+	if err != nil {
+		panic(err)
 	}
+	return res
+}
 
-	return vk
+// NewVerifyingKeyE is NewVerifyingKey returning an error instead of panicking. The
+// error wraps backend.ErrUnsupportedCurve for a curve without implementation
+// (see Register).
+func NewVerifyingKeyE(curveID ecc.ID) (VerifyingKey, error) {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return nil, err
+	}
+	return b.NewVerifyingKey(), nil
 }
@@ -0,0 +1,579 @@
+package plonk_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/internal/tinyfield"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type calldataCircuit struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *calldataCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsDifferent(c.X, c.Z)
+	return nil
+}
+
+func TestCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	calldata, err := plonk.EncodeCalldata(proof, public)
+	assert.NoError(err)
+	// selector, 2 offsets, 2 lengths, 2 public inputs and 26 words of proof
+	assert.Len(calldata, 4+(2+2+2+26)*32)
+
+	decodedProof, decodedPublic, err := plonk.DecodeCalldata(ecc.BN254, calldata)
+	assert.NoError(err)
+	assert.Equal(public.Vector(), decodedPublic.Vector())
+	assert.NoError(plonk.Verify(decodedProof, vk, decodedPublic))
+
+	// invalid layout
+	calldata[4+31] = 0
+	_, _, err = plonk.DecodeCalldata(ecc.BN254, calldata)
+	assert.Error(err)
+}
+
+func TestCairoCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	calldata, err := plonk.EncodeCairoCalldata(proof, public)
+	assert.NoError(err)
+	// 2 lengths, 2 u256 public inputs and 26 u256 of proof
+	assert.Len(calldata, 2+2*(2+26))
+	assert.Equal(big.NewInt(2), calldata[0])
+	assert.Equal(big.NewInt(9), calldata[1])
+	assert.Equal(big.NewInt(26), calldata[5])
+
+	decodedProof, decodedPublic, err := plonk.DecodeCairoCalldata(ecc.BN254, calldata)
+	assert.NoError(err)
+	assert.Equal(public.Vector(), decodedPublic.Vector())
+	assert.NoError(plonk.Verify(decodedProof, vk, decodedPublic))
+
+	// invalid layout
+	calldata[5] = big.NewInt(25)
+	_, _, err = plonk.DecodeCairoCalldata(ecc.BN254, calldata)
+	assert.Error(err)
+}
+
+func TestCircuitDigest(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), vk.CircuitDigest())
+
+	// the digest is serialized with the keys
+	var bPK, bVK bytes.Buffer
+	_, err = pk.WriteTo(&bPK)
+	assert.NoError(err)
+	_, err = vk.WriteTo(&bVK)
+	assert.NoError(err)
+	pk = plonk.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&bPK)
+	assert.NoError(err)
+	assert.NoError(pk.InitKZG(srs))
+	vk = plonk.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&bVK)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), pk.CircuitDigest())
+	assert.Equal(ccs.Digest(), vk.CircuitDigest())
+
+	// proving with another circuit
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &otherCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&otherCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = plonk.Prove(other, pk, w)
+	assert.ErrorIs(err, backend.ErrCircuitMismatch)
+}
+
+type otherCircuit struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *otherCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsDifferent(c.Y, c.Z)
+	return nil
+}
+
+func TestUnsafeReadFromValidate(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	assert.NoError(pk.Validate())
+	assert.NoError(vk.Validate())
+
+	var bPK, bVK bytes.Buffer
+	_, err = pk.WriteTo(&bPK)
+	assert.NoError(err)
+	_, err = vk.WriteTo(&bVK)
+	assert.NoError(err)
+	read := func() (*plonk_bn254.ProvingKey, *plonk_bn254.VerifyingKey) {
+		pk := plonk.NewProvingKey(ecc.BN254)
+		_, err := pk.UnsafeReadFrom(bytes.NewReader(bPK.Bytes()))
+		assert.NoError(err)
+		vk := plonk.NewVerifyingKey(ecc.BN254)
+		_, err = vk.UnsafeReadFrom(bytes.NewReader(bVK.Bytes()))
+		assert.NoError(err)
+		return pk.(*plonk_bn254.ProvingKey), vk.(*plonk_bn254.VerifyingKey)
+	}
+	_pk, _vk := read()
+	assert.NoError(_pk.Validate())
+	assert.NoError(_vk.Validate())
+
+	for _, c := range []struct {
+		name    string
+		corrupt func(pk *plonk_bn254.ProvingKey, vk *plonk_bn254.VerifyingKey)
+		pk, vk  bool // whether the corrupted pk and vk are valid
+	}{
+		{"point not on the curve", func(_ *plonk_bn254.ProvingKey, vk *plonk_bn254.VerifyingKey) { vk.Ql.Y.SetOne() }, true, false},
+		{"inconsistent domain", func(pk *plonk_bn254.ProvingKey, _ *plonk_bn254.VerifyingKey) { pk.Vk.Size *= 2 }, false, true},
+	} {
+		_pk, _vk := read()
+		c.corrupt(_pk, _vk)
+		assert.Equal(c.pk, _pk.Validate() == nil, c.name)
+		assert.Equal(c.vk, _vk.Validate() == nil, c.name)
+	}
+}
+
+func TestArmored(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	var bProof, bVK bytes.Buffer
+	_, err = gnarkio.WriteArmored(&bProof, proof)
+	assert.NoError(err)
+	_, err = gnarkio.WriteArmored(&bVK, vk)
+	assert.NoError(err)
+	assert.Contains(bProof.String(), "-----BEGIN GNARK PLONK PROOF-----")
+	assert.Contains(bVK.String(), "-----BEGIN GNARK PLONK VERIFYING KEY-----")
+
+	// ReadFrom detects the armor
+	proof = plonk.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(&bProof)
+	assert.NoError(err)
+	vk = plonk.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&bVK)
+	assert.NoError(err)
+	assert.NoError(vk.InitKZG(srs))
+	assert.NoError(plonk.Verify(proof, vk, public))
+}
+
+// spanRecorder is a tracer provider recording the names of the started spans.
+type spanRecorder struct {
+	lock  sync.Mutex
+	names []string
+}
+
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return r
+}
+
+func (r *spanRecorder) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	r.lock.Lock()
+	r.names = append(r.names, name)
+	r.lock.Unlock()
+	return ctx, trace.SpanFromContext(context.Background())
+}
+
+func TestTracing(t *testing.T) {
+	assert := require.New(t)
+
+	var global, local spanRecorder
+	tracing.SetTracerProvider(&global)
+	defer tracing.Disable()
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{}, frontend.WithTracing(context.Background(), &local))
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w, backend.WithTracing(context.Background(), &local))
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, public))
+
+	for _, name := range []string{"gnark.compile", "gnark.compile.define", "gnark.compile.build", "plonk.prove", "plonk.prove.solve", "plonk.prove.msm", "plonk.prove.fft", "plonk.prove.open"} {
+		assert.Contains(local.names, name)
+	}
+	assert.Equal([]string{"plonk.setup", "plonk.verify", "plonk.verify.pairing"}, global.names)
+}
+
+func TestBufferPooling(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	// the second and third proofs reuse the vectors of the previous ones
+	for i := 2; i < 5; i++ {
+		w, err := frontend.NewWitness(&calldataCircuit{X: i, Y: i * i, Z: 0}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		public, err := w.Public()
+		assert.NoError(err)
+		proof, err := plonk.Prove(ccs, pk, w, backend.WithBufferPooling())
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(proof, vk, public))
+	}
+}
+
+func TestProveStream(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	const nbProofs = 6
+	witnesses := make(chan witness.Witness)
+	go func() {
+		defer close(witnesses)
+		for i := 0; i < nbProofs; i++ {
+			y := i * i
+			if i == 3 {
+				y++ // not satisfied
+			}
+			w, err := frontend.NewWitness(&calldataCircuit{X: i, Y: y, Z: -1}, ecc.BN254.ScalarField())
+			if err != nil {
+				panic(err)
+			}
+			witnesses <- w
+		}
+	}()
+
+	prover := plonk.NewProver(ccs, pk, backend.WithBufferPooling())
+	i := 0
+	for res := range prover.ProveStream(witnesses) {
+		if i == 3 {
+			assert.Error(res.Err)
+			i++
+			continue
+		}
+		assert.NoError(res.Err, "proof %d", i)
+		public, err := frontend.NewWitness(&calldataCircuit{Y: i * i, Z: -1}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(res.Proof, vk, public), "proof %d", i)
+		i++
+	}
+	assert.Equal(nbProofs, i)
+}
+
+func TestLagrangeSRS(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, lagrangeSRS, err := test.NewKZGSRSPair(ccs)
+	assert.NoError(err)
+
+	// the lagrange srs is persisted and read back
+	var buf bytes.Buffer
+	_, err = lagrangeSRS.WriteTo(&buf)
+	assert.NoError(err)
+	lagrangeSRS = kzg.NewSRS(ecc.BN254)
+	_, err = lagrangeSRS.ReadFrom(&buf)
+	assert.NoError(err)
+
+	pk, vk, err := plonk.Setup(ccs, srs, backend.WithLagrangeSRS(lagrangeSRS))
+	assert.NoError(err)
+
+	// the keys are the same as without the lagrange srs
+	pkRef, vkRef, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	var b1, b2 bytes.Buffer
+	_, err = vk.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = vkRef.WriteTo(&b2)
+	assert.NoError(err)
+	assert.Equal(b2.Bytes(), b1.Bytes())
+	b1.Reset()
+	b2.Reset()
+	_, err = pk.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = pkRef.WriteTo(&b2)
+	assert.NoError(err)
+	assert.Equal(b2.Bytes(), b1.Bytes())
+
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 0}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, public))
+
+	// the lagrange srs must have the size of the circuit
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &powerCircuit{})
+	assert.NoError(err)
+	assert.NotEqual(plonk.DomainSize(ccs), plonk.DomainSize(other))
+	_, _, err = plonk.Setup(other, srs, backend.WithLagrangeSRS(lagrangeSRS))
+	assert.Error(err)
+}
+
+type powerCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *powerCircuit) Define(api frontend.API) error {
+	res := c.X
+	for i := 0; i < 30; i++ {
+		res = api.Mul(res, c.X)
+	}
+	api.AssertIsEqual(res, c.Y)
+	return nil
+}
+
+func TestMetadata(t *testing.T) {
+	assert := require.New(t)
+
+	metadata := constraint.Metadata{"version": "v1.2.0", "source": "abcdef"}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{},
+		frontend.WithMetadata("version", "v1.2.0"), frontend.WithMetadata("source", "abcdef"))
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	assert.Equal(metadata, vk.Metadata())
+
+	// the metadata are serialized with the keys
+	var bPK, bVK bytes.Buffer
+	_, err = pk.WriteTo(&bPK)
+	assert.NoError(err)
+	_, err = vk.WriteTo(&bVK)
+	assert.NoError(err)
+	pk = plonk.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&bPK)
+	assert.NoError(err)
+	vk = plonk.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&bVK)
+	assert.NoError(err)
+	assert.Equal(metadata, pk.Metadata())
+	assert.Equal(metadata, vk.Metadata())
+}
+
+func TestVerifyingKeyJSON(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{},
+		frontend.WithMetadata("version", "v1.2.0"))
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	data, err := json.MarshalIndent(vk, "", "  ")
+	assert.NoError(err)
+	assert.Contains(string(data), `"circuitDigest": "`+ccs.Digest().String()+`"`)
+
+	decoded := plonk.NewVerifyingKey(ecc.BN254)
+	assert.NoError(json.Unmarshal(data, decoded))
+	assert.NoError(decoded.InitKZG(srs))
+	assert.Equal(vk.CircuitDigest(), decoded.CircuitDigest())
+	assert.Equal(vk.Metadata(), decoded.Metadata())
+
+	// the decoded key is the same as the original one
+	var expected, actual bytes.Buffer
+	_, err = vk.WriteTo(&expected)
+	assert.NoError(err)
+	_, err = decoded.WriteTo(&actual)
+	assert.NoError(err)
+	assert.Equal(expected.Bytes(), actual.Bytes())
+
+	// and verifies the proofs of the original one
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, decoded, pw))
+
+	// keys of another scheme or with inconsistent domain parameters are rejected
+	assert.Error(json.Unmarshal([]byte(strings.Replace(string(data), `"plonk"`, `"groth16"`, 1)), decoded))
+	assert.Error(json.Unmarshal([]byte(strings.Replace(string(data), `"sizeInv": "`, `"sizeInv": "1`, 1)), decoded))
+}
+
+func TestTrim(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+
+	var full, trimmed bytes.Buffer
+	_, err = pk.WriteTo(&full)
+	assert.NoError(err)
+	pk.Trim()
+	assert.NoError(pk.Validate())
+	_, err = pk.WriteTo(&trimmed)
+	assert.NoError(err)
+	assert.Less(trimmed.Len(), full.Len())
+
+	// the trimmed key proves, in memory and read back
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, public))
+
+	pk = plonk.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&trimmed)
+	assert.NoError(err)
+	assert.NoError(pk.InitKZG(srs))
+	assert.NoError(pk.Validate())
+	proof, err = plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, public))
+}
+
+func TestTypedErrors(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&calldataCircuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+
+	// R1CS given to PLONK
+	r1csCCS, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	_, _, err = plonk.Setup(r1csCCS, srs)
+	assert.ErrorIs(err, backend.ErrBackendMismatch)
+	_, err = plonk.Prove(r1csCCS, pk, w)
+	assert.ErrorIs(err, backend.ErrBackendMismatch)
+	_, err = plonk.DomainSizeE(r1csCCS)
+	assert.ErrorIs(err, backend.ErrBackendMismatch)
+	assert.Panics(func() { plonk.DomainSize(r1csCCS) })
+
+	// Groth16 proofs given to PLONK and SparseR1CS given to Groth16
+	g16PK, _, err := groth16.Setup(r1csCCS)
+	assert.NoError(err)
+	g16Proof, err := groth16.Prove(r1csCCS, g16PK, w)
+	assert.NoError(err)
+	assert.ErrorIs(plonk.Verify(g16Proof, vk, pw), backend.ErrBackendMismatch)
+	_, err = plonk.EncodeCalldata(g16Proof, pw)
+	assert.ErrorIs(err, backend.ErrBackendMismatch)
+	_, err = groth16.Prove(ccs, g16PK, w)
+	assert.ErrorIs(err, backend.ErrBackendMismatch)
+	assert.NoError(plonk.Verify(proof, vk, pw))
+
+	// constraint systems and curves without backend
+	tinyCCS, err := frontend.Compile(tinyfield.Modulus(), scs.NewBuilder, &calldataCircuit{})
+	assert.NoError(err)
+	_, _, err = plonk.Setup(tinyCCS, srs)
+	assert.ErrorIs(err, backend.ErrUnsupportedCurve)
+	for _, c := range []struct {
+		name string
+		f    func() error
+	}{
+		{"plonk.DecodeCalldata", func() error { _, _, err := plonk.DecodeCalldata(ecc.BLS12_381, nil); return err }},
+		{"plonk.NewCSE", func() error { _, err := plonk.NewCSE(ecc.BLS12_381); return err }},
+		{"plonk.NewProvingKeyE", func() error { _, err := plonk.NewProvingKeyE(ecc.BLS12_381); return err }},
+		{"plonk.NewVerifyingKeyE", func() error { _, err := plonk.NewVerifyingKeyE(ecc.BLS12_381); return err }},
+		{"plonk.NewProofE", func() error { _, err := plonk.NewProofE(ecc.BLS12_381); return err }},
+		{"groth16.DecodeCalldata", func() error { _, _, err := groth16.DecodeCalldata(ecc.BLS12_381, nil); return err }},
+	} {
+		assert.ErrorIs(c.f(), backend.ErrUnsupportedCurve, c.name)
+	}
+}
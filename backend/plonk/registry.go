@@ -0,0 +1,132 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonk
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Backend is the implementation of PLONK over a curve, to which the functions
+// of this package dispatch the objects of the curve (see Register).
+//
+// The constraint systems, keys and proofs given to its methods are expected to
+// be of its curve; it returns an error wrapping backend.ErrBackendMismatch for
+// the ones of other implementations.
+type Backend interface {
+	NewCS() constraint.ConstraintSystem
+	NewProvingKey() ProvingKey
+	NewVerifyingKey() VerifyingKey
+	NewProof() Proof
+
+	Setup(spr constraint.ConstraintSystem, srs kzg.SRS, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error)
+	NewLagrangeSRS(spr constraint.ConstraintSystem, srs kzg.SRS) (kzg.SRS, error)
+	DomainSize(spr constraint.ConstraintSystem) (uint64, error)
+	Prove(spr constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error)
+	Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error
+
+	NewSolidityDescriptor(vk VerifyingKey, layout *witness.PublicLayout, opts ...solidity.ExportOption) (*solidity.Descriptor, error)
+	EncodeCalldata(proof Proof, publicWitness witness.Witness) ([]byte, error)
+	DecodeCalldata(calldata []byte) (Proof, witness.Witness, error)
+	EncodeCairoCalldata(proof Proof, publicWitness witness.Witness) ([]*big.Int, error)
+	DecodeCairoCalldata(calldata []*big.Int) (Proof, witness.Witness, error)
+}
+
+var (
+	backends  = make(map[ecc.ID]Backend)
+	backendsM sync.RWMutex
+)
+
+// Register makes the implementation b of PLONK over curve available to the
+// functions of this package. As for the drivers of database/sql, it is meant to
+// be called in the init function of the package of the implementation, which
+// is then imported for its side effects; it panics if b is nil or if an
+// implementation is already registered for curve.
+//
+// The implementation over BN254 is registered by this package, unless built
+// with the tag gnark_no_bn254, so that downstream forks can strip it from their
+// binaries or replace it.
+func Register(curve ecc.ID, b Backend) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+	if b == nil {
+		panic("plonk: Register backend is nil")
+	}
+	if _, ok := backends[curve]; ok {
+		panic(fmt.Sprintf("plonk: Register called twice for curve %s", curve))
+	}
+	backends[curve] = b
+}
+
+// Curves returns the curves with a registered implementation, sorted.
+func Curves() []ecc.ID {
+	backendsM.RLock()
+	defer backendsM.RUnlock()
+	curves := make([]ecc.ID, 0, len(backends))
+	for curve := range backends {
+		curves = append(curves, curve)
+	}
+	sort.Slice(curves, func(i, j int) bool { return curves[i] < curves[j] })
+	return curves
+}
+
+// getBackend returns the implementation registered for curve, or an error
+// wrapping backend.ErrUnsupportedCurve.
+func getBackend(curve ecc.ID) (Backend, error) {
+	backendsM.RLock()
+	defer backendsM.RUnlock()
+	b, ok := backends[curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", backend.ErrUnsupportedCurve, curve)
+	}
+	return b, nil
+}
+
+// backendOf returns the implementation of the curve of the SparseR1CS ccs.
+func backendOf(ccs constraint.ConstraintSystem) (Backend, error) {
+	if _, ok := ccs.(constraint.SparseR1CS); !ok {
+		return nil, fmt.Errorf("%w: expected a SparseR1CS, got %T", backend.ErrBackendMismatch, ccs)
+	}
+	return getBackend(utils.FieldToCurve(ccs.Field()))
+}
+
+// backendOfWitness returns the implementation of the curve of the field of the
+// witness w, as the keys and proofs of PLONK don't expose their curve.
+func backendOfWitness(w witness.Witness) (Backend, error) {
+	if w == nil {
+		return nil, witness.ErrInvalidWitness
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return nil, err
+	}
+	return getBackend(utils.FieldToCurve(ins.Field()))
+}
+
+// errMismatch returns the error of the implementations for an object of
+// another proof system or curve, object naming its kind.
+func errMismatch(object string, v any) error {
+	return fmt.Errorf("%w: unexpected %s type %T", backend.ErrBackendMismatch, object, v)
+}
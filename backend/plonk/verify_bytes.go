@@ -0,0 +1,78 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// VerifyBytes verifies the serialized proof of the serialized public witness
+// with the serialized verifying key, all over the curve curveID, in one call.
+// It is meant for FFI and serverless callers, which hold the objects as bytes
+// and not as Go objects.
+//
+// Each slice must hold a single object, in any of the encodings read by its
+// ReadFrom method. The objects are decoded straight from the slices with the
+// checks of ReadFrom, which include the subgroup checks of the points of the
+// key and of the proof.
+func VerifyBytes(curveID ecc.ID, proofBytes, vkBytes, publicWitnessBytes []byte) error {
+	b, err := getBackend(curveID)
+	if err != nil {
+		return err
+	}
+
+	vk := b.NewVerifyingKey()
+	if err := readBytes(vk.ReadFrom, vkBytes); err != nil {
+		return fmt.Errorf("read verifying key: %w", err)
+	}
+	proof := b.NewProof()
+	if err := readBytes(proof.ReadFrom, proofBytes); err != nil {
+		return fmt.Errorf("read proof: %w", err)
+	}
+	w, err := witness.New(curveID.ScalarField())
+	if err != nil {
+		return err
+	}
+	if err := readBytes(w.ReadFrom, publicWitnessBytes); err != nil {
+		return fmt.Errorf("read public witness: %w", err)
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return err
+	}
+	if ins.NbSecret() != 0 {
+		return fmt.Errorf("%w: expected a public witness, got %d secret elements", witness.ErrInvalidWitness, ins.NbSecret())
+	}
+
+	return b.Verify(proof, vk, w)
+}
+
+// readBytes reads an object from data with readFrom. data must hold the object
+// only.
+func readBytes(readFrom func(io.Reader) (int64, error), data []byte) error {
+	n, err := readFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return fmt.Errorf("%d unexpected bytes after the object", int64(len(data))-n)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package plonkfri
 
 import (
 	"crypto/sha256"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fri"
@@ -100,6 +101,10 @@ type VerifyingKey struct {
 // Setup sets proving and verifying keys
 func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 
+	if spr.CommitmentInfo.Is() {
+		return nil, nil, errors.New("commitments are not supported by the plonkfri backend")
+	}
+
 	var pk ProvingKey
 	var vk VerifyingKey
 
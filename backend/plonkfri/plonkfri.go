@@ -17,8 +17,11 @@
 package plonkfri
 
 import (
+	"fmt"
+
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
 
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	plonk_bn254 "github.com/consensys/gnark/backend/plonkfri/bn254"
@@ -60,7 +63,7 @@ func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
 	case *cs_bn254.SparseR1CS:
 		return plonk_bn254.Setup(tccs)
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, nil, errConstraintSystem(ccs)
 	}
 
 }
@@ -75,10 +78,14 @@ func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.W
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Prove(tccs, pk.(*plonk_bn254.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bn254.ProvingKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected proving key type %T", backend.ErrBackendMismatch, pk)
+		}
+		return plonk_bn254.Prove(tccs, _pk, fullWitness, opts...)
 
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, errConstraintSystem(ccs)
 	}
 }
 
@@ -92,9 +99,22 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bn254.Verify(_proof, vk.(*plonk_bn254.VerifyingKey), w)
+		_vk, ok := vk.(*plonk_bn254.VerifyingKey)
+		if !ok {
+			return fmt.Errorf("%w: unexpected verifying key type %T", backend.ErrBackendMismatch, vk)
+		}
+		return plonk_bn254.Verify(_proof, _vk, w)
 
 	default:
-		panic("unrecognized proof type")
+		return fmt.Errorf("%w: unexpected proof type %T", backend.ErrBackendMismatch, proof)
+	}
+}
+
+// errConstraintSystem returns the error of the entry points for a constraint
+// system other than a SparseR1CS of an implemented curve.
+func errConstraintSystem(ccs constraint.ConstraintSystem) error {
+	if _, ok := ccs.(constraint.SparseR1CS); !ok {
+		return fmt.Errorf("%w: expected a SparseR1CS, got %T", backend.ErrBackendMismatch, ccs)
 	}
+	return fmt.Errorf("%w: %s", backend.ErrUnsupportedCurve, utils.FieldToCurve(ccs.Field()))
 }
@@ -0,0 +1,122 @@
+package solidity
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"text/template"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Descriptor describes the public inputs expected by an exported verifier, so
+// that the callers written in other languages (typically web frontends) can
+// build its calldata. It is written next to the verifier, in JSON with
+// WriteJSON or as a TypeScript module with WriteTypeScript.
+type Descriptor struct {
+	Contract string `json:"contract"` // name of the verifier contract
+	Function string `json:"function"` // signature of the verifier function
+	Argument int    `json:"argument"` // index of the public inputs in the arguments of the function
+
+	// Modulus is the decimal modulus of the scalar field, the public inputs
+	// must be smaller.
+	Modulus string `json:"modulus"`
+
+	Inputs []DescriptorInput `json:"publicInputs"`
+}
+
+// DescriptorInput is a public input of a verifier, see Descriptor.
+type DescriptorInput struct {
+	Name  string `json:"name"`  // full name of the field of the circuit
+	Index int    `json:"index"` // index in the public inputs argument
+	Type  string `json:"type"`  // Solidity type of the input
+}
+
+// NewDescriptor returns the descriptor of the function of the verifier
+// contract, whose public inputs over the scalar field of modulus field follow
+// layout. The name of the contract is the one set by the options, or
+// defaultContract.
+//
+// It is called by the backends, see for example groth16.NewSolidityDescriptor.
+func NewDescriptor(layout *witness.PublicLayout, field *big.Int, defaultContract, function string, argument int, opts ...ExportOption) (*Descriptor, error) {
+	cfg, err := NewExportConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	d := &Descriptor{
+		Contract: cfg.contractName(defaultContract),
+		Function: function,
+		Argument: argument,
+		Modulus:  field.String(),
+		Inputs:   make([]DescriptorInput, len(layout.Inputs)),
+	}
+	for i, input := range layout.Inputs {
+		d.Inputs[i] = DescriptorInput{
+			Name:  input.Name,
+			Index: input.Index,
+			Type:  "uint256",
+		}
+	}
+	return d, nil
+}
+
+// WriteJSON writes the descriptor in indented JSON.
+func (d *Descriptor) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteTypeScript writes the descriptor as a TypeScript module exporting the
+// PublicInputs interface, indexed by the names of the inputs, and the
+// encodePublicInputs function returning them in the order of the verifier,
+// checked to be in the field.
+func (d *Descriptor) WriteTypeScript(w io.Writer) error {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"quote": func(s string) (string, error) {
+			b, err := json.Marshal(s)
+			return string(b), err
+		},
+	}).Parse(typeScriptTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, d)
+}
+
+const typeScriptTemplate = `// Code generated by gnark DO NOT EDIT
+
+export const verifier = {
+  contract: {{quote .Contract}},
+  function: {{quote .Function}},
+  argument: {{.Argument}},
+} as const;
+
+export const SNARK_SCALAR_FIELD = {{.Modulus}}n;
+
+export const publicInputNames = [
+{{- range .Inputs}}
+  {{quote .Name}},
+{{- end}}
+] as const;
+
+export interface PublicInputs {
+{{- range .Inputs}}
+  {{quote .Name}}: bigint; // {{.Type}}, index {{.Index}}
+{{- end}}
+}
+
+export function encodePublicInputs(inputs: PublicInputs): bigint[] {
+  const values: bigint[] = [
+{{- range .Inputs}}
+    inputs[{{quote .Name}}],
+{{- end}}
+  ];
+  values.forEach((v, i) => {
+    if (v < 0n || v >= SNARK_SCALAR_FIELD) {
+      throw new RangeError(` + "`" + `public input ${publicInputNames[i]} is not in the scalar field` + "`" + `);
+    }
+  });
+  return values;
+}
+`
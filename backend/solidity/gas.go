@@ -0,0 +1,91 @@
+package solidity
+
+import "math/big"
+
+// Gas costs of the EVM since the Berlin fork, for the precompiled contracts
+// used by the verifiers (EIP-1108 for the BN254 ones).
+const (
+	GasTransaction      = 21000 // intrinsic cost of a transaction
+	GasCalldataZeroByte = 4
+	GasCalldataByte     = 16 // non-zero byte
+	GasECAdd            = 150
+	GasECMul            = 6000
+	GasECPairing        = 45000 // plus GasECPairingPerPair per pair
+	GasECPairingPerPair = 34000
+	GasSHA256           = 60 // plus GasSHA256PerWord per 32 bytes word
+	GasSHA256PerWord    = 12
+	gasModExpMin        = 200
+)
+
+// GasEstimate is an estimate of the gas of a transaction calling an exported
+// verifier, returned by the EstimateGas method of the verifying keys, so that
+// the backends and the export options can be compared without deploying the
+// verifiers.
+//
+// It accounts for the transaction, its calldata and the precompiled contracts
+// called, which dominate the cost of a verification, but neither for the
+// interpreted code of the verifier (memory, stack and control flow), nor for
+// the hashing of the public inputs of WithPublicInputsHashing. Hence the gas
+// actually used, which the Call method of test/evm.Contract measures, is
+// higher.
+type GasEstimate struct {
+	Transaction uint64 // intrinsic cost of the transaction
+	Calldata    uint64 // calldata, counting the field elements as non-zero bytes
+	ECAdd       uint64 // point additions (ecAdd), at most
+	ECMul       uint64 // scalar multiplications (ecMul)
+	Pairing     uint64 // pairing check (ecPairing)
+	Hashing     uint64 // Fiat-Shamir challenges (sha256)
+	ModExp      uint64 // exponentiations and inversions in the scalar field (modexp)
+}
+
+// Total returns the sum of the items of e.
+func (e GasEstimate) Total() uint64 {
+	return e.Transaction + e.Calldata + e.ECAdd + e.ECMul + e.Pairing + e.Hashing + e.ModExp
+}
+
+// CalldataGas returns the gas of calldata.
+func CalldataGas(calldata []byte) uint64 {
+	var gas uint64
+	for _, b := range calldata {
+		if b == 0 {
+			gas += GasCalldataZeroByte
+		} else {
+			gas += GasCalldataByte
+		}
+	}
+	return gas
+}
+
+// EstimateCalldataGas returns the gas of a calldata made of a function
+// selector, nbWords 32 bytes words of field elements, counted as non-zero
+// bytes, and nbSmallWords words of small integers (the offsets and lengths of
+// the dynamic arrays), counted as one non-zero byte.
+func EstimateCalldataGas(nbWords, nbSmallWords int) uint64 {
+	const selectorSize, wordSize = 4, 32
+	return uint64(selectorSize+nbWords*wordSize)*GasCalldataByte +
+		uint64(nbSmallWords)*(GasCalldataByte+(wordSize-1)*GasCalldataZeroByte)
+}
+
+// ECPairingGas returns the gas of a pairing check of nbPairs pairs.
+func ECPairingGas(nbPairs int) uint64 {
+	return GasECPairing + uint64(nbPairs)*GasECPairingPerPair
+}
+
+// SHA256Gas returns the gas of the hash of size bytes.
+func SHA256Gas(size int) uint64 {
+	return GasSHA256 + uint64((size+31)/32)*GasSHA256PerWord
+}
+
+// ModExpGas returns the gas of the exponentiation of a 32 bytes base to the
+// power exponent, of at most 32 bytes, modulo a 32 bytes modulus (EIP-2565).
+func ModExpGas(exponent *big.Int) uint64 {
+	const multComplexity = (32 / 8) * (32 / 8)
+	iterations := uint64(1)
+	if exponent.BitLen() > 1 {
+		iterations = uint64(exponent.BitLen() - 1)
+	}
+	if gas := multComplexity * iterations / 3; gas > gasModExpMin {
+		return gas
+	}
+	return gasModExpMin
+}
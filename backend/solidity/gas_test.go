@@ -0,0 +1,67 @@
+package solidity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasCosts(t *testing.T) {
+	assert := require.New(t)
+
+	assert.EqualValues(3*solidity.GasCalldataZeroByte+solidity.GasCalldataByte, solidity.CalldataGas([]byte{0, 1, 0, 0}))
+	assert.EqualValues(36*solidity.GasCalldataByte+(16+31*4), solidity.EstimateCalldataGas(1, 1))
+	assert.EqualValues(113000, solidity.ECPairingGas(2))
+	assert.EqualValues(60+2*12, solidity.SHA256Gas(33))
+	assert.EqualValues(200, solidity.ModExpGas(big.NewInt(0)))
+	assert.EqualValues(16*253/3, solidity.ModExpGas(new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(2))))
+}
+
+func TestEstimateGas(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	// one public input: a scalar multiplication and an addition, 4 pairs
+	estimate, err := vk.EstimateGas()
+	assert.NoError(err)
+	assert.Equal(solidity.GasEstimate{
+		Transaction: 21000,
+		Calldata:    (4 + 9*32) * 16,
+		ECAdd:       150,
+		ECMul:       6000,
+		Pairing:     45000 + 4*34000,
+	}, estimate)
+	assert.EqualValues(212822, estimate.Total())
+
+	sccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(sccs)
+	assert.NoError(err)
+	_, plonkVK, err := plonk.Setup(sccs, srs)
+	assert.NoError(err)
+
+	plonkEstimate, err := plonkVK.EstimateGas()
+	assert.NoError(err)
+	assert.EqualValues(19*6000, plonkEstimate.ECMul)
+	assert.EqualValues(45000+2*34000, plonkEstimate.Pairing)
+	assert.NotZero(plonkEstimate.Hashing)
+	assert.NotZero(plonkEstimate.ModExp)
+	assert.Greater(plonkEstimate.Total(), estimate.Total())
+
+	// the options the verifiers can't be exported with
+	_, err = plonkVK.EstimateGas(solidity.WithLibrary())
+	assert.Error(err)
+}
@@ -0,0 +1,267 @@
+// Package solidity provides the options of the Solidity verifiers exported
+// with the ExportSolidity method of the verifying keys.
+package solidity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// ExportOption defines option for altering the Solidity verifier generated
+// by ExportSolidity.
+type ExportOption func(*ExportConfig) error
+
+// ExportConfig is the configuration of the Solidity code generation, with the
+// options applied.
+type ExportConfig struct {
+	// PragmaVersion is the version constraint of the pragma solidity
+	// directive.
+	PragmaVersion string
+
+	// ContractName is the name of the verifier contract (or library). If
+	// empty, the default name of the backend is used.
+	ContractName string
+
+	// Library outputs the verifier as a library instead of a contract.
+	Library bool
+
+	// CustomErrors replaces the require statements with custom errors.
+	CustomErrors bool
+
+	// IVerifier adds the IVerifier interface, implemented by the verifier.
+	IVerifier bool
+
+	// PublicInputsHasher computes the single public input of the verifying key
+	// from the NbHashedInputs public inputs of the verifier function, see
+	// WithPublicInputsHashing.
+	PublicInputsHasher PublicInputsHasher
+	NbHashedInputs     int
+
+	usedErrors []string // custom errors used in the generated code
+}
+
+// NewExportConfig returns a default ExportConfig with the given options opts
+// applied.
+func NewExportConfig(opts ...ExportOption) (ExportConfig, error) {
+	cfg := ExportConfig{
+		PragmaVersion: "^0.8.0",
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return ExportConfig{}, err
+		}
+	}
+	if cfg.Library && cfg.IVerifier {
+		return ExportConfig{}, errors.New("a library can't implement the IVerifier interface")
+	}
+	return cfg, nil
+}
+
+var identifier = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+
+// WithPragmaVersion sets the version constraint of the pragma solidity
+// directive, for example "0.8.19" or ">=0.8.4 <0.9.0". The default is
+// "^0.8.0"; custom errors require at least 0.8.4.
+func WithPragmaVersion(version string) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if version == "" || strings.ContainsAny(version, ";\n") {
+			return fmt.Errorf("invalid pragma version %q", version)
+		}
+		cfg.PragmaVersion = version
+		return nil
+	}
+}
+
+// WithContractName sets the name of the verifier contract.
+func WithContractName(name string) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if !identifier.MatchString(name) {
+			return fmt.Errorf("invalid contract name %q", name)
+		}
+		cfg.ContractName = name
+		return nil
+	}
+}
+
+// WithLibrary outputs the verifier as a library, whose functions can be
+// linked to the contracts using it, instead of a contract.
+func WithLibrary() ExportOption {
+	return func(cfg *ExportConfig) error {
+		cfg.Library = true
+		return nil
+	}
+}
+
+// WithCustomErrors uses custom errors (revert Error()) instead of require
+// statements with revert strings, which reduces the deployment and revert
+// costs.
+func WithCustomErrors() ExportOption {
+	return func(cfg *ExportConfig) error {
+		cfg.CustomErrors = true
+		return nil
+	}
+}
+
+// WithIVerifier adds the interface
+//
+//	interface IVerifier {
+//	    function verify(bytes calldata proof, uint256[] calldata publicInputs) external view returns (bool);
+//	}
+//
+// implemented by the verifier contract, so that verifiers of different
+// circuits and backends can be used interchangeably. The proof is the ABI
+// encoding of the proof arguments of the verifier function.
+func WithIVerifier() ExportOption {
+	return func(cfg *ExportConfig) error {
+		cfg.IVerifier = true
+		return nil
+	}
+}
+
+// PublicInputsHasher is a hasher of the public inputs of the circuits compiled
+// with frontend.WithPublicInputsHashing, which the Solidity verifiers can
+// compute, for instance poseidon.PublicInputsHasher.
+type PublicInputsHasher interface {
+	// SolidityLibrary returns the name and the code of a Solidity library with
+	// the function
+	//
+	//	function hash(uint256[] memory inputs) internal pure returns (uint256)
+	//
+	// computing the hash of inputs as the circuit does. The inputs are checked
+	// to be in the field by the caller.
+	SolidityLibrary() (name, code string)
+}
+
+// WithPublicInputsHashing exports the verifier of a circuit compiled with
+// frontend.WithPublicInputsHashing(h): the verifier function takes the
+// nbPublicInputs public inputs of the circuit, and hashes them itself with the
+// Solidity library of h to the single public input of the verifying key. Hence
+// the callers are not trusted to compute the hash.
+func WithPublicInputsHashing(h PublicInputsHasher, nbPublicInputs int) ExportOption {
+	return func(cfg *ExportConfig) error {
+		if h == nil {
+			return errors.New("nil public inputs hasher")
+		}
+		if nbPublicInputs < 1 {
+			return fmt.Errorf("invalid number of hashed public inputs %d", nbPublicInputs)
+		}
+		cfg.PublicInputsHasher = h
+		cfg.NbHashedInputs = nbPublicInputs
+		return nil
+	}
+}
+
+// NbPublicInputs returns the number of public inputs of the verifier function
+// for a verifying key of nbPublicWitness public inputs: nbPublicWitness, or
+// the number of hashed public inputs with WithPublicInputsHashing, in which
+// case nbPublicWitness must be 1.
+func (cfg *ExportConfig) NbPublicInputs(nbPublicWitness int) (int, error) {
+	if cfg.PublicInputsHasher == nil {
+		return nbPublicWitness, nil
+	}
+	if nbPublicWitness != 1 {
+		return 0, fmt.Errorf("the verifying key of hashed public inputs must have a single public input, got %d", nbPublicWitness)
+	}
+	return cfg.NbHashedInputs, nil
+}
+
+// IVerifierInterface is the Solidity declaration of the IVerifier interface.
+const IVerifierInterface = `interface IVerifier {
+    function verify(bytes calldata proof, uint256[] calldata publicInputs) external view returns (bool);
+}`
+
+// TemplateFuncs returns the helpers applying the configuration to the
+// Solidity templates of the backends:
+//
+//   - pragma: the pragma version
+//   - contract: the declaration of the verifier, "contract Name" or "library Name"
+//     and the IVerifier inheritance, with the given default name
+//   - contractName: the name of the verifier contract, with the given default name
+//   - iverifier: whether the IVerifier interface is implemented
+//   - iverifierInterface: the declaration of the IVerifier interface
+//   - require: the check of a condition, with the name of the custom error and
+//     the revert string (if any) of the require statement
+//   - hasher: the name of the library hashing the public inputs, empty if they
+//     are not hashed (see WithPublicInputsHashing)
+//   - hasherLibrary: the code of the library hashing the public inputs
+//   - errors: the declarations of the custom errors used in the code, to be
+//     called at the end of the template
+func (cfg *ExportConfig) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pragma": func() string {
+			return cfg.PragmaVersion
+		},
+		"contractName": cfg.contractName,
+		"contract": func(defaultName string) string {
+			name := cfg.contractName(defaultName)
+			switch {
+			case cfg.Library:
+				return "library " + name
+			case cfg.IVerifier:
+				return "contract " + name + " is IVerifier"
+			default:
+				return "contract " + name
+			}
+		},
+		"iverifier": func() bool {
+			return cfg.IVerifier
+		},
+		"iverifierInterface": func() string {
+			return IVerifierInterface
+		},
+		"require": func(condition, errorName, message string) string {
+			if cfg.CustomErrors {
+				if !contains(cfg.usedErrors, errorName) {
+					cfg.usedErrors = append(cfg.usedErrors, errorName)
+				}
+				return fmt.Sprintf("if (!(%s)) revert %s();", condition, errorName)
+			}
+			if message == "" {
+				return fmt.Sprintf("require(%s);", condition)
+			}
+			return fmt.Sprintf("require(%s, %q);", condition, message)
+		},
+		"hasher": func() string {
+			if cfg.PublicInputsHasher == nil {
+				return ""
+			}
+			name, _ := cfg.PublicInputsHasher.SolidityLibrary()
+			return name
+		},
+		"hasherLibrary": func() string {
+			if cfg.PublicInputsHasher == nil {
+				return ""
+			}
+			_, code := cfg.PublicInputsHasher.SolidityLibrary()
+			return code
+		},
+		"errors": func() string {
+			var sbb strings.Builder
+			for _, name := range cfg.usedErrors {
+				sbb.WriteString("error ")
+				sbb.WriteString(name)
+				sbb.WriteString("();\n")
+			}
+			return sbb.String()
+		},
+	}
+}
+
+func (cfg *ExportConfig) contractName(defaultName string) string {
+	if cfg.ContractName == "" {
+		return defaultName
+	}
+	return cfg.ContractName
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
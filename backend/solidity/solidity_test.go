@@ -0,0 +1,205 @@
+package solidity_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type exportCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *exportCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestNewExportConfig(t *testing.T) {
+	assert := require.New(t)
+
+	cfg, err := solidity.NewExportConfig()
+	assert.NoError(err)
+	assert.Equal("^0.8.0", cfg.PragmaVersion)
+
+	_, err = solidity.NewExportConfig(solidity.WithContractName("1Verifier"))
+	assert.Error(err)
+	_, err = solidity.NewExportConfig(solidity.WithPragmaVersion("0.8.19;"))
+	assert.Error(err)
+	_, err = solidity.NewExportConfig(solidity.WithLibrary(), solidity.WithIVerifier())
+	assert.Error(err)
+}
+
+func TestExportGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&buf))
+	assert.Contains(buf.String(), "pragma solidity ^0.8.0;")
+	assert.Contains(buf.String(), "contract Verifier {")
+	assert.Contains(buf.String(), `require(success, "pairing-add-failed");`)
+	assert.NotContains(buf.String(), "IVerifier")
+
+	buf.Reset()
+	assert.NoError(vk.ExportSolidity(&buf,
+		solidity.WithPragmaVersion("0.8.19"),
+		solidity.WithContractName("MyVerifier"),
+		solidity.WithCustomErrors(),
+		solidity.WithIVerifier(),
+	))
+	assert.Contains(buf.String(), "pragma solidity 0.8.19;")
+	assert.Contains(buf.String(), solidity.IVerifierInterface)
+	assert.Contains(buf.String(), "contract MyVerifier is IVerifier {")
+	assert.Contains(buf.String(), "revert ProofInvalid();")
+	assert.Contains(buf.String(), "error ProofInvalid();")
+	assert.Contains(buf.String(), "error PublicInputsLengthMismatch();")
+	assert.NotContains(buf.String(), "require(")
+
+	buf.Reset()
+	assert.NoError(vk.ExportSolidity(&buf, solidity.WithLibrary()))
+	assert.Contains(buf.String(), "library Verifier {")
+}
+
+func TestExportPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	_, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&buf,
+		solidity.WithContractName("MyVerifier"),
+		solidity.WithCustomErrors(),
+		solidity.WithIVerifier(),
+	))
+	assert.Contains(buf.String(), solidity.IVerifierInterface)
+	assert.Contains(buf.String(), "contract MyVerifier is PlonkVerifier, IVerifier {")
+	assert.Contains(buf.String(), "error PublicInputsLengthMismatch();")
+	assert.NotContains(buf.String(), "require(")
+
+	assert.Error(vk.ExportSolidity(&buf, solidity.WithLibrary()))
+}
+
+func TestDescriptor(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	s, err := frontend.NewSchema(&exportCircuit{})
+	assert.NoError(err)
+	layout, err := witness.NewPublicLayout(s, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	d, err := groth16.NewSolidityDescriptor(vk, layout, solidity.WithContractName("MyVerifier"))
+	assert.NoError(err)
+	assert.Equal("MyVerifier", d.Contract)
+	assert.Equal("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[1])", d.Function)
+	assert.Equal(3, d.Argument)
+	assert.Equal(ecc.BN254.ScalarField().String(), d.Modulus)
+	assert.Equal([]solidity.DescriptorInput{{Name: "Y", Index: 0, Type: "uint256"}}, d.Inputs)
+
+	var buf bytes.Buffer
+	assert.NoError(d.WriteJSON(&buf))
+	var decoded solidity.Descriptor
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(*d, decoded)
+
+	buf.Reset()
+	assert.NoError(d.WriteTypeScript(&buf))
+	assert.Contains(buf.String(), `contract: "MyVerifier",`)
+	assert.Contains(buf.String(), `  "Y": bigint; // uint256, index 0`)
+	assert.Contains(buf.String(), `    inputs["Y"],`)
+	assert.Contains(buf.String(), "export const SNARK_SCALAR_FIELD = "+d.Modulus+"n;")
+
+	// the layout must match the verifying key
+	layout.Inputs = append(layout.Inputs, witness.PublicInput{Name: "Z", Index: 1})
+	_, err = groth16.NewSolidityDescriptor(vk, layout)
+	assert.Error(err)
+
+	ccs, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &exportCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	_, plonkVK, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	layout.Inputs = layout.Inputs[:1]
+	d, err = plonk.NewSolidityDescriptor(plonkVK, layout)
+	assert.NoError(err)
+	assert.Equal("KeyedPlonkVerifier", d.Contract)
+	assert.Equal("verify_serialized_proof(uint256[],uint256[])", d.Function)
+	assert.Equal(0, d.Argument)
+}
+
+type hashedExportCircuit struct {
+	X   [3]frontend.Variable `gnark:",public"`
+	Sum frontend.Variable    `gnark:",public"`
+}
+
+func (c *hashedExportCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.X[0], c.X[1], c.X[2]), c.Sum)
+	return nil
+}
+
+func TestExportPublicInputsHashing(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	opt := solidity.WithPublicInputsHashing(poseidon.PublicInputsHasher, 4)
+
+	_, err := solidity.NewExportConfig(solidity.WithPublicInputsHashing(poseidon.PublicInputsHasher, 0))
+	assert.Error(err)
+
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &hashedExportCircuit{}, frontend.WithPublicInputsHashing(poseidon.PublicInputsHasher))
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&buf, opt, solidity.WithIVerifier()))
+	assert.Contains(buf.String(), "library PoseidonSponge {")
+	assert.Contains(buf.String(), "uint256[4] calldata input")
+	assert.Contains(buf.String(), "mul_input[2] = hashPublicInputs(input);")
+	assert.Contains(buf.String(), `require(publicInputs.length == 4, "verifier-bad-input-length");`)
+
+	ccs, err = frontend.Compile(field, scs.NewBuilder, &hashedExportCircuit{}, frontend.WithPublicInputsHashing(poseidon.PublicInputsHasher))
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	_, plonkVK, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	buf.Reset()
+	assert.NoError(plonkVK.ExportSolidity(&buf, opt))
+	assert.Contains(buf.String(), "library PoseidonSponge {")
+	assert.Contains(buf.String(), "hashed_inputs[0] = PoseidonSponge.hash(public_inputs);")
+
+	// the verifying key must have a single public input
+	ccs, err = frontend.Compile(field, r1cs.NewBuilder, &hashedExportCircuit{})
+	assert.NoError(err)
+	_, vk, err = groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.Error(vk.ExportSolidity(&buf, opt))
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+)
+
+// TuningProfile holds the number of tasks (goroutines) the provers split their
+// multi-scalar multiplications (MSM) and fast Fourier transforms (FFT) into.
+//
+// The window size and the chunking of an MSM are derived by gnark-crypto from its
+// number of points and tasks, so the number of tasks tunes all three.
+type TuningProfile struct {
+	NbCPU      int `json:"nbCPU"`      // number of CPUs of the host the profile was measured on
+	MSMG1Tasks int `json:"msmG1Tasks"` // number of tasks of the MSMs in G1
+	MSMG2Tasks int `json:"msmG2Tasks"` // number of tasks of the MSMs in G2
+	FFTTasks   int `json:"fftTasks"`   // number of tasks of the FFTs
+}
+
+var tuningProfile = DefaultTuningProfile()
+
+// DefaultTuningProfile returns the profile used by the provers when Calibrate wasn't
+// run, derived from the number of CPUs only.
+func DefaultTuningProfile() TuningProfile {
+	n := runtime.NumCPU()
+	nG2 := n
+	if nG2 <= 16 {
+		// if we don't have a lot of CPUs, this may artificially split the MSM
+		nG2 *= 2
+	}
+	return TuningProfile{NbCPU: n, MSMG1Tasks: n, MSMG2Tasks: nG2, FFTTasks: n}
+}
+
+// SetTuningProfile sets the profile used by the provers, unless another one is given
+// with WithTuningProfile.
+func SetTuningProfile(p TuningProfile) error {
+	if err := p.check(); err != nil {
+		return err
+	}
+	tuningProfile = p
+	return nil
+}
+
+// GetTuningProfile returns the profile used by the provers.
+func GetTuningProfile() TuningProfile {
+	return tuningProfile
+}
+
+// WithTuningProfile sets the profile used by the prover for this proof.
+func WithTuningProfile(p TuningProfile) ProverOption {
+	return func(opt *ProverConfig) error {
+		if err := p.check(); err != nil {
+			return err
+		}
+		opt.Tuning = p
+		return nil
+	}
+}
+
+// Save writes the profile to the file at path, in JSON.
+func (p TuningProfile) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadTuningProfile reads a profile written by TuningProfile.Save and sets it as
+// the profile used by the provers. It fails if the profile was measured on a host
+// with another number of CPUs.
+func LoadTuningProfile(path string) (TuningProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TuningProfile{}, err
+	}
+	var p TuningProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return TuningProfile{}, err
+	}
+	if p.NbCPU != runtime.NumCPU() {
+		return TuningProfile{}, fmt.Errorf("tuning profile measured on a host with %d CPUs, this one has %d", p.NbCPU, runtime.NumCPU())
+	}
+	return p, SetTuningProfile(p)
+}
+
+func (p TuningProfile) check() error {
+	if p.MSMG1Tasks < 1 || p.MSMG2Tasks < 1 || p.FFTTasks < 1 {
+		return errors.New("invalid tuning profile: the number of tasks must be positive")
+	}
+	if p.MSMG1Tasks > maxTasks || p.MSMG2Tasks > maxTasks || p.FFTTasks > maxTasks {
+		return fmt.Errorf("invalid tuning profile: the number of tasks must be at most %d", maxTasks)
+	}
+	return nil
+}
+
+// maxTasks is the maximum number of tasks supported by both the MSMs and the FFTs
+const maxTasks = 512
+
+// CalibrateOption defines option for altering the behavior of Calibrate.
+type CalibrateOption func(*CalibrateConfig) error
+
+// CalibrateConfig is the configuration of Calibrate with the options applied.
+type CalibrateConfig struct {
+	Size   int // number of points of the MSMs and of elements of the FFTs
+	Rounds int // number of runs of each benchmark, the fastest one is kept
+}
+
+// WithCalibrationSize sets the size of the MSMs and FFTs benchmarked by Calibrate,
+// by default 2¹⁶. It should be close to the size of the circuits proven.
+func WithCalibrationSize(size int) CalibrateOption {
+	return func(cfg *CalibrateConfig) error {
+		if size < 2 {
+			return fmt.Errorf("invalid calibration size %d", size)
+		}
+		cfg.Size = size
+		return nil
+	}
+}
+
+// WithCalibrationRounds sets the number of runs of each benchmark, by default 3.
+func WithCalibrationRounds(rounds int) CalibrateOption {
+	return func(cfg *CalibrateConfig) error {
+		if rounds < 1 {
+			return fmt.Errorf("invalid number of calibration rounds %d", rounds)
+		}
+		cfg.Rounds = rounds
+		return nil
+	}
+}
+
+// Calibrate benchmarks the MSMs and FFTs of the provers on the host machine with
+// several numbers of tasks, and sets the fastest ones as the profile used by the
+// subsequent Prove calls. The profile can be saved with TuningProfile.Save and
+// loaded in the next runs with LoadTuningProfile.
+//
+// The default heuristics (see DefaultTuningProfile) are suboptimal on ARM servers and
+// on machines with many cores. The benchmarks run on BN254, whose timings are
+// representative of the other curves.
+func Calibrate(opts ...CalibrateOption) (TuningProfile, error) {
+	cfg := CalibrateConfig{Size: 1 << 16, Rounds: 3}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return TuningProfile{}, err
+		}
+	}
+
+	scalars := make(fr.Vector, cfg.Size)
+	for i := range scalars {
+		if _, err := scalars[i].SetRandom(); err != nil {
+			return TuningProfile{}, err
+		}
+	}
+	_, _, g1, g2 := curve.Generators()
+	pointsG1 := curve.BatchScalarMultiplicationG1(&g1, scalars)
+	pointsG2 := curve.BatchScalarMultiplicationG2(&g2, scalars)
+	domain := fft.NewDomain(uint64(cfg.Size))
+	evaluations := make(fr.Vector, domain.Cardinality)
+
+	var err error
+	fastest := func(run func(nbTasks int)) int {
+		best, bestTime := 1, time.Duration(-1)
+		for _, nbTasks := range calibrationCandidates() {
+			for i := 0; i < cfg.Rounds; i++ {
+				start := time.Now()
+				run(nbTasks)
+				if took := time.Since(start); bestTime < 0 || took < bestTime {
+					best, bestTime = nbTasks, took
+				}
+			}
+		}
+		return best
+	}
+
+	p := TuningProfile{NbCPU: runtime.NumCPU()}
+	p.MSMG1Tasks = fastest(func(nbTasks int) {
+		var res curve.G1Jac
+		if _, e := res.MultiExp(pointsG1, scalars, ecc.MultiExpConfig{NbTasks: nbTasks}); e != nil {
+			err = e
+		}
+	})
+	p.MSMG2Tasks = fastest(func(nbTasks int) {
+		var res curve.G2Jac
+		if _, e := res.MultiExp(pointsG2, scalars, ecc.MultiExpConfig{NbTasks: nbTasks}); e != nil {
+			err = e
+		}
+	})
+	p.FFTTasks = fastest(func(nbTasks int) {
+		copy(evaluations, scalars)
+		domain.FFT(evaluations, fft.DIF, fft.WithNbTasks(nbTasks))
+	})
+	if err != nil {
+		return TuningProfile{}, err
+	}
+
+	return p, SetTuningProfile(p)
+}
+
+// calibrationCandidates returns the numbers of tasks benchmarked by Calibrate: the
+// powers of two up to 4 times the number of CPUs, and the number of CPUs.
+func calibrationCandidates() []int {
+	nbCPU := runtime.NumCPU()
+	if nbCPU > maxTasks {
+		nbCPU = maxTasks
+	}
+	candidates := []int{nbCPU}
+	for n := 1; n <= 4*nbCPU && n <= maxTasks; n *= 2 {
+		if n != nbCPU {
+			candidates = append(candidates, n)
+		}
+	}
+	sort.Ints(candidates)
+	return candidates
+}
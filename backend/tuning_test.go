@@ -0,0 +1,48 @@
+package backend_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalibrate(t *testing.T) {
+	assert := require.New(t)
+	defer func() {
+		assert.NoError(backend.SetTuningProfile(backend.DefaultTuningProfile()))
+	}()
+
+	p, err := backend.Calibrate(backend.WithCalibrationSize(1<<8), backend.WithCalibrationRounds(1))
+	assert.NoError(err)
+	assert.Positive(p.MSMG1Tasks)
+	assert.Positive(p.MSMG2Tasks)
+	assert.Positive(p.FFTTasks)
+	assert.Equal(p, backend.GetTuningProfile())
+
+	// the profile is consumed by the provers
+	opt, err := backend.NewProverConfig()
+	assert.NoError(err)
+	assert.Equal(p, opt.Tuning)
+
+	// and persisted for the next runs
+	path := filepath.Join(t.TempDir(), "tuning.json")
+	assert.NoError(p.Save(path))
+	assert.NoError(backend.SetTuningProfile(backend.DefaultTuningProfile()))
+	loaded, err := backend.LoadTuningProfile(path)
+	assert.NoError(err)
+	assert.Equal(p, loaded)
+	assert.Equal(p, backend.GetTuningProfile())
+
+	// WithTuningProfile overrides it for a proof
+	custom := backend.TuningProfile{NbCPU: p.NbCPU, MSMG1Tasks: 1, MSMG2Tasks: 1, FFTTasks: 1}
+	opt, err = backend.NewProverConfig(backend.WithTuningProfile(custom))
+	assert.NoError(err)
+	assert.Equal(custom, opt.Tuning)
+
+	_, err = backend.NewProverConfig(backend.WithTuningProfile(backend.TuningProfile{}))
+	assert.Error(err)
+	_, err = backend.Calibrate(backend.WithCalibrationSize(0))
+	assert.Error(err)
+}
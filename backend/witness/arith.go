@@ -0,0 +1,210 @@
+package witness
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/tinyfield"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Scale returns the witness r·w, computed in the field of w.
+func Scale(w Witness, r *big.Int) (Witness, error) {
+	return combine(w, nil, r)
+}
+
+// LinearCombination returns the witness w1 + r·w2, computed in the field of the
+// witnesses. They must be over the same field, and have the same numbers of
+// public and secret elements.
+//
+// It is the folding of two witnesses of a relaxed relation with the challenge
+// r, and the blinding of a witness w1 with a random multiple of w2.
+func LinearCombination(w1, w2 Witness, r *big.Int) (Witness, error) {
+	i1, err := Inspect(w1)
+	if err != nil {
+		return nil, err
+	}
+	i2, err := Inspect(w2)
+	if err != nil {
+		return nil, err
+	}
+	if i1.Field().Cmp(i2.Field()) != 0 {
+		return nil, fmt.Errorf("%w: the witnesses are over different fields", ErrInvalidWitness)
+	}
+	if i1.NbPublic() != i2.NbPublic() || i1.NbSecret() != i2.NbSecret() {
+		return nil, fmt.Errorf("%w: %d public and %d secret elements, and %d public and %d secret elements", ErrInvalidWitness, i1.NbPublic(), i1.NbSecret(), i2.NbPublic(), i2.NbSecret())
+	}
+	return combine(w1, w2, r)
+}
+
+// LinearCombinationStream reads the witnesses w1 and w2 written by WriteTo from
+// r1 and r2, and writes w1 + r·w2 to dst as WriteTo would, without holding
+// more than a chunk of the witnesses in memory. The witnesses must be over
+// field, and have the same numbers of public and secret elements.
+//
+// When it returns an error, dst may hold a partial witness.
+func LinearCombinationStream(dst io.Writer, r1, r2 io.Reader, field, r *big.Int) error {
+	v, err := newVector(field, 0)
+	if err != nil {
+		return err
+	}
+	header := gnarkio.NewHeader(gnarkio.Witness, curveID(v), backend.UNKNOWN)
+	_, err = gnarkio.ReadWithHeader(r1, header, func(r1 io.Reader) error {
+		_, err := gnarkio.ReadWithHeader(r2, header, func(r2 io.Reader) error {
+			_, err := gnarkio.WriteWithHeader(dst, header, func(dst io.Writer) error {
+				return combineStream(v, dst, r1, r2, r)
+			})
+			return err
+		})
+		return err
+	})
+	return err
+}
+
+// combineStream reads the payloads of two witnesses written by writeTo, and
+// writes the payload of their linear combination.
+func combineStream(v any, dst io.Writer, r1, r2 io.Reader, r *big.Int) error {
+	// number of public, number of secret and length of the vector
+	var buf1, buf2 [12]byte
+	if _, err := io.ReadFull(r1, buf1[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r2, buf2[:]); err != nil {
+		return err
+	}
+	if buf1 != buf2 {
+		return fmt.Errorf("%w: the witnesses have different numbers of elements", ErrInvalidWitness)
+	}
+	nbPublic, nbSecret := binary.BigEndian.Uint32(buf1[:4]), binary.BigEndian.Uint32(buf1[4:8])
+	if vecLen := binary.BigEndian.Uint32(buf1[8:]); uint64(vecLen) != uint64(nbPublic)+uint64(nbSecret) {
+		return fmt.Errorf("%w: vector of length %d, expected %d public and %d secret elements", ErrInvalidWitness, vecLen, nbPublic, nbSecret)
+	}
+	if _, err := dst.Write(buf1[:]); err != nil {
+		return err
+	}
+
+	for remaining := int(nbPublic) + int(nbSecret); remaining > 0; remaining -= readChunkSize {
+		size := remaining
+		if size > readChunkSize {
+			size = readChunkSize
+		}
+		var err error
+		switch v.(type) {
+		case fr_bn254.Vector:
+			err = combineChunk[fr_bn254.Element, fr_bn254.Vector](dst, r1, r2, size, r)
+		case fr_bls12377.Vector:
+			err = combineChunk[fr_bls12377.Element, fr_bls12377.Vector](dst, r1, r2, size, r)
+		case fr_bls12381.Vector:
+			err = combineChunk[fr_bls12381.Element, fr_bls12381.Vector](dst, r1, r2, size, r)
+		case fr_bls24317.Vector:
+			err = combineChunk[fr_bls24317.Element, fr_bls24317.Vector](dst, r1, r2, size, r)
+		case fr_bls24315.Vector:
+			err = combineChunk[fr_bls24315.Element, fr_bls24315.Vector](dst, r1, r2, size, r)
+		case tinyfield.Vector:
+			err = combineChunk[tinyfield.Element, tinyfield.Vector](dst, r1, r2, size, r)
+		default:
+			panic("invalid input")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// combineChunk reads size elements from r1 and r2, and writes the elements of
+// their linear combination to dst.
+func combineChunk[E any, V ~[]E, PE fieldElement[E], PV interface {
+	*V
+	io.ReaderFrom
+}](dst io.Writer, r1, r2 io.Reader, size int, r *big.Int) error {
+	v1, _, err := readVector[E, V, PV](r1, size)
+	if err != nil {
+		return err
+	}
+	v2, _, err := readVector[E, V, PV](r2, size)
+	if err != nil {
+		return err
+	}
+	for _, e := range linearCombination[E, V, PE](v1, v2, r) {
+		if _, err := dst.Write(PE(&e).Marshal()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// combine returns the witness w1 + r·w2, or r·w1 if w2 is nil.
+func combine(w1, w2 Witness, r *big.Int) (Witness, error) {
+	i1, err := Inspect(w1)
+	if err != nil {
+		return nil, err
+	}
+	var v2 any
+	if w2 != nil {
+		v2 = w2.Vector()
+	}
+	var res any
+	switch v1 := w1.Vector().(type) {
+	case fr_bn254.Vector:
+		res = linearCombination[fr_bn254.Element](v1, asVector[fr_bn254.Vector](v2), r)
+	case fr_bls12377.Vector:
+		res = linearCombination[fr_bls12377.Element](v1, asVector[fr_bls12377.Vector](v2), r)
+	case fr_bls12381.Vector:
+		res = linearCombination[fr_bls12381.Element](v1, asVector[fr_bls12381.Vector](v2), r)
+	case fr_bls24317.Vector:
+		res = linearCombination[fr_bls24317.Element](v1, asVector[fr_bls24317.Vector](v2), r)
+	case fr_bls24315.Vector:
+		res = linearCombination[fr_bls24315.Element](v1, asVector[fr_bls24315.Vector](v2), r)
+	case tinyfield.Vector:
+		res = linearCombination[tinyfield.Element](v1, asVector[tinyfield.Vector](v2), r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported vector type %T", ErrInvalidWitness, v1)
+	}
+	return &witness{
+		vector:   res,
+		nbPublic: uint32(i1.NbPublic()),
+		nbSecret: uint32(i1.NbSecret()),
+	}, nil
+}
+
+// asVector returns v as a V, or nil if v is nil.
+func asVector[V any](v any) V {
+	var res V
+	if v != nil {
+		res = v.(V)
+	}
+	return res
+}
+
+type fieldElement[E any] interface {
+	*E
+	Add(x, y *E) *E
+	Mul(x, y *E) *E
+	SetBigInt(v *big.Int) *E
+	Marshal() []byte
+}
+
+// linearCombination returns v1 + r·v2, or r·v1 if v2 is nil.
+func linearCombination[E any, V ~[]E, PE fieldElement[E]](v1, v2 V, r *big.Int) V {
+	var re E
+	PE(&re).SetBigInt(r)
+	res := make(V, len(v1))
+	for i := range v1 {
+		if v2 == nil {
+			PE(&res[i]).Mul(&v1[i], &re)
+		} else {
+			PE(&res[i]).Mul(&v2[i], &re)
+			PE(&res[i]).Add(&res[i], &v1[i])
+		}
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+package witness_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+func elements(w witness.Witness) []string {
+	var res []string
+	w.(witness.Inspector).Iterate(func(_ int, v *big.Int) bool {
+		res = append(res, v.String())
+		return true
+	})
+	return res
+}
+
+func TestLinearCombination(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	minusOne := new(big.Int).Sub(field, big.NewInt(1))
+
+	a1 := &layoutCircuit{A: 6, X: 3}
+	a1.Inner.B = 2
+	w1, err := frontend.NewWitness(a1, field)
+	assert.NoError(err)
+	a2 := &layoutCircuit{A: 1, X: minusOne}
+	a2.Inner.B = 0
+	w2, err := frontend.NewWitness(a2, field)
+	assert.NoError(err)
+
+	r := big.NewInt(5)
+	res, err := witness.LinearCombination(w1, w2, r)
+	assert.NoError(err)
+	ins, err := witness.Inspect(res)
+	assert.NoError(err)
+	assert.Equal(2, ins.NbPublic())
+	assert.Equal(1, ins.NbSecret())
+	assert.Equal([]string{"11", "2", new(big.Int).Sub(field, big.NewInt(2)).String()}, elements(res))
+
+	scaled, err := witness.Scale(w2, r)
+	assert.NoError(err)
+	assert.Equal([]string{"5", "0", new(big.Int).Sub(field, big.NewInt(5)).String()}, elements(scaled))
+
+	// the streamed combination is the same
+	var b1, b2, out bytes.Buffer
+	_, err = w1.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = w2.WriteTo(&b2)
+	assert.NoError(err)
+	assert.NoError(witness.LinearCombinationStream(&out, &b1, &b2, field, r))
+	expected, err := res.MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(expected, out.Bytes())
+
+	// the witnesses must have the same shape
+	public, err := w2.Public()
+	assert.NoError(err)
+	_, err = witness.LinearCombination(w1, public, r)
+	assert.ErrorIs(err, witness.ErrInvalidWitness)
+	b1.Reset()
+	b2.Reset()
+	_, err = w1.WriteTo(&b1)
+	assert.NoError(err)
+	_, err = public.WriteTo(&b2)
+	assert.NoError(err)
+	assert.ErrorIs(witness.LinearCombinationStream(&out, &b1, &b2, field, r), witness.ErrInvalidWitness)
+
+	other, err := frontend.NewWitness(a1, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+	_, err = witness.LinearCombination(w1, other, r)
+	assert.ErrorIs(err, witness.ErrInvalidWitness)
+}
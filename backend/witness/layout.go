@@ -0,0 +1,159 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// PublicLayout is the canonical layout of the public inputs of a circuit: their
+// order in the public witness, which is the order expected by Verify and by the
+// exported Solidity verifiers, and their encoding as big-endian field elements of
+// ElementSize bytes.
+//
+// It is meant to be shared, in JSON, with the callers of the verifiers written in
+// other languages, and to validate the public witnesses they send.
+type PublicLayout struct {
+	Inputs      []PublicInput `json:"inputs"`
+	ElementSize int           `json:"elementSize"` // size in bytes of an encoded input
+}
+
+// PublicInput is a public input of a circuit, see PublicLayout.
+type PublicInput struct {
+	Name   string `json:"name"`   // full name of the field of the circuit, as in the constraint system
+	Index  int    `json:"index"`  // index in the public witness
+	Offset int    `json:"offset"` // offset in bytes of the encoding of the input in the concatenation of the inputs
+}
+
+// NewPublicLayout returns the layout of the public inputs of a circuit with schema
+// s, compiled over field.
+func NewPublicLayout(s *schema.Schema, field *big.Int) (*PublicLayout, error) {
+	l := &PublicLayout{
+		Inputs:      make([]PublicInput, 0, s.NbPublic),
+		ElementSize: (field.BitLen() + 7) / 8,
+	}
+
+	names, err := leafNames(s, schema.Public)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		l.Inputs = append(l.Inputs, PublicInput{
+			Name:   name,
+			Index:  i,
+			Offset: i * l.ElementSize,
+		})
+	}
+	if len(l.Inputs) != s.NbPublic {
+		return nil, fmt.Errorf("schema has %d public inputs, found %d", s.NbPublic, len(l.Inputs))
+	}
+	return l, nil
+}
+
+// CheckVerifyingKey returns an error if vk doesn't expect the public inputs of l:
+// their number, and their names in order if vk records them (see
+// groth16.VerifyingKey.PublicInputNames).
+func (l *PublicLayout) CheckVerifyingKey(vk interface{ NbPublicWitness() int }) error {
+	if n := vk.NbPublicWitness(); n != len(l.Inputs) {
+		return fmt.Errorf("the verifying key expects %d public inputs, the layout has %d", n, len(l.Inputs))
+	}
+	named, ok := vk.(interface{ PublicInputNames() []string })
+	if !ok || named.PublicInputNames() == nil {
+		return nil
+	}
+	for i, name := range named.PublicInputNames() {
+		if name != l.Inputs[i].Name {
+			return fmt.Errorf("public input %d is %q in the verifying key, %q in the layout", i, name, l.Inputs[i].Name)
+		}
+	}
+	return nil
+}
+
+// Check returns an error if publicWitness doesn't follow l: if it has secret
+// inputs or another number of public inputs, or if one of the values, indexed by
+// the names of the inputs, isn't at the index of its input.
+func (l *PublicLayout) Check(publicWitness Witness, values map[string]interface{}) error {
+	w, ok := publicWitness.(*witness)
+	if !ok {
+		return ErrInvalidWitness
+	}
+	if w.nbSecret != 0 {
+		return fmt.Errorf("%w: the public witness has %d secret inputs", ErrInvalidWitness, w.nbSecret)
+	}
+	if int(w.nbPublic) != len(l.Inputs) {
+		return fmt.Errorf("%w: %d public inputs, the layout has %d", ErrInvalidWitness, w.nbPublic, len(l.Inputs))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	actual := bigInts(w.vector)
+	nbFound := 0
+	for _, input := range l.Inputs {
+		value, ok := values[input.Name]
+		if !ok {
+			continue
+		}
+		nbFound++
+		expected, err := element(w.vector, value)
+		if err != nil {
+			return fmt.Errorf("public input %q: %w", input.Name, err)
+		}
+		if actual[input.Index].Cmp(expected) == 0 {
+			continue
+		}
+		for j := range actual {
+			if actual[j].Cmp(expected) == 0 {
+				return fmt.Errorf("%w: public input %q is at index %d, expected at index %d", ErrInvalidWitness, input.Name, j, input.Index)
+			}
+		}
+		return fmt.Errorf("%w: public input %q at index %d is %s, expected %s", ErrInvalidWitness, input.Name, input.Index, actual[input.Index], expected)
+	}
+	if nbFound != len(values) {
+		var unknown []string
+		for name := range values {
+			if !l.has(name) {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown public inputs %q", unknown)
+	}
+	return nil
+}
+
+func (l *PublicLayout) has(name string) bool {
+	for _, input := range l.Inputs {
+		if input.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// bigInts returns the elements of the vector v as big integers
+func bigInts(v any) []*big.Int {
+	var res []*big.Int
+	for e := range iterate(v) {
+		res = append(res, e.(interface{ BigInt(*big.Int) *big.Int }).BigInt(new(big.Int)))
+	}
+	return res
+}
+
+// element returns value as an element of the field of the vector v
+func element(v any, value interface{}) (*big.Int, error) {
+	if value == nil {
+		return nil, errors.New("nil value")
+	}
+	e, err := newFrom(v, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := set(e, 0, value); err != nil {
+		return nil, err
+	}
+	return bigInts(e)[0], nil
+}
@@ -0,0 +1,93 @@
+package witness_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type layoutCircuit struct {
+	A     frontend.Variable `gnark:",public"`
+	X     frontend.Variable
+	Inner struct {
+		B frontend.Variable `gnark:",public"`
+	}
+}
+
+func (c *layoutCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.Inner.B), c.A)
+	return nil
+}
+
+// swappedLayoutCircuit declares the public inputs of layoutCircuit in another order
+type swappedLayoutCircuit struct {
+	Inner struct {
+		B frontend.Variable `gnark:",public"`
+	}
+	A frontend.Variable `gnark:",public"`
+	X frontend.Variable
+}
+
+func (c *swappedLayoutCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.Inner.B), c.A)
+	return nil
+}
+
+func TestPublicLayout(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	s, err := frontend.NewSchema(&layoutCircuit{})
+	assert.NoError(err)
+	layout, err := witness.NewPublicLayout(s, field)
+	assert.NoError(err)
+	assert.Equal(&witness.PublicLayout{
+		Inputs: []witness.PublicInput{
+			{Name: "A", Index: 0, Offset: 0},
+			{Name: "Inner_B", Index: 1, Offset: 32},
+		},
+		ElementSize: 32,
+	}, layout)
+
+	// the layout matches the verifying key of the circuit, not the one of the circuit
+	// with the inputs in another order
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &layoutCircuit{})
+	assert.NoError(err)
+	_, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.NoError(layout.CheckVerifyingKey(vk))
+
+	ccs, err = frontend.Compile(field, r1cs.NewBuilder, &swappedLayoutCircuit{})
+	assert.NoError(err)
+	_, vk, err = groth16.Setup(ccs)
+	assert.NoError(err)
+	assert.Error(layout.CheckVerifyingKey(vk))
+
+	// public witnesses
+	assignment := &layoutCircuit{A: 6}
+	assignment.Inner.B = 2
+	w, err := frontend.NewWitness(assignment, field, frontend.PublicOnly())
+	assert.NoError(err)
+	values := map[string]interface{}{"A": 6, "Inner_B": 2}
+	assert.NoError(layout.Check(w, values))
+	assert.NoError(layout.Check(w, nil))
+
+	swapped := &swappedLayoutCircuit{A: 6}
+	swapped.Inner.B = 2
+	w, err = frontend.NewWitness(swapped, field, frontend.PublicOnly())
+	assert.NoError(err)
+	assert.ErrorIs(layout.Check(w, values), witness.ErrInvalidWitness)
+	assert.ErrorContains(layout.Check(w, values), `"A" is at index 1, expected at index 0`)
+
+	assert.ErrorContains(layout.Check(w, map[string]interface{}{"C": 1}), "unknown public inputs")
+
+	assignment.X = 3
+	w, err = frontend.NewWitness(assignment, field)
+	assert.NoError(err)
+	assert.ErrorIs(layout.Check(w, nil), witness.ErrInvalidWitness, "secret inputs")
+}
@@ -0,0 +1,114 @@
+package witness
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/tinyfield"
+)
+
+func (w *witness) NbPublic() int {
+	return int(w.nbPublic)
+}
+
+func (w *witness) NbSecret() int {
+	return int(w.nbSecret)
+}
+
+func (w *witness) Field() *big.Int {
+	if id := curveID(w.vector); id != ecc.UNKNOWN {
+		return id.ScalarField()
+	}
+	return tinyfield.Modulus()
+}
+
+func (w *witness) Iterate(fn func(i int, v *big.Int) bool) {
+	i := 0
+	chValues := w.iterate()
+	for e := range chValues {
+		if !fn(i, e.(interface{ BigInt(*big.Int) *big.Int }).BigInt(new(big.Int))) {
+			// let the producer terminate
+			for range chValues {
+			}
+			return
+		}
+		i++
+	}
+}
+
+// Print writes the elements of the witness to wr, in the format
+//
+//	witness over bn254: 1 public, 2 secret
+//	public
+//	  Y = 35
+//	secret
+//	  X = 3
+//	  Z = 2
+//
+// The schema s of the circuit may be read from JSON, so that the witness can be
+// inspected without the code of the circuit.
+func (w *witness) Print(wr io.Writer, s *schema.Schema) error {
+	var names []string
+	if s != nil {
+		if s.NbPublic != int(w.nbPublic) || (w.nbSecret != 0 && w.nbSecret != uint32(s.NbSecret)) {
+			return fmt.Errorf("%w: schema has %d public and %d secret elements", ErrInvalidWitness, s.NbPublic, s.NbSecret)
+		}
+		var err error
+		if names, err = leafNames(s, schema.Public); err != nil {
+			return err
+		}
+		if w.nbSecret != 0 {
+			secret, err := leafNames(s, schema.Secret)
+			if err != nil {
+				return err
+			}
+			names = append(names, secret...)
+		}
+	}
+
+	field := "field " + w.Field().String()
+	if id := curveID(w.vector); id != ecc.UNKNOWN {
+		field = id.String()
+	}
+	if _, err := fmt.Fprintf(wr, "witness over %s: %d public, %d secret\n", field, w.nbPublic, w.nbSecret); err != nil {
+		return err
+	}
+	var err error
+	w.Iterate(func(i int, v *big.Int) bool {
+		if i == 0 && w.nbPublic != 0 {
+			_, err = fmt.Fprintln(wr, "public")
+		} else if i == int(w.nbPublic) {
+			_, err = fmt.Fprintln(wr, "secret")
+		}
+		if err != nil {
+			return false
+		}
+		name := fmt.Sprintf("[%d]", i)
+		if names != nil {
+			name = names[i]
+		}
+		_, err = fmt.Fprintf(wr, "  %s = %s\n", name, v)
+		return err == nil
+	})
+	return err
+}
+
+// leafNames returns the full names of the leaves of the schema s with the given
+// visibility, in order.
+func leafNames(s *schema.Schema, visibility schema.Visibility) ([]string, error) {
+	var names []string
+	tLeaf := reflect.TypeOf((*big.Int)(nil))
+	if _, err := schema.Walk(s.Instantiate(tLeaf), tLeaf, func(leaf schema.LeafInfo, _ reflect.Value) error {
+		if leaf.Visibility == visibility {
+			names = append(names, leaf.FullName())
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
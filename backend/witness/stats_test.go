@@ -0,0 +1,77 @@
+package witness_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWitnessStats(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	assignment := &layoutCircuit{A: 6, X: 3}
+	assignment.Inner.B = 2
+	w, err := frontend.NewWitness(assignment, field)
+	assert.NoError(err)
+	ins, err := witness.Inspect(w)
+	assert.NoError(err)
+	assert.Equal(2, ins.NbPublic())
+	assert.Equal(1, ins.NbSecret())
+	assert.Equal(0, field.Cmp(ins.Field()))
+
+	var values []int64
+	ins.Iterate(func(i int, v *big.Int) bool {
+		assert.Equal(len(values), i)
+		values = append(values, v.Int64())
+		return true
+	})
+	assert.Equal([]int64{6, 2, 3}, values)
+	values = values[:0]
+	ins.Iterate(func(i int, v *big.Int) bool {
+		values = append(values, v.Int64())
+		return false
+	})
+	assert.Equal([]int64{6}, values)
+
+	// the schema is read from JSON, without the circuit
+	s, err := frontend.NewSchema(&layoutCircuit{})
+	assert.NoError(err)
+	data, err := json.Marshal(s)
+	assert.NoError(err)
+	var decoded schema.Schema
+	assert.NoError(json.Unmarshal(data, &decoded))
+
+	var buf bytes.Buffer
+	assert.NoError(ins.Print(&buf, &decoded))
+	assert.Equal(`witness over bn254: 2 public, 1 secret
+public
+  A = 6
+  Inner_B = 2
+secret
+  X = 3
+`, buf.String())
+
+	public, err := w.Public()
+	assert.NoError(err)
+	pub, err := witness.Inspect(public)
+	assert.NoError(err)
+	assert.Equal(0, pub.NbSecret())
+	buf.Reset()
+	assert.NoError(pub.Print(&buf, nil))
+	assert.Equal(`witness over bn254: 2 public, 0 secret
+public
+  [0] = 6
+  [1] = 2
+`, buf.String())
+
+	s.NbPublic++
+	assert.Error(ins.Print(&buf, s))
+}
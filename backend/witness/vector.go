@@ -37,6 +37,25 @@ func newVector(field *big.Int, size int) (any, error) {
 	}
 }
 
+// curveID returns the curve whose scalar field is the field of v
+// (ecc.UNKNOWN for tinyfield)
+func curveID(v any) ecc.ID {
+	switch v.(type) {
+	case fr_bn254.Vector:
+		return ecc.BN254
+	case fr_bls12377.Vector:
+		return ecc.BLS12_377
+	case fr_bls12381.Vector:
+		return ecc.BLS12_381
+	case fr_bls24317.Vector:
+		return ecc.BLS24_317
+	case fr_bls24315.Vector:
+		return ecc.BLS24_315
+	default:
+		return ecc.UNKNOWN
+	}
+}
+
 func newFrom(from any, n int) (any, error) {
 	switch wt := from.(type) {
 	case fr_bn254.Vector:
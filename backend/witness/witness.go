@@ -16,9 +16,12 @@
 //
 // Binary protocol
 //
-//	Witness     ->  [uint32(nbPublic) | uint32(nbSecret) | fr.Vector(variables)]
+//	Witness     ->  [header | uint32(nbPublic) | uint32(nbSecret) | fr.Vector(variables) | checksum]
 //	fr.Vector is a *field element* vector encoded a big-endian byte array like so: [uint32(len(vector)) | elements]
 //
+// header and checksum are described in package github.com/consensys/gnark/io; witnesses
+// encoded without them are still accepted by ReadFrom.
+//
 // # Ordering
 //
 // First, `publicVariables`, then `secretVariables`. Each subset is ordered from the order of definition in the circuit structure.
@@ -30,7 +33,7 @@
 //	    Z frontend.Variable
 //	}
 //
-// A valid witness would be:
+// A valid witness (without header and checksum) would be:
 //   - `[uint32(1)|uint32(2)|uint32(3)|bytes(Y)|bytes(X)|bytes(Z)]`
 //   - Hex representation with values `Y = 35`, `X = 3`, `Z = 2`
 //     `000000010000000200000003000000000000000000000000000000000000000000000000000000000000002300000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000002`
@@ -52,9 +55,11 @@ import (
 	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/tinyfield"
+	gnarkio "github.com/consensys/gnark/io"
 )
 
 var ErrInvalidWitness = errors.New("invalid witness")
@@ -93,6 +98,45 @@ type Witness interface {
 	Fill(nbPublic, nbSecret int, values <-chan any) error
 }
 
+// Inspector reads the elements of a witness without knowing the type of its
+// vector. The witnesses returned by this package implement it; it is not part
+// of Witness so that other implementations of Witness don't have to, and
+// callers type-assert it:
+//
+//	if ins, ok := w.(witness.Inspector); ok {
+//		fmt.Println(ins.NbPublic(), ins.NbSecret())
+//	}
+type Inspector interface {
+	// NbPublic returns the number of public elements of the witness.
+	NbPublic() int
+
+	// NbSecret returns the number of secret elements of the witness, 0 for a
+	// public witness.
+	NbSecret() int
+
+	// Field returns the modulus of the field of the witness elements.
+	Field() *big.Int
+
+	// Iterate calls fn with the index and the value of the elements of the
+	// witness, public elements first, until fn returns false.
+	Iterate(fn func(i int, v *big.Int) bool)
+
+	// Print writes the elements of the witness to w, one per line, named after
+	// the leaves of the schema s of the circuit; s may be nil, the elements are
+	// then named after their index.
+	Print(w io.Writer, s *schema.Schema) error
+}
+
+// Inspect returns w as an Inspector, or an error wrapping ErrInvalidWitness if
+// w doesn't implement it.
+func Inspect(w Witness) (Inspector, error) {
+	ins, ok := w.(Inspector)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T doesn't implement witness.Inspector", ErrInvalidWitness, w)
+	}
+	return ins, nil
+}
+
 type witness struct {
 	vector             any
 	nbPublic, nbSecret uint32
@@ -157,7 +201,16 @@ func (w *witness) Public() (Witness, error) {
 	}, nil
 }
 
+// WriteTo writes the binary encoding of the witness to wr, framed by a gnarkio header
 func (w *witness) WriteTo(wr io.Writer) (n int64, err error) {
+	header := gnarkio.NewHeader(gnarkio.Witness, curveID(w.vector), backend.UNKNOWN)
+	return gnarkio.WriteWithHeader(wr, header, func(wr io.Writer) error {
+		_, err := w.writeTo(wr)
+		return err
+	})
+}
+
+func (w *witness) writeTo(wr io.Writer) (n int64, err error) {
 	// write number of public, number of secret
 	if err := binary.Write(wr, binary.BigEndian, w.nbPublic); err != nil {
 		return 0, err
@@ -190,7 +243,17 @@ func (w *witness) WriteTo(wr io.Writer) (n int64, err error) {
 	return n, err
 }
 
+// ReadFrom reads a witness written by WriteTo from r. The witness must be defined
+// over the same field (see New).
 func (w *witness) ReadFrom(r io.Reader) (n int64, err error) {
+	header := gnarkio.NewHeader(gnarkio.Witness, curveID(w.vector), backend.UNKNOWN)
+	return gnarkio.ReadWithHeader(r, header, func(r io.Reader) error {
+		_, err := w.readFrom(r)
+		return err
+	})
+}
+
+func (w *witness) readFrom(r io.Reader) (n int64, err error) {
 	var buf [4]byte
 	if read, err := io.ReadFull(r, buf[:]); err != nil {
 		return int64(read), err
@@ -201,26 +264,30 @@ func (w *witness) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 	w.nbSecret = binary.BigEndian.Uint32(buf[:4])
 
+	// the length of the vector must match, and is not trusted for allocations
+	if read, err := io.ReadFull(r, buf[:]); err != nil {
+		return int64(read) + 8, err
+	}
+	n = 12
+	if vecLen := binary.BigEndian.Uint32(buf[:4]); uint64(vecLen) != uint64(w.nbPublic)+uint64(w.nbSecret) {
+		return n, fmt.Errorf("%w: vector of length %d, expected %d public and %d secret elements", ErrInvalidWitness, vecLen, w.nbPublic, w.nbSecret)
+	}
+	nbElements := int(w.nbPublic) + int(w.nbSecret)
+
 	var m int64
-	switch t := w.vector.(type) {
+	switch w.vector.(type) {
 	case fr_bn254.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[fr_bn254.Element, fr_bn254.Vector](r, nbElements)
 	case fr_bls12377.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[fr_bls12377.Element, fr_bls12377.Vector](r, nbElements)
 	case fr_bls12381.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[fr_bls12381.Element, fr_bls12381.Vector](r, nbElements)
 	case fr_bls24317.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[fr_bls24317.Element, fr_bls24317.Vector](r, nbElements)
 	case fr_bls24315.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[fr_bls24315.Element, fr_bls24315.Vector](r, nbElements)
 	case tinyfield.Vector:
-		m, err = t.ReadFrom(r)
-		w.vector = t
+		w.vector, m, err = readVector[tinyfield.Element, tinyfield.Vector](r, nbElements)
 	default:
 		panic("invalid input")
 	}
@@ -229,6 +296,48 @@ func (w *witness) ReadFrom(r io.Reader) (n int64, err error) {
 	return n, err
 }
 
+// readChunkSize is the number of elements of the chunks of the vector read by
+// readVector.
+const readChunkSize = 1 << 12
+
+// readVector reads the nbElements elements of a vector whose length was already
+// read. It reads them by chunks, so that the memory allocated is bounded by the
+// size of the data actually read, not by the announced length.
+func readVector[E any, V ~[]E, PV interface {
+	*V
+	io.ReaderFrom
+}](r io.Reader, nbElements int) (V, int64, error) {
+	var (
+		chunk V
+		n     int64
+		buf   [4]byte
+	)
+	// an empty vector is read as an empty, non-nil vector, like Vector.ReadFrom does
+	capacity := nbElements
+	if capacity > readChunkSize {
+		capacity = readChunkSize
+	}
+	res := make(V, 0, capacity)
+	for len(res) < nbElements {
+		size := nbElements - len(res)
+		if size > readChunkSize {
+			size = readChunkSize
+		}
+		binary.BigEndian.PutUint32(buf[:], uint32(size))
+		m, err := PV(&chunk).ReadFrom(io.MultiReader(bytes.NewReader(buf[:]), r))
+		// m counts the length prefix, which is not read from r, unless ReadFrom
+		// failed before reading it all
+		if m > 4 {
+			n += m - 4
+		}
+		if err != nil {
+			return res, n, err
+		}
+		res = append(res, chunk...)
+	}
+	return res, n, nil
+}
+
 // MarshalBinary encodes the number of public, number of secret and the fr.Vector.
 func (w *witness) MarshalBinary() (data []byte, err error) {
 	var buf bytes.Buffer
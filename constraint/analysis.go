@@ -0,0 +1,175 @@
+package constraint
+
+import "sort"
+
+// FreeInput is a secret input which doesn't restrict the witnesses accepted by a
+// constraint system: any value of the input is accepted with the same values of
+// the other inputs. Such inputs are often soundness bugs, where a variable of the
+// circuit is assigned but never constrained.
+type FreeInput struct {
+	Name string
+
+	// Constraints are the IDs of the trivially satisfied constraints in which
+	// the input appears. It is empty if the input appears in no constraint.
+	Constraints []int
+}
+
+// inputRefs records the constraints in which the secret inputs appear.
+type inputRefs struct {
+	nbPublic    int
+	constrained []bool
+	trivial     [][]int
+}
+
+func newInputRefs(system *System) *inputRefs {
+	return &inputRefs{
+		nbPublic:    system.GetNbPublicVariables(),
+		constrained: make([]bool, system.GetNbSecretVariables()),
+		trivial:     make([][]int, system.GetNbSecretVariables()),
+	}
+}
+
+// add records that the wire of t appears in the constraint cID.
+func (r *inputRefs) add(t Term, cID int, trivial bool) {
+	sID := t.WireID() - r.nbPublic
+	if sID < 0 || sID >= len(r.constrained) || t.CoeffID() == CoeffIdZero {
+		return
+	}
+	if !trivial {
+		r.constrained[sID] = true
+		return
+	}
+	if refs := r.trivial[sID]; len(refs) == 0 || refs[len(refs)-1] != cID {
+		r.trivial[sID] = append(refs, cID)
+	}
+}
+
+func (r *inputRefs) freeInputs(secretNames []string) []FreeInput {
+	var res []FreeInput
+	for i, constrained := range r.constrained {
+		if !constrained {
+			res = append(res, FreeInput{Name: secretNames[i], Constraints: r.trivial[i]})
+		}
+	}
+	return res
+}
+
+// FreeSecretInputs implements ConstraintSystem.
+func (r1cs *R1CSCore) FreeSecretInputs() []FreeInput {
+	refs := newInputRefs(&r1cs.System)
+	for cID := range r1cs.Constraints {
+		c := &r1cs.Constraints[cID]
+		trivial := c.isTrivial()
+		for _, l := range []LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				refs.add(t, cID, trivial)
+			}
+		}
+	}
+	return refs.freeInputs(r1cs.Secret)
+}
+
+// FreeSecretInputs implements ConstraintSystem.
+func (system *SparseR1CSCore) FreeSecretInputs() []FreeInput {
+	refs := newInputRefs(&system.System)
+	for cID := range system.Constraints {
+		c := &system.Constraints[cID]
+		trivial := c.isTrivial()
+		for _, t := range []Term{c.L, c.R, c.O, c.M[0], c.M[1]} {
+			refs.add(t, cID, trivial)
+		}
+	}
+	return refs.freeInputs(system.Secret)
+}
+
+// isTrivial returns true if the constraint is satisfied by any assignment of its
+// wires: when L·R and O are zero, or when L (resp. R) is the constant 1 and R
+// (resp. L) and O are the same linear expression.
+func (r1c *R1C) isTrivial() bool {
+	if (isZero(r1c.L) || isZero(r1c.R)) && isZero(r1c.O) {
+		return true
+	}
+	if isConstantOne(r1c.L) && sameTerms(r1c.R, r1c.O) {
+		return true
+	}
+	return isConstantOne(r1c.R) && sameTerms(r1c.L, r1c.O)
+}
+
+// isTrivial returns true if the constraint is satisfied by any assignment of its
+// wires: when it has no constant and no multiplication, and the linear terms on
+// the same wire cancel out. Only the coefficients ±1 and ±2 are compared.
+func (c *SparseR1C) isTrivial() bool {
+	if c.K != CoeffIdZero || (c.M[0].CoeffID() != CoeffIdZero && c.M[1].CoeffID() != CoeffIdZero) {
+		return false
+	}
+	sums := make(map[int]int, 3)
+	for _, t := range []Term{c.L, c.R, c.O} {
+		switch t.CoeffID() {
+		case CoeffIdZero:
+		case CoeffIdOne:
+			sums[t.WireID()]++
+		case CoeffIdMinusOne:
+			sums[t.WireID()]--
+		case CoeffIdTwo:
+			sums[t.WireID()] += 2
+		case CoeffIdMinusTwo:
+			sums[t.WireID()] -= 2
+		default:
+			return false
+		}
+	}
+	for _, s := range sums {
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(l LinearExpression) bool {
+	for _, t := range l {
+		if t.CoeffID() != CoeffIdZero {
+			return false
+		}
+	}
+	return true
+}
+
+// isConstantOne returns true if l is the constant wire of a R1CS (wire 0).
+func isConstantOne(l LinearExpression) bool {
+	nonZero := nonZeroTerms(l)
+	return len(nonZero) == 1 && nonZero[0].WireID() == 0 && nonZero[0].CoeffID() == CoeffIdOne
+}
+
+// sameTerms returns true if a and b have the same non-zero terms. The
+// coefficients are compared by ID, the values of the coefficients being unique in
+// a constraint system.
+func sameTerms(a, b LinearExpression) bool {
+	ta, tb := nonZeroTerms(a), nonZeroTerms(b)
+	if len(ta) != len(tb) {
+		return false
+	}
+	for i := range ta {
+		if ta[i] != tb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// nonZeroTerms returns the terms of l with a non-zero coefficient, sorted.
+func nonZeroTerms(l LinearExpression) []Term {
+	res := make([]Term, 0, len(l))
+	for _, t := range l {
+		if t.CoeffID() != CoeffIdZero {
+			res = append(res, t)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].VID != res[j].VID {
+			return res[i].VID < res[j].VID
+		}
+		return res[i].CID < res[j].CID
+	})
+	return res
+}
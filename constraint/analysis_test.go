@@ -0,0 +1,52 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeSecretInputs(t *testing.T) {
+	assert := require.New(t)
+
+	r1cs := cs.NewR1CS(0)
+	ONE := r1cs.AddPublicVariable("1")
+	Y := r1cs.AddPublicVariable("Y")
+	X := r1cs.AddSecretVariable("X")
+	Z := r1cs.AddSecretVariable("Z")
+	W := r1cs.AddSecretVariable("W")
+	cOne := r1cs.FromInterface(1)
+
+	// X · W == Y
+	r1cs.AddConstraint(constraint.R1C{
+		L: constraint.LinearExpression{r1cs.MakeTerm(&cOne, X)},
+		R: constraint.LinearExpression{r1cs.MakeTerm(&cOne, W)},
+		O: constraint.LinearExpression{r1cs.MakeTerm(&cOne, Y)},
+	})
+	// 1 · Z == Z
+	r1cs.AddConstraint(constraint.R1C{
+		L: constraint.LinearExpression{r1cs.MakeTerm(&cOne, ONE)},
+		R: constraint.LinearExpression{r1cs.MakeTerm(&cOne, Z)},
+		O: constraint.LinearExpression{r1cs.MakeTerm(&cOne, Z)},
+	})
+	assert.Equal([]constraint.FreeInput{{Name: "Z", Constraints: []int{1}}}, r1cs.FreeSecretInputs())
+
+	scs := cs.NewSparseR1CS(0)
+	A := scs.AddSecretVariable("A")
+	B := scs.AddSecretVariable("B")
+	scs.AddSecretVariable("C")
+	cMinusOne := scs.FromInterface(-1)
+
+	// A - A == 0
+	scs.AddConstraint(constraint.SparseR1C{
+		L: scs.MakeTerm(&cOne, A),
+		O: scs.MakeTerm(&cMinusOne, A),
+	})
+	// A · B == 0
+	scs.AddConstraint(constraint.SparseR1C{
+		M: [2]constraint.Term{scs.MakeTerm(&cOne, A), scs.MakeTerm(&cOne, B)},
+	})
+	assert.Equal([]constraint.FreeInput{{Name: "C"}}, scs.FreeSecretInputs())
+}
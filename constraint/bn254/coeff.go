@@ -17,13 +17,22 @@
 package cs
 
 import (
+	"errors"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/internal/utils"
 	"math/big"
+	"unsafe"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
 
+// serializedHeader is the part of a constraint system encoded with gob;
+// the constraints are serialized separately.
+type serializedHeader struct {
+	System       constraint.System
+	Coefficients []fr.Element
+}
+
 // CoeffTable ensure we store unique coefficients in the constraint system
 type CoeffTable struct {
 	Coefficients []fr.Element
@@ -158,3 +167,34 @@ func (engine *arithEngine) String(a *constraint.Coeff) string {
 	e := (*fr.Element)(a[:])
 	return e.String()
 }
+
+// coefficientsDigestBytes returns the concatenation of the big-endian encodings
+// of the coefficients, in regular form (see constraint.DigestR1CS)
+func coefficientsDigestBytes(c []fr.Element) []byte {
+	buf := make([]byte, 0, len(c)*fr.Bytes)
+	for i := range c {
+		b := c[i].Bytes()
+		buf = append(buf, b[:]...)
+	}
+	return buf
+}
+
+// coefficientsToBytes returns the memory backing the coefficients
+func coefficientsToBytes(c []fr.Element) []byte {
+	if len(c) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&c[0])), len(c)*int(unsafe.Sizeof(c[0])))
+}
+
+// coefficientsFromBytes returns the coefficients backed by b, without copy
+func coefficientsFromBytes(b []byte) ([]fr.Element, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var e fr.Element
+	if len(b)%int(unsafe.Sizeof(e)) != 0 || uintptr(unsafe.Pointer(&b[0]))%unsafe.Alignof(e) != 0 {
+		return nil, errors.New("invalid coefficients encoding")
+	}
+	return unsafe.Slice((*fr.Element)(unsafe.Pointer(&b[0])), len(b)/int(unsafe.Sizeof(e))), nil
+}
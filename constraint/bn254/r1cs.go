@@ -23,10 +23,11 @@ import (
 	"io"
 	"time"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/constraint/solver"
-	"github.com/consensys/gnark/internal/backend/ioutils"
+	gnarkio "github.com/consensys/gnark/io"
 	"github.com/consensys/gnark/logger"
 	"github.com/consensys/gnark/profile"
 
@@ -101,7 +102,7 @@ func (cs *R1CS) Solve(witness witness.Witness, opts ...solver.Option) (any, erro
 // witness = [publicWires | secretWires] (without the ONE_WIRE !)
 // returns  [publicWires | secretWires | internalWires ]
 func (cs *R1CS) solve(witness, a, b, c fr.Vector, opt solver.Config) (fr.Vector, error) {
-	log := logger.Logger().With().Int("nbConstraints", len(cs.Constraints)).Str("backend", "groth16").Logger()
+	log := logger.Logger().With().Fields(opt.LogContext).Int("nbConstraints", len(cs.Constraints)).Str("backend", "groth16").Logger()
 
 	nbWires := len(cs.Public) + len(cs.Secret) + cs.NbInternalVariables
 	solution, err := newSolution(&cs.System, nbWires, opt.HintFunctions, cs.Coefficients)
@@ -109,6 +110,10 @@ func (cs *R1CS) solve(witness, a, b, c fr.Vector, opt solver.Config) (fr.Vector,
 		return make(fr.Vector, nbWires), err
 	}
 	start := time.Now()
+	if opt.Report != nil {
+		solution.report = opt.Report
+		defer func() { solution.fillReport(time.Since(start)) }()
+	}
 
 	if len(witness) != len(cs.Public)-1+len(cs.Secret) { // - 1 for ONE_WIRE
 		err = fmt.Errorf("invalid witness size, got %d, expected %d = %d (public) + %d (secret)", len(witness), int(len(cs.Public)-1+len(cs.Secret)), len(cs.Public)-1, len(cs.Secret))
@@ -138,7 +143,11 @@ func (cs *R1CS) solve(witness, a, b, c fr.Vector, opt solver.Config) (fr.Vector,
 	// (or sooner, if a constraint is not satisfied)
 	defer solution.printLogs(opt.Logger, cs.Logs)
 
-	if err := cs.parallelSolve(a, b, c, &solution); err != nil {
+	var id []byte
+	if opt.CheckpointPath != "" {
+		id = checkpointID(cs.Digest(), witness)
+	}
+	if err := cs.parallelSolve(a, b, c, &solution, opt, id); err != nil {
 		if unsatisfiedErr, ok := err.(*UnsatisfiedConstraintError); ok {
 			log.Err(errors.New("unsatisfied constraint")).Int("id", unsatisfiedErr.CID).Send()
 		} else {
@@ -158,43 +167,63 @@ func (cs *R1CS) solve(witness, a, b, c fr.Vector, opt solver.Config) (fr.Vector,
 	return solution.values, nil
 }
 
-// This method has been rewrite to be not be parallel
-func (cs *R1CS) parallelSolve(a, b, c fr.Vector, solution *solution) error {
-	for _, level := range cs.Levels {
-		// Calculate the number of tasks for this level
-		nbTasks := len(level)
-		nbIterationsPerCpus := 1
-
-		extraTasks := len(level) - (nbTasks * nbIterationsPerCpus)
-		extraTasksOffset := 0
-
-		for i := 0; i < nbTasks; i++ {
-			_start := i*nbIterationsPerCpus + extraTasksOffset
-			_end := _start + nbIterationsPerCpus
-			if extraTasks > 0 {
-				_end++
-				extraTasks--
-				extraTasksOffset++
-			}
+// r1csState is the state of the R1CS solver saved in its checkpoints (see
+// solver.WithCheckpoint): the solved wires and the a, b, c vectors.
+type r1csState struct {
+	solution *solution
+	a, b, c  fr.Vector
+}
 
-			// For each task, solve the constraints in the task
-			for _, j := range level[_start:_end] {
-				if err := cs.solveConstraint(cs.Constraints[j], solution, &a[j], &b[j], &c[j]); err != nil {
-					var debugInfo *string
-					if dID, ok := cs.MDebug[j]; ok {
-						debugInfo = new(string)
-						*debugInfo = solution.logValue(cs.DebugInfo[dID])
-					}
-					return &UnsatisfiedConstraintError{CID: j, Err: err, DebugInfo: debugInfo}
-				}
-			}
+func (s *r1csState) WriteTo(w io.Writer) (int64, error) {
+	n, err := s.solution.WriteTo(w)
+	for _, v := range []fr.Vector{s.a, s.b, s.c} {
+		if err != nil {
+			return n, err
 		}
+		var m int64
+		m, err = v.WriteTo(w)
+		n += m
 	}
-
-	return nil
+	return n, err
 }
 
+func (s *r1csState) ReadFrom(r io.Reader) (int64, error) {
+	n, err := s.solution.ReadFrom(r)
+	for _, v := range []fr.Vector{s.a, s.b, s.c} {
+		if err != nil {
+			return n, err
+		}
+		var m int64
+		m, err = readVector(r, v)
+		n += m
+	}
+	return n, err
+}
 
+// parallelSolve solves the constraints level by level, saving checkpoints of
+// the state if opt.CheckpointPath is set (see solver.WithCheckpoint); id
+// identifies the system and its witness in the checkpoints.
+func (cs *R1CS) parallelSolve(a, b, c fr.Vector, solution *solution, opt solver.Config, id []byte) error {
+	// cs.Levels has a list of levels, where all constraints in a level l(n) are independent
+	// and may only have dependencies on previous levels
+	// for each constraint
+	// we are guaranteed that each R1C contains at most one unsolved wire
+	// first we solve the unsolved wire (if any)
+	// then we check that the constraint is valid
+	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
+	state := &r1csState{solution: solution, a: a, b: b, c: c}
+	return solver.SolveLevelsWithCheckpoints(cs.Levels, opt, id, state, func(i int) error {
+		if err := cs.solveConstraint(cs.Constraints[i], solution, &a[i], &b[i], &c[i]); err != nil {
+			var debugInfo *string
+			if dID, ok := cs.MDebug[i]; ok {
+				debugInfo = new(string)
+				*debugInfo = solution.logValue(cs.DebugInfo[dID])
+			}
+			return &UnsatisfiedConstraintError{CID: i, Scope: cs.GetConstraintScope(i), Err: err, DebugInfo: debugInfo}
+		}
+		return nil
+	})
+}
 
 // IsSolved
 // Deprecated: use _, err := Solve(...) instead
@@ -338,6 +367,12 @@ func (cs *R1CS) GetConstraints() ([]constraint.R1C, constraint.Resolver) {
 	return cs.Constraints, cs
 }
 
+// GetCoefficient returns coefficient with given id in the coeff table
+func (cs *R1CS) GetCoefficient(i int) (r constraint.Coeff) {
+	copy(r[:], cs.Coefficients[i][:])
+	return
+}
+
 // GetNbCoefficients return the number of unique coefficients needed in the R1CS
 func (cs *R1CS) GetNbCoefficients() int {
 	return len(cs.Coefficients)
@@ -348,31 +383,100 @@ func (cs *R1CS) CurveID() ecc.ID {
 	return ecc.BN254
 }
 
-// WriteTo encodes R1CS into provided io.Writer using gob
+// Digest returns the fingerprint of the R1CS (see constraint.DigestR1CS)
+func (cs *R1CS) Digest() constraint.Digest {
+	return constraint.DigestR1CS(&cs.System, coefficientsDigestBytes(cs.Coefficients), cs.Constraints)
+}
+
+// WriteTo encodes R1CS into provided io.Writer. The coefficients and the system
+// metadata are encoded using gob, the constraints are then streamed in chunks
+// (see constraint.WriteR1Cs).
 func (cs *R1CS) WriteTo(w io.Writer) (int64, error) {
-	_w := ioutils.WriterCounter{W: w} // wraps writer to count the bytes written
+	header := gnarkio.NewHeader(gnarkio.R1CS, cs.CurveID(), backend.UNKNOWN)
+	return gnarkio.WriteWithHeader(w, header, cs.writeTo)
+}
 
-	// encode our object
-	encoder := gob.NewEncoder(&_w)
+func (cs *R1CS) writeTo(w io.Writer) error {
+	// encode everything but the constraints
+	encoder := gob.NewEncoder(w)
+	header := serializedHeader{System: cs.System, Coefficients: cs.Coefficients}
+	if err := encoder.Encode(&header); err != nil {
+		return err
+	}
 
-	return _w.N, encoder.Encode(cs)
+	_, err := constraint.WriteR1Cs(w, cs.Constraints)
+	return err
 }
 
-// ReadFrom attempts to decode R1CS from io.Reader using gob
+// ReadFrom attempts to decode R1CS from io.Reader (see WriteTo)
 func (cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
-	_r := ioutils.ReaderCounter{R: r} // wraps reader to count the bytes written
-	decoder := gob.NewDecoder(&_r)
+	header := gnarkio.NewHeader(gnarkio.R1CS, cs.CurveID(), backend.UNKNOWN)
+	return gnarkio.ReadWithHeader(r, header, cs.readFrom)
+}
+
+func (cs *R1CS) readFrom(r io.Reader) error {
+	// r is an io.ByteReader (see gnarkio.ReadWithHeader): gob doesn't read past the
+	// encoded header, the constraints can then be read from the same reader.
+	decoder := gob.NewDecoder(r)
 
 	// initialize coeff table
 	cs.CoeffTable = newCoeffTable(0)
 
-	if err := decoder.Decode(cs); err != nil {
-		return _r.N, err
+	var header serializedHeader
+	if err := decoder.Decode(&header); err != nil {
+		return err
+	}
+	cs.System = header.System
+	cs.Coefficients = header.Coefficients
+
+	constraints, _, err := constraint.ReadR1Cs(r)
+	if err != nil {
+		return err
+	}
+	cs.Constraints = constraints
+	if err := cs.CheckTerms(len(cs.Coefficients)); err != nil {
+		return err
+	}
+
+	return cs.CheckSerializationHeader()
+}
+
+// WriteMmapTo encodes R1CS into provided io.Writer in a format which can be
+// memory mapped and used without copy (see NewR1CSFromMmap)
+func (cs *R1CS) WriteMmapTo(w io.Writer) (int64, error) {
+	return constraint.WriteMmapR1CS(w, &cs.System, coefficientsToBytes(cs.Coefficients), cs.Constraints)
+}
+
+// NewR1CSFromMmap returns a R1CS backed by data, as written by WriteMmapTo.
+// data is typically obtained from constraint.OpenMappedFile; the coefficients and the
+// constraints are not copied, so data must not be released while the R1CS is in
+// use. data is only read, it may be a read-only mapping: the constraints are copied
+// before being modified in place.
+func NewR1CSFromMmap(data []byte) (*R1CS, error) {
+	var cs R1CS
+	cs.CoeffTable = newCoeffTable(0)
+	coefficients, err := constraint.ReadMmapR1CS(data, &cs.R1CSCore)
+	if err != nil {
+		return nil, err
+	}
+	// the coefficients are only appended to, and the capacity of the slice is its
+	// length: new coefficients are never written in data.
+	if cs.Coefficients, err = coefficientsFromBytes(coefficients); err != nil {
+		return nil, err
 	}
 
 	if err := cs.CheckSerializationHeader(); err != nil {
-		return _r.N, err
+		return nil, err
 	}
+	return &cs, nil
+}
 
-	return _r.N, nil
+// ToSparseR1CS returns a SparseR1CS equivalent to cs, which can be used with PLONK.
+// Constraint systems with commitments can't be converted.
+func (cs *R1CS) ToSparseR1CS() (*SparseR1CS, error) {
+	res := NewSparseR1CS(len(cs.Constraints))
+	if err := cs.ConvertToSparseR1CS(cs, &res.SparseR1CSCore, res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
@@ -18,9 +18,16 @@ package cs_test
 
 import (
 	"bytes"
+	"errors"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/internal/backend/circuits"
+	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -82,6 +89,7 @@ func TestSerialization(t *testing.T) {
 						"System.lbHints",
 						"System.SymbolTable",
 						"System.lbOutputs",
+						"System.scopeStack",
 						"System.bitLen")); diff != "" {
 					t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
 				}
@@ -132,6 +140,57 @@ func TestSerialization(t *testing.T) {
 
 const n = 10000
 
+func TestConversion(t *testing.T) {
+	for name := range circuits.Circuits {
+		t.Run(name, func(t *testing.T) {
+			tc := circuits.Circuits[name]
+
+			r1cs1, err := frontend.Compile(fr.Modulus(), r1cs.NewBuilder, tc.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testing.Short() && r1cs1.GetNbConstraints() > 50 {
+				return
+			}
+			sparse, err := r1cs1.(*cs.R1CS).ToSparseR1CS()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			scs1, err := frontend.Compile(fr.Modulus(), scs.NewBuilder, tc.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dense, err := scs1.(*cs.SparseR1CS).ToR1CS()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opt := solver.WithHints(tc.HintFunctions...)
+			for _, ccs := range []constraint.ConstraintSystem{sparse, dense} {
+				for _, a := range tc.ValidAssignments {
+					w, err := frontend.NewWitness(a, fr.Modulus())
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := ccs.Solve(w, opt); err != nil {
+						t.Fatalf("%T: valid assignment: %v", ccs, err)
+					}
+				}
+				for _, a := range tc.InvalidAssignments {
+					w, err := frontend.NewWitness(a, fr.Modulus())
+					if err != nil {
+						continue
+					}
+					if _, err := ccs.Solve(w, opt); err == nil {
+						t.Fatalf("%T: invalid assignment solved", ccs)
+					}
+				}
+			}
+		})
+	}
+}
+
 type circuit struct {
 	X frontend.Variable
 	Y frontend.Variable `gnark:",public"`
@@ -166,3 +225,105 @@ func BenchmarkSolve(b *testing.B) {
 		_ = ccs.IsSolved(witness)
 	}
 }
+
+func identityHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	outputs[0].Set(inputs[0])
+	return nil
+}
+
+var (
+	earlyHint = solver.NewHint("cs_test.early", identityHint)
+	lateHint  = solver.NewHint("cs_test.late", identityHint)
+)
+
+type checkpointCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *checkpointCircuit) Define(api frontend.API) error {
+	x := circuit.X
+	for i := 0; i < 20; i++ {
+		x = api.Mul(x, x)
+		var hint solver.Hint
+		switch i {
+		case 5:
+			hint = earlyHint
+		case 15:
+			hint = lateHint
+		default:
+			continue
+		}
+		res, err := api.Compiler().NewHint(hint, 1, x)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(res[0], x)
+		x = res[0]
+	}
+	api.AssertIsEqual(x, circuit.Y)
+	return nil
+}
+
+func TestSolveCheckpoint(t *testing.T) {
+	var y fr.Element
+	y.SetUint64(3)
+	for i := 0; i < 20; i++ {
+		y.Square(&y)
+	}
+	w, err := frontend.NewWitness(&checkpointCircuit{X: 3, Y: y}, fr.Modulus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hints := solver.WithHints(earlyHint, lateHint)
+	interrupt := func(*big.Int, []*big.Int, []*big.Int) error {
+		return errors.New("interrupted")
+	}
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(fr.Modulus(), newBuilder, &checkpointCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := ccs.Solve(w, hints)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the solver is interrupted after the early hint, then resumed from the
+		// checkpoint: the early hint isn't called again
+		path := filepath.Join(t.TempDir(), "checkpoint")
+		checkpoint := solver.WithCheckpoint(path, 0)
+		if _, err := ccs.Solve(w, hints, checkpoint, solver.OverrideHint(lateHint.ID, interrupt)); err == nil {
+			t.Fatalf("%T: expected the solver to be interrupted", ccs)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		}
+		solution, err := ccs.Solve(w, hints, checkpoint, solver.OverrideHint(earlyHint.ID, interrupt))
+		if err != nil {
+			t.Fatalf("%T: resume: %v", ccs, err)
+		}
+		if !reflect.DeepEqual(expected, solution) {
+			t.Fatalf("%T: resumed solution differs", ccs)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatal("checkpoint not removed")
+		}
+
+		// a checkpoint of another witness is ignored
+		other, err := frontend.NewWitness(&checkpointCircuit{X: 2, Y: 1}, fr.Modulus())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.Solve(other, hints, checkpoint); err == nil {
+			t.Fatal("invalid witness solved")
+		}
+		if _, err := ccs.Solve(w, hints, checkpoint, solver.OverrideHint(lateHint.ID, interrupt)); err == nil {
+			t.Fatalf("%T: expected the solver to be interrupted", ccs)
+		}
+		if _, err := ccs.Solve(other, hints, checkpoint); err == nil {
+			t.Fatal("invalid witness solved from the checkpoint of another witness")
+		}
+	}
+}
@@ -17,7 +17,8 @@
 package cs
 
 import (
-	"errors"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/constraint/solver"
@@ -28,6 +29,7 @@ import (
 	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
@@ -41,6 +43,7 @@ type solution struct {
 	mHintsFunctions      map[solver.HintID]solver.HintFn // maps hintID to hint function
 	st                   *debug.SymbolTable
 	cs                   *constraint.System
+	report               *solver.Report // statistics of the solving, see solver.WithReport
 }
 
 func newSolution(cs *constraint.System, nbWires int, hintFunctions map[solver.HintID]solver.HintFn, coefficients []fr.Element) (solution, error) {
@@ -55,15 +58,8 @@ func newSolution(cs *constraint.System, nbWires int, hintFunctions map[solver.Hi
 	}
 
 	// hintsDependencies is from compile time; it contains the list of hints the solver **needs**
-	var missing []string
-	for hintUUID, hintID := range cs.MHintsDependencies {
-		if _, ok := s.mHintsFunctions[hintUUID]; !ok {
-			missing = append(missing, hintID)
-		}
-	}
-
-	if len(missing) > 0 {
-		return s, fmt.Errorf("solver missing hint(s): %v", missing)
+	if err := cs.CheckHintFunctions(s.mHintsFunctions); err != nil {
+		return s, err
 	}
 
 	return s, nil
@@ -152,7 +148,7 @@ func (s *solution) solveWithHint(vID int, hID int) error {
 	// ensure hint function was provided
 	f, ok := s.mHintsFunctions[h.HintID]
 	if !ok {
-		return errors.New("missing hint function")
+		return &solver.MissingHintsError{IDs: []solver.HintID{h.HintID}, Names: []string{s.cs.GetHintName(h.HintID)}}
 	}
 
 	// tmp IO big int memory
@@ -202,8 +198,17 @@ func (s *solution) solveWithHint(vID int, hID int) error {
 		v.BigInt(inputs[i])
 	}
 
+	var start time.Time
+	if s.report != nil {
+		start = time.Now()
+	}
+
 	err := f(q, inputs, outputs)
 
+	if s.report != nil {
+		s.report.AddHintCall(s.cs.GetHintName(h.HintID), time.Since(start))
+	}
+
 	var v fr.Element
 	for i := range outputs {
 		v.SetBigInt(outputs[i])
@@ -213,58 +218,76 @@ func (s *solution) solveWithHint(vID int, hID int) error {
 	return err
 }
 
+// fillReport records the bit lengths of the solved wires and the total solving
+// time in the report, see solver.WithReport.
+func (s *solution) fillReport(took time.Duration) {
+	// the values are stored in Montgomery form
+	var v big.Int
+	for i := range s.values {
+		if s.solved[i] {
+			s.report.AddWire(s.values[i].BigInt(&v).BitLen())
+		}
+	}
+	s.report.Finish(took)
+}
+
 func (s *solution) printLogs(log zerolog.Logger, logs []constraint.LogEntry) {
 	if log.GetLevel() == zerolog.Disabled {
 		return
 	}
 
 	for i := 0; i < len(logs); i++ {
-		logLine := s.logValue(logs[i])
-		log.Debug().Str(zerolog.CallerFieldName, logs[i].Caller).Msg(logLine)
+		l := &logs[i]
+		if l.Level < log.GetLevel() {
+			continue
+		}
+		if l.Condition != nil {
+			// logs gated by a condition which can't be evaluated are not printed
+			if c, ok := s.evaluateLog(l.Condition); !ok || c.IsZero() {
+				continue
+			}
+		}
+		e := log.WithLevel(l.Level).Str(zerolog.CallerFieldName, l.Caller)
+		for j, key := range l.Keys {
+			e = e.Str(key, s.logExpression(l.Values[j]))
+		}
+		e.Msg(s.logValue(*l))
 	}
 }
 
 const unsolvedVariable = "<unsolved>"
 
-func (s *solution) logValue(log constraint.LogEntry) string {
-	var toResolve []interface{}
-	var (
-		eval         fr.Element
-		missingValue bool
-	)
-	for j := 0; j < len(log.ToResolve); j++ {
-		// before eval le
-
-		missingValue = false
-		eval.SetZero()
-
-		for _, t := range log.ToResolve[j] {
-			// for each term in the linear expression
-
-			cID, vID := t.CoeffID(), t.WireID()
-			if t.IsConstant() {
-				// just add the constant
-				eval.Add(&eval, &s.coefficients[cID])
-				continue
-			}
-
-			if !s.solved[vID] {
-				missingValue = true
-				break // stop the loop we can't evaluate.
-			}
-
-			tv := s.computeTerm(t)
-			eval.Add(&eval, &tv)
+// evaluateLog returns the value of le, and false if one of its wires is not solved.
+func (s *solution) evaluateLog(le constraint.LinearExpression) (fr.Element, bool) {
+	var eval fr.Element
+	for _, t := range le {
+		if t.IsConstant() {
+			// just add the constant
+			eval.Add(&eval, &s.coefficients[t.CoeffID()])
+			continue
 		}
-
-		// after
-		if missingValue {
-			toResolve = append(toResolve, unsolvedVariable)
-		} else {
-			// we have to append our accumulator
-			toResolve = append(toResolve, eval.String())
+		if !s.solved[t.WireID()] {
+			return eval, false
 		}
+		tv := s.computeTerm(t)
+		eval.Add(&eval, &tv)
+	}
+	return eval, true
+}
 
+// logExpression returns the string representation of the value of le.
+func (s *solution) logExpression(le constraint.LinearExpression) string {
+	eval, ok := s.evaluateLog(le)
+	if !ok {
+		return unsolvedVariable
+	}
+	return eval.String()
+}
+
+func (s *solution) logValue(log constraint.LogEntry) string {
+	toResolve := make([]interface{}, 0, len(log.ToResolve)+1)
+	for j := 0; j < len(log.ToResolve); j++ {
+		toResolve = append(toResolve, s.logExpression(log.ToResolve[j]))
 	}
 	if len(log.Stack) > 0 {
 		var sbb strings.Builder
@@ -285,18 +308,104 @@ func (s *solution) logValue(log constraint.LogEntry) string {
 	return fmt.Sprintf(log.Format, toResolve...)
 }
 
+// WriteTo writes the wires solved so far, for the checkpoints of the solver (see
+// solver.WithCheckpoint).
+func (s *solution) WriteTo(w io.Writer) (int64, error) {
+	values := fr.Vector(s.values)
+	n, err := values.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	solved := make([]byte, len(s.solved))
+	for i := range s.solved {
+		if s.solved[i] {
+			solved[i] = 1
+		}
+	}
+	m, err := w.Write(solved)
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+	err = binary.Write(w, binary.BigEndian, s.nbSolved)
+	return n + 8, err
+}
+
+// ReadFrom reads the wires written by WriteTo into s, which must be the solution of
+// the same system.
+func (s *solution) ReadFrom(r io.Reader) (int64, error) {
+	n, err := readVector(r, s.values)
+	if err != nil {
+		return n, err
+	}
+	solved := make([]byte, len(s.solved))
+	m, err := io.ReadFull(r, solved)
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+	for i := range solved {
+		s.solved[i] = solved[i] == 1
+	}
+	err = binary.Read(r, binary.BigEndian, &s.nbSolved)
+	return n + 8, err
+}
+
+// readVector reads a vector written by fr.Vector.WriteTo into v, which must be of
+// the same length.
+func readVector(r io.Reader, v fr.Vector) (int64, error) {
+	var buf [fr.Bytes]byte
+	if read, err := io.ReadFull(r, buf[:4]); err != nil {
+		return int64(read), err
+	}
+	if l := binary.BigEndian.Uint32(buf[:4]); int(l) != len(v) {
+		return 4, fmt.Errorf("invalid vector length %d, expected %d", l, len(v))
+	}
+
+	n := int64(4)
+	for i := range v {
+		read, err := io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return n, err
+		}
+		if v[i], err = fr.BigEndian.Element(&buf); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// checkpointID identifies the system of the given digest and its witness in the
+// checkpoints of the solver.
+func checkpointID(digest constraint.Digest, witness fr.Vector) []byte {
+	h := sha256.New()
+	h.Write(digest[:])
+	var buf [fr.Bytes]byte
+	for i := range witness {
+		fr.BigEndian.PutElement(&buf, witness[i])
+		h.Write(buf[:])
+	}
+	return h.Sum(nil)
+}
+
 // UnsatisfiedConstraintError wraps an error with useful metadata on the unsatisfied constraint
 type UnsatisfiedConstraintError struct {
 	Err       error
 	CID       int     // constraint ID
+	Scope     string  // scope of the constraint, if any (see frontend.Scoper)
 	DebugInfo *string // optional debug info
 }
 
 func (r *UnsatisfiedConstraintError) Error() string {
+	name := fmt.Sprintf("constraint #%d", r.CID)
+	if r.Scope != "" {
+		name += " (" + r.Scope + ")"
+	}
 	if r.DebugInfo != nil {
-		return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
+		return fmt.Sprintf("%s is not satisfied: %s", name, *r.DebugInfo)
 	}
-	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
+	return fmt.Sprintf("%s is not satisfied: %s", name, r.Err.Error())
 }
 
 // R1CSSolution represent a valid assignment to all the variables in the constraint system.
@@ -0,0 +1,58 @@
+// Package circom imports circuits compiled by circom, so that they can be
+// proven with the gnark Groth16 prover.
+//
+// [ReadR1CS] reads the .r1cs file produced by circom (--r1cs) and returns the
+// equivalent BN254 R1CS. The circom wires are kept as is: the public wires are
+// the constant wire, the outputs and the public inputs, the secret wires are
+// the private inputs, followed by the internal wires. The symbol file (--sym)
+// optionally names the inputs in the witness schema and the wires of the
+// constraints in the solver errors.
+//
+// The circom constraints are not necessarily solvable by the gnark solver: the
+// witness of circom circuits is computed by the witness generator produced by
+// circom, and the internal wires are provided to the gnark solver with the
+// witness, see [ReadWitness]:
+//
+//	ccs, s, err := circom.ReadR1CS(r1csFile, circom.WithSymbols(symFile))
+//	// ...
+//	pk, vk, err := groth16.Setup(ccs)
+//	// ...
+//	w, opt, err := circom.ReadWitness(wtnsFile, ccs)
+//	// ...
+//	proof, err := groth16.Prove(ccs, pk, w, backend.WithSolverOptions(opt))
+package circom
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// witnessHint computes the internal wires of the imported circuits. Its
+// function is overridden by the solver option returned by ReadWitness.
+var witnessHint = solver.NewHint("github.com/consensys/gnark/constraint/circom.witness", func(_ *big.Int, _, _ []*big.Int) error {
+	return errors.New("the internal wires of circom circuits must be provided with circom.ReadWitness")
+})
+
+func init() {
+	solver.RegisterHint(witnessHint)
+}
+
+// Option defines option for altering the behaviour of ReadR1CS.
+type Option func(*config) error
+
+type config struct {
+	symbols io.Reader
+}
+
+// WithSymbols reads the symbol file (.sym) produced by circom along with the
+// .r1cs file. The input signals are then named in the schema, and the
+// constraints carry debug information naming their signals.
+func WithSymbols(r io.Reader) Option {
+	return func(c *config) error {
+		c.symbols = r
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package circom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint/circom"
+	"github.com/stretchr/testify/require"
+)
+
+// wires of the test circuit:
+//
+//	0: one, 1: out (output), 2: c (public input), 3: a, 4: b (private inputs),
+//	5: t, 6: inv (internal)
+//
+// constraints:
+//
+//	a ⋅ b == t
+//	(t + c) ⋅ 1 == out
+//	a ⋅ inv == 1
+const testSymbols = `1,1,0,main.out
+2,2,0,main.c
+3,3,0,main.a
+4,4,0,main.b
+5,5,0,main.t
+6,6,1,main.sub.inv
+7,-1,1,main.sub.removed
+`
+
+type term struct {
+	wire  uint32
+	coeff int64
+}
+
+var testConstraints = [][3][]term{
+	{{{3, 1}}, {{4, 1}}, {{5, 1}}},
+	{{{5, 1}, {2, 1}}, {{0, 1}}, {{1, 1}}},
+	{{{3, 1}}, {{6, 1}}, {{0, 1}}},
+}
+
+func testWitness(a, b, c int64) []*big.Int {
+	p := fr.Modulus()
+	t := big.NewInt(a * b)
+	inv := new(big.Int).ModInverse(big.NewInt(a), p)
+	return []*big.Int{big.NewInt(1), big.NewInt(a*b + c), big.NewInt(c), big.NewInt(a), big.NewInt(b), t, inv}
+}
+
+// writeElement writes v in little endian, negative values are reduced modulo
+// the prime (the prime itself is written as is).
+func writeElement(buf *bytes.Buffer, v *big.Int) {
+	if v.Sign() < 0 {
+		v = new(big.Int).Mod(v, fr.Modulus())
+	}
+	var le [fr.Bytes]byte
+	v.FillBytes(le[:])
+	for i, j := 0, len(le)-1; i < j; i, j = i+1, j-1 {
+		le[i], le[j] = le[j], le[i]
+	}
+	buf.Write(le[:])
+}
+
+func writeSection(buf *bytes.Buffer, typ uint32, content []byte) {
+	_ = binary.Write(buf, binary.LittleEndian, typ)
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(content)))
+	buf.Write(content)
+}
+
+func writeFileHeader(buf *bytes.Buffer, magic string, version, nbSections uint32) {
+	buf.WriteString(magic)
+	_ = binary.Write(buf, binary.LittleEndian, version)
+	_ = binary.Write(buf, binary.LittleEndian, nbSections)
+}
+
+func writeR1CS(constraintsFirst bool) []byte {
+	var header bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, uint32(fr.Bytes))
+	writeElement(&header, fr.Modulus())
+	for _, v := range []uint32{7, 1, 1, 2} { // wires, outputs, public inputs, private inputs
+		_ = binary.Write(&header, binary.LittleEndian, v)
+	}
+	_ = binary.Write(&header, binary.LittleEndian, uint64(8)) // labels
+	_ = binary.Write(&header, binary.LittleEndian, uint32(len(testConstraints)))
+
+	var constraints bytes.Buffer
+	for _, c := range testConstraints {
+		for _, l := range c {
+			_ = binary.Write(&constraints, binary.LittleEndian, uint32(len(l)))
+			for _, t := range l {
+				_ = binary.Write(&constraints, binary.LittleEndian, t.wire)
+				writeElement(&constraints, big.NewInt(t.coeff))
+			}
+		}
+	}
+
+	var wire2Label bytes.Buffer
+	for _, label := range []uint64{0, 1, 2, 3, 4, 5, 6} {
+		_ = binary.Write(&wire2Label, binary.LittleEndian, label)
+	}
+
+	var buf bytes.Buffer
+	writeFileHeader(&buf, "r1cs", 1, 3)
+	if constraintsFirst {
+		writeSection(&buf, 2, constraints.Bytes())
+		writeSection(&buf, 1, header.Bytes())
+	} else {
+		writeSection(&buf, 1, header.Bytes())
+		writeSection(&buf, 2, constraints.Bytes())
+	}
+	writeSection(&buf, 3, wire2Label.Bytes())
+	return buf.Bytes()
+}
+
+func writeWitness(values []*big.Int) []byte {
+	var header, content bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, uint32(fr.Bytes))
+	writeElement(&header, fr.Modulus())
+	_ = binary.Write(&header, binary.LittleEndian, uint32(len(values)))
+	for _, v := range values {
+		writeElement(&content, v)
+	}
+
+	var buf bytes.Buffer
+	writeFileHeader(&buf, "wtns", 2, 2)
+	writeSection(&buf, 1, header.Bytes())
+	writeSection(&buf, 2, content.Bytes())
+	return buf.Bytes()
+}
+
+func TestReadR1CS(t *testing.T) {
+	assert := require.New(t)
+
+	for _, constraintsFirst := range []bool{false, true} {
+		ccs, s, err := circom.ReadR1CS(bytes.NewReader(writeR1CS(constraintsFirst)), circom.WithSymbols(strings.NewReader(testSymbols)))
+		assert.NoError(err)
+		assert.Equal(3, ccs.GetNbPublicVariables())
+		assert.Equal(2, ccs.GetNbSecretVariables())
+		assert.Equal(2, ccs.GetNbInternalVariables())
+		assert.Equal(len(testConstraints), ccs.GetNbConstraints())
+		assert.Equal([]string{"1", "out", "c"}, ccs.Public)
+		assert.Equal([]string{"a", "b"}, ccs.Secret)
+		assert.Equal(2, s.NbPublic)
+		assert.Equal(2, s.NbSecret)
+
+		w, opt, err := circom.ReadWitness(bytes.NewReader(writeWitness(testWitness(3, 5, 2))), ccs)
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w, opt))
+		// the internal wires must be provided
+		assert.Error(ccs.IsSolved(w))
+
+		data, err := w.ToJSON(s)
+		assert.NoError(err)
+		assert.JSONEq(`{"out":17,"c":2,"a":3,"b":5}`, string(data))
+
+		values := testWitness(3, 5, 2)
+		values[1].SetInt64(18)
+		w, opt, err = circom.ReadWitness(bytes.NewReader(writeWitness(values)), ccs)
+		assert.NoError(err)
+		err = ccs.IsSolved(w, opt)
+		assert.Error(err)
+		assert.Contains(err.Error(), "[circom] (t + c) ⋅ (1) == (out), t = 15, c = 2, out = 18")
+	}
+
+	// counts which don't match the data, allocations must be bounded by the
+	// size of the file
+	const (
+		nbConstraintsOffset = len("r1cs") + 4 + 4 + 4 + 8 + 4 + fr.Bytes + 4*4 + 8
+		nbTermsOffset       = nbConstraintsOffset + 4 + 4 + 8
+	)
+	for _, offset := range []int{nbConstraintsOffset, nbTermsOffset} {
+		r1cs := writeR1CS(false)
+		binary.LittleEndian.PutUint32(r1cs[offset:], math.MaxUint32)
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, _, err := circom.ReadR1CS(bytes.NewReader(r1cs))
+		runtime.ReadMemStats(&after)
+		assert.Error(err)
+		assert.Less(after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+	}
+
+	// wrong field
+	r1cs := writeR1CS(false)
+	r1cs[len("r1cs")+4+4+4+8+4] ^= 1 // first byte of the prime
+	_, _, err := circom.ReadR1CS(bytes.NewReader(r1cs))
+	assert.Error(err)
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, _, err := circom.ReadR1CS(bytes.NewReader(writeR1CS(false)))
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	w, opt, err := circom.ReadWitness(bytes.NewReader(writeWitness(testWitness(3, 5, 2))), ccs)
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w, backend.WithSolverOptions(opt))
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, public))
+
+	// the public values of another witness don't verify
+	other, _, err := circom.ReadWitness(bytes.NewReader(writeWitness(testWitness(3, 6, 2))), ccs)
+	assert.NoError(err)
+	public, err = other.Public()
+	assert.NoError(err)
+	assert.Error(groth16.Verify(proof, vk, public))
+}
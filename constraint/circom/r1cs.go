@@ -0,0 +1,366 @@
+package circom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// section types of the .r1cs format
+const (
+	r1csHeader      = 1
+	r1csConstraints = 2
+	r1csWire2Label  = 3
+	r1csCustomGates = 4
+)
+
+// r1csFileHeader is the header section of a .r1cs file.
+type r1csFileHeader struct {
+	n8                             uint32 // size of the field elements in bytes
+	nbWires                        uint32
+	nbOutputs, nbPublic, nbPrivate uint32
+	nbConstraints                  uint32
+}
+
+// ReadR1CS reads the circom .r1cs file from r and returns the equivalent
+// BN254 R1CS, along with the schema of its witness. Circuits compiled for
+// other fields, or using custom gates, are not supported.
+func ReadR1CS(r io.Reader, opts ...Option) (*cs.R1CS, *schema.Schema, error) {
+	var cfg config
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	var names map[int]string
+	if cfg.symbols != nil {
+		var err error
+		if names, err = readSymbols(cfg.symbols); err != nil {
+			return nil, nil, fmt.Errorf("read symbols: %w", err)
+		}
+	}
+
+	br := bufio.NewReader(r)
+	nbSections, err := readFileHeader(br, "r1cs")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		header      *r1csFileHeader
+		constraints []byte // constraints section read before the header
+		ccs         *cs.R1CS
+	)
+	for i := 0; i < int(nbSections); i++ {
+		typ, size, err := readSectionHeader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		section := io.LimitReader(br, int64(size))
+		switch typ {
+		case r1csHeader:
+			if header, err = readR1CSHeader(section); err != nil {
+				return nil, nil, err
+			}
+			ccs = newR1CS(header, names)
+			if constraints != nil {
+				if err = readConstraints(bytes.NewReader(constraints), header, ccs, names); err != nil {
+					return nil, nil, err
+				}
+			}
+		case r1csConstraints:
+			if header == nil {
+				if constraints, err = io.ReadAll(section); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			if err = readConstraints(section, header, ccs, names); err != nil {
+				return nil, nil, err
+			}
+		case r1csCustomGates:
+			return nil, nil, errors.New("circom custom gates are not supported")
+		}
+		// skip the rest of the section (wire to label map, unknown sections)
+		if _, err = io.Copy(io.Discard, section); err != nil {
+			return nil, nil, err
+		}
+	}
+	if header == nil {
+		return nil, nil, errors.New("missing header section")
+	}
+	if ccs.GetNbConstraints() != int(header.nbConstraints) {
+		return nil, nil, fmt.Errorf("read %d constraints, expected %d", ccs.GetNbConstraints(), header.nbConstraints)
+	}
+
+	return ccs, newSchema(ccs), nil
+}
+
+func readR1CSHeader(r io.Reader) (*r1csFileHeader, error) {
+	var h r1csFileHeader
+	if err := readPrime(r, &h.n8); err != nil {
+		return nil, err
+	}
+	var nbLabels uint64
+	for _, v := range []any{&h.nbWires, &h.nbOutputs, &h.nbPublic, &h.nbPrivate, &nbLabels, &h.nbConstraints} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+	}
+	if h.nbWires < 1+h.nbOutputs+h.nbPublic+h.nbPrivate {
+		return nil, errors.New("invalid header: not enough wires for the inputs")
+	}
+	return &h, nil
+}
+
+// newR1CS returns the R1CS with the input wires of the circom circuit, and its
+// internal wires computed by the witness hint.
+func newR1CS(h *r1csFileHeader, names map[int]string) *cs.R1CS {
+	// the counts of the header can't be trusted to size allocations, the
+	// constraints are appended as they are read.
+	ccs := cs.NewR1CS(0)
+	ccs.AddPublicVariable("1")
+	wire := 1
+	for ; wire <= int(h.nbOutputs+h.nbPublic); wire++ {
+		ccs.AddPublicVariable(wireName(names, wire))
+	}
+	for ; wire <= int(h.nbOutputs+h.nbPublic+h.nbPrivate); wire++ {
+		ccs.AddSecretVariable(wireName(names, wire))
+	}
+	if nbInternal := int(h.nbWires) - wire; nbInternal > 0 {
+		// the hint has no input, the internal wires are then solved first. As
+		// AddSolverHint allocates the wires in order, they keep their circom IDs.
+		if _, err := ccs.AddSolverHint(witnessHint, nil, nbInternal); err != nil {
+			panic(err) // can't happen, nbInternal > 0
+		}
+	}
+	return ccs
+}
+
+func readConstraints(r io.Reader, h *r1csFileHeader, ccs *cs.R1CS, names map[int]string) error {
+	br := bufio.NewReader(r)
+	buf := make([]byte, h.n8)
+	var v big.Int
+	readLinearExpression := func() (constraint.LinearExpression, error) {
+		var nbTerms uint32
+		if err := binary.Read(br, binary.LittleEndian, &nbTerms); err != nil {
+			return nil, err
+		}
+		var l constraint.LinearExpression
+		for i := uint32(0); i < nbTerms; i++ {
+			var wire uint32
+			if err := binary.Read(br, binary.LittleEndian, &wire); err != nil {
+				return nil, err
+			}
+			if wire >= h.nbWires {
+				return nil, fmt.Errorf("wire %d out of range", wire)
+			}
+			if _, err := io.ReadFull(br, buf); err != nil {
+				return nil, err
+			}
+			coeff := ccs.FromInterface(fromLittleEndian(&v, buf))
+			l = append(l, ccs.MakeTerm(&coeff, int(wire)))
+		}
+		return l, nil
+	}
+
+	for ccs.GetNbConstraints() < int(h.nbConstraints) {
+		var (
+			r1c constraint.R1C
+			err error
+		)
+		if r1c.L, err = readLinearExpression(); err != nil {
+			return fmt.Errorf("read constraint %d: %w", ccs.GetNbConstraints(), err)
+		}
+		if r1c.R, err = readLinearExpression(); err != nil {
+			return fmt.Errorf("read constraint %d: %w", ccs.GetNbConstraints(), err)
+		}
+		if r1c.O, err = readLinearExpression(); err != nil {
+			return fmt.Errorf("read constraint %d: %w", ccs.GetNbConstraints(), err)
+		}
+		if names != nil {
+			ccs.AddConstraint(r1c, debugInfo(ccs, r1c, names))
+		} else {
+			ccs.AddConstraint(r1c)
+		}
+	}
+	return nil
+}
+
+// debugInfo describes r1c with the names of the signals and their values.
+func debugInfo(ccs *cs.R1CS, r1c constraint.R1C, names map[int]string) constraint.DebugInfo {
+	var (
+		l   constraint.LogEntry
+		sbb strings.Builder
+	)
+	sbb.WriteString("[circom] ")
+	writeLinearExpression := func(le constraint.LinearExpression) {
+		sbb.WriteByte('(')
+		if len(le) == 0 {
+			sbb.WriteByte('0')
+		}
+		for i, t := range le {
+			if i > 0 {
+				sbb.WriteString(" + ")
+			}
+			c := ccs.GetCoefficient(t.CoeffID())
+			if t.WireID() == 0 {
+				sbb.WriteString(ccs.String(&c))
+				continue
+			}
+			if !ccs.IsOne(&c) {
+				sbb.WriteString(ccs.String(&c))
+				sbb.WriteString("⋅")
+			}
+			sbb.WriteString(escape(wireName(names, t.WireID())))
+		}
+		sbb.WriteByte(')')
+	}
+	writeLinearExpression(r1c.L)
+	sbb.WriteString(" ⋅ ")
+	writeLinearExpression(r1c.R)
+	sbb.WriteString(" == ")
+	writeLinearExpression(r1c.O)
+
+	// values of the signals
+	one := ccs.One()
+	seen := make(map[int]struct{})
+	for _, le := range []constraint.LinearExpression{r1c.L, r1c.R, r1c.O} {
+		for _, t := range le {
+			wire := t.WireID()
+			if _, ok := seen[wire]; ok || wire == 0 {
+				continue
+			}
+			seen[wire] = struct{}{}
+			sbb.WriteString(", ")
+			sbb.WriteString(escape(wireName(names, wire)))
+			sbb.WriteString(" = ")
+			l.WriteVariable(constraint.LinearExpression{ccs.MakeTerm(&one, wire)}, &sbb)
+		}
+	}
+	l.Format = sbb.String()
+	return constraint.DebugInfo(l)
+}
+
+// newSchema returns the schema of the witness of ccs: one leaf per input wire,
+// named after the circom signal.
+func newSchema(ccs *cs.R1CS) *schema.Schema {
+	s := &schema.Schema{NbPublic: len(ccs.Public) - 1, NbSecret: len(ccs.Secret)}
+	goNames := make(map[string]struct{})
+	addField := func(name string, visibility schema.Visibility) {
+		goName := toGoName(name)
+		if _, ok := goNames[goName]; ok {
+			goName = fmt.Sprintf("%s_%d", goName, len(s.Fields))
+		}
+		goNames[goName] = struct{}{}
+		s.Fields = append(s.Fields, schema.Field{
+			Name:       goName,
+			NameTag:    name,
+			FullName:   name,
+			Visibility: visibility,
+			Type:       schema.Leaf,
+		})
+	}
+	for _, name := range ccs.Public[1:] {
+		addField(name, schema.Public)
+	}
+	for _, name := range ccs.Secret {
+		addField(name, schema.Secret)
+	}
+	return s
+}
+
+// toGoName returns an exported Go identifier for the signal name.
+func toGoName(name string) string {
+	var sbb strings.Builder
+	sbb.WriteByte('S')
+	for _, c := range name {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			sbb.WriteRune(c)
+		} else {
+			sbb.WriteByte('_')
+		}
+	}
+	return sbb.String()
+}
+
+// wireName returns the name of the signal of the wire, without the main
+// component prefix.
+func wireName(names map[int]string, wire int) string {
+	if name, ok := names[wire]; ok {
+		return strings.TrimPrefix(name, "main.")
+	}
+	return fmt.Sprintf("w%d", wire)
+}
+
+// escape escapes s for a fmt format string.
+func escape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// readFileHeader reads the magic and version of a circom binary file and
+// returns its number of sections.
+func readFileHeader(r io.Reader, magic string) (nbSections uint32, err error) {
+	var header struct {
+		Magic      [4]byte
+		Version    uint32
+		NbSections uint32
+	}
+	if err = binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return 0, fmt.Errorf("read file header: %w", err)
+	}
+	if string(header.Magic[:]) != magic {
+		return 0, fmt.Errorf("invalid magic %q, expected %q", header.Magic[:], magic)
+	}
+	return header.NbSections, nil
+}
+
+func readSectionHeader(r io.Reader) (typ uint32, size uint64, err error) {
+	if err = binary.Read(r, binary.LittleEndian, &typ); err != nil {
+		return 0, 0, fmt.Errorf("read section header: %w", err)
+	}
+	if err = binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return 0, 0, fmt.Errorf("read section header: %w", err)
+	}
+	return typ, size, nil
+}
+
+// readPrime reads the size of the field elements and the prime, which must be
+// the BN254 scalar field.
+func readPrime(r io.Reader, n8 *uint32) error {
+	if err := binary.Read(r, binary.LittleEndian, n8); err != nil {
+		return fmt.Errorf("read field size: %w", err)
+	}
+	if *n8 != fr.Bytes {
+		return fmt.Errorf("unsupported field size %d, only BN254 is supported", *n8)
+	}
+	buf := make([]byte, *n8)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return fmt.Errorf("read prime: %w", err)
+	}
+	if p := fromLittleEndian(new(big.Int), buf); p.Cmp(fr.Modulus()) != 0 {
+		return fmt.Errorf("unsupported prime %s, only BN254 is supported", p.String())
+	}
+	return nil
+}
+
+// fromLittleEndian sets v to the little endian integer buf and returns v.
+func fromLittleEndian(v *big.Int, buf []byte) *big.Int {
+	be := make([]byte, len(buf))
+	for i := range buf {
+		be[len(buf)-1-i] = buf[i]
+	}
+	return v.SetBytes(be)
+}
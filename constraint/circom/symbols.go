@@ -0,0 +1,38 @@
+package circom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// readSymbols reads a circom .sym file, with lines
+//
+//	labelID,wireID,componentID,name
+//
+// and returns the name of the wires. The wires with several labels are named
+// after the first one.
+func readSymbols(r io.Reader) (map[int]string, error) {
+	names := make(map[int]string)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.SplitN(scanner.Text(), ",", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields", line)
+		}
+		wire, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid wire: %w", line, err)
+		}
+		if wire < 0 {
+			continue // signal removed by the circom optimizer
+		}
+		if _, ok := names[wire]; !ok {
+			names[wire] = fields[3]
+		}
+	}
+	return names, scanner.Err()
+}
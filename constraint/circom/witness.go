@@ -0,0 +1,108 @@
+package circom
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// section types of the .wtns format
+const (
+	wtnsHeader = 1
+	wtnsValues = 2
+)
+
+// ReadWitness reads the circom .wtns file from r, computed by the circom
+// witness generator for the circuit imported as ccs. It returns the witness
+// of ccs (public and secret inputs) and the solver option providing the
+// internal wires to the solver.
+func ReadWitness(r io.Reader, ccs constraint.ConstraintSystem) (witness.Witness, solver.Option, error) {
+	br := bufio.NewReader(r)
+	nbSections, err := readFileHeader(br, "wtns")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		n8      uint32
+		values  []*big.Int
+		nbWires = ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables() + ccs.GetNbInternalVariables()
+	)
+	for i := 0; i < int(nbSections); i++ {
+		typ, size, err := readSectionHeader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		section := io.LimitReader(br, int64(size))
+		switch typ {
+		case wtnsHeader:
+			if err = readPrime(section, &n8); err != nil {
+				return nil, nil, err
+			}
+			var nbValues uint32
+			if err = binary.Read(section, binary.LittleEndian, &nbValues); err != nil {
+				return nil, nil, fmt.Errorf("read header: %w", err)
+			}
+			if int(nbValues) != nbWires {
+				return nil, nil, fmt.Errorf("witness has %d wires, expected %d", nbValues, nbWires)
+			}
+		case wtnsValues:
+			if n8 == 0 {
+				return nil, nil, errors.New("values section before the header section")
+			}
+			values = make([]*big.Int, nbWires)
+			buf := make([]byte, n8)
+			for j := range values {
+				if _, err = io.ReadFull(section, buf); err != nil {
+					return nil, nil, fmt.Errorf("read value %d: %w", j, err)
+				}
+				values[j] = fromLittleEndian(new(big.Int), buf)
+			}
+		}
+		if _, err = io.Copy(io.Discard, section); err != nil {
+			return nil, nil, err
+		}
+	}
+	if values == nil {
+		return nil, nil, errors.New("missing values section")
+	}
+	if values[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, errors.New("invalid witness: the constant wire is not 1")
+	}
+
+	// the witness doesn't contain the constant wire
+	nbPublic, nbSecret := ccs.GetNbPublicVariables()-1, ccs.GetNbSecretVariables()
+	w, err := witness.New(ccs.Field())
+	if err != nil {
+		return nil, nil, err
+	}
+	chValues := make(chan any)
+	go func() {
+		defer close(chValues)
+		for _, v := range values[1 : 1+nbPublic+nbSecret] {
+			chValues <- v
+		}
+	}()
+	if err = w.Fill(nbPublic, nbSecret, chValues); err != nil {
+		return nil, nil, err
+	}
+
+	internal := values[1+nbPublic+nbSecret:]
+	opt := solver.OverrideHint(witnessHint.ID, func(_ *big.Int, _, outputs []*big.Int) error {
+		if len(outputs) != len(internal) {
+			return fmt.Errorf("witness has %d internal wires, expected %d", len(internal), len(outputs))
+		}
+		for i := range outputs {
+			outputs[i].Set(internal[i])
+		}
+		return nil
+	})
+	return w, opt, nil
+}
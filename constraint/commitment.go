@@ -1,9 +1,15 @@
 package constraint
 
 import (
+	"crypto/sha256"
+	"errors"
 	"math/big"
+	"os"
+	"strings"
 
 	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/logger"
 )
 
 const CommitmentDst = "bsb22-commitment"
@@ -60,3 +66,40 @@ func (i *Commitment) PrivateToPublic() []int {
 func (i *Commitment) PrivateCommitted() []int {
 	return i.Committed[i.NbPublicCommitted():]
 }
+
+// NbPlaceholderConstraints returns the number of constraints PLONK adds for the commitment,
+// after the public inputs placeholders: one binding the commitment wire to the value
+// the verifier derives from the proof, followed by one per committed wire, binding it to
+// the committed polynomial.
+func (i *Commitment) NbPlaceholderConstraints() int {
+	if !i.Is() {
+		return 0
+	}
+	return 1 + i.NbCommitted()
+}
+
+// Bsb22CommitmentHint computes the commitment wire. The provers replace it (see
+// solver.OverrideHint) by the actual computation of the commitment.
+var Bsb22CommitmentHint = solver.NewHint("bsb22_compute_placeholder", bsb22CommitmentComputePlaceholder)
+
+func bsb22CommitmentComputePlaceholder(mod *big.Int, input []*big.Int, output []*big.Int) error {
+	if (len(os.Args) > 0 && (strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], ".test.exe"))) || debug.Debug {
+		// usually we only run solver without prover during testing
+		log := logger.Logger()
+		log.Error().Msg("Augmented commitment hint not replaced. Proof will not be sound!")
+		// the gadgets checking random linear combinations need a value depending on
+		// the committed variables, as the commitment
+		h := sha256.New()
+		buf := make([]byte, (mod.BitLen()+7)/8)
+		for i := range input {
+			h.Write(input[i].FillBytes(buf))
+		}
+		output[0].SetBytes(h.Sum(nil)).Mod(output[0], mod)
+		return nil
+	}
+	return errors.New("placeholder function: to be replaced by commitment computation")
+}
+
+func init() {
+	solver.RegisterHint(Bsb22CommitmentHint)
+}
@@ -0,0 +1,523 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constraint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The conversions keep the wires of the source system: public and secret inputs,
+// hint outputs and internal wires keep their relative order, only the ONE wire of the
+// R1CS is added or removed. Wires introduced by the conversion are appended after the
+// internal wires of the source system.
+//
+// The converted constraints are laid out so that the solver of the destination system
+// solves, in each constraint, the wire that the solver of the source system solves.
+
+var (
+	errConvertCommitment  = errors.New("constraint systems with commitments can't be converted")
+	errConvertUnsolved    = errors.New("more than one unsolved wire")
+	errConvertUnsolvable  = errors.New("unsolved wire has a zero coefficient")
+	errConvertNoConstWire = errors.New("R1CS has no constant wire")
+)
+
+// coeffTable is the coefficient table of a concrete constraint system
+type coeffTable interface {
+	CoeffEngine
+	GetCoefficient(i int) Coeff
+	MakeTerm(coeff *Coeff, variableID int) Term
+}
+
+// ConvertToSparseR1CS rewrites the R1CS, whose coefficients are resolved by coeffs, as
+// PLONK constraints in dst. dst must be an empty SparseR1CS defined over the same field,
+// dstCoeffs its coefficient table.
+func (r1cs *R1CSCore) ConvertToSparseR1CS(coeffs coeffTable, dst *SparseR1CSCore, dstCoeffs coeffTable) error {
+	if r1cs.CommitmentInfo.Is() {
+		return errConvertCommitment
+	}
+	if r1cs.GetNbPublicVariables() == 0 {
+		return errConvertNoConstWire
+	}
+
+	// the ONE wire of the R1CS is wire 0, it becomes a constant
+	c := converter{src: &r1cs.System, srcCoeffs: coeffs, dst: &dst.System, dstCoeffs: dstCoeffs, shift: -1}
+	c.copySystem(r1cs.Public[1:])
+
+	w := scsWriter{converter: &c, cs: dst}
+	solved := c.solvedWires()
+	for i := range r1cs.Constraints {
+		start := len(dst.Constraints)
+		if err := w.convert(&r1cs.Constraints[i], solved); err != nil {
+			return fmt.Errorf("constraint %d: %w", i, err)
+		}
+		c.attachDebugInfo(i, start, len(dst.Constraints))
+	}
+	return nil
+}
+
+// ConvertToR1CS rewrites the SparseR1CS, whose coefficients are resolved by coeffs, as
+// R1C constraints in dst. dst must be an empty R1CS defined over the same field,
+// dstCoeffs its coefficient table.
+func (cs *SparseR1CSCore) ConvertToR1CS(coeffs coeffTable, dst *R1CSCore, dstCoeffs coeffTable) error {
+	if cs.CommitmentInfo.Is() {
+		return errConvertCommitment
+	}
+
+	// the R1CS needs a ONE wire as first public input
+	c := converter{src: &cs.System, srcCoeffs: coeffs, dst: &dst.System, dstCoeffs: dstCoeffs, shift: 1}
+	c.copySystem(append([]string{"1"}, cs.Public...))
+
+	w := r1csWriter{converter: &c, cs: dst}
+	solved := c.solvedWires()
+	for i := range cs.Constraints {
+		if err := w.convert(&cs.Constraints[i], solved); err != nil {
+			return fmt.Errorf("constraint %d: %w", i, err)
+		}
+		c.attachDebugInfo(i, i, i+1)
+	}
+	return nil
+}
+
+// converter holds the state shared by the conversions in both directions
+type converter struct {
+	src, dst             *System
+	srcCoeffs, dstCoeffs coeffTable
+
+	// shift maps a source wire to a destination wire; negative wires denote the ONE wire
+	shift int
+}
+
+// copySystem copies the inputs, hints, logs and debug info of the source system
+func (c *converter) copySystem(public []string) {
+	c.dst.Public = append(c.dst.Public, public...)
+	c.dst.Secret = append(c.dst.Secret, c.src.Secret...)
+	c.dst.NbInternalVariables = c.src.NbInternalVariables
+	c.dst.SymbolTable = c.src.SymbolTable
+
+	for id, name := range c.src.MHintsDependencies {
+		c.dst.MHintsDependencies[id] = name
+	}
+	c.dst.HintMappings = make([]HintMapping, len(c.src.HintMappings))
+	for i, h := range c.src.HintMappings {
+		hm := HintMapping{
+			HintID:  h.HintID,
+			Inputs:  make([]LinearExpression, len(h.Inputs)),
+			Outputs: make([]int, len(h.Outputs)),
+		}
+		for j := range h.Inputs {
+			hm.Inputs[j] = c.linearExpression(h.Inputs[j])
+		}
+		for j, wID := range h.Outputs {
+			hm.Outputs[j] = wID + c.shift
+			c.dst.MHints[hm.Outputs[j]] = i
+		}
+		c.dst.HintMappings[i] = hm
+	}
+
+	for _, l := range c.src.Logs {
+		c.dst.Logs = append(c.dst.Logs, c.logEntry(l))
+	}
+	for _, l := range c.src.DebugInfo {
+		c.dst.DebugInfo = append(c.dst.DebugInfo, c.logEntry(l))
+	}
+}
+
+// attachDebugInfo attaches the debug info and the scope of the source constraint cID to the
+// destination constraints [start, end)
+func (c *converter) attachDebugInfo(cID, start, end int) {
+	if start < end {
+		c.dst.addScopeRange(start, c.src.GetConstraintScope(cID))
+	}
+	dID, ok := c.src.MDebug[cID]
+	if !ok {
+		return
+	}
+	for i := start; i < end; i++ {
+		c.dst.MDebug[i] = dID
+	}
+}
+
+func (c *converter) logEntry(l LogEntry) LogEntry {
+	res := l
+	res.ToResolve = make([]LinearExpression, len(l.ToResolve))
+	for i := range l.ToResolve {
+		res.ToResolve[i] = c.linearExpression(l.ToResolve[i])
+	}
+	if l.Condition != nil {
+		res.Condition = c.linearExpression(l.Condition)
+	}
+	if len(l.Values) != 0 {
+		res.Values = make([]LinearExpression, len(l.Values))
+		for i := range l.Values {
+			res.Values[i] = c.linearExpression(l.Values[i])
+		}
+	}
+	return res
+}
+
+// linearExpression returns l in the destination system; the ONE wire is replaced by
+// constant terms.
+func (c *converter) linearExpression(l LinearExpression) LinearExpression {
+	res := make(LinearExpression, len(l))
+	for i, t := range l {
+		coeff := c.srcCoeffs.GetCoefficient(t.CoeffID())
+		if t.IsConstant() || t.WireID()+c.shift < 0 {
+			res[i] = c.dstCoeffs.MakeTerm(&coeff, 0)
+			res[i].MarkConstant()
+			continue
+		}
+		res[i] = c.dstCoeffs.MakeTerm(&coeff, t.WireID()+c.shift)
+	}
+	return res
+}
+
+// solvedWires returns the source wires which are known before solving the constraints:
+// the inputs and the hint outputs, which the solvers compute when they first need them.
+func (c *converter) solvedWires() []bool {
+	nbInputs := c.src.GetNbPublicVariables() + c.src.GetNbSecretVariables()
+	solved := make([]bool, nbInputs+c.src.NbInternalVariables)
+	for i := 0; i < nbInputs; i++ {
+		solved[i] = true
+	}
+	for wID := range c.src.MHints {
+		solved[wID] = true
+	}
+	return solved
+}
+
+// wireCoeff is a coeff⋅wire term in the destination system, before the coefficient is
+// added to the coefficient table
+type wireCoeff struct {
+	wire  int
+	coeff Coeff
+}
+
+// linear is Σ terms + k in the destination system
+type linear struct {
+	terms []wireCoeff
+	k     Coeff
+}
+
+// add adds coeff⋅wire to l, merging the terms on the same wire
+func (c *converter) add(l *linear, wire int, coeff Coeff) {
+	for i := range l.terms {
+		if l.terms[i].wire == wire {
+			c.dstCoeffs.Add(&l.terms[i].coeff, &coeff)
+			return
+		}
+	}
+	l.terms = append(l.terms, wireCoeff{wire: wire, coeff: coeff})
+}
+
+// scsWriter writes R1C constraints as SparseR1C
+type scsWriter struct {
+	*converter
+	cs *SparseR1CSCore
+}
+
+// convert adds the SparseR1C equivalent to L⋅R == O.
+func (w *scsWriter) convert(r1c *R1C, solved []bool) error {
+	// find the unsolved wire, if any
+	var (
+		loc     *LinearExpression
+		unknown = -1
+		uCoeff  Coeff
+	)
+	for _, l := range [3]*LinearExpression{&r1c.L, &r1c.R, &r1c.O} {
+		for _, t := range *l {
+			if t.IsConstant() || solved[t.WireID()] {
+				continue
+			}
+			if unknown != -1 {
+				return errConvertUnsolved
+			}
+			loc, unknown, uCoeff = l, t.WireID(), w.srcCoeffs.GetCoefficient(t.CoeffID())
+		}
+	}
+
+	L, R, O := w.linear(r1c.L, unknown), w.linear(r1c.R, unknown), w.linear(r1c.O, unknown)
+	switch {
+	case unknown == -1:
+		l, r, o := w.reduce(L), w.reduce(R), w.reduce(O)
+		o.coeff = w.neg(o.coeff)
+		qM, l, r, k := w.product(l, L.k, r, R.k)
+		w.dstCoeffs.Sub(&k, &O.k)
+		if qM.IsZero() && l.coeff.IsZero() && r.coeff.IsZero() && o.coeff.IsZero() && k.IsZero() {
+			return nil
+		}
+		w.addGate(l, r, o, qM, k)
+	case loc == &r1c.O:
+		solved[unknown] = true
+		u := wireCoeff{wire: unknown + w.shift, coeff: w.neg(uCoeff)}
+		l, r, o := w.reduce(L), w.reduce(R), w.reduce(O)
+		qM, l, r, k := w.product(l, L.k, r, R.k)
+		w.dstCoeffs.Sub(&k, &O.k)
+		if o.coeff.IsZero() {
+			w.addGate(l, r, u, qM, k)
+			return nil
+		}
+		o.coeff = w.neg(o.coeff)
+		if !qM.IsZero() {
+			// no room left for o in the gate, the product is computed first
+			p := w.cs.AddInternalVariable()
+			w.addGate(l, r, wireCoeff{wire: p, coeff: w.minusOne()}, qM, k)
+			w.addGate(wireCoeff{wire: p, coeff: w.dstCoeffs.One()}, o, u, Coeff{}, Coeff{})
+			return nil
+		}
+		if l.coeff.IsZero() {
+			l = r
+		}
+		w.addGate(l, o, u, Coeff{}, k)
+	default:
+		solved[unknown] = true
+		if loc == &r1c.R {
+			// the product commutes, we solve for a wire of L
+			L, R = R, L
+		}
+		// (c⋅u + L')⋅R == O  <=>  c⋅u⋅R + t == 0 with t = L'⋅R - O
+		u := wireCoeff{wire: unknown + w.shift, coeff: uCoeff}
+		l, r, o := w.reduce(L), w.reduce(R), w.reduce(O)
+		o.coeff = w.neg(o.coeff)
+		qM, l, rl, k := w.product(l, L.k, r, R.k)
+		w.dstCoeffs.Sub(&k, &O.k)
+
+		if r.coeff.IsZero() {
+			// R is a constant, the constraint is linear: c⋅R⋅u + t == 0
+			w.dstCoeffs.Mul(&u.coeff, &R.k)
+			if u.coeff.IsZero() {
+				return errConvertUnsolvable
+			}
+			if o.coeff.IsZero() || l.coeff.IsZero() {
+				if l.coeff.IsZero() {
+					l = o
+				}
+				w.addGate(l, wireCoeff{}, u, Coeff{}, k)
+				return nil
+			}
+			t := w.cs.AddInternalVariable()
+			w.addGate(l, o, wireCoeff{wire: t, coeff: w.minusOne()}, Coeff{}, k)
+			w.addGate(wireCoeff{wire: t, coeff: w.dstCoeffs.One()}, wireCoeff{}, u, Coeff{}, Coeff{})
+			return nil
+		}
+
+		// t is computed first, then u⋅(c⋅r.coeff⋅r.wire + c⋅R.k) + t == 0 is solved for u
+		t := w.cs.AddInternalVariable()
+		if !qM.IsZero() && !o.coeff.IsZero() {
+			p := w.cs.AddInternalVariable()
+			w.addGate(l, rl, wireCoeff{wire: p, coeff: w.minusOne()}, qM, k)
+			w.addGate(wireCoeff{wire: p, coeff: w.dstCoeffs.One()}, o, wireCoeff{wire: t, coeff: w.minusOne()}, Coeff{}, Coeff{})
+		} else if !qM.IsZero() {
+			w.addGate(l, rl, wireCoeff{wire: t, coeff: w.minusOne()}, qM, k)
+		} else {
+			// L' is a constant
+			w.addGate(rl, o, wireCoeff{wire: t, coeff: w.minusOne()}, Coeff{}, k)
+		}
+
+		qM = u.coeff
+		w.dstCoeffs.Mul(&qM, &r.coeff)
+		w.dstCoeffs.Mul(&u.coeff, &R.k)
+		w.addGate(u, wireCoeff{wire: r.wire}, wireCoeff{wire: t, coeff: w.dstCoeffs.One()}, qM, Coeff{})
+	}
+	return nil
+}
+
+// linear returns the expression l without the unknown wire, in the destination system
+func (w *scsWriter) linear(l LinearExpression, unknown int) linear {
+	var res linear
+	for _, t := range l {
+		if t.WireID() == unknown && !t.IsConstant() {
+			continue
+		}
+		coeff := w.srcCoeffs.GetCoefficient(t.CoeffID())
+		if t.IsConstant() || t.WireID()+w.shift < 0 {
+			w.dstCoeffs.Add(&res.k, &coeff)
+			continue
+		}
+		w.add(&res, t.WireID()+w.shift, coeff)
+	}
+	return res
+}
+
+// reduce returns a single term equal to Σ l.terms, adding the intermediate sums to the
+// constraint system. The constant of l is ignored, a zero term is returned if l has no
+// wire.
+func (w *scsWriter) reduce(l linear) wireCoeff {
+	if len(l.terms) == 0 {
+		return wireCoeff{}
+	}
+	acc := l.terms[0]
+	for _, t := range l.terms[1:] {
+		s := w.cs.AddInternalVariable()
+		w.addGate(acc, t, wireCoeff{wire: s, coeff: w.minusOne()}, Coeff{}, Coeff{})
+		acc = wireCoeff{wire: s, coeff: w.dstCoeffs.One()}
+	}
+	return acc
+}
+
+// product expands (l + kl)⋅(r + kr) as qM⋅l⋅r + l' + r' + k
+func (w *scsWriter) product(l wireCoeff, kl Coeff, r wireCoeff, kr Coeff) (qM Coeff, lk, rk wireCoeff, k Coeff) {
+	qM = l.coeff
+	w.dstCoeffs.Mul(&qM, &r.coeff)
+	lk, rk = l, r
+	w.dstCoeffs.Mul(&lk.coeff, &kr)
+	w.dstCoeffs.Mul(&rk.coeff, &kl)
+	k = kl
+	w.dstCoeffs.Mul(&k, &kr)
+	return
+}
+
+// addGate adds qL⋅l + qR⋅r + qM⋅l⋅r + qO⋅o + k == 0 to the constraint system; the
+// coefficients of l, r and o are qL, qR and qO.
+func (w *scsWriter) addGate(l, r, o wireCoeff, qM, k Coeff) {
+	var mR Coeff
+	if !qM.IsZero() {
+		mR = w.dstCoeffs.One()
+	}
+	K := w.dstCoeffs.MakeTerm(&k, 0)
+	c := SparseR1C{
+		L: w.dstCoeffs.MakeTerm(&l.coeff, l.wire),
+		R: w.dstCoeffs.MakeTerm(&r.coeff, r.wire),
+		O: w.dstCoeffs.MakeTerm(&o.coeff, o.wire),
+		M: [2]Term{w.dstCoeffs.MakeTerm(&qM, l.wire), w.dstCoeffs.MakeTerm(&mR, r.wire)},
+		K: K.CoeffID(),
+	}
+	cID := len(w.cs.Constraints)
+	w.cs.Constraints = append(w.cs.Constraints, c)
+	w.cs.updateLevel(cID, &c)
+}
+
+func (c *converter) neg(a Coeff) Coeff {
+	c.dstCoeffs.Neg(&a)
+	return a
+}
+
+func (c *converter) minusOne() Coeff {
+	return c.neg(c.dstCoeffs.One())
+}
+
+// r1csWriter writes SparseR1C constraints as R1C
+type r1csWriter struct {
+	*converter
+	cs *R1CSCore
+}
+
+// convert adds the R1C equivalent to qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa×xb) + qC == 0.
+func (w *r1csWriter) convert(c *SparseR1C, solved []bool) error {
+	qM := w.srcCoeffs.GetCoefficient(c.M[0].CoeffID())
+	m1 := w.srcCoeffs.GetCoefficient(c.M[1].CoeffID())
+	w.srcCoeffs.Mul(&qM, &m1)
+
+	// find the unsolved wire, as the SparseR1CS solver does
+	unknown, loc := -1, -1
+	for i, t := range [3]Term{c.L, c.R, c.O} {
+		used := t.CoeffID() != CoeffIdZero || (i < 2 && c.M[i].CoeffID() != CoeffIdZero)
+		if !used || solved[t.WireID()] {
+			continue
+		}
+		if unknown != -1 && unknown != t.WireID() {
+			return errConvertUnsolved
+		}
+		unknown, loc = t.WireID(), i
+	}
+	if unknown != -1 {
+		solved[unknown] = true
+	}
+
+	var L, R, O linear
+	if qM.IsZero() {
+		// linear constraint: 1 ⋅ (qL⋅xa + qR⋅xb + qO⋅xc + qC) == 0
+		L.k = w.dstCoeffs.One()
+		w.addTerm(&R, c.K, -1)
+		for _, t := range [3]Term{c.L, c.R, c.O} {
+			w.addTerm(&R, t.CoeffID(), t.WireID())
+		}
+		w.addR1C(L, R, linear{})
+		return nil
+	}
+	w.addTerm(&O, c.K, -1)
+
+	// the unsolved wire may appear in the product and in a linear term; it is then factored:
+	// xa⋅(qM⋅xb + qL) == -(qR⋅xb + qO⋅xc + qC)
+	if loc == 0 && c.M[0].WireID() == unknown || loc == 1 && c.M[1].WireID() == unknown {
+		u, other := c.L, c.R
+		m := c.M[1]
+		if loc == 1 {
+			u, other = c.R, c.L
+			m = c.M[0]
+		}
+		if m.WireID() == unknown || other.WireID() == unknown && other.CoeffID() != CoeffIdZero {
+			return errConvertUnsolved
+		}
+		w.add(&L, unknown+w.shift, w.dstCoeffs.One())
+		w.add(&R, m.WireID()+w.shift, qM)
+		w.addTerm(&R, u.CoeffID(), -1)
+		w.addTerm(&O, other.CoeffID(), other.WireID())
+		w.addTerm(&O, c.O.CoeffID(), c.O.WireID())
+		w.negate(&O)
+		w.addR1C(L, R, O)
+		return nil
+	}
+
+	// qM⋅xa × xb == -(qL⋅xa + qR⋅xb + qO⋅xc + qC)
+	w.add(&L, c.M[0].WireID()+w.shift, qM)
+	w.add(&R, c.M[1].WireID()+w.shift, w.dstCoeffs.One())
+	for _, t := range [3]Term{c.L, c.R, c.O} {
+		w.addTerm(&O, t.CoeffID(), t.WireID())
+	}
+	w.negate(&O)
+	w.addR1C(L, R, O)
+	return nil
+}
+
+// addTerm adds the source term cID⋅wire to l; wire -1 denotes a constant.
+func (w *r1csWriter) addTerm(l *linear, cID int, wire int) {
+	if cID == CoeffIdZero {
+		return
+	}
+	coeff := w.srcCoeffs.GetCoefficient(cID)
+	if wire == -1 {
+		w.dstCoeffs.Add(&l.k, &coeff)
+		return
+	}
+	w.add(l, wire+w.shift, coeff)
+}
+
+func (w *r1csWriter) negate(l *linear) {
+	for i := range l.terms {
+		w.dstCoeffs.Neg(&l.terms[i].coeff)
+	}
+	w.dstCoeffs.Neg(&l.k)
+}
+
+// addR1C adds L⋅R == O to the constraint system; constants are set on the ONE wire (wire 0).
+func (w *r1csWriter) addR1C(L, R, O linear) {
+	toLinearExpression := func(l linear) LinearExpression {
+		res := make(LinearExpression, 0, len(l.terms)+1)
+		if !l.k.IsZero() {
+			res = append(res, w.dstCoeffs.MakeTerm(&l.k, 0))
+		}
+		for i := range l.terms {
+			if !l.terms[i].coeff.IsZero() {
+				res = append(res, w.dstCoeffs.MakeTerm(&l.terms[i].coeff, l.terms[i].wire))
+			}
+		}
+		return res
+	}
+	r1c := R1C{L: toLinearExpression(L), R: toLinearExpression(R), O: toLinearExpression(O)}
+	cID := len(w.cs.Constraints)
+	w.cs.Constraints = append(w.cs.Constraints, r1c)
+	w.cs.updateLevel(cID, &r1c)
+}
@@ -8,12 +8,36 @@ import (
 
 type DebugInfo LogEntry
 
+// NewDebugInfo returns the debug information of a constraint: the message built
+// from errName and i, followed by the stack of the circuit code adding it.
 func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
+	l := system.newDebugMessage(errName, i...)
+	l.Format += "%s\n" // some space for the stack.
+
+	// get the stack
+	l.Stack = system.SymbolTable.CollectStack()
+
+	return DebugInfo(l)
+}
+
+// NewDebugMessage returns the debug information of a constraint without the
+// stack, which is lighter than NewDebugInfo: only the message built from errName
+// and i is reported when the constraint isn't satisfied.
+func (system *System) NewDebugMessage(errName string, i ...interface{}) DebugInfo {
+	return DebugInfo(system.newDebugMessage(errName, i...))
+}
+
+func (system *System) newDebugMessage(errName string, i ...interface{}) LogEntry {
 	var l LogEntry
 
 	const minLogSize = 500
 	var sbb strings.Builder
 	sbb.Grow(minLogSize)
+	if scope := system.CurrentScope(); scope != "" {
+		sbb.WriteString("[")
+		sbb.WriteString(strings.ReplaceAll(scope, "%", "%%")) // Format is a fmt format string
+		sbb.WriteString("] ")
+	}
 	sbb.WriteString("[")
 	sbb.WriteString(errName)
 	sbb.WriteString("] ")
@@ -32,11 +56,7 @@ func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
 		}
 	}
 	sbb.WriteByte('\n')
-	sbb.WriteString("%s\n") // some space for the stack.
 	l.Format = sbb.String()
 
-	// get the stack
-	l.Stack = system.SymbolTable.CollectStack()
-
-	return DebugInfo(l)
+	return l
 }
@@ -0,0 +1,212 @@
+package constraint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SystemDiff lists the differences between two constraint systems, typically
+// the same circuit compiled before and after bumping a dependency, as returned
+// by Diff. Its String method formats them for a review.
+type SystemDiff struct {
+	// Counts are the sizes of the systems which differ: constraints,
+	// coefficients, internal variables, secret and public inputs.
+	Counts []CountDiff
+
+	// Scopes are the numbers of constraints per scope (see frontend.Scope) which
+	// differ, sorted by scope path. A constraint is counted in its innermost
+	// scope only; the constraints outside any scope are counted in "(root)".
+	Scopes []CountDiff
+
+	// AddedHints and RemovedHints are the names of the hints needed by the
+	// second system and not by the first one, and conversely, sorted.
+	AddedHints, RemovedHints []string
+
+	// Public and PublicOther are the names of the public inputs of the first
+	// and the second system, in the order of the witness, if they differ.
+	Public, PublicOther []string
+}
+
+// CountDiff is a count which differs between two constraint systems.
+type CountDiff struct {
+	Name          string
+	Before, After int
+}
+
+func (c CountDiff) String() string {
+	return fmt.Sprintf("%s: %d -> %d (%+d)", c.Name, c.Before, c.After, c.After-c.Before)
+}
+
+// systemer is implemented by the constraint systems built on System.
+type systemer interface {
+	system() *System
+}
+
+func (system *System) system() *System {
+	return system
+}
+
+// Diff returns the differences between the constraint systems a and b: the
+// number of constraints per scope, the hints added or removed and the layout of
+// the public inputs. It doesn't compare the constraints themselves, which their
+// Digest does, but summarizes where a and b differ, to review the impact of a
+// change of the code producing them.
+func Diff(a, b ConstraintSystem) *SystemDiff {
+	var d SystemDiff
+
+	counts := func(cs ConstraintSystem) []int {
+		return []int{cs.GetNbConstraints(), cs.GetNbCoefficients(), cs.GetNbInternalVariables(), cs.GetNbSecretVariables(), cs.GetNbPublicVariables()}
+	}
+	countsA, countsB := counts(a), counts(b)
+	for i, name := range []string{"constraints", "coefficients", "internal variables", "secret inputs", "public inputs"} {
+		if countsA[i] != countsB[i] {
+			d.Counts = append(d.Counts, CountDiff{Name: name, Before: countsA[i], After: countsB[i]})
+		}
+	}
+
+	scopesA, scopesB := constraintsPerScope(a), constraintsPerScope(b)
+	for scope, n := range scopesA {
+		if scopesB[scope] != n {
+			d.Scopes = append(d.Scopes, CountDiff{Name: scope, Before: n, After: scopesB[scope]})
+		}
+	}
+	for scope, n := range scopesB {
+		if _, ok := scopesA[scope]; !ok {
+			d.Scopes = append(d.Scopes, CountDiff{Name: scope, After: n})
+		}
+	}
+	sort.Slice(d.Scopes, func(i, j int) bool { return d.Scopes[i].Name < d.Scopes[j].Name })
+
+	hintsA, hintsB := hintNames(a), hintNames(b)
+	d.AddedHints = missing(hintsB, hintsA)
+	d.RemovedHints = missing(hintsA, hintsB)
+
+	publicA, publicB := publicNames(a), publicNames(b)
+	if !equalStrings(publicA, publicB) {
+		d.Public, d.PublicOther = publicA, publicB
+	}
+	return &d
+}
+
+// Empty returns true if Diff found no difference.
+func (d *SystemDiff) Empty() bool {
+	return len(d.Counts) == 0 && len(d.Scopes) == 0 && len(d.AddedHints) == 0 &&
+		len(d.RemovedHints) == 0 && d.Public == nil && d.PublicOther == nil
+}
+
+// String returns the differences one per line, "no difference" if there is none.
+func (d *SystemDiff) String() string {
+	if d.Empty() {
+		return "no difference"
+	}
+	var sb strings.Builder
+	for _, c := range d.Counts {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+	for _, c := range d.Scopes {
+		sb.WriteString("scope ")
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+	for _, name := range d.AddedHints {
+		fmt.Fprintf(&sb, "hint added: %s\n", name)
+	}
+	for _, name := range d.RemovedHints {
+		fmt.Fprintf(&sb, "hint removed: %s\n", name)
+	}
+	if d.Public != nil || d.PublicOther != nil {
+		fmt.Fprintf(&sb, "public inputs: [%s] -> [%s]\n", strings.Join(d.Public, ", "), strings.Join(d.PublicOther, ", "))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+const rootScope = "(root)"
+
+func constraintsPerScope(cs ConstraintSystem) map[string]int {
+	res := make(map[string]int)
+	add := func(scope string, n int) {
+		if scope == "" {
+			scope = rootScope
+		}
+		if n > 0 {
+			res[scope] += n
+		}
+	}
+	nbConstraints := cs.GetNbConstraints()
+	s, ok := cs.(systemer)
+	if !ok {
+		for cID := 0; cID < nbConstraints; cID++ {
+			add(cs.GetConstraintScope(cID), 1)
+		}
+		return res
+	}
+	ranges := s.system().Scopes
+	if len(ranges) == 0 || ranges[0].Start > 0 {
+		// the constraints preceding the first range are outside any scope
+		start := nbConstraints
+		if len(ranges) != 0 && ranges[0].Start < start {
+			start = ranges[0].Start
+		}
+		add("", start)
+	}
+	for i, r := range ranges {
+		end := nbConstraints
+		if i+1 < len(ranges) && ranges[i+1].Start < end {
+			end = ranges[i+1].Start
+		}
+		add(r.Scope, end-r.Start)
+	}
+	return res
+}
+
+func hintNames(cs ConstraintSystem) []string {
+	ids := cs.GetHintIDs()
+	res := make([]string, len(ids))
+	for i, id := range ids {
+		res[i] = cs.GetHintName(id)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// missing returns the sorted strings of a which aren't in b.
+func missing(a, b []string) []string {
+	inB := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		inB[s] = struct{}{}
+	}
+	var res []string
+	for _, s := range a {
+		if _, ok := inB[s]; !ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// publicNames returns the names of the public inputs of cs, or their number if
+// cs isn't built on System.
+func publicNames(cs ConstraintSystem) []string {
+	if s, ok := cs.(systemer); ok {
+		return s.system().Public
+	}
+	res := make([]string, cs.GetNbPublicVariables())
+	for i := range res {
+		res[i] = fmt.Sprintf("public %d", i)
+	}
+	return res
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/stretchr/testify/require"
+)
+
+type diffCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *diffCircuit) Define(api frontend.API) error {
+	pop := frontend.Scope(api, "square")
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	pop()
+	return nil
+}
+
+// diffCircuitBumped is diffCircuit with a range check of X and a public input Z.
+type diffCircuitBumped struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *diffCircuitBumped) Define(api frontend.API) error {
+	pop := frontend.Scope(api, "square")
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	pop()
+	pop = frontend.Scope(api, "range")
+	bits.ToBinary(api, c.X, bits.WithNbDigits(8))
+	pop()
+	api.AssertIsDifferent(c.X, c.Z)
+	return nil
+}
+
+func TestDiff(t *testing.T) {
+	assert := require.New(t)
+
+	a, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &diffCircuit{})
+	assert.NoError(err)
+	b, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &diffCircuitBumped{})
+	assert.NoError(err)
+
+	d := constraint.Diff(a, a)
+	assert.True(d.Empty())
+	assert.Equal("no difference", d.String())
+
+	d = constraint.Diff(a, b)
+	assert.False(d.Empty())
+	assert.Equal(constraint.CountDiff{Name: "constraints", Before: a.GetNbConstraints(), After: b.GetNbConstraints()}, d.Counts[0])
+	assert.Len(d.Scopes, 2)
+	assert.Equal("(root)", d.Scopes[0].Name)
+	assert.Equal(0, d.Scopes[0].Before)
+	assert.Equal("range", d.Scopes[1].Name)
+	assert.Equal(0, d.Scopes[1].Before)
+	assert.GreaterOrEqual(d.Scopes[1].After, 8) // one per bit
+	assert.Equal([]string{"n_bits"}, d.AddedHints)
+	assert.Empty(d.RemovedHints)
+	assert.Equal([]string{"1", "Y"}, d.Public)
+	assert.Equal([]string{"1", "Y", "Z"}, d.PublicOther)
+	assert.Contains(d.String(), "public inputs: [1, Y] -> [1, Y, Z]")
+
+	// the diff is symmetric
+	r := constraint.Diff(b, a)
+	assert.Equal(d.AddedHints, r.RemovedHints)
+	assert.Equal(d.Public, r.PublicOther)
+}
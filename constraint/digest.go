@@ -0,0 +1,130 @@
+package constraint
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+// Digest is a fingerprint of a compiled constraint system (see DigestR1CS). It is
+// embedded in the proving and verifying keys, so that a key can be matched with
+// the constraint system it was generated for.
+type Digest [sha256.Size]byte
+
+// String returns the hex encoding of the digest.
+func (d Digest) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+// IsZero returns true if the digest is not set, as in the keys written before
+// digests were introduced.
+func (d Digest) IsZero() bool {
+	return d == Digest{}
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the digest in hex.
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding a digest encoded
+// with MarshalText.
+func (d *Digest) UnmarshalText(text []byte) error {
+	if hex.DecodedLen(len(text)) != len(d) {
+		return errors.New("invalid digest length")
+	}
+	_, err := hex.Decode(d[:], text)
+	return err
+}
+
+// WriteTo writes the 32 bytes of the digest to w.
+func (d *Digest) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(d[:])
+	return int64(n), err
+}
+
+// ReadFrom reads a digest written by WriteTo. If r is at EOF, as with the
+// objects written before digests were introduced, the digest is set to zero.
+func (d *Digest) ReadFrom(r io.Reader) (int64, error) {
+	n, err := io.ReadFull(r, d[:])
+	if err == io.EOF {
+		*d = Digest{}
+		err = nil
+	}
+	return int64(n), err
+}
+
+// DigestR1CS returns the SHA-256 hash of a canonical encoding of a R1CS: its
+// scalar field, numbers of wires, coefficients, constraints, hints and
+// commitment. coefficients is the concatenation of the big-endian encodings of
+// the coefficients in regular form.
+//
+// Debug information, logs, wire names and the gnark version are not part of the
+// digest: it only changes with what the circuit computes.
+func DigestR1CS(system *System, coefficients []byte, constraints []R1C) Digest {
+	h := system.newDigester("R1CS", coefficients)
+	_, _ = WriteR1Cs(h, constraints) // a hash never returns an error
+	return h.sum()
+}
+
+// DigestSparseR1CS returns the SHA-256 hash of a canonical encoding of a
+// SparseR1CS, see DigestR1CS.
+func DigestSparseR1CS(system *System, coefficients []byte, constraints []SparseR1C) Digest {
+	h := system.newDigester("SparseR1CS", coefficients)
+	_, _ = WriteSparseR1Cs(h, constraints)
+	return h.sum()
+}
+
+type digester struct {
+	hash.Hash
+}
+
+// newDigester returns a digester which has hashed everything but the constraints.
+func (system *System) newDigester(kind string, coefficients []byte) digester {
+	h := digester{sha256.New()}
+	h.writeBytes([]byte(kind))
+	h.writeBytes([]byte(system.ScalarField))
+	h.writeInts(len(system.Public), len(system.Secret), system.NbInternalVariables)
+	h.writeBytes(coefficients)
+
+	h.writeInts(len(system.HintMappings))
+	for _, m := range system.HintMappings {
+		h.writeInts(int(m.HintID), len(m.Inputs))
+		for _, l := range m.Inputs {
+			h.writeInts(len(l))
+			for _, t := range l {
+				h.writeInts(int(t.CID), int(t.VID))
+			}
+		}
+		h.writeInts(len(m.Outputs))
+		h.writeInts(m.Outputs...)
+	}
+
+	c := &system.CommitmentInfo
+	h.writeInts(len(c.Committed))
+	h.writeInts(c.Committed...)
+	h.writeInts(c.NbPrivateCommitted, int(c.HintID), c.CommitmentIndex, len(c.CommittedAndCommitment))
+	h.writeInts(c.CommittedAndCommitment...)
+	return h
+}
+
+func (h digester) writeInts(v ...int) {
+	var buf [8]byte
+	for _, x := range v {
+		binary.BigEndian.PutUint64(buf[:], uint64(x))
+		h.Write(buf[:])
+	}
+}
+
+func (h digester) writeBytes(b []byte) {
+	h.writeInts(len(b))
+	h.Write(b)
+}
+
+func (h digester) sum() (d Digest) {
+	h.Sum(d[:0])
+	return
+}
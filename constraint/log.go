@@ -16,6 +16,8 @@ package constraint
 
 import (
 	"strings"
+
+	"github.com/rs/zerolog"
 )
 
 // LogEntry is used as a shared data structure between the frontend and the backend
@@ -26,6 +28,25 @@ type LogEntry struct {
 	Format    string
 	ToResolve []LinearExpression // TODO @gbotrel we could store here a struct with a flag that says if we expand or evaluate the expression
 	Stack     []int
+
+	// Level of the log line; the zero value is zerolog.DebugLevel.
+	Level zerolog.Level
+	// Condition, if not nil, gates the log line: it is printed only when the
+	// expression evaluates to a non-zero value.
+	Condition LinearExpression
+	// Keys and Values are the structured fields of the log line.
+	Keys   []string
+	Values []LinearExpression
+}
+
+// linearExpressions returns all the expressions to resolve to print l.
+func (l *LogEntry) linearExpressions() []LinearExpression {
+	res := make([]LinearExpression, 0, len(l.ToResolve)+len(l.Values)+1)
+	res = append(res, l.ToResolve...)
+	if l.Condition != nil {
+		res = append(res, l.Condition)
+	}
+	return append(res, l.Values...)
 }
 
 func (l *LogEntry) WriteVariable(le LinearExpression, sbb *strings.Builder) {
@@ -0,0 +1,103 @@
+package constraint
+
+import (
+	"encoding/binary"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Metadata is a set of key/value pairs describing a constraint system, such as
+// the version of its source or the build time, set at compile time (see
+// frontend.WithMetadata). It is serialized with the constraint system and with
+// the keys generated for it, so that the artifacts are self-describing.
+//
+// Metadata is not part of the digest of the constraint system.
+type Metadata map[string]string
+
+// Clone returns a copy of m, nil if m is empty.
+func (m Metadata) Clone() Metadata {
+	if len(m) == 0 {
+		return nil
+	}
+	res := make(Metadata, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// WriteTo writes uint32(len(m)) followed by the pairs sorted by key, each string
+// encoded as uint32(len(s)) followed by its bytes.
+func (m Metadata) WriteTo(w io.Writer) (int64, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(keys)))
+	n, err := w.Write(buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	written := int64(n)
+	for _, k := range keys {
+		for _, s := range [2]string{k, m[k]} {
+			binary.BigEndian.PutUint32(buf[:], uint32(len(s)))
+			n, err = w.Write(buf[:])
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+			n, err = io.WriteString(w, s)
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// ReadFrom reads metadata written by WriteTo. If r is at EOF, as with the objects
+// written before metadata were introduced, m is set to nil.
+func (m *Metadata) ReadFrom(r io.Reader) (int64, error) {
+	var buf [4]byte
+	n, err := io.ReadFull(r, buf[:])
+	read := int64(n)
+	if err == io.EOF {
+		*m = nil
+		return read, nil
+	}
+	if err != nil {
+		return read, err
+	}
+	nbPairs := binary.BigEndian.Uint32(buf[:])
+	*m = nil
+	var pair [2]string
+	var sb strings.Builder
+	for i := uint32(0); i < nbPairs; i++ {
+		for j := range pair {
+			n, err = io.ReadFull(r, buf[:])
+			read += int64(n)
+			if err != nil {
+				return read, err
+			}
+			sb.Reset()
+			// copy instead of allocating the length read, which may be corrupted
+			n64, err := io.CopyN(&sb, r, int64(binary.BigEndian.Uint32(buf[:])))
+			read += n64
+			if err != nil {
+				return read, err
+			}
+			pair[j] = sb.String()
+		}
+		if *m == nil {
+			*m = make(Metadata)
+		}
+		(*m)[pair[0]] = pair[1]
+	}
+	return read, nil
+}
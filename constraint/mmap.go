@@ -0,0 +1,289 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constraint
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// The mmap format stores the large arrays of a constraint system (coefficients, terms
+// and constraints) as fixed-width records in the native byte order, so that they can be
+// used in place from a read-only memory mapping shared by several processes.
+//
+// The file starts with a fixed size header:
+//
+//	magic [8]byte | byteOrder uint32 | kind uint32 | recordSize uint64 | section[4]
+//
+// where each section is {offset, length uint64}, in bytes, for the gob encoded System,
+// the coefficients, the terms (R1CS only) and the constraint records. Sections are aligned
+// on mmapAlignment bytes.
+//
+// Files are only portable between platforms with the same byte order and word size.
+
+var mmapMagic = [8]byte{'g', 'n', 'a', 'r', 'k', 'm', 'a', 'p'}
+
+const (
+	mmapKindR1CS       = 1
+	mmapKindSparseR1CS = 2
+
+	mmapByteOrder = 0x01020304
+	mmapAlignment = 64
+	mmapNbSection = 4
+
+	mmapHeaderSize = 8 + 4 + 4 + 8 + mmapNbSection*16
+)
+
+const (
+	mmapSectionSystem = iota
+	mmapSectionCoefficients
+	mmapSectionTerms
+	mmapSectionConstraints
+)
+
+var errInvalidMmap = errors.New("invalid memory mappable constraint system")
+
+type mmapHeader struct {
+	Magic      [8]byte
+	ByteOrder  uint32
+	Kind       uint32
+	RecordSize uint64
+	Sections   [mmapNbSection]struct{ Offset, Length uint64 }
+}
+
+// r1cRecord is the fixed size record of a R1C; terms are stored contiguously
+// in the terms section, in order L, R, O.
+type r1cRecord struct {
+	NbL, NbR, NbO uint32
+}
+
+// WriteMmapR1CS writes a R1CS in the memory mappable format.
+// coefficients is the raw memory of the coefficient table.
+func WriteMmapR1CS(w io.Writer, system *System, coefficients []byte, constraints []R1C) (int64, error) {
+	nbTerms := 0
+	for i := range constraints {
+		nbTerms += len(constraints[i].L) + len(constraints[i].R) + len(constraints[i].O)
+	}
+	terms := make([]Term, 0, nbTerms)
+	records := make([]r1cRecord, len(constraints))
+	for i := range constraints {
+		c := &constraints[i]
+		records[i] = r1cRecord{uint32(len(c.L)), uint32(len(c.R)), uint32(len(c.O))}
+		terms = append(terms, c.L...)
+		terms = append(terms, c.R...)
+		terms = append(terms, c.O...)
+	}
+	return writeMmap(w, mmapKindR1CS, system, coefficients, asBytes(terms), asBytes(records), unsafe.Sizeof(r1cRecord{}))
+}
+
+// ReadMmapR1CS reads a R1CS written by WriteMmapR1CS from data into cs. The returned
+// coefficients and the terms of the constraints point into data, which must outlive the
+// constraint system. data is never written to: RemoveDeadWires copies the linear
+// expressions before renumbering them.
+func ReadMmapR1CS(data []byte, cs *R1CSCore) (coefficients []byte, err error) {
+	sections, err := readMmap(data, mmapKindR1CS, &cs.System, unsafe.Sizeof(r1cRecord{}))
+	if err != nil {
+		return nil, err
+	}
+	terms, err := fromBytes[Term](sections[mmapSectionTerms])
+	if err != nil {
+		return nil, err
+	}
+	records, err := fromBytes[r1cRecord](sections[mmapSectionConstraints])
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := make([]R1C, len(records))
+	next := func(n uint32) (LinearExpression, error) {
+		if uint64(n) > uint64(len(terms)) {
+			return nil, errInvalidMmap
+		}
+		l := LinearExpression(terms[:n:n])
+		terms = terms[n:]
+		return l, nil
+	}
+	for i, r := range records {
+		if constraints[i].L, err = next(r.NbL); err != nil {
+			return nil, err
+		}
+		if constraints[i].R, err = next(r.NbR); err != nil {
+			return nil, err
+		}
+		if constraints[i].O, err = next(r.NbO); err != nil {
+			return nil, err
+		}
+	}
+	if len(terms) != 0 {
+		return nil, errInvalidMmap
+	}
+	cs.Constraints = constraints
+	return sections[mmapSectionCoefficients], nil
+}
+
+// WriteMmapSparseR1CS writes a SparseR1CS in the memory mappable format.
+// coefficients is the raw memory of the coefficient table.
+func WriteMmapSparseR1CS(w io.Writer, system *System, coefficients []byte, constraints []SparseR1C) (int64, error) {
+	return writeMmap(w, mmapKindSparseR1CS, system, coefficients, nil, asBytes(constraints), unsafe.Sizeof(SparseR1C{}))
+}
+
+// ReadMmapSparseR1CS reads a SparseR1CS written by WriteMmapSparseR1CS from data into cs.
+// The returned coefficients and the constraints point into data, which must outlive the
+// constraint system. data is never written to: the methods of cs modifying the
+// constraints in place copy them first.
+func ReadMmapSparseR1CS(data []byte, cs *SparseR1CSCore) (coefficients []byte, err error) {
+	sections, err := readMmap(data, mmapKindSparseR1CS, &cs.System, unsafe.Sizeof(SparseR1C{}))
+	if err != nil {
+		return nil, err
+	}
+	if cs.Constraints, err = fromBytes[SparseR1C](sections[mmapSectionConstraints]); err != nil {
+		return nil, err
+	}
+	cs.mapped = true
+	return sections[mmapSectionCoefficients], nil
+}
+
+// ownConstraints copies the constraints of cs to memory it owns, if they are
+// backed by a memory mapping, before they are modified in place.
+func (cs *SparseR1CSCore) ownConstraints() {
+	if !cs.mapped {
+		return
+	}
+	cs.Constraints = append([]SparseR1C(nil), cs.Constraints...)
+	cs.mapped = false
+}
+
+func writeMmap(w io.Writer, kind uint32, system *System, coefficients, terms, records []byte, recordSize uintptr) (int64, error) {
+	var meta bytes.Buffer
+	if err := gob.NewEncoder(&meta).Encode(system); err != nil {
+		return 0, err
+	}
+
+	header := mmapHeader{
+		Magic:      mmapMagic,
+		ByteOrder:  mmapByteOrder,
+		Kind:       kind,
+		RecordSize: uint64(recordSize),
+	}
+	sections := [mmapNbSection][]byte{meta.Bytes(), coefficients, terms, records}
+	offset := uint64(alignMmap(mmapHeaderSize))
+	for i, s := range sections {
+		header.Sections[i].Offset = offset
+		header.Sections[i].Length = uint64(len(s))
+		offset += uint64(alignMmap(len(s)))
+	}
+
+	var n int64
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	padding := make([]byte, mmapAlignment)
+
+	if err := write(asBytes([]mmapHeader{header})); err != nil {
+		return n, err
+	}
+	if err := write(padding[:alignMmap(mmapHeaderSize)-mmapHeaderSize]); err != nil {
+		return n, err
+	}
+	for _, s := range sections {
+		if err := write(s); err != nil {
+			return n, err
+		}
+		if err := write(padding[:alignMmap(len(s))-len(s)]); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func readMmap(data []byte, kind uint32, system *System, recordSize uintptr) ([mmapNbSection][]byte, error) {
+	var sections [mmapNbSection][]byte
+	if len(data) < mmapHeaderSize || uintptr(unsafe.Pointer(&data[0]))%8 != 0 {
+		return sections, errInvalidMmap
+	}
+	header := *(*mmapHeader)(unsafe.Pointer(&data[0]))
+	if header.Magic != mmapMagic {
+		return sections, errInvalidMmap
+	}
+	if header.ByteOrder != mmapByteOrder || header.RecordSize != uint64(recordSize) {
+		return sections, errors.New("constraint system was dumped on an incompatible platform")
+	}
+	if header.Kind != kind {
+		return sections, errors.New("unexpected constraint system type")
+	}
+	for i, s := range header.Sections {
+		if s.Offset > uint64(len(data)) || s.Length > uint64(len(data))-s.Offset {
+			return sections, errInvalidMmap
+		}
+		sections[i] = data[s.Offset : s.Offset+s.Length : s.Offset+s.Length]
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(sections[mmapSectionSystem])).Decode(system); err != nil {
+		return sections, err
+	}
+	return sections, nil
+}
+
+func alignMmap(n int) int {
+	return (n + mmapAlignment - 1) / mmapAlignment * mmapAlignment
+}
+
+// asBytes returns the memory backing s
+func asBytes[T any](s []T) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	var t T
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*int(unsafe.Sizeof(t)))
+}
+
+// fromBytes returns a slice of T backed by b
+func fromBytes[T any](b []byte) ([]T, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var t T
+	size := int(unsafe.Sizeof(t))
+	if len(b)%size != 0 || uintptr(unsafe.Pointer(&b[0]))%unsafe.Alignof(t) != 0 {
+		return nil, errInvalidMmap
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&b[0])), len(b)/size), nil
+}
+
+// MappedFile is a read-only view of a file, see OpenMappedFile.
+type MappedFile struct {
+	data  []byte
+	unmap func() error
+}
+
+// Bytes returns the content of the file. It must not be modified.
+func (f *MappedFile) Bytes() []byte {
+	return f.data
+}
+
+// Close releases the mapping. Objects backed by the mapping must not be used afterwards.
+func (f *MappedFile) Close() error {
+	if f.unmap == nil {
+		return nil
+	}
+	err := f.unmap()
+	f.data, f.unmap = nil, nil
+	return err
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package constraint
+
+import "os"
+
+// OpenMappedFile reads the file at path in memory. Memory mapping is not supported on
+// this platform, the content of the file is copied.
+func OpenMappedFile(path string) (*MappedFile, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- path is provided by the caller
+	if err != nil {
+		return nil, err
+	}
+	return &MappedFile{data: data}, nil
+}
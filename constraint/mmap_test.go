@@ -0,0 +1,99 @@
+package constraint_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// deadWireCircuit computes products it never uses, which RemoveDeadWires drops
+type deadWireCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *deadWireCircuit) Define(api frontend.API) error {
+	api.Mul(c.X, c.X, 3)
+	api.Mul(c.X, c.Y)
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+// writeMapped writes ccs in the memory mappable format and maps the file
+func writeMapped(t *testing.T, ccs interface {
+	WriteMmapTo(w io.Writer) (int64, error)
+}) *constraint.MappedFile {
+	path := filepath.Join(t.TempDir(), "ccs.mmap")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = ccs.WriteMmapTo(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	mapped, err := constraint.OpenMappedFile(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { mapped.Close() })
+	return mapped
+}
+
+func TestMmapRoundTrip(t *testing.T) {
+	assert := require.New(t)
+	witness, err := frontend.NewWitness(&deadWireCircuit{X: 3, Y: 27}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	t.Run("r1cs", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &deadWireCircuit{})
+		assert.NoError(err)
+		expected := ccs.(*cs.R1CS)
+
+		mapped := writeMapped(t, expected)
+		data := append([]byte(nil), mapped.Bytes()...)
+		read, err := cs.NewR1CSFromMmap(mapped.Bytes())
+		assert.NoError(err)
+		assert.Equal(expected.Coefficients, read.Coefficients)
+		assert.Equal(expected.Constraints, read.Constraints)
+		assert.NoError(read.IsSolved(witness))
+
+		// removing the dead wires renumbers the constraints in place; the
+		// mapping is read-only, the constraints must be copied first
+		nbRemoved := expected.RemoveDeadWires()
+		assert.NotZero(nbRemoved)
+		assert.Equal(nbRemoved, read.RemoveDeadWires())
+		assert.Equal(expected.Constraints, read.Constraints)
+		assert.NoError(read.IsSolved(witness))
+		assert.True(bytes.Equal(data, mapped.Bytes()), "mapped data was modified")
+	})
+
+	t.Run("scs", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &deadWireCircuit{})
+		assert.NoError(err)
+		expected := ccs.(*cs.SparseR1CS)
+
+		mapped := writeMapped(t, expected)
+		data := append([]byte(nil), mapped.Bytes()...)
+		read, err := cs.NewSparseR1CSFromMmap(mapped.Bytes())
+		assert.NoError(err)
+		assert.Equal(expected.Coefficients, read.Coefficients)
+		assert.Equal(expected.Constraints, read.Constraints)
+		assert.NoError(read.IsSolved(witness))
+
+		nbRemoved := expected.RemoveDeadWires()
+		assert.NotZero(nbRemoved)
+		assert.Equal(nbRemoved, read.RemoveDeadWires())
+		assert.Equal(expected.Constraints, read.Constraints)
+		assert.NoError(read.IsSolved(witness))
+		assert.True(bytes.Equal(data, mapped.Bytes()), "mapped data was modified")
+	})
+}
@@ -0,0 +1,33 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package constraint
+
+import (
+	"os"
+	"syscall"
+)
+
+// OpenMappedFile maps the file at path in memory, read-only. The mapping is shared
+// with the other processes mapping the same file.
+func OpenMappedFile(path string) (*MappedFile, error) {
+	f, err := os.Open(path) //#nosec G304 -- path is provided by the caller
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return &MappedFile{}, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	return &MappedFile{data: data, unmap: func() error { return syscall.Munmap(data) }}, nil
+}
@@ -0,0 +1,401 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constraint
+
+// A wire is dead when it is an internal wire, which is not the output of a hint, and which is
+// referenced by exactly one constraint: the one that solves it. If the wire appears only linearly
+// in the output of that constraint, any assignment of the other wires can be completed by
+// solving the dead wire, so the constraint does not restrict the circuit and can be dropped
+// along with the wire.
+//
+// Removing a constraint may in turn make other wires dead, so the passes below iterate
+// until no more wire can be removed.
+
+// RemoveDeadWires removes the internal wires which are not used by the circuit, along with the
+// constraints defining them. Remaining internal wires and constraints are renumbered.
+//
+// It returns the number of removed wires.
+func (r1cs *R1CSCore) RemoveDeadWires() int {
+	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
+	pinned := r1cs.pinnedWires(nbWires)
+
+	// wires returns the distinct wires of the constraint, and the ones which may be dead.
+	wires := func(c *R1C) (all, candidates []int) {
+		inLR := make(map[int]struct{}, len(c.L)+len(c.R))
+		for _, t := range c.L {
+			inLR[t.WireID()] = struct{}{}
+		}
+		for _, t := range c.R {
+			inLR[t.WireID()] = struct{}{}
+		}
+		seen := make(map[int]struct{}, len(inLR)+len(c.O))
+		for wID := range inLR {
+			seen[wID] = struct{}{}
+			all = append(all, wID)
+		}
+		for _, t := range c.O {
+			wID := t.WireID()
+			if _, ok := seen[wID]; ok {
+				continue
+			}
+			seen[wID] = struct{}{}
+			all = append(all, wID)
+			if t.CoeffID() != CoeffIdZero && !pinned[wID] {
+				candidates = append(candidates, wID)
+			}
+		}
+		return
+	}
+
+	removed := r1cs.findDeadConstraints(len(r1cs.Constraints), nbWires, func(cID int) ([]int, []int) {
+		return wires(&r1cs.Constraints[cID])
+	})
+	if removed == nil {
+		return 0
+	}
+
+	// the linear expressions of the constraints may share their memory, they are
+	// copied so that each term is renumbered once.
+	r1cs.ownConstraints()
+	remap, nbDead := r1cs.removeWires(nbWires, removed.wires)
+	for i := range r1cs.Constraints {
+		remapLinearExpression(r1cs.Constraints[i].L, remap)
+		remapLinearExpression(r1cs.Constraints[i].R, remap)
+		remapLinearExpression(r1cs.Constraints[i].O, remap)
+	}
+
+	kept := r1cs.Constraints[:0]
+	for cID := range r1cs.Constraints {
+		if !removed.constraints[cID] {
+			kept = append(kept, r1cs.Constraints[cID])
+		}
+	}
+	r1cs.Constraints = kept
+	r1cs.remapDebugInfo(removed.constraints)
+
+	r1cs.resetLevels()
+	for cID := range r1cs.Constraints {
+		r1cs.updateLevel(cID, &r1cs.Constraints[cID])
+	}
+
+	return nbDead
+}
+
+// RemoveDeadWires removes the internal wires which are not used by the circuit, along with the
+// constraints defining them. Remaining internal wires and constraints are renumbered.
+//
+// It returns the number of removed wires.
+func (cs *SparseR1CSCore) RemoveDeadWires() int {
+	nbWires := cs.NbInternalVariables + cs.GetNbPublicVariables() + cs.GetNbSecretVariables()
+	pinned := cs.pinnedWires(nbWires)
+
+	wires := func(c *SparseR1C) (all, candidates []int) {
+		all = append(all, c.L.WireID())
+		if r := c.R.WireID(); r != c.L.WireID() {
+			all = append(all, r)
+		}
+		o := c.O.WireID()
+		if o == c.L.WireID() || o == c.R.WireID() {
+			return
+		}
+		all = append(all, o)
+		if c.O.CoeffID() != CoeffIdZero && !pinned[o] {
+			candidates = append(candidates, o)
+		}
+		return
+	}
+
+	removed := cs.findDeadConstraints(len(cs.Constraints), nbWires, func(cID int) ([]int, []int) {
+		return wires(&cs.Constraints[cID])
+	})
+	if removed == nil {
+		return 0
+	}
+
+	// the constraints may be backed by a read-only memory mapping.
+	cs.ownConstraints()
+	remap, nbDead := cs.removeWires(nbWires, removed.wires)
+	remapTerm := func(t *Term) {
+		if !t.IsConstant() {
+			t.VID = uint32(remap[t.VID])
+		}
+	}
+	for i := range cs.Constraints {
+		c := &cs.Constraints[i]
+		remapTerm(&c.L)
+		remapTerm(&c.R)
+		remapTerm(&c.O)
+		remapTerm(&c.M[0])
+		remapTerm(&c.M[1])
+	}
+
+	kept := cs.Constraints[:0]
+	for cID := range cs.Constraints {
+		if !removed.constraints[cID] {
+			kept = append(kept, cs.Constraints[cID])
+		}
+	}
+	cs.Constraints = kept
+	cs.remapDebugInfo(removed.constraints)
+
+	cs.resetLevels()
+	for cID := range cs.Constraints {
+		cs.updateLevel(cID, &cs.Constraints[cID])
+	}
+
+	return nbDead
+}
+
+// ownConstraints copies the linear expressions of the constraints, so that no
+// two of them share memory.
+func (r1cs *R1CSCore) ownConstraints() {
+	nbTerms := 0
+	for i := range r1cs.Constraints {
+		c := &r1cs.Constraints[i]
+		nbTerms += len(c.L) + len(c.R) + len(c.O)
+	}
+	terms := make([]Term, 0, nbTerms)
+	own := func(l LinearExpression) LinearExpression {
+		if l == nil {
+			return nil
+		}
+		terms = append(terms, l...)
+		return terms[len(terms)-len(l) : len(terms) : len(terms)]
+	}
+	for i := range r1cs.Constraints {
+		c := &r1cs.Constraints[i]
+		c.L, c.R, c.O = own(c.L), own(c.R), own(c.O)
+	}
+}
+
+type deadConstraints struct {
+	constraints []bool // constraints to remove
+	wires       []bool // wires to remove
+}
+
+// findDeadConstraints iterates over the constraints until no more dead wire is found.
+// wires returns the distinct wires of a constraint and the wires it may solve.
+// It returns nil if nothing can be removed.
+func (system *System) findDeadConstraints(nbConstraints, nbWires int, wires func(cID int) ([]int, []int)) *deadConstraints {
+	nbInputs := system.GetNbPublicVariables() + system.GetNbSecretVariables()
+
+	refs := make([]int, nbWires)
+	for cID := 0; cID < nbConstraints; cID++ {
+		all, _ := wires(cID)
+		for _, wID := range all {
+			refs[wID]++
+		}
+	}
+
+	res := deadConstraints{
+		constraints: make([]bool, nbConstraints),
+		wires:       make([]bool, nbWires),
+	}
+	found := false
+	for changed := true; changed; {
+		changed = false
+		for cID := nbConstraints - 1; cID >= 0; cID-- {
+			if res.constraints[cID] {
+				continue
+			}
+			all, candidates := wires(cID)
+			candidate := -1
+			for _, wID := range candidates {
+				if wID < nbInputs || refs[wID] != 1 {
+					continue
+				}
+				if candidate != -1 {
+					// at most one unsolved wire per constraint, this one is not trivial.
+					candidate = -1
+					break
+				}
+				candidate = wID
+			}
+			if candidate == -1 || system.solvesHint(all, refs) {
+				continue
+			}
+			res.constraints[cID] = true
+			res.wires[candidate] = true
+			for _, wID := range all {
+				refs[wID]--
+			}
+			changed, found = true, true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &res
+}
+
+// solvesHint returns true if the constraint is the only one referencing the output of a hint,
+// in which case it must be kept for the solver to run the hint.
+func (system *System) solvesHint(wires []int, refs []int) bool {
+	for _, wID := range wires {
+		if _, ok := system.MHints[wID]; ok && refs[wID] == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// pinnedWires marks the wires which must be kept whether they appear in constraints or not:
+// hint inputs and outputs, logged values and committed values.
+func (system *System) pinnedWires(nbWires int) []bool {
+	pinned := make([]bool, nbWires)
+	pin := func(l LinearExpression) {
+		for _, t := range l {
+			if !t.IsConstant() {
+				pinned[t.WireID()] = true
+			}
+		}
+	}
+	for _, h := range system.HintMappings {
+		for _, in := range h.Inputs {
+			pin(in)
+		}
+		for _, wID := range h.Outputs {
+			pinned[wID] = true
+		}
+	}
+	for _, l := range system.Logs {
+		for _, le := range l.linearExpressions() {
+			pin(le)
+		}
+	}
+	for _, wID := range system.CommitmentInfo.CommittedAndCommitment {
+		pinned[wID] = true
+	}
+	for _, wID := range system.CommitmentInfo.Committed {
+		pinned[wID] = true
+	}
+	if system.CommitmentInfo.Is() {
+		pinned[system.CommitmentInfo.CommitmentIndex] = true
+	}
+	return pinned
+}
+
+// removeWires drops the dead internal wires and renumbers the remaining ones in the
+// hints, logs, debug info and commitment. It returns the old wire ID → new wire ID table.
+func (system *System) removeWires(nbWires int, dead []bool) (remap []int, nbDead int) {
+	remap = make([]int, nbWires)
+	for wID := range remap {
+		if dead[wID] {
+			remap[wID] = -1
+			nbDead++
+			continue
+		}
+		remap[wID] = wID - nbDead
+	}
+
+	for i := range system.HintMappings {
+		h := &system.HintMappings[i]
+		for _, in := range h.Inputs {
+			remapLinearExpression(in, remap)
+		}
+		for j := range h.Outputs {
+			h.Outputs[j] = remap[h.Outputs[j]]
+		}
+	}
+	mHints := make(map[int]int, len(system.MHints))
+	for wID, hID := range system.MHints {
+		mHints[remap[wID]] = hID
+	}
+	system.MHints = mHints
+
+	for i := range system.Logs {
+		for _, le := range system.Logs[i].linearExpressions() {
+			remapLinearExpression(le, remap)
+		}
+	}
+	// debug info attached to removed constraints may still reference dead wires,
+	// these are never displayed and are kept as constants to preserve the format.
+	for i := range system.DebugInfo {
+		for _, le := range system.DebugInfo[i].ToResolve {
+			for j := range le {
+				if le[j].IsConstant() {
+					continue
+				}
+				if remap[le[j].VID] == -1 {
+					le[j].MarkConstant()
+					continue
+				}
+				le[j].VID = uint32(remap[le[j].VID])
+			}
+		}
+	}
+
+	c := &system.CommitmentInfo
+	for i := range c.Committed {
+		c.Committed[i] = remap[c.Committed[i]]
+	}
+	for i := range c.CommittedAndCommitment {
+		c.CommittedAndCommitment[i] = remap[c.CommittedAndCommitment[i]]
+	}
+	if c.Is() {
+		c.CommitmentIndex = remap[c.CommitmentIndex]
+	}
+
+	system.NbInternalVariables -= nbDead
+	return
+}
+
+// remapDebugInfo updates the constraint ID → debug info and scope tables once the removed
+// constraints have been filtered out.
+func (system *System) remapDebugInfo(removed []bool) {
+	newID := make([]int, len(removed))
+	n := 0
+	for cID := range removed {
+		newID[cID] = n
+		if !removed[cID] {
+			n++
+		}
+	}
+	mDebug := make(map[int]int, len(system.MDebug))
+	for cID, dID := range system.MDebug {
+		if cID < len(removed) && removed[cID] {
+			continue
+		}
+		mDebug[newID[cID]] = dID
+	}
+	system.MDebug = mDebug
+
+	scopes := system.Scopes
+	system.Scopes = nil
+	for _, r := range scopes {
+		start := n
+		if r.Start < len(removed) {
+			start = newID[r.Start]
+		}
+		system.addScopeRange(start, r.Scope)
+	}
+}
+
+// resetLevels clears the levels and the level builder state, so that they can be
+// computed again from the constraints.
+func (system *System) resetLevels() {
+	system.Levels = nil
+	system.lbWireLevel = nil
+	system.lbOutputs = system.lbOutputs[:0]
+	system.lbHints = map[int]struct{}{}
+}
+
+func remapLinearExpression(l LinearExpression, remap []int) {
+	for i := range l {
+		if !l[i].IsConstant() {
+			l[i].VID = uint32(remap[l[i].VID])
+		}
+	}
+}
@@ -35,6 +35,13 @@ type R1CS interface {
 	// See StringBuilder for more info.
 	// ! this is an experimental API.
 	GetConstraints() ([]R1C, Resolver)
+
+	// GetCoefficient returns coefficient with given id in the coeff table.
+	GetCoefficient(i int) Coeff
+
+	// RemoveDeadWires removes the internal wires which are never used, along with the constraints
+	// defining them, and returns the number of removed wires.
+	RemoveDeadWires() int
 }
 
 // R1CS describes a set of R1C constraint
@@ -144,6 +151,45 @@ func (r1cs *R1CSCore) CheckUnconstrainedWires() error {
 	return errors.New(sbb.String())
 }
 
+// UnconstrainedInputs implements ConstraintSystem. The constant wire (public
+// wire 0) is never reported.
+func (r1cs *R1CSCore) UnconstrainedInputs() (public, secret []string) {
+	inputConstrained := make([]bool, r1cs.GetNbSecretVariables()+r1cs.GetNbPublicVariables())
+	if len(inputConstrained) == 0 {
+		return nil, nil
+	}
+	inputConstrained[0] = true
+	mark := func(l LinearExpression) {
+		for _, t := range l {
+			if t.CoeffID() != CoeffIdZero && t.WireID() < len(inputConstrained) {
+				inputConstrained[t.WireID()] = true
+			}
+		}
+	}
+	for _, r1c := range r1cs.Constraints {
+		mark(r1c.L)
+		mark(r1c.R)
+		mark(r1c.O)
+	}
+	return unconstrainedNames(inputConstrained, r1cs.Public, r1cs.Secret)
+}
+
+// unconstrainedNames returns the names of the inputs which aren't constrained,
+// the public ones preceding the secret ones in the wires.
+func unconstrainedNames(inputConstrained []bool, publicNames, secretNames []string) (public, secret []string) {
+	for i, constrained := range inputConstrained {
+		if constrained {
+			continue
+		}
+		if i < len(publicNames) {
+			public = append(public, publicNames[i])
+		} else {
+			secret = append(secret, secretNames[i-len(publicNames)])
+		}
+	}
+	return
+}
+
 // R1C used to compute the wires
 type R1C struct {
 	L, R, O LinearExpression
@@ -40,6 +40,10 @@ type SparseR1CS interface {
 	// See StringBuilder for more info.
 	// ! this is an experimental API.
 	GetConstraints() ([]SparseR1C, Resolver)
+
+	// RemoveDeadWires removes the internal wires which are never used, along with the constraints
+	// defining them, and returns the number of removed wires.
+	RemoveDeadWires() int
 }
 
 // R1CS describes a set of SparseR1C constraint
@@ -47,6 +51,10 @@ type SparseR1CS interface {
 type SparseR1CSCore struct {
 	System
 	Constraints []SparseR1C
+
+	// mapped is set when the constraints are backed by a read-only memory
+	// mapping, see ReadMmapSparseR1CS.
+	mapped bool
 }
 
 // GetNbConstraints returns the number of constraints
@@ -136,6 +144,24 @@ func (system *SparseR1CSCore) CheckUnconstrainedWires() error {
 	return errors.New(sbb.String())
 }
 
+// UnconstrainedInputs implements ConstraintSystem.
+func (system *SparseR1CSCore) UnconstrainedInputs() (public, secret []string) {
+	inputConstrained := make([]bool, system.GetNbSecretVariables()+system.GetNbPublicVariables())
+	mark := func(t Term) {
+		if t.CoeffID() != CoeffIdZero && t.WireID() < len(inputConstrained) {
+			inputConstrained[t.WireID()] = true
+		}
+	}
+	for _, c := range system.Constraints {
+		mark(c.L)
+		mark(c.R)
+		mark(c.M[0])
+		mark(c.M[1])
+		mark(c.O)
+	}
+	return unconstrainedNames(inputConstrained, system.Public, system.Secret)
+}
+
 // SparseR1C used to compute the wires
 // L+R+M[0]M[1]+O+k=0
 // if a Term is zero, it means the field doesn't exist (ex M=[0,0] means there is no multiplicative term)
@@ -0,0 +1,67 @@
+package constraint
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/profile"
+)
+
+// ScopeRange attributes the constraints with ID greater or equal to Start, up to
+// the start of the next range, to the scope Scope.
+type ScopeRange struct {
+	Start int
+	Scope string // path of nested scope names, separated by '/'. Empty outside any scope.
+}
+
+// PushScope enters the scope name, nested in the current scope. nextCID is the
+// ID of the next constraint to be added to the system.
+func (system *System) PushScope(name string, nextCID int) {
+	system.scopeStack = append(system.scopeStack, name)
+	system.setScope(nextCID)
+}
+
+// PopScope leaves the current scope. nextCID is the ID of the next constraint
+// to be added to the system.
+func (system *System) PopScope(nextCID int) error {
+	if len(system.scopeStack) == 0 {
+		return errors.New("no scope to pop")
+	}
+	system.scopeStack = system.scopeStack[:len(system.scopeStack)-1]
+	system.setScope(nextCID)
+	return nil
+}
+
+// CurrentScope returns the path of the current scope, empty outside any scope.
+func (system *System) CurrentScope() string {
+	return strings.Join(system.scopeStack, "/")
+}
+
+// GetConstraintScope returns the path of the scope in which the constraint
+// cID was added, empty if it was added outside any scope.
+func (system *System) GetConstraintScope(cID int) string {
+	i := sort.Search(len(system.Scopes), func(i int) bool { return system.Scopes[i].Start > cID })
+	if i == 0 {
+		return ""
+	}
+	return system.Scopes[i-1].Scope
+}
+
+func (system *System) setScope(nextCID int) {
+	scope := system.CurrentScope()
+	profile.SetScope(scope)
+	system.addScopeRange(nextCID, scope)
+}
+
+// addScopeRange attributes the constraints from start on to scope. start must be
+// greater or equal to the start of the last range.
+func (system *System) addScopeRange(start int, scope string) {
+	// consecutive scope changes without constraints in between overwrite each other
+	if n := len(system.Scopes); n != 0 && system.Scopes[n-1].Start == start {
+		system.Scopes = system.Scopes[:n-1]
+	}
+	if system.GetConstraintScope(start) != scope {
+		system.Scopes = append(system.Scopes, ScopeRange{Start: start, Scope: scope})
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constraint
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Constraints are serialized in chunks, so that writing and reading a constraint system
+// only needs a bounded amount of memory on top of the constraint system itself, and
+// reading can decode the chunks in parallel.
+//
+// The format is
+//
+//	nbConstraints uint64
+//	chunk*
+//
+// and each chunk is
+//
+//	nbConstraints uint32 | nbBytes uint32 | bytes
+//
+// with all integers big-endian encoded.
+
+// ConstraintChunkSize is the number of constraints per serialized chunk.
+const ConstraintChunkSize = 1 << 14
+
+// WriteR1Cs writes the R1C constraints to w in chunks.
+func WriteR1Cs(w io.Writer, constraints []R1C) (int64, error) {
+	return writeChunks(w, constraints, encodeR1C)
+}
+
+// ReadR1Cs reads R1C constraints written by WriteR1Cs, decoding the chunks in parallel.
+func ReadR1Cs(r io.Reader) ([]R1C, int64, error) {
+	return readChunks(r, decodeR1C)
+}
+
+// WriteSparseR1Cs writes the SparseR1C constraints to w in chunks.
+func WriteSparseR1Cs(w io.Writer, constraints []SparseR1C) (int64, error) {
+	return writeChunks(w, constraints, encodeSparseR1C)
+}
+
+// ReadSparseR1Cs reads SparseR1C constraints written by WriteSparseR1Cs, decoding the chunks in parallel.
+func ReadSparseR1Cs(r io.Reader) ([]SparseR1C, int64, error) {
+	return readChunks(r, decodeSparseR1C)
+}
+
+var errInvalidChunk = errors.New("invalid constraint chunk")
+
+// readBufferSize is the size of the pieces in which a chunk is read: chunks
+// announcing more bytes than the stream holds fail after at most this many bytes
+// are allocated in excess.
+const readBufferSize = 1 << 20
+
+func writeChunks[T any](w io.Writer, constraints []T, encode func([]byte, *T) []byte) (int64, error) {
+	var n int64
+	var header [8]byte
+	binary.BigEndian.PutUint64(header[:], uint64(len(constraints)))
+	m, err := w.Write(header[:])
+	n += int64(m)
+	if err != nil {
+		return n, err
+	}
+
+	var buf []byte
+	for start := 0; start < len(constraints); start += ConstraintChunkSize {
+		end := start + ConstraintChunkSize
+		if end > len(constraints) {
+			end = len(constraints)
+		}
+		buf = buf[:0]
+		buf = appendUint32(buf, uint32(end-start))
+		buf = appendUint32(buf, 0) // placeholder for the chunk length
+		for i := start; i < end; i++ {
+			buf = encode(buf, &constraints[i])
+		}
+		if uint64(len(buf)-8) > math.MaxUint32 {
+			return n, fmt.Errorf("constraints %d to %d are encoded on %d bytes, more than the %d bytes of a chunk", start, end-1, len(buf)-8, uint64(math.MaxUint32))
+		}
+		binary.BigEndian.PutUint32(buf[4:8], uint32(len(buf)-8))
+
+		m, err := w.Write(buf)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func readChunks[T any](r io.Reader, decode func([]byte, []T) error) ([]T, int64, error) {
+	var n int64
+	var header [8]byte
+	m, err := io.ReadFull(r, header[:])
+	n += int64(m)
+	if err != nil {
+		return nil, n, err
+	}
+	// the header can't be trusted to size allocations: the constraints grow
+	// with the chunks actually read, up to the announced number.
+	nbConstraints := binary.BigEndian.Uint64(header[:])
+
+	// chunks are read sequentially and decoded concurrently in place; the number
+	// of chunks in flight is bounded to bound memory usage.
+	var (
+		wg     sync.WaitGroup
+		errMu  sync.Mutex
+		errDec error
+	)
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	var constraints []T
+	chunkID := 0
+	for uint64(len(constraints)) < nbConstraints {
+		var chunkHeader [8]byte
+		m, err := io.ReadFull(r, chunkHeader[:])
+		n += int64(m)
+		if err != nil {
+			wg.Wait()
+			return nil, n, err
+		}
+		nb := binary.BigEndian.Uint32(chunkHeader[:4])
+		nbBytes := binary.BigEndian.Uint32(chunkHeader[4:])
+		if nb == 0 || nb > ConstraintChunkSize || uint64(nb) > nbConstraints-uint64(len(constraints)) {
+			wg.Wait()
+			return nil, n, errInvalidChunk
+		}
+
+		buf, m64, err := readBytes(r, nbBytes)
+		n += m64
+		if err != nil {
+			wg.Wait()
+			return nil, n, err
+		}
+
+		start := len(constraints)
+		if start+int(nb) > cap(constraints) {
+			// growing moves the constraints: the chunks being decoded must be done
+			wg.Wait()
+			size := 2 * cap(constraints)
+			if size < start+int(nb) {
+				size = start + int(nb)
+			}
+			if uint64(size) > nbConstraints {
+				size = int(nbConstraints)
+			}
+			grown := make([]T, start, size)
+			copy(grown, constraints)
+			constraints = grown
+		}
+		constraints = constraints[:start+int(nb)]
+		chunk := constraints[start:]
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(chunkID int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := decode(buf, chunk); err != nil {
+				errMu.Lock()
+				if errDec == nil {
+					errDec = fmt.Errorf("chunk %d: %w", chunkID, err)
+				}
+				errMu.Unlock()
+			}
+		}(chunkID)
+		chunkID++
+	}
+	wg.Wait()
+	if errDec != nil {
+		return nil, n, errDec
+	}
+	return constraints, n, nil
+}
+
+// CheckTerms returns an error if a term of the constraints refers to a
+// coefficient out of the nbCoefficients coefficients, or to a wire of the
+// system which doesn't exist. Constraints read from an untrusted source would
+// otherwise make the solver and the backends panic.
+func (r1cs *R1CSCore) CheckTerms(nbCoefficients int) error {
+	nbWires := r1cs.nbWires()
+	for i := range r1cs.Constraints {
+		c := &r1cs.Constraints[i]
+		for _, l := range [3]LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				if err := checkTerm(t, nbCoefficients, nbWires); err != nil {
+					return fmt.Errorf("constraint %d: %w", i, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckTerms returns an error if a term of the constraints refers to a
+// coefficient out of the nbCoefficients coefficients, or to a wire of the
+// system which doesn't exist (see R1CSCore.CheckTerms).
+func (cs *SparseR1CSCore) CheckTerms(nbCoefficients int) error {
+	nbWires := cs.nbWires()
+	for i := range cs.Constraints {
+		c := &cs.Constraints[i]
+		for _, t := range [5]Term{c.L, c.R, c.O, c.M[0], c.M[1]} {
+			if err := checkTerm(t, nbCoefficients, nbWires); err != nil {
+				return fmt.Errorf("constraint %d: %w", i, err)
+			}
+		}
+		if c.K < 0 || c.K >= nbCoefficients {
+			return fmt.Errorf("constraint %d: constant term refers to coefficient %d of %d", i, c.K, nbCoefficients)
+		}
+	}
+	return nil
+}
+
+// nbWires returns the number of wires of the system
+func (system *System) nbWires() int {
+	return system.NbInternalVariables + system.GetNbPublicVariables() + system.GetNbSecretVariables()
+}
+
+func checkTerm(t Term, nbCoefficients, nbWires int) error {
+	if int64(t.CID) >= int64(nbCoefficients) {
+		return fmt.Errorf("term refers to coefficient %d of %d", t.CID, nbCoefficients)
+	}
+	if !t.IsConstant() && int64(t.VID) >= int64(nbWires) {
+		return fmt.Errorf("term refers to wire %d of %d", t.VID, nbWires)
+	}
+	return nil
+}
+
+// readBytes reads exactly nbBytes from r, allocating as the bytes are read
+func readBytes(r io.Reader, nbBytes uint32) ([]byte, int64, error) {
+	var buf []byte
+	var n int64
+	for remaining := int(nbBytes); remaining > 0; {
+		size := remaining
+		if size > readBufferSize {
+			size = readBufferSize
+		}
+		buf = append(buf, make([]byte, size)...)
+		m, err := io.ReadFull(r, buf[len(buf)-size:])
+		n += int64(m)
+		if err != nil {
+			return nil, n, err
+		}
+		remaining -= size
+	}
+	return buf, n, nil
+}
+
+func encodeR1C(buf []byte, c *R1C) []byte {
+	buf = appendUint32(buf, uint32(len(c.L)))
+	buf = appendUint32(buf, uint32(len(c.R)))
+	buf = appendUint32(buf, uint32(len(c.O)))
+	for _, l := range [3]LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			buf = appendTerm(buf, t)
+		}
+	}
+	return buf
+}
+
+func decodeR1C(buf []byte, constraints []R1C) error {
+	// first pass to allocate all the terms of the chunk at once
+	nbTerms := 0
+	for i, b := 0, buf; i < len(constraints); i++ {
+		if len(b) < 12 {
+			return errInvalidChunk
+		}
+		// computed on 64 bits, so that it can't overflow on 32-bit platforms;
+		// once bounded by len(b) it fits in an int.
+		n := uint64(binary.BigEndian.Uint32(b[0:4])) + uint64(binary.BigEndian.Uint32(b[4:8])) + uint64(binary.BigEndian.Uint32(b[8:12]))
+		if uint64(len(b)) < 12+8*n {
+			return errInvalidChunk
+		}
+		nbTerms += int(n)
+		b = b[12+8*n:]
+	}
+	terms := make([]Term, nbTerms)
+
+	next := func(n int) LinearExpression {
+		if n == 0 {
+			return nil
+		}
+		l := LinearExpression(terms[:n:n])
+		terms = terms[n:]
+		for i := range l {
+			l[i], buf = readTerm(buf)
+		}
+		return l
+	}
+	for i := range constraints {
+		nL := int(binary.BigEndian.Uint32(buf[0:4]))
+		nR := int(binary.BigEndian.Uint32(buf[4:8]))
+		nO := int(binary.BigEndian.Uint32(buf[8:12]))
+		buf = buf[12:]
+		constraints[i].L = next(nL)
+		constraints[i].R = next(nR)
+		constraints[i].O = next(nO)
+	}
+	if len(buf) != 0 {
+		return errInvalidChunk
+	}
+	return nil
+}
+
+const sparseR1CSize = 5*8 + 4
+
+func encodeSparseR1C(buf []byte, c *SparseR1C) []byte {
+	buf = appendTerm(buf, c.L)
+	buf = appendTerm(buf, c.R)
+	buf = appendTerm(buf, c.O)
+	buf = appendTerm(buf, c.M[0])
+	buf = appendTerm(buf, c.M[1])
+	return appendUint32(buf, uint32(c.K))
+}
+
+func decodeSparseR1C(buf []byte, constraints []SparseR1C) error {
+	if len(buf) != len(constraints)*sparseR1CSize {
+		return errInvalidChunk
+	}
+	for i := range constraints {
+		c := &constraints[i]
+		c.L, buf = readTerm(buf)
+		c.R, buf = readTerm(buf)
+		c.O, buf = readTerm(buf)
+		c.M[0], buf = readTerm(buf)
+		c.M[1], buf = readTerm(buf)
+		c.K = int(binary.BigEndian.Uint32(buf[:4]))
+		buf = buf[4:]
+	}
+	return nil
+}
+
+func appendTerm(buf []byte, t Term) []byte {
+	buf = appendUint32(buf, t.CID)
+	return appendUint32(buf, t.VID)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func readTerm(buf []byte) (Term, []byte) {
+	return Term{CID: binary.BigEndian.Uint32(buf[:4]), VID: binary.BigEndian.Uint32(buf[4:8])}, buf[8:]
+}
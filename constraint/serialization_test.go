@@ -0,0 +1,179 @@
+package constraint_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/stretchr/testify/require"
+)
+
+func randomR1Cs(rng *rand.Rand, n int) []constraint.R1C {
+	randomLE := func() constraint.LinearExpression {
+		l := make(constraint.LinearExpression, rng.Intn(4))
+		for i := range l {
+			l[i] = constraint.Term{CID: rng.Uint32(), VID: rng.Uint32()}
+		}
+		return l
+	}
+	res := make([]constraint.R1C, n)
+	for i := range res {
+		res[i] = constraint.R1C{L: randomLE(), R: randomLE(), O: randomLE()}
+		// the reader decodes empty linear expressions as nil
+		for _, l := range []*constraint.LinearExpression{&res[i].L, &res[i].R, &res[i].O} {
+			if len(*l) == 0 {
+				*l = nil
+			}
+		}
+	}
+	return res
+}
+
+func randomSparseR1Cs(rng *rand.Rand, n int) []constraint.SparseR1C {
+	term := func() constraint.Term { return constraint.Term{CID: rng.Uint32(), VID: rng.Uint32()} }
+	res := make([]constraint.SparseR1C, n)
+	for i := range res {
+		res[i] = constraint.SparseR1C{L: term(), R: term(), O: term(), M: [2]constraint.Term{term(), term()}, K: int(rng.Uint32())}
+	}
+	return res
+}
+
+func TestConstraintChunksRoundTrip(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, constraint.ConstraintChunkSize, 2*constraint.ConstraintChunkSize + 3} {
+		r1cs := randomR1Cs(rng, n)
+		var buf bytes.Buffer
+		written, err := constraint.WriteR1Cs(&buf, r1cs)
+		assert.NoError(err)
+		read, nRead, err := constraint.ReadR1Cs(&buf)
+		assert.NoError(err)
+		assert.Equal(written, nRead)
+		assert.Equal(len(r1cs), len(read))
+		if n > 0 {
+			assert.Equal(r1cs, read)
+		}
+
+		sparse := randomSparseR1Cs(rng, n)
+		buf.Reset()
+		written, err = constraint.WriteSparseR1Cs(&buf, sparse)
+		assert.NoError(err)
+		readSparse, nRead, err := constraint.ReadSparseR1Cs(&buf)
+		assert.NoError(err)
+		assert.Equal(written, nRead)
+		assert.Equal(len(sparse), len(readSparse))
+		if n > 0 {
+			assert.Equal(sparse, readSparse)
+		}
+	}
+}
+
+func TestConstraintChunksTruncated(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(2))
+
+	var buf bytes.Buffer
+	_, err := constraint.WriteR1Cs(&buf, randomR1Cs(rng, constraint.ConstraintChunkSize+10))
+	assert.NoError(err)
+	data := buf.Bytes()
+
+	for _, size := range []int{0, 4, 8, 12, 16, 100, len(data) / 2, len(data) - 1} {
+		_, _, err := constraint.ReadR1Cs(bytes.NewReader(data[:size]))
+		assert.Error(err, "truncated to %d bytes", size)
+	}
+}
+
+func TestConstraintChunksCorrupted(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(3))
+
+	var buf bytes.Buffer
+	_, err := constraint.WriteR1Cs(&buf, randomR1Cs(rng, 100))
+	assert.NoError(err)
+	data := buf.Bytes()
+
+	// corrupted bytes must be rejected, or decoded without panicking
+	for i := 0; i < 1000; i++ {
+		corrupted := append([]byte(nil), data...)
+		corrupted[rng.Intn(len(corrupted))] ^= byte(1 + rng.Intn(255))
+		assert.NotPanics(func() {
+			_, _, _ = constraint.ReadR1Cs(bytes.NewReader(corrupted))
+		})
+	}
+}
+
+func TestConstraintChunksBoundedAllocations(t *testing.T) {
+	assert := require.New(t)
+
+	// allocated returns the number of bytes allocated by f
+	allocated := func(f func()) uint64 {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		f()
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+	const bound = 16 << 20
+
+	// a header announcing a huge number of constraints, without any chunk
+	var header [8]byte
+	binary.BigEndian.PutUint64(header[:], 1<<40)
+	assert.Less(allocated(func() {
+		_, _, err := constraint.ReadR1Cs(bytes.NewReader(header[:]))
+		assert.ErrorIs(err, io.EOF)
+	}), uint64(bound))
+
+	// a chunk announcing 4GiB of data, followed by a few bytes
+	data := append(header[:], 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff)
+	data = append(data, make([]byte, 100)...)
+	assert.Less(allocated(func() {
+		_, _, err := constraint.ReadSparseR1Cs(bytes.NewReader(data))
+		assert.ErrorIs(err, io.ErrUnexpectedEOF)
+	}), uint64(bound))
+}
+
+func TestCheckTerms(t *testing.T) {
+	assert := require.New(t)
+
+	// 3 coefficients and 4 wires
+	const nbCoefficients = 3
+	system := constraint.System{NbInternalVariables: 2, Public: []string{"1"}, Secret: []string{"x"}}
+	var constant constraint.Term
+	constant.MarkConstant()
+
+	for _, c := range []struct {
+		name  string
+		term  constraint.Term
+		valid bool
+	}{
+		{"last coefficient and wire", constraint.Term{CID: 2, VID: 3}, true},
+		{"constant", constant, true},
+		{"coefficient out of the table", constraint.Term{CID: 3, VID: 0}, false},
+		{"wire out of the system", constraint.Term{CID: 0, VID: 4}, false},
+		{"huge coefficient", constraint.Term{CID: ^uint32(0), VID: 0}, false},
+	} {
+		r1cs := constraint.R1CSCore{System: system, Constraints: []constraint.R1C{{L: constraint.LinearExpression{c.term}}}}
+		sparse := constraint.SparseR1CSCore{System: system, Constraints: []constraint.SparseR1C{{O: c.term}}}
+		for _, err := range []error{r1cs.CheckTerms(nbCoefficients), sparse.CheckTerms(nbCoefficients)} {
+			if c.valid {
+				assert.NoError(err, c.name)
+			} else {
+				assert.Error(err, c.name)
+			}
+		}
+	}
+
+	// the constant term of the sparse constraints is a coefficient
+	sparse := constraint.SparseR1CSCore{System: system, Constraints: []constraint.SparseR1C{{K: nbCoefficients}}}
+	assert.Error(sparse.CheckTerms(nbCoefficients))
+
+	// a corrupted number of wires doesn't let any wire through
+	system.NbInternalVariables = -10
+	r1cs := constraint.R1CSCore{System: system, Constraints: []constraint.R1C{{L: constraint.LinearExpression{{CID: 0, VID: 0}}}}}
+	assert.Error(r1cs.CheckTerms(nbCoefficients))
+}
@@ -0,0 +1,161 @@
+package solver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// checkpointMagic starts the files written by WithCheckpoint
+var checkpointMagic = [8]byte{'g', 'n', 'a', 'r', 'k', 'c', 'k', 'p'}
+
+// State is the state of a solver saved in the checkpoints (see WithCheckpoint): the
+// constraint systems implement it on the vectors they fill while solving. ReadFrom
+// reads into the vectors in place, which have the same sizes as when they were
+// written.
+type State interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// SolveLevelsWithCheckpoints is SolveLevels with the checkpoints set in opt by
+// WithCheckpoint: it first restores state from the checkpoint file if it exists,
+// then saves it after a level when opt.CheckpointInterval has elapsed since the last
+// save. id identifies the constraint system and its witness; a checkpoint saved with
+// another id is ignored. The constraints solved are counted in opt.Report, if set
+// by WithReport.
+func SolveLevelsWithCheckpoints(levels [][]int, opt Config, id []byte, state State, solve func(i int) error) error {
+	if opt.Report != nil {
+		solve = opt.Report.countConstraints(solve)
+	}
+	if opt.CheckpointPath == "" {
+		return SolveLevels(levels, opt.NbTasks, solve)
+	}
+
+	start, err := restoreCheckpoint(opt, id, state)
+	if err != nil {
+		return fmt.Errorf("restore checkpoint: %w", err)
+	}
+	if start > len(levels) {
+		return fmt.Errorf("restore checkpoint: level %d out of range", start)
+	}
+
+	last := time.Now()
+	err = solveLevels(levels, start, opt.NbTasks, solve, func(l int) error {
+		if l == len(levels)-1 || time.Since(last) < opt.CheckpointInterval {
+			return nil
+		}
+		if err := saveCheckpoint(opt.CheckpointPath, id, l+1, state); err != nil {
+			return fmt.Errorf("save checkpoint: %w", err)
+		}
+		opt.Logger.Debug().Int("level", l+1).Msg("solver checkpoint saved")
+		last = time.Now()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(opt.CheckpointPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// restoreCheckpoint reads state from the checkpoint file and returns the level to
+// solve next, 0 if there is no checkpoint for id.
+func restoreCheckpoint(opt Config, id []byte, state State) (int, error) {
+	f, err := os.Open(opt.CheckpointPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	var magic [len(checkpointMagic)]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return 0, err
+	}
+	if magic != checkpointMagic {
+		return 0, errors.New("not a solver checkpoint")
+	}
+	var idLen uint32
+	if err := binary.Read(r, binary.BigEndian, &idLen); err != nil {
+		return 0, err
+	}
+	if idLen != uint32(len(id)) {
+		opt.Logger.Warn().Str("path", opt.CheckpointPath).Msg("ignoring the checkpoint of another constraint system or witness")
+		return 0, nil
+	}
+	savedID := make([]byte, idLen)
+	if _, err := io.ReadFull(r, savedID); err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(savedID, id) {
+		opt.Logger.Warn().Str("path", opt.CheckpointPath).Msg("ignoring the checkpoint of another constraint system or witness")
+		return 0, nil
+	}
+	var level uint64
+	if err := binary.Read(r, binary.BigEndian, &level); err != nil {
+		return 0, err
+	}
+	if _, err := state.ReadFrom(r); err != nil {
+		return 0, err
+	}
+
+	opt.Logger.Info().Uint64("level", level).Msg("solver resumed from checkpoint")
+	return int(level), nil
+}
+
+// saveCheckpoint writes state and the level to solve next to a temporary file,
+// renamed to path once complete so that an interrupted save keeps the previous
+// checkpoint.
+func saveCheckpoint(path string, id []byte, level int, state State) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	w := bufio.NewWriter(f)
+	err = writeCheckpoint(w, id, level, state)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeCheckpoint(w io.Writer, id []byte, level int, state State) error {
+	if _, err := w.Write(checkpointMagic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(id))); err != nil {
+		return err
+	}
+	if _, err := w.Write(id); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint64(level)); err != nil {
+		return err
+	}
+	_, err := state.WriteTo(w)
+	return err
+}
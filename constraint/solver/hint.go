@@ -1,8 +1,10 @@
 package solver
 
 import (
+	"fmt"
 	"hash/fnv"
 	"math/big"
+	"strings"
 )
 
 // HintID is a unique identifier for a hint function used for lookup.
@@ -80,8 +82,9 @@ type HintID uint32
 // In the init() method of the gadget, call the method RegisterHint(hintFn) function on
 // the hint function hintFn to register a hint function in the package registry.
 type Hint struct {
-	Fn HintFn
-	ID HintID
+	Fn   HintFn
+	ID   HintID
+	Name string // human readable name, may be empty
 }
 
 // HintFn is the function that performs the hint computation.
@@ -102,8 +105,9 @@ func GetHintID(name string) HintID {
 // NewHint creates a new hint with the given name and function. It does not register the hint in the registry.
 func NewHint(name string, fn HintFn) Hint {
 	return Hint{
-		Fn: fn,
-		ID: GetHintID(name),
+		Fn:   fn,
+		ID:   GetHintID(name),
+		Name: name,
 	}
 }
 
@@ -115,3 +119,18 @@ func NewHint(name string, fn HintFn) Hint {
 	return identifier
 }
 */
+
+// MissingHintsError is returned by the solvers when some hints required by the
+// constraint system are not provided (see WithHints) nor registered (see RegisterHint).
+type MissingHintsError struct {
+	IDs   []HintID
+	Names []string // human readable names, in the same order as IDs
+}
+
+func (e *MissingHintsError) Error() string {
+	names := make([]string, len(e.IDs))
+	for i := range e.IDs {
+		names[i] = fmt.Sprintf("%s (%d)", e.Names[i], e.IDs[i])
+	}
+	return "solver missing hint(s): " + strings.Join(names, ", ")
+}
@@ -9,12 +9,13 @@ import (
 )
 
 func init() {
-	RegisterHint(Hint{Fn: InvZeroHint, ID: GetHintID("inv_zero")})
+	RegisterHint(NewHint("inv_zero", InvZeroHint))
 }
 
 var (
-	registry  = make(map[HintID]HintFn)
-	registryM sync.RWMutex
+	registry      = make(map[HintID]HintFn)
+	registryNames = make(map[HintID]string)
+	registryM     sync.RWMutex
 )
 
 // RegisterHint registers a hint function in the global registry.
@@ -28,9 +29,21 @@ func RegisterHint(hints ...Hint) {
 			return
 		}
 		registry[hint.ID] = hint.Fn
+		if hint.Name != "" {
+			registryNames[hint.ID] = hint.Name
+		}
 	}
 }
 
+// GetRegisteredHintName returns the name of the registered hint with the given ID, if
+// it was registered with a name (see NewHint).
+func GetRegisteredHintName(id HintID) (string, bool) {
+	registryM.RLock()
+	defer registryM.RUnlock()
+	name, ok := registryNames[id]
+	return name, ok
+}
+
 // GetRegisteredHints returns all registered hint functions.
 func GetRegisteredHints() map[HintID]HintFn {
 	registryM.RLock()
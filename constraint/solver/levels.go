@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minWorkPerTask is the minimum number of constraints a task should hold on
+// average. A level with at most minWorkPerTask constraints is solved
+// sequentially, as the synchronization would cost more than it saves.
+const minWorkPerTask = 50
+
+// chunksPerTask is the number of chunks the constraints of a task are split
+// into. The workers pull the chunks of a level one after the other, so that the
+// constraints which are slow to solve, typically those calling hints, are
+// spread across the workers instead of delaying the task they fall in.
+const chunksPerTask = 8
+
+// SolveLevels calls solve on the constraints of each level, level after level. All
+// the constraints of a level are independent and may only depend on the previous
+// levels (see constraint.System.Levels), so they are solved concurrently by up to
+// nbTasks goroutines. If nbTasks is not positive, runtime.NumCPU() is used.
+//
+// If solve fails on several constraints of a level, the error of the first one (in
+// the order of the level) is returned, so that the errors don't depend on the
+// scheduling.
+func SolveLevels(levels [][]int, nbTasks int, solve func(i int) error) error {
+	return solveLevels(levels, 0, nbTasks, solve, nil)
+}
+
+// solveLevels is SolveLevels starting at level start, calling done(l), if not nil,
+// after solving level l. It stops if done fails.
+func solveLevels(levels [][]int, start, nbTasks int, solve func(i int) error, done func(l int) error) error {
+	if nbTasks < 1 {
+		nbTasks = runtime.NumCPU()
+	}
+
+	var (
+		wg      sync.WaitGroup
+		chTasks chan []int // the level whose chunks the workers pull
+		chunk   int        // the number of constraints of a chunk
+		cursor  int64      // the position in the level of the next chunk
+		errLock sync.Mutex
+		errPos  int // position in the level of the constraint which failed first
+		err     error
+	)
+
+	// failedBefore returns true if a constraint before position pos failed
+	failedBefore := func(pos int) bool {
+		errLock.Lock()
+		defer errLock.Unlock()
+		return err != nil && errPos < pos
+	}
+
+	// the worker pool is started on the first level large enough to be parallelized
+	startWorkers := func() {
+		chTasks = make(chan []int, nbTasks)
+		for w := 0; w < nbTasks; w++ {
+			go func() {
+				for level := range chTasks {
+					for {
+						// the chunks after a failed constraint are skipped,
+						// the ones before are solved to find the first failure
+						start := int(atomic.AddInt64(&cursor, int64(chunk))) - chunk
+						if start >= len(level) || failedBefore(start) {
+							break
+						}
+						end := start + chunk
+						if end > len(level) {
+							end = len(level)
+						}
+						for pos := start; pos < end; pos++ {
+							if e := solve(level[pos]); e != nil {
+								errLock.Lock()
+								if err == nil || pos < errPos {
+									err, errPos = e, pos
+								}
+								errLock.Unlock()
+								break
+							}
+						}
+					}
+					wg.Done()
+				}
+			}()
+		}
+	}
+	defer func() {
+		if chTasks != nil {
+			close(chTasks)
+		}
+	}()
+
+	for l := start; l < len(levels); l++ {
+		level := levels[l]
+		n := (len(level) + minWorkPerTask - 1) / minWorkPerTask // number of tasks for this level
+		if n > nbTasks {
+			n = nbTasks
+		}
+		if n <= 1 {
+			for _, i := range level {
+				if err := solve(i); err != nil {
+					return err
+				}
+			}
+		} else {
+			if chTasks == nil {
+				startWorkers()
+			}
+			chunk = (len(level) + n*chunksPerTask - 1) / (n * chunksPerTask)
+			cursor = 0
+			wg.Add(n)
+			for t := 0; t < n; t++ {
+				chTasks <- level
+			}
+			wg.Wait()
+			if err != nil {
+				return err
+			}
+		}
+
+		if done != nil {
+			if err := done(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolveLevels(t *testing.T) {
+	assert := require.New(t)
+
+	// constraint i of level l reads the constraints of level l-1
+	const nbLevels, width = 5, 1000
+	levels := make([][]int, nbLevels)
+	for l := range levels {
+		for i := 0; i < width; i++ {
+			levels[l] = append(levels[l], l*width+i)
+		}
+	}
+	levels = append(levels, []int{nbLevels * width}) // a small level, solved sequentially
+
+	for _, nbTasks := range []int{0, 1, 3, 64} {
+		solved := make([]int32, nbLevels*width+1)
+		err := SolveLevels(levels, nbTasks, func(i int) error {
+			if l := i / width; l > 0 {
+				for j := (l - 1) * width; j < l*width; j += 97 {
+					if atomic.LoadInt32(&solved[j]) == 0 {
+						return fmt.Errorf("constraint %d solved before %d", i, j)
+					}
+				}
+			}
+			atomic.StoreInt32(&solved[i], 1)
+			return nil
+		})
+		assert.NoError(err, "nbTasks=%d", nbTasks)
+		for i := range solved {
+			assert.EqualValues(1, solved[i], "constraint %d not solved", i)
+		}
+	}
+}
+
+func TestSolveLevelsError(t *testing.T) {
+	assert := require.New(t)
+
+	level := make([]int, 1000)
+	for i := range level {
+		level[i] = len(level) - i // the first error is not the one of the smallest constraint
+	}
+	levels := [][]int{level, {0}}
+	errFailed := errors.New("failed")
+
+	for _, nbTasks := range []int{1, 8} {
+		err := SolveLevels(levels, nbTasks, func(i int) error {
+			if i == 0 {
+				assert.Fail("the next levels mustn't be solved after an error")
+			}
+			if i%100 == 3 {
+				return fmt.Errorf("%w: %d", errFailed, i)
+			}
+			return nil
+		})
+		assert.ErrorIs(err, errFailed)
+		assert.EqualError(err, "failed: 903", "nbTasks=%d", nbTasks)
+	}
+}
+
+// BenchmarkSolveLevels solves wide levels in which one constraint in 16 is
+// slower to solve, as the constraints calling hints, sequentially and
+// concurrently.
+func BenchmarkSolveLevels(b *testing.B) {
+	const nbLevels, width = 8, 2000
+	levels := make([][]int, nbLevels)
+	for l := range levels {
+		for i := 0; i < width; i++ {
+			levels[l] = append(levels[l], l*width+i)
+		}
+	}
+	values := make([]uint64, nbLevels*width)
+	solve := func(i int) error {
+		n := 20
+		if i%16 == 0 {
+			n = 5000
+		}
+		x := uint64(i)
+		for j := 0; j < n; j++ {
+			x = x*6364136223846793005 + 1442695040888963407
+		}
+		values[i] = x
+		return nil
+	}
+
+	for _, nbTasks := range []int{1, 0} {
+		b.Run(fmt.Sprintf("nbTasks=%d", nbTasks), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := SolveLevels(levels, nbTasks, solve); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package solver
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
+	"time"
 
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
@@ -16,6 +19,19 @@ type Option func(*Config) error
 type Config struct {
 	HintFunctions map[HintID]HintFn // defaults to all built-in hint functions
 	Logger        zerolog.Logger    // defaults to gnark.Logger
+	NbTasks       int               // defaults to runtime.NumCPU()
+
+	// LogContext holds the fields set with WithLogContext, attached to every
+	// line logged by the solver.
+	LogContext map[string]interface{}
+
+	// CheckpointPath and CheckpointInterval are set by WithCheckpoint; the solver
+	// doesn't save checkpoints if CheckpointPath is empty.
+	CheckpointPath     string
+	CheckpointInterval time.Duration
+
+	// Report is set by WithReport; the solver fills it if it is not nil.
+	Report *Report
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -55,10 +71,53 @@ func WithLogger(l zerolog.Logger) Option {
 	}
 }
 
+// WithNbTasks is a solver option that sets the maximum number of goroutines solving
+// the independent constraints of a level concurrently (see SolveLevels). 1 solves
+// the constraints sequentially.
+func WithNbTasks(nbTasks int) Option {
+	return func(opt *Config) error {
+		if nbTasks < 1 {
+			return fmt.Errorf("invalid number of tasks %d", nbTasks)
+		}
+		opt.NbTasks = nbTasks
+		return nil
+	}
+}
+
+// WithLogContext is a solver option that attaches the given fields (for instance
+// a job ID, the circuit name or the curve) to every line logged by the solver and
+// by api.Println, so that the logs of proofs solved concurrently can be told apart.
+// It can be combined with WithLogger, in any order.
+func WithLogContext(fields map[string]interface{}) Option {
+	return func(opt *Config) error {
+		for k, v := range fields {
+			opt.LogContext[k] = v
+		}
+		return nil
+	}
+}
+
+// WithCheckpoint is a solver option that saves the state of the solver (the wires
+// solved so far and the next level of constraints to solve) in the file at path,
+// at most every interval, so that a long solving which is interrupted can be
+// resumed: if the file exists, the solver restores the state it holds and goes on
+// from there. A checkpoint of another constraint system or witness is ignored. The
+// file is removed once the system is solved.
+func WithCheckpoint(path string, interval time.Duration) Option {
+	return func(opt *Config) error {
+		if path == "" {
+			return errors.New("empty checkpoint path")
+		}
+		opt.CheckpointPath = path
+		opt.CheckpointInterval = interval
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
-	opt := Config{Logger: log, HintFunctions: make(map[HintID]HintFn)}
+	opt := Config{Logger: log, HintFunctions: make(map[HintID]HintFn), LogContext: make(map[string]interface{}), NbTasks: runtime.NumCPU()}
 	for k, v := range GetRegisteredHints() {
 		opt.HintFunctions[k] = v // copy
 	}
@@ -67,5 +126,8 @@ func NewConfig(opts ...Option) (Config, error) {
 			return Config{}, err
 		}
 	}
+	if len(opt.LogContext) != 0 {
+		opt.Logger = opt.Logger.With().Fields(opt.LogContext).Logger()
+	}
 	return opt, nil
 }
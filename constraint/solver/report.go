@@ -0,0 +1,110 @@
+package solver
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Report holds the statistics of a solving, for the performance analysis of the
+// witness generation. It is filled by the solver given WithReport, even if the
+// solving fails.
+type Report struct {
+	Duration     time.Duration // total solving time
+	HintDuration time.Duration // time spent in the hint functions, summed over the goroutines
+
+	NbConstraints int // number of constraints evaluated
+	NbHintCalls   int // number of calls to hint functions
+
+	// Hints holds the calls to each hint function, by name.
+	Hints map[string]HintReport
+
+	// BitLenHistogram[i] is the number of solved wires whose value has i bits.
+	// Small negative values are close to the modulus and have its length.
+	BitLenHistogram []int
+
+	// MaxBitLen is the largest number of bits of the value of a solved wire.
+	MaxBitLen int
+
+	lock          sync.Mutex
+	nbConstraints uint64 // updated atomically while solving
+}
+
+// HintReport holds the calls to a hint function, see Report.
+type HintReport struct {
+	NbCalls  int
+	Duration time.Duration
+}
+
+// WithReport is a solver option that fills r with the statistics of the
+// solving (see Report); it resets r. The statistics slow the solver down a bit,
+// mostly the timing of the hint functions.
+func WithReport(r *Report) Option {
+	return func(opt *Config) error {
+		r.lock.Lock()
+		r.Duration, r.HintDuration = 0, 0
+		r.NbConstraints, r.NbHintCalls = 0, 0
+		r.Hints = make(map[string]HintReport)
+		r.BitLenHistogram = nil
+		r.MaxBitLen = 0
+		r.nbConstraints = 0
+		r.lock.Unlock()
+		opt.Report = r
+		return nil
+	}
+}
+
+// AddHintCall records a call to the hint function name which took d. It is safe
+// for concurrent use.
+func (r *Report) AddHintCall(name string, d time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	h := r.Hints[name]
+	h.NbCalls++
+	h.Duration += d
+	r.Hints[name] = h
+	r.NbHintCalls++
+	r.HintDuration += d
+}
+
+// AddWire records a solved wire whose value has bitLen bits.
+func (r *Report) AddWire(bitLen int) {
+	for len(r.BitLenHistogram) <= bitLen {
+		r.BitLenHistogram = append(r.BitLenHistogram, 0)
+	}
+	r.BitLenHistogram[bitLen]++
+	if bitLen > r.MaxBitLen {
+		r.MaxBitLen = bitLen
+	}
+}
+
+// Finish records the total solving time, once the solver is done.
+func (r *Report) Finish(took time.Duration) {
+	r.Duration = took
+	r.NbConstraints = int(atomic.LoadUint64(&r.nbConstraints))
+}
+
+// HintNames returns the names of the hint functions called, from the one which
+// took the longest to the one which took the shortest.
+func (r *Report) HintNames() []string {
+	names := make([]string, 0, len(r.Hints))
+	for name := range r.Hints {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if r.Hints[names[i]].Duration != r.Hints[names[j]].Duration {
+			return r.Hints[names[i]].Duration > r.Hints[names[j]].Duration
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// countConstraints wraps solve to count the constraints evaluated in r.
+func (r *Report) countConstraints(solve func(i int) error) func(i int) error {
+	return func(i int) error {
+		atomic.AddUint64(&r.nbConstraints, 1)
+		return solve(i)
+	}
+}
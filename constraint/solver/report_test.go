@@ -0,0 +1,78 @@
+package solver_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+var slowSquare = solver.NewHint("slow_square", slowSquareHint)
+
+func slowSquareHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	time.Sleep(time.Millisecond)
+	outputs[0].Mul(inputs[0], inputs[0])
+	return nil
+}
+
+type reportCircuit struct {
+	X [4]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *reportCircuit) Define(api frontend.API) error {
+	var sum frontend.Variable = 0
+	for i := range c.X {
+		sq, err := api.Compiler().NewHint(slowSquare, 1, c.X[i])
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(sq[0], api.Mul(c.X[i], c.X[i]))
+		sum = api.Add(sum, sq[0])
+	}
+	api.AssertIsEqual(sum, c.Y)
+	return nil
+}
+
+func TestReport(t *testing.T) {
+	assert := require.New(t)
+	solver.RegisterHint(slowSquare)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &reportCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&reportCircuit{X: [4]frontend.Variable{1, 2, 3, 1 << 40}, Y: new(big.Int).Add(big.NewInt(14), new(big.Int).Lsh(big.NewInt(1), 80))}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		var report solver.Report
+		assert.NoError(ccs.IsSolved(w, solver.WithReport(&report)))
+		assert.Equal(ccs.GetNbConstraints(), report.NbConstraints)
+		assert.Equal(4, report.NbHintCalls)
+		hintName := ccs.GetHintName(slowSquare.ID)
+		assert.Equal([]string{hintName}, report.HintNames())
+		assert.Equal(4, report.Hints[hintName].NbCalls)
+		assert.GreaterOrEqual(report.HintDuration, 4*time.Millisecond)
+		assert.GreaterOrEqual(report.Duration, report.HintDuration)
+		assert.GreaterOrEqual(report.MaxBitLen, 81)
+		assert.NotZero(report.BitLenHistogram[81], "(2⁴⁰)² has 81 bits")
+		nbWires := 0
+		for _, n := range report.BitLenHistogram {
+			nbWires += n
+		}
+		assert.Equal(ccs.GetNbInternalVariables()+ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables(), nbWires)
+
+		// the report is reset, and filled on failure
+		w, err = frontend.NewWitness(&reportCircuit{X: [4]frontend.Variable{1, 2, 3, 4}, Y: 0}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(w, solver.WithReport(&report)))
+		assert.Equal(4, report.NbHintCalls)
+		assert.LessOrEqual(report.NbConstraints, ccs.GetNbConstraints())
+		assert.NotZero(report.BitLenHistogram[5], "4² has 5 bits")
+	}
+}
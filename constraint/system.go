@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sort"
 
 	"github.com/blang/semver/v4"
 	"github.com/consensys/gnark"
@@ -22,7 +23,8 @@ type ConstraintSystem interface {
 	io.ReaderFrom
 	CoeffEngine
 
-	// IsSolved returns nil if given witness solves the constraint system and error otherwise
+	// IsSolved returns nil if given witness solves the constraint system and error otherwise;
+	// solver.WithReport collects statistics of the solving for performance analysis.
 	// Deprecated: use _, err := Solve(...) instead
 	IsSolved(witness witness.Witness, opts ...solver.Option) error
 
@@ -55,6 +57,12 @@ type ConstraintSystem interface {
 
 	AddCommitment(c Commitment) error
 
+	// GetHintIDs returns the sorted IDs of the hints needed to solve the constraint system.
+	GetHintIDs() []solver.HintID
+
+	// GetHintName returns a human readable name of the hint with the given ID.
+	GetHintName(id solver.HintID) string
+
 	AddLog(l LogEntry)
 
 	// MakeTerm returns a new Term. The constraint system may store coefficients in a map, so
@@ -63,14 +71,46 @@ type ConstraintSystem interface {
 
 	NewDebugInfo(errName string, i ...interface{}) DebugInfo
 
+	// NewDebugMessage is NewDebugInfo without the stack of the caller.
+	NewDebugMessage(errName string, i ...interface{}) DebugInfo
+
 	// AttachDebugInfo enables attaching debug information to multiple constraints.
 	// This is more efficient than using the AddConstraint(.., debugInfo) since it will store the
 	// debug information only once.
 	AttachDebugInfo(debugInfo DebugInfo, constraintID []int)
 
+	// PushScope and PopScope enter and leave a named scope, to which the constraints added
+	// in between are attributed (see frontend.Scoper). nextCID is the ID of the next constraint.
+	PushScope(name string, nextCID int)
+	PopScope(nextCID int) error
+
+	// GetConstraintScope returns the path of the scope in which the constraint cID was added.
+	GetConstraintScope(cID int) string
+
 	// CheckUnconstrainedWires returns and error if the constraint system has wires that are not uniquely constrained.
 	// This is experimental.
 	CheckUnconstrainedWires() error
+
+	// UnconstrainedInputs returns the names of the public and secret inputs
+	// which appear in no constraint with a non-zero coefficient.
+	UnconstrainedInputs() (public, secret []string)
+
+	// FreeSecretInputs returns the secret inputs which appear in no constraint,
+	// or only in trivially satisfied ones (see FreeInput). Unlike
+	// CheckUnconstrainedWires, it can be run on any compiled or deserialized
+	// constraint system to audit it.
+	FreeSecretInputs() []FreeInput
+
+	// Digest returns a fingerprint of the constraint system (see DigestR1CS), embedded
+	// in the keys generated for it.
+	Digest() Digest
+
+	// GetMetadata returns the metadata of the constraint system, which the keys
+	// generated for it carry (see frontend.WithMetadata).
+	GetMetadata() Metadata
+
+	// SetMetadata sets the value of key in the metadata of the constraint system.
+	SetMetadata(key, value string)
 }
 
 type Iterable interface {
@@ -110,6 +150,10 @@ type System struct {
 	// several constraints may point to the same debug info
 	MDebug map[int]int
 
+	// attributes constraints to scopes, sorted by constraint id
+	Scopes     []ScopeRange
+	scopeStack []string `cbor:"-"` // current scope, while building the system
+
 	HintMappings       []HintMapping
 	MHints             map[int]int              // maps wireID to hint
 	MHintsDependencies map[solver.HintID]string // maps hintID to hint string identifier
@@ -132,6 +176,9 @@ type System struct {
 	lbHints     map[int]struct{} `cbor:"-"` // hints we processed in current round
 
 	CommitmentInfo Commitment
+
+	// key/value pairs describing the system, see GetMetadata
+	Metadata Metadata
 }
 
 // NewSystem initialize the common structure among constraint system
@@ -149,6 +196,17 @@ func NewSystem(scalarField *big.Int) System {
 	}
 }
 
+func (system *System) GetMetadata() Metadata {
+	return system.Metadata
+}
+
+func (system *System) SetMetadata(key, value string) {
+	if system.Metadata == nil {
+		system.Metadata = make(Metadata)
+	}
+	system.Metadata[key] = value
+}
+
 func (system *System) GetNbSecretVariables() int {
 	return len(system.Secret)
 }
@@ -231,7 +289,11 @@ func (system *System) AddSolverHint(f solver.Hint, input []LinearExpression, nbO
 
 	// register the hint as dependency
 	if _, ok := system.MHintsDependencies[f.ID]; !ok {
-		system.MHintsDependencies[f.ID] = fmt.Sprintf("hint %d", f.ID)
+		name := f.Name
+		if name == "" {
+			name = defaultHintName(f.ID)
+		}
+		system.MHintsDependencies[f.ID] = name
 	}
 
 	// prepare wires
@@ -251,6 +313,49 @@ func (system *System) AddSolverHint(f solver.Hint, input []LinearExpression, nbO
 	return
 }
 
+// GetHintIDs returns the sorted IDs of the hints needed to solve the constraint system.
+func (system *System) GetHintIDs() []solver.HintID {
+	ids := make([]solver.HintID, 0, len(system.MHintsDependencies))
+	for id := range system.MHintsDependencies {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// GetHintName returns a human readable name of the hint with the given ID. Hints
+// created without name are resolved through the hint registry, if possible.
+func (system *System) GetHintName(id solver.HintID) string {
+	name, ok := system.MHintsDependencies[id]
+	if ok && name != defaultHintName(id) {
+		return name
+	}
+	if name, ok := solver.GetRegisteredHintName(id); ok {
+		return name
+	}
+	return defaultHintName(id)
+}
+
+// CheckHintFunctions returns a *solver.MissingHintsError if some hints needed to
+// solve the constraint system are not in hintFunctions.
+func (system *System) CheckHintFunctions(hintFunctions map[solver.HintID]solver.HintFn) error {
+	var err solver.MissingHintsError
+	for _, id := range system.GetHintIDs() {
+		if _, ok := hintFunctions[id]; !ok {
+			err.IDs = append(err.IDs, id)
+			err.Names = append(err.Names, system.GetHintName(id))
+		}
+	}
+	if len(err.IDs) != 0 {
+		return &err
+	}
+	return nil
+}
+
+func defaultHintName(id solver.HintID) string {
+	return fmt.Sprintf("hint %d", id)
+}
+
 func (system *System) AddCommitment(c Commitment) error {
 	if system.CommitmentInfo.Is() {
 		return fmt.Errorf("currently only one commitment per circuit is supported")
@@ -262,6 +367,9 @@ func (system *System) AddCommitment(c Commitment) error {
 }
 
 func (system *System) AddLog(l LogEntry) {
+	if scope := system.CurrentScope(); scope != "" {
+		l.Caller = scope + " " + l.Caller
+	}
 	system.Logs = append(system.Logs, l)
 }
 
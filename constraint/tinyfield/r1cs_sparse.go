@@ -22,15 +22,13 @@ import (
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	"io"
-	"math"
-	"runtime"
-	"sync"
 	"time"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/constraint/solver"
-	"github.com/consensys/gnark/internal/backend/ioutils"
+	gnarkio "github.com/consensys/gnark/io"
 	"github.com/consensys/gnark/logger"
 	"github.com/consensys/gnark/profile"
 
@@ -94,7 +92,7 @@ func (c *SparseR1CS) Solve(witness witness.Witness, opts ...solver.Option) (any,
 func (c *SparseR1CS) evaluateLROSmallDomain(solution []fr.Element) ([]fr.Element, []fr.Element, []fr.Element) {
 
 	//s := int(pk.Domain[0].Cardinality)
-	s := c.GetNbConstraints() + len(c.Public) // len(spr.Public) is for the placeholder constraints
+	s := c.GetNbConstraints() + len(c.Public) + c.CommitmentInfo.NbPlaceholderConstraints() // placeholder constraints for public inputs and commitment
 	s = int(ecc.NextPowerOfTwo(uint64(s)))
 
 	var l, r, o []fr.Element
@@ -109,6 +107,17 @@ func (c *SparseR1CS) evaluateLROSmallDomain(solution []fr.Element) ([]fr.Element
 		o[i] = s0
 	}
 	offset := len(c.Public)
+	if c.CommitmentInfo.Is() { // placeholders for the commitment and the committed wires
+		l[offset] = solution[c.CommitmentInfo.CommitmentIndex]
+		r[offset] = s0
+		o[offset] = s0
+		for i, wireID := range c.CommitmentInfo.Committed {
+			l[offset+1+i] = solution[wireID]
+			r[offset+1+i] = s0
+			o[offset+1+i] = s0
+		}
+		offset += c.CommitmentInfo.NbPlaceholderConstraints()
+	}
 	for i := 0; i < len(c.Constraints); i++ { // constraints
 		l[offset+i] = solution[c.Constraints[i].L.WireID()]
 		r[offset+i] = solution[c.Constraints[i].R.WireID()]
@@ -131,7 +140,7 @@ func (c *SparseR1CS) evaluateLROSmallDomain(solution []fr.Element) ([]fr.Element
 // witness: contains the input variables
 // it returns the full slice of wires
 func (cs *SparseR1CS) solve(witness fr.Vector, opt solver.Config) (fr.Vector, error) {
-	log := logger.Logger().With().Int("nbConstraints", len(cs.Constraints)).Str("backend", "plonk").Logger()
+	log := logger.Logger().With().Fields(opt.LogContext).Int("nbConstraints", len(cs.Constraints)).Str("backend", "plonk").Logger()
 
 	// set the slices holding the solution.values and monitoring which variables have been solved
 	nbVariables := cs.NbInternalVariables + len(cs.Secret) + len(cs.Public)
@@ -154,6 +163,10 @@ func (cs *SparseR1CS) solve(witness fr.Vector, opt solver.Config) (fr.Vector, er
 	if err != nil {
 		return solution.values, err
 	}
+	if opt.Report != nil {
+		solution.report = opt.Report
+		defer func() { solution.fillReport(time.Since(start)) }()
+	}
 
 	// solution.values = [publicInputs | secretInputs | internalVariables ] -> we fill publicInputs | secretInputs
 	copy(solution.values, witness)
@@ -174,7 +187,11 @@ func (cs *SparseR1CS) solve(witness fr.Vector, opt solver.Config) (fr.Vector, er
 		coefficientsNegInv[i].Neg(&coefficientsNegInv[i])
 	}
 
-	if err := cs.parallelSolve(&solution, coefficientsNegInv); err != nil {
+	var id []byte
+	if opt.CheckpointPath != "" {
+		id = checkpointID(cs.Digest(), witness)
+	}
+	if err := cs.parallelSolve(&solution, coefficientsNegInv, opt, id); err != nil {
 		if unsatisfiedErr, ok := err.(*UnsatisfiedConstraintError); ok {
 			log.Err(errors.New("unsatisfied constraint")).Int("id", unsatisfiedErr.CID).Send()
 		} else {
@@ -195,119 +212,25 @@ func (cs *SparseR1CS) solve(witness fr.Vector, opt solver.Config) (fr.Vector, er
 
 }
 
-func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv fr.Vector) error {
-	// minWorkPerCPU is the minimum target number of constraint a task should hold
-	// in other words, if a level has less than minWorkPerCPU, it will not be parallelized and executed
-	// sequentially without sync.
-	const minWorkPerCPU = 50.0
-
+// parallelSolve solves the constraints level by level, saving checkpoints of
+// the solution if opt.CheckpointPath is set (see solver.WithCheckpoint); id
+// identifies the system and its witness in the checkpoints.
+func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv fr.Vector, opt solver.Config, id []byte) error {
 	// cs.Levels has a list of levels, where all constraints in a level l(n) are independent
 	// and may only have dependencies on previous levels
-
-	var wg sync.WaitGroup
-	chTasks := make(chan []int, runtime.NumCPU())
-	chError := make(chan *UnsatisfiedConstraintError, runtime.NumCPU())
-
-	// start a worker pool
-	// each worker wait on chTasks
-	// a task is a slice of constraint indexes to be solved
-	for i := 0; i < runtime.NumCPU(); i++ {
-		go func() {
-			for t := range chTasks {
-				for _, i := range t {
-					// for each constraint in the task, solve it.
-					if err := cs.solveConstraint(cs.Constraints[i], solution, coefficientsNegInv); err != nil {
-						chError <- &UnsatisfiedConstraintError{CID: i, Err: err}
-						wg.Done()
-						return
-					}
-					if err := cs.checkConstraint(cs.Constraints[i], solution); err != nil {
-						if dID, ok := cs.MDebug[i]; ok {
-							errMsg := solution.logValue(cs.DebugInfo[dID])
-							chError <- &UnsatisfiedConstraintError{CID: i, DebugInfo: &errMsg}
-						} else {
-							chError <- &UnsatisfiedConstraintError{CID: i, Err: err}
-						}
-						wg.Done()
-						return
-					}
-				}
-				wg.Done()
-			}
-		}()
-	}
-
-	// clean up pool go routines
-	defer func() {
-		close(chTasks)
-		close(chError)
-	}()
-
-	// for each level, we push the tasks
-	for _, level := range cs.Levels {
-
-		// max CPU to use
-		maxCPU := float64(len(level)) / minWorkPerCPU
-
-		if maxCPU <= 1.0 {
-			// we do it sequentially
-			for _, i := range level {
-				if err := cs.solveConstraint(cs.Constraints[i], solution, coefficientsNegInv); err != nil {
-					return &UnsatisfiedConstraintError{CID: i, Err: err}
-				}
-				if err := cs.checkConstraint(cs.Constraints[i], solution); err != nil {
-					if dID, ok := cs.MDebug[i]; ok {
-						errMsg := solution.logValue(cs.DebugInfo[dID])
-						return &UnsatisfiedConstraintError{CID: i, DebugInfo: &errMsg}
-					}
-					return &UnsatisfiedConstraintError{CID: i, Err: err}
-				}
-			}
-			continue
-		}
-
-		// number of tasks for this level is set to number of CPU
-		// but if we don't have enough work for all our CPU, it can be lower.
-		nbTasks := runtime.NumCPU()
-		maxTasks := int(math.Ceil(maxCPU))
-		if nbTasks > maxTasks {
-			nbTasks = maxTasks
+	return solver.SolveLevelsWithCheckpoints(cs.Levels, opt, id, solution, func(i int) error {
+		if err := cs.solveConstraint(cs.Constraints[i], solution, coefficientsNegInv); err != nil {
+			return &UnsatisfiedConstraintError{CID: i, Scope: cs.GetConstraintScope(i), Err: err}
 		}
-		nbIterationsPerCpus := len(level) / nbTasks
-
-		// more CPUs than tasks: a CPU will work on exactly one iteration
-		// note: this depends on minWorkPerCPU constant
-		if nbIterationsPerCpus < 1 {
-			nbIterationsPerCpus = 1
-			nbTasks = len(level)
-		}
-
-		extraTasks := len(level) - (nbTasks * nbIterationsPerCpus)
-		extraTasksOffset := 0
-
-		for i := 0; i < nbTasks; i++ {
-			wg.Add(1)
-			_start := i*nbIterationsPerCpus + extraTasksOffset
-			_end := _start + nbIterationsPerCpus
-			if extraTasks > 0 {
-				_end++
-				extraTasks--
-				extraTasksOffset++
+		if err := cs.checkConstraint(cs.Constraints[i], solution); err != nil {
+			if dID, ok := cs.MDebug[i]; ok {
+				errMsg := solution.logValue(cs.DebugInfo[dID])
+				return &UnsatisfiedConstraintError{CID: i, Scope: cs.GetConstraintScope(i), DebugInfo: &errMsg}
 			}
-			// since we're never pushing more than num CPU tasks
-			// we will never be blocked here
-			chTasks <- level[_start:_end]
+			return &UnsatisfiedConstraintError{CID: i, Scope: cs.GetConstraintScope(i), Err: err}
 		}
-
-		// wait for the level to be done
-		wg.Wait()
-
-		if len(chError) > 0 {
-			return <-chError
-		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // computeHints computes wires associated with a hint function, if any
@@ -484,31 +407,101 @@ func (cs *SparseR1CS) CurveID() ecc.ID {
 	return ecc.UNKNOWN
 }
 
-// WriteTo encodes SparseR1CS into provided io.Writer using gob
+// Digest returns the fingerprint of the SparseR1CS (see constraint.DigestSparseR1CS)
+func (cs *SparseR1CS) Digest() constraint.Digest {
+	return constraint.DigestSparseR1CS(&cs.System, coefficientsDigestBytes(cs.Coefficients), cs.Constraints)
+}
+
+// WriteTo encodes SparseR1CS into provided io.Writer. The coefficients and the system
+// metadata are encoded using gob, the constraints are then streamed in chunks
+// (see constraint.WriteSparseR1Cs).
 func (cs *SparseR1CS) WriteTo(w io.Writer) (int64, error) {
-	_w := ioutils.WriterCounter{W: w} // wraps writer to count the bytes written
+	header := gnarkio.NewHeader(gnarkio.SparseR1CS, cs.CurveID(), backend.UNKNOWN)
+	return gnarkio.WriteWithHeader(w, header, cs.writeTo)
+}
 
-	// encode our object
-	encoder := gob.NewEncoder(&_w)
+func (cs *SparseR1CS) writeTo(w io.Writer) error {
+	// encode everything but the constraints
+	encoder := gob.NewEncoder(w)
+	header := serializedHeader{System: cs.System, Coefficients: cs.Coefficients}
+	if err := encoder.Encode(&header); err != nil {
+		return err
+	}
 
-	return _w.N, encoder.Encode(cs)
+	_, err := constraint.WriteSparseR1Cs(w, cs.Constraints)
+	return err
 }
 
-// ReadFrom attempts to decode SparseR1CS from io.Reader using gob
+// ReadFrom attempts to decode SparseR1CS from io.Reader (see WriteTo)
 func (cs *SparseR1CS) ReadFrom(r io.Reader) (int64, error) {
-	_r := ioutils.ReaderCounter{R: r} // wraps reader to count the bytes written
-	decoder := gob.NewDecoder(&_r)
+	header := gnarkio.NewHeader(gnarkio.SparseR1CS, cs.CurveID(), backend.UNKNOWN)
+	return gnarkio.ReadWithHeader(r, header, cs.readFrom)
+}
+
+func (cs *SparseR1CS) readFrom(r io.Reader) error {
+	// r is an io.ByteReader (see gnarkio.ReadWithHeader): gob doesn't read past the
+	// encoded header, the constraints can then be read from the same reader.
+	decoder := gob.NewDecoder(r)
 
 	// initialize coeff table
 	cs.CoeffTable = newCoeffTable(0)
 
-	if err := decoder.Decode(cs); err != nil {
-		return _r.N, err
+	var header serializedHeader
+	if err := decoder.Decode(&header); err != nil {
+		return err
+	}
+	cs.System = header.System
+	cs.Coefficients = header.Coefficients
+
+	constraints, _, err := constraint.ReadSparseR1Cs(r)
+	if err != nil {
+		return err
+	}
+	cs.Constraints = constraints
+	if err := cs.CheckTerms(len(cs.Coefficients)); err != nil {
+		return err
+	}
+
+	return cs.CheckSerializationHeader()
+}
+
+// WriteMmapTo encodes SparseR1CS into provided io.Writer in a format which can be
+// memory mapped and used without copy (see NewSparseR1CSFromMmap)
+func (cs *SparseR1CS) WriteMmapTo(w io.Writer) (int64, error) {
+	return constraint.WriteMmapSparseR1CS(w, &cs.System, coefficientsToBytes(cs.Coefficients), cs.Constraints)
+}
+
+// NewSparseR1CSFromMmap returns a SparseR1CS backed by data, as written by WriteMmapTo.
+// data is typically obtained from constraint.OpenMappedFile; the coefficients and the
+// constraints are not copied, so data must not be released while the SparseR1CS is in
+// use. data is only read, it may be a read-only mapping: the constraints are copied
+// before being modified in place. The constraints returned by GetConstraint must
+// not be modified.
+func NewSparseR1CSFromMmap(data []byte) (*SparseR1CS, error) {
+	var cs SparseR1CS
+	cs.CoeffTable = newCoeffTable(0)
+	coefficients, err := constraint.ReadMmapSparseR1CS(data, &cs.SparseR1CSCore)
+	if err != nil {
+		return nil, err
+	}
+	// the coefficients are only appended to, and the capacity of the slice is its
+	// length: new coefficients are never written in data.
+	if cs.Coefficients, err = coefficientsFromBytes(coefficients); err != nil {
+		return nil, err
 	}
 
 	if err := cs.CheckSerializationHeader(); err != nil {
-		return _r.N, err
+		return nil, err
 	}
+	return &cs, nil
+}
 
-	return _r.N, nil
+// ToR1CS returns a R1CS equivalent to cs, which can be used with Groth16.
+// Constraint systems with commitments can't be converted.
+func (cs *SparseR1CS) ToR1CS() (*R1CS, error) {
+	res := NewR1CS(len(cs.Constraints))
+	if err := cs.ConvertToR1CS(cs, &res.R1CSCore, res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
@@ -85,6 +85,7 @@ func TestSerialization(t *testing.T) {
 						"System.lbHints",
 						"System.SymbolTable",
 						"System.lbOutputs",
+						"System.scopeStack",
 						"System.bitLen")); diff != "" {
 					t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
 				}
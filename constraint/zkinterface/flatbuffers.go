@@ -0,0 +1,175 @@
+package zkinterface
+
+import "encoding/binary"
+
+// builder is a minimal flatbuffers builder, sufficient for the zkinterface
+// schema. As with the reference implementation, the buffer is built back to
+// front: the children of an object are written before the object itself, and
+// offsets are counted from the end of the buffer.
+type builder struct {
+	bytes    []byte
+	head     int // the data is bytes[head:]
+	minAlign int
+
+	fields    []uint32 // offsets of the fields of the current table
+	objectEnd uint32   // offset of the end of the current table
+}
+
+func newBuilder(size int) *builder {
+	return &builder{bytes: make([]byte, size), head: size, minAlign: 1}
+}
+
+// offset returns the offset of the data from the end of the buffer.
+func (b *builder) offset() uint32 {
+	return uint32(len(b.bytes) - b.head)
+}
+
+// reserve makes room for n bytes before the data and returns them.
+func (b *builder) reserve(n int) []byte {
+	for b.head < n {
+		size := len(b.bytes) - b.head
+		bytes := make([]byte, 2*len(b.bytes)+n)
+		copy(bytes[len(bytes)-size:], b.bytes[b.head:])
+		b.head = len(bytes) - size
+		b.bytes = bytes
+	}
+	b.head -= n
+	return b.bytes[b.head : b.head+n]
+}
+
+// prep pads the buffer so that after writing additional bytes, the data is
+// aligned on size bytes.
+func (b *builder) prep(size, additional int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+	// the bytes before the data are never written, the padding is zero
+	b.reserve(-(int(b.offset()) + additional) & (size - 1))
+}
+
+func (b *builder) putUint8(v uint8) {
+	b.prep(1, 0)
+	b.reserve(1)[0] = v
+}
+
+func (b *builder) putUint16(v uint16) {
+	b.prep(2, 0)
+	binary.LittleEndian.PutUint16(b.reserve(2), v)
+}
+
+func (b *builder) putUint32(v uint32) {
+	b.prep(4, 0)
+	binary.LittleEndian.PutUint32(b.reserve(4), v)
+}
+
+func (b *builder) putUint64(v uint64) {
+	b.prep(8, 0)
+	binary.LittleEndian.PutUint64(b.reserve(8), v)
+}
+
+// putOffset writes the offset to the object at off, relative to its own
+// position.
+func (b *builder) putOffset(off uint32) {
+	b.prep(4, 0)
+	b.putUint32(b.offset() + 4 - off)
+}
+
+// createBytes writes a vector of bytes and returns its offset.
+func (b *builder) createBytes(v []byte) uint32 {
+	b.prep(4, len(v))
+	copy(b.reserve(len(v)), v)
+	b.putUint32(uint32(len(v)))
+	return b.offset()
+}
+
+// createString writes a null terminated string and returns its offset.
+func (b *builder) createString(s string) uint32 {
+	b.prep(4, len(s)+1)
+	b.reserve(1)[0] = 0
+	copy(b.reserve(len(s)), s)
+	b.putUint32(uint32(len(s)))
+	return b.offset()
+}
+
+// createUint64s writes a vector of uint64 and returns its offset.
+func (b *builder) createUint64s(v []uint64) uint32 {
+	b.prep(4, 8*len(v))
+	b.prep(8, 8*len(v))
+	for i := len(v) - 1; i >= 0; i-- {
+		b.putUint64(v[i])
+	}
+	b.putUint32(uint32(len(v)))
+	return b.offset()
+}
+
+// createOffsets writes a vector of offsets to objects and returns its offset.
+func (b *builder) createOffsets(v []uint32) uint32 {
+	b.prep(4, 4*len(v))
+	for i := len(v) - 1; i >= 0; i-- {
+		b.putOffset(v[i])
+	}
+	b.putUint32(uint32(len(v)))
+	return b.offset()
+}
+
+// startTable starts a table with nbFields fields; the fields are then added
+// with the add* methods, before endTable is called.
+func (b *builder) startTable(nbFields int) {
+	b.fields = make([]uint32, nbFields)
+	b.objectEnd = b.offset()
+}
+
+func (b *builder) addUint8(field int, v uint8) {
+	b.putUint8(v)
+	b.fields[field] = b.offset()
+}
+
+func (b *builder) addUint64(field int, v uint64) {
+	b.putUint64(v)
+	b.fields[field] = b.offset()
+}
+
+func (b *builder) addOffset(field int, off uint32) {
+	b.putOffset(off)
+	b.fields[field] = b.offset()
+}
+
+// endTable writes the table and its vtable, and returns the offset of the
+// table.
+func (b *builder) endTable() uint32 {
+	// offset to the vtable, set below
+	b.putUint32(0)
+	table := b.offset()
+
+	nbFields := len(b.fields)
+	for nbFields > 0 && b.fields[nbFields-1] == 0 {
+		nbFields--
+	}
+	for i := nbFields - 1; i >= 0; i-- {
+		var off uint16
+		if b.fields[i] != 0 {
+			off = uint16(table - b.fields[i])
+		}
+		b.putUint16(off)
+	}
+	b.putUint16(uint16(table - b.objectEnd))
+	b.putUint16(uint16(2 * (nbFields + 2)))
+	vtable := b.offset()
+
+	// the vtable is before the table, at table - vtable
+	pos := len(b.bytes) - int(table)
+	binary.LittleEndian.PutUint32(b.bytes[pos:], vtable-table)
+
+	b.fields = nil
+	return table
+}
+
+// finish writes the size prefix, the offset of the root table and the file
+// identifier, and returns the buffer.
+func (b *builder) finish(root uint32, identifier string) []byte {
+	b.prep(b.minAlign, 4+len(identifier)+4)
+	copy(b.reserve(len(identifier)), identifier)
+	b.putOffset(root)
+	b.putUint32(b.offset())
+	return b.bytes[b.head:]
+}
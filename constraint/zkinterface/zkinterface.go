@@ -0,0 +1,332 @@
+// Package zkinterface exports compiled constraint systems and their witness
+// in the zkinterface format, so that gnark circuits can be consumed by the
+// proving systems and analysis tools of the zkinterface ecosystem.
+//
+// The output is a sequence of size prefixed flatbuffers messages, as defined
+// by the zkinterface schema (https://github.com/QED-it/zkinterface):
+// [WriteCircuit] writes the circuit header and the constraints, [WriteWitness]
+// writes the circuit header with the public values and the witness.
+//
+// zkinterface variable 0 is the constant one. The wires of R1CS keep their
+// IDs, as gnark reserves wire 0 for the constant one; the wires of SparseR1CS
+// are shifted by one. Sparse constraints qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xa×xb) + qC == 0
+// are exported as the bilinear constraint (qM⋅xa) × (xb) == -(qL⋅xa + qR⋅xb + qO⋅xc + qC).
+package zkinterface
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	cs_tinyfield "github.com/consensys/gnark/constraint/tinyfield"
+)
+
+const fileIdentifier = "zkif"
+
+// message types of the Message union
+const (
+	messageCircuitHeader    = 1
+	messageConstraintSystem = 2
+	messageWitness          = 3
+)
+
+// ConstraintType of the ConstraintSystem message
+const constraintTypeR1CS = 0
+
+// linearCombination is the list of the (variable, coefficient) terms of one
+// side of a bilinear constraint.
+type linearCombination struct {
+	ids    []uint64
+	coeffs []*big.Int
+}
+
+func (lc *linearCombination) add(id uint64, coeff *big.Int) {
+	for i := range lc.ids {
+		if lc.ids[i] == id {
+			lc.coeffs[i].Add(lc.coeffs[i], coeff)
+			return
+		}
+	}
+	lc.ids = append(lc.ids, id)
+	lc.coeffs = append(lc.coeffs, new(big.Int).Set(coeff))
+}
+
+type bilinearConstraint struct {
+	a, b, c linearCombination
+}
+
+// encoder writes the messages of a constraint system.
+type encoder struct {
+	w           io.Writer
+	q           *big.Int
+	elementSize int // size of the field elements in bytes
+
+	sparse            bool // shift the wire IDs
+	nbPublic, nbWires int  // without the constant one
+}
+
+func newEncoder(w io.Writer, ccs constraint.ConstraintSystem) (*encoder, error) {
+	e := &encoder{
+		w:           w,
+		q:           ccs.Field(),
+		elementSize: (ccs.FieldBitLen() + 7) / 8,
+	}
+	switch ccs.(type) {
+	case constraint.R1CS:
+		e.nbPublic = ccs.GetNbPublicVariables() - 1
+	case constraint.SparseR1CS:
+		e.sparse = true
+		e.nbPublic = ccs.GetNbPublicVariables()
+	default:
+		return nil, fmt.Errorf("unsupported constraint system %T", ccs)
+	}
+	e.nbWires = e.nbPublic + ccs.GetNbSecretVariables() + ccs.GetNbInternalVariables()
+	return e, nil
+}
+
+// variableID returns the zkinterface variable of the wire.
+func (e *encoder) variableID(wire int) uint64 {
+	if e.sparse {
+		return uint64(wire) + 1
+	}
+	return uint64(wire)
+}
+
+// WriteCircuit writes the zkinterface messages describing ccs to w: the circuit
+// header, without the values of the public inputs, followed by the constraint
+// system.
+func WriteCircuit(w io.Writer, ccs constraint.ConstraintSystem) error {
+	e, err := newEncoder(w, ccs)
+	if err != nil {
+		return err
+	}
+	if err = e.writeCircuitHeader(nil); err != nil {
+		return err
+	}
+
+	var constraints []bilinearConstraint
+	switch t := ccs.(type) {
+	case constraint.R1CS:
+		r1cs, _ := t.GetConstraints()
+		constraints = make([]bilinearConstraint, len(r1cs))
+		for i, r1c := range r1cs {
+			for _, v := range []struct {
+				lc *linearCombination
+				le constraint.LinearExpression
+			}{{&constraints[i].a, r1c.L}, {&constraints[i].b, r1c.R}, {&constraints[i].c, r1c.O}} {
+				for _, term := range v.le {
+					c := t.GetCoefficient(term.CoeffID())
+					v.lc.add(e.variableID(term.WireID()), ccs.ToBigInt(&c))
+				}
+			}
+		}
+	case constraint.SparseR1CS:
+		scs, _ := t.GetConstraints()
+		constraints = make([]bilinearConstraint, len(scs))
+		for i, c := range scs {
+			var linear linearCombination
+			for _, term := range []constraint.Term{c.L, c.R, c.O} {
+				if term.CoeffID() != constraint.CoeffIdZero {
+					coeff := t.GetCoefficient(term.CoeffID())
+					linear.add(e.variableID(term.WireID()), ccs.ToBigInt(&coeff))
+				}
+			}
+			if c.K != constraint.CoeffIdZero {
+				k := t.GetCoefficient(c.K)
+				linear.add(0, ccs.ToBigInt(&k))
+			}
+
+			bc := &constraints[i]
+			if c.M[0].CoeffID() == constraint.CoeffIdZero {
+				// 1 × (qL⋅xa + qR⋅xb + qO⋅xc + qC) == 0
+				bc.a.add(0, big.NewInt(1))
+				bc.b = linear
+				continue
+			}
+			qM0, qM1 := t.GetCoefficient(c.M[0].CoeffID()), t.GetCoefficient(c.M[1].CoeffID())
+			bc.a.add(e.variableID(c.M[0].WireID()), ccs.ToBigInt(&qM0))
+			bc.b.add(e.variableID(c.M[1].WireID()), ccs.ToBigInt(&qM1))
+			for j := range linear.coeffs {
+				linear.coeffs[j].Neg(linear.coeffs[j])
+			}
+			bc.c = linear
+		}
+	}
+
+	return e.writeConstraintSystem(constraints)
+}
+
+// WriteWitness solves ccs with the given full witness and writes the zkinterface
+// messages describing the solution to w: the circuit header, with the values of
+// the public inputs, followed by the witness, assigning the secret inputs and
+// the internal wires.
+//
+// For SparseR1CS, the internal wires which are not used in any constraint are
+// assigned zero.
+func WriteWitness(w io.Writer, ccs constraint.ConstraintSystem, fullWitness witness.Witness, opts ...solver.Option) error {
+	e, err := newEncoder(w, ccs)
+	if err != nil {
+		return err
+	}
+	values, err := solve(ccs, fullWitness, opts...)
+	if err != nil {
+		return err
+	}
+	if !e.sparse {
+		values = values[1:] // constant one
+	}
+	if err = e.writeCircuitHeader(values[:e.nbPublic]); err != nil {
+		return err
+	}
+	return e.writeWitness(values[e.nbPublic:])
+}
+
+// solve returns the values of the wires of ccs.
+func solve(ccs constraint.ConstraintSystem, fullWitness witness.Witness, opts ...solver.Option) ([]*big.Int, error) {
+	solution, err := ccs.Solve(fullWitness, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch s := solution.(type) {
+	case *cs_bn254.R1CSSolution:
+		return toBigInts(s.W), nil
+	case *cs_tinyfield.R1CSSolution:
+		return toBigInts(s.W), nil
+	case *cs_bn254.SparseR1CSSolution:
+		return sparseValues(&ccs.(*cs_bn254.SparseR1CS).SparseR1CSCore, toBigInts(s.L), toBigInts(s.R), toBigInts(s.O)), nil
+	case *cs_tinyfield.SparseR1CSSolution:
+		return sparseValues(&ccs.(*cs_tinyfield.SparseR1CS).SparseR1CSCore, toBigInts(s.L), toBigInts(s.R), toBigInts(s.O)), nil
+	default:
+		return nil, fmt.Errorf("unsupported solution %T", solution)
+	}
+}
+
+func toBigInts[E any, PE interface {
+	*E
+	BigInt(*big.Int) *big.Int
+}](v []E) []*big.Int {
+	res := make([]*big.Int, len(v))
+	for i := range v {
+		res[i] = PE(&v[i]).BigInt(new(big.Int))
+	}
+	return res
+}
+
+// sparseValues recovers the values of the wires from the l, r, o vectors of
+// the solution, in the layout of the PLONK prover: the placeholders of the
+// public inputs and of the commitment, followed by the constraints.
+func sparseValues(scs *constraint.SparseR1CSCore, l, r, o []*big.Int) []*big.Int {
+	values := make([]*big.Int, len(scs.Public)+len(scs.Secret)+scs.NbInternalVariables)
+	for i := range values {
+		values[i] = new(big.Int)
+	}
+	offset := len(scs.Public) + scs.CommitmentInfo.NbPlaceholderConstraints()
+	for i, c := range scs.Constraints {
+		values[c.L.WireID()] = l[offset+i]
+		values[c.R.WireID()] = r[offset+i]
+		values[c.O.WireID()] = o[offset+i]
+	}
+	for i := range scs.Public {
+		values[i] = l[i]
+	}
+	return values
+}
+
+// writeCircuitHeader writes the CircuitHeader message, with the values of the
+// public inputs if any.
+func (e *encoder) writeCircuitHeader(public []*big.Int) error {
+	b := newBuilder(1024)
+	ids := make([]uint64, e.nbPublic)
+	for i := range ids {
+		ids[i] = uint64(i) + 1
+	}
+	instance := e.createVariables(b, ids, public)
+	fieldMaximum := b.createBytes(e.toLittleEndian(new(big.Int).Sub(e.q, big.NewInt(1))))
+
+	b.startTable(4)
+	b.addOffset(0, instance)
+	b.addUint64(1, uint64(e.nbWires)+1)
+	b.addOffset(2, fieldMaximum)
+	return e.writeMessage(b, messageCircuitHeader, b.endTable())
+}
+
+func (e *encoder) writeConstraintSystem(constraints []bilinearConstraint) error {
+	b := newBuilder(1024)
+	offsets := make([]uint32, len(constraints))
+	for i, c := range constraints {
+		a := e.createVariables(b, c.a.ids, c.a.coeffs)
+		bb := e.createVariables(b, c.b.ids, c.b.coeffs)
+		cc := e.createVariables(b, c.c.ids, c.c.coeffs)
+		b.startTable(3)
+		b.addOffset(0, a)
+		b.addOffset(1, bb)
+		b.addOffset(2, cc)
+		offsets[i] = b.endTable()
+	}
+	vector := b.createOffsets(offsets)
+
+	b.startTable(3)
+	b.addOffset(0, vector)
+	b.addUint8(1, constraintTypeR1CS)
+	return e.writeMessage(b, messageConstraintSystem, b.endTable())
+}
+
+func (e *encoder) writeWitness(values []*big.Int) error {
+	b := newBuilder(1024)
+	ids := make([]uint64, len(values))
+	for i := range ids {
+		ids[i] = uint64(e.nbPublic+i) + 1
+	}
+	assigned := e.createVariables(b, ids, values)
+
+	b.startTable(1)
+	b.addOffset(0, assigned)
+	return e.writeMessage(b, messageWitness, b.endTable())
+}
+
+// createVariables writes a Variables table. values is either nil or of the
+// same length as ids.
+func (e *encoder) createVariables(b *builder, ids []uint64, values []*big.Int) uint32 {
+	vIDs := b.createUint64s(ids)
+	var vValues uint32
+	if values != nil {
+		buf := make([]byte, 0, len(values)*e.elementSize)
+		for _, v := range values {
+			buf = append(buf, e.toLittleEndian(v)...)
+		}
+		vValues = b.createBytes(buf)
+	}
+
+	b.startTable(3)
+	b.addOffset(0, vIDs)
+	if values != nil {
+		b.addOffset(1, vValues)
+	}
+	return b.endTable()
+}
+
+// writeMessage writes the Root table holding the message, and the finished
+// buffer to e.w.
+func (e *encoder) writeMessage(b *builder, messageType uint8, message uint32) error {
+	b.startTable(2)
+	b.addUint8(0, messageType)
+	b.addOffset(1, message)
+	root := b.endTable()
+	_, err := e.w.Write(b.finish(root, fileIdentifier))
+	return err
+}
+
+// toLittleEndian returns the little endian encoding of v mod q on
+// e.elementSize bytes.
+func (e *encoder) toLittleEndian(v *big.Int) []byte {
+	buf := make([]byte, e.elementSize)
+	new(big.Int).Mod(v, e.q).FillBytes(buf)
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return buf
+}
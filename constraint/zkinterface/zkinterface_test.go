@@ -0,0 +1,177 @@
+package zkinterface_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/zkinterface"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+// table is a flatbuffers table at pos in buf.
+type table struct {
+	buf []byte
+	pos int
+}
+
+func (t table) uint32(pos int) int {
+	return int(binary.LittleEndian.Uint32(t.buf[pos:]))
+}
+
+// field returns the position of the field i, or -1 if it is not set.
+func (t table) field(i int) int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if 4+2*i >= int(binary.LittleEndian.Uint16(t.buf[vtable:])) {
+		return -1
+	}
+	off := int(binary.LittleEndian.Uint16(t.buf[vtable+4+2*i:]))
+	if off == 0 {
+		return -1
+	}
+	return t.pos + off
+}
+
+func (t table) table(i int) table {
+	pos := t.field(i)
+	return table{t.buf, pos + t.uint32(pos)}
+}
+
+// vector returns the position of the first element and the length of the
+// vector field i.
+func (t table) vector(i int) (int, int) {
+	pos := t.field(i)
+	if pos == -1 {
+		return 0, 0
+	}
+	pos += t.uint32(pos)
+	return pos + 4, t.uint32(pos)
+}
+
+func (t table) tables(i int) []table {
+	pos, n := t.vector(i)
+	res := make([]table, n)
+	for j := range res {
+		res[j] = table{t.buf, pos + 4*j + t.uint32(pos+4*j)}
+	}
+	return res
+}
+
+// variables returns the assignment of the Variables table.
+func (t table) variables(elementSize int) ([]uint64, []*big.Int) {
+	pos, n := t.vector(0)
+	ids := make([]uint64, n)
+	for j := range ids {
+		ids[j] = binary.LittleEndian.Uint64(t.buf[pos+8*j:])
+	}
+	pos, size := t.vector(1)
+	values := make([]*big.Int, size/elementSize)
+	for j := range values {
+		be := make([]byte, elementSize)
+		for k := range be {
+			be[elementSize-1-k] = t.buf[pos+elementSize*j+k]
+		}
+		values[j] = new(big.Int).SetBytes(be)
+	}
+	return ids, values
+}
+
+// readMessages returns the type and content of the messages in data.
+func readMessages(t *testing.T, data []byte) ([]uint8, []table) {
+	var (
+		types    []uint8
+		messages []table
+	)
+	for len(data) > 0 {
+		size := int(binary.LittleEndian.Uint32(data))
+		buf := data[4 : 4+size]
+		require.Equal(t, "zkif", string(buf[4:8]))
+		root := table{buf, int(binary.LittleEndian.Uint32(buf))}
+		types = append(types, buf[root.field(0)])
+		messages = append(messages, root.table(1))
+		data = data[4+size:]
+	}
+	return types, messages
+}
+
+func TestWrite(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	const elementSize = 32
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, newBuilder, &cubicCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, field)
+		assert.NoError(err)
+
+		var circuit, witness bytes.Buffer
+		assert.NoError(zkinterface.WriteCircuit(&circuit, ccs))
+		assert.NoError(zkinterface.WriteWitness(&witness, ccs, w))
+
+		types, messages := readMessages(t, circuit.Bytes())
+		assert.Equal([]uint8{1, 2}, types)
+		header, constraints := messages[0], messages[1]
+		ids, values := header.table(0).variables(elementSize)
+		assert.Equal([]uint64{1}, ids)
+		assert.Empty(values)
+		pos, n := header.vector(2)
+		assert.Equal(elementSize, n)
+		maximum := make([]byte, n)
+		for i := range maximum {
+			maximum[n-1-i] = header.buf[pos+i]
+		}
+		assert.Equal(new(big.Int).Sub(field, big.NewInt(1)), new(big.Int).SetBytes(maximum))
+		freeID := binary.LittleEndian.Uint64(header.buf[header.field(1):])
+
+		types, messages = readMessages(t, witness.Bytes())
+		assert.Equal([]uint8{1, 3}, types)
+		assignment := map[uint64]*big.Int{0: big.NewInt(1)}
+		ids, values = messages[0].table(0).variables(elementSize)
+		assert.Equal([]uint64{1}, ids)
+		assert.Equal([]*big.Int{big.NewInt(35)}, values)
+		assignment[1] = values[0]
+		ids, values = messages[1].table(0).variables(elementSize)
+		assert.Equal(len(ids), len(values))
+		assert.Equal(freeID, uint64(len(ids))+2)
+		for i, id := range ids {
+			assignment[id] = values[i]
+		}
+		assert.Equal(big.NewInt(3), assignment[2], "secret input")
+
+		// check the constraints with the assignment
+		eval := func(lc table) *big.Int {
+			ids, coeffs := lc.variables(elementSize)
+			res := new(big.Int)
+			for i, id := range ids {
+				v, ok := assignment[id]
+				assert.True(ok, "unassigned variable %d", id)
+				res.Add(res, new(big.Int).Mul(coeffs[i], v))
+			}
+			return res.Mod(res, field)
+		}
+		bilinear := constraints.tables(0)
+		assert.Equal(ccs.GetNbConstraints(), len(bilinear))
+		for i, c := range bilinear {
+			a, b := eval(c.table(0)), eval(c.table(1))
+			ab := new(big.Int).Mul(a, b)
+			assert.Equal(0, ab.Mod(ab, field).Cmp(eval(c.table(2))), "constraint %d", i)
+		}
+	}
+}
@@ -119,7 +119,8 @@ type API interface {
 	AssertIsLessOrEqual(v Variable, bound Variable)
 
 	// Println behaves like fmt.Println but accepts cd.Variable as parameter
-	// whose value will be resolved at runtime when computed by the solver
+	// whose value will be resolved at runtime when computed by the solver.
+	// See [Log] for leveled, conditional and structured logs.
 	Println(a ...Variable)
 
 	// Compiler returns the compiler object for advanced circuit development
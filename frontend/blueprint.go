@@ -0,0 +1,83 @@
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// Blueprint describes a custom constraint which can be registered once (see
+// RegisterBlueprint) and then instantiated in any circuit with AddBlueprint.
+//
+// A blueprint computes its outputs from its inputs at solving time (Solve) and
+// constrains them at compile time (Define). Solve is registered as a hint in
+// the solver registry, so proving a circuit using a registered blueprint needs
+// no extra solver option, whatever the arithmetization.
+type Blueprint interface {
+	// Name uniquely identifies the blueprint. It is used to derive the ID of the
+	// hint solving the blueprint and must thus be stable across versions.
+	Name() string
+
+	// NbOutputs returns the number of outputs computed for nbInputs inputs.
+	NbOutputs(nbInputs int) int
+
+	// Solve computes the outputs from the inputs.
+	Solve(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
+
+	// Define adds the constraints binding the outputs to the inputs.
+	Define(api API, inputs, outputs []Variable) error
+}
+
+var (
+	blueprints  = make(map[string]Blueprint)
+	blueprintsM sync.RWMutex
+)
+
+// RegisterBlueprint registers b in the global registry and the hint solving
+// it in the solver registry. It returns an error if a blueprint with the same
+// name is already registered.
+func RegisterBlueprint(b Blueprint) error {
+	blueprintsM.Lock()
+	defer blueprintsM.Unlock()
+	if _, ok := blueprints[b.Name()]; ok {
+		return fmt.Errorf("blueprint %q registered multiple times", b.Name())
+	}
+	blueprints[b.Name()] = b
+	solver.RegisterHint(blueprintHint(b))
+	return nil
+}
+
+// GetBlueprint returns the registered blueprint with the given name.
+func GetBlueprint(name string) (Blueprint, bool) {
+	blueprintsM.RLock()
+	defer blueprintsM.RUnlock()
+	b, ok := blueprints[name]
+	return b, ok
+}
+
+// AddBlueprint instantiates the registered blueprint name on inputs and
+// returns its outputs, computed by a hint and constrained by Blueprint.Define.
+func AddBlueprint(api API, name string, inputs ...Variable) ([]Variable, error) {
+	b, ok := GetBlueprint(name)
+	if !ok {
+		return nil, fmt.Errorf("blueprint %q is not registered", name)
+	}
+	nbOutputs := b.NbOutputs(len(inputs))
+	if nbOutputs <= 0 {
+		return nil, fmt.Errorf("blueprint %q: no output for %d inputs", name, len(inputs))
+	}
+	outputs, err := api.Compiler().NewHint(blueprintHint(b), nbOutputs, inputs...)
+	if err != nil {
+		return nil, fmt.Errorf("blueprint %q: %w", name, err)
+	}
+	if err := b.Define(api, inputs, outputs); err != nil {
+		return nil, fmt.Errorf("blueprint %q: %w", name, err)
+	}
+	return outputs, nil
+}
+
+func blueprintHint(b Blueprint) solver.Hint {
+	return solver.NewHint("blueprint/"+b.Name(), b.Solve)
+}
@@ -0,0 +1,82 @@
+package frontend_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// cubeBlueprint computes x³ and x⁴
+type cubeBlueprint struct{}
+
+func (cubeBlueprint) Name() string { return "test/cube" }
+
+func (cubeBlueprint) NbOutputs(int) int { return 2 }
+
+func (cubeBlueprint) Solve(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 {
+		return errors.New("expected one input")
+	}
+	outputs[0].Exp(inputs[0], big.NewInt(3), field)
+	outputs[1].Exp(inputs[0], big.NewInt(4), field)
+	return nil
+}
+
+func (cubeBlueprint) Define(api frontend.API, inputs, outputs []frontend.Variable) error {
+	x2 := api.Mul(inputs[0], inputs[0])
+	api.AssertIsEqual(outputs[0], api.Mul(x2, inputs[0]))
+	api.AssertIsEqual(outputs[1], api.Mul(x2, x2))
+	return nil
+}
+
+type blueprintCircuit struct {
+	X    frontend.Variable
+	Cube frontend.Variable `gnark:",public"`
+}
+
+func (c *blueprintCircuit) Define(api frontend.API) error {
+	res, err := frontend.AddBlueprint(api, cubeBlueprint{}.Name(), c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], c.Cube)
+	return nil
+}
+
+func TestBlueprint(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(frontend.RegisterBlueprint(cubeBlueprint{}))
+	assert.Error(frontend.RegisterBlueprint(cubeBlueprint{}))
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &blueprintCircuit{})
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(&blueprintCircuit{X: 3, Cube: 27}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w))
+
+		w, err = frontend.NewWitness(&blueprintCircuit{X: 3, Cube: 28}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(w))
+	}
+
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &unknownBlueprintCircuit{})
+	assert.Error(err)
+}
+
+type unknownBlueprintCircuit struct {
+	X frontend.Variable
+}
+
+func (c *unknownBlueprintCircuit) Define(api frontend.API) error {
+	_, err := frontend.AddBlueprint(api, "test/unknown", c.X)
+	return err
+}
@@ -0,0 +1,237 @@
+package frontend
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend/internal/expr"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+// This file provides boolean algebra over slices of variables. All the helpers
+// constrain their inputs to be boolean through api.AssertIsBoolean, which the
+// compilers skip for the variables already known to be boolean (see
+// [Compiler.IsBoolean] and [BooleanTracker]); the results are marked boolean,
+// so that chaining the helpers doesn't add booleanity constraints.
+//
+// The results are also cached per compiler: calling a helper again on the same
+// variables, as when negating a result or adding constants to a slice, returns
+// the variable computed the first time without adding constraints.
+
+// AssertIsBooleans asserts that all the variables are boolean. The constants
+// are checked at compile time, and the variables already known to be boolean
+// (results of And, Or, Xor, ToBinary, other assertions...) are not constrained
+// again.
+func AssertIsBooleans(api API, vs ...Variable) {
+	for i := range vs {
+		api.AssertIsBoolean(vs[i])
+	}
+}
+
+// Not returns 1 - v, v being constrained boolean. The result is marked boolean
+// and costs no constraint in R1CS.
+func Not(api API, v Variable) Variable {
+	api.AssertIsBoolean(v)
+	return cached(api, "not", []Variable{v}, func() Variable {
+		res := api.Sub(1, v)
+		markBoolean(api, res)
+		return res
+	})
+}
+
+// NotEach returns the negation of each of the variables, see [Not].
+func NotEach(api API, vs []Variable) []Variable {
+	res := make([]Variable, len(vs))
+	for i := range vs {
+		res[i] = Not(api, vs[i])
+	}
+	return res
+}
+
+// AndAll returns 1 if all the variables are 1, 0 otherwise. It returns 1 for no
+// variable.
+//
+// In R1CS, instead of a chain of len(vs)-1 multiplications, the result is
+// computed as IsZero(n - Σᵢ vᵢ), whose cost doesn't depend on the number of
+// variables. In PLONK, where the sums aren't free, it is the chain.
+func AndAll(api API, vs ...Variable) Variable {
+	vars, zero := nonConstantBooleans(api, vs, 0)
+	if zero {
+		return 0
+	}
+	switch len(vars) {
+	case 0:
+		return 1
+	case 1:
+		api.AssertIsBoolean(vars[0])
+		return vars[0]
+	case 2:
+		return api.And(vars[0], vars[1])
+	}
+	AssertIsBooleans(api, vars...)
+	return cached(api, "and", vars, func() Variable {
+		var res Variable
+		if sparse(vars) {
+			res = api.Mul(vars[0], vars[1], vars[2:]...)
+		} else {
+			res = api.IsZero(api.Sub(len(vars), sum(api, vars)))
+		}
+		markBoolean(api, res)
+		return res
+	})
+}
+
+// OrAll returns 1 if any of the variables is 1, 0 otherwise. It returns 0 for
+// no variable.
+//
+// In R1CS, instead of a chain of len(vs)-1 disjunctions, the result is computed
+// as 1 - IsZero(Σᵢ vᵢ), whose cost doesn't depend on the number of variables.
+// In PLONK, it is the chain.
+func OrAll(api API, vs ...Variable) Variable {
+	vars, one := nonConstantBooleans(api, vs, 1)
+	if one {
+		return 1
+	}
+	switch len(vars) {
+	case 0:
+		return 0
+	case 1:
+		api.AssertIsBoolean(vars[0])
+		return vars[0]
+	case 2:
+		return api.Or(vars[0], vars[1])
+	}
+	AssertIsBooleans(api, vars...)
+	return cached(api, "or", vars, func() Variable {
+		if sparse(vars) {
+			res := vars[0]
+			for i := 1; i < len(vars); i++ {
+				res = api.Or(res, vars[i])
+			}
+			return res
+		}
+		isZero := api.IsZero(sum(api, vars))
+		markBoolean(api, isZero)
+		return Not(api, isZero)
+	})
+}
+
+// XorAll returns the parity of the number of variables which are 1. It returns
+// 0 for no variable.
+//
+// In R1CS and for more than a few variables, the result is the least
+// significant bit of Σᵢ vᵢ, which costs about log₂(len(vs)) constraints instead
+// of len(vs)-1. In PLONK, it is the chain. The constants don't change the
+// cached result, only whether it is negated.
+func XorAll(api API, vs ...Variable) Variable {
+	// the constants only flip the result
+	var parity uint
+	vars := make([]Variable, 0, len(vs))
+	for i := range vs {
+		if c, ok := api.Compiler().ConstantValue(vs[i]); ok {
+			if !c.IsUint64() || c.Uint64() > 1 {
+				panic("xor of a non-boolean constant")
+			}
+			parity ^= uint(c.Uint64())
+			continue
+		}
+		vars = append(vars, vs[i])
+	}
+
+	var res Variable = 0
+	switch len(vars) {
+	case 0:
+	case 1:
+		api.AssertIsBoolean(vars[0])
+		res = vars[0]
+	default:
+		AssertIsBooleans(api, vars...)
+		res = cached(api, "xor", vars, func() Variable {
+			nbBits := bits.Len(uint(len(vars)))
+			if !sparse(vars) && nbBits+1 < len(vars)-1 {
+				// the sum is smaller than the modulus, its decomposition is unique
+				return api.ToBinary(sum(api, vars), nbBits)[0]
+			}
+			res := vars[0]
+			for i := 1; i < len(vars); i++ {
+				res = api.Xor(res, vars[i])
+			}
+			return res
+		})
+	}
+	if parity == 1 {
+		return Not(api, res)
+	}
+	return res
+}
+
+// nonConstantBooleans returns the variables of vs which are not constants, and
+// whether one of the constants is absorbing. The other constants are neutral
+// and dropped. It panics on non-boolean constants.
+func nonConstantBooleans(api API, vs []Variable, absorbing uint64) ([]Variable, bool) {
+	vars := make([]Variable, 0, len(vs))
+	for i := range vs {
+		c, ok := api.Compiler().ConstantValue(vs[i])
+		if !ok {
+			vars = append(vars, vs[i])
+			continue
+		}
+		if !c.IsUint64() || c.Uint64() > 1 {
+			panic("boolean operation on a non-boolean constant")
+		}
+		if c.Uint64() == absorbing {
+			return nil, true
+		}
+	}
+	return vars, false
+}
+
+// sparse returns whether the variables are built by the PLONK compiler, in
+// which each addition costs a constraint.
+func sparse(vs []Variable) bool {
+	_, ok := vs[0].(expr.Term)
+	return ok
+}
+
+type booleanCacheKey struct{}
+
+// cached returns the result of the operation op over the variables vs computed
+// by a previous call, or computes it with f and caches it if the compiler
+// allows. The variables are identified by their canonical representation, the
+// coefficients of which the compilers intern.
+func cached(api API, op string, vs []Variable, f func() Variable) Variable {
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		return f()
+	}
+	for i := range vs {
+		if !IsCanonical(vs[i]) {
+			return f()
+		}
+	}
+	cache, ok := kv.GetKeyValue(booleanCacheKey{}).(map[string]Variable)
+	if !ok {
+		cache = make(map[string]Variable)
+		kv.SetKeyValue(booleanCacheKey{}, cache)
+	}
+	key := op + fmt.Sprint(vs)
+	if res, ok := cache[key]; ok {
+		return res
+	}
+	res := f()
+	cache[key] = res
+	return res
+}
+
+func sum(api API, vs []Variable) Variable {
+	if len(vs) == 1 {
+		return vs[0]
+	}
+	return api.Add(vs[0], vs[1], vs[2:]...)
+}
+
+func markBoolean(api API, v Variable) {
+	if !api.Compiler().IsBoolean(v) {
+		api.Compiler().MarkBoolean(v)
+	}
+}
@@ -0,0 +1,111 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type booleanCircuit struct {
+	X            [8]frontend.Variable
+	And, Or, Xor frontend.Variable `gnark:",public"`
+	NotXor, Xor1 frontend.Variable `gnark:",public"`
+	naive        bool
+}
+
+func (c *booleanCircuit) Define(api frontend.API) error {
+	if c.naive {
+		and, or, xor := c.X[0], c.X[0], c.X[0]
+		for i := 1; i < len(c.X); i++ {
+			and = api.And(and, c.X[i])
+			or = api.Or(or, c.X[i])
+			xor = api.Xor(xor, c.X[i])
+		}
+		api.AssertIsEqual(and, c.And)
+		api.AssertIsEqual(or, c.Or)
+		api.AssertIsEqual(xor, c.Xor)
+		api.AssertIsEqual(api.Sub(1, xor), c.NotXor)
+		api.AssertIsEqual(api.Xor(xor, 1), c.Xor1)
+		return nil
+	}
+	frontend.AssertIsBooleans(api, c.X[:]...)
+	xor := frontend.XorAll(api, c.X[:]...)
+	api.AssertIsEqual(frontend.AndAll(api, c.X[:]...), c.And)
+	api.AssertIsEqual(frontend.OrAll(api, c.X[:]...), c.Or)
+	api.AssertIsEqual(xor, c.Xor)
+	api.AssertIsEqual(frontend.Not(api, xor), c.NotXor)
+	api.AssertIsEqual(frontend.XorAll(api, append(c.X[:], 1, 0)...), c.Xor1)
+	return nil
+}
+
+func TestBooleanAlgebra(t *testing.T) {
+	assert := require.New(t)
+
+	for _, x := range [][8]frontend.Variable{
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 0, 1, 1, 0, 1, 1, 1},
+	} {
+		var and, or, xor uint64 = 1, 0, 0
+		for i := range x {
+			b := uint64(x[i].(int))
+			and &= b
+			or |= b
+			xor ^= b
+		}
+		for _, naive := range []bool{false, true} {
+			witness := &booleanCircuit{X: x, And: and, Or: or, Xor: xor, NotXor: 1 - xor, Xor1: 1 - xor}
+			assert.NoError(test.IsSolved(&booleanCircuit{naive: naive}, witness, ecc.BN254.ScalarField()))
+			witness.Or = 1 - or
+			assert.Error(test.IsSolved(&booleanCircuit{naive: naive}, witness, ecc.BN254.ScalarField()))
+		}
+	}
+
+	x := [8]frontend.Variable{1, 1, 1, 1, 1, 1, 1, 2}
+	assert.Error(test.IsSolved(&booleanCircuit{}, &booleanCircuit{X: x, And: 0, Or: 1, Xor: 1, NotXor: 0, Xor1: 0}, ecc.BN254.ScalarField()))
+}
+
+func TestBooleanAlgebraSize(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		naive, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &booleanCircuit{naive: true})
+		assert.NoError(err)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &booleanCircuit{})
+		assert.NoError(err)
+		assert.Less(ccs.GetNbConstraints(), naive.GetNbConstraints())
+	}
+}
+
+type booleanTrackerCircuit struct {
+	X [4]frontend.Variable
+
+	added, elided *int
+}
+
+func (c *booleanTrackerCircuit) Define(api frontend.API) error {
+	frontend.AssertIsBooleans(api, c.X[:]...)
+	frontend.AssertIsBooleans(api, frontend.NotEach(api, c.X[:])...)
+	api.AssertIsEqual(frontend.AndAll(api, c.X[:]...), 0)
+	*c.added, *c.elided = api.Compiler().(frontend.BooleanTracker).NbBooleanAssertions()
+	return nil
+}
+
+func TestBooleanTracker(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var added, elided int
+		_, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &booleanTrackerCircuit{added: &added, elided: &elided})
+		assert.NoError(err)
+		// only the inputs are constrained, their negations and the second
+		// assertions in NotEach and AndAll are elided
+		assert.Equal(4, added)
+		assert.Equal(4*3, elided)
+	}
+}
@@ -52,6 +52,11 @@ type Compiler interface {
 	// Defer is called after circuit.Define() and before Compile(). This method
 	// allows for the circuits to register callbacks which finalize batching
 	// operations etc. Unlike Go defer, it is not locally scoped.
+	//
+	// Callbacks are run once each, in the order in which they were registered.
+	// A callback may itself call Defer; the new callback is then run after all
+	// the ones registered before it. An error returned by a callback aborts the
+	// compilation.
 	Defer(cb func(api API) error)
 }
 
@@ -79,6 +84,43 @@ type Committer interface {
 	Commit(toCommit ...Variable) (commitment Variable, err error)
 }
 
+// Linker allows to instantiate separately compiled constraint systems (fragments)
+// in the circuit being built, so that gadgets can be compiled once, serialized and
+// reused. Not all compilers implement this interface.
+type Linker interface {
+	// Link adds the constraints of fragment to the circuit, remapping its wires.
+	// The public inputs of the fragment (excluding the constant wire) are bound to
+	// inputs, in order. The secret inputs of the fragment are allocated as new
+	// internal variables and returned in order; the fragment constraints must allow
+	// the solver to compute them from the inputs.
+	Link(fragment constraint.ConstraintSystem, inputs ...Variable) (outputs []Variable, err error)
+}
+
+// Scoper allows to attribute constraints to named scopes, typically gadget
+// instances ("census/merkle[17]"). The scope of a constraint is reported in
+// debug information, logs, solver errors and profiles. Not all compilers
+// implement this interface; use [Scope] which is a no-op for them.
+type Scoper interface {
+	// PushScope enters the scope name, nested in the current scope.
+	PushScope(name string)
+
+	// PopScope leaves the current scope. It panics if there is no scope to leave.
+	PopScope()
+}
+
+// Scope enters the scope name if the compiler implements [Scoper] and returns a
+// function leaving it, meant to be deferred:
+//
+//	defer frontend.Scope(api, fmt.Sprintf("merkle[%d]", i))()
+func Scope(api API, name string) (pop func()) {
+	s, ok := api.Compiler().(Scoper)
+	if !ok {
+		return func() {}
+	}
+	s.PushScope(name)
+	return s.PopScope
+}
+
 // Rangechecker allows to externally range-check the variables to be of
 // specified width. Not all compilers implement this interface. Users should
 // instead use [github.com/consensys/gnark/std/rangecheck] package which
@@ -87,3 +129,13 @@ type Rangechecker interface {
 	// Check checks that the given variable v has bit-length bits.
 	Check(v Variable, bits int)
 }
+
+// BooleanTracker is implemented by the compilers keeping track of the variables
+// known to be boolean, so that they are constrained only once. Not all
+// compilers implement this interface.
+type BooleanTracker interface {
+	// NbBooleanAssertions returns the number of booleanity constraints added by
+	// AssertIsBoolean, and the number of assertions elided because the
+	// variable was already known to be boolean.
+	NbBooleanAssertions() (added, elided int)
+}
@@ -1,16 +1,22 @@
 package frontend
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
+	runtimedebug "runtime/debug"
+	"strings"
 
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Compile will generate a ConstraintSystem from the given circuit
@@ -33,7 +39,7 @@ import (
 //
 // initialCapacity is an optional parameter that reserves memory in slices
 // it should be set to the estimated number of constraints in the circuit, if known.
-func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (constraint.ConstraintSystem, error) {
+func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (ccs constraint.ConstraintSystem, err error) {
 	log := logger.Logger()
 	log.Info().Msg("compiling circuit")
 	// parse options
@@ -45,6 +51,16 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 		}
 	}
 
+	params, err := parametersMetadata(circuit)
+	if err != nil {
+		log.Err(err).Msg("validating circuit parameters")
+		return nil, fmt.Errorf("invalid circuit parameters: %w", err)
+	}
+
+	tracer := tracing.Tracer(opt.TracerProvider)
+	ctx, span := tracing.Start(opt.TraceContext, tracer, "gnark.compile", attribute.String("circuit", reflect.TypeOf(circuit).String()))
+	defer func() { tracing.End(span, err) }()
+
 	// instantiate new builder
 	builder, err := newBuilder(field, opt)
 	if err != nil {
@@ -54,17 +70,46 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 
 	// parse the circuit builds a schema of the circuit
 	// and call circuit.Define() method to initialize a list of constraints in the compiler
-	if err = parseCircuit(builder, circuit); err != nil {
+	_, defineSpan := tracer.Start(ctx, "gnark.compile.define")
+	publicInputs := newPublicInputsChecker()
+	err = parseCircuit(builder, circuit, opt, publicInputs)
+	tracing.End(defineSpan, err)
+	if err != nil {
 		log.Err(err).Msg("parsing circuit")
 		return nil, fmt.Errorf("parse circuit: %w", err)
 
 	}
 
 	// compile the circuit into its final form
-	return builder.Compile()
+	_, buildSpan := tracer.Start(ctx, "gnark.compile.build")
+	ccs, err = builder.Compile()
+	tracing.End(buildSpan, err)
+	if err != nil {
+		return nil, err
+	}
+	if opt.IgnoreUnconstrainedInputs && !opt.DryRun {
+		// otherwise the builder already failed on the unconstrained inputs
+		publicInputs.checkConstrained(ccs)
+	}
+	for _, issue := range publicInputs.issues {
+		log.Warn().Msg(issue)
+	}
+	if opt.StrictPublicInputs && len(publicInputs.issues) != 0 {
+		return nil, fmt.Errorf("invalid public inputs: %s", strings.Join(publicInputs.issues, "; "))
+	}
+	span.SetAttributes(attribute.Int("nbConstraints", ccs.GetNbConstraints()))
+	for k, v := range params {
+		ccs.SetMetadata(k, v)
+	}
+	for k, v := range opt.Metadata {
+		ccs.SetMetadata(k, v)
+	}
+	return ccs, nil
 }
 
-func parseCircuit(builder Builder, circuit Circuit) (err error) {
+// parseCircuit allocates the inputs of the circuit and defines it. The public
+// inputs are recorded in checker, if not nil.
+func parseCircuit(builder Builder, circuit Circuit, opt CompileConfig, checker *publicInputsChecker) (err error) {
 	// ensure circuit.Define has pointer receiver
 	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
 		return errors.New("frontend.Circuit methods must be defined on pointer receiver")
@@ -78,6 +123,17 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	log := logger.Logger()
 	log.Info().Int("nbSecret", s.Secret).Int("nbPublic", s.Public).Msg("parsed circuit inputs")
 
+	// when the public inputs are hashed, the only public variable is their hash
+	// and the declared public inputs are allocated as secret variables
+	var publicInputs []Variable
+	var publicInputsHash Variable
+	if opt.PublicInputsHasher != nil {
+		publicInputsHash = builder.PublicVariable(schema.LeafInfo{
+			Visibility: schema.Public,
+			FullName:   func() string { return publicInputsHashName },
+		})
+	}
+
 	// leaf handlers are called when encoutering leafs in the circuit data struct
 	// leafs are Constraints that need to be initialized in the context of compiling a circuit
 	variableAdder := func(targetVisibility schema.Visibility) func(f schema.LeafInfo, tInput reflect.Value) error {
@@ -87,7 +143,14 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 					return errors.New("can't set val " + f.FullName() + " visibility is unset")
 				}
 				if f.Visibility == targetVisibility {
-					if f.Visibility == schema.Public {
+					if f.Visibility == schema.Public && checker != nil {
+						checker.add(f, tInput)
+					}
+					if f.Visibility == schema.Public && opt.PublicInputsHasher != nil {
+						v := builder.SecretVariable(f)
+						publicInputs = append(publicInputs, v)
+						tInput.Set(reflect.ValueOf(v))
+					} else if f.Visibility == schema.Public {
 						tInput.Set(reflect.ValueOf(builder.PublicVariable(f)))
 					} else if f.Visibility == schema.Secret {
 						tInput.Set(reflect.ValueOf(builder.SecretVariable(f)))
@@ -126,17 +189,29 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	if err = callDeferred(builder); err != nil {
 		return fmt.Errorf("deferred: %w", err)
 	}
+	if opt.PublicInputsHasher != nil {
+		h, err := opt.PublicInputsHasher.Define(builder, publicInputs)
+		if err != nil {
+			return fmt.Errorf("hash public inputs: %w", err)
+		}
+		builder.AssertIsEqual(h, publicInputsHash)
+	}
 
 	return
 }
 
 func callDeferred(builder Builder) error {
-	for i, cb := range circuitdefer.GetAll[func(API) error](builder) {
-		if err := cb(builder); err != nil {
+	// deferred callbacks may themselves defer callbacks, which are run after
+	// the ones already registered
+	for i := 0; ; i++ {
+		deferred := circuitdefer.GetAll[func(API) error](builder)
+		if i >= len(deferred) {
+			return nil
+		}
+		if err := deferred[i](builder); err != nil {
 			return fmt.Errorf("defer fn %d: %w", i, err)
 		}
 	}
-	return nil
 }
 
 // CompileOption defines option for altering the behaviour of the Compile
@@ -148,6 +223,90 @@ type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
 	CompressThreshold         int
+	OptimizationLevel         int
+
+	// DryRun is set by EstimateSize; builders then only count constraints and
+	// the returned constraint system can't be solved.
+	DryRun bool
+
+	// ConstraintsByCaller is set by WithConstraintsByCaller.
+	ConstraintsByCaller bool
+
+	// PublicInputsHasher is set by WithPublicInputsHashing.
+	PublicInputsHasher PublicInputsHasher
+
+	// Metadata is set by WithMetadata and WithBuildMetadata.
+	Metadata constraint.Metadata
+
+	// TraceContext and TracerProvider are set by WithTracing.
+	TraceContext   context.Context
+	TracerProvider trace.TracerProvider
+
+	// DebugInfo is set by WithDebugInfo.
+	DebugInfo DebugInfoLevel
+
+	// StrictPublicInputs is set by WithStrictPublicInputs.
+	StrictPublicInputs bool
+}
+
+// DebugInfoLevel sets the debug information retained in the constraint system
+// to explain the constraints which are not satisfied, see WithDebugInfo.
+type DebugInfoLevel int
+
+const (
+	// DebugInfoFull retains the message of the assertions and the stack of the
+	// circuit code adding them. It is the default.
+	DebugInfoFull DebugInfoLevel = iota
+
+	// DebugInfoErrorsOnly retains the message of the assertions (the failing
+	// operation and its operands) without the stack.
+	DebugInfoErrorsOnly
+
+	// DebugInfoNone retains no debug information: the solver only reports the
+	// ID and the scope of the constraints which are not satisfied.
+	DebugInfoNone
+)
+
+// WithDebugInfo is a compile option which sets the debug information retained
+// in the constraint system. The stacks of DebugInfoFull may double the memory
+// used by the largest circuits; DebugInfoErrorsOnly still reports the failing
+// operations, at a fraction of the cost. The logs of api.Println are always
+// retained.
+func WithDebugInfo(level DebugInfoLevel) CompileOption {
+	return func(opt *CompileConfig) error {
+		if level < DebugInfoFull || level > DebugInfoNone {
+			return fmt.Errorf("invalid debug info level %d", level)
+		}
+		opt.DebugInfo = level
+		return nil
+	}
+}
+
+// WithStrictPublicInputs is a compile option which fails the compilation when
+// the public inputs of the circuit have issues, which are otherwise only logged:
+//   - a public input is bound more than once, for example through two pointers
+//     to the same struct: the variable of the first binding is overwritten, and
+//     the corresponding public wire is left free;
+//   - two public inputs have the same name, so that the assignments of the
+//     public witness (for example in JSON) are ambiguous;
+//   - a public input isn't constrained, which IgnoreUnconstrainedInputs would
+//     otherwise allow: the proofs are valid for any value of the input.
+func WithStrictPublicInputs() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.StrictPublicInputs = true
+		return nil
+	}
+}
+
+// WithTracing is a compile option which traces the compilation with OpenTelemetry
+// spans created from tp, children of the span in ctx (if any). If tp is nil, the
+// tracer provider set with tracing.SetTracerProvider is used.
+func WithTracing(ctx context.Context, tp trace.TracerProvider) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.TraceContext = ctx
+		opt.TracerProvider = tp
+		return nil
+	}
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -194,6 +353,91 @@ func WithCompressThreshold(threshold int) CompileOption {
 	}
 }
 
+// WithOptimizationLevel is a compile option which enables optimization passes
+// reducing the size of the constraint system:
+//   - level 0 (default) does not perform any optimization beyond the ones done
+//     by the builders;
+//   - level 1 deduplicates identical constraints and reuses the result of
+//     multiplications of identical expressions;
+//   - level 2 additionally removes internal wires which are never used,
+//     along with the constraints defining them.
+//
+// The optimizations preserve the set of accepted witnesses, but internal wires
+// and constraints may be renumbered.
+func WithOptimizationLevel(level int) CompileOption {
+	return func(opt *CompileConfig) error {
+		if level < 0 || level > 2 {
+			return fmt.Errorf("invalid optimization level %d", level)
+		}
+		opt.OptimizationLevel = level
+		return nil
+	}
+}
+
+// WithPublicInputsHashing is a compile option which replaces the public inputs
+// of the circuit by a single public input, their hash computed by h. The
+// declared public inputs become secret inputs, constrained to hash to the
+// public one.
+//
+// This reduces the verification cost (for example on-chain) of circuits with
+// many public inputs, at the cost of hashing them in the circuit. The witness
+// must be built with the same hasher (see HashPublicInputs), and the verifier
+// computes the public witness off-circuit from the public inputs.
+func WithPublicInputsHashing(h PublicInputsHasher) CompileOption {
+	return func(opt *CompileConfig) error {
+		if h == nil {
+			return errors.New("nil public inputs hasher")
+		}
+		opt.PublicInputsHasher = h
+		return nil
+	}
+}
+
+// WithMetadata is a compile option which sets the value of key in the metadata
+// of the constraint system (see constraint.Metadata), for example the version of
+// the circuit. The metadata are serialized with the constraint system and the
+// keys generated for it, but don't change its digest.
+func WithMetadata(key, value string) CompileOption {
+	return func(opt *CompileConfig) error {
+		if key == "" {
+			return errors.New("empty metadata key")
+		}
+		if opt.Metadata == nil {
+			opt.Metadata = make(constraint.Metadata)
+		}
+		opt.Metadata[key] = value
+		return nil
+	}
+}
+
+// WithBuildMetadata is a compile option which sets in the metadata of the
+// constraint system the version control information of the compiling binary,
+// as embedded by the go command: the keys "vcs", "vcs.revision", "vcs.time" and
+// "vcs.modified" (see runtime/debug.BuildInfo), and "main.path" and "main.version" for
+// its main module. It is a no-op if the binary doesn't embed the information.
+func WithBuildMetadata() CompileOption {
+	return func(opt *CompileConfig) error {
+		info, ok := runtimedebug.ReadBuildInfo()
+		if !ok {
+			return nil
+		}
+		if opt.Metadata == nil {
+			opt.Metadata = make(constraint.Metadata)
+		}
+		if info.Main.Path != "" {
+			opt.Metadata["main.path"] = info.Main.Path
+			opt.Metadata["main.version"] = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs", "vcs.revision", "vcs.time", "vcs.modified":
+				opt.Metadata[s.Key] = s.Value
+			}
+		}
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -0,0 +1,268 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/logger"
+)
+
+// CompileCached behaves as Compile, but stores the compiled constraint system in
+// the directory dir and reuses it on later calls.
+//
+// Entries are addressed by a hash of the circuit structure (types, field names,
+// tags and the values of non-Variable fields), the field, the builder, the compile
+// options (with the values of the fields of the public inputs hasher, if any)
+// and the gnark version. Values computed inside Define from other sources
+// than the circuit struct (globals, closures) are not part of the key; circuits
+// depending on them must not be cached.
+//
+// Circuits or options holding a func, a channel or an unsafe pointer have no
+// stable key and are refused with an error. A cache entry which can't be read
+// is ignored and replaced.
+func CompileCached(dir string, field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (constraint.ConstraintSystem, error) {
+	log := logger.Logger()
+
+	opt := CompileConfig{}
+	for _, o := range opts {
+		if err := o(&opt); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	key, err := compileCacheKey(field, newBuilder, opt, circuit)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, key+".ccs")
+
+	if ccs, err := readCachedCCS(path, field, newBuilder, opt); err == nil {
+		log.Info().Str("path", path).Msg("loaded constraint system from compile cache")
+		return ccs, nil
+	} else if !os.IsNotExist(err) {
+		log.Warn().Err(err).Str("path", path).Msg("ignoring invalid compile cache entry")
+	}
+
+	ccs, err := Compile(field, newBuilder, circuit, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeCachedCCS(dir, path, ccs); err != nil {
+		return nil, fmt.Errorf("write compile cache: %w", err)
+	}
+	return ccs, nil
+}
+
+func readCachedCCS(path string, field *big.Int, newBuilder NewBuilder, opt CompileConfig) (constraint.ConstraintSystem, error) {
+	f, err := os.Open(path) //#nosec G304 -- path is built from a hash in the cache directory
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// the concrete constraint system type depends on the builder and the field;
+	// we get it from an empty builder.
+	opt.IgnoreUnconstrainedInputs = true
+	builder, err := newBuilder(field, opt)
+	if err != nil {
+		return nil, err
+	}
+	empty, err := builder.Compile()
+	if err != nil {
+		return nil, err
+	}
+	ccs := reflect.New(reflect.TypeOf(empty).Elem()).Interface().(constraint.ConstraintSystem)
+	if _, err := ccs.ReadFrom(f); err != nil {
+		return nil, err
+	}
+	return ccs, nil
+}
+
+func writeCachedCCS(dir, path string, ccs constraint.ConstraintSystem) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	// write to a temporary file first, so that concurrent readers never see a partial entry
+	f, err := os.CreateTemp(dir, "ccs-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := ccs.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// compileCacheKey returns the hex encoded key of a circuit in the compile cache
+func compileCacheKey(field *big.Int, newBuilder NewBuilder, opt CompileConfig, circuit Circuit) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "gnark %s\n", gnark.Version.String())
+	fmt.Fprintf(h, "field %s\n", field.Text(16))
+	fmt.Fprintf(h, "builder %s\n", runtime.FuncForPC(reflect.ValueOf(newBuilder).Pointer()).Name())
+	if err := writeCompileConfig(h, opt); err != nil {
+		return "", err
+	}
+	if err := writeCircuitStructure(h, reflect.ValueOf(circuit), map[uintptr]struct{}{}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCompileConfig writes a canonical description of opt in h: each field by
+// name, with the same encoding as the circuit structure. Interface values are
+// described by their concrete type and content, never by their address, so that
+// equal options give the same key in every process. The tracing options don't
+// change the compiled constraint system and are not part of the key.
+func writeCompileConfig(h hash.Hash, opt CompileConfig) error {
+	v := reflect.ValueOf(opt)
+	t := v.Type()
+	fmt.Fprint(h, "config {")
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch f.Name {
+		case "TraceContext", "TracerProvider":
+			continue
+		}
+		fmt.Fprintf(h, "%s=", f.Name)
+		if err := writeCircuitStructure(h, v.Field(i), map[uintptr]struct{}{}); err != nil {
+			return fmt.Errorf("compile option %s: %w", f.Name, err)
+		}
+	}
+	fmt.Fprint(h, "}\n")
+	return nil
+}
+
+// writeCircuitStructure writes a deterministic description of v in w. Values are
+// read with the kind-specific accessors, so that unexported fields are part of
+// the description too. Funcs, channels and unsafe pointers have no stable
+// representation: a circuit holding one can't be cached.
+func writeCircuitStructure(w io.Writer, v reflect.Value, visited map[uintptr]struct{}) error {
+	if !v.IsValid() {
+		fmt.Fprint(w, "nil;")
+		return nil
+	}
+	fmt.Fprintf(w, "%s:", v.Type().String())
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+			return nil
+		}
+		if v.Type() == bigIntType && v.CanInterface() {
+			fmt.Fprintf(w, "%s;", v.Interface().(*big.Int).Text(16))
+			return nil
+		}
+		if _, ok := visited[v.Pointer()]; ok {
+			return fmt.Errorf("cycle in circuit structure at %s", v.Type().String())
+		}
+		visited[v.Pointer()] = struct{}{}
+		err := writeCircuitStructure(w, v.Elem(), visited)
+		delete(visited, v.Pointer())
+		return err
+	case reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+			return nil
+		}
+		return writeCircuitStructure(w, v.Elem(), visited)
+	case reflect.Struct:
+		t := v.Type()
+		fmt.Fprint(w, "{")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fmt.Fprintf(w, "%s `%s` ", f.Name, f.Tag)
+			if err := writeCircuitStructure(w, v.Field(i), visited); err != nil {
+				return err
+			}
+		}
+		fmt.Fprint(w, "}")
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(w, "[%d]", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := writeCircuitStructure(w, v.Index(i), visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sKeys := make([]string, len(keys))
+		for i := range keys {
+			var sb strings.Builder
+			if err := writeCircuitStructure(&sb, keys[i], visited); err != nil {
+				return err
+			}
+			sKeys[i] = sb.String()
+		}
+		sort.Sort(byKey{sKeys, keys})
+		fmt.Fprintf(w, "map[%d]", len(keys))
+		for i := range keys {
+			fmt.Fprintf(w, "%q=", sKeys[i])
+			if err := writeCircuitStructure(w, v.MapIndex(keys[i]), visited); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		// quoted, so that a string can't be mistaken for the structure around it
+		fmt.Fprintf(w, "%q", v.String())
+	case reflect.Bool:
+		fmt.Fprint(w, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprint(w, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprint(w, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprint(w, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprint(w, v.Complex())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+			return nil
+		}
+		return fmt.Errorf("%w: %s value in circuit structure", errNotCacheable, v.Type().String())
+	default:
+		return fmt.Errorf("%w: unsupported kind %s in circuit structure", errNotCacheable, v.Kind())
+	}
+	fmt.Fprint(w, ";")
+	return nil
+}
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// errNotCacheable is returned by CompileCached for circuits or options without
+// a stable description.
+var errNotCacheable = errors.New("circuit can't be cached")
+
+// byKey sorts map keys by their string representation
+type byKey struct {
+	s    []string
+	keys []reflect.Value
+}
+
+func (b byKey) Len() int           { return len(b.s) }
+func (b byKey) Less(i, j int) bool { return b.s[i] < b.s[j] }
+func (b byKey) Swap(i, j int) {
+	b.s[i], b.s[j] = b.s[j], b.s[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
@@ -0,0 +1,168 @@
+package frontend_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheCircuit struct {
+	X, Y  frontend.Variable `gnark:",public"`
+	Power int
+}
+
+func (c *cacheCircuit) Define(api frontend.API) error {
+	res := c.X
+	for i := 1; i < c.Power; i++ {
+		res = api.Mul(res, c.X)
+	}
+	api.AssertIsEqual(res, c.Y)
+	return nil
+}
+
+// unexportedCircuit is parameterized by an unexported field only.
+type unexportedCircuit struct {
+	X, Y  frontend.Variable `gnark:",public"`
+	power int
+}
+
+func (c *unexportedCircuit) Define(api frontend.API) error {
+	res := c.X
+	for i := 1; i < c.power; i++ {
+		res = api.Mul(res, c.X)
+	}
+	api.AssertIsEqual(res, c.Y)
+	return nil
+}
+
+// funcCircuit holds a func, which has no stable description.
+type funcCircuit struct {
+	X  frontend.Variable
+	Fn func(frontend.API, frontend.Variable) frontend.Variable
+}
+
+func (c *funcCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Fn(api, c.X), 0)
+	return nil
+}
+
+// sumHasher and otherSumHasher hash the public inputs the same way but are
+// distinct options.
+type sumHasher struct{ Offset int }
+
+func (h *sumHasher) Define(api frontend.API, publicInputs []frontend.Variable) (frontend.Variable, error) {
+	return api.Add(h.Offset, 0, publicInputs...), nil
+}
+
+func (h *sumHasher) Hash(field *big.Int, publicInputs []*big.Int) (*big.Int, error) {
+	res := big.NewInt(int64(h.Offset))
+	for _, p := range publicInputs {
+		res.Add(res, p)
+	}
+	return res.Mod(res, field), nil
+}
+
+type otherSumHasher struct{ sumHasher }
+
+// cacheEntries returns the number of entries in the compile cache dir
+func cacheEntries(t *testing.T, dir string) int {
+	entries, err := filepath.Glob(filepath.Join(dir, "*.ccs"))
+	require.NoError(t, err)
+	return len(entries)
+}
+
+func TestCompileCached(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	field := ecc.BN254.ScalarField()
+
+	compile := func(circuit frontend.Circuit, opts ...frontend.CompileOption) {
+		t.Helper()
+		cached, err := frontend.CompileCached(dir, field, r1cs.NewBuilder, circuit, opts...)
+		assert.NoError(err)
+		ccs, err := frontend.Compile(field, r1cs.NewBuilder, circuit, opts...)
+		assert.NoError(err)
+		assert.Equal(ccs.GetNbConstraints(), cached.GetNbConstraints())
+		assert.Equal(ccs.GetNbPublicVariables(), cached.GetNbPublicVariables())
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		compile(&cacheCircuit{Power: 3})
+		assert.Equal(1, cacheEntries(t, dir))
+
+		// the entry is read back rather than compiled and written again
+		entries, err := filepath.Glob(filepath.Join(dir, "*.ccs"))
+		assert.NoError(err)
+		before, err := os.Stat(entries[0])
+		assert.NoError(err)
+		compile(&cacheCircuit{Power: 3})
+		after, err := os.Stat(entries[0])
+		assert.NoError(err)
+		assert.Equal(before.ModTime(), after.ModTime())
+		assert.Equal(1, cacheEntries(t, dir))
+
+		// options with equal values, built separately, share the entry
+		compile(&cacheCircuit{Power: 3}, frontend.WithPublicInputsHashing(&sumHasher{Offset: 1}),
+			frontend.WithMetadata("a", "1"), frontend.WithMetadata("b", "2"))
+		assert.Equal(2, cacheEntries(t, dir))
+		compile(&cacheCircuit{Power: 3}, frontend.WithPublicInputsHashing(&sumHasher{Offset: 1}),
+			frontend.WithMetadata("b", "2"), frontend.WithMetadata("a", "1"))
+		assert.Equal(2, cacheEntries(t, dir))
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		n := cacheEntries(t, dir)
+		compile(&cacheCircuit{Power: 4})
+		assert.Equal(n+1, cacheEntries(t, dir), "circuit parameter")
+		compile(&cacheCircuit{Power: 4}, frontend.WithCompressThreshold(10))
+		assert.Equal(n+2, cacheEntries(t, dir), "compile option")
+		compile(&cacheCircuit{Power: 4}, frontend.WithPublicInputsHashing(&sumHasher{Offset: 2}))
+		assert.Equal(n+3, cacheEntries(t, dir), "hasher field value")
+		compile(&cacheCircuit{Power: 4}, frontend.WithPublicInputsHashing(&sumHasher{Offset: 3}))
+		assert.Equal(n+4, cacheEntries(t, dir), "hasher field value")
+
+		_, err := frontend.CompileCached(dir, field, scs.NewBuilder, &cacheCircuit{Power: 4})
+		assert.NoError(err)
+		assert.Equal(n+5, cacheEntries(t, dir), "builder")
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		// hashers of distinct types with the same field values must not share
+		// an entry
+		n := cacheEntries(t, dir)
+		compile(&cacheCircuit{Power: 5}, frontend.WithPublicInputsHashing(&sumHasher{Offset: 7}))
+		compile(&cacheCircuit{Power: 5}, frontend.WithPublicInputsHashing(&otherSumHasher{sumHasher{Offset: 7}}))
+		assert.Equal(n+2, cacheEntries(t, dir))
+
+		// nor metadata whose concatenation is the same
+		compile(&cacheCircuit{Power: 5}, frontend.WithMetadata("a", "b;c"))
+		compile(&cacheCircuit{Power: 5}, frontend.WithMetadata("a;b", "c"))
+		assert.Equal(n+4, cacheEntries(t, dir))
+	})
+
+	t.Run("unexported", func(t *testing.T) {
+		n := cacheEntries(t, dir)
+		small, err := frontend.CompileCached(dir, field, r1cs.NewBuilder, &unexportedCircuit{power: 2})
+		assert.NoError(err)
+		large, err := frontend.CompileCached(dir, field, r1cs.NewBuilder, &unexportedCircuit{power: 6})
+		assert.NoError(err)
+		assert.Equal(n+2, cacheEntries(t, dir))
+		assert.Equal(small.GetNbConstraints()+4, large.GetNbConstraints())
+	})
+
+	t.Run("not cacheable", func(t *testing.T) {
+		n := cacheEntries(t, dir)
+		_, err := frontend.CompileCached(dir, field, r1cs.NewBuilder, &funcCircuit{
+			Fn: func(api frontend.API, x frontend.Variable) frontend.Variable { return api.Sub(x, 1) },
+		})
+		assert.Error(err)
+		assert.Equal(n, cacheEntries(t, dir))
+	})
+}
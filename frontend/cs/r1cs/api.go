@@ -19,8 +19,6 @@ package r1cs
 import (
 	"errors"
 	"fmt"
-	"math/big"
-	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -28,12 +26,11 @@ import (
 
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/constraint/solver"
-	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
-	"github.com/consensys/gnark/logger"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/rs/zerolog"
 )
 
 // ---------------------------------------------------------------------------------------------
@@ -57,8 +54,13 @@ func (builder *builder) MulAcc(a, b, c frontend.Variable) frontend.Variable {
 
 		// v1 and v2 are both unknown, this is the only case we add a constraint
 		if !v1Constant && !v2Constant {
-			res := builder.newInternalVariable()
-			builder.cs.AddConstraint(builder.newR1C(b, c, res))
+			_b, _c := builder.toVariable(b), builder.toVariable(c)
+			res, ok := builder.mulExist(_b, _c)
+			if !ok {
+				res = builder.newInternalVariable()
+				builder.addConstraint(builder.newR1C(_b, _c, res))
+				builder.recordMul(_b, _c, res)
+			}
 			builder.mbuf1 = append(builder.mbuf1, res...)
 			return
 		}
@@ -204,8 +206,12 @@ func (builder *builder) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) f
 
 		// v1 and v2 are both unknown, this is the only case we add a constraint
 		if !v1Constant && !v2Constant {
+			if res, ok := builder.mulExist(v1, v2); ok {
+				return res
+			}
 			res := builder.newInternalVariable()
-			builder.cs.AddConstraint(builder.newR1C(v1, v2, res))
+			builder.addConstraint(builder.newR1C(v1, v2, res))
+			builder.recordMul(v1, v2, res)
 			return res
 		}
 
@@ -259,7 +265,7 @@ func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable
 		res := builder.newInternalVariable()
 		debug := builder.newDebugInfo("div", v1, "/", v2, " == ", res)
 		// note that here we don't ensure that divisor is != 0
-		builder.cs.AddConstraint(builder.newR1C(v2, res, v1), debug)
+		builder.addConstraint(builder.newR1C(v2, res, v1), debug)
 		return res
 	}
 
@@ -295,9 +301,9 @@ func (builder *builder) Div(i1, i2 frontend.Variable) frontend.Variable {
 		debug := builder.newDebugInfo("div", v1, "/", v2, " == ", res)
 		v2Inv := builder.newInternalVariable()
 		// note that here we ensure that v2 can't be 0, but it costs us one extra constraint
-		c1 := builder.cs.AddConstraint(builder.newR1C(v2, v2Inv, builder.cstOne()))
-		c2 := builder.cs.AddConstraint(builder.newR1C(v1, v2Inv, res))
-		builder.cs.AttachDebugInfo(debug, []int{c1, c2})
+		c1 := builder.addConstraint(builder.newR1C(v2, v2Inv, builder.cstOne()))
+		c2 := builder.addConstraint(builder.newR1C(v1, v2Inv, res))
+		builder.attachDebugInfo(debug, []int{c1, c2})
 		return res
 	}
 
@@ -333,7 +339,7 @@ func (builder *builder) Inverse(i1 frontend.Variable) frontend.Variable {
 	res := builder.newInternalVariable()
 
 	debug := builder.newDebugInfo("inverse", vars[0], "*", res, " == 1")
-	builder.cs.AddConstraint(builder.newR1C(res, vars[0], builder.cstOne()), debug)
+	builder.addConstraint(builder.newR1C(res, vars[0], builder.cstOne()), debug)
 
 	return res
 }
@@ -356,7 +362,7 @@ func (builder *builder) ToBinary(i1 frontend.Variable, n ...int) []frontend.Vari
 		}
 	}
 
-	return bits.ToBinary(builder, i1, bits.WithNbDigits(nbBits))
+	return bits.ToBinary(builder, i1, bits.WithWidth(nbBits))
 }
 
 // FromBinary packs b, seen as a fr.Element in little endian
@@ -409,7 +415,7 @@ func (builder *builder) Or(_a, _b frontend.Variable) frontend.Variable {
 
 	c = append(c, a...)
 	c = append(c, b...)
-	builder.cs.AddConstraint(builder.newR1C(a, b, c))
+	builder.addConstraint(builder.newR1C(a, b, c))
 
 	return res
 }
@@ -553,12 +559,12 @@ func (builder *builder) IsZero(i1 frontend.Variable) frontend.Variable {
 	}
 
 	// m = -a*x + 1         // constrain m to be 1 if a == 0
-	c1 := builder.cs.AddConstraint(builder.newR1C(builder.Neg(a), x[0], builder.Sub(m, 1)))
+	c1 := builder.addConstraint(builder.newR1C(builder.Neg(a), x[0], builder.Sub(m, 1)))
 
 	// a * m = 0            // constrain m to be 0 if a != 0
-	c2 := builder.cs.AddConstraint(builder.newR1C(a, m, builder.cstZero()))
+	c2 := builder.addConstraint(builder.newR1C(a, m, builder.cstZero()))
 
-	builder.cs.AttachDebugInfo(debug, []int{c1, c2})
+	builder.attachDebugInfo(debug, []int{c1, c2})
 
 	return m
 }
@@ -595,11 +601,31 @@ func (builder *builder) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 //
 // if one of the input is a variable, its value will be resolved avec R1CS.Solve() method is called
 func (builder *builder) Println(a ...frontend.Variable) {
-	var log constraint.LogEntry
+	cfg := frontend.LogConfig{Level: zerolog.DebugLevel}
 
 	// prefix log line with file.go:line
 	if _, file, line, ok := runtime.Caller(1); ok {
-		log.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+		cfg.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	builder.Log(cfg, a...)
+}
+
+// Log implements [frontend.Logger].
+func (builder *builder) Log(cfg frontend.LogConfig, a ...frontend.Variable) {
+	log := constraint.LogEntry{Caller: cfg.Caller, Level: cfg.Level, Keys: cfg.Keys}
+
+	if cfg.Condition != nil {
+		if c, ok := builder.constantValue(cfg.Condition); ok {
+			if c.IsZero() {
+				return
+			}
+		} else {
+			log.Condition = builder.getLinearExpression(builder.toVariable(cfg.Condition))
+		}
+	}
+	for _, v := range cfg.Values {
+		log.Values = append(log.Values, builder.getLinearExpression(builder.toVariable(v)))
 	}
 
 	var sbb strings.Builder
@@ -729,12 +755,12 @@ func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error
 
 	// hint is used at solving time to compute the actual value of the commitment
 	// it is going to be dynamically replaced at solving time.
-	hintOut, err := builder.NewHint(solver.NewHint("bsb22_compute_placeholder", bsb22CommitmentComputePlaceholder), 1, builder.getCommittedVariables(&commitment)...)
+	hintOut, err := builder.NewHint(constraint.Bsb22CommitmentHint, 1, builder.getCommittedVariables(&commitment)...)
 	if err != nil {
 		return nil, err
 	}
 	cVar := hintOut[0]
-	commitment.HintID = solver.GetHintID("bsb22_compute_placeholder") // TODO @gbotrel probably not needed
+	commitment.HintID = constraint.Bsb22CommitmentHint.ID // TODO @gbotrel probably not needed
 
 	commitment.CommitmentIndex = (cVar.(expr.LinearExpression))[0].WireID()
 
@@ -758,18 +784,3 @@ func (builder *builder) getCommittedVariables(i *constraint.Commitment) []fronte
 	}
 	return res
 }
-
-func bsb22CommitmentComputePlaceholder(_ *big.Int, _ []*big.Int, output []*big.Int) error {
-	if (len(os.Args) > 0 && (strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], ".test.exe"))) || debug.Debug {
-		// usually we only run solver without prover during testing
-		log := logger.Logger()
-		log.Error().Msg("Augmented groth16 commitment hint not replaced. Proof will not be sound!")
-		output[0].SetInt64(0)
-		return nil
-	}
-	return fmt.Errorf("placeholder function: to be replaced by commitment computation")
-}
-
-func init() {
-	solver.RegisterHint(solver.NewHint("bsb22_compute_placeholder", bsb22CommitmentComputePlaceholder))
-}
@@ -34,7 +34,7 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 
 	debug := builder.newDebugInfo("assertIsEqual", r, " == ", o)
 
-	builder.cs.AddConstraint(builder.newR1C(builder.cstOne(), r, o), debug)
+	builder.addConstraint(builder.newR1C(builder.cstOne(), r, o), debug)
 }
 
 // AssertIsDifferent constrain i1 and i2 to be different
@@ -60,9 +60,11 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 	}
 
 	if builder.IsBoolean(v) {
+		builder.nbBooleanElided++
 		return // linearExpression is already constrained
 	}
 	builder.MarkBoolean(v)
+	builder.nbBooleanAdded++
 
 	// ensure v * (1 - v) == 0
 	_v := builder.Sub(builder.cstOne(), v)
@@ -73,9 +75,9 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 
 	if debug.Debug {
 		debug := builder.newDebugInfo("assertIsBoolean", V, " == (0|1)")
-		builder.cs.AddConstraint(builder.newR1C(V, _v, o), debug)
+		builder.addConstraint(builder.newR1C(V, _v, o), debug)
 	} else {
-		builder.cs.AddConstraint(builder.newR1C(V, _v, o))
+		builder.addConstraint(builder.newR1C(V, _v, o))
 	}
 }
 
@@ -117,7 +119,7 @@ func (builder *builder) mustBeLessOrEqVar(a, bound frontend.Variable) {
 
 	nbBits := builder.cs.FieldBitLen()
 
-	aBits := bits.ToBinary(builder, a, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	aBits := bits.ToBinary(builder, a, bits.WithWidth(nbBits), bits.WithUnchecked())
 	boundBits := builder.ToBinary(bound, nbBits)
 
 	// constraint added
@@ -153,13 +155,13 @@ func (builder *builder) mustBeLessOrEqVar(a, bound frontend.Variable) {
 		if aConst {
 			// aBits[i] is a constant;
 			l = builder.Mul(l, aBits[i])
-			added = append(added, builder.cs.AddConstraint(builder.newR1C(l, zero, zero)))
+			added = append(added, builder.addConstraint(builder.newR1C(l, zero, zero)))
 		} else {
-			added = append(added, builder.cs.AddConstraint(builder.newR1C(l, aBits[i], zero)))
+			added = append(added, builder.addConstraint(builder.newR1C(l, aBits[i], zero)))
 		}
 	}
 
-	builder.cs.AttachDebugInfo(debug, added)
+	builder.attachDebugInfo(debug, added)
 
 }
 
@@ -180,7 +182,7 @@ func (builder *builder) mustBeLessOrEqCst(a expr.LinearExpression, bound *big.In
 
 	// note that at this stage, we didn't boolean-constraint these new variables yet
 	// (as opposed to ToBinary)
-	aBits := bits.ToBinary(builder, a, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	aBits := bits.ToBinary(builder, a, bits.WithWidth(nbBits), bits.WithUnchecked())
 
 	// t trailing bits in the bound
 	t := 0
@@ -212,13 +214,13 @@ func (builder *builder) mustBeLessOrEqCst(a expr.LinearExpression, bound *big.In
 			l := builder.Sub(1, p[i+1])
 			l = builder.Sub(l, aBits[i])
 
-			added = append(added, builder.cs.AddConstraint(builder.newR1C(l, aBits[i], builder.cstZero())))
+			added = append(added, builder.addConstraint(builder.newR1C(l, aBits[i], builder.cstZero())))
 		} else {
 			builder.AssertIsBoolean(aBits[i])
 		}
 	}
 
 	if len(added) != 0 {
-		builder.cs.AttachDebugInfo(debug, added)
+		builder.attachDebugInfo(debug, added)
 	}
 }
@@ -41,7 +41,7 @@ import (
 )
 
 // NewBuilder returns a new R1CS builder which implements frontend.API.
-// Additionally, this builder also implements [frontend.Committer].
+// Additionally, this builder also implements [frontend.Committer] and [frontend.Linker].
 func NewBuilder(field *big.Int, config frontend.CompileConfig) (frontend.Builder, error) {
 	return newBuilder(field, config), nil
 }
@@ -54,6 +54,14 @@ type builder struct {
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]expr.LinearExpression
 
+	// number of booleanity constraints added and elided, see NbBooleanAssertions
+	nbBooleanAdded, nbBooleanElided int
+
+	// records constraints and products to avoid duplicates when the optimization
+	// level is set. see addConstraint(...) and mulExist(...)
+	mConstraints map[uint64][]int
+	mProducts    map[uint64][]product
+
 	tOne constraint.Coeff
 
 	// helps merge k sorted linear expressions
@@ -79,6 +87,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		mbuf2:      make(expr.LinearExpression, 0, macCapacity),
 		Store:      kvstore.New(),
 	}
+	if config.OptimizationLevel > 0 {
+		builder.mConstraints = make(map[uint64][]int, config.Capacity/2)
+		builder.mProducts = make(map[uint64][]product, config.Capacity/2)
+	}
 
 	// by default the circuit is given a public wire equal to 1
 
@@ -94,6 +106,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		}
 		panic("not implemented")
 	}
+	if config.DryRun {
+		builder.cs = &dryRunR1CS{R1CS: builder.cs, store: builder.mConstraints != nil}
+	}
 
 	builder.tOne = builder.cs.One()
 	builder.cs.AddPublicVariable("1")
@@ -231,6 +246,11 @@ func (builder *builder) IsBoolean(v frontend.Variable) bool {
 	return false
 }
 
+// NbBooleanAssertions implements [frontend.BooleanTracker].
+func (builder *builder) NbBooleanAssertions() (added, elided int) {
+	return builder.nbBooleanAdded, builder.nbBooleanElided
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -245,6 +265,10 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
+	if builder.config.DryRun {
+		return builder.cs, nil
+	}
+
 	// ensure all inputs and hints are constrained
 	if err := builder.cs.CheckUnconstrainedWires(); err != nil {
 		log.Warn().Msg("circuit has unconstrained inputs")
@@ -253,6 +277,14 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if builder.config.OptimizationLevel >= 2 {
+		nbRemoved := builder.cs.RemoveDeadWires()
+		log.Debug().
+			Int("nbRemovedWires", nbRemoved).
+			Int("nbConstraints", builder.cs.GetNbConstraints()).
+			Msg("removed dead wires")
+	}
+
 	return builder.cs, nil
 }
 
@@ -393,6 +425,9 @@ func assertIsSet(l expr.LinearExpression) {
 // to build logs for both debug and println
 // and append some program location.. (see other todo in debug_info.go)
 func (builder *builder) newDebugInfo(errName string, in ...interface{}) constraint.DebugInfo {
+	if builder.config.DebugInfo == frontend.DebugInfoNone {
+		return constraint.DebugInfo{}
+	}
 	for i := 0; i < len(in); i++ {
 		// for inputs that are LinearExpressions or Term, we need to "Make" them in the backend.
 		// TODO @gbotrel this is a duplicate effort with adding a constraint and should be taken care off
@@ -413,6 +448,9 @@ func (builder *builder) newDebugInfo(errName string, in ...interface{}) constrai
 		}
 	}
 
+	if builder.config.DebugInfo == frontend.DebugInfoErrorsOnly {
+		return builder.cs.NewDebugMessage(errName, in...)
+	}
 	return builder.cs.NewDebugInfo(errName, in...)
 
 }
@@ -428,14 +466,120 @@ func (builder *builder) compress(le expr.LinearExpression) expr.LinearExpression
 
 	one := builder.cstOne()
 	t := builder.newInternalVariable()
-	builder.cs.AddConstraint(builder.newR1C(le, one, t))
+	builder.addConstraint(builder.newR1C(le, one, t))
 	return t
 }
 
+// attachDebugInfo attaches debugInfo to the constraints, unless the debug
+// information is disabled (see frontend.DebugInfoNone).
+func (builder *builder) attachDebugInfo(debugInfo constraint.DebugInfo, constraintID []int) {
+	if builder.config.DebugInfo == frontend.DebugInfoNone {
+		return
+	}
+	builder.cs.AttachDebugInfo(debugInfo, constraintID)
+}
+
+// addConstraint adds the constraint to the constraint system and returns its id.
+// If the optimization level is set and the same constraint was already added, it
+// returns the id of the existing constraint instead.
+func (builder *builder) addConstraint(r1c constraint.R1C, debugInfo ...constraint.DebugInfo) int {
+	if builder.config.DebugInfo == frontend.DebugInfoNone {
+		debugInfo = nil
+	}
+	if builder.mConstraints == nil {
+		return builder.cs.AddConstraint(r1c, debugInfo...)
+	}
+
+	h := hashR1C(&r1c)
+	if cIDs, ok := builder.mConstraints[h]; ok {
+		constraints, _ := builder.cs.GetConstraints()
+		for _, cID := range cIDs {
+			if equalR1C(&constraints[cID], &r1c) {
+				return cID
+			}
+		}
+	}
+
+	cID := builder.cs.AddConstraint(r1c, debugInfo...)
+	builder.mConstraints[h] = append(builder.mConstraints[h], cID)
+	return cID
+}
+
+// product records the result of a multiplication of two linear expressions
+type product struct {
+	a, b, res expr.LinearExpression
+}
+
+// mulExist returns the result of a previous multiplication a * b, if any.
+// The returned expression is a copy, so that the caller may mutate it.
+func (builder *builder) mulExist(a, b expr.LinearExpression) (expr.LinearExpression, bool) {
+	if builder.mProducts == nil {
+		return nil, false
+	}
+	for _, p := range builder.mProducts[hashProduct(a, b)] {
+		if (p.a.Equal(a) && p.b.Equal(b)) || (p.a.Equal(b) && p.b.Equal(a)) {
+			return p.res.Clone(), true
+		}
+	}
+	return nil, false
+}
+
+// recordMul records the result of a multiplication a * b (see mulExist)
+func (builder *builder) recordMul(a, b, res expr.LinearExpression) {
+	if builder.mProducts == nil {
+		return
+	}
+	h := hashProduct(a, b)
+	builder.mProducts[h] = append(builder.mProducts[h], product{a: a.Clone(), b: b.Clone(), res: res.Clone()})
+}
+
+// hashProduct is symmetric since multiplication is commutative
+func hashProduct(a, b expr.LinearExpression) uint64 {
+	return a.HashCode() ^ b.HashCode()
+}
+
+func hashR1C(r1c *constraint.R1C) uint64 {
+	h := uint64(17)
+	for _, l := range []constraint.LinearExpression{r1c.L, r1c.R, r1c.O} {
+		for _, t := range l {
+			h = h*23 + uint64(t.CID)*29 + uint64(t.VID)<<12
+		}
+		h = h*31 + uint64(len(l))
+	}
+	return h
+}
+
+func equalR1C(a, b *constraint.R1C) bool {
+	equal := func(l, o constraint.LinearExpression) bool {
+		if len(l) != len(o) {
+			return false
+		}
+		for i := range l {
+			if l[i] != o[i] {
+				return false
+			}
+		}
+		return true
+	}
+	return equal(a.L, b.L) && equal(a.R, b.R) && equal(a.O, b.O)
+}
+
 func (builder *builder) Defer(cb func(frontend.API) error) {
 	circuitdefer.Put(builder, cb)
 }
 
+// PushScope implements [frontend.Scoper]
+func (builder *builder) PushScope(name string) {
+	builder.cs.PushScope(name, builder.cs.GetNbConstraints())
+}
+
+// PopScope implements [frontend.Scoper]
+func (builder *builder) PopScope() {
+	if err := builder.cs.PopScope(builder.cs.GetNbConstraints()); err != nil {
+		panic(err)
+	}
+}
+
 func (*builder) FrontendType() frontendtype.Type {
 	return frontendtype.R1CS
 }
@@ -0,0 +1,41 @@
+package r1cs
+
+import (
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/profile"
+)
+
+// dryRunR1CS is used by the builder in dry-run mode (see frontend.EstimateSize). It
+// doesn't record debug information and only counts the constraints, unless the
+// builder needs to look them up to deduplicate them.
+type dryRunR1CS struct {
+	constraint.R1CS
+	store         bool
+	nbConstraints int
+}
+
+func (cs *dryRunR1CS) AddConstraint(r1c constraint.R1C, _ ...constraint.DebugInfo) int {
+	if cs.store {
+		return cs.R1CS.AddConstraint(r1c)
+	}
+	profile.RecordConstraint()
+	cs.nbConstraints++
+	return cs.nbConstraints - 1
+}
+
+func (cs *dryRunR1CS) GetNbConstraints() int {
+	if cs.store {
+		return cs.R1CS.GetNbConstraints()
+	}
+	return cs.nbConstraints
+}
+
+func (cs *dryRunR1CS) NewDebugInfo(string, ...interface{}) constraint.DebugInfo {
+	return constraint.DebugInfo{}
+}
+
+func (cs *dryRunR1CS) NewDebugMessage(string, ...interface{}) constraint.DebugInfo {
+	return constraint.DebugInfo{}
+}
+
+func (cs *dryRunR1CS) AttachDebugInfo(constraint.DebugInfo, []int) {}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package r1cs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/constraint"
+	bn254r1cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/solver"
+	tinyfieldr1cs "github.com/consensys/gnark/constraint/tinyfield"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/internal/expr"
+)
+
+// Link instantiates the constraints of a separately compiled R1CS fragment in the
+// circuit being built. See [frontend.Linker].
+func (builder *builder) Link(fragment constraint.ConstraintSystem, inputs ...frontend.Variable) ([]frontend.Variable, error) {
+	f, ok := fragment.(constraint.R1CS)
+	if !ok {
+		return nil, errors.New("fragment is not a R1CS")
+	}
+	if f.Field().Cmp(builder.Field()) != 0 {
+		return nil, errors.New("fragment is defined over a different field")
+	}
+
+	var core *constraint.R1CSCore
+	switch t := f.(type) {
+	case *bn254r1cs.R1CS:
+		core = &t.R1CSCore
+	case *tinyfieldr1cs.R1CS:
+		core = &t.R1CSCore
+	default:
+		return nil, fmt.Errorf("unsupported fragment type %T", fragment)
+	}
+	if core.CommitmentInfo.Is() {
+		return nil, errors.New("linking fragments with commitments is not supported")
+	}
+
+	nbPublic, nbSecret := core.GetNbPublicVariables(), core.GetNbSecretVariables()
+	if len(inputs) != nbPublic-1 {
+		return nil, fmt.Errorf("fragment expects %d inputs, got %d", nbPublic-1, len(inputs))
+	}
+
+	// wires maps the wires of the fragment to linear expressions in the circuit.
+	wires := make([]expr.LinearExpression, nbPublic+nbSecret+core.NbInternalVariables)
+	wires[0] = builder.cstOne()
+	for i, in := range inputs {
+		wires[i+1] = builder.toVariable(in)
+	}
+	outputs := make([]frontend.Variable, nbSecret)
+	for i := range outputs {
+		wires[nbPublic+i] = builder.newInternalVariable()
+		outputs[i] = wires[nbPublic+i]
+	}
+
+	// internal wires are allocated in order, hint outputs being allocated with their hint.
+	// hint inputs only depend on wires allocated before the hint outputs.
+	for wID := nbPublic + nbSecret; wID < len(wires); wID++ {
+		if wires[wID] != nil {
+			continue
+		}
+		hID, isHint := core.MHints[wID]
+		if !isHint {
+			wires[wID] = builder.newInternalVariable()
+			continue
+		}
+		h := &core.HintMappings[hID]
+		hintInputs := make([]constraint.LinearExpression, len(h.Inputs))
+		for i, in := range h.Inputs {
+			hintInputs[i] = builder.linkHintInput(f, in, wires)
+		}
+		vIDs, err := builder.cs.AddSolverHint(solver.Hint{ID: h.HintID}, hintInputs, len(h.Outputs))
+		if err != nil {
+			return nil, err
+		}
+		for i, out := range h.Outputs {
+			wires[out] = expr.NewLinearExpression(vIDs[i], builder.tOne)
+		}
+	}
+
+	constraints, _ := f.GetConstraints()
+	for _, r1c := range constraints {
+		L := builder.linkLinearExpression(f, r1c.L, wires)
+		R := builder.linkLinearExpression(f, r1c.R, wires)
+		O := builder.linkLinearExpression(f, r1c.O, wires)
+		builder.addConstraint(builder.newR1C(L, R, O))
+	}
+
+	return outputs, nil
+}
+
+// linkLinearExpression returns Σ cᵢ⋅wires[vᵢ] for the terms of l
+func (builder *builder) linkLinearExpression(f constraint.R1CS, l constraint.LinearExpression, wires []expr.LinearExpression) expr.LinearExpression {
+	vars := make([]expr.LinearExpression, 0, len(l))
+	capacity := 0
+	for _, t := range l {
+		c := f.GetCoefficient(t.CoeffID())
+		v := builder.mulConstant(wires[t.WireID()], c, false)
+		vars = append(vars, v)
+		capacity += len(v)
+	}
+	if len(vars) == 0 {
+		return builder.cstZero()
+	}
+	return builder.add(vars, false, capacity, nil).(expr.LinearExpression)
+}
+
+// linkHintInput is as linkLinearExpression, but preserves the constant terms used as hint inputs.
+func (builder *builder) linkHintInput(f constraint.R1CS, l constraint.LinearExpression, wires []expr.LinearExpression) constraint.LinearExpression {
+	res := make(constraint.LinearExpression, 0, len(l))
+	for _, t := range l {
+		c := f.GetCoefficient(t.CoeffID())
+		if t.IsConstant() {
+			term := builder.cs.MakeTerm(&c, 0)
+			term.MarkConstant()
+			res = append(res, term)
+			continue
+		}
+		for _, w := range builder.mulConstant(wires[t.WireID()], c, false) {
+			res = append(res, builder.cs.MakeTerm(&w.Coeff, w.VID))
+		}
+	}
+	return res
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package r1cs
 
 import (
+	"errors"
+	"math/big"
 	"math/rand"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/internal/expr"
 )
@@ -160,3 +164,171 @@ func TestPreCompileHook(t *testing.T) {
 		t.Error("callback not called")
 	}
 }
+
+type dupCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (c *dupCircuit) Define(api frontend.API) error {
+	ab := api.Mul(c.A, c.B)
+	ba := api.Mul(c.B, c.A) // same product
+	api.AssertIsEqual(ab, c.C)
+	api.AssertIsEqual(ba, c.C) // same constraint once the product is reused
+
+	_ = api.Mul(ab, c.A, c.B) // dead wires
+	return nil
+}
+
+func TestOptimizationLevel(t *testing.T) {
+	for _, tc := range []struct {
+		level, nbConstraints int
+	}{
+		{0, 6},
+		{1, 4},
+		{2, 2},
+	} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &dupCircuit{}, frontend.WithOptimizationLevel(tc.level))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ccs.GetNbConstraints() != tc.nbConstraints {
+			t.Fatalf("level %d: expected %d constraints, got %d", tc.level, tc.nbConstraints, ccs.GetNbConstraints())
+		}
+		w, err := frontend.NewWitness(&dupCircuit{A: 3, B: 5, C: 15}, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.Solve(w); err != nil {
+			t.Fatalf("level %d: %v", tc.level, err)
+		}
+	}
+}
+
+// deadProductCircuit has a dead wire numbered before wires whose linear
+// expressions are shared between constraints.
+type deadProductCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *deadProductCircuit) Define(api frontend.API) error {
+	api.Mul(c.X, c.Y)
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func TestOptimizationLevelSharedTerms(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &deadProductCircuit{}, frontend.WithOptimizationLevel(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := frontend.NewWitness(&deadProductCircuit{X: 3, Y: 27}, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ccs.Solve(w); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type squareFragment struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *squareFragment) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Y, api.Mul(c.X, c.X))
+	return nil
+}
+
+type linkCircuit struct {
+	fragment constraint.ConstraintSystem
+	X, Y     frontend.Variable
+}
+
+func (c *linkCircuit) Define(api frontend.API) error {
+	linker := api.Compiler().(frontend.Linker)
+	x2, err := linker.Link(c.fragment, api.Add(c.X, 1))
+	if err != nil {
+		return err
+	}
+	x4, err := linker.Link(c.fragment, x2[0])
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(x4[0], c.Y)
+	return nil
+}
+
+func TestLink(t *testing.T) {
+	fragment, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &squareFragment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &linkCircuit{fragment: fragment})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		y       int
+		success bool
+	}{
+		{81, true},
+		{80, false},
+	} {
+		w, err := frontend.NewWitness(&linkCircuit{X: 2, Y: tc.y}, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.Solve(w); (err == nil) != tc.success {
+			t.Fatalf("y=%d: unexpected solver result: %v", tc.y, err)
+		}
+	}
+}
+
+func doubleHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].Lsh(inputs[0], 1)
+	return nil
+}
+
+type hintCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *hintCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(solver.NewHint("test_double", doubleHint), 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], api.Mul(c.X, 2))
+	api.AssertIsEqual(res[0], c.Y)
+	return nil
+}
+
+func TestMissingHints(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &hintCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := solver.GetHintID("test_double")
+	if ids := ccs.GetHintIDs(); len(ids) != 1 || ids[0] != id {
+		t.Fatalf("unexpected hint IDs %v", ids)
+	}
+	if name := ccs.GetHintName(id); name != "test_double" {
+		t.Fatalf("unexpected hint name %q", name)
+	}
+
+	w, err := frontend.NewWitness(&hintCircuit{X: 2, Y: 4}, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ccs.Solve(w)
+	var missing *solver.MissingHintsError
+	if !errors.As(err, &missing) || len(missing.IDs) != 1 || missing.IDs[0] != id || missing.Names[0] != "test_double" {
+		t.Fatalf("expected missing hint error, got %v", err)
+	}
+	if _, err = ccs.Solve(w, solver.WithHints(solver.NewHint("test_double", doubleHint))); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package scs
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/consensys/gnark/constraint"
@@ -30,6 +32,7 @@ import (
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/frontendtype"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/rs/zerolog"
 )
 
 // Add returns res = i1+i2+...in
@@ -179,7 +182,7 @@ func (builder *builder) ToBinary(i1 frontend.Variable, n ...int) []frontend.Vari
 		}
 	}
 
-	return bits.ToBinary(builder, i1, bits.WithNbDigits(nbBits))
+	return bits.ToBinary(builder, i1, bits.WithWidth(nbBits))
 }
 
 // FromBinary packs b, seen as a fr.Element in little endian
@@ -493,11 +496,31 @@ func (builder *builder) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 //
 // if one of the input is a variable, its value will be resolved when R1CS.Solve() method is called
 func (builder *builder) Println(a ...frontend.Variable) {
-	var log constraint.LogEntry
+	cfg := frontend.LogConfig{Level: zerolog.DebugLevel}
 
 	// prefix log line with file.go:line
 	if _, file, line, ok := runtime.Caller(1); ok {
-		log.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+		cfg.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+
+	builder.Log(cfg, a...)
+}
+
+// Log implements [frontend.Logger].
+func (builder *builder) Log(cfg frontend.LogConfig, a ...frontend.Variable) {
+	log := constraint.LogEntry{Caller: cfg.Caller, Level: cfg.Level, Keys: cfg.Keys}
+
+	if cfg.Condition != nil {
+		if c, ok := builder.constantValue(cfg.Condition); ok {
+			if c.IsZero() {
+				return
+			}
+		} else {
+			log.Condition = builder.logExpression(cfg.Condition)
+		}
+	}
+	for _, v := range cfg.Values {
+		log.Values = append(log.Values, builder.logExpression(v))
 	}
 
 	var sbb strings.Builder
@@ -523,6 +546,17 @@ func (builder *builder) Println(a ...frontend.Variable) {
 	builder.cs.AddLog(log)
 }
 
+// logExpression returns the expression evaluated by the solver to print v.
+func (builder *builder) logExpression(v frontend.Variable) constraint.LinearExpression {
+	if t, ok := v.(expr.Term); ok {
+		return constraint.LinearExpression{builder.cs.MakeTerm(&t.Coeff, t.VID)}
+	}
+	c := builder.cs.FromInterface(v)
+	term := builder.cs.MakeTerm(&c, 0)
+	term.MarkConstant()
+	return constraint.LinearExpression{term}
+}
+
 func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable) {
 
 	leafCount, err := schema.Walk(a, tVariable, nil)
@@ -559,6 +593,54 @@ func (builder *builder) Compiler() frontend.Compiler {
 	return builder
 }
 
+// Commit implements [frontend.Committer]. The constraints binding the commitment to the
+// committed variables are added by the PLONK backend (see constraint.Commitment.NbPlaceholderConstraints).
+func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error) {
+	// sorted committed wires, without duplicates; constants are not committed
+	seen := make(map[int]struct{}, len(v))
+	committed := make([]int, 0, len(v))
+	for _, vI := range v {
+		t, ok := vI.(expr.Term)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[t.VID]; !ok {
+			seen[t.VID] = struct{}{}
+			committed = append(committed, t.VID)
+		}
+	}
+	if len(committed) == 0 {
+		return nil, errors.New("must commit to at least one variable")
+	}
+	sort.Ints(committed)
+
+	nbPublicCommitted := 0
+	for nbPublicCommitted < len(committed) && committed[nbPublicCommitted] < builder.cs.GetNbPublicVariables() {
+		nbPublicCommitted++
+	}
+	commitment := constraint.NewCommitment(committed, nbPublicCommitted)
+
+	// the hint computing the commitment is replaced by the prover
+	inputs := make([]frontend.Variable, len(committed))
+	for i, wireID := range committed {
+		inputs[i] = expr.NewTerm(wireID, builder.tOne)
+	}
+	hintOut, err := builder.NewHint(constraint.Bsb22CommitmentHint, 1, inputs...)
+	if err != nil {
+		return nil, err
+	}
+	cVar := hintOut[0]
+	commitment.HintID = constraint.Bsb22CommitmentHint.ID
+	commitment.CommitmentIndex = cVar.(expr.Term).VID
+	commitment.CommittedAndCommitment = append(commitment.Committed, commitment.CommitmentIndex)
+
+	if err := builder.cs.AddCommitment(commitment); err != nil {
+		return nil, err
+	}
+
+	return cVar, nil
+}
+
 func (*builder) FrontendType() frontendtype.Type {
 	return frontendtype.SCS
 }
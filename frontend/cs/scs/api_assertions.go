@@ -94,9 +94,11 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 
 	v := i1.(expr.Term)
 	if builder.IsBoolean(v) {
+		builder.nbBooleanElided++
 		return
 	}
 	builder.MarkBoolean(v)
+	builder.nbBooleanAdded++
 
 	// ensure v * (1 - v) == 0
 	// that is v + -v*v == 0
@@ -135,7 +137,7 @@ func (builder *builder) mustBeLessOrEqVar(a frontend.Variable, bound expr.Term)
 
 	nbBits := builder.cs.FieldBitLen()
 
-	aBits := bits.ToBinary(builder, a, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	aBits := bits.ToBinary(builder, a, bits.WithWidth(nbBits), bits.WithUnchecked())
 	boundBits := builder.ToBinary(bound, nbBits)
 
 	p := make([]frontend.Variable, nbBits+1)
@@ -206,7 +208,7 @@ func (builder *builder) mustBeLessOrEqCst(a frontend.Variable, bound big.Int) {
 
 	// note that at this stage, we didn't boolean-constraint these new variables yet
 	// (as opposed to ToBinary)
-	aBits := bits.ToBinary(builder, a, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	aBits := bits.ToBinary(builder, a, bits.WithWidth(nbBits), bits.WithUnchecked())
 
 	// t trailing bits in the bound
 	t := 0
@@ -37,6 +37,8 @@ import (
 	tinyfieldr1cs "github.com/consensys/gnark/constraint/tinyfield"
 )
 
+// NewBuilder returns a new SparseR1CS builder which implements frontend.API.
+// Additionally, this builder also implements [frontend.Committer].
 func NewBuilder(field *big.Int, config frontend.CompileConfig) (frontend.Builder, error) {
 	return newBuilder(field, config), nil
 }
@@ -49,6 +51,9 @@ type builder struct {
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[expr.Term]struct{}
 
+	// number of booleanity constraints added and elided, see NbBooleanAssertions
+	nbBooleanAdded, nbBooleanElided int
+
 	// records multiplications constraint to avoid duplicate.
 	// see mulConstraintExist(...)
 	mMulConstraints map[uint64]int
@@ -57,6 +62,9 @@ type builder struct {
 	// see addConstraintExist(...)
 	mAddConstraints map[uint64]int
 
+	// records all the constraints to avoid duplicates when the optimization level is set.
+	mConstraints map[constraint.SparseR1C]struct{}
+
 	// frequently used coefficients
 	tOne, tMinusOne constraint.Coeff
 }
@@ -71,6 +79,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		config:          config,
 		Store:           kvstore.New(),
 	}
+	if config.OptimizationLevel > 0 {
+		b.mConstraints = make(map[constraint.SparseR1C]struct{}, config.Capacity)
+	}
 
 	curve := utils.FieldToCurve(field)
 
@@ -84,6 +95,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		}
 		panic("not implemented")
 	}
+	if config.DryRun {
+		b.cs = dryRunSparseR1CS{b.cs}
+	}
 
 	b.tOne = b.cs.One()
 	b.tMinusOne = b.cs.FromInterface(-1)
@@ -142,7 +156,17 @@ func (builder *builder) addPlonkConstraint(c sparseR1C, debug ...constraint.Debu
 	K := builder.cs.MakeTerm(&c.qC, 0)
 	K.MarkConstant()
 
-	builder.cs.AddConstraint(constraint.SparseR1C{L: L, R: R, O: O, M: [2]constraint.Term{U, V}, K: K.CoeffID()}, debug...)
+	c1 := constraint.SparseR1C{L: L, R: R, O: O, M: [2]constraint.Term{U, V}, K: K.CoeffID()}
+	if builder.mConstraints != nil {
+		if _, ok := builder.mConstraints[c1]; ok {
+			return
+		}
+		builder.mConstraints[c1] = struct{}{}
+	}
+	if builder.config.DebugInfo == frontend.DebugInfoNone {
+		debug = nil
+	}
+	builder.cs.AddConstraint(c1, debug...)
 }
 
 // newInternalVariable creates a new wire, appends it on the list of wires of the circuit, sets
@@ -208,6 +232,11 @@ func (builder *builder) MarkBoolean(v frontend.Variable) {
 	builder.mtBooleans[v.(expr.Term)] = struct{}{}
 }
 
+// NbBooleanAssertions implements [frontend.BooleanTracker].
+func (builder *builder) NbBooleanAssertions() (added, elided int) {
+	return builder.nbBooleanAdded, builder.nbBooleanElided
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -220,6 +249,10 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
+	if builder.config.DryRun {
+		return builder.cs, nil
+	}
+
 	// ensure all inputs and hints are constrained
 	err := builder.cs.CheckUnconstrainedWires()
 	if err != nil {
@@ -229,6 +262,14 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if builder.config.OptimizationLevel >= 2 {
+		nbRemoved := builder.cs.RemoveDeadWires()
+		log.Debug().
+			Int("nbRemovedWires", nbRemoved).
+			Int("nbConstraints", builder.cs.GetNbConstraints()).
+			Msg("removed dead wires")
+	}
+
 	return builder.cs, nil
 }
 
@@ -566,6 +607,9 @@ func (builder *builder) splitProd(acc expr.Term, r expr.LinearExpression) expr.T
 // to build logs for both debug and println
 // and append some program location.. (see other todo in debug_info.go)
 func (builder *builder) newDebugInfo(errName string, in ...interface{}) constraint.DebugInfo {
+	if builder.config.DebugInfo == frontend.DebugInfoNone {
+		return constraint.DebugInfo{}
+	}
 	for i := 0; i < len(in); i++ {
 		// for inputs that are LinearExpressions or Term, we need to "Make" them in the backend.
 		// TODO @gbotrel this is a duplicate effort with adding a constraint and should be taken care off
@@ -584,6 +628,9 @@ func (builder *builder) newDebugInfo(errName string, in ...interface{}) constrai
 		}
 	}
 
+	if builder.config.DebugInfo == frontend.DebugInfoErrorsOnly {
+		return builder.cs.NewDebugMessage(errName, in...)
+	}
 	return builder.cs.NewDebugInfo(errName, in...)
 
 }
@@ -591,3 +638,15 @@ func (builder *builder) newDebugInfo(errName string, in ...interface{}) constrai
 func (builder *builder) Defer(cb func(frontend.API) error) {
 	circuitdefer.Put(builder, cb)
 }
+
+// PushScope implements [frontend.Scoper]
+func (builder *builder) PushScope(name string) {
+	builder.cs.PushScope(name, builder.cs.GetNbConstraints())
+}
+
+// PopScope implements [frontend.Scoper]
+func (builder *builder) PopScope() {
+	if err := builder.cs.PopScope(builder.cs.GetNbConstraints()); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,26 @@
+package scs
+
+import (
+	"github.com/consensys/gnark/constraint"
+)
+
+// dryRunSparseR1CS is used by the builder in dry-run mode (see frontend.EstimateSize).
+// The builder looks up previous constraints to reuse them, so the constraints are
+// still stored, but no debug information is recorded.
+type dryRunSparseR1CS struct {
+	constraint.SparseR1CS
+}
+
+func (cs dryRunSparseR1CS) AddConstraint(c constraint.SparseR1C, _ ...constraint.DebugInfo) int {
+	return cs.SparseR1CS.AddConstraint(c)
+}
+
+func (cs dryRunSparseR1CS) NewDebugInfo(string, ...interface{}) constraint.DebugInfo {
+	return constraint.DebugInfo{}
+}
+
+func (cs dryRunSparseR1CS) NewDebugMessage(string, ...interface{}) constraint.DebugInfo {
+	return constraint.DebugInfo{}
+}
+
+func (cs dryRunSparseR1CS) AttachDebugInfo(constraint.DebugInfo, []int) {}
@@ -0,0 +1,58 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type debugInfoCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *debugInfoCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestDebugInfoLevel(t *testing.T) {
+	assert := require.New(t)
+
+	w, err := frontend.NewWitness(&debugInfoCircuit{X: 3, Y: 10}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		for _, tc := range []struct {
+			level          frontend.DebugInfoLevel
+			message, stack bool
+		}{
+			{frontend.DebugInfoFull, true, true},
+			{frontend.DebugInfoErrorsOnly, true, false},
+			{frontend.DebugInfoNone, false, false},
+		} {
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &debugInfoCircuit{}, frontend.WithDebugInfo(tc.level))
+			assert.NoError(err)
+			err = ccs.IsSolved(w)
+			assert.Error(err)
+			if tc.message {
+				assert.Contains(err.Error(), "[assertIsEqual]")
+			} else {
+				assert.NotContains(err.Error(), "[assertIsEqual]")
+			}
+			if tc.stack {
+				assert.Contains(err.Error(), "debuginfo_test.go")
+			} else {
+				assert.NotContains(err.Error(), "debuginfo_test.go")
+				assert.NotContains(err.Error(), "MISSING")
+			}
+		}
+	}
+
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &debugInfoCircuit{}, frontend.WithDebugInfo(frontend.DebugInfoNone+1))
+	assert.Error(err)
+}
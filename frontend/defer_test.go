@@ -0,0 +1,81 @@
+package frontend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// batcher is a gadget accumulating values, which are only constrained when it
+// is flushed after the circuit definition.
+type batcher struct {
+	values  []frontend.Variable
+	flushes *[]int
+}
+
+func newBatcher(api frontend.API, flushes *[]int) *batcher {
+	b := &batcher{flushes: flushes}
+	api.Compiler().Defer(b.flush)
+	return b
+}
+
+func (b *batcher) Add(v frontend.Variable) {
+	b.values = append(b.values, v)
+}
+
+func (b *batcher) flush(api frontend.API) error {
+	*b.flushes = append(*b.flushes, len(b.values))
+	for _, v := range b.values {
+		api.AssertIsBoolean(v)
+	}
+	// a nested batcher, registered while flushing
+	if len(b.values) > 1 {
+		nested := newBatcher(api, b.flushes)
+		nested.Add(b.values[0])
+	}
+	return nil
+}
+
+type deferCircuit struct {
+	X [3]frontend.Variable
+
+	flushes *[]int
+	err     error
+}
+
+func (c *deferCircuit) Define(api frontend.API) error {
+	b := newBatcher(api, c.flushes)
+	for i := range c.X {
+		b.Add(c.X[i])
+	}
+	if c.err != nil {
+		api.Compiler().Defer(func(frontend.API) error { return c.err })
+	}
+	return nil
+}
+
+func TestDefer(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var flushes []int
+		_, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &deferCircuit{flushes: &flushes})
+		assert.NoError(err)
+		assert.Equal([]int{3, 1}, flushes, "each callback must run once, nested ones last")
+
+		errFlush := errors.New("flush failed")
+		_, err = frontend.Compile(ecc.BN254.ScalarField(), builder, &deferCircuit{flushes: &flushes, err: errFlush})
+		assert.ErrorIs(err, errFlush)
+	}
+
+	var flushes []int
+	assert.NoError(test.IsSolved(&deferCircuit{flushes: &flushes}, &deferCircuit{X: [3]frontend.Variable{1, 0, 1}, flushes: &flushes}, ecc.BN254.ScalarField()))
+	assert.Equal([]int{3, 1}, flushes)
+	assert.Error(test.IsSolved(&deferCircuit{flushes: &flushes}, &deferCircuit{X: [3]frontend.Variable{1, 2, 1}, flushes: &flushes}, ecc.BN254.ScalarField()))
+}
@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/profile"
+)
+
+// SizeEstimate is the size of a circuit as computed by EstimateSize.
+type SizeEstimate struct {
+	NbConstraints       int
+	NbPublicVariables   int // including the constant wire, if any
+	NbSecretVariables   int
+	NbInternalVariables int
+
+	// NbConstraintsByCaller maps the gadgets (the innermost functions outside the
+	// frontend, see profile.Profile.NbConstraintsByCaller) to the number of
+	// constraints they add. It is only set with WithConstraintsByCaller.
+	NbConstraintsByCaller map[string]int
+}
+
+// WithConstraintsByCaller is an option of EstimateSize which attributes the
+// constraints to the gadgets adding them, see SizeEstimate.NbConstraintsByCaller.
+// The call stack of each constraint is then recorded, which slows the estimation
+// down. It has no effect on Compile.
+func WithConstraintsByCaller() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.ConstraintsByCaller = true
+		return nil
+	}
+}
+
+// EstimateSize runs circuit.Define with the given builder in dry-run mode: the
+// builder counts the constraints instead of building a solvable constraint system,
+// and no debug information nor call stack is collected. It is much faster than Compile and is meant
+// for sizing (SRS size, proving cost) circuits in tooling.
+//
+// The number of constraints is exact, except with WithOptimizationLevel(2) where
+// dead wires are not removed and the count is an upper bound.
+func EstimateSize(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (SizeEstimate, error) {
+	log := logger.Logger()
+	log.Info().Msg("estimating circuit size")
+
+	opt := CompileConfig{}
+	for _, o := range opts {
+		if err := o(&opt); err != nil {
+			return SizeEstimate{}, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	opt.DryRun = true
+
+	builder, err := newBuilder(field, opt)
+	if err != nil {
+		return SizeEstimate{}, fmt.Errorf("new compiler: %w", err)
+	}
+
+	var p *profile.Profile
+	if opt.ConstraintsByCaller {
+		p = profile.Start(profile.WithNoOutput())
+	}
+	err = parseCircuit(builder, circuit, opt, nil)
+	if p != nil {
+		p.Stop()
+	}
+	if err != nil {
+		return SizeEstimate{}, fmt.Errorf("parse circuit: %w", err)
+	}
+
+	cs, err := builder.Compile()
+	if err != nil {
+		return SizeEstimate{}, err
+	}
+	res := SizeEstimate{
+		NbConstraints:       cs.GetNbConstraints(),
+		NbPublicVariables:   cs.GetNbPublicVariables(),
+		NbSecretVariables:   cs.GetNbSecretVariables(),
+		NbInternalVariables: cs.GetNbInternalVariables(),
+	}
+	if p != nil {
+		res.NbConstraintsByCaller = p.NbConstraintsByCaller()
+	}
+	return res, nil
+}
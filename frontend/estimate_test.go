@@ -0,0 +1,56 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type estimateCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *estimateCircuit) Define(api frontend.API) error {
+	res := c.X
+	for i := 0; i < 10; i++ {
+		res = api.Add(api.Mul(res, res), i)
+	}
+	bits := api.ToBinary(res)
+	api.AssertIsEqual(api.FromBinary(bits...), c.Y)
+	return nil
+}
+
+func TestEstimateSize(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		for _, level := range []int{0, 1} {
+			opt := frontend.WithOptimizationLevel(level)
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &estimateCircuit{}, opt)
+			assert.NoError(err)
+			estimate, err := frontend.EstimateSize(ecc.BN254.ScalarField(), builder, &estimateCircuit{}, opt)
+			assert.NoError(err)
+
+			assert.Equal(ccs.GetNbConstraints(), estimate.NbConstraints)
+			assert.Equal(ccs.GetNbPublicVariables(), estimate.NbPublicVariables)
+			assert.Equal(ccs.GetNbSecretVariables(), estimate.NbSecretVariables)
+			assert.Equal(ccs.GetNbInternalVariables(), estimate.NbInternalVariables)
+
+			assert.Nil(estimate.NbConstraintsByCaller)
+
+			byCaller, err := frontend.EstimateSize(ecc.BN254.ScalarField(), builder, &estimateCircuit{}, opt, frontend.WithConstraintsByCaller())
+			assert.NoError(err)
+			assert.Equal(estimate.NbConstraints, byCaller.NbConstraints)
+			nbConstraints := 0
+			for _, n := range byCaller.NbConstraintsByCaller {
+				nbConstraints += n
+			}
+			assert.Equal(estimate.NbConstraints, nbConstraints)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+// LogConfig describes a log line added with [Logger.Log].
+type LogConfig struct {
+	// Caller is the location (file.go:line) reported with the log line.
+	Caller string
+
+	// Level of the log line. It is printed by the solver only if the solver
+	// logger level allows it.
+	Level zerolog.Level
+
+	// Condition, if not nil, gates the log line: it is printed only when the
+	// condition is not zero once solved.
+	Condition Variable
+
+	// Keys and Values are the structured fields of the log line, printed as
+	// key/value pairs by the solver logger.
+	Keys   []string
+	Values []Variable
+}
+
+// Logger allows to add leveled, conditional and structured log lines to the
+// circuit. The log lines are printed by the solver through its zerolog logger
+// (see solver.WithLogger). Not all compilers implement this interface; use
+// [Log] which falls back to api.Println.
+type Logger interface {
+	// Log behaves like api.Println, with the configuration cfg.
+	Log(cfg LogConfig, a ...Variable)
+}
+
+// LogEvent is a log line being built, see [Log].
+type LogEvent struct {
+	api API
+	cfg LogConfig
+}
+
+// Log starts a log line at debug level, in the style of zerolog:
+//
+//	frontend.Log(api).Level(zerolog.InfoLevel).If(isLast).Var("root", root).Msg("merkle proof")
+//
+// The line is added to the circuit when calling Msg.
+func Log(api API) *LogEvent {
+	return &LogEvent{api: api, cfg: LogConfig{Level: zerolog.DebugLevel}}
+}
+
+// Level sets the level of the log line.
+func (e *LogEvent) Level(level zerolog.Level) *LogEvent {
+	e.cfg.Level = level
+	return e
+}
+
+// If gates the log line: it is printed only when condition is not zero. If is
+// typically called with a boolean variable.
+func (e *LogEvent) If(condition Variable) *LogEvent {
+	e.cfg.Condition = condition
+	return e
+}
+
+// Var adds the structured field key, with value v.
+func (e *LogEvent) Var(key string, v Variable) *LogEvent {
+	e.cfg.Keys = append(e.cfg.Keys, key)
+	e.cfg.Values = append(e.cfg.Values, v)
+	return e
+}
+
+// Msg adds the log line to the circuit, with the message a formatted as in
+// api.Println.
+func (e *LogEvent) Msg(a ...Variable) {
+	if _, file, line, ok := runtime.Caller(1); ok {
+		e.cfg.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	if l, ok := e.api.Compiler().(Logger); ok {
+		l.Log(e.cfg, a...)
+		return
+	}
+
+	// the compiler can't gate nor level the log line, print everything
+	args := make([]Variable, 0, len(a)+2*len(e.cfg.Keys)+2)
+	args = append(args, e.cfg.Level.String())
+	args = append(args, a...)
+	if e.cfg.Condition != nil {
+		args = append(args, "if=", e.cfg.Condition)
+	}
+	for i, key := range e.cfg.Keys {
+		args = append(args, key+"=", e.cfg.Values[i])
+	}
+	e.api.Println(args...)
+}
@@ -0,0 +1,101 @@
+package frontend_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	gnarklogger "github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type logCircuit struct {
+	X [4]frontend.Variable
+}
+
+func (c *logCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		isOne := api.IsZero(api.Sub(c.X[i], 1))
+		frontend.Log(api).Level(zerolog.InfoLevel).If(isOne).Var("i", i).Var("square", api.Mul(c.X[i], c.X[i])).Msg("found one")
+		frontend.Log(api).Var("x", c.X[i]).Msg("x")
+	}
+	frontend.Log(api).Level(zerolog.WarnLevel).If(0).Msg("never")
+	return nil
+}
+
+func TestLog(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &logCircuit{})
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(&logCircuit{X: [4]frontend.Variable{3, 1, 5, 1}}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf).Level(zerolog.InfoLevel)
+		_, err = ccs.Solve(w, solver.WithLogger(logger))
+		assert.NoError(err)
+
+		// only the info lines whose condition holds are printed
+		var lines []map[string]string
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var line map[string]string
+			assert.NoError(dec.Decode(&line))
+			lines = append(lines, line)
+		}
+		assert.Len(lines, 2)
+		for j, i := range []string{"1", "3"} {
+			assert.Equal("info", lines[j]["level"])
+			assert.Equal("found one", lines[j]["message"])
+			assert.Equal(i, lines[j]["i"])
+			assert.Equal("1", lines[j]["square"])
+			assert.Contains(lines[j][zerolog.CallerFieldName], "log_test.go")
+		}
+	}
+}
+
+func TestLogContext(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &logCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&logCircuit{X: [4]frontend.Variable{3, 1, 5, 1}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the fields are attached to the lines printed by the circuit, whatever the order of the options
+	var buf bytes.Buffer
+	logContext := solver.WithLogContext(map[string]interface{}{"job": "42", "circuit": "logCircuit"})
+	_, err = ccs.Solve(w, logContext, solver.WithLogger(zerolog.New(&buf).Level(zerolog.InfoLevel)))
+	assert.NoError(err)
+	dec := json.NewDecoder(&buf)
+	nbLines := 0
+	for ; dec.More(); nbLines++ {
+		var line map[string]string
+		assert.NoError(dec.Decode(&line))
+		assert.Equal("42", line["job"])
+		assert.Equal("logCircuit", line["circuit"])
+	}
+	assert.Equal(2, nbLines)
+
+	// and to the lines printed by the solver (here, invalid witness size)
+	buf.Reset()
+	gnarklogger.Set(zerolog.New(&buf))
+	defer gnarklogger.Disable()
+	public, err := w.Public()
+	assert.NoError(err)
+	_, err = ccs.Solve(public, logContext, solver.WithLogger(zerolog.Nop()))
+	assert.Error(err)
+	var line map[string]interface{}
+	assert.NoError(json.NewDecoder(&buf).Decode(&line))
+	assert.Equal("error", line["level"])
+	assert.Equal("42", line["job"])
+}
@@ -0,0 +1,166 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// Parameters are the compile-time parameters of a circuit, such as the depth of
+// a Merkle tree or the size of a batch, which change the constraint system.
+//
+// Parameters must be a struct (or a pointer to a struct) whose exported fields
+// are booleans, integers, strings or structs of them. The fields are part of the
+// encoding of the parameters (see ParametersString), the unexported ones aren't.
+type Parameters interface {
+	// Validate returns an error if the parameters can't be compiled.
+	Validate() error
+}
+
+// Parameterized is implemented by the circuits whose structure depends on
+// compile-time parameters. Compile validates the parameters before defining the
+// circuit, and records them in the metadata of the constraint system, so that
+// the artifacts of a parameterization can be checked with CheckParameters.
+type Parameterized interface {
+	Parameters() Parameters
+}
+
+const (
+	// MetadataParameters is the metadata key of the parameters of a
+	// Parameterized circuit, encoded by ParametersString.
+	MetadataParameters = "circuit.params"
+
+	// MetadataParametersDigest is the metadata key of the digest of the
+	// parameters of a Parameterized circuit, see ParametersDigest.
+	MetadataParametersDigest = "circuit.params.digest"
+)
+
+// ParametersString returns the canonical encoding of p: the name=value pairs of
+// its fields in declaration order, separated by commas, with the names of the
+// fields of nested structs prefixed by the name of the struct and a dot.
+func ParametersString(p Parameters) (string, error) {
+	if p == nil {
+		return "", errors.New("nil parameters")
+	}
+	v := reflect.ValueOf(p)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", errors.New("nil parameters")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("parameters must be a struct, got %s", v.Type())
+	}
+	var pairs []string
+	if err := appendParameters(&pairs, "", v); err != nil {
+		return "", err
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+func appendParameters(pairs *[]string, prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := prefix + f.Name
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.Bool:
+			*pairs = append(*pairs, name+"="+strconv.FormatBool(fv.Bool()))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			*pairs = append(*pairs, name+"="+strconv.FormatInt(fv.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			*pairs = append(*pairs, name+"="+strconv.FormatUint(fv.Uint(), 10))
+		case reflect.String:
+			*pairs = append(*pairs, name+"="+strconv.Quote(fv.String()))
+		case reflect.Struct:
+			if err := appendParameters(pairs, name+".", fv); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("parameter %s: unsupported type %s", name, f.Type)
+		}
+	}
+	return nil
+}
+
+// ParametersDigest returns the hex encoded SHA-256 digest of the type name and
+// the canonical encoding of p (see ParametersString). Two parameterizations of
+// the same circuit have the same digest iff their parameters are equal.
+func ParametersDigest(p Parameters) (string, error) {
+	s, err := ParametersString(p)
+	if err != nil {
+		return "", err
+	}
+	t := reflect.TypeOf(p)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s.%s\n%s", t.PkgPath(), t.Name(), s)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ArtifactName returns the name of the artifacts (constraint system, keys,
+// verifier contract, ...) of the parameterization p of the circuit name: name
+// followed by a dash and the first 16 hex characters of the digest of p.
+func ArtifactName(name string, p Parameters) (string, error) {
+	digest, err := ParametersDigest(p)
+	if err != nil {
+		return "", err
+	}
+	return name + "-" + digest[:16], nil
+}
+
+// CheckParameters returns an error if the constraint system ccs wasn't compiled
+// from a circuit with the parameters p, as recorded in its metadata by Compile.
+func CheckParameters(ccs constraint.ConstraintSystem, p Parameters) error {
+	digest, err := ParametersDigest(p)
+	if err != nil {
+		return err
+	}
+	got, ok := ccs.GetMetadata()[MetadataParametersDigest]
+	if !ok {
+		return errors.New("constraint system has no recorded parameters")
+	}
+	if got != digest {
+		expected, _ := ParametersString(p)
+		return fmt.Errorf("constraint system compiled with parameters %s, expected %s", ccs.GetMetadata()[MetadataParameters], expected)
+	}
+	return nil
+}
+
+// parametersMetadata validates the parameters of the circuit, if it is
+// Parameterized, and returns the metadata recording them.
+func parametersMetadata(circuit Circuit) (constraint.Metadata, error) {
+	pc, ok := circuit.(Parameterized)
+	if !ok {
+		return nil, nil
+	}
+	p := pc.Parameters()
+	if p == nil {
+		return nil, errors.New("nil parameters")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	s, err := ParametersString(p)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := ParametersDigest(p)
+	if err != nil {
+		return nil, err
+	}
+	return constraint.Metadata{MetadataParameters: s, MetadataParametersDigest: digest}, nil
+}
@@ -0,0 +1,70 @@
+package frontend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type sumParams struct {
+	NbTerms int
+	Label   string
+}
+
+func (p sumParams) Validate() error {
+	if p.NbTerms < 1 {
+		return errors.New("at least one term")
+	}
+	return nil
+}
+
+type sumCircuit struct {
+	params sumParams
+	Terms  []frontend.Variable
+	Sum    frontend.Variable `gnark:",public"`
+}
+
+func newSumCircuit(p sumParams) *sumCircuit {
+	return &sumCircuit{params: p, Terms: make([]frontend.Variable, p.NbTerms)}
+}
+
+func (c *sumCircuit) Parameters() frontend.Parameters {
+	return c.params
+}
+
+func (c *sumCircuit) Define(api frontend.API) error {
+	var sum frontend.Variable = 0
+	for i := range c.Terms {
+		sum = api.Add(sum, c.Terms[i])
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	return nil
+}
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	p := sumParams{NbTerms: 3, Label: "a,b"}
+	s, err := frontend.ParametersString(p)
+	assert.NoError(err)
+	assert.Equal(`NbTerms=3,Label="a,b"`, s)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, newSumCircuit(p))
+	assert.NoError(err)
+	assert.Equal(s, ccs.GetMetadata()[frontend.MetadataParameters])
+	assert.NoError(frontend.CheckParameters(ccs, p))
+	assert.Error(frontend.CheckParameters(ccs, sumParams{NbTerms: 4, Label: "a,b"}))
+
+	name, err := frontend.ArtifactName("sum", p)
+	assert.NoError(err)
+	other, err := frontend.ArtifactName("sum", &sumParams{NbTerms: 3, Label: "a"})
+	assert.NoError(err)
+	assert.NotEqual(name, other)
+
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, newSumCircuit(sumParams{}))
+	assert.Error(err)
+}
@@ -0,0 +1,45 @@
+package protogen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// EncodeVariable returns the encoding of the value assigned to a variable in a
+// protobuf message: the big-endian encoding of the value, or nil if v is nil
+// (unassigned). Zero is encoded as a single zero byte, to be told apart from an
+// unassigned variable.
+//
+// v must be a non-negative value supported by the witness (see
+// [frontend.NewWitness]); it is not reduced modulo a field.
+func EncodeVariable(v frontend.Variable) (b []byte, err error) {
+	if v == nil {
+		return nil, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid value: %v", r)
+		}
+	}()
+	n := utils.FromInterface(v)
+	switch n.Sign() {
+	case -1:
+		return nil, errors.New("negative value")
+	case 0:
+		return []byte{0}, nil
+	}
+	return n.Bytes(), nil
+}
+
+// DecodeVariable returns the value encoded with [EncodeVariable]: a *big.Int, or
+// nil if b is empty.
+func DecodeVariable(b []byte) frontend.Variable {
+	if len(b) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(b)
+}
@@ -0,0 +1,620 @@
+// Package protogen generates the protobuf messages of the assignments of a
+// circuit, and the functions converting the assignments to and from the Go types
+// generated from them by protoc-gen-go.
+//
+// Services exchanging witness data across languages can then rely on the
+// protobuf message instead of hand-maintaining a mapping of the circuit. The
+// generator is meant to be called from a small program run by go:generate:
+//
+//	//go:generate go run ./gen
+//	//go:generate protoc --go_out=. --go_opt=paths=source_relative assignment.proto
+//
+// where ./gen calls [WriteProto] and [WriteGo] with the circuit.
+//
+// Each variable of the circuit is a bytes field holding the big-endian encoding
+// of its value (see [EncodeVariable]); an unassigned variable is empty. Nested
+// structs are messages, and arrays are repeated fields flattened in row-major
+// order. The fields are numbered in the order of declaration of the circuit: to
+// keep the messages compatible, only append new fields to the circuit.
+package protogen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+const generatedHeader = "// Code generated by gnark/frontend/protogen DO NOT EDIT"
+
+var tVariable = reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+
+// Config is the configuration of the generator.
+type Config struct {
+	// GoPackage is the import path of the Go package generated by protoc-gen-go.
+	GoPackage string
+	// GoPackageName is the name of the Go package generated by protoc-gen-go.
+	// Defaults to the last element of GoPackage.
+	GoPackageName string
+	// ProtoPackage is the protobuf package of the messages. Defaults to
+	// GoPackageName.
+	ProtoPackage string
+	// MessageName is the name of the message of the circuit. Defaults to the name
+	// of the circuit type followed by "Assignment".
+	MessageName string
+}
+
+// Option configures the generator.
+type Option func(*Config) error
+
+// WithGoPackage sets the import path of the Go package generated by
+// protoc-gen-go from the messages (go_package option). The conversion functions
+// belong to this package. The package name defaults to the last element of the
+// import path.
+func WithGoPackage(importPath string, packageName ...string) Option {
+	return func(c *Config) error {
+		if len(packageName) > 1 {
+			return errors.New("at most one package name is allowed")
+		}
+		c.GoPackage = importPath
+		if len(packageName) == 1 {
+			c.GoPackageName = packageName[0]
+		}
+		return nil
+	}
+}
+
+// WithProtoPackage sets the protobuf package of the messages.
+func WithProtoPackage(name string) Option {
+	return func(c *Config) error {
+		c.ProtoPackage = name
+		return nil
+	}
+}
+
+// WithMessageName sets the name of the message of the circuit.
+func WithMessageName(name string) Option {
+	return func(c *Config) error {
+		c.MessageName = name
+		return nil
+	}
+}
+
+// WriteProto writes to w the protobuf definition of the messages of the
+// assignments of circuit.
+func WriteProto(w io.Writer, circuit frontend.Circuit, opts ...Option) error {
+	g, err := newGenerator(circuit, opts)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s.\n\n", generatedHeader)
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", g.cfg.ProtoPackage)
+	fmt.Fprintf(&buf, "option go_package = %q;\n", g.cfg.GoPackage+";"+g.cfg.GoPackageName)
+	for i, msg := range g.messages {
+		buf.WriteString("\n")
+		if i == 0 {
+			fmt.Fprintf(&buf, "// %s is an assignment of the circuit %s.%s.\n", msg.name, g.circuit.PkgPath(), g.circuit.Name())
+		} else {
+			fmt.Fprintf(&buf, "// %s is an assignment of %s.\n", msg.name, msg.typ)
+		}
+		fmt.Fprintf(&buf, "message %s {\n", msg.name)
+		for j, f := range msg.fields {
+			typ := "bytes"
+			if f.msg != nil {
+				typ = f.msg.name
+			}
+			if len(f.dims) > 0 {
+				typ = "repeated " + typ
+			}
+			fmt.Fprintf(&buf, "  %s %s = %d;", typ, f.protoName, j+1)
+			if len(f.dims) > 0 {
+				fmt.Fprintf(&buf, " // %s", dimsString(f.dims))
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteGo writes to w the Go functions converting the assignments of circuit to
+// and from the types generated by protoc-gen-go from the messages written by
+// [WriteProto] with the same options:
+//
+//	func <Circuit>ToProto(a *<Circuit>) (*<Message>, error)
+//	func <Circuit>FromProto(m *<Message>) (*<Circuit>, error)
+//
+// The functions belong to the package generated by protoc-gen-go, which must
+// then differ from the package of the circuit.
+func WriteGo(w io.Writer, circuit frontend.Circuit, opts ...Option) error {
+	g, err := newGenerator(circuit, opts)
+	if err != nil {
+		return err
+	}
+	if g.circuit.PkgPath() == g.cfg.GoPackage {
+		return errors.New("the Go package of the messages must differ from the package of the circuit")
+	}
+	if g.circuit.PkgPath() == "main" {
+		return errors.New("the circuit must not be declared in a main package")
+	}
+	if strings.Contains(g.circuit.Name(), "[") {
+		return errors.New("generic circuits are not supported")
+	}
+	g.imports = map[string]string{"fmt": "fmt", "github.com/consensys/gnark/frontend/protogen": "protogen"}
+	circuitType := g.typeName(g.circuit)
+	msg := g.messages[0].name
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %sToProto returns the message of the assignment a.\n", g.circuit.Name())
+	fmt.Fprintf(&body, "func %sToProto(a *%s) (*%s, error) {\n", g.circuit.Name(), circuitType, msg)
+	fmt.Fprintf(&body, "m := &%s{}\n", msg)
+	if err := g.toProto(&body, "a", "m", g.messages[0], "", nil); err != nil {
+		return err
+	}
+	body.WriteString("return m, nil\n}\n\n")
+
+	fmt.Fprintf(&body, "// %sFromProto returns the assignment of the message m.\n", g.circuit.Name())
+	fmt.Fprintf(&body, "func %sFromProto(m *%s) (*%s, error) {\n", g.circuit.Name(), msg, circuitType)
+	fmt.Fprintf(&body, "a := &%s{}\n", circuitType)
+	if err := g.fromProto(&body, "m", "a", g.messages[0], "", nil); err != nil {
+		return err
+	}
+	body.WriteString("return a, nil\n}\n")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s.\n\npackage %s\n\nimport (\n", generatedHeader, g.cfg.GoPackageName)
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	// standard library first
+	sort.Slice(paths, func(i, j int) bool {
+		si, sj := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], ".")
+		if si != sj {
+			return si
+		}
+		return paths[i] < paths[j]
+	})
+	for i, p := range paths {
+		if i > 0 && strings.Contains(p, ".") && !strings.Contains(paths[i-1], ".") {
+			buf.WriteString("\n")
+		}
+		if g.imports[p] == path.Base(p) {
+			fmt.Fprintf(&buf, "%q\n", p)
+		} else {
+			fmt.Fprintf(&buf, "%s %q\n", g.imports[p], p)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated code: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// message is a protobuf message of a struct of the circuit.
+type message struct {
+	name   string
+	typ    reflect.Type
+	fields []field
+}
+
+// field is a field of a message.
+type field struct {
+	goName    string
+	protoName string
+	// dims are the lengths of the arrays of the field, from the outermost; -1
+	// denotes a slice (only allowed as outermost).
+	dims []int
+	// elem is the Go type of the elements of the arrays.
+	elem reflect.Type
+	// msg is the message of the struct elements, nil for variables.
+	msg *message
+}
+
+type generator struct {
+	cfg      Config
+	circuit  reflect.Type
+	messages []*message
+	byType   map[reflect.Type]*message
+	names    map[string]bool
+	imports  map[string]string // import path -> name
+	nbVars   int
+}
+
+func newGenerator(circuit frontend.Circuit, opts []Option) (*generator, error) {
+	t := reflect.TypeOf(circuit)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+		return nil, errors.New("the circuit must be a named struct or a pointer to a named struct")
+	}
+	g := &generator{
+		circuit: t,
+		byType:  make(map[reflect.Type]*message),
+		names:   make(map[string]bool),
+	}
+	for _, o := range opts {
+		if err := o(&g.cfg); err != nil {
+			return nil, err
+		}
+	}
+	if g.cfg.GoPackage == "" {
+		return nil, errors.New("the Go package of the messages is not set, see WithGoPackage")
+	}
+	if g.cfg.GoPackageName == "" {
+		g.cfg.GoPackageName = strings.ReplaceAll(path.Base(g.cfg.GoPackage), "-", "_")
+	}
+	if g.cfg.ProtoPackage == "" {
+		g.cfg.ProtoPackage = g.cfg.GoPackageName
+	}
+	if g.cfg.MessageName == "" {
+		g.cfg.MessageName = t.Name() + "Assignment"
+	}
+	msg, err := g.parseStruct(t, g.cfg.MessageName)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, errors.New("the circuit has no variable")
+	}
+	return g, nil
+}
+
+// parseStruct returns the message of the struct type t, or nil if t doesn't
+// have any variable.
+func (g *generator) parseStruct(t reflect.Type, name string) (*message, error) {
+	if msg, ok := g.byType[t]; ok {
+		return msg, nil
+	}
+	msg := &message{name: g.uniqueName(name), typ: t}
+	// the messages are declared in the order they are met
+	g.messages = append(g.messages, msg)
+	protoNames := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("gnark")
+		if !sf.IsExported() || (hasTag && tag == "-") {
+			continue
+		}
+		f := field{goName: sf.Name, protoName: snakeCase(sf.Name)}
+		if nameInTag, _, _ := strings.Cut(tag, ","); nameInTag != "" && isIdentifier(nameInTag) {
+			f.protoName = snakeCase(nameInTag)
+		}
+		if protoNames[f.protoName] {
+			return nil, fmt.Errorf("%s.%s: protobuf field name %q is already used", t, sf.Name, f.protoName)
+		}
+		protoNames[f.protoName] = true
+
+		f.elem = sf.Type
+		for f.elem.Kind() == reflect.Array || f.elem.Kind() == reflect.Slice {
+			if f.elem.Kind() == reflect.Slice {
+				if len(f.dims) > 0 {
+					return nil, fmt.Errorf("%s.%s: only the outermost dimension may be a slice", t, sf.Name)
+				}
+				f.dims = append(f.dims, -1)
+			} else {
+				f.dims = append(f.dims, f.elem.Len())
+			}
+			f.elem = f.elem.Elem()
+		}
+		switch {
+		case f.elem == tVariable:
+		case f.elem.Kind() == reflect.Struct:
+			sub, err := g.parseStruct(f.elem, msgName(f.elem, msg.name, f.protoName))
+			if err != nil {
+				return nil, err
+			}
+			if sub == nil {
+				continue
+			}
+			f.msg = sub
+		default:
+			// not part of the witness
+			continue
+		}
+		msg.fields = append(msg.fields, f)
+	}
+	if len(msg.fields) == 0 {
+		g.messages = g.messages[:len(g.messages)-1]
+		delete(g.names, msg.name)
+		return nil, nil
+	}
+	g.byType[t] = msg
+	return msg, nil
+}
+
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// toProto writes the statements setting the fields of the message dst from the
+// struct src. The errors are prefixed with the path pathFmt formatted with
+// pathArgs.
+func (g *generator) toProto(w io.Writer, src, dst string, msg *message, pathFmt string, pathArgs []string) error {
+	for _, f := range msg.fields {
+		s, d := src+"."+f.goName, dst+"."+goCamelCase(f.protoName)
+		p, args := joinPath(pathFmt, f.goName), pathArgs
+		for range f.dims {
+			v := g.newVar("i")
+			fmt.Fprintf(w, "for %s := range %s {\n", v, s)
+			s += "[" + v + "]"
+			p += "[%d]"
+			args = append(args, v)
+		}
+		if f.msg == nil {
+			v := g.newVar("b")
+			fmt.Fprintf(w, "%s, err := protogen.EncodeVariable(%s)\n", v, s)
+			fmt.Fprintf(w, "if err != nil {\nreturn nil, fmt.Errorf(%s, %s)\n}\n", strconv.Quote(p+": %w"), strings.Join(append(args, "err"), ", "))
+			if len(f.dims) == 0 {
+				fmt.Fprintf(w, "%s = %s\n", d, v)
+			} else {
+				fmt.Fprintf(w, "%s = append(%s, %s)\n", d, d, v)
+			}
+		} else {
+			v := g.newVar("m")
+			fmt.Fprintf(w, "%s := &%s{}\n", v, f.msg.name)
+			if err := g.toProto(w, s, v, f.msg, p, args); err != nil {
+				return err
+			}
+			if len(f.dims) == 0 {
+				fmt.Fprintf(w, "%s = %s\n", d, v)
+			} else {
+				fmt.Fprintf(w, "%s = append(%s, %s)\n", d, d, v)
+			}
+		}
+		for range f.dims {
+			fmt.Fprintln(w, "}")
+		}
+	}
+	return nil
+}
+
+// fromProto writes the statements setting the fields of the struct dst from the
+// message src.
+func (g *generator) fromProto(w io.Writer, src, dst string, msg *message, pathFmt string, pathArgs []string) error {
+	for _, f := range msg.fields {
+		s, d := src+".Get"+goCamelCase(f.protoName)+"()", dst+"."+f.goName
+		p := joinPath(pathFmt, f.goName)
+		errArgs := strings.Join(pathArgs, ", ")
+		if errArgs != "" {
+			errArgs += ", "
+		}
+
+		if len(f.dims) == 0 {
+			if f.msg == nil {
+				fmt.Fprintf(w, "%s = protogen.DecodeVariable(%s)\n", d, s)
+				continue
+			}
+			v := g.newVar("m")
+			fmt.Fprintf(w, "if %s := %s; %s != nil {\n", v, s, v)
+			if err := g.fromProto(w, v, d, f.msg, p, pathArgs); err != nil {
+				return err
+			}
+			fmt.Fprintln(w, "}")
+			continue
+		}
+
+		// the elements are flattened in row-major order
+		inner := 1
+		for _, n := range f.dims {
+			if n != -1 {
+				inner *= n
+			}
+		}
+		sv, k := g.newVar("s"), g.newVar("k")
+		fmt.Fprintf(w, "%s := %s\n", sv, s)
+		if f.dims[0] == -1 {
+			sliceType, err := g.typeExpr(reflect.SliceOf(arrayOf(f.elem, f.dims[1:])))
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", msg.typ, f.goName, err)
+			}
+			if inner == 1 {
+				fmt.Fprintf(w, "%s = make(%s, len(%s))\n", d, sliceType, sv)
+			} else {
+				fmt.Fprintf(w, "if len(%s)%%%d != 0 {\nreturn nil, fmt.Errorf(%s, %slen(%s))\n}\n",
+					sv, inner, strconv.Quote(p+": the number of elements %d is not a multiple of "+strconv.Itoa(inner)), errArgs, sv)
+				fmt.Fprintf(w, "%s = make(%s, len(%s)/%d)\n", d, sliceType, sv, inner)
+			}
+		} else {
+			fmt.Fprintf(w, "if len(%s) != %d {\nreturn nil, fmt.Errorf(%s, %slen(%s))\n}\n",
+				sv, inner, strconv.Quote(p+": expected "+strconv.Itoa(inner)+" elements, got %d"), errArgs, sv)
+		}
+		fmt.Fprintf(w, "%s := 0\n", k)
+		args := pathArgs
+		for range f.dims {
+			v := g.newVar("i")
+			fmt.Fprintf(w, "for %s := range %s {\n", v, d)
+			d += "[" + v + "]"
+			p += "[%d]"
+			args = append(args, v)
+		}
+		if f.msg == nil {
+			fmt.Fprintf(w, "%s = protogen.DecodeVariable(%s[%s])\n", d, sv, k)
+		} else {
+			v := g.newVar("m")
+			fmt.Fprintf(w, "if %s := %s[%s]; %s != nil {\n", v, sv, k, v)
+			if err := g.fromProto(w, v, d, f.msg, p, args); err != nil {
+				return err
+			}
+			fmt.Fprintln(w, "}")
+		}
+		fmt.Fprintf(w, "%s++\n", k)
+		for range f.dims {
+			fmt.Fprintln(w, "}")
+		}
+	}
+	return nil
+}
+
+func (g *generator) newVar(prefix string) string {
+	g.nbVars++
+	return prefix + strconv.Itoa(g.nbVars)
+}
+
+// typeName returns the qualified name of the named type t, importing its
+// package.
+func (g *generator) typeName(t reflect.Type) string {
+	name := path.Base(t.PkgPath())
+	taken := false
+	for p, n := range g.imports {
+		if n == name && p != t.PkgPath() {
+			taken = true
+		}
+	}
+	if alias, ok := g.imports[t.PkgPath()]; ok {
+		name = alias
+	} else {
+		if taken || !isIdentifier(name) {
+			name = g.newVar("pkg")
+		}
+		g.imports[t.PkgPath()] = name
+	}
+	return name + "." + t.Name()
+}
+
+// typeExpr returns the Go expression of the type t.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	switch {
+	case t == tVariable:
+		return g.typeName(t), nil
+	case t.Kind() == reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+	case t.Kind() == reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return "[" + strconv.Itoa(t.Len()) + "]" + elem, err
+	case t.Name() == "" || strings.Contains(t.Name(), "["):
+		return "", fmt.Errorf("unsupported slice of anonymous or generic type %s", t)
+	}
+	return g.typeName(t), nil
+}
+
+func arrayOf(elem reflect.Type, dims []int) reflect.Type {
+	for i := len(dims) - 1; i >= 0; i-- {
+		elem = reflect.ArrayOf(dims[i], elem)
+	}
+	return elem
+}
+
+func joinPath(pathFmt, name string) string {
+	if pathFmt == "" {
+		return name
+	}
+	return pathFmt + "." + name
+}
+
+func dimsString(dims []int) string {
+	var sb strings.Builder
+	for _, n := range dims {
+		if n == -1 {
+			sb.WriteString("[]")
+		} else {
+			fmt.Fprintf(&sb, "[%d]", n)
+		}
+	}
+	return sb.String()
+}
+
+// msgName returns the name of the message of the struct type t, field of the
+// message parent.
+func msgName(t reflect.Type, parent, fieldName string) string {
+	name := t.Name()
+	if name == "" {
+		return parent + goCamelCase(fieldName)
+	}
+	// generic types: Element[pkg.Params] -> ElementParams
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		params := strings.Split(strings.TrimSuffix(name[i+1:], "]"), ",")
+		name = name[:i]
+		for _, p := range params {
+			name += p[strings.LastIndexAny(p, "./")+1:]
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// snakeCase returns the lower_snake_case protobuf field name of the Go field
+// name s.
+func snakeCase(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return strings.ReplaceAll(sb.String(), "__", "_")
+}
+
+// goCamelCase returns the name of the Go field generated by protoc-gen-go for
+// the protobuf field name s.
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool { return 'a' <= c && c <= 'z' }
+func isASCIIDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,150 @@
+package protogen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y frontend.Variable
+}
+
+type protoCircuit struct {
+	A      frontend.Variable `gnark:",public"`
+	Matrix [2][3]frontend.Variable
+	P      point
+	Points []point
+	Inner  struct {
+		Z frontend.Variable
+	}
+	KeyID  frontend.Variable `gnark:"pubKeyID,public"`
+	Omit   frontend.Variable `gnark:"-"`
+	params int
+}
+
+func (c *protoCircuit) Define(api frontend.API) error {
+	return nil
+}
+
+const expectedProto = `// Code generated by gnark/frontend/protogen DO NOT EDIT.
+
+syntax = "proto3";
+
+package circuits.v1;
+
+option go_package = "example.com/circuits/pb;pb";
+
+// ProtoCircuitAssignment is an assignment of the circuit github.com/consensys/gnark/frontend/protogen.protoCircuit.
+message ProtoCircuitAssignment {
+  bytes a = 1;
+  repeated bytes matrix = 2; // [2][3]
+  point p = 3;
+  repeated point points = 4; // []
+  ProtoCircuitAssignmentInner inner = 5;
+  bytes pub_key_id = 6;
+}
+
+// point is an assignment of protogen.point.
+message point {
+  bytes x = 1;
+  bytes y = 2;
+}
+
+// ProtoCircuitAssignmentInner is an assignment of struct { Z frontend.Variable }.
+message ProtoCircuitAssignmentInner {
+  bytes z = 1;
+}
+`
+
+func TestWriteProto(t *testing.T) {
+	assert := require.New(t)
+
+	var buf bytes.Buffer
+	err := WriteProto(&buf, &protoCircuit{}, WithGoPackage("example.com/circuits/pb"), WithProtoPackage("circuits.v1"), WithMessageName("ProtoCircuitAssignment"))
+	assert.NoError(err)
+	assert.Equal(expectedProto, buf.String())
+
+	assert.Error(WriteProto(&buf, &protoCircuit{}), "missing Go package")
+}
+
+func TestWriteGo(t *testing.T) {
+	assert := require.New(t)
+
+	var buf bytes.Buffer
+	err := WriteGo(&buf, &protoCircuit{}, WithGoPackage("example.com/circuits/pb"), WithMessageName("ProtoCircuitAssignment"))
+	assert.NoError(err)
+	f, err := parser.ParseFile(token.NewFileSet(), "", buf.Bytes(), 0)
+	assert.NoError(err)
+	assert.Equal("pb", f.Name.Name)
+
+	src := buf.String()
+	assert.Contains(src, "func protoCircuitToProto(a *protogen.protoCircuit) (*ProtoCircuitAssignment, error)")
+	assert.Contains(src, "func protoCircuitFromProto(m *ProtoCircuitAssignment) (*protogen.protoCircuit, error)")
+	assert.Contains(src, "m.PubKeyId = ")
+	assert.Contains(src, "a.KeyID = protogen.DecodeVariable(m.GetPubKeyId())")
+	assert.Contains(src, `return nil, fmt.Errorf("Matrix: expected 6 elements, got %d", len(`)
+	assert.Contains(src, "= make([]protogen.point, len(")
+
+	// the conversions can't live in the package of the circuit
+	err = WriteGo(&buf, &protoCircuit{}, WithGoPackage("github.com/consensys/gnark/frontend/protogen"))
+	assert.Error(err)
+}
+
+func TestEncodeVariable(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range []frontend.Variable{nil, 0, 1, uint64(1 << 63), "0x1234", big.NewInt(42)} {
+		b, err := EncodeVariable(v)
+		assert.NoError(err)
+		if v == nil {
+			assert.Empty(b)
+			assert.Nil(DecodeVariable(b))
+			continue
+		}
+		assert.NotEmpty(b)
+		expected, actual := toBigInt(v), DecodeVariable(b).(*big.Int)
+		assert.Equal(0, expected.Cmp(actual), "%v", v)
+	}
+
+	_, err := EncodeVariable(-1)
+	assert.Error(err)
+	_, err = EncodeVariable(1.5)
+	assert.Error(err)
+}
+
+func toBigInt(v frontend.Variable) *big.Int {
+	switch v := v.(type) {
+	case *big.Int:
+		return v
+	case string:
+		n, _ := new(big.Int).SetString(v, 0)
+		return n
+	case uint64:
+		return new(big.Int).SetUint64(v)
+	default:
+		return big.NewInt(int64(v.(int)))
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert := require.New(t)
+
+	for _, c := range []struct{ goName, protoName, pbName string }{
+		{"A", "a", "A"},
+		{"InnerB", "inner_b", "InnerB"},
+		{"X1", "x1", "X1"},
+		{"PublicKeyX", "public_key_x", "PublicKeyX"},
+		{"KeyID", "key_id", "KeyId"},
+		{"IDNumber", "id_number", "IdNumber"},
+		{"snake_case", "snake_case", "SnakeCase"},
+	} {
+		assert.Equal(c.protoName, snakeCase(c.goName), c.goName)
+		assert.Equal(c.pbName, goCamelCase(c.protoName), c.protoName)
+	}
+}
@@ -0,0 +1,21 @@
+package frontend
+
+import "math/big"
+
+// publicInputsHashName is the name of the single public input of circuits
+// compiled with WithPublicInputsHashing
+const publicInputsHashName = "publicInputsHash"
+
+// PublicInputsHasher hashes the public inputs of a circuit to a single field
+// element, see WithPublicInputsHashing.
+//
+// Define and Hash must compute the same function, respectively in the circuit
+// and off-circuit.
+type PublicInputsHasher interface {
+	// Define returns the hash of the public inputs, in the order in which they
+	// are declared in the circuit.
+	Define(api API, publicInputs []Variable) (Variable, error)
+
+	// Hash returns the hash of the public inputs, in the field.
+	Hash(field *big.Int, publicInputs []*big.Int) (*big.Int, error)
+}
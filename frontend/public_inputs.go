@@ -0,0 +1,52 @@
+package frontend
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// publicInputsChecker collects the issues of the public inputs of a circuit:
+// leaves bound more than once (aliased through pointers or slices sharing their
+// elements), duplicate names and inputs which aren't constrained. The issues are
+// logged, and fail the compilation with WithStrictPublicInputs.
+type publicInputsChecker struct {
+	names  map[string]struct{}
+	leaves map[uintptr]string
+	issues []string
+}
+
+func newPublicInputsChecker() *publicInputsChecker {
+	return &publicInputsChecker{
+		names:  make(map[string]struct{}),
+		leaves: make(map[uintptr]string),
+	}
+}
+
+// add records the public input f, stored in tInput.
+func (c *publicInputsChecker) add(f schema.LeafInfo, tInput reflect.Value) {
+	name := f.FullName()
+	if _, ok := c.names[name]; ok {
+		c.issues = append(c.issues, fmt.Sprintf("public input %s is declared more than once", name))
+	}
+	c.names[name] = struct{}{}
+	if !tInput.CanAddr() {
+		return
+	}
+	addr := tInput.UnsafeAddr()
+	if other, ok := c.leaves[addr]; ok {
+		c.issues = append(c.issues, fmt.Sprintf("public input %s is bound to the same variable as %s", name, other))
+		return
+	}
+	c.leaves[addr] = name
+}
+
+// checkConstrained records the public inputs of ccs which aren't constrained.
+func (c *publicInputsChecker) checkConstrained(ccs constraint.ConstraintSystem) {
+	public, _ := ccs.UnconstrainedInputs()
+	for _, name := range public {
+		c.issues = append(c.issues, fmt.Sprintf("public input %s is not constrained", name))
+	}
+}
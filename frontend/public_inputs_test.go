@@ -0,0 +1,65 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type publicPoint struct {
+	X frontend.Variable `gnark:",public"`
+}
+
+type aliasedCircuit struct {
+	A, B *publicPoint
+	Y    frontend.Variable
+}
+
+func (c *aliasedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.A.X, c.Y)
+	api.AssertIsEqual(c.B.X, c.Y)
+	return nil
+}
+
+type duplicateNameCircuit struct {
+	A frontend.Variable `gnark:"x,public"`
+	B frontend.Variable `gnark:"x,public"`
+}
+
+func (c *duplicateNameCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.A, c.B)
+	return nil
+}
+
+type unconstrainedPublicCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+}
+
+func (c *unconstrainedPublicCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, 1)
+	return nil
+}
+
+func TestStrictPublicInputs(t *testing.T) {
+	assert := require.New(t)
+
+	shared := &publicPoint{}
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		for _, c := range []frontend.Circuit{
+			&aliasedCircuit{A: shared, B: shared},
+			&duplicateNameCircuit{},
+			&unconstrainedPublicCircuit{},
+		} {
+			_, err := frontend.Compile(ecc.BN254.ScalarField(), builder, c, frontend.IgnoreUnconstrainedInputs())
+			assert.NoError(err)
+			_, err = frontend.Compile(ecc.BN254.ScalarField(), builder, c, frontend.IgnoreUnconstrainedInputs(), frontend.WithStrictPublicInputs())
+			assert.Error(err)
+		}
+		_, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &aliasedCircuit{A: &publicPoint{}, B: &publicPoint{}}, frontend.WithStrictPublicInputs())
+		assert.NoError(err)
+	}
+}
@@ -8,6 +8,14 @@ type LeafInfo struct {
 	Visibility Visibility
 	FullName   func() string // in most instances, we don't need to actually evaluate the name.
 	name       string
+	tags       leafTags
+}
+
+// leafTags are the values of the struct tags of a leaf, or of its closest
+// parent with the tag.
+type leafTags struct {
+	random   string // see Random
+	sanitize string // see Sanitize
 }
 
 // LeafCount stores the number of secret and public interface of type target(reflect.Type)
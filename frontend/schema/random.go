@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// randomTagKey is the key of the struct tag constraining the values assigned by
+// Random.
+const randomTagKey = "random"
+
+// RandomOption constrains the values assigned by Random, or configures it.
+type RandomOption func(*randomConfig) error
+
+type randomConfig struct {
+	source        io.Reader
+	distributions map[string]distribution
+}
+
+// RandomBits constrains the leaves named name, or whose name starts with name
+// followed by "_" (the elements of an array or the fields of a struct), to
+// values of nbBits bits. It is equivalent to the tag `random:"bits=nbBits"`.
+func RandomBits(name string, nbBits int) RandomOption {
+	return func(cfg *randomConfig) error {
+		if nbBits < 0 {
+			return fmt.Errorf("%s: negative number of bits", name)
+		}
+		cfg.distributions[name] = bitsDistribution(nbBits)
+		return nil
+	}
+}
+
+// RandomRange constrains the leaves selected by name as in RandomBits to values
+// between min and max included. It is equivalent to the tag
+// `random:"range=min:max"`.
+func RandomRange(name string, min, max *big.Int) RandomOption {
+	return func(cfg *randomConfig) error {
+		if min.Cmp(max) > 0 {
+			return fmt.Errorf("%s: empty range [%s, %s]", name, min, max)
+		}
+		cfg.distributions[name] = distribution{min: new(big.Int).Set(min), max: new(big.Int).Set(max)}
+		return nil
+	}
+}
+
+// RandomValue assigns value to the leaves selected by name as in RandomBits.
+// It is equivalent to the tag `random:"value=v"`.
+func RandomValue(name string, value *big.Int) RandomOption {
+	return RandomRange(name, value, value)
+}
+
+// RandomSource sets the source of randomness of Random, crypto/rand.Reader by
+// default. A seeded math/rand.Rand gives reproducible assignments.
+func RandomSource(r io.Reader) RandomOption {
+	return func(cfg *randomConfig) error {
+		cfg.source = r
+		return nil
+	}
+}
+
+// Random assigns random elements of the scalar field of curve, as *big.Int, to
+// the leaves of type tLeaf (in practice frontend.Variable) of circuit, which
+// must be a pointer to a structure whose slices are allocated. It is meant to
+// build the assignments of tests and benchmarks which must satisfy the
+// invariants of the application, which the fuzz fillers of the test package
+// ignore.
+//
+// The values of a leaf are uniform over the field, unless constrained by the
+// random tag of the leaf, or of its closest tagged parent:
+//
+//	type Circuit struct {
+//	    Age     frontend.Variable    `random:"range=18:120"`
+//	    Balance []frontend.Variable  `random:"bits=64"`
+//	    Version frontend.Variable    `gnark:",public" random:"value=2"`
+//	}
+//
+// or by the options RandomBits, RandomRange and RandomValue, which override the
+// tags and select the leaves by their full names (see LeafInfo.FullName); the
+// option of the longest name applies when several select a leaf. The bounds of
+// the ranges and the values are decimal or 0x-prefixed hexadecimal integers,
+// negative ones being reduced modulo the field.
+func Random(circuit interface{}, tLeaf reflect.Type, curve ecc.ID, opts ...RandomOption) error {
+	cfg := randomConfig{source: rand.Reader, distributions: make(map[string]distribution)}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return err
+		}
+	}
+	field := curve.ScalarField()
+	if field == nil {
+		return fmt.Errorf("unknown curve %s", curve)
+	}
+
+	handler := func(f LeafInfo, tValue reflect.Value) error {
+		name := f.FullName()
+		d, err := cfg.distribution(name, f.tags.random)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		v, err := d.sample(cfg.source, field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		tValue.Set(reflect.ValueOf(v))
+		return nil
+	}
+	_, err := Walk(circuit, tLeaf, handler)
+	return err
+}
+
+// distribution returns the distribution of the leaf name with the random tag
+// tag: the one of the option of the longest name selecting the leaf, or else
+// the one of the tag.
+func (cfg *randomConfig) distribution(name, tag string) (distribution, error) {
+	best := -1
+	var res distribution
+	for prefix, d := range cfg.distributions {
+		if (name == prefix || strings.HasPrefix(name, prefix+"_")) && len(prefix) > best {
+			best, res = len(prefix), d
+		}
+	}
+	if best >= 0 {
+		return res, nil
+	}
+	return parseRandomTag(tag)
+}
+
+// distribution is the uniform distribution of the integers between min and
+// max included, or over the whole field if min is nil.
+type distribution struct {
+	min, max *big.Int
+}
+
+func bitsDistribution(nbBits int) distribution {
+	max := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+	return distribution{min: new(big.Int), max: max.Sub(max, big.NewInt(1))}
+}
+
+// sample returns a random value of d reduced modulo field.
+func (d distribution) sample(source io.Reader, field *big.Int) (*big.Int, error) {
+	if d.min == nil {
+		return rand.Int(source, field)
+	}
+	width := new(big.Int).Sub(d.max, d.min)
+	width.Add(width, big.NewInt(1))
+	v, err := rand.Int(source, width)
+	if err != nil {
+		return nil, err
+	}
+	v.Add(v, d.min)
+	return v.Mod(v, field), nil
+}
+
+// parseRandomTag returns the distribution of the random tag tag, one of
+// bits=n, range=min:max and value=v; the empty tag is the uniform distribution
+// over the field. It parses the bounds of the sanitize tag as well.
+func parseRandomTag(tag string) (distribution, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return distribution{}, nil
+	}
+	key, value, ok := strings.Cut(tag, "=")
+	if !ok {
+		return distribution{}, fmt.Errorf("invalid tag option %q", tag)
+	}
+	switch strings.TrimSpace(key) {
+	case "bits":
+		nbBits, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || nbBits < 0 {
+			return distribution{}, fmt.Errorf("invalid number of bits %q", value)
+		}
+		return bitsDistribution(nbBits), nil
+	case "range":
+		bounds := strings.Split(value, ":")
+		if len(bounds) != 2 {
+			return distribution{}, fmt.Errorf("invalid range %q, expected min:max", value)
+		}
+		min, err := parseInt(bounds[0])
+		if err != nil {
+			return distribution{}, err
+		}
+		max, err := parseInt(bounds[1])
+		if err != nil {
+			return distribution{}, err
+		}
+		if min.Cmp(max) > 0 {
+			return distribution{}, fmt.Errorf("empty range %q", value)
+		}
+		return distribution{min: min, max: max}, nil
+	case "value":
+		v, err := parseInt(value)
+		if err != nil {
+			return distribution{}, err
+		}
+		return distribution{min: v, max: v}, nil
+	default:
+		return distribution{}, fmt.Errorf("unknown tag option %q", key)
+	}
+}
+
+func parseInt(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimSpace(s), 0)
+	if !ok {
+		return nil, errors.New("invalid integer " + strconv.Quote(s))
+	}
+	return v, nil
+}
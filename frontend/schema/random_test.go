@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+type randomCircuit struct {
+	Age     variable    `random:"range=18:120"`
+	Balance [4]variable `random:"bits=8"`
+	Version variable    `gnark:",public" random:"value=0x2"`
+	Inner   struct {
+		A, B variable
+	} `random:"value=-1"`
+	Free variable
+}
+
+func TestRandom(t *testing.T) {
+	assert := require.New(t)
+	tVariable := reflect.ValueOf(struct{ A variable }{}).FieldByName("A").Type()
+	field := ecc.BN254.ScalarField()
+	minusOne := new(big.Int).Sub(field, big.NewInt(1))
+
+	for i := 0; i < 20; i++ {
+		var c randomCircuit
+		assert.NoError(Random(&c, tVariable, ecc.BN254, RandomSource(mrand.New(mrand.NewSource(int64(i))))))
+
+		age := c.Age.(*big.Int)
+		assert.True(age.Cmp(big.NewInt(18)) >= 0 && age.Cmp(big.NewInt(120)) <= 0, age)
+		for _, b := range c.Balance {
+			assert.Less(b.(*big.Int).BitLen(), 9)
+		}
+		assert.Equal(0, c.Version.(*big.Int).Cmp(big.NewInt(2)))
+		assert.Equal(0, c.Inner.A.(*big.Int).Cmp(minusOne))
+		assert.Equal(0, c.Inner.B.(*big.Int).Cmp(minusOne))
+		assert.Equal(-1, c.Free.(*big.Int).Cmp(field))
+	}
+
+	// the options override the tags, the longest name first
+	var c randomCircuit
+	assert.NoError(Random(&c, tVariable, ecc.BN254,
+		RandomValue("Balance", big.NewInt(7)),
+		RandomValue("Balance_2", big.NewInt(8)),
+		RandomBits("Free", 0),
+	))
+	assert.Equal([4]variable{big.NewInt(7), big.NewInt(7), big.NewInt(8), big.NewInt(7)}, c.Balance)
+	assert.Equal(0, c.Free.(*big.Int).Sign())
+
+	// the same seed gives the same assignment
+	var c1, c2 randomCircuit
+	assert.NoError(Random(&c1, tVariable, ecc.BN254, RandomSource(mrand.New(mrand.NewSource(42)))))
+	assert.NoError(Random(&c2, tVariable, ecc.BN254, RandomSource(mrand.New(mrand.NewSource(42)))))
+	assert.Equal(c1, c2)
+
+	// invalid tags and options
+	var invalid struct {
+		X variable `random:"range=3:2"`
+	}
+	assert.Error(Random(&invalid, tVariable, ecc.BN254))
+	assert.Error(Random(&c, tVariable, ecc.BN254, RandomRange("Age", big.NewInt(3), big.NewInt(2))))
+}
@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// sanitizeTagKey is the key of the struct tag declaring the bounds checked by
+// Sanitize and the leaves it blinds.
+const sanitizeTagKey = "sanitize"
+
+// sanitizeBlind is the option of the sanitize tag marking a leaf as a blinding
+// factor.
+const sanitizeBlind = "blind"
+
+// ErrValueOutOfBounds is returned by Sanitize when a value of an assignment
+// exceeds the bounds of its leaf.
+var ErrValueOutOfBounds = errors.New("value out of bounds")
+
+// SanitizeOption declares the bounds checked by Sanitize, or configures it.
+type SanitizeOption func(*sanitizeConfig) error
+
+type sanitizeConfig struct {
+	bounds   map[string]distribution
+	blinding io.Reader // nil if the blinding factors are kept
+}
+
+// SanitizeBits bounds the leaves selected by name as in RandomBits to values
+// of nbBits bits. It is equivalent to the tag `sanitize:"bits=nbBits"`.
+func SanitizeBits(name string, nbBits int) SanitizeOption {
+	return func(cfg *sanitizeConfig) error {
+		if nbBits < 0 {
+			return fmt.Errorf("%s: negative number of bits", name)
+		}
+		cfg.bounds[name] = bitsDistribution(nbBits)
+		return nil
+	}
+}
+
+// SanitizeRange bounds the leaves selected by name as in RandomBits to values
+// between min and max included. It is equivalent to the tag
+// `sanitize:"range=min:max"`.
+func SanitizeRange(name string, min, max *big.Int) SanitizeOption {
+	return func(cfg *sanitizeConfig) error {
+		if min.Cmp(max) > 0 {
+			return fmt.Errorf("%s: empty range [%s, %s]", name, min, max)
+		}
+		cfg.bounds[name] = distribution{min: new(big.Int).Set(min), max: new(big.Int).Set(max)}
+		return nil
+	}
+}
+
+// SanitizeBlinding makes Sanitize assign fresh random values, read from
+// source, to the blinding factors of the assignment. A nil source is
+// crypto/rand.Reader.
+func SanitizeBlinding(source io.Reader) SanitizeOption {
+	return func(cfg *sanitizeConfig) error {
+		if source == nil {
+			source = rand.Reader
+		}
+		cfg.blinding = source
+		return nil
+	}
+}
+
+// Sanitize checks the values of the leaves of type tLeaf (in practice
+// frontend.Variable) of the assignment circuit before proving, in the scalar
+// field field: the values of the bounded leaves must be within their bounds
+// once reduced modulo the field, else Sanitize returns an error wrapping
+// ErrValueOutOfBounds naming the leaf. A value exceeding the bit width which
+// the circuit assumes would otherwise be rejected by its range checks at best,
+// and leak through the commitments of the proof at worst.
+//
+// The leaves are bounded by their sanitize tag, or the one of their closest
+// tagged parent, and the blinding factors of the circuit, which it uses to
+// hide the values it commits to, are marked by the blind option:
+//
+//	type Circuit struct {
+//	    Balance frontend.Variable   `sanitize:"bits=64"`
+//	    Age     frontend.Variable   `sanitize:"range=18:120"`
+//	    Salt    frontend.Variable   `sanitize:"blind"`
+//	    Nonces  []frontend.Variable `sanitize:"blind,bits=128"`
+//	}
+//
+// The options SanitizeBits and SanitizeRange override the bounds of the tags,
+// selecting the leaves as the options of Random do. With SanitizeBlinding, the
+// blinding factors are assigned fresh random values, within their bounds if
+// any, so that two proofs of the same statement don't share them; else they
+// are checked like the other leaves. The unbounded leaves are left unchecked,
+// but must be assigned.
+func Sanitize(circuit interface{}, tLeaf reflect.Type, field *big.Int, opts ...SanitizeOption) error {
+	cfg := sanitizeConfig{bounds: make(map[string]distribution)}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return err
+		}
+	}
+
+	handler := func(f LeafInfo, tValue reflect.Value) error {
+		name := f.FullName()
+		bounds, blind, err := cfg.leafBounds(name, f.tags.sanitize)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if blind && cfg.blinding != nil {
+			v, err := bounds.sample(cfg.blinding, field)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			tValue.Set(reflect.ValueOf(v))
+			return nil
+		}
+		value := tValue.Interface()
+		if value == nil {
+			return fmt.Errorf("%s is not assigned", name)
+		}
+		if bounds.min == nil {
+			return nil
+		}
+		v := utils.FromInterface(value)
+		v.Mod(&v, field)
+		if v.Cmp(bounds.min) < 0 || v.Cmp(bounds.max) > 0 {
+			return fmt.Errorf("%w: %s is not in [%s, %s]", ErrValueOutOfBounds, name, bounds.min, bounds.max)
+		}
+		return nil
+	}
+	_, err := Walk(circuit, tLeaf, handler)
+	return err
+}
+
+// leafBounds returns the bounds of the leaf name with the sanitize tag tag,
+// unbounded if min is nil, and whether the leaf is a blinding factor. The
+// bounds of the option of the longest name selecting the leaf override the
+// ones of the tag.
+func (cfg *sanitizeConfig) leafBounds(name, tag string) (bounds distribution, blind bool, err error) {
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == sanitizeBlind:
+			blind = true
+		case option == "":
+		case bounds.min != nil:
+			return distribution{}, false, fmt.Errorf("several bounds in sanitize tag %q", tag)
+		default:
+			if bounds, err = parseRandomTag(option); err != nil {
+				return distribution{}, false, err
+			}
+		}
+	}
+	best := -1
+	for prefix, d := range cfg.bounds {
+		if (name == prefix || strings.HasPrefix(name, prefix+"_")) && len(prefix) > best {
+			best, bounds = len(prefix), d
+		}
+	}
+	return bounds, blind, nil
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"errors"
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/require"
+)
+
+type sanitizeCircuit struct {
+	Balance variable    `sanitize:"bits=8"`
+	Age     variable    `gnark:",public" sanitize:"range=18:120"`
+	Salt    variable    `sanitize:"blind"`
+	Nonces  [2]variable `sanitize:"blind,bits=16"`
+	Free    variable
+}
+
+func TestSanitize(t *testing.T) {
+	assert := require.New(t)
+	tVariable := reflect.ValueOf(struct{ A variable }{}).FieldByName("A").Type()
+	field := ecc.BN254.ScalarField()
+
+	valid := func() *sanitizeCircuit {
+		return &sanitizeCircuit{Balance: 255, Age: 18, Salt: 1, Nonces: [2]variable{2, 3}, Free: -1}
+	}
+	assert.NoError(Sanitize(valid(), tVariable, field))
+
+	c := valid()
+	c.Balance = 256
+	err := Sanitize(c, tVariable, field)
+	assert.True(errors.Is(err, ErrValueOutOfBounds), err)
+	assert.ErrorContains(err, "Balance")
+
+	c = valid()
+	c.Age = -1 // reduced modulo the field
+	assert.ErrorIs(Sanitize(c, tVariable, field), ErrValueOutOfBounds)
+
+	c = valid()
+	c.Nonces[1] = 1 << 16
+	assert.ErrorIs(Sanitize(c, tVariable, field), ErrValueOutOfBounds)
+
+	// the options override the tags
+	c = valid()
+	assert.ErrorIs(Sanitize(c, tVariable, field, SanitizeBits("Balance", 4)), ErrValueOutOfBounds)
+	assert.ErrorIs(Sanitize(c, tVariable, field, SanitizeRange("Free", big.NewInt(0), big.NewInt(10))), ErrValueOutOfBounds)
+	c.Age = 150
+	assert.NoError(Sanitize(c, tVariable, field, SanitizeRange("Age", big.NewInt(0), big.NewInt(200))))
+
+	c = valid()
+	c.Free = nil
+	assert.ErrorContains(Sanitize(c, tVariable, field), "Free is not assigned")
+
+	// blinding
+	c = valid()
+	c.Salt, c.Nonces[1] = nil, 1<<20
+	assert.NoError(Sanitize(c, tVariable, field, SanitizeBlinding(mrand.New(mrand.NewSource(0))))) //#nosec G404 weak rng is fine here
+	assert.Equal(255, c.Balance)
+	salt, ok := c.Salt.(*big.Int)
+	assert.True(ok)
+	assert.True(salt.Cmp(field) < 0)
+	for _, n := range c.Nonces {
+		assert.Less(n.(*big.Int).BitLen(), 17)
+	}
+
+	blinded := valid()
+	assert.NoError(Sanitize(blinded, tVariable, field, SanitizeBlinding(nil)))
+	assert.NotEqual(0, blinded.Salt.(*big.Int).Cmp(salt))
+
+	type invalid struct {
+		A variable `sanitize:"bits=8,range=0:1"`
+	}
+	assert.ErrorContains(Sanitize(&invalid{A: 0}, tVariable, field), "several bounds")
+}
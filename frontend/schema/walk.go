@@ -59,7 +59,7 @@ func (w *walker) Interface(value reflect.Value) error {
 
 	// call the handler.
 	if w.handler != nil {
-		if err := w.handler(LeafInfo{Visibility: v, FullName: w.name, name: ""}, value); err != nil {
+		if err := w.handler(LeafInfo{Visibility: v, FullName: w.name, name: "", tags: w.tags()}, value); err != nil {
 			return err
 		}
 	}
@@ -92,7 +92,7 @@ func (w *walker) Slice(value reflect.Value) error {
 }
 
 func (w *walker) SliceElem(index int, _ reflect.Value) error {
-	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index)})
+	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index), tags: w.tags()})
 	return nil
 }
 
@@ -104,7 +104,7 @@ func (w *walker) Array(value reflect.Value) error {
 	return nil
 }
 func (w *walker) ArrayElem(index int, _ reflect.Value) error {
-	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index)})
+	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index), tags: w.tags()})
 	return nil
 }
 
@@ -119,12 +119,13 @@ func (w *walker) handleLeaves(value reflect.Value) error {
 	// call the handler.
 	if w.handler != nil {
 		n := w.name()
+		tags := w.tags()
 		for i := 0; i < value.Len(); i++ {
 			fName := func() string {
 				return n + "_" + strconv.Itoa(i)
 			}
 			vv := value.Index(i)
-			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, name: ""}, vv); err != nil {
+			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, name: "", tags: tags}, vv); err != nil {
 				return err
 			}
 		}
@@ -164,6 +165,13 @@ func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
 	info := LeafInfo{
 		name:       sf.Name,
 		Visibility: parentVisibility,
+		tags:       w.tags(),
+	}
+	if randomTag, ok := sf.Tag.Lookup(randomTagKey); ok {
+		info.tags.random = randomTag
+	}
+	if sanitizeTag, ok := sf.Tag.Lookup(sanitizeTagKey); ok {
+		info.tags.sanitize = sanitizeTag
 	}
 
 	var nameInTag string
@@ -221,6 +229,14 @@ func (w *walker) visibility() Visibility {
 	return Unset
 }
 
+// defaults to no tag
+func (w *walker) tags() leafTags {
+	if !w.path.isEmpty() {
+		return w.path.top().tags
+	}
+	return leafTags{}
+}
+
 func (w *walker) name() string {
 	if w.path.isEmpty() {
 		return ""
@@ -0,0 +1,52 @@
+package frontend_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/profile"
+	"github.com/stretchr/testify/require"
+)
+
+type scopedCircuit struct {
+	X [3]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *scopedCircuit) Define(api frontend.API) error {
+	defer frontend.Scope(api, "census")()
+	for i := range c.X {
+		pop := frontend.Scope(api, fmt.Sprintf("check[%d]", i))
+		api.AssertIsEqual(api.Mul(c.X[i], c.X[i]), c.Y)
+		pop()
+	}
+	return nil
+}
+
+func TestScope(t *testing.T) {
+	assert := require.New(t)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		p := profile.Start(profile.WithNoOutput())
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &scopedCircuit{})
+		p.Stop()
+		assert.NoError(err)
+
+		byScope := p.NbConstraintsByScope()
+		assert.Len(byScope, 3)
+		for i := 0; i < 3; i++ {
+			assert.NotZero(byScope[fmt.Sprintf("census/check[%d]", i)])
+		}
+		assert.Equal("census/check[2]", ccs.GetConstraintScope(ccs.GetNbConstraints()-1))
+
+		w, err := frontend.NewWitness(&scopedCircuit{X: [3]frontend.Variable{2, -2, 3}, Y: 4}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		err = ccs.IsSolved(w)
+		assert.Error(err)
+		assert.Contains(err.Error(), "(census/check[2])")
+	}
+}
@@ -0,0 +1,443 @@
+// Package witgen evaluates circuits to generate witnesses, without recording
+// constraints.
+//
+// The compilation of a circuit and the solving of its constraint system are
+// heavy when only the witness is needed, for example on a client whose proofs
+// are computed by a remote prover from a constraint system compiled elsewhere.
+// [Generate] instead runs the circuit Define method directly on the assigned
+// values, executing the hints and failing on the first unsatisfied assertion.
+//
+// The evaluator doesn't implement [frontend.Committer]: the value of a
+// commitment depends on the proving key, so circuits committing to variables
+// must be solved by the prover.
+package witgen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/kvstore"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// Option defines option for altering the behaviour of Generate.
+type Option func(*config) error
+
+type config struct {
+	solverOpts  []solver.Option
+	witnessOpts []frontend.WitnessOption
+}
+
+// WithSolverOptions sets the solver options, which provide the hint functions
+// and the logger printing api.Println.
+func WithSolverOptions(opts ...solver.Option) Option {
+	return func(c *config) error {
+		c.solverOpts = opts
+		return nil
+	}
+}
+
+// WithWitnessOptions sets the options used to build the returned witness, see
+// frontend.NewWitness.
+func WithWitnessOptions(opts ...frontend.WitnessOption) Option {
+	return func(c *config) error {
+		c.witnessOpts = opts
+		return nil
+	}
+}
+
+// Generate evaluates circuit on assignment and returns the full witness. It
+// returns an error if an assertion of the circuit doesn't hold, or if a hint
+// fails.
+//
+// The witness is the one returned by frontend.NewWitness; evaluating the
+// circuit ensures that the prover will be able to solve the constraint system
+// with it.
+func Generate(field *big.Int, circuit, assignment frontend.Circuit, opts ...Option) (w witness.Witness, err error) {
+	var cfg config
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	solverCfg, err := solver.NewConfig(cfg.solverOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("solver options: %w", err)
+	}
+
+	// we evaluate a copy of the circuit, as Define may use other attributes
+	// of the circuit than the variables
+	c, err := assign(circuit, assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &evaluator{
+		q:     new(big.Int).Set(field),
+		cfg:   solverCfg,
+		Store: kvstore.New(),
+	}
+	if err = e.run(c); err != nil {
+		return nil, err
+	}
+
+	return frontend.NewWitness(assignment, field, cfg.witnessOpts...)
+}
+
+// run calls Define and the deferred callbacks, converting the panics raised
+// by failing assertions to errors.
+func (e *evaluator) run(c frontend.Circuit) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+			if debug.Debug {
+				err = fmt.Errorf("%w\n%s", err, string(debug.Stack()))
+			}
+		}
+	}()
+	if err = c.Define(e); err != nil {
+		return fmt.Errorf("define: %w", err)
+	}
+	for i := 0; ; i++ {
+		deferred := circuitdefer.GetAll[func(frontend.API) error](e)
+		if i >= len(deferred) {
+			return nil
+		}
+		if err = deferred[i](e); err != nil {
+			return fmt.Errorf("deferred: defer fn %d: %w", i, err)
+		}
+	}
+}
+
+var tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
+
+// assign returns a shallow copy of circuit, with the variables set to the
+// values of assignment.
+func assign(circuit, assignment frontend.Circuit) (frontend.Circuit, error) {
+	cValue := reflect.ValueOf(circuit).Elem()
+	copied := reflect.New(cValue.Type())
+	copied.Elem().Set(cValue)
+	c := copied.Interface().(frontend.Circuit)
+
+	var values []reflect.Value
+	if _, err := schema.Walk(assignment, tVariable, func(f schema.LeafInfo, tValue reflect.Value) error {
+		if tValue.IsNil() {
+			return fmt.Errorf("when parsing variable %s: missing assignment", f.FullName())
+		}
+		values = append(values, tValue)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	i := 0
+	if _, err := schema.Walk(c, tVariable, func(f schema.LeafInfo, tValue reflect.Value) error {
+		if i >= len(values) {
+			return errors.New("assignment doesn't match the circuit")
+		}
+		tValue.Set(values[i])
+		i++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if i != len(values) {
+		return nil, errors.New("assignment doesn't match the circuit")
+	}
+	return c, nil
+}
+
+// evaluator implements frontend.API on *big.Int values reduced modulo q.
+type evaluator struct {
+	q   *big.Int
+	cfg solver.Config
+	kvstore.Store
+}
+
+func (e *evaluator) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	res := new(big.Int).Add(e.toBigInt(i1), e.toBigInt(i2))
+	for i := range in {
+		res.Add(res, e.toBigInt(in[i]))
+	}
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) MulAcc(a, b, c frontend.Variable) frontend.Variable {
+	res := new(big.Int).Mul(e.toBigInt(b), e.toBigInt(c))
+	res.Add(res, e.toBigInt(a))
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) Neg(i1 frontend.Variable) frontend.Variable {
+	res := new(big.Int).Neg(e.toBigInt(i1))
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	res := new(big.Int).Sub(e.toBigInt(i1), e.toBigInt(i2))
+	for i := range in {
+		res.Sub(res, e.toBigInt(in[i]))
+	}
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	res := new(big.Int).Mul(e.toBigInt(i1), e.toBigInt(i2))
+	res.Mod(res, e.q)
+	for i := range in {
+		res.Mul(res, e.toBigInt(in[i]))
+		res.Mod(res, e.q)
+	}
+	return res
+}
+
+func (e *evaluator) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
+	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
+	if b1.Sign() == 0 && b2.Sign() == 0 {
+		return 0
+	}
+	return e.Div(b1, b2)
+}
+
+func (e *evaluator) Div(i1, i2 frontend.Variable) frontend.Variable {
+	res := e.inverse(e.toBigInt(i2), "div")
+	res.Mul(res, e.toBigInt(i1))
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) Inverse(i1 frontend.Variable) frontend.Variable {
+	return e.inverse(e.toBigInt(i1), "inverse")
+}
+
+func (e *evaluator) inverse(b *big.Int, op string) *big.Int {
+	res := new(big.Int)
+	if res.ModInverse(b, e.q) == nil {
+		panic(fmt.Sprintf("[%s] %s has no inverse", op, b.String()))
+	}
+	return res
+}
+
+func (e *evaluator) ToBinary(i1 frontend.Variable, n ...int) []frontend.Variable {
+	nbBits := e.FieldBitLen()
+	if len(n) == 1 {
+		nbBits = n[0]
+		if nbBits < 0 {
+			panic("invalid n")
+		}
+	}
+	b1 := e.toBigInt(i1)
+	if b1.BitLen() > nbBits {
+		panic(fmt.Sprintf("[toBinary] %s doesn't fit in %d bits", b1.String(), nbBits))
+	}
+	res := make([]frontend.Variable, nbBits)
+	for i := range res {
+		res[i] = b1.Bit(i)
+	}
+	return res
+}
+
+func (e *evaluator) FromBinary(b ...frontend.Variable) frontend.Variable {
+	res := new(big.Int)
+	for i := len(b) - 1; i >= 0; i-- {
+		res.Lsh(res, 1)
+		if e.mustBeBoolean(e.toBigInt(b[i]), "fromBinary") {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) Xor(a, b frontend.Variable) frontend.Variable {
+	return boolToInt(e.mustBeBoolean(e.toBigInt(a), "xor") != e.mustBeBoolean(e.toBigInt(b), "xor"))
+}
+
+func (e *evaluator) Or(a, b frontend.Variable) frontend.Variable {
+	ba, bb := e.mustBeBoolean(e.toBigInt(a), "or"), e.mustBeBoolean(e.toBigInt(b), "or")
+	return boolToInt(ba || bb)
+}
+
+func (e *evaluator) And(a, b frontend.Variable) frontend.Variable {
+	ba, bb := e.mustBeBoolean(e.toBigInt(a), "and"), e.mustBeBoolean(e.toBigInt(b), "and")
+	return boolToInt(ba && bb)
+}
+
+func (e *evaluator) Select(b frontend.Variable, i1, i2 frontend.Variable) frontend.Variable {
+	if e.mustBeBoolean(e.toBigInt(b), "select") {
+		return e.toBigInt(i1)
+	}
+	return e.toBigInt(i2)
+}
+
+func (e *evaluator) Lookup2(b0, b1 frontend.Variable, i0, i1, i2, i3 frontend.Variable) frontend.Variable {
+	s0, s1 := e.mustBeBoolean(e.toBigInt(b0), "lookup2"), e.mustBeBoolean(e.toBigInt(b1), "lookup2")
+	switch {
+	case s0 && s1:
+		return e.toBigInt(i3)
+	case s1:
+		return e.toBigInt(i2)
+	case s0:
+		return e.toBigInt(i1)
+	}
+	return e.toBigInt(i0)
+}
+
+func (e *evaluator) IsZero(i1 frontend.Variable) frontend.Variable {
+	return boolToInt(e.toBigInt(i1).Sign() == 0)
+}
+
+func (e *evaluator) Cmp(i1, i2 frontend.Variable) frontend.Variable {
+	res := big.NewInt(int64(e.toBigInt(i1).Cmp(e.toBigInt(i2))))
+	return res.Mod(res, e.q)
+}
+
+func (e *evaluator) AssertIsEqual(i1, i2 frontend.Variable) {
+	if b1, b2 := e.toBigInt(i1), e.toBigInt(i2); b1.Cmp(b2) != 0 {
+		panic(fmt.Sprintf("[assertIsEqual] %s == %s", b1.String(), b2.String()))
+	}
+}
+
+func (e *evaluator) AssertIsDifferent(i1, i2 frontend.Variable) {
+	if b1, b2 := e.toBigInt(i1), e.toBigInt(i2); b1.Cmp(b2) == 0 {
+		panic(fmt.Sprintf("[assertIsDifferent] %s != %s", b1.String(), b2.String()))
+	}
+}
+
+func (e *evaluator) AssertIsBoolean(i1 frontend.Variable) {
+	e.mustBeBoolean(e.toBigInt(i1), "assertIsBoolean")
+}
+
+func (e *evaluator) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
+	if b1, b2 := e.toBigInt(v), e.toBigInt(bound); b1.Cmp(b2) == 1 {
+		panic(fmt.Sprintf("[assertIsLessOrEqual] %s <= %s", b1.String(), b2.String()))
+	}
+}
+
+func (e *evaluator) Println(a ...frontend.Variable) {
+	cfg := frontend.LogConfig{Level: zerolog.DebugLevel}
+	if _, file, line, ok := runtime.Caller(1); ok {
+		cfg.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	e.Log(cfg, a...)
+}
+
+// Log implements [frontend.Logger], printing to the solver logger.
+func (e *evaluator) Log(cfg frontend.LogConfig, a ...frontend.Variable) {
+	if cfg.Condition != nil && e.toBigInt(cfg.Condition).Sign() == 0 {
+		return
+	}
+	event := e.cfg.Logger.WithLevel(cfg.Level).Str(zerolog.CallerFieldName, cfg.Caller)
+	for i, key := range cfg.Keys {
+		event = event.Str(key, e.toBigInt(cfg.Values[i]).String())
+	}
+	var sbb strings.Builder
+	for i := range a {
+		if i > 0 {
+			sbb.WriteByte(' ')
+		}
+		if b, ok := a[i].(*big.Int); ok {
+			sbb.WriteString(b.String())
+		} else {
+			sbb.WriteString(fmt.Sprint(a[i]))
+		}
+	}
+	event.Msg(sbb.String())
+}
+
+func (e *evaluator) Compiler() frontend.Compiler {
+	return e
+}
+
+// NewHint runs the hint f, or the function the solver options provide for
+// it.
+func (e *evaluator) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
+	if nbOutputs <= 0 {
+		return nil, errors.New("hint function must return at least one output")
+	}
+	fn, ok := e.cfg.HintFunctions[f.ID]
+	if !ok {
+		fn = f.Fn
+	}
+	in := make([]*big.Int, len(inputs))
+	for i := range inputs {
+		in[i] = e.toBigInt(inputs[i])
+	}
+	out := make([]*big.Int, nbOutputs)
+	for i := range out {
+		out[i] = new(big.Int)
+	}
+	if err := fn(e.q, in, out); err != nil {
+		panic(fmt.Sprintf("hint %s: %v", f.Name, err))
+	}
+	res := make([]frontend.Variable, nbOutputs)
+	for i := range out {
+		res[i] = out[i].Mod(out[i], e.q)
+	}
+	return res, nil
+}
+
+// ConstantValue returns false: the values are not known when compiling the
+// circuit, Define must not depend on them.
+func (e *evaluator) ConstantValue(v frontend.Variable) (*big.Int, bool) {
+	return nil, false
+}
+
+func (e *evaluator) MarkBoolean(v frontend.Variable) {
+	e.mustBeBoolean(e.toBigInt(v), "markBoolean")
+}
+
+func (e *evaluator) IsBoolean(v frontend.Variable) bool {
+	b := e.toBigInt(v)
+	return b.IsUint64() && b.Uint64() <= 1
+}
+
+func (e *evaluator) Field() *big.Int {
+	return e.q
+}
+
+func (e *evaluator) FieldBitLen() int {
+	return e.q.BitLen()
+}
+
+func (e *evaluator) Defer(cb func(frontend.API) error) {
+	circuitdefer.Put(e, cb)
+}
+
+// Check implements [frontend.Rangechecker].
+func (e *evaluator) Check(v frontend.Variable, bits int) {
+	if b := e.toBigInt(v); b.BitLen() > bits {
+		panic(fmt.Sprintf("[rangeCheck] %s doesn't fit in %d bits", b.String(), bits))
+	}
+}
+
+func (e *evaluator) toBigInt(v frontend.Variable) *big.Int {
+	if b, ok := v.(*big.Int); ok && b.Sign() >= 0 && b.Cmp(e.q) < 0 {
+		return b
+	}
+	b := utils.FromInterface(v)
+	return b.Mod(&b, e.q)
+}
+
+func (e *evaluator) mustBeBoolean(b *big.Int, op string) bool {
+	if !b.IsUint64() || b.Uint64() > 1 {
+		panic(fmt.Sprintf("[%s] %s is not boolean", op, b.String()))
+	}
+	return b.Uint64() == 1
+}
+
+func boolToInt(b bool) *big.Int {
+	if b {
+		return big.NewInt(1)
+	}
+	return big.NewInt(0)
+}
@@ -0,0 +1,99 @@
+package witgen_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/witgen"
+	"github.com/consensys/gnark/std/types"
+	"github.com/stretchr/testify/require"
+)
+
+var sqrtHint = solver.NewHint("witgen_test.sqrt", func(field *big.Int, inputs, outputs []*big.Int) error {
+	outputs[0].ModSqrt(inputs[0], field)
+	return nil
+})
+
+type sqrtCircuit struct {
+	X     frontend.Variable
+	Y     frontend.Variable `gnark:",public"`
+	Bound frontend.Variable `gnark:",public"`
+}
+
+func (c *sqrtCircuit) Define(api frontend.API) error {
+	t := types.New(api)
+	root, err := api.Compiler().NewHint(sqrtHint, 1, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(root[0], root[0]), c.Y)
+	api.Compiler().Defer(func(api frontend.API) error {
+		t.AssertTrue(t.IsLessU64(t.U64(c.X), t.U64(c.Bound)))
+		return nil
+	})
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestGenerate(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &sqrtCircuit{})
+	assert.NoError(err)
+
+	assignment := &sqrtCircuit{X: 5, Y: 25, Bound: 10}
+	w, err := witgen.Generate(field, &sqrtCircuit{}, assignment, witgen.WithSolverOptions(solver.WithHints(sqrtHint)))
+	assert.NoError(err)
+	expected, err := frontend.NewWitness(assignment, field)
+	assert.NoError(err)
+	assert.Equal(expected.Vector(), w.Vector())
+	assert.NoError(ccs.IsSolved(w, solver.WithHints(sqrtHint)))
+
+	public, err := witgen.Generate(field, &sqrtCircuit{}, assignment, witgen.WithWitnessOptions(frontend.PublicOnly()))
+	assert.NoError(err)
+	expectedPublic, err := expected.Public()
+	assert.NoError(err)
+	assert.Equal(expectedPublic.Vector(), public.Vector())
+
+	// unsatisfied assertion
+	_, err = witgen.Generate(field, &sqrtCircuit{}, &sqrtCircuit{X: 5, Y: 26, Bound: 10})
+	assert.Error(err)
+	// unsatisfied deferred assertion
+	_, err = witgen.Generate(field, &sqrtCircuit{}, &sqrtCircuit{X: 5, Y: 25, Bound: 5})
+	assert.Error(err)
+	// overridden hint
+	_, err = witgen.Generate(field, &sqrtCircuit{}, assignment, witgen.WithSolverOptions(solver.OverrideHint(sqrtHint.ID, func(_ *big.Int, _, outputs []*big.Int) error {
+		outputs[0].SetUint64(4)
+		return nil
+	})))
+	assert.Error(err)
+	// missing assignment
+	_, err = witgen.Generate(field, &sqrtCircuit{}, &sqrtCircuit{X: 5, Y: 25})
+	assert.Error(err)
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	field := ecc.BN254.ScalarField()
+	assignment := &sqrtCircuit{X: 5, Y: 25, Bound: 10}
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &sqrtCircuit{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("witgen", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = witgen.Generate(field, &sqrtCircuit{}, assignment, witgen.WithSolverOptions(solver.WithHints(sqrtHint)))
+		}
+	})
+	b.Run("solver", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			w, _ := frontend.NewWitness(assignment, field)
+			_, _ = ccs.Solve(w, solver.WithHints(sqrtHint))
+		}
+	})
+}
@@ -1,11 +1,14 @@
 package frontend
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
 )
 
 // NewWitness build an ordered vector of field elements from the given assignment (Circuit)
@@ -24,6 +27,9 @@ func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witn
 	if err != nil {
 		return nil, err
 	}
+	if opt.publicInputsHasher != nil {
+		return newHashedWitness(assignment, field, s, opt)
+	}
 	if opt.publicOnly {
 		s.Secret = 0
 	}
@@ -60,6 +66,72 @@ func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witn
 	return w, nil
 }
 
+// newHashedWitness builds the witness of a circuit compiled with
+// WithPublicInputsHashing: [hash | public | secret].
+func newHashedWitness(assignment Circuit, field *big.Int, s schema.LeafCount, opt witnessConfig) (witness.Witness, error) {
+	var publicInputs []*big.Int
+	var values []any
+	for _, visibility := range []schema.Visibility{schema.Public, schema.Secret} {
+		if visibility == schema.Secret && opt.publicOnly {
+			break
+		}
+		_, err := schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+			if leaf.Visibility != visibility {
+				return nil
+			}
+			v := tValue.Interface()
+			if v == nil {
+				return fmt.Errorf("%s is not assigned", leaf.FullName())
+			}
+			if visibility == schema.Public {
+				b := utils.FromInterface(v)
+				publicInputs = append(publicInputs, &b)
+			}
+			values = append(values, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h, err := opt.publicInputsHasher.Hash(field, publicInputs)
+	if err != nil {
+		return nil, fmt.Errorf("hash public inputs: %w", err)
+	}
+
+	nbSecret := s.Public + s.Secret
+	if opt.publicOnly {
+		nbSecret = 0
+		values = nil
+	}
+
+	w, err := witness.New(field)
+	if err != nil {
+		return nil, err
+	}
+	chValues := make(chan any)
+	go func() {
+		defer close(chValues)
+		chValues <- h
+		for _, v := range values {
+			chValues <- v
+		}
+	}()
+	if err := w.Fill(1, nbSecret, chValues); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Sanitize checks the values of assignment against the bounds declared by the
+// sanitize struct tags of the circuit and the options, and assigns fresh
+// randomness to its blinding factors with schema.SanitizeBlinding; see
+// schema.Sanitize. It is meant to be called by the prover before NewWitness.
+func Sanitize(assignment Circuit, field *big.Int, opts ...schema.SanitizeOption) error {
+	return schema.Sanitize(assignment, tVariable, field, opts...)
+}
+
 // NewSchema returns the schema corresponding to the circuit structure.
 //
 // This is used to JSON (un)marshall witnesses.
@@ -86,7 +158,8 @@ func options(opts ...WitnessOption) (witnessConfig, error) {
 type WitnessOption func(*witnessConfig) error
 
 type witnessConfig struct {
-	publicOnly bool
+	publicOnly         bool
+	publicInputsHasher PublicInputsHasher
 }
 
 // PublicOnly enables to instantiate a witness with the public part only of the assignment
@@ -96,3 +169,16 @@ func PublicOnly() WitnessOption {
 		return nil
 	}
 }
+
+// HashPublicInputs builds the witness of a circuit compiled with
+// WithPublicInputsHashing(h): the public part of the witness is the hash of the
+// public inputs of the assignment, which are moved to the secret part.
+func HashPublicInputs(h PublicInputsHasher) WitnessOption {
+	return func(opt *witnessConfig) error {
+		if h == nil {
+			return errors.New("nil public inputs hasher")
+		}
+		opt.publicInputsHasher = h
+		return nil
+	}
+}
@@ -15,9 +15,7 @@ func Put[T any](builder any, cb T) {
 	}
 	val := kv.GetKeyValue(deferKey{})
 	var deferred []T
-	if val == nil {
-		deferred = []T{cb}
-	} else {
+	if val != nil {
 		var ok bool
 		deferred, ok = val.([]T)
 		if !ok {
@@ -43,3 +41,38 @@ func GetAll[T any](builder any) []T {
 	}
 	return deferred
 }
+
+type postponedKey struct{}
+
+// Postpone defers cb again, like Put, and records it as postponed. Gadgets
+// postpone their deferred callbacks while the callbacks deferred after them
+// may still use the gadget, see NbPendingAfter.
+func Postpone[T any](builder any, cb T) {
+	Put(builder, cb)
+	kv := builder.(kvstore.Store)
+	postponed, _ := kv.GetKeyValue(postponedKey{}).(map[int]struct{})
+	if postponed == nil {
+		postponed = make(map[int]struct{})
+		kv.SetKeyValue(postponedKey{}, postponed)
+	}
+	postponed[len(GetAll[T](builder))-1] = struct{}{}
+}
+
+// NbPendingAfter returns the number of callbacks deferred after the first n
+// ones, which are pending when the n-th one runs. The callbacks deferred by
+// Postpone aren't counted if ignorePostponed is set: two gadgets postponing
+// their callbacks while any other is pending would postpone each other forever.
+func NbPendingAfter[T any](builder any, n int, ignorePostponed bool) int {
+	all := GetAll[T](builder)
+	if !ignorePostponed {
+		return len(all) - n
+	}
+	postponed, _ := builder.(kvstore.Store).GetKeyValue(postponedKey{}).(map[int]struct{})
+	nb := 0
+	for i := n; i < len(all); i++ {
+		if _, ok := postponed[i]; !ok {
+			nb++
+		}
+	}
+	return nb
+}
@@ -0,0 +1,143 @@
+package groth16
+
+// cairoTemplate is a StarkNet port of the solidityTemplate verifier, written in Cairo 2.
+// Cairo has no native support for the BN254 curve, so the contract delegates
+// the curve arithmetic to a contract implementing the IBN254 interface (for example
+// one built on the garaga library), set at deployment.
+// this is an experimental feature and gnark cairo generator as not been thoroughly tested
+const cairoTemplate = `
+{{- $lenK := len .G1.K }}
+// SPDX-License-Identifier: Apache-2.0
+//
+// Groth16 verifier over BN254, generated by gnark.
+
+#[derive(Copy, Drop, Serde)]
+pub struct G1Point {
+    pub x: u256,
+    pub y: u256,
+}
+
+// Encoding of field elements is: x_a0 + x_a1 * u
+#[derive(Copy, Drop, Serde)]
+pub struct G2Point {
+    pub x_a0: u256,
+    pub x_a1: u256,
+    pub y_a0: u256,
+    pub y_a1: u256,
+}
+
+// IBN254 exposes the operations of the EVM precompiles 0x06 (ec_add), 0x07
+// (ec_mul) and 0x08 (pairing_check) on BN254.
+#[starknet::interface]
+pub trait IBN254<TContractState> {
+    fn ec_add(self: @TContractState, p: G1Point, q: G1Point) -> G1Point;
+    fn ec_mul(self: @TContractState, p: G1Point, s: u256) -> G1Point;
+    fn pairing_check(self: @TContractState, p: Span<G1Point>, q: Span<G2Point>) -> bool;
+}
+
+#[starknet::interface]
+pub trait IGroth16Verifier<TContractState> {
+    fn verify_proof(
+        self: @TContractState, a: G1Point, b: G2Point, c: G1Point, input: Span<u256>,
+    ) -> bool;
+}
+
+#[starknet::contract]
+pub mod Groth16Verifier {
+    use starknet::ContractAddress;
+    use starknet::storage::{StoragePointerReadAccess, StoragePointerWriteAccess};
+    use super::{G1Point, G2Point, IBN254Dispatcher, IBN254DispatcherTrait};
+
+    const SNARK_SCALAR_FIELD: u256 = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+    const PRIME_Q: u256 = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
+
+    #[storage]
+    struct Storage {
+        bn254: ContractAddress,
+    }
+
+    #[constructor]
+    fn constructor(ref self: ContractState, bn254: ContractAddress) {
+        self.bn254.write(bn254);
+    }
+
+    fn alpha1() -> G1Point {
+        G1Point { x: {{.G1.Alpha.X.String}}, y: {{.G1.Alpha.Y.String}} }
+    }
+
+    fn beta2() -> G2Point {
+        G2Point {
+            x_a0: {{.G2.Beta.X.A0.String}},
+            x_a1: {{.G2.Beta.X.A1.String}},
+            y_a0: {{.G2.Beta.Y.A0.String}},
+            y_a1: {{.G2.Beta.Y.A1.String}},
+        }
+    }
+
+    fn gamma2() -> G2Point {
+        G2Point {
+            x_a0: {{.G2.Gamma.X.A0.String}},
+            x_a1: {{.G2.Gamma.X.A1.String}},
+            y_a0: {{.G2.Gamma.Y.A0.String}},
+            y_a1: {{.G2.Gamma.Y.A1.String}},
+        }
+    }
+
+    fn delta2() -> G2Point {
+        G2Point {
+            x_a0: {{.G2.Delta.X.A0.String}},
+            x_a1: {{.G2.Delta.X.A1.String}},
+            y_a0: {{.G2.Delta.Y.A0.String}},
+            y_a1: {{.G2.Delta.Y.A1.String}},
+        }
+    }
+
+    #[abi(embed_v0)]
+    impl Groth16VerifierImpl of super::IGroth16Verifier<ContractState> {
+        // Returns whether the proof is valid given the hardcoded verifying key
+        // above and the public inputs
+        fn verify_proof(
+            self: @ContractState, a: G1Point, b: G2Point, c: G1Point, input: Span<u256>,
+        ) -> bool {
+            assert(input.len() == {{sub $lenK 1}}, 'verifier-bad-input-length');
+
+            // Make sure that a, b, and c are each less than the prime q
+            assert(a.x < PRIME_Q && a.y < PRIME_Q, 'verifier-a-gte-prime-q');
+            assert(b.x_a0 < PRIME_Q && b.x_a1 < PRIME_Q, 'verifier-bX-gte-prime-q');
+            assert(b.y_a0 < PRIME_Q && b.y_a1 < PRIME_Q, 'verifier-bY-gte-prime-q');
+            assert(c.x < PRIME_Q && c.y < PRIME_Q, 'verifier-c-gte-prime-q');
+
+            // Make sure that every input is less than the snark scalar field
+            let mut i = 0;
+            while i < input.len() {
+                assert(*input.at(i) < SNARK_SCALAR_FIELD, 'verifier-gte-snark-scalar-field');
+                i += 1;
+            };
+
+            let bn254 = IBN254Dispatcher { contract_address: self.bn254.read() };
+
+            // Compute the linear combination vk_x
+            {{- $k0 := index .G1.K 0}}
+            let mut vk_x = G1Point { x: {{$k0.X.String}}, y: {{$k0.Y.String}} }; // vk.K[0]
+            {{- if eq (len .G1.K) 1}}
+            // no public input, vk_x == vk.K[0]
+            {{- end}}
+            {{- range $i, $ki := .G1.K }}
+                {{- if gt $i 0 -}}
+                    {{- $j := sub $i 1 }}
+            let k = G1Point { x: {{$ki.X.String}}, y: {{$ki.Y.String}} }; // vk.K[{{$i}}]
+            vk_x = bn254.ec_add(vk_x, bn254.ec_mul(k, *input.at({{$j}}))); // vk_x += vk.K[{{$i}}] * input[{{$j}}]
+                {{- end -}}
+            {{- end }}
+
+            // e(-a, b)⋅e(α, β)⋅e(vk_x, γ)⋅e(c, δ) == 1
+            let neg_a_y = if a.y == 0 { 0 } else { PRIME_Q - a.y };
+            let neg_a = G1Point { x: a.x, y: neg_a_y };
+            bn254.pairing_check(
+                array![neg_a, alpha1(), vk_x, c].span(),
+                array![b, beta2(), gamma2(), delta2()].span(),
+            )
+        }
+    }
+}
+`
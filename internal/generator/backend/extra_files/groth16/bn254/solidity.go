@@ -27,7 +27,7 @@ const solidityTemplate = `
 
 // 2019 OKIMS
 
-pragma solidity ^0.8.0;
+pragma solidity {{pragma}};
 
 library Pairing {
 
@@ -79,7 +79,7 @@ library Pairing {
             switch success case 0 { invalid() }
         }
 
-        require(success,"pairing-add-failed");
+        {{require "success" "PairingAddFailed" "pairing-add-failed"}}
     }
 
 
@@ -97,7 +97,7 @@ library Pairing {
             switch success case 0 {invalid()}
         }
 
-        require(success, "pairing-add-failed");
+        {{require "success" "PairingAddFailed" "pairing-add-failed"}}
     }
 
     /*
@@ -118,7 +118,7 @@ library Pairing {
             // Use "invalid" to make gas estimation work
             switch success case 0 { invalid() }
         }
-        require (success,"pairing-mul-failed");
+        {{require "success" "PairingMulFailed" "pairing-mul-failed"}}
     }
 
 
@@ -135,7 +135,7 @@ library Pairing {
             // Use "invalid" to make gas estimation work
             switch success case 0 {invalid()}
         }
-        require(success, "pairing-mul-failed");
+        {{require "success" "PairingMulFailed" "pairing-mul-failed"}}
     }
 
     /* @return The result of computing the pairing check
@@ -179,13 +179,22 @@ library Pairing {
             switch success case 0 { invalid() }
         }
 
-        require(success,"pairing-opcode-failed");
+        {{require "success" "PairingOpcodeFailed" "pairing-opcode-failed"}}
 
         return out[0] != 0;
     }
 }
 
-contract Verifier {
+{{- with hasherLibrary}}
+
+{{.}}
+{{- end}}
+{{- if iverifier}}
+
+{{iverifierInterface}}
+{{- end}}
+
+{{contract "Verifier"}} {
 
     using Pairing for *;
 
@@ -235,6 +244,19 @@ contract Verifier {
         Pairing.plus_raw(buffer, q);
     }
 
+{{- if hasher}}
+
+    // hashPublicInputs returns the hash of the public inputs of the circuit,
+    // which is the public input of the verifying key
+    function hashPublicInputs(uint256[{{nbInputs}}] calldata input) internal pure returns (uint256) {
+        uint256[] memory inputs = new uint256[](input.length);
+        for (uint256 i = 0; i < input.length; i++) {
+            inputs[i] = input[i];
+        }
+        return {{hasher}}.hash(inputs);
+    }
+{{- end}}
+
     /*
      * @returns Whether the proof is valid given the hardcoded verifying key
      *          above and the public inputs
@@ -243,7 +265,7 @@ contract Verifier {
         uint256[2] memory a,
         uint256[2][2] memory b,
         uint256[2] memory c,
-        uint256[{{sub $lenK 1}}] calldata input
+        uint256[{{nbInputs}}] calldata input
     ) public view returns (bool r) {
 
         Proof memory proof;
@@ -252,21 +274,21 @@ contract Verifier {
         proof.C = Pairing.G1Point(c[0], c[1]);
 
         // Make sure that proof.A, B, and C are each less than the prime q
-        require(proof.A.X < PRIME_Q, "verifier-aX-gte-prime-q");
-        require(proof.A.Y < PRIME_Q, "verifier-aY-gte-prime-q");
+        {{require "proof.A.X < PRIME_Q" "ProofInvalid" "verifier-aX-gte-prime-q"}}
+        {{require "proof.A.Y < PRIME_Q" "ProofInvalid" "verifier-aY-gte-prime-q"}}
 
-        require(proof.B.X[0] < PRIME_Q, "verifier-bX0-gte-prime-q");
-        require(proof.B.Y[0] < PRIME_Q, "verifier-bY0-gte-prime-q");
+        {{require "proof.B.X[0] < PRIME_Q" "ProofInvalid" "verifier-bX0-gte-prime-q"}}
+        {{require "proof.B.Y[0] < PRIME_Q" "ProofInvalid" "verifier-bY0-gte-prime-q"}}
 
-        require(proof.B.X[1] < PRIME_Q, "verifier-bX1-gte-prime-q");
-        require(proof.B.Y[1] < PRIME_Q, "verifier-bY1-gte-prime-q");
+        {{require "proof.B.X[1] < PRIME_Q" "ProofInvalid" "verifier-bX1-gte-prime-q"}}
+        {{require "proof.B.Y[1] < PRIME_Q" "ProofInvalid" "verifier-bY1-gte-prime-q"}}
 
-        require(proof.C.X < PRIME_Q, "verifier-cX-gte-prime-q");
-        require(proof.C.Y < PRIME_Q, "verifier-cY-gte-prime-q");
+        {{require "proof.C.X < PRIME_Q" "ProofInvalid" "verifier-cX-gte-prime-q"}}
+        {{require "proof.C.Y < PRIME_Q" "ProofInvalid" "verifier-cY-gte-prime-q"}}
 
         // Make sure that every input is less than the snark scalar field
         for (uint256 i = 0; i < input.length; i++) {
-            require(input[i] < SNARK_SCALAR_FIELD,"verifier-gte-snark-scalar-field");
+            {{require "input[i] < SNARK_SCALAR_FIELD" "PublicInputNotInField" "verifier-gte-snark-scalar-field"}}
         }
 
         VerifyingKey memory vk = verifyingKey();
@@ -298,7 +320,7 @@ contract Verifier {
                 {{- $j := sub $i 1 }}
         mul_input[0] = uint256({{$ki.X.String}}); // vk.K[{{$i}}].X
         mul_input[1] = uint256({{$ki.Y.String}}); // vk.K[{{$i}}].Y
-        mul_input[2] = input[{{$j}}];
+        mul_input[2] = {{if hasher}}hashPublicInputs(input){{else}}input[{{$j}}]{{end}};
         accumulate(mul_input, q, add_input, vk_x); // vk_x += vk.K[{{$i}}] * input[{{$j}}]
             {{- end -}}
         {{- end }}
@@ -314,5 +336,26 @@ contract Verifier {
             vk.delta2
         );
     }
+{{- if iverifier}}
+
+    /*
+     * @notice IVerifier implementation, proof is abi.encode(a, b, c)
+     * @returns Whether the proof is valid given the hardcoded verifying key
+     *          above and the public inputs
+     */
+    function verify(bytes calldata proof, uint256[] calldata publicInputs) external view override returns (bool) {
+        {{require (printf "publicInputs.length == %d" nbInputs) "PublicInputsLengthMismatch" "verifier-bad-input-length"}}
+        (uint256[2] memory a, uint256[2][2] memory b, uint256[2] memory c) = abi.decode(proof, (uint256[2], uint256[2][2], uint256[2]));
+        uint256[{{nbInputs}}] memory input;
+        for (uint256 i = 0; i < input.length; i++) {
+            input[i] = publicInputs[i];
+        }
+        return this.verifyProof(a, b, c, input);
+    }
+{{- end}}
 }
+{{- with errors}}
+
+{{.}}
+{{- end}}
 `
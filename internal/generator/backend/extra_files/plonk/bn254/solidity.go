@@ -7,7 +7,7 @@ const solidityTemplate = `
 // It has not been audited and is provided as-is, we make no guarantees or warranties to its safety and reliability. 
 // 
 // According to https://eprint.iacr.org/archive/2019/953/1585767119.pdf
-pragma solidity ^0.8.0;
+pragma solidity {{pragma}};
 pragma experimental ABIEncoderV2;
 
 library PairingsBn254 {
@@ -25,7 +25,7 @@ library PairingsBn254 {
     }
 
     function new_fr(uint256 fr) internal pure returns (Fr memory) {
-        require(fr < r_mod);
+        {{require "fr < r_mod" "FrNotInField" ""}}
         return Fr({value: fr});
     }
 
@@ -38,7 +38,7 @@ library PairingsBn254 {
     }
 
     function inverse(Fr memory fr) internal view returns (Fr memory) {
-        require(fr.value != 0);
+        {{require "fr.value != 0" "InverseOfZero" ""}}
         return pow(fr, r_mod-2);
     }
 
@@ -61,7 +61,7 @@ library PairingsBn254 {
         assembly {
             success := staticcall(gas(), 0x05, input, 0xc0, result, 0x20)
         }
-        require(success);
+        {{require "success" "PrecompileFailed" ""}}
         return Fr({value: result[0]});
     }
 
@@ -86,14 +86,14 @@ library PairingsBn254 {
         }
 
         // check encoding
-        require(x < q_mod);
-        require(y < q_mod);
+        {{require "x < q_mod" "PointNotInField" ""}}
+        {{require "y < q_mod" "PointNotInField" ""}}
         // check on curve
         uint256 lhs = mulmod(y, y, q_mod); // y^2
         uint256 rhs = mulmod(x, x, q_mod); // x^2
         rhs = mulmod(rhs, x, q_mod); // x^3
         rhs = addmod(rhs, bn254_b_coeff, q_mod); // x^3 + b
-        require(lhs == rhs);
+        {{require "lhs == rhs" "PointNotOnCurve" ""}}
 
         return G1Point(x, y);
     }
@@ -121,7 +121,7 @@ library PairingsBn254 {
     function negate(G1Point memory self) internal pure {
         // The prime q in the base field F_q for G1
         if (self.Y == 0) {
-            require(self.X == 0);
+            {{require "self.X == 0" "InvalidPoint" ""}}
             return;
         }
 
@@ -166,7 +166,7 @@ library PairingsBn254 {
             assembly {
                 success := staticcall(gas(), 6, input, 0x80, dest, 0x40)
             }
-            require(success);
+            {{require "success" "PrecompileFailed" ""}}
         }
     }
 
@@ -201,7 +201,7 @@ library PairingsBn254 {
             assembly {
                 success := staticcall(gas(), 6, input, 0x80, dest, 0x40)
             }
-            require(success);
+            {{require "success" "PrecompileFailed" ""}}
         }
     }
 
@@ -229,13 +229,13 @@ library PairingsBn254 {
         assembly {
             success := staticcall(gas(), 7, input, 0x60, dest, 0x40)
         }
-        require(success);
+        {{require "success" "PrecompileFailed" ""}}
     }
 
     function pairing(G1Point[] memory p1, G2Point[] memory p2)
     internal view returns (bool)
     {
-        require(p1.length == p2.length);
+        {{require "p1.length == p2.length" "PairingLengthMismatch" ""}}
         uint elements = p1.length;
         uint inputSize = elements * 6;
         uint[] memory input = new uint[](inputSize);
@@ -253,7 +253,7 @@ library PairingsBn254 {
         assembly {
             success := staticcall(gas(), 8, add(input, 0x20), mul(inputSize, 0x20), out, 0x20)
         }
-        require(success);
+        {{require "success" "PrecompileFailed" ""}}
         return out[0] != 0;
     }
 
@@ -376,8 +376,8 @@ contract PlonkVerifier {
         Proof memory proof,
         VerificationKey memory vk) internal view returns (bool) {
 
-        require(proof.input_values.length == vk.num_inputs, "not match");
-        require(vk.num_inputs >= 1, "inv input");
+        {{require "proof.input_values.length == vk.num_inputs" "PublicInputsLengthMismatch" "not match"}}
+        {{require "vk.num_inputs >= 1" "NoPublicInput" "inv input"}}
         
         TranscriptLibrary.Transcript memory t = TranscriptLibrary.new_transcript();
         t.set_challenge_name("gamma");
@@ -628,7 +628,7 @@ contract PlonkVerifier {
         PairingsBn254.Fr memory vanishing_at_zeta = at.pow(domain_size);
         vanishing_at_zeta.sub_assign(one);
         // we can not have random point z be in domain
-        require(vanishing_at_zeta.value != 0);
+        {{require "vanishing_at_zeta.value != 0" "ZetaInDomain" ""}}
         PairingsBn254.Fr[] memory nums = new PairingsBn254.Fr[](poly_nums.length);
         PairingsBn254.Fr[] memory dens = new PairingsBn254.Fr[](poly_nums.length);
         // numerators in a form omega^i * (z^n - 1)
@@ -675,7 +675,7 @@ contract PlonkVerifier {
         VerificationKey memory vk
     ) internal view returns (bool) {
         PairingsBn254.Fr memory lhs = evaluate_vanishing(vk.domain_size, state.zeta);
-        require(lhs.value != 0); // we can not check a polynomial relationship if point z is in the domain
+        {{require "lhs.value != 0" "ZetaInDomain" ""}} // we can not check a polynomial relationship if point z is in the domain
         lhs.mul_assign(proof.quotient_polynomial_at_zeta);
 
         PairingsBn254.Fr memory quotient_challenge = PairingsBn254.new_fr(1);
@@ -754,8 +754,19 @@ contract PlonkVerifier {
     }
 }
 
-contract KeyedPlonkVerifier is PlonkVerifier {
+{{with hasherLibrary -}}
+{{.}}
+
+{{end -}}
+{{if iverifier -}}
+{{iverifierInterface}}
+
+{{end -}}
+contract {{contractName "KeyedPlonkVerifier"}} is PlonkVerifier{{if iverifier}}, IVerifier{{end}} {
     uint256 constant SERIALIZED_PROOF_LENGTH = 26;
+{{- if hasher}}
+    uint256 constant SNARK_SCALAR_FIELD = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+{{- end}}
 	using PairingsBn254 for PairingsBn254.Fr;
     function get_verification_key() internal pure returns(VerificationKey memory vk) {
         vk.domain_size = {{.Size}};
@@ -816,7 +827,7 @@ contract KeyedPlonkVerifier is PlonkVerifier {
         uint256[] memory public_inputs,
         uint256[] memory serialized_proof
     ) internal pure returns(Proof memory proof) {
-        require(serialized_proof.length == SERIALIZED_PROOF_LENGTH);
+        {{require "serialized_proof.length == SERIALIZED_PROOF_LENGTH" "InvalidProofLength" ""}}
         proof.input_values = new uint256[](public_inputs.length);
         for (uint256 i = 0; i < public_inputs.length; i++) {
             proof.input_values[i] = public_inputs[i];
@@ -898,10 +909,35 @@ contract KeyedPlonkVerifier is PlonkVerifier {
         uint256[] memory serialized_proof
     ) public view returns (bool) {
         VerificationKey memory vk = get_verification_key();
-        require(vk.num_inputs == public_inputs.length);
+{{- if hasher}}
+
+        // the public input of the verifying key is the hash of the public inputs
+        {{require (printf "public_inputs.length == %d" nbInputs) "PublicInputsLengthMismatch" ""}}
+        for (uint256 i = 0; i < public_inputs.length; i++) {
+            {{require "public_inputs[i] < SNARK_SCALAR_FIELD" "PublicInputNotInField" ""}}
+        }
+        uint256[] memory hashed_inputs = new uint256[](1);
+        hashed_inputs[0] = {{hasher}}.hash(public_inputs);
+        public_inputs = hashed_inputs;
+{{- end}}
+        {{require "vk.num_inputs == public_inputs.length" "PublicInputsLengthMismatch" ""}}
         Proof memory proof = deserialize_proof(public_inputs, serialized_proof);
         bool valid = verify(proof, vk);
         return valid;
     }
+{{- if iverifier}}
+
+    /*
+     * @notice IVerifier implementation, proof is abi.encode(serialized_proof)
+     */
+    function verify(bytes calldata proof, uint256[] calldata publicInputs) external view override returns (bool) {
+        uint256[] memory serialized_proof = abi.decode(proof, (uint256[]));
+        return verify_serialized_proof(publicInputs, serialized_proof);
+    }
+{{- end}}
 }
+{{- with errors}}
+
+{{.}}
+{{- end}}
 `
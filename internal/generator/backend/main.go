@@ -179,11 +179,21 @@ func main() {
 
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16Dir, "verify.go"), Templates: []string{"groth16/groth16.verify.go.tmpl", importCurve}},
-				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}},
-				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
+				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
+				{File: filepath.Join(groth16Dir, "keys.go"), Templates: []string{"groth16/groth16.keys.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "shard.go"), Templates: []string{"groth16/groth16.shard.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
 				{File: filepath.Join(groth16Dir, "commitment.go"), Templates: []string{"groth16/groth16.commitment.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "snarkjs.go"), Templates: []string{"groth16/groth16.snarkjs.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "calldata.go"), Templates: []string{"groth16/groth16.calldata.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "json.go"), Templates: []string{"groth16/groth16.json.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "utils_test.go"), Templates: []string{"groth16/tests/groth16.utils.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
+			}
+			if d.Curve == "BN254" {
+				// the gas estimation follows the exported Solidity verifier
+				entries = append(entries, bavard.Entry{File: filepath.Join(groth16Dir, "gas.go"), Templates: []string{"groth16/groth16.gas.go.tmpl", importCurve}})
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
 				panic(err) // TODO handle
@@ -215,15 +225,29 @@ func main() {
 			// plonk
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkDir, "verify.go"), Templates: []string{"plonk/plonk.verify.go.tmpl", importCurve}},
-				{File: filepath.Join(plonkDir, "prove.go"), Templates: []string{"plonk/plonk.prove.go.tmpl", importCurve}},
-				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "prove.go"), Templates: []string{"plonk/plonk.prove.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
+				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}, BuildTag: "!gnark_verifier_only"},
+				{File: filepath.Join(plonkDir, "keys.go"), Templates: []string{"plonk/plonk.keys.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal.go"), Templates: []string{"plonk/plonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "calldata.go"), Templates: []string{"plonk/plonk.calldata.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "json.go"), Templates: []string{"plonk/plonk.json.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal_test.go"), Templates: []string{"plonk/tests/marshal.go.tmpl", importCurve}},
 			}
+			if d.Curve == "BN254" {
+				// the gas estimation follows the exported Solidity verifier
+				entries = append(entries, bavard.Entry{File: filepath.Join(plonkDir, "gas.go"), Templates: []string{"plonk/plonk.gas.go.tmpl", importCurve}})
+			}
 			if err := bgen.Generate(d, "plonk", "./template/zkpschemes/", entries...); err != nil {
 				panic(err)
 			}
 
+			entries = []bavard.Entry{
+				{File: filepath.Join(plonkDir, "commitment_test.go"), Templates: []string{"plonk/tests/commitment.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "plonk_test", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
 			os.Remove(filepath.Join(plonkDir, "plonk_test.go"))
 
 			// plonkfri
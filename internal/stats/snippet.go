@@ -59,19 +59,19 @@ func initSnippets() {
 		_ = bits.ToBinary(api, newVariable())
 	})
 	registerSnippet("math/bits.ToBinary/unconstrained", func(api frontend.API, newVariable func() frontend.Variable) {
-		_ = bits.ToBinary(api, newVariable(), bits.WithUnconstrainedOutputs())
+		_ = bits.ToBinary(api, newVariable(), bits.WithUnchecked())
 	})
 	registerSnippet("math/bits.ToTernary", func(api frontend.API, newVariable func() frontend.Variable) {
 		_ = bits.ToTernary(api, newVariable())
 	})
 	registerSnippet("math/bits.ToTernary/unconstrained", func(api frontend.API, newVariable func() frontend.Variable) {
-		_ = bits.ToTernary(api, newVariable(), bits.WithUnconstrainedOutputs())
+		_ = bits.ToTernary(api, newVariable(), bits.WithUnchecked())
 	})
 	registerSnippet("math/bits.ToNAF", func(api frontend.API, newVariable func() frontend.Variable) {
 		_ = bits.ToNAF(api, newVariable())
 	})
 	registerSnippet("math/bits.ToNAF/unconstrained", func(api frontend.API, newVariable func() frontend.Variable) {
-		_ = bits.ToNAF(api, newVariable(), bits.WithUnconstrainedOutputs())
+		_ = bits.ToNAF(api, newVariable(), bits.WithUnchecked())
 	})
 
 	registerSnippet("hash/mimc", func(api frontend.API, newVariable func() frontend.Variable) {
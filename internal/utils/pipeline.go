@@ -0,0 +1,35 @@
+package utils
+
+// Pipeline calls f on the values received from in, with up to depth calls running
+// concurrently, and sends their results on the returned channel in the order of in.
+// The returned channel is closed once in is closed and all the results are sent.
+func Pipeline[In, Out any](in <-chan In, depth int, f func(In) Out) <-chan Out {
+	if depth < 1 {
+		depth = 1
+	}
+
+	// the consumer holds one pending result and the buffer the others, which bounds
+	// the number of calls running to depth
+	pending := make(chan chan Out, depth-1)
+	out := make(chan Out)
+
+	go func() {
+		defer close(pending)
+		for v := range in {
+			res := make(chan Out, 1)
+			pending <- res
+			go func(v In) {
+				res <- f(v)
+			}(v)
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for res := range pending {
+			out <- <-res
+		}
+	}()
+
+	return out
+}
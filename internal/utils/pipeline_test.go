@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	assert := require.New(t)
+
+	const depth, n = 3, 20
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+	}()
+
+	var (
+		lock                sync.Mutex
+		running, maxRunning int
+	)
+	out := Pipeline(in, depth, func(i int) int {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		// the first calls are the slowest, the results must still be in order
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return i * i
+	})
+
+	i := 0
+	for r := range out {
+		assert.Equal(i*i, r)
+		i++
+	}
+	assert.Equal(n, i)
+	assert.LessOrEqual(maxRunning, depth)
+	assert.Greater(maxRunning, 1, "the calls should overlap")
+}
@@ -0,0 +1,68 @@
+package utils
+
+import "sync"
+
+// SlicePool is a pool of slices of T, by capacity. The zero value is ready to use.
+type SlicePool[T any] struct {
+	pools sync.Map // capacity -> *sync.Pool of *[]T
+}
+
+// Get returns a zeroed slice of length and capacity n, allocated if the pool holds
+// none.
+func (p *SlicePool[T]) Get(n int) []T {
+	if pool, ok := p.pools.Load(n); ok {
+		if s, ok := pool.(*sync.Pool).Get().(*[]T); ok {
+			var zero T
+			for i := range *s {
+				(*s)[i] = zero
+			}
+			return *s
+		}
+	}
+	return make([]T, n)
+}
+
+// Put puts s back in the pool, for a later Get of a slice of capacity cap(s).
+func (p *SlicePool[T]) Put(s []T) {
+	s = s[:cap(s)]
+	pool, _ := p.pools.LoadOrStore(len(s), new(sync.Pool))
+	pool.(*sync.Pool).Put(&s)
+}
+
+// Scratch allocates the scratch vectors of a computation, from a pool if it is
+// enabled, and puts them back in the pool on Release.
+type Scratch[T any] struct {
+	pool    *SlicePool[T]
+	vectors [][]T
+}
+
+// NewScratch returns a Scratch allocating from pool if enabled is set, with make
+// otherwise.
+func NewScratch[T any](pool *SlicePool[T], enabled bool) *Scratch[T] {
+	if !enabled {
+		pool = nil
+	}
+	return &Scratch[T]{pool: pool}
+}
+
+// Make returns a zeroed slice of length n and capacity c.
+func (s *Scratch[T]) Make(n, c int) []T {
+	if s.pool == nil {
+		return make([]T, n, c)
+	}
+	v := s.pool.Get(c)[:n]
+	s.vectors = append(s.vectors, v)
+	return v
+}
+
+// Release puts the slices returned by Make back in the pool. They must not be
+// used afterwards.
+func (s *Scratch[T]) Release() {
+	if s.pool == nil {
+		return
+	}
+	for _, v := range s.vectors {
+		s.pool.Put(v)
+	}
+	s.vectors = nil
+}
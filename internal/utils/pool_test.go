@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScratch(t *testing.T) {
+	assert := require.New(t)
+
+	var pool SlicePool[int]
+	scratch := NewScratch(&pool, true)
+	v := scratch.Make(3, 5)
+	assert.Len(v, 3)
+	assert.Equal(5, cap(v))
+	v[0], v[1], v[2] = 1, 2, 3
+	scratch.Release()
+
+	// reused vectors are zeroed
+	for i := 0; i < 10; i++ {
+		w := scratch.Make(5, 5)
+		assert.Equal([]int{0, 0, 0, 0, 0}, w)
+		w[4] = 1
+		scratch.Release()
+	}
+
+	// pooling disabled
+	scratch = NewScratch(&pool, false)
+	v = scratch.Make(2, 4)
+	assert.Len(v, 2)
+	assert.Equal(4, cap(v))
+	scratch.Release()
+}
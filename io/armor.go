@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Objects (typically proofs and verifying keys) may also be written as text, armored
+// like PEM (RFC 7468) blocks:
+//
+//	-----BEGIN GNARK GROTH16 PROOF-----
+//	Curve: bn254
+//	Version: 0.8.0
+//
+//	<base64 encoding of the object, as written by WriteTo>
+//	-----END GNARK GROTH16 PROOF-----
+//
+// so that they can be pasted in configuration files, emails or JSON strings. The
+// armor headers are informative only: the binary header of the object is checked
+// when reading it. ReadWithHeader detects armored objects, so that ReadFrom reads
+// both encodings.
+
+const armorLabel = "GNARK "
+
+var (
+	armorBegin = []byte("-----BEGIN " + armorLabel)
+	armorEnd   = []byte("-----END ")
+)
+
+// ErrInvalidArmor is returned when reading a malformed armored object.
+var ErrInvalidArmor = errors.New("invalid armored object")
+
+// WriteArmored writes v, as written by its WriteTo method, to w in armored text form.
+// It returns the number of bytes written. Only objects written with a header (see
+// WriteWithHeader) can be armored.
+func WriteArmored(w io.Writer, v io.WriterTo) (int64, error) {
+	var buf bytes.Buffer
+	if _, err := v.WriteTo(&buf); err != nil {
+		return 0, err
+	}
+	data := buf.Bytes()
+	if len(data) < headerSize || !bytes.Equal(data[:len(headerMagic)], headerMagic[:]) {
+		return 0, errors.New("only objects written with a header can be armored")
+	}
+	var h Header
+	h.decode(data)
+
+	block := pem.Block{
+		Type: armorType(h),
+		Headers: map[string]string{
+			"Curve":   h.Curve.String(),
+			"Version": h.Version.String(),
+		},
+		Bytes: data,
+	}
+	cw := countingWriter{w: w}
+	err := pem.Encode(&cw, &block)
+	return cw.n, err
+}
+
+// armorType returns the type of the armored block, for instance "GNARK PLONK VERIFYING KEY"
+func armorType(h Header) string {
+	return armorLabel + strings.ToUpper(h.Backend.String()+" "+h.Object.String())
+}
+
+// readArmored reads the armored block starting with prefix from r, up to the end of
+// its last line. It returns the decoded object and the number of bytes read,
+// including prefix.
+func readArmored(r io.ByteReader, prefix []byte) ([]byte, int64, error) {
+	armored := append([]byte{}, prefix...)
+	lineStart := 0
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF && bytes.HasPrefix(armored[lineStart:], armorEnd) {
+			break
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = ErrInvalidArmor
+			}
+			return nil, int64(len(armored)), err
+		}
+		armored = append(armored, b)
+		if b != '\n' {
+			continue
+		}
+		if bytes.HasPrefix(armored[lineStart:], armorEnd) {
+			break
+		}
+		lineStart = len(armored)
+	}
+
+	block, _ := pem.Decode(armored)
+	if block == nil || !strings.HasPrefix(block.Type, armorLabel) {
+		return nil, int64(len(armored)), ErrInvalidArmor
+	}
+	return block.Bytes, int64(len(armored)), nil
+}
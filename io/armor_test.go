@@ -0,0 +1,75 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+type writerToFunc func(w io.Writer) (int64, error)
+
+func (f writerToFunc) WriteTo(w io.Writer) (int64, error) { return f(w) }
+
+func TestArmor(t *testing.T) {
+	assert := require.New(t)
+
+	header := NewHeader(VerifyingKey, ecc.BN254, backend.PLONK)
+	payload := bytes.Repeat([]byte("payload"), 20)
+	object := writerToFunc(func(w io.Writer) (int64, error) {
+		return WriteWithHeader(w, header, func(w io.Writer) error {
+			_, err := w.Write(payload)
+			return err
+		})
+	})
+	read := func(r io.Reader) error {
+		buf := make([]byte, len(payload))
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		assert.Equal(payload, buf)
+		return nil
+	}
+
+	var buf bytes.Buffer
+	written, err := WriteArmored(&buf, object)
+	assert.NoError(err)
+	assert.Equal(int64(buf.Len()), written)
+	armored := buf.String()
+	assert.True(strings.HasPrefix(armored, "-----BEGIN GNARK PLONK VERIFYING KEY-----\n"))
+	assert.Contains(armored, "Curve: bn254\n")
+	assert.True(strings.HasSuffix(armored, "-----END GNARK PLONK VERIFYING KEY-----\n"))
+
+	// round trip, followed by other data
+	r := strings.NewReader(armored + "trailing data")
+	nRead, err := ReadWithHeader(r, header, read)
+	assert.NoError(err)
+	assert.Equal(written, nRead)
+	rest, err := io.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal("trailing data", string(rest))
+
+	// the binary header is checked
+	_, err = ReadWithHeader(strings.NewReader(armored), NewHeader(Proof, ecc.BN254, backend.PLONK), read)
+	assert.Error(err)
+
+	// truncated
+	_, err = ReadWithHeader(strings.NewReader(armored[:len(armored)/2]), header, read)
+	assert.ErrorIs(err, ErrInvalidArmor)
+
+	// corrupted
+	corrupted := strings.Replace(armored, "\n", "\n!", 4)
+	_, err = ReadWithHeader(strings.NewReader(corrupted), header, read)
+	assert.ErrorIs(err, ErrInvalidArmor)
+
+	// objects without header can't be armored
+	_, err = WriteArmored(&buf, writerToFunc(func(w io.Writer) (int64, error) {
+		n, err := w.Write(payload)
+		return int64(n), err
+	}))
+	assert.Error(err)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic starts every Zstandard frame
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// CompressOption configures WriteCompressedTo.
+type CompressOption func(*CompressConfig) error
+
+// CompressConfig is the configuration of WriteCompressedTo.
+type CompressConfig struct {
+	Level       zstd.EncoderLevel
+	Concurrency int
+}
+
+// WithCompressionLevel sets the zstd compression level, from 1 (fastest) to 22
+// (best compression). Levels are mapped to the closest level supported by the
+// encoder, see zstd.EncoderLevelFromZstd. The default is 3.
+func WithCompressionLevel(level int) CompressOption {
+	return func(cfg *CompressConfig) error {
+		if level < 1 || level > 22 {
+			return fmt.Errorf("invalid compression level %d", level)
+		}
+		cfg.Level = zstd.EncoderLevelFromZstd(level)
+		return nil
+	}
+}
+
+// WithCompressionConcurrency sets the number of goroutines compressing
+// concurrently. The default is GOMAXPROCS.
+func WithCompressionConcurrency(n int) CompressOption {
+	return func(cfg *CompressConfig) error {
+		if n < 1 {
+			return fmt.Errorf("invalid concurrency %d", n)
+		}
+		cfg.Concurrency = n
+		return nil
+	}
+}
+
+// WriteCompressedTo writes v to w, compressed with Zstandard. The object is
+// streamed through the encoder, so it is never held uncompressed in memory.
+// It returns the number of compressed bytes written.
+//
+// v is typically a constraint system, a proving key or a KZG SRS; anything
+// written with WriteTo can be compressed.
+func WriteCompressedTo(w io.Writer, v io.WriterTo, opts ...CompressOption) (int64, error) {
+	cfg := CompressConfig{Level: zstd.SpeedDefault}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return 0, err
+		}
+	}
+	encOpts := []zstd.EOption{zstd.WithEncoderLevel(cfg.Level)}
+	if cfg.Concurrency != 0 {
+		encOpts = append(encOpts, zstd.WithEncoderConcurrency(cfg.Concurrency))
+	}
+
+	cw := countingWriter{w: w}
+	enc, err := zstd.NewWriter(&cw, encOpts...)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := v.WriteTo(enc); err != nil {
+		enc.Close()
+		return cw.n, err
+	}
+	err = enc.Close()
+	return cw.n, err
+}
+
+// ReadCompressedFrom reads into v an object written by WriteCompressedTo.
+// Objects which are not compressed (as written by WriteTo) are read as is, so
+// that callers don't need to know how an artifact was stored.
+//
+// It returns the number of bytes read from r, which may be more than the size
+// of the compressed object since r is read ahead.
+func ReadCompressedFrom(r io.Reader, v io.ReaderFrom) (int64, error) {
+	cr := countingReader{r: r}
+	br := bufio.NewReader(&cr)
+
+	if magic, err := br.Peek(len(zstdMagic)); err != nil || !bytes.Equal(magic, zstdMagic) {
+		// not compressed
+		_, err := v.ReadFrom(br)
+		return cr.n, err
+	}
+
+	dec, err := zstd.NewReader(br)
+	if err != nil {
+		return cr.n, err
+	}
+	defer dec.Close()
+	_, err = v.ReadFrom(dec)
+	return cr.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,82 @@
+package io_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type compressCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *compressCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	return nil
+}
+
+type readerWriter interface {
+	io.ReaderFrom
+	io.WriterTo
+}
+
+func TestCompressed(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &compressCircuit{})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	sccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &compressCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(sccs)
+	assert.NoError(err)
+	ppk, _, err := plonk.Setup(sccs, srs)
+	assert.NoError(err)
+
+	check := func(written io.WriterTo, read, empty readerWriter, opts ...gnarkio.CompressOption) {
+		var raw, compressed bytes.Buffer
+		_, err := written.WriteTo(&raw)
+		assert.NoError(err)
+		n, err := gnarkio.WriteCompressedTo(&compressed, written, opts...)
+		assert.NoError(err)
+		assert.Equal(int64(compressed.Len()), n)
+
+		// compressed
+		_, err = gnarkio.ReadCompressedFrom(bytes.NewReader(compressed.Bytes()), read)
+		assert.NoError(err)
+		var reencoded bytes.Buffer
+		_, err = read.WriteTo(&reencoded)
+		assert.NoError(err)
+		assert.Equal(raw.Bytes(), reencoded.Bytes())
+
+		// not compressed
+		_, err = gnarkio.ReadCompressedFrom(bytes.NewReader(raw.Bytes()), empty)
+		assert.NoError(err)
+		reencoded.Reset()
+		_, err = empty.WriteTo(&reencoded)
+		assert.NoError(err)
+		assert.Equal(raw.Bytes(), reencoded.Bytes())
+	}
+
+	check(ccs, groth16.NewCS(ecc.BN254), groth16.NewCS(ecc.BN254))
+	check(pk, groth16.NewProvingKey(ecc.BN254), groth16.NewProvingKey(ecc.BN254), gnarkio.WithCompressionLevel(1))
+	check(ppk, plonk.NewProvingKey(ecc.BN254), plonk.NewProvingKey(ecc.BN254), gnarkio.WithCompressionConcurrency(1))
+	check(srs, &kzg.SRS{}, &kzg.SRS{})
+
+	_, err = gnarkio.WriteCompressedTo(&bytes.Buffer{}, ccs, gnarkio.WithCompressionLevel(0))
+	assert.Error(err)
+}
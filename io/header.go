@@ -0,0 +1,336 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+)
+
+// Serialized objects (constraint systems, keys, proofs and witnesses) are framed as
+//
+//	header | object | checksum
+//
+// where header is
+//
+//	magic [8]byte | formatVersion uint16 | object uint16 | major, minor, patch uint16 | curve uint16 | backend uint16
+//
+// and checksum is the CRC-32 (Castagnoli) of the header and the object. All integers
+// are big-endian encoded. Objects without header (written by earlier versions of gnark)
+// are still read, without any check.
+
+// ObjectType identifies the type of a serialized object
+type ObjectType uint16
+
+const (
+	UnknownObject ObjectType = iota
+	R1CS
+	SparseR1CS
+	ProvingKey
+	VerifyingKey
+	Proof
+	Witness
+)
+
+// String returns the string representation of an object type
+func (o ObjectType) String() string {
+	switch o {
+	case R1CS:
+		return "R1CS"
+	case SparseR1CS:
+		return "SparseR1CS"
+	case ProvingKey:
+		return "proving key"
+	case VerifyingKey:
+		return "verifying key"
+	case Proof:
+		return "proof"
+	case Witness:
+		return "witness"
+	default:
+		return "unknown object"
+	}
+}
+
+// Header describes a serialized object.
+type Header struct {
+	Object  ObjectType
+	Version semver.Version // version of gnark which wrote the object, without pre-release and build metadata
+	Curve   ecc.ID
+	Backend backend.ID
+}
+
+// NewHeader returns the header of an object written by this version of gnark.
+func NewHeader(object ObjectType, curve ecc.ID, b backend.ID) Header {
+	return Header{
+		Object:  object,
+		Version: semver.Version{Major: gnark.Version.Major, Minor: gnark.Version.Minor, Patch: gnark.Version.Patch},
+		Curve:   curve,
+		Backend: b,
+	}
+}
+
+var (
+	// ErrIncompatibleVersion is matched (see errors.Is) by the errors returned when reading an object
+	// written by an incompatible version of gnark.
+	ErrIncompatibleVersion = errors.New("incompatible gnark version")
+
+	// ErrChecksumMismatch is returned when reading a corrupted object.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// IncompatibleVersionError is returned when reading an object written by an incompatible version of gnark.
+type IncompatibleVersionError struct {
+	Object  ObjectType
+	Version semver.Version // version of gnark which wrote the object
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	return fmt.Sprintf("%s written by gnark %s can't be read by gnark %s", e.Object, e.Version, gnark.Version)
+}
+
+// Is reports whether target is ErrIncompatibleVersion
+func (e *IncompatibleVersionError) Is(target error) bool {
+	return target == ErrIncompatibleVersion
+}
+
+// headerFormatVersion is incremented when the layout of the header changes; the
+// magic and the format version itself are never moved.
+const headerFormatVersion = 1
+
+const (
+	headerSize   = 8 + 7*2
+	checksumSize = 4
+)
+
+var (
+	headerMagic = [8]byte{0x89, 'G', 'N', 'A', 'R', 'K', '\r', '\n'}
+	castagnoli  = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// WriteWithHeader writes the header h, the object written by payload and a checksum to w.
+// It returns the total number of bytes written.
+func WriteWithHeader(w io.Writer, h Header, payload func(io.Writer) error) (int64, error) {
+	cw := checksumWriter{w: w, h: crc32.New(castagnoli)}
+
+	var buf [headerSize]byte
+	h.encode(buf[:])
+	if _, err := cw.Write(buf[:]); err != nil {
+		return cw.n, err
+	}
+	if err := payload(&cw); err != nil {
+		return cw.n, err
+	}
+
+	var sum [checksumSize]byte
+	binary.BigEndian.PutUint32(sum[:], cw.h.Sum32())
+	m, err := w.Write(sum[:])
+	return cw.n + int64(m), err
+}
+
+// ReadWithHeader reads an object written by WriteWithHeader, or by WriteArmored; the header
+// must match expected (its Version is ignored) and payload must read exactly the object.
+// Objects written without header are passed to payload as is.
+//
+// payload is given an io.Reader which also implements io.ByteReader. ReadWithHeader doesn't
+// read past the object, so that other objects can be read from r afterwards; if r doesn't
+// implement io.ByteReader, the bytes read with ReadByte are read from r one at a time.
+func ReadWithHeader(r io.Reader, expected Header, payload func(io.Reader) error) (int64, error) {
+	var magic [len(headerMagic)]byte
+	m, err := io.ReadFull(r, magic[:])
+	if err == nil && bytes.Equal(magic[:], armorBegin[:len(magic)]) {
+		// maybe an armored object, see WriteArmored
+		prefix := make([]byte, len(armorBegin))
+		copy(prefix, magic[:])
+		m2, err := io.ReadFull(r, prefix[len(magic):])
+		if err == nil && bytes.Equal(prefix, armorBegin) {
+			data, n, err := readArmored(newChecksumReader(r), prefix)
+			if err != nil {
+				return n, err
+			}
+			_, err = ReadWithHeader(bytes.NewReader(data), expected, payload)
+			return n, err
+		}
+		// object written before headers were introduced
+		cr := newChecksumReader(io.MultiReader(bytes.NewReader(prefix[:len(magic)+m2]), r))
+		err = payload(cr)
+		return cr.n, err
+	}
+	if err != nil || !bytes.Equal(magic[:], headerMagic[:]) {
+		// object written before headers were introduced
+		cr := newChecksumReader(io.MultiReader(bytes.NewReader(magic[:m]), r))
+		err := payload(cr)
+		return cr.n, err
+	}
+
+	cr := newChecksumReader(r)
+	cr.h = crc32.New(castagnoli)
+	cr.h.Write(magic[:])
+	cr.n = int64(len(magic))
+
+	var buf [headerSize]byte
+	copy(buf[:], magic[:])
+	if _, err := io.ReadFull(cr, buf[len(magic):]); err != nil {
+		return cr.n, err
+	}
+	if err := expected.check(buf[:]); err != nil {
+		return cr.n, err
+	}
+	if err := payload(cr); err != nil {
+		return cr.n, err
+	}
+	checksum := cr.h.Sum32()
+
+	var sum [checksumSize]byte
+	m, err = io.ReadFull(r, sum[:])
+	n := cr.n + int64(m)
+	if err != nil {
+		return n, err
+	}
+	if binary.BigEndian.Uint32(sum[:]) != checksum {
+		return n, ErrChecksumMismatch
+	}
+	return n, nil
+}
+
+func (h *Header) encode(buf []byte) {
+	copy(buf, headerMagic[:])
+	fields := [7]uint16{
+		headerFormatVersion,
+		uint16(h.Object),
+		uint16(h.Version.Major),
+		uint16(h.Version.Minor),
+		uint16(h.Version.Patch),
+		uint16(h.Curve),
+		uint16(h.Backend),
+	}
+	for i, f := range fields {
+		binary.BigEndian.PutUint16(buf[8+2*i:], f)
+	}
+}
+
+// decode decodes the header in buf and returns its format version
+func (h *Header) decode(buf []byte) (formatVersion uint16) {
+	field := func(i int) uint16 {
+		return binary.BigEndian.Uint16(buf[8+2*i:])
+	}
+	h.Object = ObjectType(field(1))
+	h.Version = semver.Version{Major: uint64(field(2)), Minor: uint64(field(3)), Patch: uint64(field(4))}
+	h.Curve = ecc.ID(field(5))
+	h.Backend = backend.ID(field(6))
+	return field(0)
+}
+
+// check returns an error if the encoded header doesn't match the expected one
+func (h *Header) check(buf []byte) error {
+	var actual Header
+	formatVersion := actual.decode(buf)
+	object, version := actual.Object, actual.Version
+
+	if formatVersion != headerFormatVersion || !compatible(version, gnark.Version) {
+		return &IncompatibleVersionError{Object: object, Version: version}
+	}
+
+	if object != h.Object {
+		return fmt.Errorf("expected %s, got %s", h.Object, object)
+	}
+	if actual.Curve != h.Curve {
+		return fmt.Errorf("%s is defined over %s, expected %s", object, actual.Curve, h.Curve)
+	}
+	if actual.Backend != h.Backend {
+		return fmt.Errorf("%s was produced by %s, expected %s", object, actual.Backend, h.Backend)
+	}
+	return nil
+}
+
+// compatible returns true if the objects written by gnark v are read by gnark
+// reader. Following semantic versioning, formats may only change with major
+// releases, and with minor releases before 1.0.0: objects written by other patch
+// releases, older or newer, are read, and by other minor releases from 1.0.0 on.
+func compatible(v, reader semver.Version) bool {
+	if v.Major != reader.Major {
+		return false
+	}
+	return v.Major != 0 || v.Minor == reader.Minor
+}
+
+// checksumWriter counts and hashes the bytes written to w
+type checksumWriter struct {
+	w io.Writer
+	h hash.Hash32
+	n int64
+}
+
+func (cw *checksumWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.h.Write(p[:n])
+	cw.n += int64(n)
+	return n, err
+}
+
+// checksumReader counts and hashes (if h is set) the bytes read from r
+type checksumReader struct {
+	r  io.Reader
+	br io.ByteReader // r, if it implements io.ByteReader
+	h  hash.Hash32
+	n  int64
+	b  [1]byte
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	br, _ := r.(io.ByteReader)
+	return &checksumReader{r: r, br: br}
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	if cr.h != nil {
+		cr.h.Write(p[:n])
+	}
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *checksumReader) ReadByte() (byte, error) {
+	if cr.br == nil {
+		// reading one byte at a time, as buffering would read past the object
+		if _, err := io.ReadFull(cr, cr.b[:]); err != nil {
+			return 0, err
+		}
+		return cr.b[0], nil
+	}
+	b, err := cr.br.ReadByte()
+	if err != nil {
+		return b, err
+	}
+	if cr.h != nil {
+		cr.b[0] = b
+		cr.h.Write(cr.b[:])
+	}
+	cr.n++
+	return b, nil
+}
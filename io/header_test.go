@@ -0,0 +1,133 @@
+package io
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	assert := require.New(t)
+
+	header := NewHeader(Proof, ecc.BN254, backend.GROTH16)
+	payload := []byte("payload of the object")
+	write := func(w io.Writer) error {
+		_, err := w.Write(payload)
+		return err
+	}
+	read := func(r io.Reader) error {
+		buf := make([]byte, len(payload))
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		if !bytes.Equal(buf, payload) {
+			return errors.New("payload mismatch")
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	written, err := WriteWithHeader(&buf, header, write)
+	assert.NoError(err)
+	data := append([]byte{}, buf.Bytes()...)
+
+	// round trip
+	nRead, err := ReadWithHeader(bytes.NewReader(data), header, read)
+	assert.NoError(err)
+	assert.Equal(written, nRead)
+
+	// objects are read without reading past them, whether the reader buffers or not
+	for _, o := range [][]byte{data, payload} {
+		stream := append(append([]byte{}, o...), data...)
+		for _, r := range []io.Reader{bytes.NewReader(stream), onlyReader{bytes.NewReader(stream)}} {
+			nRead, err := ReadWithHeader(r, header, read)
+			assert.NoError(err)
+			assert.Equal(int64(len(o)), nRead)
+			nRead, err = ReadWithHeader(r, header, read)
+			assert.NoError(err)
+			assert.Equal(written, nRead)
+			_, err = r.Read(make([]byte, 1))
+			assert.Equal(io.EOF, err)
+		}
+	}
+
+	// objects without header
+	_, err = ReadWithHeader(bytes.NewReader(payload), header, read)
+	assert.NoError(err)
+
+	// other object
+	_, err = ReadWithHeader(bytes.NewReader(data), NewHeader(VerifyingKey, ecc.BN254, backend.GROTH16), read)
+	assert.Error(err)
+
+	// other curve
+	_, err = ReadWithHeader(bytes.NewReader(data), NewHeader(Proof, ecc.BLS12_381, backend.GROTH16), read)
+	assert.Error(err)
+
+	// other patch releases
+	for _, d := range []int{1, -1} {
+		other := header
+		other.Version.Patch = uint64(int(other.Version.Patch) + d)
+		buf.Reset()
+		_, err = WriteWithHeader(&buf, other, write)
+		assert.NoError(err)
+		_, err = ReadWithHeader(&buf, header, read)
+		assert.NoError(err, "version %s", other.Version)
+	}
+
+	// incompatible versions: gnark is at 0.x, where minor releases may change
+	// the formats
+	for _, d := range []struct{ major, minor int }{{1, 0}, {0, 1}, {0, -1}} {
+		other := header
+		other.Version.Major = uint64(int(other.Version.Major) + d.major)
+		other.Version.Minor = uint64(int(other.Version.Minor) + d.minor)
+		buf.Reset()
+		_, err = WriteWithHeader(&buf, other, write)
+		assert.NoError(err)
+		_, err = ReadWithHeader(&buf, header, read)
+		assert.ErrorIs(err, ErrIncompatibleVersion, "version %s", other.Version)
+		var versionErr *IncompatibleVersionError
+		assert.ErrorAs(err, &versionErr)
+		assert.Equal(other.Version, versionErr.Version)
+	}
+
+	// corrupted payload
+	corrupted := append([]byte{}, data...)
+	corrupted[headerSize] ^= 1
+	_, err = ReadWithHeader(bytes.NewReader(corrupted), NewHeader(Proof, ecc.BN254, backend.GROTH16), func(r io.Reader) error {
+		_, err := io.ReadFull(r, make([]byte, len(payload)))
+		return err
+	})
+	assert.ErrorIs(err, ErrChecksumMismatch)
+}
+
+func TestCompatible(t *testing.T) {
+	for _, c := range []struct {
+		v, reader  string
+		compatible bool
+	}{
+		{"0.8.1", "0.8.1", true},
+		{"0.8.0", "0.8.1", true},
+		{"0.8.2", "0.8.1", true},
+		{"0.7.1", "0.8.1", false},
+		{"0.9.0", "0.8.1", false},
+		{"1.2.3", "1.2.3", true},
+		{"1.1.0", "1.2.3", true},
+		{"1.3.0", "1.2.3", true},
+		{"0.8.1", "1.2.3", false},
+		{"2.0.0", "1.2.3", false},
+	} {
+		got := compatible(semver.MustParse(c.v), semver.MustParse(c.reader))
+		require.Equal(t, c.compatible, got, "%s read by %s", c.v, c.reader)
+	}
+}
+
+// onlyReader hides the other methods of the wrapped reader, io.ByteReader in particular
+type onlyReader struct{ r io.Reader }
+
+func (o onlyReader) Read(p []byte) (int, error) { return o.r.Read(p) }
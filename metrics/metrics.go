@@ -0,0 +1,117 @@
+// Package metrics exposes Prometheus metrics on the proofs generated by gnark backends.
+//
+// The metrics are collected once registered with Register:
+//
+//	gnark_proofs_total{backend, curve}: number of proofs generated
+//	gnark_prove_duration_seconds{backend, curve, circuit}: prove duration, by circuit digest
+//	gnark_solver_errors_total{backend, curve}: number of witnesses the solver failed to solve
+//	gnark_memory_high_water_bytes: highest heap in use observed while proving
+//
+// Until then, the hooks called by the backends are no-ops.
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "gnark"
+
+var (
+	proofs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "proofs_total",
+		Help:      "Number of proofs generated.",
+	}, []string{"backend", "curve"})
+
+	proveDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "prove_duration_seconds",
+		Help:      "Duration of the proof generation, by circuit digest.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+	}, []string{"backend", "curve", "circuit"})
+
+	solverErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solver_errors_total",
+		Help:      "Number of witnesses the solver failed to solve.",
+	}, []string{"backend", "curve"})
+
+	memoryHighWater = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "memory_high_water_bytes",
+		Help:      "Highest heap in use observed while proving.",
+	})
+
+	collectors = []prometheus.Collector{proofs, proveDuration, solverErrors, memoryHighWater}
+
+	enabled       int32 // set to 1 when the collectors are registered
+	highWater     uint64
+	highWaterLock sync.Mutex
+)
+
+// Register registers the gnark collectors to r and enables the metrics.
+func Register(r prometheus.Registerer) error {
+	for i, c := range collectors {
+		if err := r.Register(c); err != nil {
+			for _, c := range collectors[:i] {
+				r.Unregister(c)
+			}
+			return err
+		}
+	}
+	atomic.StoreInt32(&enabled, 1)
+	return nil
+}
+
+// Unregister unregisters the gnark collectors from r and disables the metrics.
+func Unregister(r prometheus.Registerer) {
+	atomic.StoreInt32(&enabled, 0)
+	for _, c := range collectors {
+		r.Unregister(c)
+	}
+}
+
+// Enabled returns true if the collectors are registered.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// ObserveProof records a proof generated by backend on curve for the circuit with the
+// given digest, which took the given duration.
+func ObserveProof(backend, curve, circuit string, took time.Duration) {
+	if !Enabled() {
+		return
+	}
+	proofs.WithLabelValues(backend, curve).Inc()
+	proveDuration.WithLabelValues(backend, curve, circuit).Observe(took.Seconds())
+}
+
+// ObserveSolverError records a witness the solver failed to solve.
+func ObserveSolverError(backend, curve string) {
+	if !Enabled() {
+		return
+	}
+	solverErrors.WithLabelValues(backend, curve).Inc()
+}
+
+// ObserveMemory samples the heap in use and updates the memory high-water mark.
+// It stops the world (see runtime.ReadMemStats), so the backends call it only at
+// the end of the prover phases using most memory.
+func ObserveMemory() {
+	if !Enabled() {
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	highWaterLock.Lock()
+	defer highWaterLock.Unlock()
+	if m.HeapInuse > highWater {
+		highWater = m.HeapInuse
+		memoryHighWater.Set(float64(highWater))
+	}
+}
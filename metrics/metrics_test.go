@@ -0,0 +1,74 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/metrics"
+	"github.com/consensys/gnark/test"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, _, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	valid, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	invalid, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 36}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// not registered: nothing is recorded
+	_, err = plonk.Prove(ccs, pk, valid)
+	assert.NoError(err)
+
+	registry := prometheus.NewRegistry()
+	assert.NoError(metrics.Register(registry))
+	defer metrics.Unregister(registry)
+	assert.Error(metrics.Register(registry), "collectors registered twice")
+
+	_, err = plonk.Prove(ccs, pk, valid)
+	assert.NoError(err)
+	_, err = plonk.Prove(ccs, pk, invalid)
+	assert.Error(err)
+
+	families, err := registry.Gather()
+	assert.NoError(err)
+	gathered := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			switch {
+			case m.Counter != nil:
+				gathered[f.GetName()] += m.Counter.GetValue()
+			case m.Histogram != nil:
+				gathered[f.GetName()] += float64(m.Histogram.GetSampleCount())
+			case m.Gauge != nil:
+				gathered[f.GetName()] = m.Gauge.GetValue()
+			}
+		}
+	}
+	assert.Equal(1.0, gathered["gnark_proofs_total"])
+	assert.Equal(1.0, gathered["gnark_prove_duration_seconds"])
+	assert.Equal(1.0, gathered["gnark_solver_errors_total"])
+	assert.Greater(gathered["gnark_memory_high_water_bytes"], 0.0)
+}
@@ -0,0 +1,161 @@
+// Package mobile provides a flat API to solve and prove BN254 circuits from
+// their serialized constraint system and proving key, suitable for gomobile
+// bind on iOS and Android: its functions only take and return byte slices,
+// strings, integers, errors and the *Prover type.
+//
+// The circuits are compiled ahead of time (see the WriteTo methods of the
+// constraint systems, keys and witnesses). The hints used by a circuit must be
+// registered with solver.RegisterHint by the Go code bound with this package.
+package mobile
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// Prover proves a circuit, whose constraint system and proving key are
+// deserialized once.
+type Prover struct {
+	backend   backend.ID
+	ccs       constraint.ConstraintSystem
+	groth16PK groth16.ProvingKey
+	plonkPK   plonk.ProvingKey
+}
+
+// NewGroth16Prover returns a Groth16 Prover from the binary encodings of the
+// constraint system and the proving key. The proving key is read without the
+// subgroup checks of its points, it must come from a trusted source.
+func NewGroth16Prover(ccs, pk []byte) (*Prover, error) {
+	p := &Prover{
+		backend:   backend.GROTH16,
+		ccs:       groth16.NewCS(ecc.BN254),
+		groth16PK: groth16.NewProvingKey(ecc.BN254),
+	}
+	if _, err := p.ccs.ReadFrom(bytes.NewReader(ccs)); err != nil {
+		return nil, err
+	}
+	if _, err := p.groth16PK.UnsafeReadFrom(bytes.NewReader(pk)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewPLONKProver returns a PLONK Prover from the binary encodings of the
+// constraint system, the proving key and the KZG SRS.
+func NewPLONKProver(ccs, pk, srs []byte) (*Prover, error) {
+	p := &Prover{
+		backend: backend.PLONK,
+		ccs:     plonk.NewCS(ecc.BN254),
+		plonkPK: plonk.NewProvingKey(ecc.BN254),
+	}
+	if _, err := p.ccs.ReadFrom(bytes.NewReader(ccs)); err != nil {
+		return nil, err
+	}
+	if _, err := p.plonkPK.ReadFrom(bytes.NewReader(pk)); err != nil {
+		return nil, err
+	}
+	var _srs kzg.SRS
+	if _, err := _srs.ReadFrom(bytes.NewReader(srs)); err != nil {
+		return nil, err
+	}
+	if err := p.plonkPK.InitKZG(&_srs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NbPublicVariables returns the number of public variables of the circuit,
+// the constant wire excluded.
+func (p *Prover) NbPublicVariables() int {
+	return p.ccs.GetNbPublicVariables() - 1
+}
+
+// Solve returns nil if the binary full witness satisfies the constraint
+// system, and the error of the solver otherwise.
+func (p *Prover) Solve(fullWitness []byte) error {
+	w, err := readWitness(fullWitness)
+	if err != nil {
+		return err
+	}
+	_, err = p.ccs.Solve(w)
+	return err
+}
+
+// Prove solves the constraint system with the binary full witness and returns
+// the binary encoding of the proof.
+func (p *Prover) Prove(fullWitness []byte) ([]byte, error) {
+	w, err := readWitness(fullWitness)
+	if err != nil {
+		return nil, err
+	}
+
+	var proof io.WriterTo
+	switch p.backend {
+	case backend.GROTH16:
+		proof, err = groth16.Prove(p.ccs, p.groth16PK, w)
+	default:
+		proof, err = plonk.Prove(p.ccs, p.plonkPK, w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WitnessFromJSON returns the binary encoding of the witness assigned in JSON,
+// following the JSON encoded schema of the circuit (see frontend.NewSchema).
+// If the secret values are missing, the witness is the public one.
+func WitnessFromJSON(schemaJSON []byte, assignment string) ([]byte, error) {
+	var s schema.Schema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		return nil, err
+	}
+	w, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.FromJSON(&s, []byte(assignment)); err != nil {
+		return nil, err
+	}
+	return w.MarshalBinary()
+}
+
+// PublicWitness returns the binary encoding of the public part of the binary
+// full witness.
+func PublicWitness(fullWitness []byte) ([]byte, error) {
+	w, err := readWitness(fullWitness)
+	if err != nil {
+		return nil, err
+	}
+	public, err := w.Public()
+	if err != nil {
+		return nil, err
+	}
+	return public.MarshalBinary()
+}
+
+func readWitness(data []byte) (witness.Witness, error) {
+	w, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,97 @@
+package mobile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/mobile"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func serialize(t *testing.T, v io.WriterTo) []byte {
+	var buf bytes.Buffer
+	_, err := v.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestProve(t *testing.T) {
+	assert := require.New(t)
+
+	s, err := frontend.NewSchema(&squareCircuit{})
+	assert.NoError(err)
+	bSchema, err := json.Marshal(s)
+	assert.NoError(err)
+	fullWitness, err := mobile.WitnessFromJSON(bSchema, `{"X":3,"Y":9}`)
+	assert.NoError(err)
+	wrongWitness, err := mobile.WitnessFromJSON(bSchema, `{"X":3,"Y":10}`)
+	assert.NoError(err)
+	bPublic, err := mobile.PublicWitness(fullWitness)
+	assert.NoError(err)
+	publicWitness, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(publicWitness.UnmarshalBinary(bPublic))
+
+	t.Run("groth16", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+
+		prover, err := mobile.NewGroth16Prover(serialize(t, ccs), serialize(t, pk))
+		assert.NoError(err)
+		assert.Equal(1, prover.NbPublicVariables())
+		assert.NoError(prover.Solve(fullWitness))
+		assert.Error(prover.Solve(wrongWitness))
+
+		bProof, err := prover.Prove(fullWitness)
+		assert.NoError(err)
+		proof := groth16.NewProof(ecc.BN254)
+		_, err = proof.ReadFrom(bytes.NewReader(bProof))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness))
+	})
+
+	t.Run("plonk", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &squareCircuit{})
+		assert.NoError(err)
+		srs, err := test.NewKZGSRS(ccs)
+		assert.NoError(err)
+		pk, vk, err := plonk.Setup(ccs, srs)
+		assert.NoError(err)
+
+		prover, err := mobile.NewPLONKProver(serialize(t, ccs), serialize(t, pk), serialize(t, srs))
+		assert.NoError(err)
+		_, err = prover.Prove(wrongWitness)
+		assert.Error(err)
+
+		bProof, err := prover.Prove(fullWitness)
+		assert.NoError(err)
+		proof := plonk.NewProof(ecc.BN254)
+		_, err = proof.ReadFrom(bytes.NewReader(bProof))
+		assert.NoError(err)
+		assert.NoError(plonk.Verify(proof, vk, publicWitness))
+	})
+}
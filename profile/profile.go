@@ -21,8 +21,12 @@ import (
 var (
 	sessions       []*Profile // active sessions
 	activeSessions uint32
+	currentScope   atomic.Value // string, see SetScope
 )
 
+// scopeLabel is the label of the samples holding the scope of the constraint
+const scopeLabel = "scope"
+
 // Profile represents an active constraint system profiling session.
 type Profile struct {
 	// defaults to ./gnark.pprof
@@ -141,6 +145,46 @@ func (p *Profile) NbConstraints() int {
 	return len(p.pprof.Sample)
 }
 
+// NbConstraintsByCaller returns the number of collected constraints per function. A constraint
+// is attributed to the innermost function of its call stack which is not part of the gnark
+// frontend (builders and API implementations), typically a gadget or the circuit Define method.
+//
+// It must be called after Stop().
+func (p *Profile) NbConstraintsByCaller() map[string]int {
+	const frontendPrefix = "github.com/consensys/gnark/frontend"
+	res := make(map[string]int)
+	for _, s := range p.pprof.Sample {
+		if len(s.Location) == 0 {
+			continue
+		}
+		caller := s.Location[0].Line[0].Function.SystemName
+		for _, l := range s.Location {
+			if f := l.Line[0].Function.SystemName; !strings.HasPrefix(f, frontendPrefix) {
+				caller = f
+				break
+			}
+		}
+		res[caller] += int(s.Value[0])
+	}
+	return res
+}
+
+// NbConstraintsByScope returns the number of collected constraints per scope (see SetScope).
+// Constraints recorded outside any scope are counted with the empty scope.
+//
+// It must be called after Stop().
+func (p *Profile) NbConstraintsByScope() map[string]int {
+	res := make(map[string]int)
+	for _, s := range p.pprof.Sample {
+		var scope string
+		if l := s.Label[scopeLabel]; len(l) != 0 {
+			scope = l[0]
+		}
+		res[scope] += int(s.Value[0])
+	}
+	return res
+}
+
 // Top return a similar output than pprof top command
 func (p *Profile) Top() string {
 	r := report.NewDefault(&p.pprof, report.Options{
@@ -169,7 +213,14 @@ func RecordConstraint() {
 		return
 	}
 	pc = pc[:n]
-	chCommands <- command{pc: pc}
+	scope, _ := currentScope.Load().(string)
+	chCommands <- command{pc: pc, scope: scope}
+}
+
+// SetScope sets the scope (see frontend.Scoper) attached as a label to the
+// constraints recorded next.
+func SetScope(scope string) {
+	currentScope.Store(scope)
 }
 
 func (p *Profile) getLocation(frame *runtime.Frame) *profile.Location {
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// CircuitOption defines option for altering the registration of a circuit.
+type CircuitOption func(*Circuit) error
+
+// WithGroth16Keys sets the proving artifacts of a Groth16 circuit.
+func WithGroth16Keys(pk groth16.ProvingKey, vk groth16.VerifyingKey) CircuitOption {
+	return func(c *Circuit) error {
+		if c.Backend != backend.GROTH16 {
+			return errors.New("groth16 keys given for a circuit of another backend")
+		}
+		c.pk, c.vk = pk, vk
+		return nil
+	}
+}
+
+// WithPlonkKeys sets the proving artifacts of a PLONK circuit. The keys must
+// be initialized with the KZG SRS.
+func WithPlonkKeys(pk plonk.ProvingKey, vk plonk.VerifyingKey) CircuitOption {
+	return func(c *Circuit) error {
+		if c.Backend != backend.PLONK {
+			return errors.New("plonk keys given for a circuit of another backend")
+		}
+		c.pk, c.vk = pk, vk
+		return nil
+	}
+}
+
+// WithKZGSRS sets the KZG SRS used by the setup of a PLONK circuit.
+func WithKZGSRS(srs kzg.SRS) CircuitOption {
+	return func(c *Circuit) error {
+		if c.Backend != backend.PLONK {
+			return errors.New("a KZG SRS is only used by plonk")
+		}
+		c.srs = srs
+		return nil
+	}
+}
+
+// Circuit is a circuit of the registry of a Server.
+type Circuit struct {
+	ID      string
+	Backend backend.ID
+	Curve   ecc.ID
+
+	ccs constraint.ConstraintSystem
+	srs kzg.SRS
+
+	lock   sync.RWMutex
+	pk, vk any // groth16 or plonk keys, depending on Backend
+}
+
+// circuitID restricts the ids of the circuits to ones usable in paths and
+// metric labels as is.
+var circuitID = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+func newCircuit(id string, ccs constraint.ConstraintSystem, backendID backend.ID, opts ...CircuitOption) (*Circuit, error) {
+	if !circuitID.MatchString(id) {
+		return nil, fmt.Errorf("invalid circuit id %q", id)
+	}
+	switch backendID {
+	case backend.GROTH16:
+		if _, ok := ccs.(constraint.R1CS); !ok {
+			return nil, errors.New("groth16 requires a R1CS")
+		}
+	case backend.PLONK:
+		if _, ok := ccs.(constraint.SparseR1CS); !ok {
+			return nil, errors.New("plonk requires a SparseR1CS")
+		}
+	default:
+		return nil, fmt.Errorf("backend %s is not supported", backendID)
+	}
+
+	c := &Circuit{
+		ID:      id,
+		Backend: backendID,
+		Curve:   utils.FieldToCurve(ccs.Field()),
+		ccs:     ccs,
+	}
+	for _, option := range opts {
+		if err := option(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ConstraintSystem returns the constraint system of the circuit.
+func (c *Circuit) ConstraintSystem() constraint.ConstraintSystem {
+	return c.ccs
+}
+
+// Ready returns true if the circuit has proving artifacts.
+func (c *Circuit) Ready() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.pk != nil
+}
+
+// WriteVerifyingKey writes the verifying key of the circuit to w.
+func (c *Circuit) WriteVerifyingKey(w io.Writer) error {
+	c.lock.RLock()
+	vk, ok := c.vk.(io.WriterTo)
+	c.lock.RUnlock()
+	if !ok {
+		return ErrNotReady
+	}
+	_, err := vk.WriteTo(w)
+	return err
+}
+
+func (c *Circuit) setup() error {
+	var pk, vk any
+	var err error
+	switch c.Backend {
+	case backend.GROTH16:
+		pk, vk, err = groth16.Setup(c.ccs)
+	case backend.PLONK:
+		if c.srs == nil {
+			return errors.New("plonk setup requires a KZG SRS")
+		}
+		pk, vk, err = plonk.Setup(c.ccs, c.srs)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pk, c.vk = pk, vk
+	return nil
+}
+
+func (c *Circuit) prove(fullWitness witness.Witness) (io.WriterTo, error) {
+	c.lock.RLock()
+	pk := c.pk
+	c.lock.RUnlock()
+	if pk == nil {
+		return nil, ErrNotReady
+	}
+
+	switch c.Backend {
+	case backend.GROTH16:
+		return groth16.Prove(c.ccs, pk.(groth16.ProvingKey), fullWitness)
+	default:
+		return plonk.Prove(c.ccs, pk.(plonk.ProvingKey), fullWitness)
+	}
+}
+
+func (c *Circuit) verify(r io.Reader, publicWitness witness.Witness) error {
+	c.lock.RLock()
+	vk := c.vk
+	c.lock.RUnlock()
+	if vk == nil {
+		return ErrNotReady
+	}
+
+	switch c.Backend {
+	case backend.GROTH16:
+		proof, err := groth16.NewProofE(c.Curve)
+		if err != nil {
+			return err
+		}
+		if _, err := proof.ReadFrom(r); err != nil {
+			return err
+		}
+		return groth16.Verify(proof, vk.(groth16.VerifyingKey), publicWitness)
+	default:
+		proof, err := plonk.NewProofE(c.Curve)
+		if err != nil {
+			return err
+		}
+		if _, err := proof.ReadFrom(r); err != nil {
+			return err
+		}
+		return plonk.Verify(proof, vk.(plonk.VerifyingKey), publicWitness)
+	}
+}
+
+// newWitness returns an empty witness of the circuit, to be read.
+func (c *Circuit) newWitness() (witness.Witness, error) {
+	return witness.New(c.ccs.Field())
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/server/serverpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RegisterGRPC registers the gRPC API of the server (see serverpb.ProverServer)
+// to r, typically a *grpc.Server. It exposes the same methods as Handler, with
+// the witnesses streamed in the Prove requests.
+func (s *Server) RegisterGRPC(r grpc.ServiceRegistrar) {
+	serverpb.RegisterProverServer(r, &grpcServer{s: s})
+}
+
+type grpcServer struct {
+	serverpb.UnimplementedProverServer
+	s *Server
+}
+
+func (g *grpcServer) ListCircuits(context.Context, *serverpb.ListCircuitsRequest) (*serverpb.ListCircuitsResponse, error) {
+	circuits := g.s.Circuits()
+	res := &serverpb.ListCircuitsResponse{Circuits: make([]*serverpb.Circuit, len(circuits))}
+	for i, c := range circuits {
+		res.Circuits[i] = &serverpb.Circuit{
+			Id:            c.ID,
+			Backend:       c.Backend.String(),
+			Curve:         c.Curve.String(),
+			NbConstraints: uint64(c.ccs.GetNbConstraints()),
+			Ready:         c.Ready(),
+		}
+	}
+	return res, nil
+}
+
+func (g *grpcServer) Setup(_ context.Context, req *serverpb.SetupRequest) (*serverpb.SetupResponse, error) {
+	if err := g.s.Setup(req.Circuit); err != nil {
+		return nil, grpcError(err)
+	}
+	return &serverpb.SetupResponse{}, nil
+}
+
+func (g *grpcServer) GetVerifyingKey(_ context.Context, req *serverpb.GetVerifyingKeyRequest) (*serverpb.GetVerifyingKeyResponse, error) {
+	c, err := g.s.Circuit(req.Circuit)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteVerifyingKey(&buf); err != nil {
+		return nil, grpcError(err)
+	}
+	return &serverpb.GetVerifyingKeyResponse{VerifyingKey: buf.Bytes()}, nil
+}
+
+func (g *grpcServer) Prove(stream serverpb.Prover_ProveServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	c, err := g.s.Circuit(first.Circuit)
+	if err != nil {
+		return grpcError(err)
+	}
+	fullWitness, err := c.newWitness()
+	if err != nil {
+		return grpcError(err)
+	}
+	// the witness is decoded as it is uploaded
+	r := &witnessStream{stream: stream, chunk: first.Witness, remaining: g.s.cfg.MaxWitnessSize}
+	if _, err := fullWitness.ReadFrom(r); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid witness: %v", err)
+	}
+
+	job, err := g.s.Submit(c.ID, fullWitness)
+	if err != nil {
+		return grpcError(err)
+	}
+	if first.Wait {
+		// the error of the prover is reported in the job
+		_, _ = job.Wait(stream.Context())
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+	}
+	res, err := newGRPCJob(job)
+	if err != nil {
+		return grpcError(err)
+	}
+	return stream.SendAndClose(res)
+}
+
+func (g *grpcServer) GetJob(_ context.Context, req *serverpb.GetJobRequest) (*serverpb.Job, error) {
+	job, err := g.s.Job(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res, err := newGRPCJob(job)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return res, nil
+}
+
+func (g *grpcServer) Verify(_ context.Context, req *serverpb.VerifyRequest) (*serverpb.VerifyResponse, error) {
+	c, err := g.s.Circuit(req.Circuit)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	publicWitness, err := c.newWitness()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := publicWitness.UnmarshalBinary(req.PublicWitness); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public witness: %v", err)
+	}
+
+	if err := g.s.Verify(c.ID, bytes.NewReader(req.Proof), publicWitness); err != nil {
+		if errors.Is(err, ErrNotReady) {
+			return nil, grpcError(err)
+		}
+		return &serverpb.VerifyResponse{Error: err.Error()}, nil
+	}
+	return &serverpb.VerifyResponse{Valid: true}, nil
+}
+
+// newGRPCJob returns the job, with its proof if it is done.
+func newGRPCJob(job *Job) (*serverpb.Job, error) {
+	jobStatus, err := job.Status()
+	res := &serverpb.Job{
+		Id:      job.ID,
+		Circuit: job.Circuit,
+		Status:  string(jobStatus),
+		Created: timestamppb.New(job.Created),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	if proof := job.Proof(); proof != nil {
+		var buf bytes.Buffer
+		if _, err := proof.WriteTo(&buf); err != nil {
+			return nil, err
+		}
+		res.Proof = buf.Bytes()
+	}
+	return res, nil
+}
+
+// witnessStream reads the witness chunks of the Prove requests, up to remaining
+// bytes.
+type witnessStream struct {
+	stream    serverpb.Prover_ProveServer
+	chunk     []byte
+	remaining int64
+}
+
+func (r *witnessStream) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = req.Witness
+	}
+	if int64(len(r.chunk)) > r.remaining {
+		return 0, fmt.Errorf("witness larger than %d bytes", r.remaining)
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	r.remaining -= int64(n)
+	return n, nil
+}
+
+// grpcError returns err with the status code matching it.
+func grpcError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ErrUnknownCircuit), errors.Is(err, ErrUnknownJob):
+		code = codes.NotFound
+	case errors.Is(err, ErrNotReady):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
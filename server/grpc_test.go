@@ -0,0 +1,142 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/server"
+	"github.com/consensys/gnark/server/serverpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type largeCircuit struct {
+	X [64]frontend.Variable
+}
+
+func (c *largeCircuit) Define(api frontend.API) error {
+	return nil
+}
+
+func TestGRPC(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	s, err := server.New(server.WithMaxWitnessSize(1 << 10))
+	assert.NoError(err)
+	defer s.Close()
+	assert.NoError(s.Register("square", ccs, backend.GROTH16))
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	s.RegisterGRPC(srv)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(err)
+	defer conn.Close()
+	client := serverpb.NewProverClient(conn)
+	ctx := context.Background()
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	bWitness, err := w.MarshalBinary()
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	bPublic, err := public.MarshalBinary()
+	assert.NoError(err)
+
+	// prove streams the witness in chunks
+	prove := func(circuit string, wait bool, witness []byte) (*serverpb.Job, error) {
+		stream, err := client.Prove(ctx)
+		assert.NoError(err)
+		assert.NoError(stream.Send(&serverpb.ProveRequest{Circuit: circuit, Wait: wait}))
+		for len(witness) > 0 {
+			n := 16
+			if n > len(witness) {
+				n = len(witness)
+			}
+			assert.NoError(stream.Send(&serverpb.ProveRequest{Witness: witness[:n]}))
+			witness = witness[n:]
+		}
+		return stream.CloseAndRecv()
+	}
+
+	// proving requires the setup
+	_, err = prove("square", true, bWitness)
+	assert.Equal(codes.FailedPrecondition, status.Code(err))
+	_, err = client.Setup(ctx, &serverpb.SetupRequest{Circuit: "square"})
+	assert.NoError(err)
+	circuits, err := client.ListCircuits(ctx, &serverpb.ListCircuitsRequest{})
+	assert.NoError(err)
+	assert.Len(circuits.Circuits, 1)
+	assert.True(circuits.Circuits[0].Ready)
+	vk, err := client.GetVerifyingKey(ctx, &serverpb.GetVerifyingKeyRequest{Circuit: "square"})
+	assert.NoError(err)
+	assert.NotEmpty(vk.VerifyingKey)
+
+	// asynchronous proving
+	job, err := prove("square", false, bWitness)
+	assert.NoError(err)
+	j, err := s.Job(job.Id)
+	assert.NoError(err)
+	_, err = j.Wait(ctx)
+	assert.NoError(err)
+	job, err = client.GetJob(ctx, &serverpb.GetJobRequest{Id: job.Id})
+	assert.NoError(err)
+	assert.Equal("done", job.Status)
+	assert.NotEmpty(job.Proof)
+
+	verify := func(proof, public []byte) *serverpb.VerifyResponse {
+		res, err := client.Verify(ctx, &serverpb.VerifyRequest{Circuit: "square", Proof: proof, PublicWitness: public})
+		assert.NoError(err)
+		return res
+	}
+	assert.True(verify(job.Proof, bPublic).Valid)
+
+	// synchronous proving of an unsatisfied witness
+	w, err = frontend.NewWitness(&squareCircuit{X: 4, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	bOther, err := w.MarshalBinary()
+	assert.NoError(err)
+	job, err = prove("square", true, bOther)
+	assert.NoError(err)
+	assert.Equal("failed", job.Status)
+	assert.NotEmpty(job.Error)
+	assert.Empty(job.Proof)
+
+	res := verify(job.Proof, bPublic)
+	assert.False(res.Valid)
+	assert.NotEmpty(res.Error)
+
+	_, err = prove("unknown", true, bWitness)
+	assert.Equal(codes.NotFound, status.Code(err))
+	_, err = client.GetJob(ctx, &serverpb.GetJobRequest{Id: "unknown"})
+	assert.Equal(codes.NotFound, status.Code(err))
+
+	// the witness is larger than the limit
+	var large largeCircuit
+	for i := range large.X {
+		large.X[i] = i
+	}
+	w, err = frontend.NewWitness(&large, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	bLarge, err := w.MarshalBinary()
+	assert.NoError(err)
+	_, err = prove("square", true, bLarge)
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(status.Convert(err).Message(), "larger than")
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler returns the JSON/HTTP API of the server:
+//
+//	GET  /circuits              list of the registered circuits
+//	POST /circuits/{id}/setup   run the setup of the circuit
+//	GET  /circuits/{id}/vk      binary verifying key of the circuit
+//	POST /circuits/{id}/prove   queue a proving job, the body is the binary full witness;
+//	                            with ?wait=true, respond with the binary proof instead of the job
+//	POST /circuits/{id}/verify  verify a proof, the body is {"proof": base64, "publicWitness": base64}
+//	GET  /jobs/{id}             status of a proving job
+//	GET  /jobs/{id}/proof       binary proof of a finished job
+//	GET  /metrics               metrics of Config.Registry in the Prometheus text format
+//
+// The binary encodings are the ones of the WriteTo methods of the witnesses,
+// keys and proofs. Errors are responded as {"error": message}.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+type circuitResponse struct {
+	ID            string `json:"id"`
+	Backend       string `json:"backend"`
+	Curve         string `json:"curve"`
+	NbConstraints int    `json:"nbConstraints"`
+	Ready         bool   `json:"ready"`
+}
+
+type jobResponse struct {
+	ID      string    `json:"id"`
+	Circuit string    `json:"circuit"`
+	Status  JobStatus `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type verifyRequest struct {
+	Proof         []byte `json:"proof"`
+	PublicWitness []byte `json:"publicWitness"`
+}
+
+type verifyResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	route := func(method string, parts ...string) bool {
+		if r.Method != method || len(path) != len(parts) {
+			return false
+		}
+		for i := range parts {
+			if parts[i] != "*" && parts[i] != path[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case route(http.MethodGet, "metrics"):
+		promhttp.HandlerFor(s.cfg.Registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	case route(http.MethodGet, "circuits"):
+		circuits := s.Circuits()
+		res := make([]circuitResponse, len(circuits))
+		for i, c := range circuits {
+			res[i] = circuitResponse{
+				ID:            c.ID,
+				Backend:       c.Backend.String(),
+				Curve:         c.Curve.String(),
+				NbConstraints: c.ccs.GetNbConstraints(),
+				Ready:         c.Ready(),
+			}
+		}
+		writeJSON(w, http.StatusOK, res)
+	case route(http.MethodPost, "circuits", "*", "setup"):
+		if err := s.Setup(path[1]); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case route(http.MethodGet, "circuits", "*", "vk"):
+		c, err := s.Circuit(path[1])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var buf bytes.Buffer
+		if err := c.WriteVerifyingKey(&buf); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = buf.WriteTo(w)
+	case route(http.MethodPost, "circuits", "*", "prove"):
+		s.serveProve(w, r, path[1])
+	case route(http.MethodPost, "circuits", "*", "verify"):
+		s.serveVerify(w, r, path[1])
+	case route(http.MethodGet, "jobs", "*"):
+		job, err := s.Job(path[1])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newJobResponse(job))
+	case route(http.MethodGet, "jobs", "*", "proof"):
+		job, err := s.Job(path[1])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		proof := job.Proof()
+		if proof == nil {
+			writeJSON(w, http.StatusConflict, newJobResponse(job))
+			return
+		}
+		writeProof(w, proof)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func (s *Server) serveProve(w http.ResponseWriter, r *http.Request, id string) {
+	c, err := s.Circuit(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	fullWitness, err := c.newWitness()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// the witness is decoded as it is uploaded
+	if _, err := fullWitness.ReadFrom(http.MaxBytesReader(w, r.Body, s.cfg.MaxWitnessSize)); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid witness: " + err.Error()})
+		return
+	}
+
+	job, err := s.Submit(id, fullWitness)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if r.URL.Query().Get("wait") != "true" {
+		writeJSON(w, http.StatusAccepted, newJobResponse(job))
+		return
+	}
+	proof, err := job.Wait(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, newJobResponse(job))
+		return
+	}
+	writeProof(w, proof)
+}
+
+func (s *Server) serveVerify(w http.ResponseWriter, r *http.Request, id string) {
+	c, err := s.Circuit(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req verifyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.cfg.MaxWitnessSize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request: " + err.Error()})
+		return
+	}
+	publicWitness, err := c.newWitness()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := publicWitness.UnmarshalBinary(req.PublicWitness); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid public witness: " + err.Error()})
+		return
+	}
+
+	if err := s.Verify(id, bytes.NewReader(req.Proof), publicWitness); err != nil {
+		if errors.Is(err, ErrNotReady) {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, verifyResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, verifyResponse{Valid: true})
+}
+
+func newJobResponse(job *Job) jobResponse {
+	status, err := job.Status()
+	res := jobResponse{
+		ID:      job.ID,
+		Circuit: job.Circuit,
+		Status:  status,
+		Created: job.Created,
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func writeProof(w http.ResponseWriter, proof io.WriterTo) {
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = buf.WriteTo(w)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err with the status matching it.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnknownCircuit), errors.Is(err, ErrUnknownJob):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotReady):
+		status = http.StatusConflict
+	case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
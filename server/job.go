@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// JobStatus is the status of a proving job.
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// Job is a proving job, created by Server.Submit.
+type Job struct {
+	ID      string
+	Circuit string
+	Created time.Time
+
+	circuit *Circuit
+	witness witness.Witness // released once proved
+
+	lock     sync.Mutex
+	status   JobStatus
+	proof    io.WriterTo
+	err      error
+	finished time.Time
+	done     chan struct{}
+}
+
+// Status returns the status of the job, and the error of the prover if it
+// failed.
+func (job *Job) Status() (JobStatus, error) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.status, job.err
+}
+
+// Wait waits for the job to be finished, and returns the proof.
+func (job *Job) Wait(ctx context.Context) (io.WriterTo, error) {
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.proof, job.err
+}
+
+// Proof returns the proof if the job is done, or nil.
+func (job *Job) Proof() io.WriterTo {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.proof
+}
+
+func (job *Job) setStatus(status JobStatus) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	job.status = status
+}
+
+func (job *Job) finish(proof io.WriterTo, err error) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	if err != nil {
+		job.status = JobFailed
+		proof = nil // the provers return a typed nil proof with their error
+	} else {
+		job.status = JobDone
+	}
+	job.proof, job.err = proof, err
+	job.witness = nil
+	job.finished = time.Now()
+	close(job.done)
+}
+
+// finishedAt returns when the job finished, and whether it is finished.
+func (job *Job) finishedAt() (time.Time, bool) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.finished, !job.finished.IsZero()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	gnarkmetrics "github.com/consensys/gnark/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics of a Server, registered to Config.Registry.
+type metrics struct {
+	registry   prometheus.Registerer
+	queuedJobs prometheus.Gauge
+	operations *prometheus.CounterVec
+	durations  *prometheus.HistogramVec
+}
+
+// newMetrics registers the metrics of a Server to r, along with the metrics of
+// the gnark backends (see package metrics).
+func newMetrics(r prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		registry: r,
+		queuedJobs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "gnark",
+			Subsystem: "server",
+			Name:      "queued_jobs",
+			Help:      "Number of proving jobs waiting for a worker.",
+		}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gnark",
+			Subsystem: "server",
+			Name:      "operations_total",
+			Help:      "Number of setups, proofs and verifications.",
+		}, []string{"operation", "circuit", "result"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gnark",
+			Subsystem: "server",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of the setups, proofs and verifications.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+		}, []string{"operation", "circuit"}),
+	}
+	for i, c := range m.collectors() {
+		if err := r.Register(c); err != nil {
+			for _, c := range m.collectors()[:i] {
+				r.Unregister(c)
+			}
+			return nil, err
+		}
+	}
+
+	// the metrics of the backends may already be registered to r, by the
+	// caller or by another Server
+	if err := gnarkmetrics.Register(r); err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		m.unregister()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.queuedJobs, m.operations, m.durations}
+}
+
+// unregister unregisters the metrics of the Server, the metrics of the
+// backends stay registered.
+func (m *metrics) unregister() {
+	for _, c := range m.collectors() {
+		m.registry.Unregister(c)
+	}
+}
+
+// queued adds delta to the number of queued jobs.
+func (m *metrics) queued(delta int) {
+	m.queuedJobs.Add(float64(delta))
+}
+
+// observe records an operation on a circuit, which failed if err != nil.
+func (m *metrics) observe(operation, circuit string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.operations.WithLabelValues(operation, circuit, result).Inc()
+	m.durations.WithLabelValues(operation, circuit).Observe(duration.Seconds())
+}
@@ -0,0 +1,343 @@
+// Package server exposes Setup, Prove and Verify as a service, so that a gnark
+// prover can be deployed as a standalone microservice.
+//
+// A Server holds a registry of circuits (constraint system and proving
+// artifacts), and a queue of proving jobs processed by a pool of workers.
+// Its methods are transport independent; Handler exposes them as a JSON/HTTP
+// API, with the witnesses streamed in the request bodies and the metrics of
+// Config.Registry in the Prometheus text format, and RegisterGRPC as the gRPC
+// service defined in serverpb.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrUnknownCircuit is returned when the circuit is not registered.
+	ErrUnknownCircuit = errors.New("unknown circuit")
+
+	// ErrNotReady is returned when proving a circuit without proving key.
+	ErrNotReady = errors.New("circuit has no proving key, run the setup first")
+
+	// ErrUnknownJob is returned when the job doesn't exist or was evicted.
+	ErrUnknownJob = errors.New("unknown job")
+
+	// ErrQueueFull is returned when the job queue is full.
+	ErrQueueFull = errors.New("job queue is full")
+
+	// ErrClosed is returned when the server is closed.
+	ErrClosed = errors.New("server closed")
+)
+
+// Option defines option for altering the behavior of the Server.
+type Option func(*Config) error
+
+// Config is the configuration of a Server, with the options applied.
+type Config struct {
+	NbWorkers      int           // number of jobs proved concurrently
+	QueueSize      int           // number of jobs waiting for a worker
+	MaxWitnessSize int64         // maximum size of an uploaded witness, in bytes
+	JobRetention   time.Duration // time a finished job is kept
+
+	Registry *prometheus.Registry // registry of the metrics of the server and of the backends
+}
+
+// WithWorkers sets the number of jobs proved concurrently. The provers are
+// already parallel, the default is 1.
+func WithWorkers(n int) Option {
+	return func(cfg *Config) error {
+		if n < 1 {
+			return fmt.Errorf("invalid number of workers %d", n)
+		}
+		cfg.NbWorkers = n
+		return nil
+	}
+}
+
+// WithQueueSize sets the number of jobs waiting for a worker, beyond which
+// Submit returns ErrQueueFull. The default is 64.
+func WithQueueSize(n int) Option {
+	return func(cfg *Config) error {
+		if n < 0 {
+			return fmt.Errorf("invalid queue size %d", n)
+		}
+		cfg.QueueSize = n
+		return nil
+	}
+}
+
+// WithMaxWitnessSize sets the maximum size in bytes of a witness uploaded
+// through the HTTP or gRPC API. The default is 64MiB.
+func WithMaxWitnessSize(n int64) Option {
+	return func(cfg *Config) error {
+		if n < 1 {
+			return fmt.Errorf("invalid witness size %d", n)
+		}
+		cfg.MaxWitnessSize = n
+		return nil
+	}
+}
+
+// WithJobRetention sets the time a finished job (and its proof) is kept
+// before being evicted. The default is one hour.
+func WithJobRetention(d time.Duration) Option {
+	return func(cfg *Config) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid job retention %s", d)
+		}
+		cfg.JobRetention = d
+		return nil
+	}
+}
+
+// WithRegistry sets the Prometheus registry to which the metrics of the server
+// and of the backends (see package metrics) are registered. The default is a
+// new registry, served by Handler.
+func WithRegistry(r *prometheus.Registry) Option {
+	return func(cfg *Config) error {
+		if r == nil {
+			return errors.New("nil registry")
+		}
+		cfg.Registry = r
+		return nil
+	}
+}
+
+// Server proves and verifies the registered circuits.
+type Server struct {
+	cfg     Config
+	metrics *metrics
+
+	lock     sync.RWMutex
+	circuits map[string]*Circuit
+	jobs     map[string]*Job
+	closed   bool
+
+	queue chan *Job
+	wg    sync.WaitGroup
+}
+
+// New returns a Server with the given options, whose workers are started.
+func New(opts ...Option) (*Server, error) {
+	cfg := Config{
+		NbWorkers:      1,
+		QueueSize:      64,
+		MaxWitnessSize: 64 << 20,
+		JobRetention:   time.Hour,
+	}
+	for _, option := range opts {
+		if err := option(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Registry == nil {
+		cfg.Registry = prometheus.NewRegistry()
+	}
+	m, err := newMetrics(cfg.Registry)
+	if err != nil {
+		return nil, fmt.Errorf("register metrics: %w", err)
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		metrics:  m,
+		circuits: make(map[string]*Circuit),
+		jobs:     make(map[string]*Job),
+		queue:    make(chan *Job, cfg.QueueSize),
+	}
+	s.wg.Add(cfg.NbWorkers)
+	for i := 0; i < cfg.NbWorkers; i++ {
+		go s.work()
+	}
+	return s, nil
+}
+
+// Close stops accepting jobs and waits for the queued jobs to be proved. The
+// metrics of the server are unregistered from Config.Registry.
+func (s *Server) Close() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	s.lock.Unlock()
+	s.wg.Wait()
+	s.metrics.unregister()
+}
+
+// Register adds the circuit ccs to the registry under id, to be proved with
+// the given backend. Without proving artifacts in opts, Setup must be called
+// before proving.
+func (s *Server) Register(id string, ccs constraint.ConstraintSystem, backendID backend.ID, opts ...CircuitOption) error {
+	c, err := newCircuit(id, ccs, backendID, opts...)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.circuits[id]; ok {
+		return fmt.Errorf("circuit %q is already registered", id)
+	}
+	s.circuits[id] = c
+	return nil
+}
+
+// Circuit returns the registered circuit id.
+func (s *Server) Circuit(id string) (*Circuit, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	c, ok := s.circuits[id]
+	if !ok {
+		return nil, ErrUnknownCircuit
+	}
+	return c, nil
+}
+
+// Circuits returns the registered circuits.
+func (s *Server) Circuits() []*Circuit {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	res := make([]*Circuit, 0, len(s.circuits))
+	for _, c := range s.circuits {
+		res = append(res, c)
+	}
+	return res
+}
+
+// Setup runs the setup of the circuit id, replacing its proving artifacts.
+func (s *Server) Setup(id string) error {
+	c, err := s.Circuit(id)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = c.setup()
+	s.metrics.observe("setup", id, time.Since(start), err)
+	return err
+}
+
+// Submit queues a proving job of the circuit id with the full witness. The job
+// is processed asynchronously, see Job.Wait.
+func (s *Server) Submit(id string, fullWitness witness.Witness) (*Job, error) {
+	c, err := s.Circuit(id)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Ready() {
+		return nil, ErrNotReady
+	}
+
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	job := &Job{
+		ID:      hex.EncodeToString(b[:]),
+		Circuit: id,
+		Created: time.Now(),
+		status:  JobQueued,
+		witness: fullWitness,
+		circuit: c,
+		done:    make(chan struct{}),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	s.evict()
+	s.metrics.queued(1)
+	select {
+	case s.queue <- job:
+	default:
+		s.metrics.queued(-1)
+		return nil, ErrQueueFull
+	}
+	s.jobs[job.ID] = job
+	return job, nil
+}
+
+// Job returns the job id.
+func (s *Server) Job(id string) (*Job, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	return job, nil
+}
+
+// Prove proves the circuit id with the full witness, and writes the proof to
+// w. The proving job is queued like the ones of Submit.
+func (s *Server) Prove(ctx context.Context, id string, fullWitness witness.Witness, w io.Writer) error {
+	job, err := s.Submit(id, fullWitness)
+	if err != nil {
+		return err
+	}
+	proof, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = proof.WriteTo(w)
+	return err
+}
+
+// Verify verifies the proof, read from r, of the circuit id with the public
+// witness.
+func (s *Server) Verify(id string, r io.Reader, publicWitness witness.Witness) error {
+	c, err := s.Circuit(id)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = c.verify(r, publicWitness)
+	s.metrics.observe("verify", id, time.Since(start), err)
+	return err
+}
+
+// work proves the queued jobs until the queue is closed.
+func (s *Server) work() {
+	defer s.wg.Done()
+	log := logger.Logger().With().Str("component", "server").Logger()
+	for job := range s.queue {
+		s.metrics.queued(-1)
+		job.setStatus(JobRunning)
+		start := time.Now()
+		proof, err := job.circuit.prove(job.witness)
+		s.metrics.observe("prove", job.Circuit, time.Since(start), err)
+		if err != nil {
+			log.Debug().Str("job", job.ID).Str("circuit", job.Circuit).Err(err).Msg("proving job failed")
+		}
+		job.finish(proof, err)
+	}
+}
+
+// evict removes the jobs finished for longer than the retention time. The lock
+// must be held.
+func (s *Server) evict() {
+	deadline := time.Now().Add(-s.cfg.JobRetention)
+	for id, job := range s.jobs {
+		if t, ok := job.finishedAt(); ok && t.Before(deadline) {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/server"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestHTTP(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	s, err := server.New()
+	assert.NoError(err)
+	defer s.Close()
+	assert.NoError(s.Register("square", ccs, backend.GROTH16))
+
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	post := func(path string, body []byte) *http.Response {
+		res, err := http.Post(srv.URL+path, "application/octet-stream", bytes.NewReader(body))
+		assert.NoError(err)
+		return res
+	}
+	get := func(path string) *http.Response {
+		res, err := http.Get(srv.URL + path)
+		assert.NoError(err)
+		return res
+	}
+	readAll := func(res *http.Response) []byte {
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		assert.NoError(err)
+		return b
+	}
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	bWitness, err := w.MarshalBinary()
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	bPublic, err := public.MarshalBinary()
+	assert.NoError(err)
+
+	// proving requires the setup
+	res := post("/circuits/square/prove", bWitness)
+	readAll(res)
+	assert.Equal(http.StatusConflict, res.StatusCode)
+	res = post("/circuits/square/setup", nil)
+	readAll(res)
+	assert.Equal(http.StatusNoContent, res.StatusCode)
+	res = get("/circuits/square/vk")
+	assert.NotEmpty(readAll(res))
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	// asynchronous proving
+	res = post("/circuits/square/prove", bWitness)
+	var job struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	assert.NoError(json.Unmarshal(readAll(res), &job))
+	assert.Equal(http.StatusAccepted, res.StatusCode)
+	j, err := s.Job(job.ID)
+	assert.NoError(err)
+	_, err = j.Wait(context.Background())
+	assert.NoError(err)
+	res = get("/jobs/" + job.ID)
+	assert.NoError(json.Unmarshal(readAll(res), &job))
+	assert.Equal("done", job.Status)
+	res = get("/jobs/" + job.ID + "/proof")
+	proof := readAll(res)
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	verify := func(proof, public []byte) (valid bool) {
+		body, err := json.Marshal(map[string][]byte{"proof": proof, "publicWitness": public})
+		assert.NoError(err)
+		res := post("/circuits/square/verify", body)
+		var v struct {
+			Valid bool `json:"valid"`
+		}
+		assert.NoError(json.Unmarshal(readAll(res), &v))
+		assert.Equal(http.StatusOK, res.StatusCode)
+		return v.Valid
+	}
+	assert.True(verify(proof, bPublic))
+
+	// synchronous proving, with another public input
+	res = post("/circuits/square/prove?wait=true", bWitness)
+	assert.True(verify(readAll(res), bPublic))
+	w, err = frontend.NewWitness(&squareCircuit{X: 4, Y: 16}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err = w.Public()
+	assert.NoError(err)
+	bOtherPublic, err := public.MarshalBinary()
+	assert.NoError(err)
+	assert.False(verify(proof, bOtherPublic))
+
+	// unsatisfied witness
+	w, err = frontend.NewWitness(&squareCircuit{X: 4, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	bWitness, err = w.MarshalBinary()
+	assert.NoError(err)
+	res = post("/circuits/square/prove?wait=true", bWitness)
+	readAll(res)
+	assert.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+
+	res = get("/circuits/unknown/vk")
+	readAll(res)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+
+	metrics := string(readAll(get("/metrics")))
+	assert.Contains(metrics, `gnark_server_operations_total{circuit="square",operation="prove",result="success"} 2`)
+	assert.Contains(metrics, `gnark_server_operations_total{circuit="square",operation="prove",result="failure"} 1`)
+	assert.Contains(metrics, `gnark_server_operations_total{circuit="square",operation="verify",result="failure"} 1`)
+	assert.Contains(metrics, "gnark_server_queued_jobs 0")
+	assert.Contains(metrics, `gnark_proofs_total{backend="groth16",curve="bn254"}`)
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	s, err := server.New(server.WithWorkers(2), server.WithJobRetention(time.Minute))
+	assert.NoError(err)
+	defer s.Close()
+	assert.Error(s.Register("square", ccs, backend.GROTH16))
+	assert.NoError(s.Register("square", ccs, backend.PLONK, server.WithKZGSRS(srs)))
+	assert.NoError(s.Setup("square"))
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+
+	var proof bytes.Buffer
+	assert.NoError(s.Prove(context.Background(), "square", w, &proof))
+	assert.NoError(s.Verify("square", &proof, public))
+}
@@ -0,0 +1,5 @@
+// Package serverpb holds the protocol buffers and the gRPC service of the API
+// of package server, generated from server.proto.
+package serverpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative server.proto
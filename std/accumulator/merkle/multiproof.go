@@ -0,0 +1,306 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// MultiProof is a batch opening of several leaves of a Merkle tree of any arity:
+// the nodes shared by the paths of the leaves are hashed once, instead of once per
+// path with independent MerkleProof.
+//
+// The proof lists, level by level from the parents of the leaves to the root,
+// the nodes on the paths of the leaves with all their children. Each child is
+// either a node of the level below on the paths (or one of the leaves), or an
+// opaque sibling. The circuit hashes every node of the proof, and checks with a
+// multiset argument on the commitment of the circuit (see std/multicommit)
+// that the nodes of each level are exactly the children marked as coming from
+// below. The number of nodes per level is fixed at compile time (see
+// NewMultiProof and NewRangeMultiProof); unused nodes are padding.
+//
+// Leaves are hashed as in MerkleProof, and a node is the hash of its children.
+type MultiProof struct {
+	// RootHash root of the Merkle tree
+	RootHash frontend.Variable
+
+	// Levels are the nodes of the proof, from the parents of the leaves (level
+	// 0) to the root (last level, of a single node).
+	Levels [][]MultiProofNode
+}
+
+// MultiProofNode is a node of a MultiProof.
+type MultiProofNode struct {
+	// Index is the index of the node in its level.
+	Index frontend.Variable
+	// Children are the values of the children of the node.
+	Children []frontend.Variable
+	// FromBelow[i] is 1 if Children[i] is a node of the level below, or a
+	// leaf, in the proof, and 0 if it is a sibling.
+	FromBelow []frontend.Variable
+	// Used is 1 if the node is in the proof, 0 if it is padding.
+	Used frontend.Variable
+}
+
+// NewMultiProof returns a MultiProof of nbLeaves leaves of a tree of the given
+// depth and arity, sized for any set of leaves: a level has at most as many
+// nodes as there are leaves.
+func NewMultiProof(depth, arity, nbLeaves int) MultiProof {
+	return newMultiProof(depth, arity, func(int) int { return nbLeaves })
+}
+
+// NewRangeMultiProof returns a MultiProof of nbLeaves consecutive leaves of a
+// tree of the given depth and arity. The paths of consecutive leaves share most
+// of their nodes: the level of height h has at most ⌈(nbLeaves-1)/arityʰ⌉+1
+// nodes.
+func NewRangeMultiProof(depth, arity, nbLeaves int) MultiProof {
+	return newMultiProof(depth, arity, func(height int) int {
+		span := 1
+		for i := 0; i < height && span < nbLeaves; i++ {
+			span *= arity
+		}
+		return (nbLeaves-1+span-1)/span + 1
+	})
+}
+
+func newMultiProof(depth, arity int, maxNodes func(height int) int) MultiProof {
+	if depth < 1 || arity < 2 {
+		panic("the depth must be positive and the arity at least 2")
+	}
+	mp := MultiProof{Levels: make([][]MultiProofNode, depth)}
+	levelSize := 1 // number of nodes of the tree at the level, saturated
+	for i := depth - 1; i >= 0; i-- {
+		n := maxNodes(i + 1)
+		if levelSize < n {
+			n = levelSize
+		}
+		mp.Levels[i] = make([]MultiProofNode, n)
+		for j := range mp.Levels[i] {
+			mp.Levels[i][j].Children = make([]frontend.Variable, arity)
+			mp.Levels[i][j].FromBelow = make([]frontend.Variable, arity)
+		}
+		if levelSize < 1<<32 {
+			levelSize *= arity
+		}
+	}
+	return mp
+}
+
+// Assign sets the values of the proof opening the leaves at the given indices
+// of the tree of leaves, of depth len(mp.Levels). mp must be allocated with
+// NewMultiProof or NewRangeMultiProof, with the arity of the tree, and
+// len(leaves) must be arity^depth. The indices must be distinct.
+//
+// The leaves are encoded field elements, of the size of the blocks of h, and
+// h is the native counterpart of the hash function given to VerifyProof. All
+// the nodes of the tree are computed.
+func (mp *MultiProof) Assign(h stdhash.Hash, leaves [][]byte, indices []int) error {
+	if len(mp.Levels) == 0 || len(mp.Levels[len(mp.Levels)-1]) != 1 {
+		return errors.New("the proof is not allocated")
+	}
+	arity := len(mp.Levels[0][0].Children)
+	nbLeaves := 1
+	for range mp.Levels {
+		nbLeaves *= arity
+	}
+	if len(leaves) != nbLeaves {
+		return fmt.Errorf("expected %d leaves, got %d", nbLeaves, len(leaves))
+	}
+
+	// nodes of the level below, and indices of the nodes on the paths
+	below := make([][]byte, len(leaves))
+	for i := range leaves {
+		h.Reset()
+		h.Write(leaves[i])
+		below[i] = h.Sum(nil)
+	}
+	onPaths := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= nbLeaves {
+			return fmt.Errorf("leaf index %d out of range", i)
+		}
+		if onPaths[i] {
+			return fmt.Errorf("duplicate leaf index %d", i)
+		}
+		onPaths[i] = true
+	}
+
+	for l, level := range mp.Levels {
+		nodes := make([][]byte, len(below)/arity)
+		for i := range nodes {
+			h.Reset()
+			for _, child := range below[i*arity : (i+1)*arity] {
+				h.Write(child)
+			}
+			nodes[i] = h.Sum(nil)
+		}
+
+		parents := make(map[int]bool)
+		for i := range onPaths {
+			parents[i/arity] = true
+		}
+		if len(parents) > len(level) {
+			return fmt.Errorf("level %d: %d nodes on the paths, the proof has room for %d", l, len(parents), len(level))
+		}
+		sorted := make([]int, 0, len(parents))
+		for i := range parents {
+			sorted = append(sorted, i)
+		}
+		sort.Ints(sorted)
+
+		for j := range level {
+			node := &level[j]
+			if j >= len(sorted) {
+				// padding
+				node.Index, node.Used = 0, 0
+				for c := range node.Children {
+					node.Children[c], node.FromBelow[c] = 0, 0
+				}
+				continue
+			}
+			node.Index, node.Used = sorted[j], 1
+			for c := range node.Children {
+				i := sorted[j]*arity + c
+				node.Children[c] = new(big.Int).SetBytes(below[i])
+				node.FromBelow[c] = 0
+				if onPaths[i] {
+					node.FromBelow[c] = 1
+				}
+			}
+		}
+		below, onPaths = nodes, parents
+	}
+	mp.RootHash = new(big.Int).SetBytes(below[0])
+	return nil
+}
+
+// VerifyProof asserts that the leaves are in the tree of root mp.RootHash, at
+// the given indices. The indices must be distinct.
+//
+// It costs a hash per leaf and per node of the proof, and a few constraints per
+// child of the nodes for the multiset argument. The circuit must support
+// commitments (see frontend.Committer).
+func (mp *MultiProof) VerifyProof(api frontend.API, h hash.Hash, leaves, indices []frontend.Variable) {
+	if len(leaves) != len(indices) {
+		panic("the numbers of leaves and indices differ")
+	}
+	if len(mp.Levels) == 0 || len(mp.Levels[len(mp.Levels)-1]) != 1 {
+		panic("the last level of the proof must be the root")
+	}
+	arity := len(mp.Levels[0][0].Children)
+
+	// the leaf indices must be in range for the nodes of their paths to be the
+	// digits of the indices in base arity
+	nbLeaves := new(big.Int).Exp(big.NewInt(int64(arity)), big.NewInt(int64(len(mp.Levels))), nil)
+	for i := range indices {
+		if arity&(arity-1) == 0 {
+			rangecheck.New(api).Check(indices[i], nbLeaves.BitLen()-1)
+		} else {
+			api.AssertIsLessOrEqual(indices[i], new(big.Int).Sub(nbLeaves, big.NewInt(1)))
+		}
+	}
+
+	// the values and indices of the nodes of the level below, and whether they
+	// are in the proof
+	values := make([]frontend.Variable, len(leaves))
+	for i := range leaves {
+		values[i] = leafSum(api, h, leaves[i])
+	}
+	below := levelNodes{values: values, indices: indices, used: make([]frontend.Variable, len(leaves))}
+	for i := range below.used {
+		below.used[i] = 1
+	}
+
+	var committed []frontend.Variable
+	var checks []func(api frontend.API, gamma, beta frontend.Variable) frontend.Variable
+	for l, level := range mp.Levels {
+		nodes := levelNodes{
+			values:  make([]frontend.Variable, len(level)),
+			indices: make([]frontend.Variable, len(level)),
+			used:    make([]frontend.Variable, len(level)),
+		}
+		for j := range level {
+			node := &level[j]
+			if len(node.Children) != arity || len(node.FromBelow) != arity {
+				panic(fmt.Sprintf("level %d, node %d: expected %d children", l, j, arity))
+			}
+			api.AssertIsBoolean(node.Used)
+			for c := range node.FromBelow {
+				api.AssertIsBoolean(node.FromBelow[c])
+				// padding nodes don't consume nodes of the level below
+				api.AssertIsEqual(api.Mul(node.FromBelow[c], api.Sub(1, node.Used)), 0)
+			}
+			h.Reset()
+			h.Write(node.Children...)
+			nodes.values[j] = h.Sum()
+			nodes.indices[j] = node.Index
+			nodes.used[j] = node.Used
+			committed = append(committed, node.Index, node.Used)
+			committed = append(committed, node.Children...)
+			committed = append(committed, node.FromBelow...)
+		}
+		checks = append(checks, levelCheck(below, level, arity))
+		below = nodes
+	}
+
+	root := &mp.Levels[len(mp.Levels)-1][0]
+	api.AssertIsEqual(root.Used, 1)
+	api.AssertIsEqual(root.Index, 0)
+	api.AssertIsEqual(below.values[0], mp.RootHash)
+
+	// the multiset of the nodes (index, value) of each level in the proof is the
+	// multiset of the children from below of the level above, at index
+	// arity·parent + position. The pairs are encoded as γ - (index + β·value),
+	// with β = γⁿ⁺¹ for n larger than the number of pairs, which is a Kronecker
+	// substitution of independent challenges.
+	committed = append(committed, values...)
+	committed = append(committed, indices...)
+	nbPairs := len(leaves)
+	for _, level := range mp.Levels {
+		nbPairs += len(level) * (arity + 1)
+	}
+	multicommit.WithCommitment(api, func(api frontend.API, gamma frontend.Variable) error {
+		beta := gamma
+		for n := 1; n <= nbPairs; n *= 2 {
+			beta = api.Mul(beta, beta)
+		}
+		for _, check := range checks {
+			api.AssertIsEqual(check(api, gamma, beta), 0)
+		}
+		return nil
+	}, committed...)
+}
+
+// levelNodes are the nodes of a level of a MultiProof.
+type levelNodes struct {
+	values, indices, used []frontend.Variable
+}
+
+// levelCheck returns the function computing the difference of the products of
+// the encodings of the nodes in the proof of a level, and of the children from
+// below of the nodes of the level above.
+func levelCheck(below levelNodes, above []MultiProofNode, arity int) func(api frontend.API, gamma, beta frontend.Variable) frontend.Variable {
+	return func(api frontend.API, gamma, beta frontend.Variable) frontend.Variable {
+		encode := func(index, value frontend.Variable) frontend.Variable {
+			return api.Sub(gamma, api.Add(index, api.Mul(beta, value)))
+		}
+		var left, right frontend.Variable = 1, 1
+		for i := range below.values {
+			left = api.Mul(left, api.Select(below.used[i], encode(below.indices[i], below.values[i]), 1))
+		}
+		for j := range above {
+			base := api.Mul(above[j].Index, arity)
+			for c := range above[j].Children {
+				right = api.Mul(right, api.Select(above[j].FromBelow[c], encode(api.Add(base, c), above[j].Children[c]), 1))
+			}
+		}
+		return api.Sub(left, right)
+	}
+}
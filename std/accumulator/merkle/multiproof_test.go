@@ -0,0 +1,150 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	mimc_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type multiProofCircuit struct {
+	Proof   MultiProof
+	Leaves  []frontend.Variable
+	Indices []frontend.Variable
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyProof(api, &h, c.Leaves, c.Indices)
+	return nil
+}
+
+// pathCircuit verifies the leaves with independent MerkleProof, for comparison.
+type pathCircuit struct {
+	Proofs  []MerkleProof
+	Indices []frontend.Variable
+}
+
+func (c *pathCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Proofs {
+		c.Proofs[i].VerifyProof(api, &h, c.Indices[i])
+	}
+	return nil
+}
+
+func treeLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		var e fr.Element
+		e.SetUint64(uint64(1000 + i))
+		b := e.Bytes()
+		leaves[i] = b[:]
+	}
+	return leaves
+}
+
+func newMultiProofCircuits(mp MultiProof, nbLeaves int) (*multiProofCircuit, *multiProofCircuit) {
+	circuit := &multiProofCircuit{Proof: mp, Leaves: make([]frontend.Variable, nbLeaves), Indices: make([]frontend.Variable, nbLeaves)}
+	return circuit, &multiProofCircuit{Leaves: make([]frontend.Variable, nbLeaves), Indices: make([]frontend.Variable, nbLeaves)}
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the JSON witness schema doesn't support slices of structs holding slices
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK), test.NoSerialization()}
+
+	for _, tc := range []struct {
+		name         string
+		depth, arity int
+		indices      []int
+		rangeProof   bool
+	}{
+		{"binary", 4, 2, []int{0, 1, 6, 15}, false},
+		{"binary/range", 4, 2, []int{5, 6, 7, 8, 9}, true},
+		{"ternary", 3, 3, []int{2, 13, 14, 26}, false},
+		{"single", 3, 2, []int{3}, false},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			leaves := treeLeaves(pow(tc.arity, tc.depth))
+			alloc := NewMultiProof
+			if tc.rangeProof {
+				alloc = NewRangeMultiProof
+			}
+			circuit, witness := newMultiProofCircuits(alloc(tc.depth, tc.arity, len(tc.indices)), len(tc.indices))
+			witness.Proof = alloc(tc.depth, tc.arity, len(tc.indices))
+			assert.NoError(witness.Proof.Assign(mimc_bn254.NewMiMC(), leaves, tc.indices))
+			for i, index := range tc.indices {
+				witness.Leaves[i] = new(big.Int).SetBytes(leaves[index])
+				witness.Indices[i] = index
+			}
+			assert.ProverSucceeded(circuit, witness, opts...)
+
+			// wrong leaf
+			witness.Leaves[0] = 1
+			assert.ProverFailed(circuit, witness, opts...)
+			witness.Leaves[0] = new(big.Int).SetBytes(leaves[tc.indices[0]])
+
+			// wrong index
+			witness.Indices[0] = (tc.indices[0] + 1) % len(leaves)
+			assert.ProverFailed(circuit, witness, opts...)
+		}, tc.name)
+	}
+}
+
+func TestMultiProofAssign(t *testing.T) {
+	assert := require.New(t)
+	leaves := treeLeaves(16)
+
+	mp := NewMultiProof(4, 2, 2)
+	assert.Error(mp.Assign(mimc_bn254.NewMiMC(), leaves, []int{1, 1}))
+	assert.Error(mp.Assign(mimc_bn254.NewMiMC(), leaves[:8], []int{1, 2}))
+	assert.Error(mp.Assign(mimc_bn254.NewMiMC(), leaves, []int{1, 16}))
+
+	// a range proof has no room for leaves far apart
+	mp = NewRangeMultiProof(4, 2, 3)
+	assert.NoError(mp.Assign(mimc_bn254.NewMiMC(), leaves, []int{7, 8, 9}))
+	assert.Error(mp.Assign(mimc_bn254.NewMiMC(), leaves, []int{0, 5, 10}))
+}
+
+func TestMultiProofSize(t *testing.T) {
+	assert := require.New(t)
+
+	// 64 consecutive leaves of a tree of depth 20
+	const depth, nbLeaves = 20, 64
+	nbConstraints := func(circuit frontend.Circuit) int {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+		assert.NoError(err)
+		return ccs.GetNbConstraints()
+	}
+	paths := &pathCircuit{Proofs: make([]MerkleProof, nbLeaves), Indices: make([]frontend.Variable, nbLeaves)}
+	for i := range paths.Proofs {
+		paths.Proofs[i].Path = make([]frontend.Variable, depth+1)
+	}
+	circuit, _ := newMultiProofCircuits(NewRangeMultiProof(depth, 2, nbLeaves), nbLeaves)
+	multi, independent := nbConstraints(circuit), nbConstraints(paths)
+	t.Logf("multiproof: %d constraints, independent paths: %d constraints", multi, independent)
+	assert.Less(multi, independent/2)
+}
+
+func pow(a, n int) int {
+	r := 1
+	for i := 0; i < n; i++ {
+		r *= a
+	}
+	return r
+}
@@ -46,6 +46,11 @@ limitations under the License.
 package merkle
 
 import (
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/hash"
 )
@@ -60,6 +65,56 @@ type MerkleProof struct {
 	Path []frontend.Variable
 }
 
+// Assign sets the values of the proof of the leaf at the given index of the
+// binary tree of leaves, whose number must be a power of 2. Path is allocated if
+// nil, and must otherwise have a length of depth+1.
+//
+// The leaves are encoded field elements, of the size of the blocks of h, and h
+// is the native counterpart of the hash function given to VerifyProof, for
+// instance the native MiMC of gnark-crypto or poseidon.NewHasher. All the nodes
+// of the tree are computed.
+func (mp *MerkleProof) Assign(h stdhash.Hash, leaves [][]byte, index int) error {
+	depth := 0
+	for 1<<depth < len(leaves) {
+		depth++
+	}
+	if len(leaves) < 2 || 1<<depth != len(leaves) {
+		return errors.New("the number of leaves must be a power of 2")
+	}
+	if index < 0 || index >= len(leaves) {
+		return fmt.Errorf("leaf index %d out of range", index)
+	}
+	if mp.Path == nil {
+		mp.Path = make([]frontend.Variable, depth+1)
+	}
+	if len(mp.Path) != depth+1 {
+		return fmt.Errorf("expected a path of length %d, got %d", depth+1, len(mp.Path))
+	}
+
+	level := make([][]byte, len(leaves))
+	for i := range leaves {
+		h.Reset()
+		if _, err := h.Write(leaves[i]); err != nil {
+			return fmt.Errorf("leaf %d: %w", i, err)
+		}
+		level[i] = h.Sum(nil)
+	}
+	mp.Path[0] = new(big.Int).SetBytes(leaves[index])
+	for i := 1; i <= depth; i++ {
+		mp.Path[i] = new(big.Int).SetBytes(level[index^1])
+		next := make([][]byte, len(level)/2)
+		for j := range next {
+			h.Reset()
+			h.Write(level[2*j])
+			h.Write(level[2*j+1])
+			next[j] = h.Sum(nil)
+		}
+		level, index = next, index/2
+	}
+	mp.RootHash = new(big.Int).SetBytes(level[0])
+	return nil
+}
+
 // leafSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
 func leafSum(api frontend.API, h hash.Hash, data frontend.Variable) frontend.Variable {
@@ -0,0 +1,73 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type poseidonProofCircuit struct {
+	Proof MerkleProof
+	Index frontend.Variable
+}
+
+func (c *poseidonProofCircuit) Define(api frontend.API) error {
+	h, err := poseidon.NewPoseidon(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyProof(api, &h, c.Index)
+	return nil
+}
+
+func TestMerkleProofAssign(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 4
+
+	leaves := make([][]byte, 1<<depth)
+	for i := range leaves {
+		leaves[i] = big.NewInt(int64(i*i + 7)).FillBytes(make([]byte, 32))
+	}
+
+	// the root is the one of the native Poseidon
+	level := make([]*big.Int, len(leaves))
+	for i := range leaves {
+		v, err := poseidon.Hash([]*big.Int{new(big.Int).SetBytes(leaves[i])})
+		require.NoError(t, err)
+		level[i] = v
+	}
+	for len(level) > 1 {
+		next := make([]*big.Int, len(level)/2)
+		for i := range next {
+			v, err := poseidon.Hash(level[2*i : 2*i+2])
+			require.NoError(t, err)
+			next[i] = v
+		}
+		level = next
+	}
+	root := level[0]
+
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+	circuit := &poseidonProofCircuit{Proof: MerkleProof{Path: make([]frontend.Variable, depth+1)}}
+	for _, index := range []int{0, 5, 1<<depth - 1} {
+		var witness poseidonProofCircuit
+		require.NoError(t, witness.Proof.Assign(poseidon.NewHasher(), leaves, index))
+		require.Equal(t, root, witness.Proof.RootHash)
+		witness.Index = index
+		assert.ProverSucceeded(circuit, &witness, opts...)
+
+		// the proof of another leaf
+		witness.Index = index ^ 1
+		assert.ProverFailed(circuit, &witness, opts...)
+	}
+
+	var proof MerkleProof
+	require.Error(t, proof.Assign(poseidon.NewHasher(), leaves[:3], 0))
+	require.Error(t, proof.Assign(poseidon.NewHasher(), leaves, len(leaves)))
+}
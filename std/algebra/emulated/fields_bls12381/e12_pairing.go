@@ -0,0 +1,79 @@
+package fields_bls12381
+
+// Expt sets z to x^t in E12 and returns z, where t = -0xd201000000010000 is the
+// seed of the curve. x must be in the cyclotomic subgroup.
+func (e Ext12) Expt(x *E12) *E12 {
+	// the bits of |t| are 63, 62, 60, 57, 48 and 16
+	z := e.CyclotomicSquare(x) // z.CyclotomicSquare(x)
+	z = e.Mul(z, x)            // z.Mul(z, x)
+	z = e.NCycloSquare(z, 2)   // z.NCycloSquare(2)
+	z = e.Mul(z, x)            // z.Mul(z, x)
+	z = e.NCycloSquare(z, 3)   // z.NCycloSquare(3)
+	z = e.Mul(z, x)            // z.Mul(z, x)
+	z = e.NCycloSquare(z, 9)   // z.NCycloSquare(9)
+	z = e.Mul(z, x)            // z.Mul(z, x)
+	z = e.NCycloSquare(z, 32)  // z.NCycloSquare(32)
+	z = e.Mul(z, x)            // z.Mul(z, x)
+	z = e.NCycloSquare(z, 16)  // z.NCycloSquare(16)
+	z = e.Conjugate(z)         // z.Conjugate(z) // because t is negative
+	return z                   // return z
+}
+
+func (e Ext12) MulBy014(z *E12, c0, c1, c4 *E2) *E12 {
+	// var a, b E6
+	// var d E2
+	a := e.Ext6.MulBy01(&z.C0, c0, c1) // a.MulBy01(c0, c1)
+	b := e.Ext6.MulBy1(&z.C1, c4)      // b.MulBy1(c4)
+	d := e.Ext2.Add(c1, c4)            // d.Add(c1, c4)
+	z1 := e.Ext6.Add(&z.C1, &z.C0)     // z.C1.Add(&z.C1, &z.C0)
+	z1 = e.Ext6.MulBy01(z1, c0, d)     // z.C1.MulBy01(c0, &d)
+	z1 = e.Ext6.Sub(z1, a)             // z.C1.Sub(&z.C1, &a)
+	z1 = e.Ext6.Sub(z1, b)             // z.C1.Sub(&z.C1, &b)
+	z0 := e.Ext6.MulByNonResidue(b)    // z.C0.MulByNonResidue(&b)
+	z0 = e.Ext6.Add(z0, a)             // z.C0.Add(&z.C0, &a)
+	return &E12{                       // return z
+		C0: *z0,
+		C1: *z1,
+	}
+}
+
+func (e Ext12) Mul014By014(d0, d1, d4, c0, c1, c4 *E2) *E12 {
+	// var tmp, x0, x1, x4, x04, x01, x14 E2
+	x0 := e.Ext2.Mul(c0, d0)          // x0.Mul(c0, d0)
+	x1 := e.Ext2.Mul(c1, d1)          // x1.Mul(c1, d1)
+	x4 := e.Ext2.Mul(c4, d4)          // x4.Mul(c4, d4)
+	tmp := e.Ext2.Add(d0, d4)         // tmp.Add(d0, d4)
+	x04 := e.Ext2.Add(c0, c4)         // x04.Add(c0, c4).
+	x04 = e.Ext2.Mul(x04, tmp)        // 	Mul(&x04, &tmp).
+	x04 = e.Ext2.Sub(x04, x0)         // 	Sub(&x04, &x0).
+	x04 = e.Ext2.Sub(x04, x4)         // 	Sub(&x04, &x4)
+	tmp = e.Ext2.Add(d0, d1)          // tmp.Add(d0, d1)
+	x01 := e.Ext2.Add(c0, c1)         // x01.Add(c0, c1).
+	x01 = e.Ext2.Mul(x01, tmp)        // 	Mul(&x01, &tmp).
+	x01 = e.Ext2.Sub(x01, x0)         // 	Sub(&x01, &x0).
+	x01 = e.Ext2.Sub(x01, x1)         // 	Sub(&x01, &x1)
+	tmp = e.Ext2.Add(d1, d4)          // tmp.Add(d1, d4)
+	x14 := e.Ext2.Add(c1, c4)         // x14.Add(c1, c4).
+	x14 = e.Ext2.Mul(x14, tmp)        // 	Mul(&x14, &tmp).
+	x14 = e.Ext2.Sub(x14, x1)         // 	Sub(&x14, &x1).
+	x14 = e.Ext2.Sub(x14, x4)         // 	Sub(&x14, &x4)
+	z00 := e.Ext2.MulByNonResidue(x4) // z.C0.B0.MulByNonResidue(&x4).
+	z00 = e.Ext2.Add(z00, x0)         // 	Add(&z.C0.B0, &x0)
+	z01 := x01                        // z.C0.B1.Set(&x01)
+	z02 := x1                         // z.C0.B2.Set(&x1)
+	z10 := e.Ext2.Zero()              // z.C1.B0.SetZero()
+	z11 := x04                        // z.C1.B1.Set(&x04)
+	z12 := x14                        // z.C1.B2.Set(&x14)
+	return &E12{                      // return z
+		C0: E6{
+			B0: *z00,
+			B1: *z01,
+			B2: *z02,
+		},
+		C1: E6{
+			B0: *z10,
+			B1: *z11,
+			B2: *z12,
+		},
+	}
+}
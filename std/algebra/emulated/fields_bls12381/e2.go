@@ -0,0 +1,295 @@
+package fields_bls12381
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+type curveF = emulated.Field[emulated.BLS12381Fp]
+type baseEl = emulated.Element[emulated.BLS12381Fp]
+
+type E2 struct {
+	A0, A1 baseEl
+}
+
+type Ext2 struct {
+	fp          *curveF
+	nonResidues map[int]map[int]*E2
+}
+
+func NewExt2(baseField *curveF) *Ext2 {
+	pwrs := map[int]map[int]struct {
+		A0 string
+		A1 string
+	}{
+		1: {
+			1: {"3850754370037169011952147076051364057158807420970682438676050522613628423219637725072182697113062777891589506424760", "151655185184498381465642749684540099398075398968325446656007613510403227271200139370504932015952886146304766135027"},
+			2: {"0", "4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436"},
+			3: {"1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257", "1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257"},
+			4: {"4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437", "0"},
+			5: {"877076961050607968509681729531255177986764537961432449499635504522207616027455086505066378536590128544573588734230", "3125332594171059424908108096204648978570118281977575435832422631601824034463382777937621250592425535493320683825557"},
+		},
+		2: {
+			1: {"793479390729215512621379701633421447060886740281060493010456487427281649075476305620758731620351", "0"},
+			2: {"793479390729215512621379701633421447060886740281060493010456487427281649075476305620758731620350", "0"},
+			3: {"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559786", "0"},
+			4: {"4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436", "0"},
+			5: {"4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437", "0"},
+		},
+		3: {
+			1: {"2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530", "1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257"},
+			2: {"0", "1"},
+			3: {"2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530", "2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530"},
+			4: {"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559786", "0"},
+			5: {"1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257", "2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530"},
+		},
+	}
+	nonResidues := make(map[int]map[int]*E2)
+	for pwr, v := range pwrs {
+		for coeff, v := range v {
+			el := E2{emulated.ValueOf[emulated.BLS12381Fp](v.A0), emulated.ValueOf[emulated.BLS12381Fp](v.A1)}
+			if nonResidues[pwr] == nil {
+				nonResidues[pwr] = make(map[int]*E2)
+			}
+			nonResidues[pwr][coeff] = &el
+		}
+	}
+	return &Ext2{fp: baseField, nonResidues: nonResidues}
+}
+
+// TODO: check where to use Mod and where ModMul.
+
+func (e Ext2) MulByElement(x *E2, y *baseEl) *E2 {
+	// var yCopy fp.Element
+	// yCopy.Set(y)
+	z0 := e.fp.MulMod(&x.A0, y) // z.A0.Mul(&x.A0, &yCopy)
+	z1 := e.fp.MulMod(&x.A1, y) // z.A1.Mul(&x.A1, &yCopy)
+	return &E2{                 // return z
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Conjugate(x *E2) *E2 {
+	z0 := x.A0            // z.A0 = x.A0
+	z1 := e.fp.Neg(&x.A1) // z.A1.Neg(&x.A1)
+	return &E2{           // return z
+		A0: z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) MulByNonResidueGeneric(x *E2, power, coef int) *E2 {
+	y := e.nonResidues[power][coef]
+	z := e.Mul(x, y)
+	return z
+}
+
+func (e Ext2) MulByNonResidue(x *E2) *E2 {
+	a := e.fp.Sub(&x.A0, &x.A1) // a.Sub(&x.A0, &x.A1)
+	b := e.fp.Add(&x.A0, &x.A1) // b.Add(&x.A0, &x.A1)
+	return &E2{
+		A0: *a, // z.A0.Set(&a)
+		A1: *b, // z.A1.Set(&b)
+	} // return z
+}
+
+func (e Ext2) MulByNonResidue1Power1(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 1, 1)
+}
+
+func (e Ext2) MulByNonResidue1Power2(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 1, 2)
+}
+
+func (e Ext2) MulByNonResidue1Power3(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 1, 3)
+}
+
+func (e Ext2) MulByNonResidue1Power4(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 1, 4)
+}
+
+func (e Ext2) MulByNonResidue1Power5(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 1, 5)
+}
+
+func (e Ext2) MulByNonResidue2Power1(x *E2) *E2 {
+	// TODO: A1 is 0, we can optimize for it
+	return e.MulByNonResidueGeneric(x, 2, 1)
+}
+func (e Ext2) MulByNonResidue2Power2(x *E2) *E2 {
+	// TODO: A1 is 0, we can optimize for it
+	return e.MulByNonResidueGeneric(x, 2, 2)
+}
+
+func (e Ext2) MulByNonResidue2Power3(x *E2) *E2 {
+	// TODO: A1 is 0, we can optimize for it
+	return e.MulByNonResidueGeneric(x, 2, 3)
+}
+
+func (e Ext2) MulByNonResidue2Power4(x *E2) *E2 {
+	// TODO: A1 is 0, we can optimize for it
+	return e.MulByNonResidueGeneric(x, 2, 4)
+}
+
+func (e Ext2) MulByNonResidue2Power5(x *E2) *E2 {
+	// TODO: A1 is 0, we can optimize for it
+	return e.MulByNonResidueGeneric(x, 2, 5)
+}
+
+func (e Ext2) MulByNonResidue3Power1(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 3, 1)
+}
+
+func (e Ext2) MulByNonResidue3Power2(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 3, 2)
+}
+
+func (e Ext2) MulByNonResidue3Power3(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 3, 3)
+}
+
+func (e Ext2) MulByNonResidue3Power4(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 3, 4)
+}
+
+func (e Ext2) MulByNonResidue3Power5(x *E2) *E2 {
+	return e.MulByNonResidueGeneric(x, 3, 5)
+}
+
+func (e Ext2) Mul(x, y *E2) *E2 {
+	// var a, b, c fp.Element
+	a := e.fp.Add(&x.A0, &x.A1)    // a.Add(&x.A0, &x.A1)
+	b := e.fp.Add(&y.A0, &y.A1)    // b.Add(&y.A0, &y.A1)
+	a = e.fp.MulMod(a, b)          // a.Mul(&a, &b)
+	b = e.fp.MulMod(&x.A0, &y.A0)  // b.Mul(&x.A0, &y.A0)
+	c := e.fp.MulMod(&x.A1, &y.A1) // c.Mul(&x.A1, &y.A1)
+	z1 := e.fp.Sub(a, b)           // z.A1.Sub(&a, &b).
+	z1 = e.fp.Sub(z1, c)           //   Sub(&z.A1, &c)
+	z0 := e.fp.Sub(b, c)           // z.A0.Sub(&b, &c)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Add(x, y *E2) *E2 {
+	z0 := e.fp.Add(&x.A0, &y.A0) // z.A0.Add(&x.A0, &y.A0)
+	z1 := e.fp.Add(&x.A1, &y.A1) // z.A1.Add(&x.A1, &y.A1)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Sub(x, y *E2) *E2 {
+	z0 := e.fp.Sub(&x.A0, &y.A0) // z.A0.Sub(&x.A0, &y.A0)
+	z1 := e.fp.Sub(&x.A1, &y.A1) // z.A1.Sub(&x.A1, &y.A1)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Neg(x *E2) *E2 {
+	z0 := e.fp.Neg(&x.A0) // z.A0.Neg(&x.A0)
+	z1 := e.fp.Neg(&x.A1) // z.A1.Neg(&x.A1)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) One() *E2 {
+	z0 := e.fp.One()  // z.A0.SetOne()
+	z1 := e.fp.Zero() // z.A1.SetZero()
+	return &E2{       // return z
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Zero() *E2 {
+	z0 := e.fp.Zero()
+	z1 := e.fp.Zero()
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Square(x *E2) *E2 {
+	// var a, b fp.Element
+	a := e.fp.Add(&x.A0, &x.A1)         // a.Add(&x.A0, &x.A1)
+	b := e.fp.Sub(&x.A0, &x.A1)         // b.Sub(&x.A0, &x.A1)
+	a = e.fp.MulMod(a, b)               // a.Mul(&a, &b)
+	b = e.fp.MulMod(&x.A0, &x.A1)       // b.Mul(&x.A0, &x.A1).
+	b = e.fp.MulConst(b, big.NewInt(2)) //   Double(&b)
+	return &E2{
+		A0: *a, // z.A0.Set(&a)
+		A1: *b, // z.A1.Set(&b)
+	}
+}
+
+func (e Ext2) Double(x *E2) *E2 {
+	two := big.NewInt(2)
+	z0 := e.fp.MulConst(&x.A0, two) // z.A0.Double(&x.A0)
+	z1 := e.fp.MulConst(&x.A1, two) // z.A1.Double(&x.A1)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) Halve(x *E2) *E2 {
+	// I'm trying to avoid hard-coding modulus here in case want to make generic
+	// for different curves.
+	// TODO: if implemented Half in field emulation, then replace with it.
+	one := e.fp.One()
+	two := e.fp.MulConst(one, big.NewInt(2))
+	z0 := e.fp.Div(&x.A0, two)
+	z1 := e.fp.Div(&x.A1, two)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) MulBybTwistCurveCoeff(x *E2) *E2 {
+	// var res E2
+	res := e.MulByNonResidue(x) // res.MulByNonResidue(x)
+	z := e.Double(res)          // z.Double(&res).
+	z = e.Double(z)             // 	Double(z)
+	return z                    // return z
+}
+
+func (e Ext2) Inverse(x *E2) *E2 {
+	// var t0, t1 fp.Element
+	t0 := e.fp.MulMod(&x.A0, &x.A0) // t0.Square(&x.A0)
+	t1 := e.fp.MulMod(&x.A1, &x.A1) // t1.Square(&x.A1)
+	t0 = e.fp.Add(t0, t1)           // t0.Add(&t0, &t1)
+	t1 = e.fp.Inverse(t0)           // t1.Inverse(&t0)
+	z0 := e.fp.MulMod(&x.A0, t1)    // z.A0.Mul(&x.A0, &t1)
+	z1 := e.fp.MulMod(&x.A1, t1)    // z.A1.Mul(&x.A1, &t1).
+	z1 = e.fp.Neg(z1)               //   Neg(&z.A1)
+	return &E2{
+		A0: *z0,
+		A1: *z1,
+	}
+}
+
+func (e Ext2) AssertIsEqual(x, y *E2) {
+	e.fp.AssertIsEqual(&x.A0, &y.A0)
+	e.fp.AssertIsEqual(&x.A1, &y.A1)
+}
+
+// Select returns x if selector == 1 and y otherwise.
+func (e Ext2) Select(selector frontend.Variable, x, y *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Select(selector, &x.A0, &y.A0),
+		A1: *e.fp.Select(selector, &x.A1, &y.A1),
+	}
+}
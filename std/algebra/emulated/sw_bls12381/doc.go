@@ -0,0 +1,7 @@
+// Package sw_bls12381 implements G1 and G2 arithmetics and pairing computation over BLS12-381 curve.
+//
+// The implementation follows very closely the implementation of its out-circuit
+// counterpart in [gnark-crypto].
+//
+// [gnark-crypto]: https://github.com/ConsenSys/gnark-crypto/tree/master/ecc/bls12-381
+package sw_bls12381
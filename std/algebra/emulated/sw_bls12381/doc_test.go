@@ -0,0 +1,85 @@
+package sw_bls12381_test
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+)
+
+type PairingCheckCircuit struct {
+	InG1 [2]sw_bls12381.G1Affine
+	InG2 [2]sw_bls12381.G2Affine
+}
+
+func (c *PairingCheckCircuit) Define(api frontend.API) error {
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	return pairing.PairingCheck(
+		[]*sw_bls12381.G1Affine{&c.InG1[0], &c.InG1[1]},
+		[]*sw_bls12381.G2Affine{&c.InG2[0], &c.InG2[1]},
+	)
+}
+
+func ExamplePairing_PairingCheck() {
+	// e([s]P, Q) · e(-P, [s]Q) = 1
+	_, _, g1, g2 := bls12381.Generators()
+	s, err := rand.Int(rand.Reader, ecc.BLS12_381.ScalarField())
+	if err != nil {
+		panic(err)
+	}
+	var sP, negP bls12381.G1Affine
+	var sQ bls12381.G2Affine
+	sP.ScalarMultiplication(&g1, s)
+	negP.Neg(&g1)
+	sQ.ScalarMultiplication(&g2, s)
+
+	circuit := PairingCheckCircuit{}
+	witness := PairingCheckCircuit{
+		InG1: [2]sw_bls12381.G1Affine{sw_bls12381.NewG1Affine(sP), sw_bls12381.NewG1Affine(negP)},
+		InG2: [2]sw_bls12381.G2Affine{sw_bls12381.NewG2Affine(g2), sw_bls12381.NewG2Affine(sQ)},
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("compiled")
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("setup done")
+	}
+	secretWitness, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("secret witness")
+	}
+	publicWitness, err := secretWitness.Public()
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("public witness")
+	}
+	proof, err := groth16.Prove(ccs, pk, secretWitness)
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("proof")
+	}
+	err = groth16.Verify(proof, vk, publicWitness)
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("verify")
+	}
+}
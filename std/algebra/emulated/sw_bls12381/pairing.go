@@ -0,0 +1,300 @@
+package sw_bls12381
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+type Pairing struct {
+	*fields_bls12381.Ext12
+}
+
+type GTEl = fields_bls12381.E12
+
+func NewGTEl(v bls12381.GT) GTEl {
+	return GTEl{
+		C0: fields_bls12381.E6{
+			B0: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B0.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B0.A1),
+			},
+			B1: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B1.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B1.A1),
+			},
+			B2: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B2.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C0.B2.A1),
+			},
+		},
+		C1: fields_bls12381.E6{
+			B0: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B0.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B0.A1),
+			},
+			B1: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B1.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B1.A1),
+			},
+			B2: fields_bls12381.E2{
+				A0: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B2.A0),
+				A1: emulated.ValueOf[emulated.BLS12381Fp](v.C1.B2.A1),
+			},
+		},
+	}
+}
+
+func NewPairing(api frontend.API) (*Pairing, error) {
+	ba, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	return &Pairing{
+		Ext12: fields_bls12381.NewExt12(ba),
+	}, nil
+}
+
+func (pr Pairing) DoubleStep(p *g2Projective) (*g2Projective, *lineEvaluation) {
+	// var t1, A, B, C, D, E, EE, F, G, H, I, J, K fptower.E2
+	A := pr.Ext2.Mul(&p.X, &p.Y)          // A.Mul(&p.x, &p.y)
+	A = pr.Ext2.Halve(A)                  // A.Halve()
+	B := pr.Ext2.Square(&p.Y)             // B.Square(&p.y)
+	C := pr.Ext2.Square(&p.Z)             // C.Square(&p.z)
+	D := pr.Ext2.Double(C)                // D.Double(&C).
+	D = pr.Ext2.Add(D, C)                 // 	Add(&D, &C)
+	E := pr.Ext2.MulBybTwistCurveCoeff(D) // E.MulBybTwistCurveCoeff(&D)
+	F := pr.Ext2.Double(E)                // F.Double(&E).
+	F = pr.Ext2.Add(F, E)                 // 	Add(&F, &E)
+	G := pr.Ext2.Add(B, F)                // G.Add(&B, &F)
+	G = pr.Ext2.Halve(G)                  // G.Halve()
+	H := pr.Ext2.Add(&p.Y, &p.Z)          // H.Add(&p.y, &p.z).
+	H = pr.Ext2.Square(H)                 // 	Square(&H)
+	t1 := pr.Ext2.Add(B, C)               // t1.Add(&B, &C)
+	H = pr.Ext2.Sub(H, t1)                // H.Sub(&H, &t1)
+	I := pr.Ext2.Sub(E, B)                // I.Sub(&E, &B)
+	J := pr.Ext2.Square(&p.X)             // J.Square(&p.x)
+	EE := pr.Ext2.Square(E)               // EE.Square(&E)
+	K := pr.Ext2.Double(EE)               // K.Double(&EE).
+	K = pr.Ext2.Add(K, EE)                // 	Add(&K, &EE)
+	px := pr.Ext2.Sub(B, F)               // p.x.Sub(&B, &F).
+	px = pr.Ext2.Mul(px, A)               // 	Mul(&p.x, &A)
+	py := pr.Ext2.Square(G)               // p.y.Square(&G).
+	py = pr.Ext2.Sub(py, K)               // 	Sub(&p.y, &K)
+	pz := pr.Ext2.Mul(B, H)               // p.z.Mul(&B, &H)
+	ev0 := I                              // evaluations.r0.Set(&I)
+	ev1 := pr.Ext2.Double(J)              // evaluations.r1.Double(&J).
+	ev1 = pr.Ext2.Add(ev1, J)             // 	Add(&evaluations.r1, &J)
+	ev2 := pr.Ext2.Neg(H)                 // evaluations.r2.Neg(&H)
+	return &g2Projective{
+			X: *px,
+			Y: *py,
+			Z: *pz,
+		},
+		&lineEvaluation{
+			r0: *ev0,
+			r1: *ev1,
+			r2: *ev2,
+		}
+}
+
+func (pr Pairing) affineToProjective(Q *G2Affine) *g2Projective {
+	// TODO: check point at infinity? We do not filter them in the Miller Loop neither.
+	// if Q.X.IsZero() && Q.Y.IsZero() {
+	// 	p.z.SetZero()
+	// 	p.x.SetOne()
+	// 	p.y.SetOne()
+	// 	return p
+	// }
+	pz := pr.Ext2.One()   // p.z.SetOne()
+	px := &Q.X            // p.x.Set(&Q.X)
+	py := &Q.Y            // p.y.Set(&Q.Y)
+	return &g2Projective{ // return p
+		X: *px,
+		Y: *py,
+		Z: *pz,
+	}
+}
+
+func (pr Pairing) NegAffine(a *G2Affine) *G2Affine {
+	px := &a.X              // p.X = a.X
+	py := pr.Ext2.Neg(&a.Y) // p.Y.Neg(&a.Y)
+	return &G2Affine{       // return p
+		X: *px,
+		Y: *py,
+	}
+}
+
+func (pr Pairing) AddStep(p *g2Projective, a *G2Affine) (*g2Projective, *lineEvaluation) {
+	// var Y2Z1, X2Z1, O, L, C, D, E, F, G, H, t0, t1, t2, J fptower.E2
+	Y2Z1 := pr.Ext2.Mul(&a.Y, &p.Z) // Y2Z1.Mul(&a.Y, &p.z)
+	O := pr.Ext2.Sub(&p.Y, Y2Z1)    // O.Sub(&p.y, &Y2Z1)
+	X2Z1 := pr.Ext2.Mul(&a.X, &p.Z) // X2Z1.Mul(&a.X, &p.z)
+	L := pr.Ext2.Sub(&p.X, X2Z1)    // L.Sub(&p.x, &X2Z1)
+	C := pr.Ext2.Square(O)          // C.Square(&O)
+	D := pr.Ext2.Square(L)          // D.Square(&L)
+	E := pr.Ext2.Mul(L, D)          // E.Mul(&L, &D)
+	F := pr.Ext2.Mul(&p.Z, C)       // F.Mul(&p.z, &C)
+	G := pr.Ext2.Mul(&p.X, D)       // G.Mul(&p.x, &D)
+	t0 := pr.Ext2.Double(G)         // t0.Double(&G)
+	H := pr.Ext2.Add(E, F)          // H.Add(&E, &F).
+	H = pr.Ext2.Sub(H, t0)          // 	Sub(&H, &t0)
+	t1 := pr.Ext2.Mul(&p.Y, E)      // t1.Mul(&p.y, &E)
+	px := pr.Ext2.Mul(L, H)         // p.x.Mul(&L, &H)
+	py := pr.Ext2.Sub(G, H)         // p.y.Sub(&G, &H).
+	py = pr.Ext2.Mul(py, O)         // 	Mul(&p.y, &O).
+	py = pr.Ext2.Sub(py, t1)        // 	Sub(&p.y, &t1)
+	pz := pr.Ext2.Mul(E, &p.Z)      // p.z.Mul(&E, &p.z)
+	t2 := pr.Ext2.Mul(L, &a.Y)      // t2.Mul(&L, &a.Y)
+	J := pr.Ext2.Mul(&a.X, O)       // J.Mul(&a.X, &O).
+	J = pr.Ext2.Sub(J, t2)          // 	Sub(&J, &t2)
+	ev0 := J                        // evaluations.r0.Set(&J)
+	ev1 := pr.Ext2.Neg(O)           // evaluations.r1.Neg(&O)
+	ev2 := L                        // evaluations.r2.Set(&L)
+	return &g2Projective{
+		X: *px,
+		Y: *py,
+		Z: *pz,
+	}, &lineEvaluation{
+		r0: *ev0,
+		r1: *ev1,
+		r2: *ev2,
+	}
+}
+
+type lineEvaluation struct {
+	r0 fields_bls12381.E2
+	r1 fields_bls12381.E2
+	r2 fields_bls12381.E2
+}
+
+var loopCounter = [64]int8{
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 1, 0, 1, 1,
+}
+
+func (pr Pairing) MillerLoop(p []*G1Affine, q []*G2Affine) (*GTEl, error) {
+	n := len(p)
+	if n == 0 || n != len(q) {
+		return nil, fmt.Errorf("invalid inputs sizes")
+	}
+
+	// TODO: we have omitted filtering for infinity points.
+
+	// projective points for Q
+	qProj := make([]*g2Projective, n) // qProj := make([]g2Proj, n)
+	for k := 0; k < n; k++ {
+		qProj[k] = pr.affineToProjective(q[k]) // qProj[k].FromAffine(&q[k])
+	}
+
+	var l, l0 *lineEvaluation
+	var result *GTEl // var result GTEl
+
+	// i == len(loopCounter) - 2
+	for k := 0; k < n; k++ {
+		qProj[k], l = pr.DoubleStep(qProj[k])                                    // qProj[k].DoubleStep(&l)
+		l.r1 = *pr.Ext12.Ext2.MulByElement(&l.r1, &p[k].X)                       // l.r1.MulByElement(&l.r1, &p[k].X)
+		l.r2 = *pr.Ext12.Ext2.MulByElement(&l.r2, &p[k].Y)                       // l.r2.MulByElement(&l.r2, &p[k].Y)
+		qProj[k], l0 = pr.AddStep(qProj[k], q[k])                                // qProj[k].AddMixedStep(&l0, &q[k])
+		l0.r1 = *pr.Ext12.Ext2.MulByElement(&l0.r1, &p[k].X)                     // l0.r1.MulByElement(&l0.r1, &p[k].X)
+		l0.r2 = *pr.Ext12.Ext2.MulByElement(&l0.r2, &p[k].Y)                     // l0.r2.MulByElement(&l0.r2, &p[k].Y)
+		tmp := pr.Ext12.Mul014By014(&l.r0, &l.r1, &l.r2, &l0.r0, &l0.r1, &l0.r2) // tmp.Mul014By014(&l.r0, &l.r1, &l.r2, &l0.r0, &l0.r1, &l0.r2)
+		if k == 0 {
+			result = tmp // result.Set(&tmp)
+		} else {
+			result = pr.Ext12.Mul(result, tmp) // result.Mul(&result, &tmp)
+		}
+	}
+
+	for i := len(loopCounter) - 3; i >= 0; i-- {
+		result = pr.Ext12.Square(result) // result.Square(&result)
+
+		for k := 0; k < n; k++ {
+			qProj[k], l = pr.DoubleStep(qProj[k])              // qProj[k].DoubleStep(&l)
+			l.r1 = *pr.Ext12.Ext2.MulByElement(&l.r1, &p[k].X) // l.r1.MulByElement(&l.r1, &p[k].X)
+			l.r2 = *pr.Ext12.Ext2.MulByElement(&l.r2, &p[k].Y) // l.r2.MulByElement(&l.r2, &p[k].Y)
+
+			if loopCounter[i] == 0 {
+				result = pr.Ext12.MulBy014(result, &l.r0, &l.r1, &l.r2) // result.MulBy014(&l.r0, &l.r1, &l.r2)
+			} else {
+				qProj[k], l0 = pr.AddStep(qProj[k], q[k])                                // qProj[k].AddMixedStep(&l0, &q[k])
+				l0.r1 = *pr.Ext12.Ext2.MulByElement(&l0.r1, &p[k].X)                     // l0.r1.MulByElement(&l0.r1, &p[k].X)
+				l0.r2 = *pr.Ext12.Ext2.MulByElement(&l0.r2, &p[k].Y)                     // l0.r2.MulByElement(&l0.r2, &p[k].Y)
+				tmp := pr.Ext12.Mul014By014(&l.r0, &l.r1, &l.r2, &l0.r0, &l0.r1, &l0.r2) // tmp.Mul014By014(&l.r0, &l.r1, &l.r2, &l0.r0, &l0.r1, &l0.r2)
+				result = pr.Ext12.Mul(result, tmp)                                       // result.Mul(&result, &tmp)
+			}
+		}
+	}
+
+	// negative seed
+	result = pr.Ext12.Conjugate(result) // result.Conjugate(&result)
+
+	return result, nil
+}
+
+// FinalExponentiation computes the exponentiation eᵈ where d = 3(p¹²-1)/r, the
+// hard part following [HHT20].
+//
+// [HHT20]: https://eprint.iacr.org/2020/875.pdf
+func (pr Pairing) FinalExponentiation(e *GTEl) *GTEl {
+	// var result GT
+	// result.Set(z)
+	var t [3]*GTEl // var t [3]GT
+
+	// easy part
+	t[0] = pr.Ext12.Conjugate(e)            // t[0].Conjugate(&result)
+	result := pr.Ext12.Inverse(e)           // result.Inverse(&result)
+	t[0] = pr.Ext12.Mul(t[0], result)       // t[0].Mul(&t[0], &result)
+	result = pr.Ext12.FrobeniusSquare(t[0]) // result.FrobeniusSquare(&t[0]).
+	result = pr.Ext12.Mul(result, t[0])     // 	Mul(&result, &t[0])
+
+	// hard part
+	t[0] = pr.Ext12.CyclotomicSquare(result) // t[0].CyclotomicSquare(&result)
+	t[1] = pr.Ext12.Expt(result)             // t[1].Expt(&result)
+	t[2] = pr.Ext12.Conjugate(result)        // t[2].InverseUnitary(&result)
+	t[1] = pr.Ext12.Mul(t[1], t[2])          // t[1].Mul(&t[1], &t[2])
+	t[2] = pr.Ext12.Expt(t[1])               // t[2].Expt(&t[1])
+	t[1] = pr.Ext12.Conjugate(t[1])          // t[1].InverseUnitary(&t[1])
+	t[1] = pr.Ext12.Mul(t[1], t[2])          // t[1].Mul(&t[1], &t[2])
+	t[2] = pr.Ext12.Expt(t[1])               // t[2].Expt(&t[1])
+	t[1] = pr.Ext12.Frobenius(t[1])          // t[1].Frobenius(&t[1])
+	t[1] = pr.Ext12.Mul(t[1], t[2])          // t[1].Mul(&t[1], &t[2])
+	result = pr.Ext12.Mul(result, t[0])      // result.Mul(&result, &t[0])
+	t[0] = pr.Ext12.Expt(t[1])               // t[0].Expt(&t[1])
+	t[2] = pr.Ext12.Expt(t[0])               // t[2].Expt(&t[0])
+	t[0] = pr.Ext12.FrobeniusSquare(t[1])    // t[0].FrobeniusSquare(&t[1])
+	t[1] = pr.Ext12.Conjugate(t[1])          // t[1].InverseUnitary(&t[1])
+	t[1] = pr.Ext12.Mul(t[1], t[2])          // t[1].Mul(&t[1], &t[2])
+	t[1] = pr.Ext12.Mul(t[1], t[0])          // t[1].Mul(&t[1], &t[0])
+	result = pr.Ext12.Mul(result, t[1])      // result.Mul(&result, &t[1])
+	return result                            // return result
+}
+
+func (pr Pairing) Pair(P []*G1Affine, Q []*G2Affine) (*GTEl, error) {
+	res, err := pr.MillerLoop(P, Q)
+	if err != nil {
+		return nil, fmt.Errorf("miller loop: %w", err)
+	}
+	res = pr.FinalExponentiation(res)
+	return res, nil
+}
+
+func (pr Pairing) AssertIsEqual(x, y *GTEl) {
+	pr.Ext12.AssertIsEqual(x, y)
+}
+
+// PairingCheck asserts that the product of the pairings of the points of P and
+// Q is one: ∏ᵢ e(Pᵢ, Qᵢ) = 1.
+func (pr Pairing) PairingCheck(P []*G1Affine, Q []*G2Affine) error {
+	res, err := pr.Pair(P, Q)
+	if err != nil {
+		return err
+	}
+	pr.AssertIsEqual(res, pr.Ext12.One())
+	return nil
+}
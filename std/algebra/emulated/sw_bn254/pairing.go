@@ -155,14 +155,14 @@ func (pr Pairing) AddStep(p *g2Projective, a *G2Affine) (*g2Projective, *lineEva
 	ev1 := pr.Ext2.Neg(O)           // evaluations.r1.Neg(&O)
 	ev2 := J                        // evaluations.r2.Set(&J)
 	return &g2Projective{
-			X: *px,
-			Y: *py,
-			Z: *pz,
-		}, &lineEvaluation{
-			r0: *ev0,
-			r1: *ev1,
-			r2: *ev2,
-		}
+		X: *px,
+		Y: *py,
+		Z: *pz,
+	}, &lineEvaluation{
+		r0: *ev0,
+		r1: *ev1,
+		r2: *ev2,
+	}
 }
 
 type lineEvaluation struct {
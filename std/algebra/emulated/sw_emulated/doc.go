@@ -10,8 +10,10 @@ over some base field 𝐅p for some constants a, b ∈ 𝐅p.
 Additionally, for every curve we also define its generator (base point) G. All
 these parameters are stored in the variable of type [CurveParams].
 
-The package provides a few curve parameters, see functions [GetSecp256k1Params]
-and [GetBN254Params].
+The package provides a few curve parameters, see functions [GetSecp256k1Params],
+[GetBN254Params], [GetBLS12381Params], [GetP384Params],
+[GetBrainpoolP256r1Params] and [GetBrainpoolP384r1Params]; the last three are
+among the curves of the signatures of e-passports (ICAO 9303).
 
 Unconventionally, this package uses type parameters to define the base field of
 the points and variables to define the coefficients of the curve. This is due to
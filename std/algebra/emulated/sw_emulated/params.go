@@ -1,8 +1,10 @@
 package sw_emulated
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 	"github.com/consensys/gnark/std/math/emulated"
@@ -13,13 +15,15 @@ import (
 //
 //	Y² = X³ + aX + b
 //
-// The base point is defined by (Gx, Gy).
+// The base point is defined by (Gx, Gy). A nil Cofactor stands for a curve of
+// prime order.
 type CurveParams struct {
-	A  *big.Int      // a in curve equation
-	B  *big.Int      // b in curve equation
-	Gx *big.Int      // base point x
-	Gy *big.Int      // base point y
-	Gm [][2]*big.Int // m*base point coords
+	A        *big.Int      // a in curve equation
+	B        *big.Int      // b in curve equation
+	Gx       *big.Int      // base point x
+	Gy       *big.Int      // base point y
+	Gm       [][2]*big.Int // m*base point coords
+	Cofactor *big.Int      // order of the curve divided by the order of the base point
 }
 
 // GetSecp256k1Params returns curve parameters for the curve secp256k1. When
@@ -28,11 +32,12 @@ type CurveParams struct {
 func GetSecp256k1Params() CurveParams {
 	_, g1aff := secp256k1.Generators()
 	return CurveParams{
-		A:  big.NewInt(0),
-		B:  big.NewInt(7),
-		Gx: g1aff.X.BigInt(new(big.Int)),
-		Gy: g1aff.Y.BigInt(new(big.Int)),
-		Gm: computeSecp256k1Table(),
+		A:        big.NewInt(0),
+		B:        big.NewInt(7),
+		Gx:       g1aff.X.BigInt(new(big.Int)),
+		Gy:       g1aff.Y.BigInt(new(big.Int)),
+		Gm:       computeSecp256k1Table(),
+		Cofactor: big.NewInt(1),
 	}
 }
 
@@ -42,14 +47,84 @@ func GetSecp256k1Params() CurveParams {
 func GetBN254Params() CurveParams {
 	_, _, g1aff, _ := bn254.Generators()
 	return CurveParams{
-		A:  big.NewInt(0),
-		B:  big.NewInt(3),
-		Gx: g1aff.X.BigInt(new(big.Int)),
-		Gy: g1aff.Y.BigInt(new(big.Int)),
-		Gm: computeBN254Table(),
+		A:        big.NewInt(0),
+		B:        big.NewInt(3),
+		Gx:       g1aff.X.BigInt(new(big.Int)),
+		Gy:       g1aff.Y.BigInt(new(big.Int)),
+		Gm:       computeBN254Table(),
+		Cofactor: big.NewInt(1),
 	}
 }
 
+// GetBLS12381Params returns the curve parameters for the curve BLS12-381. When
+// initialising new curve, use the base field [emulated.BLS12381Fp] and scalar
+// field [emulated.BLS12381Fr].
+func GetBLS12381Params() CurveParams {
+	_, _, g1aff, _ := bls12381.Generators()
+	return CurveParams{
+		A:        big.NewInt(0),
+		B:        big.NewInt(4),
+		Gx:       g1aff.X.BigInt(new(big.Int)),
+		Gy:       g1aff.Y.BigInt(new(big.Int)),
+		Gm:       computeBLS12381Table(),
+		Cofactor: hexInt("396c8c005555e1568c00aaab0000aaab"),
+	}
+}
+
+// GetP384Params returns the curve parameters for the curve NIST P-384
+// (secp384r1). When initialising new curve, use the base field
+// [emulated.P384Fp] and scalar field [emulated.P384Fr].
+func GetP384Params() CurveParams {
+	p := elliptic.P384().Params()
+	params := CurveParams{
+		A:        new(big.Int).Sub(p.P, big.NewInt(3)),
+		B:        new(big.Int).Set(p.B),
+		Gx:       new(big.Int).Set(p.Gx),
+		Gy:       new(big.Int).Set(p.Gy),
+		Cofactor: big.NewInt(1),
+	}
+	params.Gm = computeTable(params, p.P, p.N.BitLen())
+	return params
+}
+
+// GetBrainpoolP256r1Params returns the curve parameters for the curve
+// brainpoolP256r1 (RFC 5639). When initialising new curve, use the base field
+// [emulated.BrainpoolP256r1Fp] and scalar field [emulated.BrainpoolP256r1Fr].
+func GetBrainpoolP256r1Params() CurveParams {
+	params := CurveParams{
+		A:        hexInt("7d5a0975fc2c3057eef67530417affe7fb8055c126dc5c6ce94a4b44f330b5d9"),
+		B:        hexInt("26dc5c6ce94a4b44f330b5d9bbd77cbf958416295cf7e1ce6bccdc18ff8c07b6"),
+		Gx:       hexInt("8bd2aeb9cb7e57cb2c4b482ffc81b7afb9de27e1e3bd23c23a4453bd9ace3262"),
+		Gy:       hexInt("547ef835c3dac4fd97f8461a14611dc9c27745132ded8e545c1d54c72f046997"),
+		Cofactor: big.NewInt(1),
+	}
+	params.Gm = computeTable(params, emulated.BrainpoolP256r1Fp{}.Modulus(), emulated.BrainpoolP256r1Fr{}.Modulus().BitLen())
+	return params
+}
+
+// GetBrainpoolP384r1Params returns the curve parameters for the curve
+// brainpoolP384r1 (RFC 5639). When initialising new curve, use the base field
+// [emulated.BrainpoolP384r1Fp] and scalar field [emulated.BrainpoolP384r1Fr].
+func GetBrainpoolP384r1Params() CurveParams {
+	params := CurveParams{
+		A:        hexInt("7bc382c63d8c150c3c72080ace05afa0c2bea28e4fb22787139165efba91f90f8aa5814a503ad4eb04a8c7dd22ce2826"),
+		B:        hexInt("04a8c7dd22ce28268b39b55416f0447c2fb77de107dcd2a62e880ea53eeb62d57cb4390295dbc9943ab78696fa504c11"),
+		Gx:       hexInt("1d1c64f068cf45ffa2a63a81b7c13f6b8847a3e77ef14fe3db7fcafe0cbd10e8e826e03436d646aaef87b2e247d4af1e"),
+		Gy:       hexInt("8abe1d7520f9c2a45cb1eb8e95cfd55262b70b29feec5864e19c054ff99129280e4646217791811142820341263c5315"),
+		Cofactor: big.NewInt(1),
+	}
+	params.Gm = computeTable(params, emulated.BrainpoolP384r1Fp{}.Modulus(), emulated.BrainpoolP384r1Fr{}.Modulus().BitLen())
+	return params
+}
+
+func hexInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex integer " + s)
+	}
+	return v
+}
+
 // GetCurveParams returns suitable curve parameters given the parametric type Base as base field.
 func GetCurveParams[Base emulated.FieldParams]() CurveParams {
 	var t Base
@@ -58,6 +133,14 @@ func GetCurveParams[Base emulated.FieldParams]() CurveParams {
 		return secp256k1Params
 	case "30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47":
 		return bn254Params
+	case "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab":
+		return bls12381Params
+	case "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffff":
+		return p384Params
+	case "a9fb57dba1eea9bc3e660a909d838d726e3bf623d52620282013481d1f6e5377":
+		return brainpoolP256r1Params
+	case "8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b412b1da197fb71123acd3a729901d1a71874700133107ec53":
+		return brainpoolP384r1Params
 	default:
 		panic("no stored parameters")
 	}
@@ -66,9 +149,18 @@ func GetCurveParams[Base emulated.FieldParams]() CurveParams {
 var (
 	secp256k1Params CurveParams
 	bn254Params     CurveParams
+	bls12381Params  CurveParams
+
+	p384Params            CurveParams
+	brainpoolP256r1Params CurveParams
+	brainpoolP384r1Params CurveParams
 )
 
 func init() {
 	secp256k1Params = GetSecp256k1Params()
 	bn254Params = GetBN254Params()
+	bls12381Params = GetBLS12381Params()
+	p384Params = GetP384Params()
+	brainpoolP256r1Params = GetBrainpoolP256r1Params()
+	brainpoolP384r1Params = GetBrainpoolP384r1Params()
 }
@@ -3,6 +3,7 @@ package sw_emulated
 import (
 	"math/big"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1"
 )
@@ -58,3 +59,81 @@ func computeBN254Table() [][2]*big.Int {
 	}
 	return table
 }
+
+func computeBLS12381Table() [][2]*big.Int {
+	Gjac, _, _, _ := bls12381.Generators()
+	table := make([][2]*big.Int, 256)
+	tmp := new(bls12381.G1Jac).Set(&Gjac)
+	aff := new(bls12381.G1Affine)
+	jac := new(bls12381.G1Jac)
+	for i := 1; i < 256; i++ {
+		tmp = tmp.Double(tmp)
+		switch i {
+		case 1, 2:
+			jac.Set(tmp).AddAssign(&Gjac)
+			aff.FromJacobian(jac)
+			table[i-1] = [2]*big.Int{aff.X.BigInt(new(big.Int)), aff.Y.BigInt(new(big.Int))}
+		case 3:
+			jac.Set(tmp).SubAssign(&Gjac)
+			aff.FromJacobian(jac)
+			table[i-1] = [2]*big.Int{aff.X.BigInt(new(big.Int)), aff.Y.BigInt(new(big.Int))}
+			fallthrough
+		default:
+			aff.FromJacobian(tmp)
+			table[i] = [2]*big.Int{aff.X.BigInt(new(big.Int)), aff.Y.BigInt(new(big.Int))}
+		}
+	}
+	return table
+}
+
+// computeTable returns the table of the multiples of the base point of the
+// curve params over the field of modulus p, for scalars of nbBits bits, laid
+// out as the tables above: 3g, 5g, 7g, then [2^i]g for 3 ≤ i < nbBits. It is
+// used for the curves which gnark-crypto doesn't implement.
+func computeTable(params CurveParams, p *big.Int, nbBits int) [][2]*big.Int {
+	double := func(x, y *big.Int) (*big.Int, *big.Int) {
+		// λ = (3x²+a)/2y
+		num := new(big.Int).Mul(x, x)
+		num.Mul(num, big.NewInt(3)).Add(num, params.A)
+		den := new(big.Int).Lsh(y, 1)
+		den.ModInverse(den, p)
+		return affineFromSlope(num.Mul(num, den).Mod(num, p), x, y, x, p)
+	}
+	add := func(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+		// λ = (y2-y1)/(x2-x1)
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		den.Mod(den, p).ModInverse(den, p)
+		return affineFromSlope(num.Mul(num, den).Mod(num, p), x1, y1, x2, p)
+	}
+
+	table := make([][2]*big.Int, nbBits)
+	gx, gy := params.Gx, params.Gy
+	x, y := gx, gy
+	for i := 1; i < nbBits; i++ {
+		x, y = double(x, y)
+		switch i {
+		case 1, 2:
+			ax, ay := add(x, y, gx, gy)
+			table[i-1] = [2]*big.Int{ax, ay}
+		case 3:
+			ax, ay := add(x, y, gx, new(big.Int).Sub(p, gy))
+			table[i-1] = [2]*big.Int{ax, ay}
+			fallthrough
+		default:
+			table[i] = [2]*big.Int{x, y}
+		}
+	}
+	return table
+}
+
+// affineFromSlope returns the sum of (x1, y1) and the point of abscissa x2 on
+// the line of slope λ through (x1, y1).
+func affineFromSlope(λ, x1, y1, x2, p *big.Int) (*big.Int, *big.Int) {
+	// xr = λ²-x1-x2, yr = λ(x1-xr)-y1
+	xr := new(big.Int).Mul(λ, λ)
+	xr.Sub(xr, x1).Sub(xr, x2).Mod(xr, p)
+	yr := new(big.Int).Sub(x1, xr)
+	yr.Mul(yr, λ).Sub(yr, y1).Mod(yr, p)
+	return xr, yr
+}
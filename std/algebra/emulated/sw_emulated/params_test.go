@@ -0,0 +1,117 @@
+package sw_emulated
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// onCurve returns true if (x, y) satisfies the equation of the curve params over
+// the field of modulus p.
+func onCurve(params CurveParams, p, x, y *big.Int) bool {
+	lhs := new(big.Int).Mul(y, y)
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Add(rhs, params.A).Mul(rhs, x).Add(rhs, params.B)
+	return lhs.Sub(lhs, rhs).Mod(lhs, p).Sign() == 0
+}
+
+func TestParams(t *testing.T) {
+	assert := require.New(t)
+
+	for _, tc := range []struct {
+		name       string
+		params     CurveParams
+		p, r       *big.Int
+		fromParams CurveParams
+	}{
+		{"P-384", GetP384Params(), emulated.P384Fp{}.Modulus(), emulated.P384Fr{}.Modulus(), GetCurveParams[emulated.P384Fp]()},
+		{"brainpoolP256r1", GetBrainpoolP256r1Params(), emulated.BrainpoolP256r1Fp{}.Modulus(), emulated.BrainpoolP256r1Fr{}.Modulus(), GetCurveParams[emulated.BrainpoolP256r1Fp]()},
+		{"brainpoolP384r1", GetBrainpoolP384r1Params(), emulated.BrainpoolP384r1Fp{}.Modulus(), emulated.BrainpoolP384r1Fr{}.Modulus(), GetCurveParams[emulated.BrainpoolP384r1Fp]()},
+	} {
+		assert.True(onCurve(tc.params, tc.p, tc.params.Gx, tc.params.Gy), tc.name)
+		assert.Len(tc.params.Gm, tc.r.BitLen(), tc.name)
+		for i := range tc.params.Gm {
+			assert.True(onCurve(tc.params, tc.p, tc.params.Gm[i][0], tc.params.Gm[i][1]), "%s: multiple %d", tc.name, i)
+		}
+		assert.Equal(0, tc.params.Gx.Cmp(tc.fromParams.Gx), tc.name)
+	}
+
+	// the table of P-384 matches the multiples computed by crypto/elliptic
+	curve := elliptic.P384()
+	params := GetP384Params()
+	for i, k := range []int64{3, 5, 7} {
+		x, y := curve.ScalarBaseMult(big.NewInt(k).Bytes())
+		assert.Equal(0, x.Cmp(params.Gm[i][0]))
+		assert.Equal(0, y.Cmp(params.Gm[i][1]))
+	}
+	for i := 3; i < len(params.Gm); i++ {
+		x, y := curve.ScalarBaseMult(new(big.Int).Lsh(big.NewInt(1), uint(i)).Bytes())
+		assert.Equal(0, x.Cmp(params.Gm[i][0]), "[2^%d]g", i)
+		assert.Equal(0, y.Cmp(params.Gm[i][1]), "[2^%d]g", i)
+	}
+}
+
+type scalarMulBaseCircuit[B, S emulated.FieldParams] struct {
+	S emulated.Element[S]
+	R AffinePoint[B]
+}
+
+func (c *scalarMulBaseCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := New[B, S](api, GetCurveParams[B]())
+	if err != nil {
+		return err
+	}
+	curve.AssertIsEqual(curve.ScalarMulBase(&c.S), &c.R)
+	curve.AssertIsEqual(curve.ScalarMul(curve.Generator(), &c.S), &c.R)
+	return nil
+}
+
+func TestScalarMulBaseP384(t *testing.T) {
+	assert := require.New(t)
+
+	s, _ := new(big.Int).SetString("9bd4d3ab0e6f4ba6d8e0e46e1a1d3b4cd1c8ac5a2f25c2e4e0d7cc4ac1a9e8d37b1e9e2f0c6d3c4b5a69788796a5b4c3", 16)
+	x, y := elliptic.P384().ScalarBaseMult(s.Bytes())
+	witness := scalarMulBaseCircuit[emulated.P384Fp, emulated.P384Fr]{
+		S: emulated.ValueOf[emulated.P384Fr](s),
+		R: AffinePoint[emulated.P384Fp]{
+			X: emulated.ValueOf[emulated.P384Fp](x),
+			Y: emulated.ValueOf[emulated.P384Fp](y),
+		},
+	}
+	assert.NoError(test.IsSolved(&scalarMulBaseCircuit[emulated.P384Fp, emulated.P384Fr]{}, &witness, ecc.BN254.ScalarField()))
+}
+
+type onCurveCircuit[B, S emulated.FieldParams] struct {
+	P AffinePoint[B]
+}
+
+func (c *onCurveCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := New[B, S](api, GetCurveParams[B]())
+	if err != nil {
+		return err
+	}
+	curve.AssertIsOnCurve(&c.P)
+	return nil
+}
+
+func TestAssertIsOnCurve(t *testing.T) {
+	assert := require.New(t)
+
+	params := GetP384Params()
+	witness := onCurveCircuit[emulated.P384Fp, emulated.P384Fr]{
+		P: AffinePoint[emulated.P384Fp]{
+			X: emulated.ValueOf[emulated.P384Fp](params.Gm[5][0]),
+			Y: emulated.ValueOf[emulated.P384Fp](params.Gm[5][1]),
+		},
+	}
+	assert.NoError(test.IsSolved(&onCurveCircuit[emulated.P384Fp, emulated.P384Fr]{}, &witness, ecc.BN254.ScalarField()))
+
+	witness.P.Y = emulated.ValueOf[emulated.P384Fp](new(big.Int).Add(params.Gm[5][1], big.NewInt(1)))
+	assert.Error(test.IsSolved(&onCurveCircuit[emulated.P384Fp, emulated.P384Fr]{}, &witness, ecc.BN254.ScalarField()))
+}
@@ -38,6 +38,7 @@ func New[Base, Scalars emulated.FieldParams](api frontend.API, params CurveParam
 		},
 		gm:   emuGm,
 		a:    emulated.ValueOf[Base](params.A),
+		b:    emulated.ValueOf[Base](params.B),
 		addA: params.A.Cmp(big.NewInt(0)) != 0,
 	}, nil
 }
@@ -60,6 +61,7 @@ type Curve[Base, Scalars emulated.FieldParams] struct {
 	gm []AffinePoint[Base]
 
 	a    emulated.Element[Base]
+	b    emulated.Element[Base]
 	addA bool
 }
 
@@ -95,6 +97,19 @@ func (c *Curve[B, S]) AssertIsEqual(p, q *AffinePoint[B]) {
 	c.baseApi.AssertIsEqual(&p.Y, &q.Y)
 }
 
+// AssertIsOnCurve asserts that p satisfies the curve equation Y² = X³ + aX + b.
+// It doesn't check that p is in the subgroup generated by the base point.
+func (c *Curve[B, S]) AssertIsOnCurve(p *AffinePoint[B]) {
+	// y² = x³ + ax + b
+	left := c.baseApi.MulMod(&p.Y, &p.Y)
+	right := c.baseApi.MulMod(&p.X, c.baseApi.MulMod(&p.X, &p.X))
+	if c.addA {
+		right = c.baseApi.Add(right, c.baseApi.MulMod(&c.a, &p.X))
+	}
+	right = c.baseApi.Add(right, &c.b)
+	c.baseApi.AssertIsEqual(left, right)
+}
+
 // Add adds p and q and returns it. It doesn't modify p nor q.
 // It uses incomplete formulas in affine coordinates.
 // The points p and q should be different and nonzero (neutral element).
@@ -0,0 +1,26 @@
+/*
+Package tw_emulated implements elliptic curve group operations in (twisted)
+Edwards form.
+
+The elliptic curve is the set of points (X,Y) satisfying the equation:
+
+	aX² + Y² = 1 + dX²Y²
+
+over some base field 𝐅p for some constants a, d ∈ 𝐅p. Additionally, for every
+curve we also define its generator (base point) G. All these parameters are
+stored in the variable of type [CurveParams].
+
+The package provides the curve parameters of Ed448, see function
+[GetEd448Params], whose base and scalar fields are [emulated.Ed448Fp] and
+[emulated.Ed448Fr]. As a is a square and d is not a square in the base field of
+Ed448, the addition law is complete: it has no exceptional case, unlike the one
+of package [github.com/consensys/gnark/std/algebra/emulated/sw_emulated].
+
+Like package sw_emulated, this package uses type parameters to define the base
+field of the points and variables to define the coefficients of the curve, and
+[GetCurveParams] resolves the curve parameters from the type parameter defining
+the base field. The curves over a native (SNARK) field are implemented by
+package [github.com/consensys/gnark/std/algebra/native/twistededwards], at a
+much lower cost.
+*/
+package tw_emulated
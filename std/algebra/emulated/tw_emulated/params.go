@@ -0,0 +1,53 @@
+package tw_emulated
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// CurveParams defines parameters of an elliptic curve in twisted Edwards form
+// given by the equation
+//
+//	aX² + Y² = 1 + dX²Y²
+//
+// The base point is defined by (Gx, Gy).
+type CurveParams struct {
+	A        *big.Int // a in curve equation
+	D        *big.Int // d in curve equation
+	Gx       *big.Int // base point x
+	Gy       *big.Int // base point y
+	Cofactor *big.Int // cofactor of the subgroup generated by the base point
+}
+
+// GetEd448Params returns the curve parameters for the curve Ed448 (RFC 8032,
+// untwisted: a = 1, d = -39081). When initialising new curve, use the base field
+// [emulated.Ed448Fp] and scalar field [emulated.Ed448Fr].
+func GetEd448Params() CurveParams {
+	gx, _ := new(big.Int).SetString("224580040295924300187604334099896036246789641632564134246125461686950415467406032909029192869357953282578032075146446173674602635247710", 10)
+	gy, _ := new(big.Int).SetString("298819210078481492676017930443930673437544040154080242095928241372331506189835876003536878655418784733982303233503462500531545062832660", 10)
+	return CurveParams{
+		A:        big.NewInt(1),
+		D:        new(big.Int).Sub(emulated.Ed448Fp{}.Modulus(), big.NewInt(39081)),
+		Gx:       gx,
+		Gy:       gy,
+		Cofactor: big.NewInt(4),
+	}
+}
+
+// GetCurveParams returns suitable curve parameters given the parametric type Base as base field.
+func GetCurveParams[Base emulated.FieldParams]() CurveParams {
+	var t Base
+	switch t.Modulus().Text(16) {
+	case "fffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffffffffffffffffffffffffffffffffffffffffffffffffffff":
+		return ed448Params
+	default:
+		panic("no stored parameters")
+	}
+}
+
+var ed448Params CurveParams
+
+func init() {
+	ed448Params = GetEd448Params()
+}
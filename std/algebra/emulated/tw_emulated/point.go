@@ -0,0 +1,221 @@
+package tw_emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// New returns a new [Curve] instance over the base field Base and scalar field
+// Scalars defined by the curve parameters params. It returns an error if
+// initialising the field emulation fails (for example, when the native field is
+// too small) or when the curve parameters are incompatible with the fields.
+func New[Base, Scalars emulated.FieldParams](api frontend.API, params CurveParams) (*Curve[Base, Scalars], error) {
+	ba, err := emulated.NewField[Base](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	sa, err := emulated.NewField[Scalars](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar api: %w", err)
+	}
+	var base Base
+	if params.A.Sign() < 0 || params.A.Cmp(base.Modulus()) >= 0 || params.D.Sign() < 0 || params.D.Cmp(base.Modulus()) >= 0 {
+		return nil, fmt.Errorf("curve coefficients are not reduced modulo the base field")
+	}
+	return &Curve[Base, Scalars]{
+		params:    params,
+		api:       api,
+		baseApi:   ba,
+		scalarApi: sa,
+		g: AffinePoint[Base]{
+			X: emulated.ValueOf[Base](params.Gx),
+			Y: emulated.ValueOf[Base](params.Gy),
+		},
+		a:      emulated.ValueOf[Base](params.A),
+		d:      emulated.ValueOf[Base](params.D),
+		aIsOne: params.A.Cmp(big.NewInt(1)) == 0,
+	}, nil
+}
+
+// Curve is an initialised curve which allows performing group operations.
+type Curve[Base, Scalars emulated.FieldParams] struct {
+	// params is the parameters of the curve
+	params CurveParams
+	// api is the native api, we construct it ourselves to be sure
+	api frontend.API
+	// baseApi is the api for point operations
+	baseApi *emulated.Field[Base]
+	// scalarApi is the api for scalar operations
+	scalarApi *emulated.Field[Scalars]
+
+	// g is the generator (base point) of the curve.
+	g AffinePoint[Base]
+
+	a, d   emulated.Element[Base]
+	aIsOne bool
+}
+
+// Generator returns the base point of the curve. The method does not copy and
+// modifying the returned element leads to undefined behaviour!
+func (c *Curve[B, S]) Generator() *AffinePoint[B] {
+	return &c.g
+}
+
+// AffinePoint represents a point on the elliptic curve. We do not check that
+// the point is actually on the curve, see [Curve.AssertIsOnCurve].
+type AffinePoint[Base emulated.FieldParams] struct {
+	X, Y emulated.Element[Base]
+}
+
+// Neutral returns the neutral element (0, 1) of the curve.
+func (c *Curve[B, S]) Neutral() *AffinePoint[B] {
+	return &AffinePoint[B]{
+		X: *c.baseApi.Zero(),
+		Y: *c.baseApi.One(),
+	}
+}
+
+// mulA returns a*x.
+func (c *Curve[B, S]) mulA(x *emulated.Element[B]) *emulated.Element[B] {
+	if c.aIsOne {
+		return x
+	}
+	return c.baseApi.MulMod(&c.a, x)
+}
+
+// AssertIsOnCurve asserts that p satisfies the equation of the curve. It
+// doesn't check that p is in the subgroup generated by the base point.
+func (c *Curve[B, S]) AssertIsOnCurve(p *AffinePoint[B]) {
+	// aX² + Y² == 1 + dX²Y²
+	xx := c.baseApi.MulMod(&p.X, &p.X)
+	yy := c.baseApi.MulMod(&p.Y, &p.Y)
+	lhs := c.baseApi.Add(c.mulA(xx), yy)
+	rhs := c.baseApi.MulMod(xx, yy)
+	rhs = c.baseApi.MulMod(&c.d, rhs)
+	rhs = c.baseApi.Add(c.baseApi.One(), rhs)
+	c.baseApi.AssertIsEqual(lhs, rhs)
+}
+
+// Neg returns an inverse of p. It doesn't modify p.
+func (c *Curve[B, S]) Neg(p *AffinePoint[B]) *AffinePoint[B] {
+	return &AffinePoint[B]{
+		X: *c.baseApi.Neg(&p.X),
+		Y: p.Y,
+	}
+}
+
+// AssertIsEqual asserts that p and q are the same point.
+func (c *Curve[B, S]) AssertIsEqual(p, q *AffinePoint[B]) {
+	c.baseApi.AssertIsEqual(&p.X, &q.X)
+	c.baseApi.AssertIsEqual(&p.Y, &q.Y)
+}
+
+// Add adds p and q and returns it. It doesn't modify p nor q.
+// It uses the unified formulas in affine coordinates, which are complete when a
+// is a square and d is not, as for Ed448: p and q may be equal or neutral.
+func (c *Curve[B, S]) Add(p, q *AffinePoint[B]) *AffinePoint[B] {
+	// dx1x2y1y2
+	x1x2 := c.baseApi.MulMod(&p.X, &q.X)
+	y1y2 := c.baseApi.MulMod(&p.Y, &q.Y)
+	dxxyy := c.baseApi.MulMod(x1x2, y1y2)
+	dxxyy = c.baseApi.MulMod(&c.d, dxxyy)
+
+	// xr = (x1y2 + y1x2) / (1 + dx1x2y1y2)
+	x1y2 := c.baseApi.MulMod(&p.X, &q.Y)
+	y1x2 := c.baseApi.MulMod(&p.Y, &q.X)
+	xr := c.baseApi.Div(c.baseApi.Add(x1y2, y1x2), c.baseApi.Add(c.baseApi.One(), dxxyy))
+
+	// yr = (y1y2 - ax1x2) / (1 - dx1x2y1y2)
+	yr := c.baseApi.Div(c.baseApi.Sub(y1y2, c.mulA(x1x2)), c.baseApi.Sub(c.baseApi.One(), dxxyy))
+
+	return &AffinePoint[B]{
+		X: *c.baseApi.Reduce(xr),
+		Y: *c.baseApi.Reduce(yr),
+	}
+}
+
+// Double doubles p and return it. It doesn't modify p. It uses the dedicated
+// formulas in affine coordinates, which hold for the points on the curve.
+func (c *Curve[B, S]) Double(p *AffinePoint[B]) *AffinePoint[B] {
+	xx := c.baseApi.MulMod(&p.X, &p.X)
+	yy := c.baseApi.MulMod(&p.Y, &p.Y)
+	axx := c.mulA(xx)
+	axxyy := c.baseApi.Add(axx, yy)
+
+	// xr = 2xy / (ax² + y²)
+	xy := c.baseApi.MulMod(&p.X, &p.Y)
+	xr := c.baseApi.Div(c.baseApi.MulConst(xy, big.NewInt(2)), axxyy)
+
+	// yr = (y² - ax²) / (2 - ax² - y²)
+	two := emulated.ValueOf[B](2)
+	yr := c.baseApi.Div(c.baseApi.Sub(yy, axx), c.baseApi.Sub(&two, axxyy))
+
+	return &AffinePoint[B]{
+		X: *c.baseApi.Reduce(xr),
+		Y: *c.baseApi.Reduce(yr),
+	}
+}
+
+// Select selects between p and q given the selector b. If b == 1, then returns
+// p and q otherwise.
+func (c *Curve[B, S]) Select(b frontend.Variable, p, q *AffinePoint[B]) *AffinePoint[B] {
+	x := c.baseApi.Select(b, &p.X, &q.X)
+	y := c.baseApi.Select(b, &p.Y, &q.Y)
+	return &AffinePoint[B]{
+		X: *x,
+		Y: *y,
+	}
+}
+
+// ScalarMul computes s * p and returns it. It doesn't modify p nor s.
+//
+// It computes the standard little-endian variable-base double-and-add algorithm
+// [HMV04] (Algorithm 3.26). As the addition law is complete, the accumulator
+// starts at the neutral element and no bit is handled apart.
+//
+// [HMV04]: https://link.springer.com/book/10.1007/b97644
+func (c *Curve[B, S]) ScalarMul(p *AffinePoint[B], s *emulated.Element[S]) *AffinePoint[B] {
+	var st S
+	sr := c.scalarApi.Reduce(s)
+	sBits := c.scalarApi.ToBits(sr)
+	n := st.Modulus().BitLen()
+
+	res := c.Neutral()
+	acc := p
+	for i := 0; i < n; i++ {
+		tmp := c.Add(res, acc)
+		res = c.Select(sBits[i], tmp, res)
+		if i+1 < n {
+			acc = c.Double(acc)
+		}
+	}
+	return res
+}
+
+// ScalarMulBase computes s * g and returns it, where g is the fixed generator.
+// It doesn't modify s.
+func (c *Curve[B, S]) ScalarMulBase(s *emulated.Element[S]) *AffinePoint[B] {
+	return c.ScalarMul(c.Generator(), s)
+}
+
+// AssertIsInSubgroup asserts that p is in the subgroup generated by the base
+// point, that is that [r]p is the neutral element where r is the order of the
+// scalar field. p must be on the curve. The check is expensive, as it costs a
+// scalar multiplication.
+func (c *Curve[B, S]) AssertIsInSubgroup(p *AffinePoint[B]) {
+	var st S
+	r := st.Modulus()
+
+	// [r]p, computed bit by bit as r can't be an element of the scalar field
+	res := c.Neutral()
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		res = c.Double(res)
+		if r.Bit(i) == 1 {
+			res = c.Add(res, p)
+		}
+	}
+	c.AssertIsEqual(res, c.Neutral())
+}
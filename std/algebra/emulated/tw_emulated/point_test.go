@@ -0,0 +1,101 @@
+package tw_emulated
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type pointCircuit[B, S emulated.FieldParams] struct {
+	P      AffinePoint[B]
+	Double AffinePoint[B] // [2]P
+	Triple AffinePoint[B] // [3]P
+	S      emulated.Element[S]
+	Mul    AffinePoint[B] // [S]P
+}
+
+func (c *pointCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := New[B, S](api, GetCurveParams[B]())
+	if err != nil {
+		return err
+	}
+	curve.AssertIsOnCurve(&c.P)
+	curve.AssertIsEqual(curve.Double(&c.P), &c.Double)
+	curve.AssertIsEqual(curve.Add(&c.P, &c.P), &c.Double)
+	curve.AssertIsEqual(curve.Add(&c.Double, &c.P), &c.Triple)
+	curve.AssertIsEqual(curve.Add(&c.P, curve.Neutral()), &c.P)
+	curve.AssertIsEqual(curve.Add(&c.P, curve.Neg(&c.P)), curve.Neutral())
+	curve.AssertIsEqual(curve.ScalarMul(&c.P, &c.S), &c.Mul)
+	return nil
+}
+
+func ed448Point(x, y string) AffinePoint[emulated.Ed448Fp] {
+	bx, _ := new(big.Int).SetString(x, 10)
+	by, _ := new(big.Int).SetString(y, 10)
+	return AffinePoint[emulated.Ed448Fp]{
+		X: emulated.ValueOf[emulated.Ed448Fp](bx),
+		Y: emulated.ValueOf[emulated.Ed448Fp](by),
+	}
+}
+
+func TestEd448(t *testing.T) {
+	assert := require.New(t)
+	params := GetEd448Params()
+
+	// the multiples of the base point, computed out of circuit
+	g := AffinePoint[emulated.Ed448Fp]{
+		X: emulated.ValueOf[emulated.Ed448Fp](params.Gx),
+		Y: emulated.ValueOf[emulated.Ed448Fp](params.Gy),
+	}
+	witness := pointCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{
+		P: g,
+		Double: ed448Point("484559149530404593699549205258669689569094240458212040187660132787056912146709081364401144455726350866276831544947397859048262938744149",
+			"494088759867433727674302672526735089350544552303727723746126484473087719117037293890093462157703888342865036477787453078312060500281069"),
+		Triple: ed448Point("23839778817283171003887799738662344287085130522697782688245073320169861206004018274567429238677677920280078599146891901463786155880335",
+			"636046652612779686502873775776967954190574036985351036782021535703553242737829645273154208057988851307101009474686328623630835377952508"),
+		S: emulated.ValueOf[emulated.Ed448Fr](new(big.Int).SetUint64(12345678901234567890)),
+		Mul: ed448Point("452284340401827147489359866913469909161754471135429603908340410282836154407407458878052308322443584637481665364052361623375538884785981",
+			"9174803556118194830522364156867528247962168234561138188738928181250292009891744295931312846844747309827976992201597729155991033910017"),
+	}
+	assert.NoError(test.IsSolved(&pointCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{}, &witness, ecc.BN254.ScalarField()))
+
+	witness.Triple = witness.Double
+	assert.Error(test.IsSolved(&pointCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{}, &witness, ecc.BN254.ScalarField()))
+}
+
+type subgroupCircuit[B, S emulated.FieldParams] struct {
+	P AffinePoint[B]
+}
+
+func (c *subgroupCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := New[B, S](api, GetCurveParams[B]())
+	if err != nil {
+		return err
+	}
+	curve.AssertIsOnCurve(&c.P)
+	curve.AssertIsInSubgroup(&c.P)
+	return nil
+}
+
+func TestEd448Subgroup(t *testing.T) {
+	assert := require.New(t)
+	params := GetEd448Params()
+
+	g := subgroupCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{P: AffinePoint[emulated.Ed448Fp]{
+		X: emulated.ValueOf[emulated.Ed448Fp](params.Gx),
+		Y: emulated.ValueOf[emulated.Ed448Fp](params.Gy),
+	}}
+	assert.NoError(test.IsSolved(&subgroupCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{}, &g, ecc.BN254.ScalarField()))
+
+	// (1, 0) is on the curve, of order 4
+	lowOrder := subgroupCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{P: AffinePoint[emulated.Ed448Fp]{
+		X: emulated.ValueOf[emulated.Ed448Fp](1),
+		Y: emulated.ValueOf[emulated.Ed448Fp](0),
+	}}
+	assert.Error(test.IsSolved(&subgroupCircuit[emulated.Ed448Fp, emulated.Ed448Fr]{}, &lowOrder, ecc.BN254.ScalarField()))
+}
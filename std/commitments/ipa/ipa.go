@@ -0,0 +1,403 @@
+// Package ipa provides ZKP-circuit functions to verify openings of Pedersen
+// vector commitments over the Bandersnatch curve, proved with inner product
+// arguments (IPA).
+//
+// A committed vector a of n field elements, n being a power of two, is seen as
+// the evaluations on the domain {0, ..., n-1} of a polynomial A of degree < n.
+// Its commitment is C = Σ aᵢ·Gᵢ. An opening proves that A(z) = y, z being either
+// an index of the domain (an element of the vector) or a point outside of the
+// domain. With b the vector such that y = ⟨a, b⟩, the verifier computes the
+// challenge w, Q' = w·Q, and C ← C + y·Q', and then for each of the log₂(n)
+// rounds of the proof:
+//
+//	x  = challenge of the round
+//	C ← C + x⁻¹·Lₖ + x·Rₖ
+//	G ← G_lo + x⁻¹·G_hi
+//	b ← b_lo + x⁻¹·b_hi
+//
+// where lo and hi are the first and second halves of the vectors. It finally
+// checks that C = a·G₀ + (a·b₀)·Q' for the last scalar a of the proof.
+//
+// The challenges are derived with a native hash function, chaining the digests:
+// w is the hash of C, z and y, and the challenge of a round the hash of the
+// previous digest and of Lₖ and Rₖ, each truncated to its 252 least significant
+// bits. The coordinates of the points are hashed as the 128 least and most
+// significant bits of their canonical representatives. The points are checked
+// to be on the curve, and are expected to be points of the prime order
+// subgroup.
+//
+// Hence the proofs are those of a prover using the same transcript. In
+// particular, this is not a verifier of the Verkle tree proofs of Ethereum: they
+// are produced by go-ipa with a SHA-256 transcript, and their points are
+// elements of the Banderwagon quotient group.
+//
+// The coordinates of the points, elements of the scalar field of BLS12-381, are
+// emulated, so that the circuits can be defined over BN254. The scalars are
+// native variables, the native field being larger than the scalar field of
+// Bandersnatch.
+package ipa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all the hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{solver.NewHint("ipa_to_native", toNativeHint)}
+}
+
+type (
+	baseField   = emulated.BLS12381Fr
+	scalarField = emulated.BandersnatchFr
+)
+
+// Params are the public parameters of the commitments.
+type Params struct {
+	// Generators are the coordinates of the points G₀, ..., Gₙ₋₁ the vectors
+	// are committed to, n being a power of two. Their discrete logarithms
+	// relative to each other and to Q must be unknown.
+	Generators [][2]*big.Int
+
+	// Q is the coordinates of the point the inner products are committed to.
+	Q [2]*big.Int
+}
+
+// Point is a point of the Bandersnatch curve in twisted Edwards affine
+// coordinates (to be used in gnark circuit).
+type Point struct {
+	X, Y emulated.Element[baseField]
+}
+
+// ValueOf returns the point of coordinates p, to be used in a witness.
+func ValueOf(p [2]*big.Int) Point {
+	return Point{
+		X: emulated.ValueOf[baseField](p[0]),
+		Y: emulated.ValueOf[baseField](p[1]),
+	}
+}
+
+// Proof is an IPA opening proof (to be used in gnark circuit).
+type Proof struct {
+	// L and R are the commitments of the log₂(n) rounds.
+	L, R []Point
+	// A is the last scalar of the folded committed vector.
+	A frontend.Variable
+}
+
+// Verifier verifies openings of commitments to vectors in a circuit defined over
+// a field larger than the scalar field of Bandersnatch, such as the scalar field
+// of BN254.
+type Verifier struct {
+	api     frontend.API
+	base    *emulated.Field[baseField]
+	fr      *emulated.Field[scalarField]
+	a, d    *emulated.Element[baseField] // coefficients of the curve
+	h       hash.Hash
+	params  *Params
+	weights []*big.Int // weights[i] = Πⱼ≠ᵢ (i-j)
+	order   *big.Int
+}
+
+// NewVerifier returns a Verifier of the openings of the commitments with the
+// parameters params, deriving the challenges with h.
+func NewVerifier(api frontend.API, h hash.Hash, params *Params) (*Verifier, error) {
+	n := len(params.Generators)
+	if n < 2 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("the number of generators must be a power of two, got %d", n)
+	}
+	for _, g := range params.Generators {
+		if g[0] == nil || g[1] == nil {
+			return nil, errors.New("nil generator coordinate")
+		}
+	}
+	if params.Q[0] == nil || params.Q[1] == nil {
+		return nil, errors.New("nil Q coordinate")
+	}
+	order := scalarField{}.Modulus()
+	if api.Compiler().Field().Cmp(order) <= 0 {
+		return nil, errors.New("the native field is smaller than the scalar field of Bandersnatch")
+	}
+	base, err := emulated.NewField[baseField](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	f, err := emulated.NewField[scalarField](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	curve := bandersnatch.GetEdwardsCurve()
+	weights := make([]*big.Int, n)
+	for i := range weights {
+		weights[i] = big.NewInt(1)
+		for j := 0; j < n; j++ {
+			if j != i {
+				weights[i].Mul(weights[i], big.NewInt(int64(i-j)))
+				weights[i].Mod(weights[i], order)
+			}
+		}
+	}
+	return &Verifier{
+		api:     api,
+		base:    base,
+		fr:      f,
+		a:       base.NewElement(curve.A.BigInt(new(big.Int))),
+		d:       base.NewElement(curve.D.BigInt(new(big.Int))),
+		h:       h,
+		params:  params,
+		weights: weights,
+		order:   order,
+	}, nil
+}
+
+// VerifyOpening asserts that proof is a valid proof that the element index of
+// the vector committed to in c is value.
+func (v *Verifier) VerifyOpening(c *Point, index, value frontend.Variable, proof *Proof) error {
+	nbRounds := mbits.Len(uint(len(v.params.Generators))) - 1
+	indexBits := bits.ToBinary(v.api, index, bits.WithNbDigits(nbRounds))
+
+	// b is the unit vector of index, which folds to the product of the inverses
+	// of the challenges of the rounds in which index is in the high half.
+	return v.verify(c, index, value, proof, func(xInv []*emulated.Element[scalarField]) *emulated.Element[scalarField] {
+		b := v.fr.One()
+		for k := range xInv {
+			b = v.fr.MulMod(b, v.fr.Select(indexBits[nbRounds-1-k], xInv[k], v.fr.One()))
+		}
+		return b
+	})
+}
+
+// VerifyEvaluation asserts that proof is a valid proof that the polynomial of
+// the evaluations committed to in c evaluates to y at z. z must not be in the
+// domain {0, ..., n-1}, see VerifyOpening for these points.
+func (v *Verifier) VerifyEvaluation(c *Point, z, y frontend.Variable, proof *Proof) error {
+	// bᵢ are the barycentric weights A(z)/(A'(i)·(z-i)), with A = Π (X-i).
+	return v.verify(c, z, y, proof, func(xInv []*emulated.Element[scalarField]) *emulated.Element[scalarField] {
+		ze := v.toEmulated(z)
+		n := len(v.params.Generators)
+		diffs := make([]*emulated.Element[scalarField], n)
+		az := v.fr.One()
+		for i := range diffs {
+			diffs[i] = v.fr.Sub(ze, v.fr.NewElement(i))
+			az = v.fr.MulMod(az, diffs[i])
+		}
+		b := make([]*emulated.Element[scalarField], n)
+		for i := range b {
+			b[i] = v.fr.Div(az, v.fr.MulMod(diffs[i], v.fr.NewElement(v.weights[i])))
+		}
+		for k := range xInv {
+			half := len(b) / 2
+			for i := 0; i < half; i++ {
+				b[i] = v.fr.Add(b[i], v.fr.MulMod(b[half+i], xInv[k]))
+			}
+			b = b[:half]
+		}
+		return b[0]
+	})
+}
+
+// verify asserts that proof is a valid proof that ⟨a, b⟩ = y for the vector a
+// committed to in c, foldedB returning the folded b from the inverses of the
+// challenges of the rounds.
+func (v *Verifier) verify(c *Point, z, y frontend.Variable, proof *Proof, foldedB func(xInv []*emulated.Element[scalarField]) *emulated.Element[scalarField]) error {
+	n := len(v.params.Generators)
+	nbRounds := mbits.Len(uint(n)) - 1
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return fmt.Errorf("proof has %d left and %d right commitments, expected %d", len(proof.L), len(proof.R), nbRounds)
+	}
+	v.assertIsOnCurve(c)
+	v.assertIsScalar(y)
+
+	v.h.Reset()
+	v.h.Write(v.coordinates(c)...)
+	v.h.Write(z, y)
+	digest := v.h.Sum()
+	w, _ := v.challenge(digest)
+	q := v.scalarMul(v.constantPoint(v.params.Q), w)
+	acc := v.add(c, v.scalarMul(q, y))
+
+	g := make([]*Point, n)
+	for i := range g {
+		g[i] = v.constantPoint(v.params.Generators[i])
+	}
+	xInv := make([]*emulated.Element[scalarField], nbRounds)
+	for k := 0; k < nbRounds; k++ {
+		l, r := &proof.L[k], &proof.R[k]
+		v.assertIsOnCurve(l)
+		v.assertIsOnCurve(r)
+
+		v.h.Reset()
+		v.h.Write(digest)
+		v.h.Write(v.coordinates(l)...)
+		v.h.Write(v.coordinates(r)...)
+		digest = v.h.Sum()
+		x, xe := v.challenge(digest)
+		xInv[k] = v.fr.Inverse(xe)
+		xInvNative := v.toNative(xInv[k])
+
+		acc = v.add(acc, v.doubleBaseScalarMul(l, r, xInvNative, x))
+		half := len(g) / 2
+		for i := 0; i < half; i++ {
+			g[i] = v.add(g[i], v.scalarMul(g[half+i], xInvNative))
+		}
+		g = g[:half]
+	}
+
+	ab := v.toNative(v.fr.MulMod(v.toEmulated(proof.A), foldedB(xInv)))
+	res := v.doubleBaseScalarMul(g[0], q, proof.A, ab)
+	v.base.AssertIsEqual(&res.X, &acc.X)
+	v.base.AssertIsEqual(&res.Y, &acc.Y)
+	return nil
+}
+
+// challenge returns the challenge of digest, as a native variable and as an
+// element of the scalar field: its 252 least significant bits.
+func (v *Verifier) challenge(digest frontend.Variable) (frontend.Variable, *emulated.Element[scalarField]) {
+	nbBits := v.order.BitLen() - 1
+	// the bits of the digest are the ones of its canonical value, so that the
+	// truncation is unique
+	bs := bits.ToBinary(v.api, digest)
+	bits.AssertIsCanonical(v.api, bs)
+	bs = bs[:nbBits]
+	return bits.FromBinary(v.api, bs), v.fr.FromBits(bs...)
+}
+
+// assertIsScalar asserts that x is a canonical element of the scalar field.
+func (v *Verifier) assertIsScalar(x frontend.Variable) {
+	v.api.AssertIsLessOrEqual(x, new(big.Int).Sub(v.order, big.NewInt(1)))
+}
+
+// toEmulated returns the element of the scalar field of x, asserting that x is
+// canonical.
+func (v *Verifier) toEmulated(x frontend.Variable) *emulated.Element[scalarField] {
+	v.assertIsScalar(x)
+	return v.fr.FromBits(bits.ToBinary(v.api, x, bits.WithNbDigits(v.order.BitLen()))...)
+}
+
+// toNative returns the canonical representative of e as a native variable.
+func (v *Verifier) toNative(e *emulated.Element[scalarField]) frontend.Variable {
+	res, err := v.api.Compiler().NewHint(solver.NewHint("ipa_to_native", toNativeHint), 1, e.Limbs...)
+	if err != nil {
+		panic(fmt.Sprintf("ipa to native: %v", err))
+	}
+	v.fr.AssertIsEqual(v.toEmulated(res[0]), e)
+	return res[0]
+}
+
+// toNativeHint returns the value of the element of the Bandersnatch scalar field
+// of limbs inputs, reduced modulo the order.
+func toNativeHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(outputs) != 1 {
+		return errors.New("expecting one output")
+	}
+	var params scalarField
+	res := new(big.Int)
+	for i := len(inputs) - 1; i >= 0; i-- {
+		res.Lsh(res, params.BitsPerLimb())
+		res.Add(res, inputs[i])
+	}
+	outputs[0].Mod(res, params.Modulus())
+	return nil
+}
+
+// coordinates returns the 128 least and most significant bits of the canonical
+// representatives of the coordinates of p, as native variables to be hashed.
+func (v *Verifier) coordinates(p *Point) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 4)
+	for _, e := range []*emulated.Element[baseField]{&p.X, &p.Y} {
+		bs := v.base.ToBytes(e)
+		for half := 0; half < 2; half++ {
+			var chunk frontend.Variable = 0
+			for i := 15; i >= 0; i-- {
+				chunk = v.api.Add(v.api.Mul(chunk, 256), bs[16*half+i])
+			}
+			res = append(res, chunk)
+		}
+	}
+	return res
+}
+
+// constantPoint returns the point of coordinates p, a constant of the circuit.
+func (v *Verifier) constantPoint(p [2]*big.Int) *Point {
+	return &Point{X: *v.base.NewElement(p[0]), Y: *v.base.NewElement(p[1])}
+}
+
+// assertIsOnCurve asserts that p satisfies the equation of the curve
+// aX² + Y² = 1 + dX²Y².
+func (v *Verifier) assertIsOnCurve(p *Point) {
+	xx := v.base.MulMod(&p.X, &p.X)
+	yy := v.base.MulMod(&p.Y, &p.Y)
+	lhs := v.base.Add(v.base.MulMod(v.a, xx), yy)
+	rhs := v.base.Add(v.base.One(), v.base.MulMod(v.d, v.base.MulMod(xx, yy)))
+	v.base.AssertIsEqual(lhs, rhs)
+}
+
+// add returns p + q with the unified formulas in affine coordinates, which
+// hold for all the points of the prime order subgroup.
+func (v *Verifier) add(p, q *Point) *Point {
+	x1x2 := v.base.MulMod(&p.X, &q.X)
+	y1y2 := v.base.MulMod(&p.Y, &q.Y)
+	dxxyy := v.base.MulMod(v.d, v.base.MulMod(x1x2, y1y2))
+
+	// xr = (x1y2 + y1x2) / (1 + dx1x2y1y2)
+	x1y2 := v.base.MulMod(&p.X, &q.Y)
+	y1x2 := v.base.MulMod(&p.Y, &q.X)
+	xr := v.base.Div(v.base.Add(x1y2, y1x2), v.base.Add(v.base.One(), dxxyy))
+
+	// yr = (y1y2 - ax1x2) / (1 - dx1x2y1y2)
+	yr := v.base.Div(v.base.Sub(y1y2, v.base.MulMod(v.a, x1x2)), v.base.Sub(v.base.One(), dxxyy))
+
+	return &Point{X: *v.base.Reduce(xr), Y: *v.base.Reduce(yr)}
+}
+
+// selectPoint returns p if b is 1, q otherwise.
+func (v *Verifier) selectPoint(b frontend.Variable, p, q *Point) *Point {
+	return &Point{X: *v.base.Select(b, &p.X, &q.X), Y: *v.base.Select(b, &p.Y, &q.Y)}
+}
+
+// neutral returns the neutral element (0, 1) of the curve.
+func (v *Verifier) neutral() *Point {
+	return &Point{X: *v.base.Zero(), Y: *v.base.One()}
+}
+
+// scalarMul returns s·p, s being a canonical element of the scalar field.
+func (v *Verifier) scalarMul(p *Point, s frontend.Variable) *Point {
+	sBits := bits.ToBinary(v.api, s, bits.WithNbDigits(v.order.BitLen()))
+	res := v.neutral()
+	for i := len(sBits) - 1; i >= 0; i-- {
+		res = v.add(res, res)
+		res = v.selectPoint(sBits[i], v.add(res, p), res)
+	}
+	return res
+}
+
+// doubleBaseScalarMul returns s1·p1 + s2·p2, s1 and s2 being canonical elements
+// of the scalar field, sharing the doublings.
+func (v *Verifier) doubleBaseScalarMul(p1, p2 *Point, s1, s2 frontend.Variable) *Point {
+	s1Bits := bits.ToBinary(v.api, s1, bits.WithNbDigits(v.order.BitLen()))
+	s2Bits := bits.ToBinary(v.api, s2, bits.WithNbDigits(v.order.BitLen()))
+	p12 := v.add(p1, p2)
+	zero := v.neutral()
+	res := v.neutral()
+	for i := len(s1Bits) - 1; i >= 0; i-- {
+		res = v.add(res, res)
+		res = v.add(res, &Point{
+			X: *v.base.Lookup2(s1Bits[i], s2Bits[i], &zero.X, &p1.X, &p2.X, &p12.X),
+			Y: *v.base.Lookup2(s1Bits[i], s2Bits[i], &zero.Y, &p1.Y, &p2.Y, &p12.Y),
+		})
+	}
+	return res
+}
@@ -0,0 +1,250 @@
+package ipa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// testSetup is a reference prover of the openings verified by Verifier.
+type testSetup struct {
+	g      []bandersnatch.PointAffine
+	q      bandersnatch.PointAffine
+	order  *big.Int
+	params *Params
+}
+
+// newTestSetup returns a setup with n generators of known discrete logarithms,
+// which is only sound for tests.
+func newTestSetup(n int) *testSetup {
+	curve := bandersnatch.GetEdwardsCurve()
+	s := &testSetup{g: make([]bandersnatch.PointAffine, n), order: &curve.Order, params: &Params{}}
+	for i := range s.g {
+		s.g[i].ScalarMultiplication(&curve.Base, big.NewInt(int64(1000003*i+17)))
+		s.params.Generators = append(s.params.Generators, coordinates(&s.g[i]))
+	}
+	s.q.ScalarMultiplication(&curve.Base, big.NewInt(4242))
+	s.params.Q = coordinates(&s.q)
+	return s
+}
+
+func coordinates(p *bandersnatch.PointAffine) [2]*big.Int {
+	return [2]*big.Int{p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int))}
+}
+
+// hashedCoordinates returns the 128 least and most significant bits of the
+// coordinates of p, as hashed by Verifier.
+func hashedCoordinates(p *bandersnatch.PointAffine) []*big.Int {
+	var res []*big.Int
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	for _, c := range coordinates(p) {
+		res = append(res, new(big.Int).And(c, mask), new(big.Int).Rsh(c, 128))
+	}
+	return res
+}
+
+// hashValues returns the native MiMC hash of the values.
+func hashValues(values ...*big.Int) *big.Int {
+	h := mimc.NewMiMC()
+	for _, v := range values {
+		var e fr.Element
+		e.SetBigInt(v)
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// challenge returns the 252 least significant bits of digest.
+func (s *testSetup) challenge(digest *big.Int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(s.order.BitLen()-1))
+	return mask.And(digest, mask.Sub(mask, big.NewInt(1)))
+}
+
+// msm returns Σ scalars[i]·points[i] + ip·q.
+func (s *testSetup) msm(scalars []*big.Int, points []bandersnatch.PointAffine, ip *big.Int, q *bandersnatch.PointAffine) bandersnatch.PointAffine {
+	var res, tmp bandersnatch.PointAffine
+	res.ScalarMultiplication(q, ip)
+	for i := range scalars {
+		tmp.ScalarMultiplication(&points[i], scalars[i])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+func (s *testSetup) innerProduct(a, b []*big.Int) *big.Int {
+	res := new(big.Int)
+	for i := range a {
+		res.Add(res, new(big.Int).Mul(a[i], b[i]))
+	}
+	return res.Mod(res, s.order)
+}
+
+// fold returns lo + x·hi.
+func (s *testSetup) fold(lo, hi []*big.Int, x *big.Int) []*big.Int {
+	res := make([]*big.Int, len(lo))
+	for i := range res {
+		res[i] = new(big.Int).Mul(hi[i], x)
+		res[i].Add(res[i], lo[i]).Mod(res[i], s.order)
+	}
+	return res
+}
+
+// prove returns the commitment of a and the proof that ⟨a, b⟩ = y, z being the
+// point absorbed in the transcript.
+func (s *testSetup) prove(a, b []*big.Int, z *big.Int) (bandersnatch.PointAffine, *big.Int, Proof) {
+	zero := new(big.Int)
+	c := s.msm(a, s.g, zero, &s.q)
+	y := s.innerProduct(a, b)
+
+	digest := hashValues(append(hashedCoordinates(&c), z, y)...)
+	var q bandersnatch.PointAffine
+	q.ScalarMultiplication(&s.q, s.challenge(digest))
+
+	var proof Proof
+	g := append([]bandersnatch.PointAffine{}, s.g...)
+	for len(a) > 1 {
+		half := len(a) / 2
+		l := s.msm(a[:half], g[half:], s.innerProduct(a[:half], b[half:]), &q)
+		r := s.msm(a[half:], g[:half], s.innerProduct(a[half:], b[:half]), &q)
+		proof.L = append(proof.L, ValueOf(coordinates(&l)))
+		proof.R = append(proof.R, ValueOf(coordinates(&r)))
+
+		values := append([]*big.Int{digest}, hashedCoordinates(&l)...)
+		digest = hashValues(append(values, hashedCoordinates(&r)...)...)
+		x := s.challenge(digest)
+		xInv := new(big.Int).ModInverse(x, s.order)
+
+		a = s.fold(a[:half], a[half:], x)
+		b = s.fold(b[:half], b[half:], xInv)
+		for i := 0; i < half; i++ {
+			var tmp bandersnatch.PointAffine
+			tmp.ScalarMultiplication(&g[half+i], xInv)
+			g[i].Add(&g[i], &tmp)
+		}
+		g = g[:half]
+	}
+	proof.A = a[0]
+	return c, y, proof
+}
+
+func emptyProof(nbRounds int) Proof {
+	return Proof{L: make([]Point, nbRounds), R: make([]Point, nbRounds)}
+}
+
+type openingCircuit struct {
+	params *Params
+
+	C     Point             `gnark:",public"`
+	Index frontend.Variable `gnark:",public"`
+	Value frontend.Variable `gnark:",public"`
+	Proof Proof
+}
+
+func (c *openingCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier(api, &h, c.params)
+	if err != nil {
+		return err
+	}
+	return v.VerifyOpening(&c.C, c.Index, c.Value, &c.Proof)
+}
+
+type evaluationCircuit struct {
+	params *Params
+
+	C     Point             `gnark:",public"`
+	Z, Y  frontend.Variable `gnark:",public"`
+	Proof Proof
+}
+
+func (c *evaluationCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier(api, &h, c.params)
+	if err != nil {
+		return err
+	}
+	return v.VerifyEvaluation(&c.C, c.Z, c.Y, &c.Proof)
+}
+
+func testVector(n int) []*big.Int {
+	a := make([]*big.Int, n)
+	for i := range a {
+		a[i] = big.NewInt(int64(i*i + 7))
+	}
+	return a
+}
+
+func TestVerifyOpening(t *testing.T) {
+	assert := require.New(t)
+	const n, nbRounds, index = 4, 2, 2
+	s := newTestSetup(n)
+	a := testVector(n)
+
+	b := make([]*big.Int, n)
+	for i := range b {
+		b[i] = new(big.Int)
+	}
+	b[index].SetInt64(1)
+	c, y, proof := s.prove(a, b, big.NewInt(index))
+
+	circuit := &openingCircuit{params: s.params, Proof: emptyProof(nbRounds)}
+	witness := &openingCircuit{C: ValueOf(coordinates(&c)), Index: index, Value: y, Proof: proof}
+	assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	// value of another element
+	witness.Value = a[index+1]
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	// opening of another index
+	witness.Value, witness.Index = y, index+1
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
+
+func TestVerifyEvaluation(t *testing.T) {
+	assert := require.New(t)
+	const n, nbRounds = 4, 2
+	s := newTestSetup(n)
+	a := testVector(n)
+
+	// bᵢ = A(z)/(A'(i)·(z-i))
+	z := big.NewInt(123456789)
+	b := make([]*big.Int, n)
+	az := big.NewInt(1)
+	for i := 0; i < n; i++ {
+		az.Mul(az, new(big.Int).Sub(z, big.NewInt(int64(i)))).Mod(az, s.order)
+	}
+	for i := range b {
+		d := new(big.Int).Sub(z, big.NewInt(int64(i)))
+		for j := 0; j < n; j++ {
+			if j != i {
+				d.Mul(d, big.NewInt(int64(i-j))).Mod(d, s.order)
+			}
+		}
+		b[i] = d.ModInverse(d, s.order)
+		b[i].Mul(b[i], az).Mod(b[i], s.order)
+	}
+	c, y, proof := s.prove(a, b, z)
+
+	circuit := &evaluationCircuit{params: s.params, Proof: emptyProof(nbRounds)}
+	witness := &evaluationCircuit{C: ValueOf(coordinates(&c)), Z: z, Y: y, Proof: proof}
+	assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	// wrong evaluation
+	witness.Y = new(big.Int).Add(y, big.NewInt(1))
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
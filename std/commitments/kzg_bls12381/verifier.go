@@ -0,0 +1,171 @@
+// Package kzg_bls12381 provides a ZKP-circuit function to verify BLS12_381 KZG
+// opening proofs, using the emulated BLS12_381 pairing.
+//
+// This is the verification of the KZG proofs of the blobs of EIP-4844
+// (verify_kzg_proof): with the commitment of a blob, an evaluation point z and a
+// value y, a circuit can bind to data made available on Ethereum. The points
+// are given in affine coordinates, their decompression from the 48 bytes of the
+// EIP-4844 encoding being left to the caller, as well as the derivation of the
+// versioned hash of the commitment and of z.
+package kzg_bls12381
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Scalar is an element of the scalar field of BLS12_381, such as the evaluation
+// points and values of the polynomials.
+type Scalar = emulated.Element[emulated.BLS12381Fr]
+
+// Digest commitment of a polynomial.
+type Digest = sw_bls12381.G1Affine
+
+// VK verification key (G2 part of SRS)
+type VK struct {
+	G2 [2]sw_bls12381.G2Affine // [G₂, [τ]G₂]
+}
+
+// NewVK returns the verification key of the SRS of G2 part [G₂, [τ]G₂], for
+// instance the first two G2 points of the trusted setup of EIP-4844.
+func NewVK(g2 [2]bls12381.G2Affine) VK {
+	return VK{G2: [2]sw_bls12381.G2Affine{sw_bls12381.NewG2Affine(g2[0]), sw_bls12381.NewG2Affine(g2[1])}}
+}
+
+// OpeningProof KZG proof for opening at a single point.
+type OpeningProof struct {
+	// H quotient polynomial (f - f(z))/(x-z)
+	H sw_bls12381.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue Scalar
+}
+
+// Verify verifies a KZG opening proof at a single point, asserting that
+//
+//	e([f(τ)]G₁ - [f(z)]G₁ + [z]H, G₂) · e(-H, [τ]G₂) = 1
+//
+// The point at infinity is encoded (0, 0), as in gnark-crypto. The verification
+// is complete: the commitment and the proof may be the point at infinity (the
+// commitments of the zero and of the constant polynomials), and the point and
+// the claimed value may take any value, zero included. The exceptional cases of
+// the incomplete formulas of G1 are handled by selections.
+func Verify(api frontend.API, commitment Digest, proof OpeningProof, point Scalar, vk VK) error {
+	g, err := newG1(api)
+	if err != nil {
+		return err
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+
+	// [f(τ) - f(z)]G₁ + [z]H, the point at infinity H being substituted by the
+	// generator in the scalar multiplication
+	hIsInfinity := g.isInfinity(&proof.H)
+	h := g.curve.Select(hIsInfinity, g.curve.Generator(), &proof.H)
+	claimedValueG1 := g.scalarMul(g.curve.Generator(), &proof.ClaimedValue, g.curve.ScalarMulBase)
+	zH := g.scalarMul(h, &point, func(s *Scalar) *sw_bls12381.G1Affine { return g.curve.ScalarMul(h, s) })
+	zH = g.curve.Select(hIsInfinity, g.infinity(), zH)
+	lhs := g.add(g.add(&commitment, zH), g.curve.Neg(claimedValueG1))
+
+	// When H is the point at infinity (f is constant), the pairing equation
+	// holds if and only if lhs is the point at infinity. Otherwise, lhs can't be
+	// the point at infinity, as e(-H, [τ]G₂) ≠ 1. In the former case, the pairing
+	// is computed on the generator instead and its value ignored.
+	api.AssertIsEqual(g.isInfinity(lhs), hIsInfinity)
+	lhs = g.curve.Select(hIsInfinity, g.curve.Generator(), lhs)
+	negH := g.curve.Neg(h)
+
+	res, err := pairing.Pair(
+		[]*sw_bls12381.G1Affine{lhs, negH},
+		[]*sw_bls12381.G2Affine{&vk.G2[0], &vk.G2[1]},
+	)
+	if err != nil {
+		return fmt.Errorf("pair: %w", err)
+	}
+	one := pairing.Ext12.One()
+	pairing.AssertIsEqual(pairing.Ext12.Select(hIsInfinity, one, res), one)
+	return nil
+}
+
+// g1 completes the group law of the emulated G1 with the point at infinity,
+// encoded (0, 0), and with the exceptional cases of the incomplete formulas of
+// sw_emulated.
+type g1 struct {
+	api   frontend.API
+	curve *sw_emulated.Curve[emulated.BLS12381Fp, emulated.BLS12381Fr]
+	fp    *emulated.Field[emulated.BLS12381Fp]
+	fr    *emulated.Field[emulated.BLS12381Fr]
+}
+
+func newG1(api frontend.API) (*g1, error) {
+	curve, err := sw_emulated.New[emulated.BLS12381Fp, emulated.BLS12381Fr](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	fp, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	fr, err := emulated.NewField[emulated.BLS12381Fr](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	return &g1{api: api, curve: curve, fp: fp, fr: fr}, nil
+}
+
+// infinity returns the point at infinity.
+func (g *g1) infinity() *sw_bls12381.G1Affine {
+	return &sw_bls12381.G1Affine{X: *g.fp.Zero(), Y: *g.fp.Zero()}
+}
+
+// isInfinity returns 1 if p is the point at infinity and 0 otherwise.
+func (g *g1) isInfinity(p *sw_bls12381.G1Affine) frontend.Variable {
+	return g.api.And(g.fp.IsZero(&p.X), g.fp.IsZero(&p.Y))
+}
+
+// add returns p + q. The addition of sw_emulated is only defined for points
+// which are not the point at infinity and have different x coordinates: it is
+// given the generator and [3]G₁ instead in the other cases, and the result is
+// selected among q, p, [2]p and the point at infinity.
+func (g *g1) add(p, q *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+	pIsInfinity := g.isInfinity(p)
+	qIsInfinity := g.isInfinity(q)
+	sameX := g.fp.IsZero(g.fp.Sub(&p.X, &q.X))
+	sameY := g.fp.IsZero(g.fp.Sub(&p.Y, &q.Y))
+	exceptional := g.api.Or(g.api.Or(pIsInfinity, qIsInfinity), sameX)
+
+	sum := g.curve.Add(
+		g.curve.Select(exceptional, g.curve.Generator(), p),
+		g.curve.Select(exceptional, &g.curve.GeneratorMultiples()[0], q),
+	)
+	double := g.curve.Double(g.curve.Select(pIsInfinity, g.curve.Generator(), p))
+
+	res := g.curve.Select(sameX, g.curve.Select(sameY, double, g.infinity()), sum)
+	res = g.curve.Select(qIsInfinity, p, res)
+	return g.curve.Select(pIsInfinity, q, res)
+}
+
+// scalarMul returns [s]p, with mul computing [s]p for the scalars other than 0,
+// 1 and -1. The scalar multiplications of sw_emulated skip the least significant
+// bit and subtract p at the end, which is undefined for these scalars: mul is
+// given 2 instead and the result is selected among the point at infinity, p and
+// -p. The point p must not be the point at infinity.
+func (g *g1) scalarMul(p *sw_bls12381.G1Affine, s *Scalar, mul func(*Scalar) *sw_bls12381.G1Affine) *sw_bls12381.G1Affine {
+	one := g.fr.One()
+	isZero := g.fr.IsZero(s)
+	isOne := g.fr.IsZero(g.fr.Sub(s, one))
+	isMinusOne := g.fr.IsZero(g.fr.Add(s, one))
+	exceptional := g.api.Or(g.api.Or(isZero, isOne), isMinusOne)
+
+	res := mul(g.fr.Select(exceptional, g.fr.NewElement(2), s))
+	res = g.curve.Select(isMinusOne, g.curve.Neg(p), res)
+	res = g.curve.Select(isOne, p, res)
+	return g.curve.Select(isZero, g.infinity(), res)
+}
@@ -0,0 +1,138 @@
+package kzg_bls12381
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type verifyCircuit struct {
+	VK         VK
+	Commitment Digest
+	Point      Scalar
+	Proof      OpeningProof
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	return Verify(api, c.Commitment, c.Proof, c.Point, c.VK)
+}
+
+func TestVerify(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+	r := ecc.BLS12_381.ScalarField()
+
+	// f = 3 + 5X + 7X², opened at z with a toy SRS of known τ
+	tau, z := big.NewInt(123456789), big.NewInt(42)
+	f := func(x *big.Int) *big.Int {
+		res := new(big.Int).Mul(x, big.NewInt(7))
+		res.Add(res, big.NewInt(5)).Mul(res, x).Add(res, big.NewInt(3))
+		return res.Mod(res, r)
+	}
+	y := f(z)
+	h := new(big.Int).Sub(f(tau), y)
+	h.Mul(h, new(big.Int).ModInverse(new(big.Int).Sub(tau, z), r)).Mod(h, r)
+
+	var commitment, quotient bls12381.G1Affine
+	var tauG2 bls12381.G2Affine
+	commitment.ScalarMultiplication(&g1, f(tau))
+	quotient.ScalarMultiplication(&g1, h)
+	tauG2.ScalarMultiplication(&g2, tau)
+
+	witness := &verifyCircuit{
+		VK:         NewVK([2]bls12381.G2Affine{g2, tauG2}),
+		Commitment: sw_bls12381.NewG1Affine(commitment),
+		Point:      emulated.ValueOf[emulated.BLS12381Fr](z),
+		Proof: OpeningProof{
+			H:            sw_bls12381.NewG1Affine(quotient),
+			ClaimedValue: emulated.ValueOf[emulated.BLS12381Fr](y),
+		},
+	}
+	err := test.IsSolved(&verifyCircuit{}, witness, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+
+	witness.Proof.ClaimedValue = emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).Add(y, big.NewInt(1)))
+	err = test.IsSolved(&verifyCircuit{}, witness, ecc.BN254.ScalarField())
+	require.Error(t, err)
+}
+
+// eip4844G2 are the first two G2 points of the trusted setup of EIP-4844,
+// [G₂, [τ]G₂], compressed.
+var eip4844G2 = [2]string{
+	"93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
+	"b5bfd7dd8cdeb128843bc287230af38926187075cbfbefa81009a2ce615ac53d2914e5870cb452d2afaaab24f3499f72185cbfee53492714734429b7b38608e23926c911cceceac9a36851477ba4c60b087041de621000edc98edada20c1def2",
+}
+
+// TestVerifyEIP4844 runs the verify_kzg_proof test vectors of c-kzg-4844
+// v1.0.0 in testdata, a selection covering the zero and constant polynomials
+// (commitments and proofs at infinity) and the scalars 0, 1 and -1. The vectors
+// with invalid encodings are left out, the decompression being the caller's.
+func TestVerifyEIP4844(t *testing.T) {
+	if testing.Short() {
+		t.Skip("solves an emulated pairing per vector")
+	}
+	assert := require.New(t)
+
+	data, err := os.ReadFile("testdata/verify_kzg_proof.json")
+	assert.NoError(err)
+	var vectors []struct {
+		Name  string
+		Input struct {
+			Commitment, Z, Y, Proof string
+		}
+		Output bool
+	}
+	assert.NoError(json.Unmarshal(data, &vectors))
+
+	var g2 [2]bls12381.G2Affine
+	for i := range g2 {
+		b, err := hex.DecodeString(eip4844G2[i])
+		assert.NoError(err)
+		_, err = g2[i].SetBytes(b)
+		assert.NoError(err)
+	}
+	vk := NewVK(g2)
+
+	decode := func(s string) []byte {
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		assert.NoError(err)
+		return b
+	}
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			t.Parallel()
+			var commitment, proof bls12381.G1Affine
+			_, err := commitment.SetBytes(decode(v.Input.Commitment))
+			require.NoError(t, err)
+			_, err = proof.SetBytes(decode(v.Input.Proof))
+			require.NoError(t, err)
+
+			witness := &verifyCircuit{
+				VK:         vk,
+				Commitment: sw_bls12381.NewG1Affine(commitment),
+				Point:      emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(decode(v.Input.Z))),
+				Proof: OpeningProof{
+					H:            sw_bls12381.NewG1Affine(proof),
+					ClaimedValue: emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(decode(v.Input.Y))),
+				},
+			}
+			err = test.IsSolved(&verifyCircuit{}, witness, ecc.BN254.ScalarField())
+			if v.Output {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Package pedersen provides ZKP-circuit functions to open Pedersen commitments on
+// a twisted Edwards curve defined over the native field, and to prove that the
+// committed value is in a range, with their native counterparts.
+//
+// A commitment to the value v with the blinding factor r is the point
+//
+//	C = v·G + r·H
+//
+// where G is the base point of the curve and H a point of unknown discrete
+// logarithm derived by hashing to the curve (see NewParams). It hides v, and
+// binds to v modulo the order of the subgroup of G.
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// DefaultSeed is the seed of the generator H of the parameters returned by
+// NewParams without seed.
+const DefaultSeed = "gnark/pedersen"
+
+// Params are the generators of the commitments on a twisted Edwards curve, as
+// affine coordinates.
+type Params struct {
+	ID tedwards.ID
+	G  [2]*big.Int
+	H  [2]*big.Int
+
+	curve   *twistededwards.CurveParams
+	modulus *big.Int
+}
+
+// NewParams returns the parameters of the commitments on the curve id: G is the
+// base point of the curve, and H is derived from seed (DefaultSeed if empty)
+// with a try-and-increment hash to the curve followed by the clearing of the
+// cofactor.
+func NewParams(id tedwards.ID, seed []byte) (*Params, error) {
+	curve, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	modulus, err := twistededwards.GetSnarkField(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(seed) == 0 {
+		seed = []byte(DefaultSeed)
+	}
+	p := &Params{
+		ID:      id,
+		G:       [2]*big.Int{new(big.Int).Set(curve.Base[0]), new(big.Int).Set(curve.Base[1])},
+		curve:   curve,
+		modulus: modulus,
+	}
+
+	one := big.NewInt(1)
+	for counter := uint32(0); ; counter++ {
+		if counter == 1<<16 {
+			return nil, errors.New("hash to curve failed")
+		}
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], counter)
+		digest := sha256.Sum256(append(append([]byte{}, seed...), buf[:]...))
+
+		// a·x² + y² = 1 + d·x²·y²  ⇔  x² = (1 - y²) / (a - d·y²)
+		y := new(big.Int).SetBytes(digest[:])
+		y.Mod(y, modulus)
+		y2 := new(big.Int).Mul(y, y)
+		num := new(big.Int).Sub(one, y2)
+		den := new(big.Int).Mul(curve.D, y2)
+		den.Sub(curve.A, den).Mod(den, modulus)
+		if den.Sign() == 0 {
+			continue
+		}
+		x2 := num.Mul(num, den.ModInverse(den, modulus)).Mod(num, modulus)
+		x := new(big.Int).ModSqrt(x2, modulus)
+		if x == nil {
+			continue
+		}
+		h := p.scalarMul([2]*big.Int{x, y}, curve.Cofactor)
+		if h[0].Sign() == 0 {
+			// small order point
+			continue
+		}
+		p.H = h
+		return p, nil
+	}
+}
+
+// Commit returns the commitment to value with the blinding factor.
+func (p *Params) Commit(value, blinding *big.Int) (x, y *big.Int) {
+	c := p.add(p.scalarMul(p.G, value), p.scalarMul(p.H, blinding))
+	return c[0], c[1]
+}
+
+// add returns p1 + p2.
+func (p *Params) add(p1, p2 [2]*big.Int) [2]*big.Int {
+	m := p.modulus
+	x1y2 := new(big.Int).Mul(p1[0], p2[1])
+	y1x2 := new(big.Int).Mul(p1[1], p2[0])
+	x1x2 := new(big.Int).Mul(p1[0], p2[0])
+	y1y2 := new(big.Int).Mul(p1[1], p2[1])
+	dxy := new(big.Int).Mul(x1x2, y1y2)
+	dxy.Mul(dxy, p.curve.D).Mod(dxy, m)
+
+	// x3 = (x1·y2 + y1·x2) / (1 + d·x1·x2·y1·y2)
+	x := x1y2.Add(x1y2, y1x2)
+	den := new(big.Int).Add(big.NewInt(1), dxy)
+	x.Mul(x, den.ModInverse(den, m)).Mod(x, m)
+
+	// y3 = (y1·y2 - a·x1·x2) / (1 - d·x1·x2·y1·y2)
+	y := y1y2.Sub(y1y2, x1x2.Mul(x1x2, p.curve.A))
+	den.Sub(big.NewInt(1), dxy).Mod(den, m)
+	y.Mul(y, den.ModInverse(den, m)).Mod(y, m)
+	return [2]*big.Int{x, y}
+}
+
+// scalarMul returns s·p1.
+func (p *Params) scalarMul(p1 [2]*big.Int, s *big.Int) [2]*big.Int {
+	res := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = p.add(res, res)
+		if s.Bit(i) == 1 {
+			res = p.add(res, p1)
+		}
+	}
+	return res
+}
+
+// Commit returns the commitment to value with the blinding factor, computed in
+// the circuit.
+func Commit(curve twistededwards.Curve, params *Params, value, blinding frontend.Variable) twistededwards.Point {
+	g := twistededwards.Point{X: params.G[0], Y: params.G[1]}
+	h := twistededwards.Point{X: params.H[0], Y: params.H[1]}
+	return curve.DoubleBaseScalarMul(g, h, value, blinding)
+}
+
+// AssertOpening asserts that commitment is the commitment to value with the
+// blinding factor.
+func AssertOpening(curve twistededwards.Curve, params *Params, commitment twistededwards.Point, value, blinding frontend.Variable) {
+	c := Commit(curve, params, value, blinding)
+	curve.API().AssertIsEqual(commitment.X, c.X)
+	curve.API().AssertIsEqual(commitment.Y, c.Y)
+}
+
+// AssertIsInRange asserts that commitment is the commitment to value with the
+// blinding factor, and that value is in [0, 2ⁿᵇᴮⁱᵗˢ). nbBits must be smaller
+// than the size of the order of G, for the commitment to bind to a single value
+// in the range.
+//
+// The range check is done with std/rangecheck, and batched with the other range
+// checks of the circuit.
+func AssertIsInRange(curve twistededwards.Curve, params *Params, commitment twistededwards.Point, value, blinding frontend.Variable, nbBits int) {
+	if nbBits < 1 || nbBits >= curve.Params().Order.BitLen() {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	rangecheck.New(curve.API()).Check(value, nbBits)
+	AssertOpening(curve, params, commitment, value, blinding)
+}
@@ -0,0 +1,91 @@
+package pedersen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bjj "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+const nbBits = 64
+
+type rangeCircuit struct {
+	Commitment twistededwards.Point `gnark:",public"`
+	Value      frontend.Variable
+	Blinding   frontend.Variable
+
+	params *Params
+}
+
+func (c *rangeCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	AssertIsInRange(curve, c.params, c.Commitment, c.Value, c.Blinding, nbBits)
+	return nil
+}
+
+func TestNewParams(t *testing.T) {
+	assert := require.New(t)
+
+	params, err := NewParams(tedwards.BN254, nil)
+	assert.NoError(err)
+	other, err := NewParams(tedwards.BN254, []byte("other"))
+	assert.NoError(err)
+	assert.NotEqual(params.H, other.H)
+
+	// H is in the subgroup of G, and the native commitment matches gnark-crypto
+	var g, h, res, tmp bjj.PointAffine
+	g = bjj.GetEdwardsCurve().Base
+	h.X.SetBigInt(params.H[0])
+	h.Y.SetBigInt(params.H[1])
+	assert.True(h.IsOnCurve())
+	order := bjj.GetEdwardsCurve().Order
+	tmp.ScalarMultiplication(&h, &order)
+	assert.True(tmp.IsZero())
+
+	value, blinding := big.NewInt(1234), big.NewInt(5678)
+	res.ScalarMultiplication(&g, value)
+	tmp.ScalarMultiplication(&h, blinding)
+	res.Add(&res, &tmp)
+	x, y := params.Commit(value, blinding)
+	assert.Equal(res.X.BigInt(new(big.Int)), x)
+	assert.Equal(res.Y.BigInt(new(big.Int)), y)
+}
+
+func TestAssertIsInRange(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := NewParams(tedwards.BN254, nil)
+	assert.NoError(err)
+
+	value := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	value.Sub(value, big.NewInt(1))
+	blinding := big.NewInt(987654321)
+	x, y := params.Commit(value, blinding)
+
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+	circuit := &rangeCircuit{params: params}
+	assert.ProverSucceeded(circuit, &rangeCircuit{
+		Commitment: twistededwards.Point{X: x, Y: y}, Value: value, Blinding: blinding,
+	}, opts...)
+
+	// value out of range, with a valid opening
+	outOfRange := new(big.Int).Add(value, big.NewInt(1))
+	x2, y2 := params.Commit(outOfRange, blinding)
+	assert.ProverFailed(circuit, &rangeCircuit{
+		Commitment: twistededwards.Point{X: x2, Y: y2}, Value: outOfRange, Blinding: blinding,
+	}, opts...)
+
+	// wrong opening
+	assert.ProverFailed(circuit, &rangeCircuit{
+		Commitment: twistededwards.Point{X: x, Y: y}, Value: 1, Blinding: blinding,
+	}, opts...)
+}
@@ -0,0 +1,50 @@
+package fiatshamir
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The values bound to the challenges of a Transcript are absorbed as field
+// elements, while the transcripts of gnark-crypto bind bytes which their hash
+// reads as big-endian blocks of the size of the field elements. Encode and
+// Decode convert between the two: binding Encode(field, values...) to a
+// challenge of a gnark-crypto transcript with the MiMC or Poseidon hash of the
+// field binds the same data as binding the values to a Transcript in the
+// circuit, so that both compute the same challenges.
+
+// BlockSize returns the number of bytes of the blocks of the elements of the
+// field.
+func BlockSize(field *big.Int) int {
+	return (field.BitLen() + 7) / 8
+}
+
+// Encode returns the values as big-endian blocks of BlockSize bytes, reducing
+// them modulo the field.
+func Encode(field *big.Int, values ...*big.Int) []byte {
+	size := BlockSize(field)
+	res := make([]byte, size*len(values))
+	var v big.Int
+	for i := range values {
+		v.Mod(values[i], field)
+		v.FillBytes(res[i*size : (i+1)*size])
+	}
+	return res
+}
+
+// Decode returns the values of the big-endian blocks of BlockSize bytes, which
+// must be smaller than the modulus of the field.
+func Decode(field *big.Int, b []byte) ([]*big.Int, error) {
+	size := BlockSize(field)
+	if len(b)%size != 0 {
+		return nil, errors.New("the length of the bytes is not a multiple of the block size")
+	}
+	res := make([]*big.Int, len(b)/size)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(b[i*size : (i+1)*size])
+		if res[i].Cmp(field) >= 0 {
+			return nil, errors.New("block is not a canonical field element")
+		}
+	}
+	return res, nil
+}
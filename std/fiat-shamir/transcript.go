@@ -52,6 +52,10 @@ type challenge struct {
 // NewTranscript returns a new transcript.
 // h is the hash function that is used to compute the challenges.
 // challenges are the name of the challenges. The order is important.
+//
+// The challenges are the ones of the transcripts of gnark-crypto with the native
+// counterpart of h, MiMC or Poseidon, when the values bound natively are encoded
+// with Encode.
 func NewTranscript(api frontend.API, h hash.Hash, challengesID ...string) Transcript {
 	n := len(challengesID)
 	t := Transcript{
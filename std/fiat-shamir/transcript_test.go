@@ -0,0 +1,105 @@
+package fiatshamir_test
+
+import (
+	stdhash "hash"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptomimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	cryptofiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/frontend"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+var challengeIDs = []string{"alpha", "beta", "gamma"}
+
+type transcriptCircuit struct {
+	usePoseidon bool
+	Bindings    [3][2]frontend.Variable
+	Challenges  [3]frontend.Variable
+}
+
+func (c *transcriptCircuit) Define(api frontend.API) error {
+	var h hash.Hash
+	if c.usePoseidon {
+		p, err := poseidon.NewPoseidon(api)
+		if err != nil {
+			return err
+		}
+		h = &p
+	} else {
+		m, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h = &m
+	}
+	ts := fiatshamir.NewTranscript(api, h, challengeIDs...)
+	for i, id := range challengeIDs {
+		if err := ts.Bind(id, c.Bindings[i][:]); err != nil {
+			return err
+		}
+		challenge, err := ts.ComputeChallenge(id)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(challenge, c.Challenges[i])
+	}
+	return nil
+}
+
+// nativeTranscript returns the assignment of transcriptCircuit with the
+// challenges computed by the transcript of gnark-crypto.
+func nativeTranscript(t *testing.T, h stdhash.Hash) *transcriptCircuit {
+	field := ecc.BN254.ScalarField()
+	ts := cryptofiatshamir.NewTranscript(h, challengeIDs...)
+	var res transcriptCircuit
+	for i, id := range challengeIDs {
+		values := []*big.Int{big.NewInt(int64(2 * i)), new(big.Int).Sub(field, big.NewInt(int64(i+1)))}
+		require.NoError(t, ts.Bind(id, fiatshamir.Encode(field, values...)))
+		challenge, err := ts.ComputeChallenge(id)
+		require.NoError(t, err)
+		res.Bindings[i] = [2]frontend.Variable{values[0], values[1]}
+		res.Challenges[i] = new(big.Int).SetBytes(challenge)
+	}
+	return &res
+}
+
+func TestTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	witness := nativeTranscript(t, cryptomimc.NewMiMC())
+	assert.NoError(test.IsSolved(&transcriptCircuit{}, witness, ecc.BN254.ScalarField()))
+	witness.Bindings[1][0] = 1
+	assert.Error(test.IsSolved(&transcriptCircuit{}, witness, ecc.BN254.ScalarField()))
+
+	witness = nativeTranscript(t, poseidon.NewHasher())
+	witness.usePoseidon = true
+	assert.NoError(test.IsSolved(&transcriptCircuit{usePoseidon: true}, witness, ecc.BN254.ScalarField()))
+	witness.Bindings[2][1] = 1
+	assert.Error(test.IsSolved(&transcriptCircuit{usePoseidon: true}, witness, ecc.BN254.ScalarField()))
+}
+
+func TestEncode(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+	assert.Equal(32, fiatshamir.BlockSize(field))
+
+	values := []*big.Int{big.NewInt(1), new(big.Int).Sub(field, big.NewInt(1))}
+	b := fiatshamir.Encode(field, append(values, new(big.Int).Add(field, big.NewInt(2)))...)
+	assert.Len(b, 96)
+	decoded, err := fiatshamir.Decode(field, b)
+	assert.NoError(err)
+	assert.Equal(append(values, big.NewInt(2)), decoded)
+
+	_, err = fiatshamir.Decode(field, b[1:])
+	assert.Error(err)
+	_, err = fiatshamir.Decode(field, field.FillBytes(make([]byte, 32)))
+	assert.Error(err)
+}
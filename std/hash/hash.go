@@ -14,7 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package hash provides an interface that hash functions (as gadget) should implement.
+// Package hash provides an interface that hash functions (as gadget) should implement,
+// and StructuredHash to hash tagged and length-prefixed data with any of them.
 package hash
 
 import "github.com/consensys/gnark/frontend"
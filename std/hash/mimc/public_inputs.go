@@ -0,0 +1,65 @@
+package mimc
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/mimc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// PublicInputsHasher hashes the public inputs with MiMC, see
+// frontend.WithPublicInputsHashing. Its Hash method matches the gnark-crypto
+// MiMC implementation, so that verifiers can compute the public witness with
+//
+//	frontend.NewWitness(assignment, field, frontend.PublicOnly(), frontend.HashPublicInputs(mimc.PublicInputsHasher))
+var PublicInputsHasher frontend.PublicInputsHasher = publicInputsHasher{}
+
+type publicInputsHasher struct{}
+
+func (publicInputsHasher) Define(api frontend.API, publicInputs []frontend.Variable) (frontend.Variable, error) {
+	h, err := NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(publicInputs...)
+	return h.Sum(), nil
+}
+
+func (publicInputsHasher) Hash(field *big.Int, publicInputs []*big.Int) (*big.Int, error) {
+	var h hash.Hash
+	switch utils.FieldToCurve(field) {
+	case ecc.BN254:
+		h = bn254.NewMiMC()
+	case ecc.BLS12_381:
+		h = bls12381.NewMiMC()
+	case ecc.BLS12_377:
+		h = bls12377.NewMiMC()
+	case ecc.BLS24_315:
+		h = bls24315.NewMiMC()
+	case ecc.BLS24_317:
+		h = bls24317.NewMiMC()
+	default:
+		return nil, errors.New("unknown curve id")
+	}
+
+	// each input is written as a block, reduced in the field
+	buf := make([]byte, h.BlockSize())
+	var v big.Int
+	for _, in := range publicInputs {
+		v.Mod(in, field)
+		v.FillBytes(buf)
+		if _, err := h.Write(buf); err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
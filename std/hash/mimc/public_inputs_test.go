@@ -0,0 +1,69 @@
+package mimc_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/stretchr/testify/require"
+)
+
+type manyPublicCircuit struct {
+	X   [4]frontend.Variable `gnark:",public"`
+	Sum frontend.Variable    `gnark:",public"`
+	Y   frontend.Variable
+}
+
+func (c *manyPublicCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.X[0], c.X[1], c.X[2], c.X[3], c.Y), c.Sum)
+	return nil
+}
+
+func TestPublicInputsHashing(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	assignment := &manyPublicCircuit{X: [4]frontend.Variable{1, 2, 3, 4}, Sum: 15, Y: 5}
+	wrong := &manyPublicCircuit{X: [4]frontend.Variable{1, 2, 3, 4}, Sum: 16, Y: 5}
+	other := &manyPublicCircuit{X: [4]frontend.Variable{1, 2, 3, 4}, Sum: 16, Y: 6}
+
+	for _, tc := range []struct {
+		builder  frontend.NewBuilder
+		nbPublic int
+	}{{r1cs.NewBuilder, 2}, {scs.NewBuilder, 1}} { // R1CS has the constant wire
+		ccs, err := frontend.Compile(field, tc.builder, &manyPublicCircuit{}, frontend.WithPublicInputsHashing(mimc.PublicInputsHasher))
+		assert.NoError(err)
+		assert.Equal(tc.nbPublic, ccs.GetNbPublicVariables())
+
+		w, err := frontend.NewWitness(assignment, field, frontend.HashPublicInputs(mimc.PublicInputsHasher))
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w))
+
+		w, err = frontend.NewWitness(wrong, field, frontend.HashPublicInputs(mimc.PublicInputsHasher))
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(w))
+	}
+
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &manyPublicCircuit{}, frontend.WithPublicInputsHashing(mimc.PublicInputsHasher))
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(assignment, field, frontend.HashPublicInputs(mimc.PublicInputsHasher))
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	public, err := frontend.NewWitness(assignment, field, frontend.PublicOnly(), frontend.HashPublicInputs(mimc.PublicInputsHasher))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, public))
+
+	// the proof is bound to the public inputs through their hash
+	public, err = frontend.NewWitness(other, field, frontend.PublicOnly(), frontend.HashPublicInputs(mimc.PublicInputsHasher))
+	assert.NoError(err)
+	assert.Error(groth16.Verify(proof, vk, public))
+}
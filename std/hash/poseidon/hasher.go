@@ -0,0 +1,80 @@
+package poseidon
+
+import (
+	"errors"
+	stdhash "hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// NewHasher returns the native counterpart of the Poseidon gadget as a
+// hash.Hash. Write takes the inputs as big-endian blocks of fr.Bytes bytes,
+// which must be canonical field elements, and Sum appends the hash of all the
+// written blocks, from 1 to MaxInputs. Sum panics for another number of
+// blocks, as the gadget does.
+//
+// Like the MiMC of gnark-crypto, it has a WriteString method writing a string
+// hashed to a field element, which the fiat-shamir transcripts use for the
+// names of the challenges.
+func NewHasher() stdhash.Hash {
+	return &hasher{}
+}
+
+type hasher struct {
+	data []fr.Element
+	// partial block of the last write
+	buf []byte
+}
+
+var errNonCanonical = errors.New("block is not a canonical field element")
+
+func (h *hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	p = append(h.buf, p...)
+	for len(p) >= fr.Bytes {
+		var e fr.Element
+		e.SetBytes(p[:fr.Bytes])
+		if b := e.Bytes(); string(b[:]) != string(p[:fr.Bytes]) {
+			h.buf = nil
+			return 0, errNonCanonical
+		}
+		h.data = append(h.data, e)
+		p = p[fr.Bytes:]
+	}
+	h.buf = append([]byte(nil), p...)
+	return n, nil
+}
+
+// WriteString writes the bytes hashed to a field element, as gnark-crypto's
+// MiMC and constant.HashedBytes do.
+func (h *hasher) WriteString(rawBytes []byte) {
+	elems, err := fr.Hash(rawBytes, []byte("string:"), 1)
+	if err != nil {
+		panic(err)
+	}
+	h.data = append(h.data, elems[0])
+}
+
+func (h *hasher) Sum(b []byte) []byte {
+	if len(h.buf) != 0 {
+		panic("poseidon: partial block")
+	}
+	if len(h.data) == 0 || len(h.data) > MaxInputs {
+		panic("poseidon: wrong number of blocks")
+	}
+	res := hash(h.data)
+	bytes := res.Bytes()
+	return append(b, bytes[:]...)
+}
+
+func (h *hasher) Reset() {
+	h.data, h.buf = nil, nil
+}
+
+func (h *hasher) Size() int {
+	return fr.Bytes
+}
+
+func (h *hasher) BlockSize() int {
+	return fr.Bytes
+}
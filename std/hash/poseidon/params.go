@@ -0,0 +1,149 @@
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// MaxInputs is the largest number of inputs of a hash.
+	MaxInputs = 16
+
+	nbFullRounds = 8
+)
+
+// nbPartialRounds are the numbers of partial rounds of the permutations of
+// width 2 to MaxInputs+1.
+var nbPartialRounds = [MaxInputs]int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+// parameters are the round constants and the MDS matrix of the permutation of
+// width t.
+type parameters struct {
+	t, nbPartialRounds int
+	constants          []fr.Element
+	mds                [][]fr.Element
+}
+
+var (
+	parametersLock  sync.Mutex
+	parametersCache = make(map[int]*parameters)
+)
+
+// getParameters returns the parameters of the permutation of width t, in
+// [2, MaxInputs+1].
+func getParameters(t int) *parameters {
+	parametersLock.Lock()
+	defer parametersLock.Unlock()
+	if p, ok := parametersCache[t]; ok {
+		return p
+	}
+	p := newParameters(t)
+	parametersCache[t] = p
+	return p
+}
+
+// newParameters generates the parameters of the permutation of width t as the
+// reference implementation of Poseidon does: the round constants, then the
+// points of the Cauchy MDS matrix, are sampled from a Grain LFSR seeded with
+// the parameters of the instance.
+func newParameters(t int) *parameters {
+	modulus := fr.Modulus()
+	nbBits := modulus.BitLen()
+	p := &parameters{t: t, nbPartialRounds: nbPartialRounds[t-2]}
+	g := newGrain(1, 0, nbBits, t, nbFullRounds, p.nbPartialRounds)
+
+	// the constants are sampled by rejection
+	p.constants = make([]fr.Element, (nbFullRounds+p.nbPartialRounds)*t)
+	for i := range p.constants {
+		c := g.next(nbBits)
+		for c.Cmp(modulus) >= 0 {
+			c = g.next(nbBits)
+		}
+		p.constants[i].SetBigInt(c)
+	}
+
+	// Mᵢⱼ = 1 / (xᵢ + yⱼ) for distinct xᵢ, yⱼ
+	for {
+		points := make([]fr.Element, 2*t)
+		for distinct := false; !distinct; {
+			seen := make(map[fr.Element]bool, len(points))
+			distinct = true
+			for i := range points {
+				points[i].SetBigInt(g.next(nbBits))
+				distinct = distinct && !seen[points[i]]
+				seen[points[i]] = true
+			}
+		}
+		xs, ys := points[:t], points[t:]
+		p.mds = make([][]fr.Element, t)
+		invertible := true
+		for i := range p.mds {
+			p.mds[i] = make([]fr.Element, t)
+			for j := range p.mds[i] {
+				p.mds[i][j].Add(&xs[i], &ys[j])
+				invertible = invertible && !p.mds[i][j].IsZero()
+				p.mds[i][j].Inverse(&p.mds[i][j])
+			}
+		}
+		if invertible {
+			return p
+		}
+	}
+}
+
+// grain is the Grain LFSR of the reference implementation of Poseidon.
+type grain struct {
+	state [80]uint8
+	head  int
+}
+
+func newGrain(field, sbox, nbBits, t, nbFullRounds, nbPartialRounds int) *grain {
+	g := new(grain)
+	i := 0
+	for _, f := range []struct{ v, n int }{{field, 2}, {sbox, 4}, {nbBits, 12}, {t, 12}, {nbFullRounds, 10}, {nbPartialRounds, 10}} {
+		for b := f.n - 1; b >= 0; b-- {
+			g.state[i] = uint8(f.v>>b) & 1
+			i++
+		}
+	}
+	for ; i < len(g.state); i++ {
+		g.state[i] = 1
+	}
+	for i := 0; i < 160; i++ {
+		g.step()
+	}
+	return g
+}
+
+// step shifts the register and returns the new bit.
+func (g *grain) step() uint8 {
+	s := func(i int) uint8 { return g.state[(g.head+i)%len(g.state)] }
+	b := s(62) ^ s(51) ^ s(38) ^ s(23) ^ s(13) ^ s(0)
+	g.state[g.head] = b
+	g.head = (g.head + 1) % len(g.state)
+	return b
+}
+
+// bit returns the next output bit: the bits are drawn by pairs, and the second
+// one is output when the first one is 1.
+func (g *grain) bit() uint8 {
+	for g.step() == 0 {
+		g.step()
+	}
+	return g.step()
+}
+
+// next returns the integer of the next nbBits output bits, most significant
+// first.
+func (g *grain) next(nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.bit() == 1 {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
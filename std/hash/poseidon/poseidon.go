@@ -0,0 +1,158 @@
+// Package poseidon provides a ZKP-circuit function to compute a Poseidon hash
+// over the scalar field of BN254, and its native counterpart.
+//
+// The instance is the one of circomlib: the S-box x⁵, 8 full rounds, and the
+// round constants and Cauchy MDS matrix of the reference implementation,
+// generated with its Grain LFSR. The state of the permutation holds a zero
+// capacity element followed by the inputs, and the hash is the first element of
+// the output state. A hash takes from 1 to MaxInputs field elements; longer
+// messages must be split, for instance in a Merkle tree.
+//
+// It costs about 240 constraints in R1CS for 2 inputs, against about 660 for
+// MiMC.
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Poseidon computes Poseidon hashes in a gnark circuit.
+type Poseidon struct {
+	api  frontend.API
+	data []frontend.Variable
+}
+
+// NewPoseidon returns a Poseidon instance, than can be used in a gnark circuit
+// over the scalar field of BN254.
+func NewPoseidon(api frontend.API) (Poseidon, error) {
+	if api.Compiler().Field().Cmp(ecc.BN254.ScalarField()) != 0 {
+		return Poseidon{}, errors.New("poseidon is only implemented over the scalar field of BN254")
+	}
+	return Poseidon{api: api}, nil
+}
+
+// Write adds more data to the hash.
+func (h *Poseidon) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Poseidon) Reset() {
+	h.data = nil
+}
+
+// Sum returns the hash of the written data, which must hold from 1 to
+// MaxInputs elements.
+func (h *Poseidon) Sum() frontend.Variable {
+	if len(h.data) == 0 || len(h.data) > MaxInputs {
+		panic(fmt.Sprintf("poseidon hashes 1 to %d elements, got %d", MaxInputs, len(h.data)))
+	}
+	state := make([]frontend.Variable, len(h.data)+1)
+	state[0] = 0
+	copy(state[1:], h.data)
+	return permutation(h.api, state)[0]
+}
+
+// permutation returns the Poseidon permutation of width len(state) of state.
+func permutation(api frontend.API, state []frontend.Variable) []frontend.Variable {
+	p := getParameters(len(state))
+	sbox := func(x frontend.Variable) frontend.Variable {
+		x2 := api.Mul(x, x)
+		return api.Mul(api.Mul(x2, x2), x)
+	}
+	for r := 0; r < nbFullRounds+p.nbPartialRounds; r++ {
+		for i := range state {
+			state[i] = api.Add(state[i], constant(&p.constants[r*p.t+i]))
+		}
+		if isFullRound(r, p.nbPartialRounds) {
+			for i := range state {
+				state[i] = sbox(state[i])
+			}
+		} else {
+			state[0] = sbox(state[0])
+		}
+		mixed := make([]frontend.Variable, p.t)
+		for i := range mixed {
+			mixed[i] = 0
+			for j := range state {
+				mixed[i] = api.Add(mixed[i], api.Mul(state[j], constant(&p.mds[i][j])))
+			}
+		}
+		state = mixed
+	}
+	return state
+}
+
+// Hash returns the Poseidon hash of the inputs, from 1 to MaxInputs elements of
+// the scalar field of BN254.
+func Hash(inputs []*big.Int) (*big.Int, error) {
+	if len(inputs) == 0 || len(inputs) > MaxInputs {
+		return nil, fmt.Errorf("poseidon hashes 1 to %d elements, got %d", MaxInputs, len(inputs))
+	}
+	elements := make([]fr.Element, len(inputs))
+	for i := range inputs {
+		if inputs[i].Sign() < 0 || inputs[i].Cmp(fr.Modulus()) >= 0 {
+			return nil, fmt.Errorf("input %d is not a field element", i)
+		}
+		elements[i].SetBigInt(inputs[i])
+	}
+	res := hash(elements)
+	return res.BigInt(new(big.Int)), nil
+}
+
+// hash returns the Poseidon hash of 1 to MaxInputs elements.
+func hash(inputs []fr.Element) fr.Element {
+	state := make([]fr.Element, len(inputs)+1)
+	copy(state[1:], inputs)
+	permute(state)
+	return state[0]
+}
+
+// permute applies the Poseidon permutation of width len(state) to state.
+func permute(state []fr.Element) {
+	p := getParameters(len(state))
+	sbox := func(x *fr.Element) {
+		var x2 fr.Element
+		x2.Square(x)
+		x2.Square(&x2)
+		x.Mul(x, &x2)
+	}
+	mixed := make([]fr.Element, p.t)
+	for r := 0; r < nbFullRounds+p.nbPartialRounds; r++ {
+		for i := range state {
+			state[i].Add(&state[i], &p.constants[r*p.t+i])
+		}
+		if isFullRound(r, p.nbPartialRounds) {
+			for i := range state {
+				sbox(&state[i])
+			}
+		} else {
+			sbox(&state[0])
+		}
+		for i := range mixed {
+			mixed[i].SetZero()
+			for j := range state {
+				var tmp fr.Element
+				tmp.Mul(&state[j], &p.mds[i][j])
+				mixed[i].Add(&mixed[i], &tmp)
+			}
+		}
+		copy(state, mixed)
+	}
+}
+
+// isFullRound returns true if the round r is one of the full rounds, half of
+// which are before the partial rounds.
+func isFullRound(r, nbPartialRounds int) bool {
+	return r < nbFullRounds/2 || r >= nbFullRounds/2+nbPartialRounds
+}
+
+func constant(e *fr.Element) *big.Int {
+	return e.BigInt(new(big.Int))
+}
@@ -0,0 +1,86 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	// test vectors of circomlib
+	for _, tc := range []struct {
+		inputs   []int64
+		expected string
+	}{
+		{[]int64{1}, "18586133768512220936620570745912940619677854269274689475585506675881198879027"},
+		{[]int64{1, 2}, "7853200120776062878684798364095072458815029376092732009249414926327459813530"},
+		{[]int64{1, 2, 3, 4}, "18821383157269793795438455681495246036402687001665670618754263018637548127333"},
+	} {
+		inputs := make([]*big.Int, len(tc.inputs))
+		for i := range inputs {
+			inputs[i] = big.NewInt(tc.inputs[i])
+		}
+		res, err := Hash(inputs)
+		assert.NoError(err)
+		assert.Equal(tc.expected, res.String(), "%v", tc.inputs)
+	}
+
+	_, err := Hash(nil)
+	assert.Error(err)
+	_, err = Hash(make([]*big.Int, MaxInputs+1))
+	assert.Error(err)
+
+	// the hasher hashes the blocks
+	h := NewHasher()
+	for _, v := range []int64{1, 2} {
+		_, err := h.Write(big.NewInt(v).FillBytes(make([]byte, h.BlockSize())))
+		assert.NoError(err)
+	}
+	assert.Equal("7853200120776062878684798364095072458815029376092732009249414926327459813530", new(big.Int).SetBytes(h.Sum(nil)).String())
+	h.Reset()
+	_, err = h.Write(ecc.BN254.ScalarField().FillBytes(make([]byte, h.BlockSize())))
+	assert.Error(err)
+}
+
+type poseidonCircuit struct {
+	Inputs   []frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *poseidonCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Inputs...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestPoseidon(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, n := range []int{1, 2, 5, MaxInputs} {
+		inputs := make([]*big.Int, n)
+		witness := &poseidonCircuit{Inputs: make([]frontend.Variable, n)}
+		for i := range inputs {
+			inputs[i] = big.NewInt(int64(1000 * (i + 1)))
+			witness.Inputs[i] = inputs[i]
+		}
+		expected, err := Hash(inputs)
+		assert.NoError(err)
+		witness.Expected = expected
+
+		circuit := &poseidonCircuit{Inputs: make([]frontend.Variable, n)}
+		assert.ProverSucceeded(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK))
+		witness.Expected = new(big.Int).Add(expected, big.NewInt(1))
+		assert.ProverFailed(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK))
+	}
+}
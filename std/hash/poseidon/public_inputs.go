@@ -0,0 +1,140 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+)
+
+// spongeRate is the number of inputs absorbed by each permutation of the
+// sponge, whose state has a single capacity element.
+const spongeRate = 2
+
+// PublicInputsHasher hashes any number of public inputs with a Poseidon sponge,
+// see frontend.WithPublicInputsHashing. It is meant for circuits verified by
+// the Solidity verifiers: with the option solidity.WithPublicInputsHashing, the
+// exported verifier takes the public inputs and hashes them itself with the
+// library returned by SolidityLibrary.
+//
+// The sponge uses the permutation of width 3: its state is initialized to
+// (n, 0, 0), n being the number of inputs, the inputs are added by pairs to the
+// last two elements, zero-padded, each pair being followed by a permutation, and
+// the hash is the second element of the final state. The inputs must be
+// elements of the scalar field of BN254.
+var PublicInputsHasher = SpongeHasher{}
+
+// SpongeHasher is the type of PublicInputsHasher.
+type SpongeHasher struct{}
+
+// Define returns the sponge hash of the public inputs in the circuit.
+func (SpongeHasher) Define(api frontend.API, publicInputs []frontend.Variable) (frontend.Variable, error) {
+	if api.Compiler().Field().Cmp(ecc.BN254.ScalarField()) != 0 {
+		return nil, errors.New("poseidon is only implemented over the scalar field of BN254")
+	}
+	state := []frontend.Variable{len(publicInputs), 0, 0}
+	for i := 0; i == 0 || i < len(publicInputs); i += spongeRate {
+		for j := 0; j < spongeRate && i+j < len(publicInputs); j++ {
+			state[1+j] = api.Add(state[1+j], publicInputs[i+j])
+		}
+		state = permutation(api, state)
+	}
+	return state[1], nil
+}
+
+// Hash returns the sponge hash of the public inputs.
+func (SpongeHasher) Hash(field *big.Int, publicInputs []*big.Int) (*big.Int, error) {
+	if field.Cmp(fr.Modulus()) != 0 {
+		return nil, errors.New("poseidon is only implemented over the scalar field of BN254")
+	}
+	state := make([]fr.Element, 1+spongeRate)
+	state[0].SetUint64(uint64(len(publicInputs)))
+	for i := 0; i == 0 || i < len(publicInputs); i += spongeRate {
+		for j := 0; j < spongeRate && i+j < len(publicInputs); j++ {
+			in := publicInputs[i+j]
+			if in.Sign() < 0 || in.Cmp(fr.Modulus()) >= 0 {
+				return nil, fmt.Errorf("input %d is not a field element", i+j)
+			}
+			var e fr.Element
+			e.SetBigInt(in)
+			state[1+j].Add(&state[1+j], &e)
+		}
+		permute(state)
+	}
+	return state[1].BigInt(new(big.Int)), nil
+}
+
+// SolidityLibrary returns the Solidity library PoseidonSponge, whose function
+//
+//	function hash(uint256[] memory inputs) internal pure returns (uint256)
+//
+// computes the sponge hash of the inputs, which the caller checks to be in the
+// field. It implements solidity.PublicInputsHasher.
+func (SpongeHasher) SolidityLibrary() (name, code string) {
+	p := getParameters(1 + spongeRate)
+	r := fmt.Sprintf("0x%x", fr.Modulus())
+	hex := func(e *fr.Element) string {
+		return fmt.Sprintf("0x%x", e.BigInt(new(big.Int)))
+	}
+
+	var sbb strings.Builder
+	w := func(format string, args ...any) {
+		fmt.Fprintf(&sbb, format, args...)
+		sbb.WriteByte('\n')
+	}
+	w("// PoseidonSponge computes the Poseidon sponge hash of the public inputs of")
+	w("// the circuit, see poseidon.PublicInputsHasher in gnark.")
+	w("library PoseidonSponge {")
+	w("    function hash(uint256[] memory inputs) internal pure returns (uint256 h) {")
+	w("        assembly {")
+	w("            function sbox(x) -> y {")
+	w("                let x2 := mulmod(x, x, %s)", r)
+	w("                y := mulmod(mulmod(x2, x2, %s), x, %s)", r, r)
+	w("            }")
+	w("            function mix(a, b, c) -> x, y, z {")
+	for i, v := range []string{"x", "y", "z"} {
+		w("                %s := addmod(addmod(mulmod(a, %s, %s), mulmod(b, %s, %s), %s), mulmod(c, %s, %s), %s)",
+			v, hex(&p.mds[i][0]), r, hex(&p.mds[i][1]), r, r, hex(&p.mds[i][2]), r, r)
+	}
+	w("            }")
+	w("            function fullRound(a, b, c, k0, k1, k2) -> x, y, z {")
+	w("                x, y, z := mix(sbox(addmod(a, k0, %s)), sbox(addmod(b, k1, %s)), sbox(addmod(c, k2, %s)))", r, r, r)
+	w("            }")
+	w("            function partialRound(a, b, c, k0, k1, k2) -> x, y, z {")
+	w("                x, y, z := mix(sbox(addmod(a, k0, %s)), addmod(b, k1, %s), addmod(c, k2, %s))", r, r, r)
+	w("            }")
+	w("            function permute(a, b, c) -> x, y, z {")
+	for round := 0; round < nbFullRounds+p.nbPartialRounds; round++ {
+		fn := "partialRound"
+		if isFullRound(round, p.nbPartialRounds) {
+			fn = "fullRound"
+		}
+		k := p.constants[round*p.t : (round+1)*p.t]
+		w("                a, b, c := %s(a, b, c, %s, %s, %s)", fn, hex(&k[0]), hex(&k[1]), hex(&k[2]))
+	}
+	w("                x := a")
+	w("                y := b")
+	w("                z := c")
+	w("            }")
+	w("")
+	w("            let n := mload(inputs)")
+	w("            let s0 := n")
+	w("            let s1 := 0")
+	w("            let s2 := 0")
+	w("            let data := add(inputs, 0x20)")
+	w("            for { let i := 0 } 1 { i := add(i, 2) } {")
+	w("                if lt(i, n) { s1 := addmod(s1, mload(add(data, mul(i, 0x20))), %s) }", r)
+	w("                if lt(add(i, 1), n) { s2 := addmod(s2, mload(add(data, mul(add(i, 1), 0x20))), %s) }", r)
+	w("                s0, s1, s2 := permute(s0, s1, s2)")
+	w("                if iszero(lt(add(i, 2), n)) { break }")
+	w("            }")
+	w("            h := s1")
+	w("        }")
+	w("    }")
+	sbb.WriteString("}")
+	return "PoseidonSponge", sbb.String()
+}
@@ -0,0 +1,62 @@
+package poseidon_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/stretchr/testify/require"
+)
+
+type manyPublicCircuit struct {
+	X   [5]frontend.Variable `gnark:",public"`
+	Sum frontend.Variable    `gnark:",public"`
+}
+
+func (c *manyPublicCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.X[0], c.X[1], c.X[2], c.X[3], c.X[4]), c.Sum)
+	return nil
+}
+
+func TestPublicInputsHashing(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	assignment := &manyPublicCircuit{X: [5]frontend.Variable{1, 2, 3, 4, 5}, Sum: 15}
+	wrong := &manyPublicCircuit{X: [5]frontend.Variable{1, 2, 3, 4, 5}, Sum: 16}
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, builder, &manyPublicCircuit{}, frontend.WithPublicInputsHashing(poseidon.PublicInputsHasher))
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(assignment, field, frontend.HashPublicInputs(poseidon.PublicInputsHasher))
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w))
+
+		w, err = frontend.NewWitness(wrong, field, frontend.HashPublicInputs(poseidon.PublicInputsHasher))
+		assert.NoError(err)
+		assert.Error(ccs.IsSolved(w))
+	}
+
+	// the inputs must be in the field
+	_, err := poseidon.PublicInputsHasher.Hash(field, []*big.Int{field})
+	assert.Error(err)
+	_, err = poseidon.PublicInputsHasher.Hash(ecc.BLS12_381.ScalarField(), []*big.Int{big.NewInt(1)})
+	assert.Error(err)
+}
+
+func TestSolidityLibrary(t *testing.T) {
+	assert := require.New(t)
+
+	name, code := poseidon.PublicInputsHasher.SolidityLibrary()
+	assert.Equal("PoseidonSponge", name)
+	assert.Contains(code, "library PoseidonSponge {")
+	assert.Contains(code, "function hash(uint256[] memory inputs) internal pure returns (uint256 h)")
+	assert.Equal(8, strings.Count(code, ":= fullRound("))
+	assert.Equal(57, strings.Count(code, ":= partialRound("))
+}
@@ -0,0 +1,199 @@
+// Package sha2 implements the SHA-256 hash function of FIPS 180-4 in circuit.
+//
+// The message is a slice of bytes, one variable of 8 bits each, whose length is
+// fixed when the circuit is compiled, so that its padding is a constant. The
+// words of 32 bits are represented by their bits: the rotations and shifts are
+// free, and each modular addition costs a single decomposition.
+package sha2
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Size is the size of a SHA-256 digest, in bytes.
+const Size = 32
+
+// BlockSize is the size of the blocks of SHA-256, in bytes.
+const BlockSize = 64
+
+// word is a word of 32 bits, least significant bit first.
+type word [32]frontend.Variable
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// Sum256 returns the SHA-256 digest of the bytes data, as Size bytes. The bytes
+// of data are range checked.
+func Sum256(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	// the bits of the padded message, least significant bit of each byte first
+	msg := make([][8]frontend.Variable, 0, len(data)+BlockSize+9)
+	for _, b := range data {
+		var bits [8]frontend.Variable
+		copy(bits[:], api.ToBinary(b, 8))
+		msg = append(msg, bits)
+	}
+	// 0x80, zeros, and the length of data in bits on 8 bytes
+	padding := []byte{0x80}
+	for (len(data)+len(padding))%BlockSize != BlockSize-8 {
+		padding = append(padding, 0)
+	}
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(data))*8)
+	for _, b := range append(padding, length[:]...) {
+		var bits [8]frontend.Variable
+		for i := range bits {
+			bits[i] = int(b>>i) & 1
+		}
+		msg = append(msg, bits)
+	}
+
+	var h [8]word
+	for i := range h {
+		h[i] = constant(iv[i])
+	}
+	for start := 0; start < len(msg); start += BlockSize {
+		h = compress(api, h, msg[start:start+BlockSize])
+	}
+
+	digest := make([]frontend.Variable, 0, Size)
+	for _, w := range h {
+		for j := 3; j >= 0; j-- {
+			digest = append(digest, api.FromBinary(w[8*j:8*j+8]...))
+		}
+	}
+	return digest
+}
+
+// compress returns the state h updated with the block of BlockSize bytes.
+func compress(api frontend.API, h [8]word, block [][8]frontend.Variable) [8]word {
+	var w [64]word
+	for t := 0; t < 16; t++ {
+		// big-endian words
+		for j := 0; j < 4; j++ {
+			copy(w[t][8*(3-j):], block[4*t+j][:])
+		}
+	}
+	for t := 16; t < 64; t++ {
+		w[t] = add(api, sigma1(api, &w[t-2]), value(api, &w[t-7]), sigma0(api, &w[t-15]), value(api, &w[t-16]))
+	}
+
+	a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	for t := 0; t < 64; t++ {
+		// t1 and t2 are kept as sums, decomposed once in the new a and e
+		t1 := api.Add(value(api, &hh), bigSigma1(api, &e), ch(api, &e, &f, &g), k[t], value(api, &w[t]))
+		t2 := api.Add(bigSigma0(api, &a), maj(api, &a, &b, &c))
+		hh, g, f = g, f, e
+		e = add(api, value(api, &d), t1)
+		d, c, b = c, b, a
+		a = add(api, t1, t2)
+	}
+
+	for i, v := range [8]*word{&a, &b, &c, &d, &e, &f, &g, &hh} {
+		h[i] = add(api, value(api, &h[i]), value(api, v))
+	}
+	return h
+}
+
+// constant returns the bits of v.
+func constant(v uint32) word {
+	var w word
+	for i := range w {
+		w[i] = int(v>>i) & 1
+	}
+	return w
+}
+
+// value returns the integer of the bits of w.
+func value(api frontend.API, w *word) frontend.Variable {
+	return api.FromBinary(w[:]...)
+}
+
+// add returns the sum of the integers v modulo 2³², which must be less than
+// 2³⁵ (at most 8 words).
+func add(api frontend.API, v ...frontend.Variable) word {
+	var sum frontend.Variable = 0
+	for i := range v {
+		sum = api.Add(sum, v[i])
+	}
+	var w word
+	copy(w[:], api.ToBinary(sum, 35)[:32])
+	return w
+}
+
+func rotr(w *word, n int) word {
+	var res word
+	for i := range res {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+func shr(w *word, n int) word {
+	var res word
+	for i := range res {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// xor3 returns the integer of the bitwise xor of a, b and c.
+func xor3(api frontend.API, a, b, c word) frontend.Variable {
+	var res word
+	for i := range res {
+		res[i] = api.Xor(api.Xor(a[i], b[i]), c[i])
+	}
+	return value(api, &res)
+}
+
+func sigma0(api frontend.API, w *word) frontend.Variable {
+	return xor3(api, rotr(w, 7), rotr(w, 18), shr(w, 3))
+}
+
+func sigma1(api frontend.API, w *word) frontend.Variable {
+	return xor3(api, rotr(w, 17), rotr(w, 19), shr(w, 10))
+}
+
+func bigSigma0(api frontend.API, w *word) frontend.Variable {
+	return xor3(api, rotr(w, 2), rotr(w, 13), rotr(w, 22))
+}
+
+func bigSigma1(api frontend.API, w *word) frontend.Variable {
+	return xor3(api, rotr(w, 6), rotr(w, 11), rotr(w, 25))
+}
+
+// ch returns the integer of the bits of f where e is set, of g elsewhere.
+func ch(api frontend.API, e, f, g *word) frontend.Variable {
+	var res word
+	for i := range res {
+		res[i] = api.Select(e[i], f[i], g[i])
+	}
+	return value(api, &res)
+}
+
+// maj returns the integer of the majority of the bits of a, b and c: c where
+// a and b differ, a elsewhere.
+func maj(api frontend.API, a, b, c *word) frontend.Variable {
+	var res word
+	for i := range res {
+		res[i] = api.Select(api.Xor(a[i], b[i]), c[i], a[i])
+	}
+	return value(api, &res)
+}
@@ -0,0 +1,61 @@
+package sha2
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type sha256Circuit struct {
+	Data   []frontend.Variable
+	Digest [Size]frontend.Variable `gnark:",public"`
+}
+
+func (c *sha256Circuit) Define(api frontend.API) error {
+	digest := Sum256(api, c.Data)
+	for i := range digest {
+		api.AssertIsEqual(digest[i], c.Digest[i])
+	}
+	return nil
+}
+
+func TestSum256(t *testing.T) {
+	assert := require.New(t)
+
+	// lengths around the boundaries of the padding
+	for _, n := range []int{0, 3, 55, 56, 63, 64, 65, 119, 200} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(7*i + n)
+		}
+		digest := sha256.Sum256(data)
+
+		circuit := sha256Circuit{Data: make([]frontend.Variable, n)}
+		witness := sha256Circuit{Data: make([]frontend.Variable, n)}
+		for i := range data {
+			witness.Data[i] = data[i]
+		}
+		for i := range digest {
+			witness.Digest[i] = digest[i]
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()), fmt.Sprintf("length %d", n))
+
+		witness.Digest[0] = digest[0] ^ 1
+		assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()), fmt.Sprintf("length %d", n))
+	}
+}
+
+func TestSum256Bytes(t *testing.T) {
+	// the bytes are range checked
+	circuit := sha256Circuit{Data: make([]frontend.Variable, 1)}
+	witness := sha256Circuit{Data: []frontend.Variable{256}}
+	for i := range witness.Digest {
+		witness.Digest[i] = 0
+	}
+	require.Error(t, test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
@@ -0,0 +1,111 @@
+package hash
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// The markers which start the encodings of the tags and of the slices.
+const (
+	TagMarker   = 1
+	SliceMarker = 2
+)
+
+// StructuredHash wraps a Hash to absorb domain-separation tags and slices of
+// variables with an unambiguous encoding, so that distinct sequences of tags
+// and slices are hashed as distinct sequences of field elements:
+//
+//   - a tag of n bytes is absorbed as TagMarker, n, and the bytes packed
+//     big-endian in chunks of ChunkSize bytes, the last one padded on the
+//     right with zeros;
+//   - a slice of n variables is absorbed as SliceMarker, n, and the variables.
+//
+// The Encoder computes the same encoding natively, for the hashes computed
+// outside of the circuit.
+type StructuredHash struct {
+	h         Hash
+	chunkSize int
+}
+
+// NewStructuredHash returns a StructuredHash absorbing into h, which is reset.
+func NewStructuredHash(api frontend.API, h Hash) *StructuredHash {
+	h.Reset()
+	return &StructuredHash{h: h, chunkSize: ChunkSize(api.Compiler().Field())}
+}
+
+// WriteTag absorbs the domain-separation tag.
+func (s *StructuredHash) WriteTag(tag string) {
+	for _, e := range encodeTag(s.chunkSize, tag) {
+		s.h.Write(e)
+	}
+}
+
+// Write absorbs data as a single slice, and may be called with no data.
+func (s *StructuredHash) Write(data ...frontend.Variable) {
+	s.h.Write(SliceMarker, len(data))
+	s.h.Write(data...)
+}
+
+// Sum returns the hash of the absorbed tags and slices.
+func (s *StructuredHash) Sum() frontend.Variable {
+	return s.h.Sum()
+}
+
+// Reset resets the underlying hash.
+func (s *StructuredHash) Reset() {
+	s.h.Reset()
+}
+
+// Encoder computes natively the field elements absorbed by a StructuredHash,
+// to be written in the same order to the native hash.
+type Encoder struct {
+	field     *big.Int
+	chunkSize int
+	elements  []*big.Int
+}
+
+// NewEncoder returns an Encoder of elements of the given field.
+func NewEncoder(field *big.Int) *Encoder {
+	return &Encoder{field: field, chunkSize: ChunkSize(field)}
+}
+
+// WriteTag encodes the domain-separation tag.
+func (e *Encoder) WriteTag(tag string) {
+	e.elements = append(e.elements, encodeTag(e.chunkSize, tag)...)
+}
+
+// Write encodes data as a single slice, reducing the values modulo the field.
+func (e *Encoder) Write(data ...*big.Int) {
+	e.elements = append(e.elements, big.NewInt(SliceMarker), big.NewInt(int64(len(data))))
+	for _, d := range data {
+		e.elements = append(e.elements, new(big.Int).Mod(d, e.field))
+	}
+}
+
+// Elements returns the encoded field elements.
+func (e *Encoder) Elements() []*big.Int {
+	return e.elements
+}
+
+// Reset empties the encoding.
+func (e *Encoder) Reset() {
+	e.elements = nil
+}
+
+// ChunkSize returns the number of bytes of the chunks in which the tags are
+// packed: the largest number of bytes which always fits in a field element.
+func ChunkSize(field *big.Int) int {
+	return (field.BitLen() - 1) / 8
+}
+
+// encodeTag returns the encoding of the tag in chunks of chunkSize bytes.
+func encodeTag(chunkSize int, tag string) []*big.Int {
+	res := []*big.Int{big.NewInt(TagMarker), big.NewInt(int64(len(tag)))}
+	for start := 0; start < len(tag); start += chunkSize {
+		chunk := make([]byte, chunkSize)
+		copy(chunk, tag[start:])
+		res = append(res, new(big.Int).SetBytes(chunk))
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package hash_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptomimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+const testTag = "gnark structured hash test, longer than a chunk"
+
+type structuredCircuit struct {
+	A, B     []frontend.Variable
+	Expected frontend.Variable
+}
+
+func (c *structuredCircuit) Define(api frontend.API) error {
+	m, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h := hash.NewStructuredHash(api, &m)
+	h.WriteTag(testTag)
+	h.Write(c.A...)
+	h.Write()
+	h.Write(c.B...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func structuredHash(a, b []*big.Int) *big.Int {
+	e := hash.NewEncoder(ecc.BN254.ScalarField())
+	e.WriteTag(testTag)
+	e.Write(a...)
+	e.Write()
+	e.Write(b...)
+	h := cryptomimc.NewMiMC()
+	buf := make([]byte, h.BlockSize())
+	for _, v := range e.Elements() {
+		v.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func TestStructuredHash(t *testing.T) {
+	assert := require.New(t)
+	a := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	b := []*big.Int{big.NewInt(3)}
+
+	witness := &structuredCircuit{
+		A:        []frontend.Variable{1, 2},
+		B:        []frontend.Variable{3},
+		Expected: structuredHash(a, b),
+	}
+	circuit := &structuredCircuit{A: make([]frontend.Variable, 2), B: make([]frontend.Variable, 1)}
+	assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	// the same elements split differently are hashed differently
+	moved := structuredHash(a[:1], append([]*big.Int{a[1]}, b...))
+	assert.NotEqual(0, moved.Cmp(witness.Expected.(*big.Int)))
+	witness.Expected = moved
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
+
+func TestEncoder(t *testing.T) {
+	assert := require.New(t)
+	e := hash.NewEncoder(ecc.BN254.ScalarField())
+	assert.Equal(31, hash.ChunkSize(ecc.BN254.ScalarField()))
+
+	e.WriteTag("ab")
+	chunk := make([]byte, 31)
+	chunk[0], chunk[1] = 'a', 'b'
+	assert.Equal([]*big.Int{big.NewInt(hash.TagMarker), big.NewInt(2), new(big.Int).SetBytes(chunk)}, e.Elements())
+
+	e.Reset()
+	e.WriteTag("")
+	e.Write(big.NewInt(-1))
+	minusOne := new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(1))
+	assert.Equal([]*big.Int{big.NewInt(hash.TagMarker), big.NewInt(0), big.NewInt(hash.SliceMarker), big.NewInt(1), minusOne}, e.Elements())
+}
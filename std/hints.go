@@ -10,6 +10,7 @@ import (
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/rangecheck"
 	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/std/types"
 )
 
 var registerOnce sync.Once
@@ -36,4 +37,5 @@ func registerHints() {
 	solver.RegisterHint(emulated.GetHints()...)
 	solver.RegisterHint(solver.NewHint("count", rangecheck.CountHint))
 	solver.RegisterHint(solver.NewHint("decompose", rangecheck.DecomposeHint))
+	solver.RegisterHint(solver.NewHint("to_bytes", types.BytesHint))
 }
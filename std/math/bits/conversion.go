@@ -17,26 +17,40 @@ const (
 	Ternary Base = 3
 )
 
+// Endianness is the order of the digits of a decomposition.
+type Endianness uint8
+
+const (
+	// LittleEndian orders the digits from the least significant one.
+	LittleEndian Endianness = iota
+
+	// BigEndian orders the digits from the most significant one.
+	BigEndian
+)
+
 // ToBase decomposes scalar v into digits in given base using options opts. The
-// decomposition is in little-endian order.
+// decomposition is in little-endian order, unless WithEndianness is set.
 func ToBase(api frontend.API, base Base, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	var digits []frontend.Variable
 	switch base {
 	case Binary:
-		return toBinary(api, v, opts...)
+		digits = toBinary(api, v, opts...)
 	case Ternary:
-		return toTernary(api, v, opts...)
+		digits = toTernary(api, v, opts...)
 	default:
 		panic("not implemented")
 	}
+	return order(digits, opts)
 }
 
-// FromBase compute from a set of digits its canonical representation in
-// little-endian order.
+// FromBase compute from a set of digits its canonical representation, the
+// digits being in little-endian order unless WithEndianness is set.
 // For example for base 2, it returns Σbi = Σ (2**i * digits[i])
 func FromBase(api frontend.API, base Base, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
 	if len(digits) == 0 {
 		panic("FromBase needs at least 1 digit")
 	}
+	digits = order(digits, opts)
 	switch base {
 	case Binary:
 		return fromBinary(api, digits, opts...)
@@ -47,21 +61,66 @@ func FromBase(api frontend.API, base Base, digits []frontend.Variable, opts ...B
 	}
 }
 
-type baseConversionConfig struct {
-	NbDigits             int
-	UnconstrainedOutputs bool
-	UnconstrainedInputs  bool
+// BaseConversionConfig is the configuration of the conversions, set by the
+// BaseConversionOption.
+type BaseConversionConfig struct {
+	// NbDigits is the number of digits of the decomposition, 0 for the full
+	// decomposition.
+	NbDigits int
+	// Unchecked is set if the digits are not constrained to be valid digits.
+	Unchecked bool
+	// Endianness is the order of the digits.
+	Endianness Endianness
+}
+
+// NewBaseConversionConfig returns the configuration set by opts. It lets the
+// gadgets converting to other representations, such as bytes, take the options
+// of this package.
+func NewBaseConversionConfig(opts ...BaseConversionOption) (*BaseConversionConfig, error) {
+	cfg := new(BaseConversionConfig)
+	for _, o := range opts {
+		if err := o(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// newConfig returns the configuration set by opts, with nbDigits digits if
+// unset. It panics if an option is invalid.
+func newConfig(nbDigits int, opts []BaseConversionOption) *BaseConversionConfig {
+	cfg, err := NewBaseConversionConfig(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.NbDigits == 0 {
+		cfg.NbDigits = nbDigits
+	}
+	return cfg
+}
+
+// order converts the little-endian digits to the order set by opts, and back:
+// both are the identity or the reversal of the digits.
+func order(digits []frontend.Variable, opts []BaseConversionOption) []frontend.Variable {
+	if newConfig(0, opts).Endianness != BigEndian {
+		return digits
+	}
+	res := make([]frontend.Variable, len(digits))
+	for i := range digits {
+		res[len(digits)-1-i] = digits[i]
+	}
+	return res
 }
 
 // BaseConversionOption configures the behaviour of scalar decomposition.
-type BaseConversionOption func(opt *baseConversionConfig) error
+type BaseConversionOption func(opt *BaseConversionConfig) error
 
-// WithNbDigits sets the resulting number of digits (nbDigits) to be used in the base conversion.
+// WithWidth sets the resulting number of digits (nbDigits) to be used in the base conversion.
 // nbDigits must be > 0. If nbDigits is lower than the length of full decomposition and
-// WithUnconstrainedOutputs option is not used, then this function generates an unsatisfiable
-// constraint. If WithNbDigits option is not set, then the full decomposition is returned.
-func WithNbDigits(nbDigits int) BaseConversionOption {
-	return func(opt *baseConversionConfig) error {
+// WithUnchecked option is not used, then this function generates an unsatisfiable
+// constraint. If WithWidth option is not set, then the full decomposition is returned.
+func WithWidth(nbDigits int) BaseConversionOption {
+	return func(opt *BaseConversionConfig) error {
 		if nbDigits <= 0 {
 			return errors.New("nbDigits <= 0")
 		}
@@ -70,24 +129,52 @@ func WithNbDigits(nbDigits int) BaseConversionOption {
 	}
 }
 
-// WithUnconstrainedOutputs sets the bit conversion API to NOT constrain the output bits.
-// This is UNSAFE but is useful when the outputs are already constrained by other circuit
-// constraints.
-// The sum of the digits will is constrained like so Σbi = Σ (base**i * digits[i])
-// But the individual digits are not constrained to be valid digits in base b.
-func WithUnconstrainedOutputs() BaseConversionOption {
-	return func(opt *baseConversionConfig) error {
-		opt.UnconstrainedOutputs = true
+// WithEndianness sets the order of the digits returned by the decompositions
+// and taken by the recompositions. Defaults to LittleEndian.
+func WithEndianness(e Endianness) BaseConversionOption {
+	return func(opt *BaseConversionConfig) error {
+		if e != LittleEndian && e != BigEndian {
+			return errors.New("invalid endianness")
+		}
+		opt.Endianness = e
 		return nil
 	}
 }
 
-// WithUnconstrainedInputs indicates to the FromBase apis to constrain its inputs (digits) to
-// ensure they are valid digits in base b. For example, FromBinary without this option will add
-// 1 constraint per bit to ensure it is either 0 or 1.
-func WithUnconstrainedInputs() BaseConversionOption {
-	return func(opt *baseConversionConfig) error {
-		opt.UnconstrainedInputs = true
+// WithUnchecked sets the conversion APIs to NOT constrain the digits to be valid
+// digits in base b: the outputs of the decompositions, and the inputs of the
+// recompositions. This is UNSAFE but is useful when the digits are already
+// constrained by other circuit constraints.
+// The sum of the digits is still constrained like so Σbi = Σ (base**i * digits[i])
+// in the decompositions.
+func WithUnchecked() BaseConversionOption {
+	return func(opt *BaseConversionConfig) error {
+		opt.Unchecked = true
 		return nil
 	}
 }
+
+// WithNbDigits is an alias of WithWidth.
+//
+// Deprecated: use WithWidth instead.
+func WithNbDigits(nbDigits int) BaseConversionOption {
+	return WithWidth(nbDigits)
+}
+
+// WithUnconstrainedOutputs sets the decompositions to NOT constrain the output
+// digits.
+//
+// Deprecated: use WithUnchecked instead, which has the same effect on the
+// decompositions.
+func WithUnconstrainedOutputs() BaseConversionOption {
+	return WithUnchecked()
+}
+
+// WithUnconstrainedInputs sets the recompositions to NOT constrain the input
+// digits.
+//
+// Deprecated: use WithUnchecked instead, which has the same effect on the
+// recompositions.
+func WithUnconstrainedInputs() BaseConversionOption {
+	return WithUnchecked()
+}
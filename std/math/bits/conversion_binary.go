@@ -25,13 +25,7 @@ func FromBinary(api frontend.API, digits []frontend.Variable, opts ...BaseConver
 
 func fromBinary(api frontend.API, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
 
-	cfg := baseConversionConfig{}
-
-	for _, o := range opts {
-		if err := o(&cfg); err != nil {
-			panic(err)
-		}
-	}
+	cfg := newConfig(0, opts)
 
 	// Σbi = Σ (2**i * b[i])
 	Σbi := frontend.Variable(0)
@@ -39,7 +33,7 @@ func fromBinary(api frontend.API, digits []frontend.Variable, opts ...BaseConver
 	c := big.NewInt(1)
 
 	for i := 0; i < len(digits); i++ {
-		if !cfg.UnconstrainedInputs {
+		if !cfg.Unchecked {
 			api.AssertIsBoolean(digits[i]) // ensures the digits are actual bits
 		}
 
@@ -52,16 +46,7 @@ func fromBinary(api frontend.API, digits []frontend.Variable, opts ...BaseConver
 
 func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
 	// parse options
-	cfg := baseConversionConfig{
-		NbDigits:             api.Compiler().FieldBitLen(),
-		UnconstrainedOutputs: false,
-	}
-
-	for _, o := range opts {
-		if err := o(&cfg); err != nil {
-			panic(err)
-		}
-	}
+	cfg := newConfig(api.Compiler().FieldBitLen(), opts)
 
 	c := big.NewInt(1)
 
@@ -75,7 +60,7 @@ func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOptio
 	for i := 0; i < cfg.NbDigits; i++ {
 		Σbi = api.Add(Σbi, api.Mul(bits[i], c))
 		c.Lsh(c, 1)
-		if !cfg.UnconstrainedOutputs {
+		if !cfg.Unchecked {
 			api.AssertIsBoolean(bits[i])
 		}
 	}
@@ -86,6 +71,28 @@ func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOptio
 	return bits
 }
 
+// AssertIsCanonical asserts that the bits, in little-endian order, are the
+// decomposition of an integer smaller than the modulus of the native field. A
+// decomposition of ToBinary of the full width of the field is not unique
+// otherwise: v and v + modulus can have the same value. The bits must be
+// constrained to be bits.
+func AssertIsCanonical(api frontend.API, bits []frontend.Variable) {
+	bound := new(big.Int).Sub(api.Compiler().Field(), big.NewInt(1))
+	if len(bits) <= bound.BitLen()-1 {
+		return
+	}
+	// eq is 1 while the most significant bits are the ones of the bound: a bit
+	// can then be 1 only where the bit of the bound is 1.
+	var eq frontend.Variable = 1
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			api.AssertIsEqual(api.Mul(eq, bits[i]), 0)
+		} else {
+			eq = api.Mul(eq, bits[i])
+		}
+	}
+}
+
 // IthBit returns the i-tb bit the input. The function expects exactly two
 // integer inputs i and n, takes the little-endian bit representation of n and
 // returns its i-th bit.
@@ -0,0 +1,50 @@
+package bits_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type canonicalCircuit struct {
+	V    frontend.Variable
+	Bits [254]frontend.Variable
+}
+
+func (c *canonicalCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(bits.FromBinary(api, c.Bits[:]), c.V)
+	bits.AssertIsCanonical(api, c.Bits[:])
+	return nil
+}
+
+func TestAssertIsCanonical(t *testing.T) {
+	assert := require.New(t)
+	modulus := ecc.BN254.ScalarField()
+	for _, c := range []struct {
+		name   string
+		v      *big.Int
+		solved bool
+	}{
+		{"zero", big.NewInt(0), true},
+		{"modulus-1", new(big.Int).Sub(modulus, big.NewInt(1)), true},
+		{"modulus", modulus, false},
+		{"modulus+5", new(big.Int).Add(modulus, big.NewInt(5)), false},
+		{"2^254-1", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 254), big.NewInt(1)), false},
+	} {
+		witness := &canonicalCircuit{V: new(big.Int).Mod(c.v, modulus)}
+		for i := range witness.Bits {
+			witness.Bits[i] = c.v.Bit(i)
+		}
+		err := test.IsSolved(&canonicalCircuit{}, witness, modulus)
+		if c.solved {
+			assert.NoError(err, c.name)
+		} else {
+			assert.Error(err, c.name)
+		}
+	}
+}
@@ -27,13 +27,7 @@ func FromTernary(api frontend.API, digits []frontend.Variable, opts ...BaseConve
 }
 
 func fromTernary(api frontend.API, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
-	cfg := baseConversionConfig{}
-
-	for _, o := range opts {
-		if err := o(&cfg); err != nil {
-			panic(err)
-		}
-	}
+	cfg := newConfig(0, opts)
 
 	// Σti = Σ (3**i * b[i])
 	Σti := frontend.Variable(0)
@@ -42,7 +36,7 @@ func fromTernary(api frontend.API, digits []frontend.Variable, opts ...BaseConve
 	base := big.NewInt(3)
 
 	for i := 0; i < len(digits); i++ {
-		if !cfg.UnconstrainedInputs {
+		if !cfg.Unchecked {
 			// TODO ensures the digits are actual trits
 			AssertIsTrit(api, digits[i])
 		}
@@ -57,15 +51,7 @@ func toTernary(api frontend.API, v frontend.Variable, opts ...BaseConversionOpti
 	// parse options
 	nbBits := api.Compiler().FieldBitLen()
 	nbTrits := int(float64(nbBits)/math.Log2(3.0)) + 1
-	cfg := baseConversionConfig{
-		NbDigits: nbTrits,
-	}
-
-	for _, o := range opts {
-		if err := o(&cfg); err != nil {
-			panic(err)
-		}
-	}
+	cfg := newConfig(nbTrits, opts)
 
 	c := big.NewInt(1)
 	b := big.NewInt(3)
@@ -80,7 +66,7 @@ func toTernary(api frontend.API, v frontend.Variable, opts ...BaseConversionOpti
 	for i := 0; i < cfg.NbDigits; i++ {
 		Σti = api.Add(Σti, api.Mul(trits[i], c))
 		c.Mul(c, b)
-		if !cfg.UnconstrainedOutputs {
+		if !cfg.Unchecked {
 			AssertIsTrit(api, trits[i])
 		}
 	}
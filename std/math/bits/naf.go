@@ -21,16 +21,7 @@ func init() {
 // in which non-zero values cannot be adjacent. For example, NAF(13) = [1, 0, -1, 0, 1].
 func ToNAF(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
 	// parse options
-	cfg := baseConversionConfig{
-		NbDigits:             api.Compiler().FieldBitLen(),
-		UnconstrainedOutputs: false,
-	}
-
-	for _, o := range opts {
-		if err := o(&cfg); err != nil {
-			panic(err)
-		}
-	}
+	cfg := newConfig(api.Compiler().FieldBitLen(), opts)
 
 	c := big.NewInt(1)
 
@@ -44,7 +35,7 @@ func ToNAF(api frontend.API, v frontend.Variable, opts ...BaseConversionOption)
 	for i := 0; i < cfg.NbDigits; i++ {
 		Σbi = api.Add(Σbi, api.Mul(bits[i], c))
 		c.Lsh(c, 1)
-		if !cfg.UnconstrainedOutputs {
+		if !cfg.Unchecked {
 			// b * (1 - b) * (1 + b) == 0
 			// TODO this adds 3 constraint, not 2. Need api.Compiler().AddConstraint(...)
 			b := bits[i]
@@ -56,7 +47,7 @@ func ToNAF(api frontend.API, v frontend.Variable, opts ...BaseConversionOption)
 	// record the constraint Σ (2**i * b[i]) == v
 	api.AssertIsEqual(Σbi, v)
 
-	return bits
+	return order(bits, opts)
 }
 
 func nNaf(_ *big.Int, inputs []*big.Int, results []*big.Int) error {
@@ -20,7 +20,7 @@ func (f *Field[T]) ToBits(a *Element[T]) []frontend.Variable {
 	var fullBits []frontend.Variable
 	var limbBits []frontend.Variable
 	for i := 0; i < len(a.Limbs); i++ {
-		limbBits = bits.ToBinary(f.api, f.api.Add(a.Limbs[i], carry), bits.WithNbDigits(int(f.fParams.BitsPerLimb()+a.overflow)))
+		limbBits = bits.ToBinary(f.api, f.api.Add(a.Limbs[i], carry), bits.WithWidth(int(f.fParams.BitsPerLimb()+a.overflow)))
 		fullBits = append(fullBits, limbBits[:f.fParams.BitsPerLimb()]...)
 		if a.overflow > 0 {
 			carry = bits.FromBinary(f.api, limbBits[f.fParams.BitsPerLimb():])
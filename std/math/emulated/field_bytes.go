@@ -0,0 +1,159 @@
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// FromBytes returns the Element of the integer encoded by the bytes bs, of any
+// length, reduced modulo the emulated modulus. The bytes are in little-endian
+// order unless set with [bits.WithEndianness], and are range checked unless
+// [bits.WithUnchecked] is set.
+//
+// The result is the canonical representative in [0, modulus): it is proved to
+// be congruent to the integer, and smaller than the modulus. This is the
+// reduction of a message hash to a scalar done by the signature schemes, for
+// instance of the 64 bytes of SHA-512 for Ed25519.
+func (f *Field[T]) FromBytes(bs []frontend.Variable, opts ...bits.BaseConversionOption) *Element[T] {
+	cfg, err := bits.NewBaseConversionConfig(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if len(bs) == 0 {
+		return f.Zero()
+	}
+	if cfg.Endianness == bits.BigEndian {
+		bs = reversed(bs)
+	}
+	limbs := f.bytesToLimbs(bs, !cfg.Unchecked)
+
+	// the integer is the sum of chunks of NbLimbs limbs, the i-th one shifted
+	// by i·NbLimbs·BitsPerLimb bits, which we evaluate with Horner's method from
+	// the most significant chunk
+	nbLimbs := int(f.fParams.NbLimbs())
+	shift := new(big.Int).Lsh(big.NewInt(1), f.fParams.NbLimbs()*f.fParams.BitsPerLimb())
+	shift.Mod(shift, f.fParams.Modulus())
+	var res *Element[T]
+	for start := ((len(limbs) - 1) / nbLimbs) * nbLimbs; start >= 0; start -= nbLimbs {
+		chunk := make([]frontend.Variable, nbLimbs)
+		for i := range chunk {
+			chunk[i] = 0
+			if start+i < len(limbs) {
+				chunk[i] = limbs[start+i]
+			}
+		}
+		e := f.newInternalElement(chunk, 0)
+		if res == nil {
+			res = e
+		} else {
+			res = f.Add(f.MulMod(res, f.NewElement(shift)), e)
+		}
+	}
+	return f.canonical(res)
+}
+
+// ToBytes returns the bytes of the canonical representative of a, as many as
+// needed for the emulated modulus. The bytes are in little-endian order unless
+// set with [bits.WithEndianness], and [bits.WithWidth] pads them with zeros to
+// the given number of bytes. The bytes are constrained to be bytes.
+func (f *Field[T]) ToBytes(a *Element[T], opts ...bits.BaseConversionOption) []frontend.Variable {
+	cfg, err := bits.NewBaseConversionConfig(opts...)
+	if err != nil {
+		panic(err)
+	}
+	nbBits := f.fParams.Modulus().BitLen()
+	nbBytes := (nbBits + 7) / 8
+	if cfg.NbDigits != 0 {
+		if cfg.NbDigits < nbBytes {
+			panic(fmt.Sprintf("the elements have %d bytes, not %d", nbBytes, cfg.NbDigits))
+		}
+		nbBytes = cfg.NbDigits
+	}
+
+	// the bits above the size of the modulus are zero in the canonical
+	// representative
+	abits := f.ToBits(f.canonical(a))[:nbBits]
+	res := make([]frontend.Variable, nbBytes)
+	for i := range res {
+		res[i] = 0
+		if 8*i < nbBits {
+			end := 8*i + 8
+			if end > nbBits {
+				end = nbBits
+			}
+			res[i] = bits.FromBinary(f.api, abits[8*i:end], bits.WithUnchecked())
+		}
+	}
+	if cfg.Endianness == bits.BigEndian {
+		res = reversed(res)
+	}
+	return res
+}
+
+// canonical returns the representative of a in [0, modulus), proved to be
+// congruent to a and smaller than the modulus. Unlike [Field.Reduce], it
+// reduces elements without overflow which are not smaller than the modulus.
+func (f *Field[T]) canonical(a *Element[T]) *Element[T] {
+	e, err := f.computeRemHint(a, f.Modulus())
+	if err != nil {
+		panic(fmt.Sprintf("reduction hint: %v", err))
+	}
+	f.AssertIsEqual(e, a)
+	f.AssertIsLessOrEqual(e, f.NewElement(new(big.Int).Sub(f.fParams.Modulus(), big.NewInt(1))))
+	return e
+}
+
+// bytesToLimbs returns the limbs of BitsPerLimb bits of the little-endian bytes,
+// asserting that the bytes fit in 8 bits if check is set.
+func (f *Field[T]) bytesToLimbs(bs []frontend.Variable, check bool) []frontend.Variable {
+	bitsPerLimb := int(f.fParams.BitsPerLimb())
+	nbLimbs := (8*len(bs) + bitsPerLimb - 1) / bitsPerLimb
+	limbs := make([]frontend.Variable, nbLimbs)
+
+	if bitsPerLimb%8 == 0 {
+		// the limbs are made of whole bytes
+		for i := range bs {
+			if check {
+				f.checker.Check(bs[i], 8)
+			}
+		}
+		bytesPerLimb := bitsPerLimb / 8
+		for i := range limbs {
+			var limb frontend.Variable = 0
+			for j := 0; j < bytesPerLimb && i*bytesPerLimb+j < len(bs); j++ {
+				limb = f.api.Add(limb, f.api.Mul(bs[i*bytesPerLimb+j], new(big.Int).Lsh(big.NewInt(1), uint(8*j))))
+			}
+			limbs[i] = limb
+		}
+		return limbs
+	}
+
+	// the bytes straddle the limbs: go through the bits
+	bbits := make([]frontend.Variable, 0, 8*len(bs))
+	for i := range bs {
+		var opts []bits.BaseConversionOption
+		if !check {
+			opts = append(opts, bits.WithUnchecked())
+		}
+		bbits = append(bbits, bits.ToBinary(f.api, bs[i], append(opts, bits.WithWidth(8))...)...)
+	}
+	for i := range limbs {
+		end := (i + 1) * bitsPerLimb
+		if end > len(bbits) {
+			end = len(bbits)
+		}
+		limbs[i] = bits.FromBinary(f.api, bbits[i*bitsPerLimb:end], bits.WithUnchecked())
+	}
+	return limbs
+}
+
+func reversed(vs []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(vs))
+	for i := range vs {
+		res[len(vs)-1-i] = vs[i]
+	}
+	return res
+}
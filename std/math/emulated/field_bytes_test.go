@@ -0,0 +1,92 @@
+package emulated_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// secp256k1Fr86 is the scalar field of secp256k1 on limbs of 86 bits, which
+// don't hold whole bytes.
+type secp256k1Fr86 struct{}
+
+func (secp256k1Fr86) NbLimbs() uint     { return 3 }
+func (secp256k1Fr86) BitsPerLimb() uint { return 86 }
+func (secp256k1Fr86) IsPrime() bool     { return true }
+func (secp256k1Fr86) Modulus() *big.Int { return emulated.Secp256k1Fr{}.Modulus() }
+
+type bytesCircuit[T emulated.FieldParams] struct {
+	// In are big-endian bytes
+	In       [64]frontend.Variable
+	Expected emulated.Element[T]
+	// Out are the little-endian bytes of Expected
+	Out [32]frontend.Variable
+}
+
+func (c *bytesCircuit[T]) Define(api frontend.API) error {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.FromBytes(c.In[:], bits.WithEndianness(bits.BigEndian))
+	f.AssertIsEqual(res, &c.Expected)
+	out := f.ToBytes(res)
+	for i := range out {
+		api.AssertIsEqual(out[i], c.Out[i])
+	}
+	return nil
+}
+
+func TestFromBytes(t *testing.T) {
+	testFromBytes[emulated.Secp256k1Fr](t)
+	testFromBytes[secp256k1Fr86](t)
+}
+
+func testFromBytes[T emulated.FieldParams](t *testing.T) {
+	assert := require.New(t)
+	var fr T
+
+	// an integer of 512 bits, whose halves are larger than the modulus
+	in := make([]byte, 64)
+	for i := range in {
+		in[i] = 0xff - byte(i)
+	}
+	v := new(big.Int).SetBytes(in)
+	v.Mod(v, fr.Modulus())
+
+	witness := &bytesCircuit[T]{Expected: emulated.ValueOf[T](v)}
+	for i := range in {
+		witness.In[i] = in[i]
+	}
+	out := v.FillBytes(make([]byte, 32))
+	for i := range out {
+		witness.Out[i] = out[31-i]
+	}
+	assert.NoError(test.IsSolved(&bytesCircuit[T]{}, witness, ecc.BN254.ScalarField()))
+
+	// a congruent but non-canonical encoding
+	wrong := *witness
+	for i := range wrong.Out {
+		wrong.Out[i] = 0
+	}
+	nonCanonical := new(big.Int).Add(v, fr.Modulus())
+	if nonCanonical.BitLen() <= 256 {
+		b := nonCanonical.FillBytes(make([]byte, 32))
+		for i := range b {
+			wrong.Out[i] = b[31-i]
+		}
+		assert.Error(test.IsSolved(&bytesCircuit[T]{}, &wrong, ecc.BN254.ScalarField()))
+	}
+
+	// the same integer, with a digit which isn't a byte
+	wrong = *witness
+	wrong.In[4] = in[4] - 1
+	wrong.In[5] = int(in[5]) + 256
+	assert.Error(test.IsSolved(&bytesCircuit[T]{}, &wrong, ecc.BN254.ScalarField()))
+}
@@ -106,6 +106,56 @@ func (f *Field[T]) MulMod(a, b *Element[T]) *Element[T] {
 	return f.Reduce(r)
 }
 
+// ModMul computes a*b modulo modulus, which is given as an element instead of
+// being the modulus of the field: the field then only sets the width of the
+// integers, for instance [Mod1e2048] for the RSA moduli of 2048 bits. The
+// inputs must be reduced elements, and a*b/modulus must fit in the width for
+// the hint to be solved, which holds when a and b are smaller than modulus.
+// The result is congruent to a*b, and smaller than modulus when computed by
+// the hint, but it is not asserted to be.
+func (f *Field[T]) ModMul(a, b, modulus *Element[T]) *Element[T] {
+	for _, e := range []*Element[T]{a, b, modulus} {
+		f.enforceWidthConditional(e)
+		if e.overflow != 0 || len(e.Limbs) != int(f.fParams.NbLimbs()) {
+			panic("modular multiplication of unreduced elements")
+		}
+	}
+	q, r, err := f.computeModMulHint(a, b, modulus)
+	if err != nil {
+		panic(fmt.Sprintf("modular multiplication hint: %v", err))
+	}
+	// a*b == q*modulus + r as integers, the products being computed without
+	// reduction
+	nextOverflow, err := f.mulPreCond(a, b)
+	if err != nil {
+		panic(err)
+	}
+	var ab []frontend.Variable
+	ba, aConst := f.constantValue(a)
+	bb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		v := make([]*big.Int, 2*len(a.Limbs))
+		for i := range v {
+			v[i] = new(big.Int)
+		}
+		if err := decompose(ba.Mul(ba, bb), f.fParams.BitsPerLimb(), v); err != nil {
+			panic(err)
+		}
+		ab = make([]frontend.Variable, len(v))
+		for i := range v {
+			ab[i] = v[i]
+		}
+	} else {
+		ab = f.mul(a, b, nextOverflow).Limbs
+	}
+	qm := f.mul(q, modulus, nextOverflow).Limbs
+	for i := range r.Limbs {
+		qm[i] = f.api.Add(qm[i], r.Limbs[i])
+	}
+	f.assertLimbsEqualitySlow(f.api, ab, qm, f.fParams.BitsPerLimb(), nextOverflow+1)
+	return r
+}
+
 // MulConst multiplies a by a constant c and returns it. We assume that the
 // input constant is "small", so that we can compute the product by multiplying
 // all individual limbs with the constant. If it is not small, then use the
@@ -291,6 +341,24 @@ func (f *Field[T]) Select(selector frontend.Variable, a, b *Element[T]) *Element
 	return e
 }
 
+// IsZero returns 1 if a is zero modulo the modulus and 0 otherwise. The
+// element is reduced to its canonical representative first, so that a multiple
+// of the modulus is zero.
+func (f *Field[T]) IsZero(a *Element[T]) frontend.Variable {
+	if v, ok := f.constantValue(a); ok {
+		if new(big.Int).Mod(v, f.fParams.Modulus()).Sign() == 0 {
+			return 1
+		}
+		return 0
+	}
+	ca := f.canonical(a)
+	res := f.api.IsZero(ca.Limbs[0])
+	for i := 1; i < len(ca.Limbs); i++ {
+		res = f.api.And(res, f.api.IsZero(ca.Limbs[i]))
+	}
+	return res
+}
+
 // Lookup2 performs two-bit lookup between a, b, c, d based on lookup bits b1
 // and b2 such that:
 //   - if b0=0 and b1=0, sets to a,
@@ -0,0 +1,62 @@
+package emulated_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// mod1e256 holds the integers of 256 bits, for a variable modulus.
+type mod1e256 struct{}
+
+func (mod1e256) NbLimbs() uint     { return 4 }
+func (mod1e256) BitsPerLimb() uint { return 64 }
+func (mod1e256) IsPrime() bool     { return false }
+func (mod1e256) Modulus() *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+}
+
+type modMulCircuit struct {
+	A, B, Modulus, Expected emulated.Element[mod1e256]
+}
+
+func (c *modMulCircuit) Define(api frontend.API) error {
+	f, err := emulated.NewField[mod1e256](api)
+	if err != nil {
+		return err
+	}
+	res := f.ModMul(&c.A, &c.B, &c.Modulus)
+	f.AssertLimbsEquality(res, &c.Expected)
+	return nil
+}
+
+func TestModMul(t *testing.T) {
+	assert := require.New(t)
+
+	modulus := emulated.Secp256k1Fp{}.Modulus()
+	a, err := rand.Int(rand.Reader, modulus)
+	assert.NoError(err)
+	b, err := rand.Int(rand.Reader, modulus)
+	assert.NoError(err)
+	expected := new(big.Int).Mul(a, b)
+	expected.Mod(expected, modulus)
+
+	witness := &modMulCircuit{
+		A:        emulated.ValueOf[mod1e256](a),
+		B:        emulated.ValueOf[mod1e256](b),
+		Modulus:  emulated.ValueOf[mod1e256](modulus),
+		Expected: emulated.ValueOf[mod1e256](expected),
+	}
+	assert.NoError(test.IsSolved(&modMulCircuit{}, witness, ecc.BN254.ScalarField()))
+
+	// the product reduced by the modulus of mod1e256 instead
+	wrong := *witness
+	wrong.Expected = emulated.ValueOf[mod1e256](new(big.Int).Mod(new(big.Int).Mul(a, b), mod1e256{}.Modulus()))
+	assert.Error(test.IsSolved(&modMulCircuit{}, &wrong, ecc.BN254.ScalarField()))
+}
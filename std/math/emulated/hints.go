@@ -24,6 +24,7 @@ func GetHints() []solver.Hint {
 		solver.NewHint("multiplication", MultiplicationHint),
 		solver.NewHint("rem", RemHint),
 		solver.NewHint("right_shift", RightShift),
+		solver.NewHint("mod_mul", ModMulHint),
 	}
 }
 
@@ -116,6 +117,60 @@ func RemHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	return nil
 }
 
+// computeModMulHint packs the inputs for the ModMulHint hint function and
+// returns the quotient and the remainder of a*b by modulus.
+func (f *Field[T]) computeModMulHint(a, b, modulus *Element[T]) (quo, rem *Element[T], err error) {
+	nbLimbs := int(f.fParams.NbLimbs())
+	hintInputs := []frontend.Variable{
+		f.fParams.BitsPerLimb(),
+		nbLimbs,
+	}
+	hintInputs = append(hintInputs, a.Limbs...)
+	hintInputs = append(hintInputs, b.Limbs...)
+	hintInputs = append(hintInputs, modulus.Limbs...)
+	limbs, err := f.api.NewHint(solver.NewHint("mod_mul", ModMulHint), 2*nbLimbs, hintInputs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.packLimbs(limbs[:nbLimbs], true), f.packLimbs(limbs[nbLimbs:], true), nil
+}
+
+// ModMulHint sets the outputs to the quotient and the remainder of the product
+// of the first two elements of the inputs by the third one. See internal method
+// computeModMulHint for the input packing.
+func ModMulHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 {
+		return fmt.Errorf("input must be at least two elements")
+	}
+	nbBits := uint(inputs[0].Uint64())
+	nbLimbs := int(inputs[1].Int64())
+	if len(inputs) != 2+3*nbLimbs {
+		return fmt.Errorf("input invalid")
+	}
+	if len(outputs) != 2*nbLimbs {
+		return fmt.Errorf("result does not fit into output")
+	}
+	values := make([]*big.Int, 3)
+	for i := range values {
+		values[i] = new(big.Int)
+		if err := recompose(inputs[2+i*nbLimbs:2+(i+1)*nbLimbs], nbBits, values[i]); err != nil {
+			return fmt.Errorf("recompose value: %w", err)
+		}
+	}
+	if values[2].Sign() == 0 {
+		return fmt.Errorf("modulus is zero")
+	}
+	q, r := new(big.Int).Mul(values[0], values[1]), new(big.Int)
+	q.QuoRem(q, values[2], r)
+	if err := decompose(q, nbBits, outputs[:nbLimbs]); err != nil {
+		return fmt.Errorf("decompose quotient: %w", err)
+	}
+	if err := decompose(r, nbBits, outputs[nbLimbs:]); err != nil {
+		return fmt.Errorf("decompose remainder: %w", err)
+	}
+	return nil
+}
+
 // computeQuoHint packs the inputs for QuoHint function and returns z = x / y
 // (discards remainder)
 func (f *Field[T]) computeQuoHint(x *Element[T]) (z *Element[T], err error) {
@@ -17,12 +17,31 @@ type FieldParams interface {
 var (
 	qSecp256k1, rSecp256k1 *big.Int
 	qGoldilocks            *big.Int
+	rBandersnatch          *big.Int
+	qEd448, rEd448         *big.Int
+	qP384, rP384           *big.Int
+	qBrainpoolP256r1       *big.Int
+	rBrainpoolP256r1       *big.Int
+	qBrainpoolP384r1       *big.Int
+	rBrainpoolP384r1       *big.Int
+	qMod1e2048, qMod1e4096 *big.Int
 )
 
 func init() {
 	qSecp256k1, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
 	rSecp256k1, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
 	qGoldilocks, _ = new(big.Int).SetString("ffffffff00000001", 16)
+	rBandersnatch, _ = new(big.Int).SetString("1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1", 16)
+	qEd448, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 16)
+	rEd448, _ = new(big.Int).SetString("3fffffffffffffffffffffffffffffffffffffffffffffffffffffff7cca23e9c44edb49aed63690216cc2728dc58f552378c292ab5844f3", 16)
+	qP384, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffff", 16)
+	rP384, _ = new(big.Int).SetString("ffffffffffffffffffffffffffffffffffffffffffffffffc7634d81f4372ddf581a0db248b0a77aecec196accc52973", 16)
+	qBrainpoolP256r1, _ = new(big.Int).SetString("a9fb57dba1eea9bc3e660a909d838d726e3bf623d52620282013481d1f6e5377", 16)
+	rBrainpoolP256r1, _ = new(big.Int).SetString("a9fb57dba1eea9bc3e660a909d838d718c397aa3b561a6f7901e0e82974856a7", 16)
+	qBrainpoolP384r1, _ = new(big.Int).SetString("8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b412b1da197fb71123acd3a729901d1a71874700133107ec53", 16)
+	rBrainpoolP384r1, _ = new(big.Int).SetString("8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b31f166e6cac0425a7cf3ab6af6b7fc3103b883202e9046565", 16)
+	qMod1e2048 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 2048), big.NewInt(1))
+	qMod1e4096 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 4096), big.NewInt(1))
 }
 
 // Goldilocks provide type parametrization for emulated field on 1 limb of width 64bits
@@ -86,3 +105,147 @@ func (fp BLS12377Fp) NbLimbs() uint     { return 6 }
 func (fp BLS12377Fp) BitsPerLimb() uint { return 64 }
 func (fp BLS12377Fp) IsPrime() bool     { return true }
 func (fp BLS12377Fp) Modulus() *big.Int { return ecc.BLS12_377.BaseField() }
+
+// BLS12381Fp provide type parametrization for emulated field on 6 limb of width
+// 64bits for modulus
+// 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab.
+// This is the base field of the BLS12-381 curve.
+type BLS12381Fp struct{}
+
+func (fp BLS12381Fp) NbLimbs() uint     { return 6 }
+func (fp BLS12381Fp) BitsPerLimb() uint { return 64 }
+func (fp BLS12381Fp) IsPrime() bool     { return true }
+func (fp BLS12381Fp) Modulus() *big.Int { return ecc.BLS12_381.BaseField() }
+
+// BLS12381Fr provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus
+// 0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001. This is
+// the scalar field of the BLS12-381 curve.
+type BLS12381Fr struct{}
+
+func (fp BLS12381Fr) NbLimbs() uint     { return 4 }
+func (fp BLS12381Fr) BitsPerLimb() uint { return 64 }
+func (fp BLS12381Fr) IsPrime() bool     { return true }
+func (fp BLS12381Fr) Modulus() *big.Int { return ecc.BLS12_381.ScalarField() }
+
+// BandersnatchFr provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus
+// 0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1. This is
+// the scalar field of the Bandersnatch curve, defined over the scalar field of
+// the BLS12-381 curve.
+type BandersnatchFr struct{}
+
+func (fp BandersnatchFr) NbLimbs() uint     { return 4 }
+func (fp BandersnatchFr) BitsPerLimb() uint { return 64 }
+func (fp BandersnatchFr) IsPrime() bool     { return true }
+func (fp BandersnatchFr) Modulus() *big.Int { return rBandersnatch }
+
+// Ed448Fp provides type parametrization for emulated field on 7 limbs of width
+// 64bits for modulus 2^448 - 2^224 - 1 (the "Goldilocks" prime of Ed448, not to
+// be confused with the 64 bits prime of [Goldilocks]). This is the base field
+// of the Ed448 curve.
+type Ed448Fp struct{}
+
+func (fp Ed448Fp) NbLimbs() uint     { return 7 }
+func (fp Ed448Fp) BitsPerLimb() uint { return 64 }
+func (fp Ed448Fp) IsPrime() bool     { return true }
+func (fp Ed448Fp) Modulus() *big.Int { return qEd448 }
+
+// Ed448Fr provides type parametrization for emulated field on 7 limbs of width
+// 64bits for modulus
+// 0x3fffffffffffffffffffffffffffffffffffffffffffffffffffffff7cca23e9c44edb49aed63690216cc2728dc58f552378c292ab5844f3.
+// This is the order of the prime subgroup of the Ed448 curve.
+type Ed448Fr struct{}
+
+func (fp Ed448Fr) NbLimbs() uint     { return 7 }
+func (fp Ed448Fr) BitsPerLimb() uint { return 64 }
+func (fp Ed448Fr) IsPrime() bool     { return true }
+func (fp Ed448Fr) Modulus() *big.Int { return rEd448 }
+
+// P384Fp provides type parametrization for emulated field on 6 limbs of width
+// 64bits for modulus
+// 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffff.
+// This is the base field of the NIST P-384 curve.
+type P384Fp struct{}
+
+func (fp P384Fp) NbLimbs() uint     { return 6 }
+func (fp P384Fp) BitsPerLimb() uint { return 64 }
+func (fp P384Fp) IsPrime() bool     { return true }
+func (fp P384Fp) Modulus() *big.Int { return qP384 }
+
+// P384Fr provides type parametrization for emulated field on 6 limbs of width
+// 64bits for modulus
+// 0xffffffffffffffffffffffffffffffffffffffffffffffffc7634d81f4372ddf581a0db248b0a77aecec196accc52973.
+// This is the scalar field of the NIST P-384 curve.
+type P384Fr struct{}
+
+func (fp P384Fr) NbLimbs() uint     { return 6 }
+func (fp P384Fr) BitsPerLimb() uint { return 64 }
+func (fp P384Fr) IsPrime() bool     { return true }
+func (fp P384Fr) Modulus() *big.Int { return rP384 }
+
+// BrainpoolP256r1Fp provides type parametrization for emulated field on 4 limbs
+// of width 64bits for modulus
+// 0xa9fb57dba1eea9bc3e660a909d838d726e3bf623d52620282013481d1f6e5377. This is
+// the base field of the brainpoolP256r1 curve (RFC 5639).
+type BrainpoolP256r1Fp struct{}
+
+func (fp BrainpoolP256r1Fp) NbLimbs() uint     { return 4 }
+func (fp BrainpoolP256r1Fp) BitsPerLimb() uint { return 64 }
+func (fp BrainpoolP256r1Fp) IsPrime() bool     { return true }
+func (fp BrainpoolP256r1Fp) Modulus() *big.Int { return qBrainpoolP256r1 }
+
+// BrainpoolP256r1Fr provides type parametrization for emulated field on 4 limbs
+// of width 64bits for modulus
+// 0xa9fb57dba1eea9bc3e660a909d838d718c397aa3b561a6f7901e0e82974856a7. This is
+// the scalar field of the brainpoolP256r1 curve (RFC 5639).
+type BrainpoolP256r1Fr struct{}
+
+func (fp BrainpoolP256r1Fr) NbLimbs() uint     { return 4 }
+func (fp BrainpoolP256r1Fr) BitsPerLimb() uint { return 64 }
+func (fp BrainpoolP256r1Fr) IsPrime() bool     { return true }
+func (fp BrainpoolP256r1Fr) Modulus() *big.Int { return rBrainpoolP256r1 }
+
+// BrainpoolP384r1Fp provides type parametrization for emulated field on 6 limbs
+// of width 64bits for modulus
+// 0x8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b412b1da197fb71123acd3a729901d1a71874700133107ec53.
+// This is the base field of the brainpoolP384r1 curve (RFC 5639).
+type BrainpoolP384r1Fp struct{}
+
+func (fp BrainpoolP384r1Fp) NbLimbs() uint     { return 6 }
+func (fp BrainpoolP384r1Fp) BitsPerLimb() uint { return 64 }
+func (fp BrainpoolP384r1Fp) IsPrime() bool     { return true }
+func (fp BrainpoolP384r1Fp) Modulus() *big.Int { return qBrainpoolP384r1 }
+
+// BrainpoolP384r1Fr provides type parametrization for emulated field on 6 limbs
+// of width 64bits for modulus
+// 0x8cb91e82a3386d280f5d6f7e50e641df152f7109ed5456b31f166e6cac0425a7cf3ab6af6b7fc3103b883202e9046565.
+// This is the scalar field of the brainpoolP384r1 curve (RFC 5639).
+type BrainpoolP384r1Fr struct{}
+
+func (fp BrainpoolP384r1Fr) NbLimbs() uint     { return 6 }
+func (fp BrainpoolP384r1Fr) BitsPerLimb() uint { return 64 }
+func (fp BrainpoolP384r1Fr) IsPrime() bool     { return true }
+func (fp BrainpoolP384r1Fr) Modulus() *big.Int { return rBrainpoolP384r1 }
+
+// Mod1e2048 provides type parametrization for emulated arithmetic on 32 limbs
+// of width 64bits for modulus 2^2048-1, which is not prime. It holds the
+// integers of 2048 bits to be multiplied modulo a variable modulus with
+// [Field.ModMul], such as the RSA moduli of 2048 bits.
+type Mod1e2048 struct{}
+
+func (fp Mod1e2048) NbLimbs() uint     { return 32 }
+func (fp Mod1e2048) BitsPerLimb() uint { return 64 }
+func (fp Mod1e2048) IsPrime() bool     { return false }
+func (fp Mod1e2048) Modulus() *big.Int { return qMod1e2048 }
+
+// Mod1e4096 provides type parametrization for emulated arithmetic on 64 limbs
+// of width 64bits for modulus 2^4096-1, which is not prime. It holds the
+// integers of 4096 bits to be multiplied modulo a variable modulus with
+// [Field.ModMul], such as the RSA moduli of 4096 bits.
+type Mod1e4096 struct{}
+
+func (fp Mod1e4096) NbLimbs() uint     { return 64 }
+func (fp Mod1e4096) BitsPerLimb() uint { return 64 }
+func (fp Mod1e4096) IsPrime() bool     { return false }
+func (fp Mod1e4096) Modulus() *big.Int { return qMod1e4096 }
@@ -0,0 +1,97 @@
+// Package multicommit shares the commitment of a circuit between the gadgets
+// which need an in-circuit random challenge.
+//
+// The backends support a single commitment per circuit (see
+// [frontend.Committer]). Gadgets building arguments on random challenges
+// (amortized equality checks, permutation or lookup arguments, ...) register
+// the variables they need to commit to with [WithCommitment]. Once the circuit
+// is defined, all the registered variables are committed to at once, and each
+// callback is called with its own challenge derived from the commitment.
+package multicommit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+type ctxMulticommitterKey struct{}
+
+// WithCommitmentFn is the function called with a challenge derived from the
+// commitment to the variables given to WithCommitment.
+type WithCommitmentFn func(api frontend.API, commitment frontend.Variable) error
+
+type multicommitter struct {
+	vars   []frontend.Variable
+	cbs    []WithCommitmentFn
+	closed bool
+	// number of deferred callbacks when the commitment was last deferred
+	nbDeferred int
+}
+
+// WithCommitment schedules fn to be called with a challenge derived from a
+// commitment to committedVariables, and to the variables given to the other
+// calls of WithCommitment in the circuit.
+//
+// The commitment is made once the circuit and the callbacks it deferred (see
+// [frontend.Compiler.Defer]) are defined: WithCommitment may be called from the
+// deferred callbacks, but not from fn. It panics if the compiler doesn't support
+// commitments.
+func WithCommitment(api frontend.API, fn WithCommitmentFn, committedVariables ...frontend.Variable) {
+	if _, ok := api.(frontend.Committer); !ok {
+		panic("compiler doesn't support commitments")
+	}
+	mct := getCommitter(api)
+	if mct.closed {
+		panic("WithCommitment called after the commitment was made")
+	}
+	mct.vars = append(mct.vars, committedVariables...)
+	mct.cbs = append(mct.cbs, fn)
+}
+
+func getCommitter(api frontend.API) *multicommitter {
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		panic("builder should implement key-value store")
+	}
+	if mct := kv.GetKeyValue(ctxMulticommitterKey{}); mct != nil {
+		if mct, ok := mct.(*multicommitter); ok {
+			return mct
+		}
+		panic("stored multicommitter is not valid")
+	}
+	mct := &multicommitter{}
+	kv.SetKeyValue(ctxMulticommitterKey{}, mct)
+	api.Compiler().Defer(mct.commit)
+	mct.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+	return mct
+}
+
+// commit commits to the registered variables and calls the callbacks. Any
+// callback deferred after it may still call WithCommitment, so commit postpones
+// itself until it is the last deferred callback.
+func (mct *multicommitter) commit(api frontend.API) error {
+	if circuitdefer.NbPendingAfter[func(frontend.API) error](api.Compiler(), mct.nbDeferred, false) > 0 {
+		circuitdefer.Postpone(api.Compiler(), mct.commit)
+		mct.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+		return nil
+	}
+	mct.closed = true
+
+	commitment, err := api.(frontend.Committer).Commit(mct.vars...)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	// the i-th callback is given commitment^(2^i)
+	for i, cb := range mct.cbs {
+		if i > 0 {
+			commitment = api.Mul(commitment, commitment)
+		}
+		if err := cb(api, commitment); err != nil {
+			return fmt.Errorf("callback %d: %w", i, err)
+		}
+	}
+	return nil
+}
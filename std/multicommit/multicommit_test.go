@@ -0,0 +1,65 @@
+package multicommit_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+)
+
+// permutationCircuit checks that B is a permutation of A with the grand product
+// argument ∏(r - Aᵢ) = ∏(r - Bᵢ), and uses other gadgets committing to variables.
+type permutationCircuit struct {
+	A, B [4]frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	committed := append(c.A[:], c.B[:]...)
+	multicommit.WithCommitment(api, func(api frontend.API, r frontend.Variable) error {
+		var left, right frontend.Variable = 1, 1
+		for i := range c.A {
+			left = api.Mul(left, api.Sub(r, c.A[i]))
+			right = api.Mul(right, api.Sub(r, c.B[i]))
+		}
+		api.AssertIsEqual(left, right)
+		return nil
+	}, committed...)
+
+	// range checks with commitments, including from a deferred callback
+	rc := rangecheck.New(api)
+	rc.Check(c.A[0], 8)
+	api.Compiler().Defer(func(api frontend.API) error {
+		rc.Check(c.B[0], 8)
+		// and a commitment registered from a deferred callback
+		multicommit.WithCommitment(api, func(api frontend.API, r frontend.Variable) error {
+			api.AssertIsDifferent(api.Sub(r, c.A[1]), 0)
+			return nil
+		}, c.A[1])
+		return nil
+	})
+	return nil
+}
+
+func TestWithCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	// plonkFRI doesn't support commitments
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+	assert.ProverSucceeded(&permutationCircuit{}, &permutationCircuit{
+		A: [4]frontend.Variable{1, 2, 3, 4},
+		B: [4]frontend.Variable{3, 1, 4, 2},
+	}, opts...)
+	assert.ProverFailed(&permutationCircuit{}, &permutationCircuit{
+		A: [4]frontend.Variable{1, 2, 3, 4},
+		B: [4]frontend.Variable{3, 1, 4, 1},
+	}, opts...)
+	// out of range
+	assert.ProverFailed(&permutationCircuit{}, &permutationCircuit{
+		A: [4]frontend.Variable{256, 2, 3, 4},
+		B: [4]frontend.Variable{256, 4, 3, 2},
+	}, opts...)
+}
@@ -0,0 +1,121 @@
+// Package nullifier provides a ZKP-circuit function deriving nullifiers, and its
+// native counterpart.
+//
+// A nullifier is the hash
+//
+//	H(domain, secretKey, externalNullifier, epoch)
+//
+// It is unique per secret key, external nullifier (typically the identifier of
+// an election or of an action) and epoch, and doesn't reveal the secret key.
+// Publishing it with a proof lets a verifier detect double actions without
+// linking them to a key. Actions of the same epoch share a nullifier: with
+// "latest wins" semantics, such as vote overwrites, the verifier keeps the
+// latest action per nullifier; moving to the next epoch allows a new action.
+//
+// The domain separates the nullifiers of different applications, and of the
+// versions of an application (see WithDomain and WithVersion): a nullifier
+// derived in a domain is unrelated to the nullifiers of the same key in other
+// domains.
+package nullifier
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// DefaultDomain is the domain of the nullifiers derived without WithDomain.
+const DefaultDomain = "gnark/nullifier"
+
+// Config is the configuration of the derivation of nullifiers.
+type Config struct {
+	// Domain is the domain separation tag.
+	Domain string
+	// Version is the version of the application in the domain.
+	Version uint64
+}
+
+// Option configures the derivation of nullifiers.
+type Option func(*Config) error
+
+// WithDomain sets the domain separation tag of the nullifiers, typically the
+// name of the application. Defaults to DefaultDomain.
+func WithDomain(domain string) Option {
+	return func(c *Config) error {
+		if domain == "" {
+			return errors.New("empty domain")
+		}
+		c.Domain = domain
+		return nil
+	}
+}
+
+// WithVersion sets the version of the nullifiers in their domain, to be
+// incremented when the nullifiers of an application must not collide with the
+// ones of its previous versions. Defaults to 0.
+func WithVersion(version uint64) Option {
+	return func(c *Config) error {
+		c.Version = version
+		return nil
+	}
+}
+
+// DomainElement returns the field element encoding the domain of the
+// configuration: the first 31 bytes of SHA-256(domain ‖ version), with the
+// version as a big-endian uint64, which are smaller than the modulus of the
+// fields of all the supported curves.
+func (c *Config) DomainElement() *big.Int {
+	var version [8]byte
+	binary.BigEndian.PutUint64(version[:], c.Version)
+	d := sha256.Sum256(append([]byte(c.Domain), version[:]...))
+	return new(big.Int).SetBytes(d[:31])
+}
+
+func newConfig(opts []Option) (*Config, error) {
+	c := &Config{Domain: DefaultDomain}
+	for _, o := range opts {
+		if err := o(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Compute returns the nullifier of secretKey for externalNullifier at epoch,
+// hashed with h.
+func Compute(h hash.Hash, secretKey, externalNullifier, epoch frontend.Variable, opts ...Option) (frontend.Variable, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	h.Reset()
+	h.Write(c.DomainElement(), secretKey, externalNullifier, epoch)
+	return h.Sum(), nil
+}
+
+// ComputeNative returns the nullifier computed by Compute, with h the native
+// counterpart of the hash function of the circuit. The inputs are written to h
+// as big-endian blocks of h.BlockSize() bytes, and must be smaller than the
+// modulus of the hash function.
+func ComputeNative(h stdhash.Hash, secretKey, externalNullifier, epoch *big.Int, opts ...Option) ([]byte, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	h.Reset()
+	for i, v := range []*big.Int{c.DomainElement(), secretKey, externalNullifier, epoch} {
+		if v.Sign() < 0 || v.BitLen() > 8*h.BlockSize() {
+			return nil, fmt.Errorf("input %d doesn't fit in a block", i)
+		}
+		if _, err := h.Write(v.FillBytes(make([]byte, h.BlockSize()))); err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,81 @@
+package nullifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type nullifierCircuit struct {
+	SecretKey         frontend.Variable
+	ExternalNullifier frontend.Variable `gnark:",public"`
+	Epoch             frontend.Variable `gnark:",public"`
+	Nullifier         frontend.Variable `gnark:",public"`
+	opts              []Option
+}
+
+func (c *nullifierCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	n, err := Compute(&h, c.SecretKey, c.ExternalNullifier, c.Epoch, c.opts...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(n, c.Nullifier)
+	return nil
+}
+
+func TestNullifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+	sk, election := big.NewInt(123456789), big.NewInt(42)
+
+	nullifier := func(epoch int64, opts ...Option) *big.Int {
+		n, err := ComputeNative(mimc.NewMiMC(), sk, election, big.NewInt(epoch), opts...)
+		assert.NoError(err)
+		return new(big.Int).SetBytes(n)
+	}
+
+	for _, options := range [][]Option{nil, {WithDomain("vocdoni/vote")}, {WithDomain("vocdoni/vote"), WithVersion(2)}} {
+		assert.ProverSucceeded(&nullifierCircuit{opts: options}, &nullifierCircuit{
+			SecretKey:         sk,
+			ExternalNullifier: election,
+			Epoch:             3,
+			Nullifier:         nullifier(3, options...),
+		}, opts...)
+		// the nullifier of another epoch
+		assert.ProverFailed(&nullifierCircuit{opts: options}, &nullifierCircuit{
+			SecretKey:         sk,
+			ExternalNullifier: election,
+			Epoch:             3,
+			Nullifier:         nullifier(4, options...),
+		}, opts...)
+	}
+}
+
+func TestDomainSeparation(t *testing.T) {
+	assert := require.New(t)
+	sk, election, epoch := big.NewInt(123456789), big.NewInt(42), big.NewInt(1)
+
+	seen := make(map[string]bool)
+	for _, opts := range [][]Option{nil, {WithDomain("a")}, {WithDomain("b")}, {WithDomain("a"), WithVersion(1)}} {
+		n, err := ComputeNative(mimc.NewMiMC(), sk, election, epoch, opts...)
+		assert.NoError(err)
+		assert.False(seen[string(n)])
+		seen[string(n)] = true
+	}
+
+	_, err := ComputeNative(mimc.NewMiMC(), sk, election, epoch, WithDomain(""))
+	assert.Error(err)
+	_, err = ComputeNative(mimc.NewMiMC(), big.NewInt(-1), election, epoch)
+	assert.Error(err)
+}
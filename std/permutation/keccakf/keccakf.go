@@ -6,8 +6,14 @@
 // package.
 //
 // The cost for a single application of permutation is:
-//   - 193650 constraints in Groth16
-//   - 292032 constraints in Plonk
+//   - 155225 constraints in Groth16
+//   - 292007 constraints in Plonk
+//
+// [PermuteLookup] does the theta and chi steps with lookups instead of boolean
+// operations when the builder supports commitments (see [frontend.Committer])
+// and compiles to R1CS. A permutation then costs 78425 constraints in Groth16,
+// about half as many, and checking the lookups of all the permutations of a
+// circuit at once when it is defined costs 1729 constraints.
 package keccakf
 
 import (
@@ -53,12 +59,35 @@ var piln = [24]int{
 // vector. The input array must consist of 64-bit (unsigned) integers. The
 // returned array also contains 64-bit unsigned integers.
 func Permute(api frontend.API, a [25]frontend.Variable) [25]frontend.Variable {
+	return permuteVariables(api, a, nil)
+}
+
+// PermuteLookup applies Keccak-F permutation like [Permute], doing the theta and
+// chi steps with lookups in R1CS builders supporting commitments, and is
+// [Permute] otherwise.
+//
+// The lookups are checked with the commitment of the circuit (see
+// [frontend.Committer]), which the Groth16 verifiers exported to Solidity don't
+// handle.
+func PermuteLookup(api frontend.API, a [25]frontend.Variable) [25]frontend.Variable {
+	return permuteVariables(api, a, newTable(api))
+}
+
+// permuteVariables applies Keccak-F permutation on a, with the lookups of t if
+// it is not nil.
+func permuteVariables(api frontend.API, a [25]frontend.Variable, t *table) [25]frontend.Variable {
 	var in [25]xuint64
 	uapi := newUint64API(api)
 	for i := range a {
 		in[i] = uapi.asUint64(a[i])
 	}
-	res := permute(api, in)
+	var res [25]xuint64
+	if t != nil {
+		t.commitTo(api, a[:]...)
+		res = permuteLookup(api, t, in)
+	} else {
+		res = permute(api, in)
+	}
 	var out [25]frontend.Variable
 	for i := range out {
 		out[i] = uapi.fromUint64(res[i])
@@ -0,0 +1,68 @@
+package keccakf_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/permutation/keccakf"
+	"github.com/consensys/gnark/test"
+)
+
+type keccakfCircuit struct {
+	In       [25]frontend.Variable
+	Expected [25]frontend.Variable `gnark:",public"`
+
+	lookup bool
+}
+
+func (c *keccakfCircuit) Define(api frontend.API) error {
+	permute := keccakf.Permute
+	if c.lookup {
+		permute = keccakf.PermuteLookup
+	}
+	// two permutations, to share the lookup table between them
+	res := permute(api, permute(api, c.In))
+	for i := range res {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestKeccakf(t *testing.T) {
+	assert := test.NewAssert(t)
+	var nativeIn [25]uint64
+	for i := range nativeIn {
+		nativeIn[i] = 2 * uint64(i) * 0x9e3779b97f4a7c15
+	}
+	nativeOut := keccakF1600(keccakF1600(nativeIn))
+
+	var witness keccakfCircuit
+	for i := range nativeIn {
+		witness.In[i] = nativeIn[i]
+		witness.Expected[i] = nativeOut[i]
+	}
+	wrong := witness
+	wrong.Expected[3] = nativeOut[3] ^ 1
+
+	// the R1CS builder supports commitments, so that PermuteLookup does the
+	// lookups
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16)}
+	for _, c := range []struct {
+		name   string
+		lookup bool
+	}{
+		{"Permute", false},
+		{"PermuteLookup", true},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			circuit := &keccakfCircuit{lookup: c.lookup}
+			assert.SolvingSucceeded(circuit, &witness, opts...)
+			assert.SolvingFailed(circuit, &wrong, opts...)
+			if !testing.Short() {
+				assert.ProverSucceeded(circuit, &witness, opts...)
+			}
+		}, c.name)
+	}
+}
@@ -0,0 +1,238 @@
+package keccakf
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/frontendtype"
+	"github.com/consensys/gnark/internal/kvstore"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all the hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{solver.NewHint("keccakf_theta_chi", thetaChiHint)}
+}
+
+// the theta step of a round replaces every bit of the state by the parity of the
+// sum of 11 bits. The lookup table maps the three sums the chi step combines to
+// the bit after the chi step, see thetaChi.
+const (
+	nbSum   = 12
+	nbTable = nbSum * nbSum * nbSum
+)
+
+type ctxTableKey struct{}
+
+// table is a lookup table of the theta and chi steps, shared by all the
+// permutations of a circuit. The queries are checked at once with a logarithmic
+// derivative argument [Hab22] once the circuit is defined:
+//
+//	Σᵢ 1/(X - (idxᵢ + nbTable·outᵢ)) = Σⱼ mⱼ/(X - (j + nbTable·f(j)))
+//
+// where mⱼ is the number of queries of index j, f the function of the table and
+// X a challenge derived from a commitment to the outputs and the multiplicities.
+//
+// [Hab22]: https://eprint.iacr.org/2022/1530
+type table struct {
+	idx, out  []frontend.Variable
+	committed []frontend.Variable
+	closed    bool
+	// number of deferred callbacks when the commitment was last deferred
+	nbDeferred int
+}
+
+// newTable returns the lookup table of the circuit, or nil if the lookups aren't
+// cheaper than the boolean operations for the frontend. The lookups need a
+// commitment (see [frontend.Committer]), and only pay off in R1CS, where the
+// sums of the theta step are free.
+func newTable(api frontend.API) *table {
+	if _, ok := api.(frontend.Committer); !ok {
+		return nil
+	}
+	if ft, ok := api.(frontendtype.FrontendTyper); !ok || ft.FrontendType() != frontendtype.R1CS {
+		return nil
+	}
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		panic("builder should implement key-value store")
+	}
+	if t := kv.GetKeyValue(ctxTableKey{}); t != nil {
+		if t, ok := t.(*table); ok {
+			return t
+		}
+		panic("stored keccakf table is not valid")
+	}
+	t := &table{}
+	kv.SetKeyValue(ctxTableKey{}, t)
+	api.Compiler().Defer(t.commit)
+	t.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+	return t
+}
+
+// commitTo adds the variables which are not constant to the commitment, so that
+// the challenge of the lookup argument depends on them.
+func (t *table) commitTo(api frontend.API, vs ...frontend.Variable) {
+	for _, v := range vs {
+		if _, isConstant := api.Compiler().ConstantValue(v); !isConstant {
+			t.committed = append(t.committed, v)
+		}
+	}
+}
+
+// thetaChi returns the bits a ⊕ (¬b ∧ c) where a, b and c are the parities of
+// the sums sa, sb and sc of the theta step, which must be in [0, nbSum).
+func (t *table) thetaChi(api frontend.API, sa, sb, sc xuint64) xuint64 {
+	if t.closed {
+		panic("keccakf table already committed")
+	}
+	var idx [64]frontend.Variable
+	for i := range idx {
+		idx[i] = api.Add(sa[i], api.Mul(sb[i], nbSum), api.Mul(sc[i], nbSum*nbSum))
+	}
+	out, err := api.Compiler().NewHint(solver.NewHint("keccakf_theta_chi", thetaChiHint), len(idx), idx[:]...)
+	if err != nil {
+		panic(fmt.Sprintf("keccakf theta chi: %v", err))
+	}
+	var res xuint64
+	for i := range res {
+		// the encoding of the queries is only injective for boolean outputs
+		api.AssertIsBoolean(out[i])
+		res[i] = out[i]
+	}
+	t.idx = append(t.idx, idx[:]...)
+	t.out = append(t.out, out...)
+	return res
+}
+
+// commit checks the queries. The callbacks deferred after it may still permute,
+// so commit postpones itself while they are pending.
+func (t *table) commit(api frontend.API) error {
+	if t.closed {
+		return nil
+	}
+	if circuitdefer.NbPendingAfter[func(frontend.API) error](api.Compiler(), t.nbDeferred, true) > 0 {
+		circuitdefer.Postpone(api.Compiler(), t.commit)
+		t.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+		return nil
+	}
+	t.closed = true
+	if len(t.idx) == 0 {
+		return nil
+	}
+	counts, err := api.Compiler().NewHint(solver.NewHint("count", rangecheck.CountHint), nbTable, t.idx...)
+	if err != nil {
+		return fmt.Errorf("keccakf count: %w", err)
+	}
+	committed := append(append(t.committed, t.out...), counts...)
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		lhs := make([]frontend.Variable, len(t.idx))
+		for i := range t.idx {
+			query := api.Add(t.idx[i], api.Mul(t.out[i], nbTable))
+			lhs[i] = api.DivUnchecked(1, api.Sub(commitment, query))
+		}
+		rhs := make([]frontend.Variable, nbTable)
+		for j := range rhs {
+			entry := j + nbTable*int(thetaChiBit(uint64(j)))
+			rhs[j] = api.DivUnchecked(counts[j], api.Sub(commitment, entry))
+		}
+		api.AssertIsEqual(sum(api, lhs), sum(api, rhs))
+		return nil
+	}, committed...)
+	return nil
+}
+
+// sum returns the sum of vs, adding them at once as the sum of many linear
+// expressions is quadratic when done one at a time.
+func sum(api frontend.API, vs []frontend.Variable) frontend.Variable {
+	switch len(vs) {
+	case 0:
+		return 0
+	case 1:
+		return vs[0]
+	}
+	return api.Add(vs[0], vs[1], vs[2:]...)
+}
+
+// thetaChiBit returns the entry of the table at index idx.
+func thetaChiBit(idx uint64) uint64 {
+	a := idx % nbSum & 1
+	b := idx / nbSum % nbSum & 1
+	c := idx / (nbSum * nbSum) & 1
+	return a ^ (^b & c & 1)
+}
+
+// thetaChiHint returns the entries of the table at the indices given as inputs.
+func thetaChiHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != len(outputs) {
+		return fmt.Errorf("expected %d outputs, got %d", len(inputs), len(outputs))
+	}
+	for i := range inputs {
+		if !inputs[i].IsUint64() || inputs[i].Uint64() >= nbTable {
+			return fmt.Errorf("index %s out of the table", inputs[i])
+		}
+		outputs[i].SetUint64(thetaChiBit(inputs[i].Uint64()))
+	}
+	return nil
+}
+
+// permuteLookup is the same as permute, with the theta and chi steps done with
+// lookups in t.
+func permuteLookup(api frontend.API, t *table, st [25]xuint64) [25]xuint64 {
+	uapi := newUint64API(api)
+	var c [5]xuint64
+	var d xuint64
+	for r := 0; r < 24; r++ {
+		// theta, without normalization: the bits are replaced by the sums of
+		// the bits they are xored with, the parities of which are the bits
+		// after theta
+		for i := 0; i < 5; i++ {
+			for z := 0; z < 64; z++ {
+				c[i][z] = api.Add(st[i][z], st[i+5][z], st[i+10][z], st[i+15][z], st[i+20][z])
+			}
+		}
+		for i := 0; i < 5; i++ {
+			rot := uapi.lrot(c[(i+1)%5], 1)
+			for z := 0; z < 64; z++ {
+				d[z] = api.Add(c[(i+4)%5][z], rot[z])
+			}
+			for j := 0; j < 25; j += 5 {
+				for z := 0; z < 64; z++ {
+					st[j+i][z] = api.Add(st[j+i][z], d[z])
+				}
+			}
+		}
+		// rho pi
+		t0 := st[1]
+		for i := 0; i < 24; i++ {
+			j := piln[i]
+			tmp := st[j]
+			st[j] = uapi.lrot(t0, rotc[i])
+			t0 = tmp
+		}
+
+		// chi, normalizing the sums of theta
+		for j := 0; j < 25; j += 5 {
+			var bc [5]xuint64
+			copy(bc[:], st[j:j+5])
+			for i := 0; i < 5; i++ {
+				st[j+i] = t.thetaChi(api, bc[i], bc[(i+1)%5], bc[(i+2)%5])
+			}
+		}
+		// iota, on boolean bits the xor with a constant is linear
+		for z := 0; z < 64; z++ {
+			if rc[r][z] == uint64(1) {
+				st[0][z] = api.Sub(1, st[0][z])
+			}
+		}
+	}
+	return st
+}
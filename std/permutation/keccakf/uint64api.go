@@ -37,14 +37,14 @@ func constUint64(a uint64) xuint64 {
 }
 
 func (w *uint64api) asUint64(in frontend.Variable) xuint64 {
-	bits := bits.ToBinary(w.api, in, bits.WithNbDigits(64))
+	bits := bits.ToBinary(w.api, in, bits.WithWidth(64))
 	var res xuint64
 	copy(res[:], bits)
 	return res
 }
 
 func (w *uint64api) fromUint64(in xuint64) frontend.Variable {
-	return bits.FromBinary(w.api, in[:], bits.WithUnconstrainedInputs())
+	return bits.FromBinary(w.api, in[:], bits.WithUnchecked())
 }
 
 func (w *uint64api) and(in ...xuint64) xuint64 {
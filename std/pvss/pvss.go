@@ -0,0 +1,84 @@
+// Package pvss provides a ZKP-circuit function to verify a publicly verifiable
+// secret sharing: the encryption of Shamir shares of a secret to a committee of
+// trustees.
+//
+// The dealer shares the secret s with the polynomial f(X) = s + a₁X + … + aₜ₋₁Xᵗ⁻¹
+// of threshold t, and encrypts the share f(i) of the i-th trustee (from 1) to its
+// public key PKᵢ on a twisted Edwards curve defined over the native field:
+//
+//	Ephemeralᵢ = rᵢ·G
+//	Maskedᵢ    = f(i) + H(rᵢ·PKᵢ)
+//
+// where H hashes the coordinates of the point. The circuit proves that the
+// ciphertexts are correct; the statement usually also binds the secret, for
+// instance to the public key s·G it is the private key of.
+//
+// The trustee of private key skᵢ decrypts its share as Maskedᵢ - H(skᵢ·Ephemeralᵢ).
+// Any t shares recover s = f(0) by Lagrange interpolation in the native field.
+package pvss
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// EncryptedShare is the share of a trustee, encrypted to its public key (to be
+// used in gnark circuit).
+type EncryptedShare struct {
+	// Ephemeral is the ephemeral public key r·G of the encryption.
+	Ephemeral twistededwards.Point
+	// Masked is the share masked with H(r·PK).
+	Masked frontend.Variable
+}
+
+// Dealing stores the secret randomness of the dealer (to be used in gnark
+// circuit).
+type Dealing struct {
+	// Coefficients are the coefficients a₁, …, aₜ₋₁ of the polynomial of the
+	// shares; the threshold t is len(Coefficients)+1.
+	Coefficients []frontend.Variable
+	// Nonces are the nonces r of the encryptions of the shares, one per trustee.
+	Nonces []frontend.Variable
+}
+
+// Verify asserts that shares are the encryptions to the trustees of the shares
+// of secret with the polynomial of dealing, computed with the hash function h.
+// The i-th share belongs to trustees[i], and is the evaluation at i+1.
+func Verify(curve twistededwards.Curve, h hash.Hash, secret frontend.Variable, dealing Dealing, trustees []twistededwards.Point, shares []EncryptedShare) error {
+	if len(shares) != len(trustees) || len(dealing.Nonces) != len(trustees) {
+		return fmt.Errorf("%d trustees, %d shares and %d nonces", len(trustees), len(shares), len(dealing.Nonces))
+	}
+	if len(dealing.Coefficients)+1 > len(trustees) {
+		return fmt.Errorf("threshold %d larger than the number of trustees %d", len(dealing.Coefficients)+1, len(trustees))
+	}
+	api := curve.API()
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	for i := range trustees {
+		curve.AssertIsOnCurve(trustees[i])
+
+		// f(i+1) with Horner's method: the evaluation point is a constant
+		x := i + 1
+		var share frontend.Variable = 0
+		for j := len(dealing.Coefficients) - 1; j >= 0; j-- {
+			share = api.Mul(api.Add(share, dealing.Coefficients[j]), x)
+		}
+		share = api.Add(share, secret)
+
+		ephemeral := curve.ScalarMul(base, dealing.Nonces[i])
+		api.AssertIsEqual(shares[i].Ephemeral.X, ephemeral.X)
+		api.AssertIsEqual(shares[i].Ephemeral.Y, ephemeral.Y)
+
+		key := curve.ScalarMul(trustees[i], dealing.Nonces[i])
+		h.Reset()
+		h.Write(key.X, key.Y)
+		api.AssertIsEqual(shares[i].Masked, api.Add(share, h.Sum()))
+	}
+	return nil
+}
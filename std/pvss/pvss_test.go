@@ -0,0 +1,124 @@
+package pvss
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	bjj "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	nbTrustees = 3
+	threshold  = 2
+)
+
+type pvssCircuit struct {
+	Secret    frontend.Variable
+	PublicKey twistededwards.Point `gnark:",public"`
+	Dealing   Dealing
+	Trustees  [nbTrustees]twistededwards.Point `gnark:",public"`
+	Shares    [nbTrustees]EncryptedShare       `gnark:",public"`
+}
+
+func (c *pvssCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	// the secret is the private key of the public key
+	pk := curve.ScalarMul(twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}, c.Secret)
+	api.AssertIsEqual(pk.X, c.PublicKey.X)
+	api.AssertIsEqual(pk.Y, c.PublicKey.Y)
+	return Verify(curve, &h, c.Secret, c.Dealing, c.Trustees[:], c.Shares[:])
+}
+
+func newCircuit() *pvssCircuit {
+	return &pvssCircuit{Dealing: Dealing{
+		Coefficients: make([]frontend.Variable, threshold-1),
+		Nonces:       make([]frontend.Variable, nbTrustees),
+	}}
+}
+
+// mask returns H(p) with the native MiMC.
+func mask(p *bjj.PointAffine) fr.Element {
+	h := mimc.NewMiMC()
+	bx, by := p.X.Bytes(), p.Y.Bytes()
+	h.Write(bx[:])
+	h.Write(by[:])
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bjj.GetEdwardsCurve()
+
+	// dealing of the secret 42 with f(X) = 42 + 7X
+	secret, coefficient := big.NewInt(42), big.NewInt(7)
+	var pk bjj.PointAffine
+	pk.ScalarMultiplication(&params.Base, secret)
+
+	witness := newCircuit()
+	witness.Secret = secret
+	witness.PublicKey = twistededwards.Point{X: pk.X, Y: pk.Y}
+	witness.Dealing.Coefficients[0] = coefficient
+	sks := make([]*big.Int, nbTrustees)
+	for i := 0; i < nbTrustees; i++ {
+		sks[i] = big.NewInt(int64(1000 + i))
+		var trustee bjj.PointAffine
+		trustee.ScalarMultiplication(&params.Base, sks[i])
+		witness.Trustees[i] = twistededwards.Point{X: trustee.X, Y: trustee.Y}
+
+		nonce := big.NewInt(int64(77 + i))
+		var ephemeral, key bjj.PointAffine
+		ephemeral.ScalarMultiplication(&params.Base, nonce)
+		key.ScalarMultiplication(&trustee, nonce)
+		var share, m fr.Element
+		share.SetInt64(42 + 7*int64(i+1))
+		m = mask(&key)
+		share.Add(&share, &m)
+
+		witness.Dealing.Nonces[i] = nonce
+		witness.Shares[i] = EncryptedShare{Ephemeral: twistededwards.Point{X: ephemeral.X, Y: ephemeral.Y}, Masked: share}
+	}
+
+	// the trustees decrypt their shares, and two of them recover the secret
+	decrypted := make([]fr.Element, nbTrustees)
+	for i := range decrypted {
+		var key bjj.PointAffine
+		e := witness.Shares[i].Ephemeral
+		ephemeral := bjj.NewPointAffine(e.X.(fr.Element), e.Y.(fr.Element))
+		key.ScalarMultiplication(&ephemeral, sks[i])
+		m := mask(&key)
+		masked := witness.Shares[i].Masked.(fr.Element)
+		decrypted[i].Sub(&masked, &m)
+	}
+	// f(0) = 2·f(1) - f(2)
+	var recovered fr.Element
+	recovered.Double(&decrypted[0]).Sub(&recovered, &decrypted[1])
+	require.Equal(t, uint64(42), recovered.Uint64())
+
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+	assert.ProverSucceeded(newCircuit(), witness, opts...)
+
+	// a share of another polynomial
+	wrong := witness.Shares[2].Masked.(fr.Element)
+	wrong.Add(&wrong, new(fr.Element).SetOne())
+	witness.Shares[2].Masked = wrong
+	assert.ProverFailed(newCircuit(), witness, opts...)
+}
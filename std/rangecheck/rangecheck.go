@@ -9,6 +9,9 @@
 package rangecheck
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 )
@@ -28,3 +31,44 @@ func New(api frontend.API) frontend.Rangechecker {
 	}
 	return plainChecker{api: api}
 }
+
+// AssertIsLessOrEqual asserts that a ≤ bound, where a and bound are in
+// [0, 2ⁿᵇᴮⁱᵗˢ). a and bound are range checked, so that the assertion fails for
+// operands out of range; it panics for constants out of range.
+//
+// The comparison costs up to three range checks of nbBits bits, of a, of bound
+// and of bound - a, which are batched with the other range checks of the circuit (see [New]),
+// instead of a binary decomposition of both operands with
+// [frontend.API.AssertIsLessOrEqual]. It panics if 2ⁿᵇᴮⁱᵗˢ⁺¹ is not smaller
+// than the modulus, as bound - a would then wrap around.
+func AssertIsLessOrEqual(api frontend.API, a, bound frontend.Variable, nbBits int) {
+	if nbBits < 1 || nbBits+1 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+	ca, aConstant := api.Compiler().ConstantValue(a)
+	cb, boundConstant := api.Compiler().ConstantValue(bound)
+	if aConstant && ca.Cmp(limit) >= 0 {
+		panic(fmt.Sprintf("constant %s doesn't fit in %d bits", ca, nbBits))
+	}
+	if boundConstant && cb.Cmp(limit) >= 0 {
+		panic(fmt.Sprintf("constant bound %s doesn't fit in %d bits", cb, nbBits))
+	}
+	if aConstant && boundConstant {
+		if ca.Cmp(cb) > 0 {
+			panic(fmt.Sprintf("constant %s is larger than constant bound %s", ca, cb))
+		}
+		return
+	}
+
+	rc := New(api)
+	if !aConstant {
+		rc.Check(a, nbBits)
+	}
+	if !boundConstant {
+		rc.Check(bound, nbBits)
+	}
+	// bound - a is in [0, 2ⁿᵇᴮⁱᵗˢ) iff a ≤ bound: otherwise it wraps around to
+	// at least p - 2ⁿᵇᴮⁱᵗˢ > 2ⁿᵇᴮⁱᵗˢ
+	rc.Check(api.Sub(bound, a), nbBits)
+}
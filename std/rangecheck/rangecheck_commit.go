@@ -8,9 +8,11 @@ import (
 
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/internal/frontendtype"
 	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/multicommit"
 )
 
 type ctxCheckerKey struct{}
@@ -27,6 +29,8 @@ type checkedVariable struct {
 type commitChecker struct {
 	collected []checkedVariable
 	closed    bool
+	// number of deferred callbacks when the commitment was last deferred
+	nbDeferred int
 }
 
 func newCommitRangechecker(api frontend.API) *commitChecker {
@@ -44,10 +48,19 @@ func newCommitRangechecker(api frontend.API) *commitChecker {
 	}
 	cht := &commitChecker{}
 	kv.SetKeyValue(ctxCheckerKey{}, cht)
-	api.Compiler().Defer(cht.commit)
+	cht.deferCommit(api)
 	return cht
 }
 
+// deferCommit defers the range checks of the collected variables. The callbacks
+// deferred after it may still range check variables, so commit postpones itself
+// while they are pending. The commitment itself is shared through multicommit,
+// which postpones it until all the range checks are done.
+func (c *commitChecker) deferCommit(api frontend.API) {
+	api.Compiler().Defer(c.commit)
+	c.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+}
+
 func (c *commitChecker) Check(in frontend.Variable, bits int) {
 	if c.closed {
 		panic("checker already closed")
@@ -59,14 +72,15 @@ func (c *commitChecker) commit(api frontend.API) error {
 	if c.closed {
 		return nil
 	}
+	if circuitdefer.NbPendingAfter[func(frontend.API) error](api.Compiler(), c.nbDeferred, true) > 0 {
+		circuitdefer.Postpone(api.Compiler(), c.commit)
+		c.nbDeferred = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler()))
+		return nil
+	}
 	defer func() { c.closed = true }()
 	if len(c.collected) == 0 {
 		return nil
 	}
-	committer, ok := api.(frontend.Committer)
-	if !ok {
-		panic("expected committer API")
-	}
 	baseLength := c.getOptimalBasewidth(api)
 	// decompose into smaller limbs
 	decomposed := make([]frontend.Variable, 0, len(c.collected))
@@ -83,6 +97,13 @@ func (c *commitChecker) commit(api frontend.API) error {
 		}
 		// store all limbs for counting
 		decomposed = append(decomposed, limbs...)
+		// the most significant limb holds fewer bits than the base when the
+		// width isn't a multiple of the base length: it is in range iff the limb
+		// shifted to the top of the base is
+		if rem := c.collected[i].bits % baseLength; rem != 0 {
+			shift := new(big.Int).Lsh(big.NewInt(1), uint(baseLength-rem))
+			decomposed = append(decomposed, api.Mul(limbs[len(limbs)-1], shift))
+		}
 		// check that limbs are correct. We check the sizes of the limbs later
 		var composed frontend.Variable = 0
 		for j := range limbs {
@@ -96,31 +117,30 @@ func (c *commitChecker) commit(api frontend.API) error {
 	if err != nil {
 		panic(fmt.Sprintf("count %v", err))
 	}
-	// compute the poly \pi (X - s_i)^{e_i}
-	commitment, err := committer.Commit(collected...)
-	if err != nil {
-		panic(fmt.Sprintf("commit %v", err))
-	}
-	logn := stdbits.Len(uint(len(decomposed)))
-	var lp frontend.Variable = 1
-	for i := 0; i < nbTable; i++ {
-		expbits := bits.ToBinary(api, exps[i], bits.WithNbDigits(logn))
-		var acc frontend.Variable = 1
-		tmp := api.Sub(commitment, i)
-		for j := 0; j < logn; j++ {
-			curr := api.Select(expbits[j], tmp, 1)
-			acc = api.Mul(acc, curr)
-			tmp = api.Mul(tmp, tmp)
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		// compute the poly \pi (X - s_i)^{e_i}
+		logn := stdbits.Len(uint(len(decomposed)))
+		var lp frontend.Variable = 1
+		for i := 0; i < nbTable; i++ {
+			expbits := bits.ToBinary(api, exps[i], bits.WithWidth(logn))
+			var acc frontend.Variable = 1
+			tmp := api.Sub(commitment, i)
+			for j := 0; j < logn; j++ {
+				curr := api.Select(expbits[j], tmp, 1)
+				acc = api.Mul(acc, curr)
+				tmp = api.Mul(tmp, tmp)
+			}
+			lp = api.Mul(lp, acc)
 		}
-		lp = api.Mul(lp, acc)
-	}
-	// compute the poly \pi (X - f_i)
-	var rp frontend.Variable = 1
-	for i := range decomposed {
-		val := api.Sub(commitment, decomposed[i])
-		rp = api.Mul(rp, val)
-	}
-	api.AssertIsEqual(lp, rp)
+		// compute the poly \pi (X - f_i)
+		var rp frontend.Variable = 1
+		for i := range decomposed {
+			val := api.Sub(commitment, decomposed[i])
+			rp = api.Mul(rp, val)
+		}
+		api.AssertIsEqual(lp, rp)
+		return nil
+	}, collected...)
 	return nil
 }
 
@@ -10,5 +10,5 @@ type plainChecker struct {
 }
 
 func (pl plainChecker) Check(v frontend.Variable, nbBits int) {
-	bits.ToBinary(pl.api, v, bits.WithNbDigits(nbBits))
+	bits.ToBinary(pl.api, v, bits.WithWidth(nbBits))
 }
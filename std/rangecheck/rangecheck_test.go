@@ -0,0 +1,98 @@
+package rangecheck_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+)
+
+type lessOrEqualCircuit struct {
+	A, Bound [3]frontend.Variable
+}
+
+func (c *lessOrEqualCircuit) Define(api frontend.API) error {
+	for i := range c.A {
+		rangecheck.AssertIsLessOrEqual(api, c.A[i], c.Bound[i], 16)
+	}
+	// constant operands
+	rangecheck.AssertIsLessOrEqual(api, c.A[0], 1<<16-1, 16)
+	rangecheck.AssertIsLessOrEqual(api, 0, c.Bound[0], 16)
+	rangecheck.AssertIsLessOrEqual(api, 3, 5, 16)
+	return nil
+}
+
+func TestAssertIsLessOrEqual(t *testing.T) {
+	assert := test.NewAssert(t)
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+	assert.ProverSucceeded(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 5, 1<<16 - 1},
+		Bound: [3]frontend.Variable{0, 5, 1<<16 - 1},
+	}, opts...)
+	assert.ProverSucceeded(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 4, 1},
+		Bound: [3]frontend.Variable{1, 5, 1<<16 - 1},
+	}, opts...)
+	// a > bound
+	assert.ProverFailed(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{1, 5, 1},
+		Bound: [3]frontend.Variable{0, 5, 1},
+	}, opts...)
+	assert.ProverFailed(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 6, 1},
+		Bound: [3]frontend.Variable{0, 5, 1},
+	}, opts...)
+	// a out of range, with bound - a in range
+	assert.ProverFailed(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 0, "21888242871839275222246405745257275088548364400416034343698204186575808495616"},
+		Bound: [3]frontend.Variable{0, 0, 1},
+	}, opts...)
+	// bound out of range
+	assert.ProverFailed(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 0, 1},
+		Bound: [3]frontend.Variable{0, 0, 1 << 17},
+	}, opts...)
+	// bound out of range, with bound - a in range
+	assert.ProverFailed(&lessOrEqualCircuit{}, &lessOrEqualCircuit{
+		A:     [3]frontend.Variable{0, 0, 1<<16 - 1},
+		Bound: [3]frontend.Variable{0, 0, 1<<16 + 5},
+	}, opts...)
+}
+
+var checkWidths = [...]int{1, 7, 8, 13, 64}
+
+type checkCircuit struct {
+	V [len(checkWidths)]frontend.Variable
+}
+
+func (c *checkCircuit) Define(api frontend.API) error {
+	rc := rangecheck.New(api)
+	for i := range c.V {
+		rc.Check(c.V[i], checkWidths[i])
+	}
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+	var largest checkCircuit
+	for i, w := range checkWidths {
+		largest.V[i] = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(w)), big.NewInt(1))
+	}
+	assert.ProverSucceeded(&checkCircuit{}, &largest, opts...)
+
+	// the widths which aren't multiples of the base of the decomposition are
+	// checked too
+	for i, w := range checkWidths {
+		outOfRange := largest
+		outOfRange.V[i] = new(big.Int).Lsh(big.NewInt(1), uint(w))
+		assert.ProverFailed(&checkCircuit{}, &outOfRange, opts...)
+	}
+}
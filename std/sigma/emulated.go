@@ -0,0 +1,136 @@
+package sigma
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// EmulatedSchnorrProof is a SchnorrProof over a short Weierstrass curve defined
+// over the emulated field Base, of order the modulus of Scalar.
+type EmulatedSchnorrProof[Base, Scalar emulated.FieldParams] struct {
+	Commitment sw_emulated.AffinePoint[Base]
+	Response   emulated.Element[Scalar]
+}
+
+// EmulatedDLEQProof is a DLEQProof over a short Weierstrass curve defined over
+// the emulated field Base, of order the modulus of Scalar.
+type EmulatedDLEQProof[Base, Scalar emulated.FieldParams] struct {
+	Commitments [2]sw_emulated.AffinePoint[Base]
+	Response    emulated.Element[Scalar]
+}
+
+// VerifySchnorrEmulated is VerifySchnorr over the short Weierstrass curve of
+// parameters params. The challenge is computed with the native hash h over the
+// bytes of the coordinates of the points (see EmulatedChallenge).
+//
+// The points are asserted to be on the curve. On curves with a cofactor, the
+// verification equation is multiplied by the cofactor, so that small order
+// components of pk and of the commitment are ignored: the proof is then a proof
+// of knowledge of the discrete logarithm of [cofactor]pk in [cofactor]G.
+func VerifySchnorrEmulated[Base, Scalar emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, h hash.Hash, pk *sw_emulated.AffinePoint[Base], proof *EmulatedSchnorrProof[Base, Scalar], msg ...frontend.Variable) error {
+	curve, err := sw_emulated.New[Base, Scalar](api, params)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	curve.AssertIsOnCurve(pk)
+	curve.AssertIsOnCurve(&proof.Commitment)
+	c, err := EmulatedChallenge[Base, Scalar](api, h, []*sw_emulated.AffinePoint[Base]{curve.Generator(), pk, &proof.Commitment}, msg...)
+	if err != nil {
+		return err
+	}
+	if !hasCofactor(params) {
+		curve.AssertIsEqual(curve.ScalarMulBase(&proof.Response), curve.Add(&proof.Commitment, curve.ScalarMul(pk, c)))
+		return nil
+	}
+	// the base point is in the subgroup of prime order, so [cofactor·s]G is
+	// [cofactor]([s]G).
+	scalarApi, err := emulated.NewField[Scalar](api)
+	if err != nil {
+		return fmt.Errorf("new scalar api: %w", err)
+	}
+	cofactor := emulated.ValueOf[Scalar](params.Cofactor)
+	lhs := curve.ScalarMulBase(scalarApi.MulMod(&proof.Response, &cofactor))
+	rhs := curve.Add(curve.ScalarMul(&proof.Commitment, &cofactor), curve.ScalarMul(curve.ScalarMul(pk, &cofactor), c))
+	curve.AssertIsEqual(lhs, rhs)
+	return nil
+}
+
+// VerifyDLEQEmulated is VerifyDLEQ over the short Weierstrass curve of
+// parameters params. The challenge is computed with the native hash hsh over
+// the bytes of the coordinates of the points (see EmulatedChallenge).
+//
+// The points are asserted to be on the curve. On curves with a cofactor, all the
+// points are multiplied by the cofactor before the verification: the proof is
+// then a proof that [cofactor]x and [cofactor]y have the same discrete logarithm
+// in [cofactor]g and [cofactor]h.
+func VerifyDLEQEmulated[Base, Scalar emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, hsh hash.Hash, g, h, x, y *sw_emulated.AffinePoint[Base], proof *EmulatedDLEQProof[Base, Scalar], msg ...frontend.Variable) error {
+	curve, err := sw_emulated.New[Base, Scalar](api, params)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	points := []*sw_emulated.AffinePoint[Base]{g, h, x, y, &proof.Commitments[0], &proof.Commitments[1]}
+	for _, p := range points {
+		curve.AssertIsOnCurve(p)
+	}
+	c, err := EmulatedChallenge[Base, Scalar](api, hsh, points, msg...)
+	if err != nil {
+		return err
+	}
+	if hasCofactor(params) {
+		cofactor := emulated.ValueOf[Scalar](params.Cofactor)
+		for i := range points {
+			points[i] = curve.ScalarMul(points[i], &cofactor)
+		}
+	}
+	g, h, x, y, r1, r2 := points[0], points[1], points[2], points[3], points[4], points[5]
+	curve.AssertIsEqual(curve.ScalarMul(g, &proof.Response), curve.Add(r1, curve.ScalarMul(x, c)))
+	curve.AssertIsEqual(curve.ScalarMul(h, &proof.Response), curve.Add(r2, curve.ScalarMul(y, c)))
+	return nil
+}
+
+// hasCofactor returns true if the curve of parameters params has a cofactor.
+func hasCofactor(params sw_emulated.CurveParams) bool {
+	return params.Cofactor != nil && params.Cofactor.Cmp(big.NewInt(1)) != 0
+}
+
+// EmulatedChallenge returns the challenge of a proof over an emulated curve: the
+// hash with h of the bytes of the X and Y coordinates of the points, in order,
+// followed by msg, truncated to its n-1 least significant bits where n is the
+// bit length of the order of the curve.
+//
+// The bytes are the ones of the canonical representative of the coordinates in
+// [0, modulus), as many as needed for the modulus of Base, from the least
+// significant one (see [emulated.Field.ToBytes]).
+func EmulatedChallenge[Base, Scalar emulated.FieldParams](api frontend.API, h hash.Hash, points []*sw_emulated.AffinePoint[Base], msg ...frontend.Variable) (*emulated.Element[Scalar], error) {
+	baseApi, err := emulated.NewField[Base](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	scalarApi, err := emulated.NewField[Scalar](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar api: %w", err)
+	}
+	h.Reset()
+	for _, p := range points {
+		h.Write(baseApi.ToBytes(&p.X)...)
+		h.Write(baseApi.ToBytes(&p.Y)...)
+	}
+	h.Write(msg...)
+
+	var fr Scalar
+	nbBits := fr.Modulus().BitLen() - 1
+	if nbBits > api.Compiler().FieldBitLen() {
+		nbBits = api.Compiler().FieldBitLen()
+	}
+	// the bits of the hash are the ones of its canonical value, so that the
+	// truncation is unique
+	cBits := bits.ToBinary(api, h.Sum())
+	bits.AssertIsCanonical(api, cBits)
+	return scalarApi.FromBits(cBits[:nbBits]...), nil
+}
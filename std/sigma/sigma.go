@@ -0,0 +1,109 @@
+// Package sigma provides ZKP-circuit functions to verify non-interactive sigma
+// protocol proofs produced outside of the circuit: Schnorr proofs of knowledge
+// of a discrete logarithm, and Chaum-Pedersen proofs of equality of discrete
+// logarithms (DLEQ), for instance of a correct decryption.
+//
+// The proofs are made non-interactive with the Fiat-Shamir heuristic. For the
+// statement X = x·G, the prover picks k, and computes
+//
+//	R = k·G
+//	c = H(G, X, R, msg)
+//	s = k + c·x mod the order of G
+//
+// where msg binds the proof to its context. For the statement X = x·G, Y = x·H,
+// the commitments are R₁ = k·G and R₂ = k·H, and c = H(G, H, X, Y, R₁, R₂, msg).
+// The verifier recomputes c and checks s·G = R + c·X (resp. s·G = R₁ + c·X and
+// s·H = R₂ + c·Y).
+//
+// The functions of this file verify proofs over twisted Edwards curves defined
+// over the native field, the challenge being the hash of the coordinates of the
+// points, in the order above, and of msg. The points of the statement must be
+// in the subgroup of prime order: otherwise a prover could add a small order
+// component to them and absorb it in the commitments. The commitments are then
+// in the subgroup too, as the verification equations fix them. See the Emulated
+// functions for short Weierstrass curves over emulated fields.
+package sigma
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// SchnorrProof is a proof of knowledge of the discrete logarithm of a point (to
+// be used in gnark circuit).
+type SchnorrProof struct {
+	// Commitment is the commitment R = k·G of the prover.
+	Commitment twistededwards.Point
+	// Response is s = k + c·x.
+	Response frontend.Variable
+}
+
+// DLEQProof is a proof that two points have the same discrete logarithm in
+// their respective bases (to be used in gnark circuit).
+type DLEQProof struct {
+	// Commitments are the commitments R₁ = k·G and R₂ = k·H of the prover.
+	Commitments [2]twistededwards.Point
+	// Response is s = k + c·x.
+	Response frontend.Variable
+}
+
+// VerifySchnorr asserts that proof is a valid proof of knowledge of the discrete
+// logarithm of pk in the base point of the curve, for the message msg, with the
+// challenge computed with h. It asserts that pk is in the subgroup of prime
+// order.
+func VerifySchnorr(curve twistededwards.Curve, h hash.Hash, pk twistededwards.Point, proof SchnorrProof, msg ...frontend.Variable) {
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+	curve.AssertIsOnCurve(pk)
+	curve.AssertIsOnCurve(proof.Commitment)
+	assertIsInSubgroup(curve, pk)
+
+	h.Reset()
+	h.Write(base.X, base.Y, pk.X, pk.Y, proof.Commitment.X, proof.Commitment.Y)
+	h.Write(msg...)
+	c := h.Sum()
+
+	assertResponse(curve, base, pk, proof.Commitment, proof.Response, c)
+}
+
+// VerifyDLEQ asserts that proof is a valid proof that x and y have the same
+// discrete logarithm in the bases g and h, for the message msg, with the
+// challenge computed with hsh. It asserts that g, h, x and y are in the
+// subgroup of prime order.
+func VerifyDLEQ(curve twistededwards.Curve, hsh hash.Hash, g, h, x, y twistededwards.Point, proof DLEQProof, msg ...frontend.Variable) {
+	for _, p := range []twistededwards.Point{g, h, x, y, proof.Commitments[0], proof.Commitments[1]} {
+		curve.AssertIsOnCurve(p)
+	}
+	for _, p := range []twistededwards.Point{g, h, x, y} {
+		assertIsInSubgroup(curve, p)
+	}
+
+	hsh.Reset()
+	hsh.Write(g.X, g.Y, h.X, h.Y, x.X, x.Y, y.X, y.Y)
+	hsh.Write(proof.Commitments[0].X, proof.Commitments[0].Y, proof.Commitments[1].X, proof.Commitments[1].Y)
+	hsh.Write(msg...)
+	c := hsh.Sum()
+
+	assertResponse(curve, g, x, proof.Commitments[0], proof.Response, c)
+	assertResponse(curve, h, y, proof.Commitments[1], proof.Response, c)
+}
+
+// assertResponse asserts that s·base - c·p = r.
+func assertResponse(curve twistededwards.Curve, base, p, r twistededwards.Point, s, c frontend.Variable) {
+	api := curve.API()
+	q := curve.DoubleBaseScalarMul(base, curve.Neg(p), s, c)
+	api.AssertIsEqual(q.X, r.X)
+	api.AssertIsEqual(q.Y, r.Y)
+}
+
+// assertIsInSubgroup asserts that [order]p is the neutral element, p being on
+// the curve.
+func assertIsInSubgroup(curve twistededwards.Curve, p twistededwards.Point) {
+	api := curve.API()
+	q := curve.ScalarMul(p, curve.Params().Order)
+	api.AssertIsEqual(q.X, 0)
+	api.AssertIsEqual(q.Y, 1)
+}
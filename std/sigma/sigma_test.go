@@ -0,0 +1,297 @@
+package sigma
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	bjj "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// challenge returns the native MiMC hash of the values, as an integer.
+func challenge(values ...*big.Int) *big.Int {
+	h := mimc.NewMiMC()
+	for _, v := range values {
+		var e fr.Element
+		e.SetBigInt(v)
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func coordinates(points ...*bjj.PointAffine) []*big.Int {
+	var res []*big.Int
+	for _, p := range points {
+		res = append(res, p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int)))
+	}
+	return res
+}
+
+func toPoint(p *bjj.PointAffine) twistededwards.Point {
+	return twistededwards.Point{X: p.X, Y: p.Y}
+}
+
+type sigmaCircuit struct {
+	PK      twistededwards.Point `gnark:",public"`
+	H, Y    twistededwards.Point `gnark:",public"`
+	Msg     frontend.Variable    `gnark:",public"`
+	Schnorr SchnorrProof
+	DLEQ    DLEQProof
+}
+
+func (c *sigmaCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifySchnorr(curve, &h, c.PK, c.Schnorr, c.Msg)
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+	VerifyDLEQ(curve, &h, base, c.H, c.PK, c.Y, c.DLEQ, c.Msg)
+	return nil
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bjj.GetEdwardsCurve()
+	base := params.Base
+	msg := big.NewInt(12345)
+
+	x, k := big.NewInt(987654321), big.NewInt(42424242)
+	var pk, h, y, r, r1, r2 bjj.PointAffine
+	pk.ScalarMultiplication(&base, x)
+	h.ScalarMultiplication(&base, big.NewInt(31337))
+	y.ScalarMultiplication(&h, x)
+
+	// Schnorr
+	r.ScalarMultiplication(&base, k)
+	c := challenge(append(coordinates(&base, &pk, &r), msg)...)
+	s := new(big.Int).Mul(c, x)
+	s.Add(s, k).Mod(s, &params.Order)
+
+	// DLEQ
+	r1.ScalarMultiplication(&base, k)
+	r2.ScalarMultiplication(&h, k)
+	cd := challenge(append(coordinates(&base, &h, &pk, &y, &r1, &r2), msg)...)
+	sd := new(big.Int).Mul(cd, x)
+	sd.Add(sd, k).Mod(sd, &params.Order)
+
+	witness := &sigmaCircuit{
+		PK:      toPoint(&pk),
+		H:       toPoint(&h),
+		Y:       toPoint(&y),
+		Msg:     msg,
+		Schnorr: SchnorrProof{Commitment: toPoint(&r), Response: s},
+		DLEQ:    DLEQProof{Commitments: [2]twistededwards.Point{toPoint(&r1), toPoint(&r2)}, Response: sd},
+	}
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+	assert.ProverSucceeded(&sigmaCircuit{}, witness, opts...)
+
+	// proof for another message
+	witness.Msg = big.NewInt(54321)
+	assert.ProverFailed(&sigmaCircuit{}, witness, opts...)
+
+	// y with another discrete logarithm
+	var other bjj.PointAffine
+	other.ScalarMultiplication(&h, big.NewInt(2))
+	witness.Msg = msg
+	witness.Y = toPoint(&other)
+	assert.ProverFailed(&sigmaCircuit{}, witness, opts...)
+
+	// y with a small order component: T = (0, -1) is of order 2, so with an
+	// even challenge c·T vanishes and the commitments of an honest proof for
+	// x·H satisfy the verification equations for x·H + T.
+	var torsion, yt bjj.PointAffine
+	torsion.X.SetZero()
+	torsion.Y.SetOne()
+	torsion.Y.Neg(&torsion.Y)
+	yt.Add(&y, &torsion)
+	for kt := big.NewInt(1); ; kt.Add(kt, big.NewInt(1)) {
+		r1.ScalarMultiplication(&base, kt)
+		r2.ScalarMultiplication(&h, kt)
+		cd = challenge(append(coordinates(&base, &h, &pk, &yt, &r1, &r2), msg)...)
+		if cd.Bit(0) == 0 {
+			sd = new(big.Int).Mul(cd, x)
+			sd.Add(sd, kt).Mod(sd, &params.Order)
+			break
+		}
+	}
+	witness.Y = toPoint(&yt)
+	witness.DLEQ = DLEQProof{Commitments: [2]twistededwards.Point{toPoint(&r1), toPoint(&r2)}, Response: sd}
+	assert.ProverFailed(&sigmaCircuit{}, witness, opts...)
+}
+
+type emulatedSchnorrCircuit struct {
+	PK    sw_emulated.AffinePoint[emulated.Secp256k1Fp]
+	Msg   frontend.Variable
+	Proof EmulatedSchnorrProof[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+}
+
+func (c *emulatedSchnorrCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return VerifySchnorrEmulated(api, sw_emulated.GetSecp256k1Params(), &h, &c.PK, &c.Proof, c.Msg)
+}
+
+// coordinateBytes returns the nbBytes little-endian bytes of each coordinate.
+func coordinateBytes(nbBytes int, coordinates ...*big.Int) []*big.Int {
+	var res []*big.Int
+	for _, c := range coordinates {
+		for i := 0; i < nbBytes; i++ {
+			b := new(big.Int).Rsh(c, uint(8*i))
+			res = append(res, b.And(b, big.NewInt(0xff)))
+		}
+	}
+	return res
+}
+
+func TestVerifySchnorrEmulated(t *testing.T) {
+	assert := require.New(t)
+	_, g := secp256k1.Generators()
+	order := emulated.Secp256k1Fr{}.Modulus()
+	msg := big.NewInt(7)
+
+	x, k := big.NewInt(123456789), big.NewInt(987654321)
+	var pk, r secp256k1.G1Affine
+	pk.ScalarMultiplication(&g, x)
+	r.ScalarMultiplication(&g, k)
+
+	var transcript []*big.Int
+	for _, p := range []*secp256k1.G1Affine{&g, &pk, &r} {
+		transcript = append(transcript, coordinateBytes(32, p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int)))...)
+	}
+	// the hash has less bits than the order: it isn't truncated
+	c := challenge(append(transcript, msg)...)
+	s := new(big.Int).Mul(c, x)
+	s.Add(s, k).Mod(s, order)
+
+	witness := &emulatedSchnorrCircuit{
+		PK:  sw_emulated.AffinePoint[emulated.Secp256k1Fp]{X: emulated.ValueOf[emulated.Secp256k1Fp](pk.X), Y: emulated.ValueOf[emulated.Secp256k1Fp](pk.Y)},
+		Msg: msg,
+		Proof: EmulatedSchnorrProof[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Commitment: sw_emulated.AffinePoint[emulated.Secp256k1Fp]{X: emulated.ValueOf[emulated.Secp256k1Fp](r.X), Y: emulated.ValueOf[emulated.Secp256k1Fp](r.Y)},
+			Response:   emulated.ValueOf[emulated.Secp256k1Fr](s),
+		},
+	}
+	assert.NoError(test.IsSolved(&emulatedSchnorrCircuit{}, witness, ecc.BN254.ScalarField()))
+
+	witness.Msg = big.NewInt(8)
+	assert.Error(test.IsSolved(&emulatedSchnorrCircuit{}, witness, ecc.BN254.ScalarField()))
+}
+
+type emulatedSchnorrBLS12381Circuit struct {
+	PK    sw_emulated.AffinePoint[emulated.BLS12381Fp]
+	Msg   frontend.Variable
+	Proof EmulatedSchnorrProof[emulated.BLS12381Fp, emulated.BLS12381Fr]
+}
+
+func (c *emulatedSchnorrBLS12381Circuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return VerifySchnorrEmulated(api, sw_emulated.GetBLS12381Params(), &h, &c.PK, &c.Proof, c.Msg)
+}
+
+func blsPoint(p *bls12381.G1Affine) sw_emulated.AffinePoint[emulated.BLS12381Fp] {
+	return sw_emulated.AffinePoint[emulated.BLS12381Fp]{X: emulated.ValueOf[emulated.BLS12381Fp](p.X), Y: emulated.ValueOf[emulated.BLS12381Fp](p.Y)}
+}
+
+// smallOrderPoint returns a point of order 3 of the curve of BLS12-381 G1.
+func smallOrderPoint(t *testing.T) bls12381.G1Affine {
+	var rh3 big.Int
+	rh3.Mul(emulated.BLS12381Fr{}.Modulus(), sw_emulated.GetBLS12381Params().Cofactor)
+	rh3.Div(&rh3, big.NewInt(3))
+	for x := int64(1); ; x++ {
+		var p bls12381.G1Affine
+		p.X.SetInt64(x)
+		var y2 fp.Element
+		y2.Square(&p.X).Mul(&y2, &p.X).Add(&y2, new(fp.Element).SetInt64(4))
+		if p.Y.Sqrt(&y2) == nil {
+			continue
+		}
+		// double and add, the scalar multiplication of gnark-crypto assumes a
+		// point of the subgroup
+		var pj, res bls12381.G1Jac
+		pj.FromAffine(&p)
+		for i := rh3.BitLen() - 1; i >= 0; i-- {
+			res.DoubleAssign()
+			if rh3.Bit(i) == 1 {
+				res.AddAssign(&pj)
+			}
+		}
+		if res.Z.IsZero() {
+			continue
+		}
+		var torsion bls12381.G1Affine
+		torsion.FromJacobian(&res)
+		require.True(t, torsion.IsOnCurve())
+		return torsion
+	}
+}
+
+func TestVerifySchnorrEmulatedCofactor(t *testing.T) {
+	assert := require.New(t)
+	_, _, g, _ := bls12381.Generators()
+	order := emulated.BLS12381Fr{}.Modulus()
+	msg := big.NewInt(7)
+
+	x := big.NewInt(123456789)
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplication(&g, x)
+
+	prove := func(pk *bls12381.G1Affine, accept func(c *big.Int) bool) *emulatedSchnorrBLS12381Circuit {
+		for k := big.NewInt(987654321); ; k.Add(k, big.NewInt(1)) {
+			var r bls12381.G1Affine
+			r.ScalarMultiplication(&g, k)
+			var transcript []*big.Int
+			for _, p := range []*bls12381.G1Affine{&g, pk, &r} {
+				transcript = append(transcript, coordinateBytes(48, p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int)))...)
+			}
+			c := challenge(append(transcript, msg)...)
+			if !accept(c) {
+				continue
+			}
+			s := new(big.Int).Mul(c, x)
+			s.Add(s, k).Mod(s, order)
+			return &emulatedSchnorrBLS12381Circuit{
+				PK:  blsPoint(pk),
+				Msg: msg,
+				Proof: EmulatedSchnorrProof[emulated.BLS12381Fp, emulated.BLS12381Fr]{
+					Commitment: blsPoint(&r),
+					Response:   emulated.ValueOf[emulated.BLS12381Fr](s),
+				},
+			}
+		}
+	}
+	witness := prove(&pk, func(*big.Int) bool { return true })
+	assert.NoError(test.IsSolved(&emulatedSchnorrBLS12381Circuit{}, witness, ecc.BN254.ScalarField()))
+
+	// a small order component of pk is cleared by the cofactor: the proof is a
+	// proof for [cofactor]pk, even with a challenge which doesn't cancel it.
+	torsion := smallOrderPoint(t)
+	var pkt bls12381.G1Affine
+	pkt.Add(&pk, &torsion)
+	witness = prove(&pkt, func(c *big.Int) bool { return new(big.Int).Mod(c, big.NewInt(3)).Sign() != 0 })
+	assert.NoError(test.IsSolved(&emulatedSchnorrBLS12381Circuit{}, witness, ecc.BN254.ScalarField()))
+}
@@ -16,6 +16,7 @@ package ecdsa
 import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/std/math/emulated"
 )
 
@@ -30,7 +31,8 @@ type PublicKey[Base, Scalar emulated.FieldParams] sw_emulated.AffinePoint[Base]
 // Verify asserts that the signature sig verifies for the message msg and public
 // key pk. The curve parameters params define the elliptic curve.
 //
-// We assume that the message msg is already hashed to the scalar field.
+// We assume that the message msg is already hashed to the scalar field, see
+// HashToScalar.
 func (pk PublicKey[T, S]) Verify(api frontend.API, params sw_emulated.CurveParams, msg *emulated.Element[S], sig *Signature[S]) {
 	cr, err := sw_emulated.New[T, S](api, params)
 	if err != nil {
@@ -63,3 +65,40 @@ func (pk PublicKey[T, S]) Verify(api frontend.API, params sw_emulated.CurveParam
 		api.AssertIsEqual(rbits[i], qxBits[i])
 	}
 }
+
+// HashToScalar returns the scalar of the message hash, given as big-endian
+// bytes: the integer of its leftmost bits, as many as the bits of the order of
+// the curve, reduced modulo the order (see SEC 1, section 4.1.3). The bytes are
+// range checked.
+func HashToScalar[Scalar emulated.FieldParams](api frontend.API, hash []frontend.Variable) *emulated.Element[Scalar] {
+	scalarApi, err := emulated.NewField[Scalar](api)
+	if err != nil {
+		panic(err)
+	}
+	var fr Scalar
+	nbBits := fr.Modulus().BitLen()
+	be := bits.WithEndianness(bits.BigEndian)
+	if excess := 8*len(hash) - nbBits; excess > 0 {
+		hash = hash[:len(hash)-excess/8]
+		if excess%8 != 0 {
+			// shift the leftmost bits to the right, through the big-endian
+			// bits of the hash
+			var hbits []frontend.Variable
+			for i := range hash {
+				hbits = append(hbits, bits.ToBinary(api, hash[i], bits.WithWidth(8), be)...)
+			}
+			hbits = hbits[:nbBits]
+			truncated := make([]frontend.Variable, (nbBits+7)/8)
+			for i := range truncated {
+				end := nbBits - 8*(len(truncated)-1-i)
+				start := end - 8
+				if start < 0 {
+					start = 0
+				}
+				truncated[i] = bits.FromBinary(api, hbits[start:end], bits.WithUnchecked(), be)
+			}
+			return scalarApi.FromBytes(truncated, bits.WithUnchecked(), be)
+		}
+	}
+	return scalarApi.FromBytes(hash, be)
+}
@@ -0,0 +1,69 @@
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type hashToScalarCircuit[S emulated.FieldParams] struct {
+	Hash     []frontend.Variable
+	Expected emulated.Element[S]
+}
+
+func (c *hashToScalarCircuit[S]) Define(api frontend.API) error {
+	f, err := emulated.NewField[S](api)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(HashToScalar[S](api, c.Hash), &c.Expected)
+	return nil
+}
+
+func testHashToScalar[S emulated.FieldParams](t *testing.T, hash []byte) {
+	assert := require.New(t)
+	var fr S
+
+	// the leftmost bits of the hash, reduced
+	e := new(big.Int).SetBytes(hash)
+	if excess := 8*len(hash) - fr.Modulus().BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	e.Mod(e, fr.Modulus())
+
+	circuit := &hashToScalarCircuit[S]{Hash: make([]frontend.Variable, len(hash))}
+	witness := &hashToScalarCircuit[S]{Hash: make([]frontend.Variable, len(hash)), Expected: emulated.ValueOf[S](e)}
+	for i := range hash {
+		witness.Hash[i] = hash[i]
+	}
+	assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	e.Add(e, big.NewInt(1))
+	witness.Expected = emulated.ValueOf[S](e)
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
+
+func TestHashToScalar(t *testing.T) {
+	h256 := sha256.Sum256([]byte("gnark"))
+	h512 := sha512.Sum512([]byte("gnark"))
+	// larger than the order of secp256k1
+	large := make([]byte, 32)
+	for i := range large {
+		large[i] = 0xff
+	}
+
+	t.Run("secp256k1/sha256", func(t *testing.T) { testHashToScalar[emulated.Secp256k1Fr](t, h256[:]) })
+	t.Run("secp256k1/large", func(t *testing.T) { testHashToScalar[emulated.Secp256k1Fr](t, large) })
+	t.Run("secp256k1/sha512", func(t *testing.T) { testHashToScalar[emulated.Secp256k1Fr](t, h512[:]) })
+	t.Run("secp256k1/short", func(t *testing.T) { testHashToScalar[emulated.Secp256k1Fr](t, h256[:20]) })
+	// the order of BN254 has 254 bits: the hash is shifted by 2 bits
+	t.Run("bn254/sha256", func(t *testing.T) { testHashToScalar[emulated.BN254Fr](t, h256[:]) })
+	t.Run("bn254/large", func(t *testing.T) { testHashToScalar[emulated.BN254Fr](t, large) })
+}
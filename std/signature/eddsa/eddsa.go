@@ -19,6 +19,7 @@ package eddsa
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/consensys/gnark/logger"
 	"github.com/consensys/gnark/std/hash"
@@ -26,9 +27,19 @@ import (
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/native/twistededwards"
 
+	edbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	edbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	edbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edbls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/twistededwards"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	edbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
 	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 )
 
+var errNotOnCurve = errors.New("point not on curve")
+
 // PublicKey stores an eddsa public key (to be used in gnark circuit)
 type PublicKey struct {
 	A twistededwards.Point
@@ -114,13 +125,126 @@ func (s *Signature) Assign(curveID tedwards.ID, buf []byte) {
 	s.S = S
 }
 
-// parseSignature parses a compressed binary signature into uncompressed R.X, R.Y and S
+// parseSignature parses a compressed binary signature into uncompressed R.X, R.Y and S.
+// The signature is the compressed point R followed by S, both of the size of
+// the base field of the curve, as serialized by gnark-crypto.
 func parseSignature(curveID tedwards.ID, buf []byte) ([]byte, []byte, []byte, error) {
-	panic("not implemented")
-
+	rx, ry, n, err := parseCompressedPoint(curveID, buf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(buf) != 2*n {
+		return nil, nil, nil, fmt.Errorf("expected a signature of %d bytes, got %d", 2*n, len(buf))
+	}
+	return rx, ry, buf[n:], nil
 }
 
 // parsePoint parses a compressed binary point into uncompressed P.X and P.Y
 func parsePoint(curveID tedwards.ID, buf []byte) ([]byte, []byte, error) {
-	panic("not implemented")
+	x, y, n, err := parseCompressedPoint(curveID, buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(buf) != n {
+		return nil, nil, fmt.Errorf("expected a point of %d bytes, got %d", n, len(buf))
+	}
+	return x, y, nil
+}
+
+// parseCompressedPoint parses the compressed point at the start of buf, and
+// returns its coordinates and the number of bytes read. The point must be on
+// the curve.
+func parseCompressedPoint(curveID tedwards.ID, buf []byte) ([]byte, []byte, int, error) {
+	switch curveID {
+	case tedwards.BN254:
+		var p edbn254.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BLS12_377:
+		var p edbls12377.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BLS12_381:
+		var p edbls12381.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BLS12_381_BANDERSNATCH:
+		var p bandersnatch.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BLS24_315:
+		var p edbls24315.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BLS24_317:
+		var p edbls24317.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BW6_761:
+		var p edbw6761.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	case tedwards.BW6_633:
+		var p edbw6633.PointAffine
+		n, err := p.SetBytes(buf)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if !p.IsOnCurve() {
+			return nil, nil, 0, errNotOnCurve
+		}
+		x, y := p.X.Bytes(), p.Y.Bytes()
+		return x[:], y[:], n, nil
+	default:
+		return nil, nil, 0, errors.New("unknown curve")
+	}
 }
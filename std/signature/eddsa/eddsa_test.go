@@ -0,0 +1,164 @@
+package eddsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	eddsabw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards/eddsa"
+	eddsabw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards/eddsa"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark-crypto/signature"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type eddsaCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (c *eddsaCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, c.curveID)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, c.Signature, c.Message, c.PublicKey, &h)
+}
+
+func sign(t *testing.T, curveID tedwards.ID, hashID hash.Hash) (signature.PublicKey, []byte, []byte) {
+	signer, err := cryptoeddsa.New(curveID, rand.Reader)
+	require.NoError(t, err)
+	snarkField, err := twistededwards.GetSnarkField(curveID)
+	require.NoError(t, err)
+	msg := big.NewInt(42).FillBytes(make([]byte, (snarkField.BitLen()+7)/8))
+	sig, err := signer.Sign(msg, hashID.New())
+	require.NoError(t, err)
+	return signer.Public(), sig, msg
+}
+
+func TestAssign(t *testing.T) {
+	// the signatures are verified in the circuit on the curves with an
+	// in-circuit MiMC
+	for _, tc := range []struct {
+		curveID tedwards.ID
+		hashID  hash.Hash
+	}{
+		{tedwards.BN254, hash.MIMC_BN254},
+		{tedwards.BLS12_377, hash.MIMC_BLS12_377},
+		{tedwards.BLS12_381, hash.MIMC_BLS12_381},
+		{tedwards.BLS24_315, hash.MIMC_BLS24_315},
+		{tedwards.BLS24_317, hash.MIMC_BLS24_317},
+	} {
+		assert := require.New(t)
+		pub, sig, msg := sign(t, tc.curveID, tc.hashID)
+
+		witness := &eddsaCircuit{Message: msg}
+		witness.PublicKey.Assign(tc.curveID, pub.Bytes())
+		witness.Signature.Assign(tc.curveID, sig)
+		snarkField, err := twistededwards.GetSnarkField(tc.curveID)
+		assert.NoError(err)
+		assert.NoError(test.IsSolved(&eddsaCircuit{curveID: tc.curveID}, witness, snarkField), "curve %d", tc.curveID)
+
+		witness.Message = 43
+		assert.Error(test.IsSolved(&eddsaCircuit{curveID: tc.curveID}, witness, snarkField), "curve %d", tc.curveID)
+	}
+
+	// on the others, the coordinates are compared with the ones of gnark-crypto
+	for _, tc := range []struct {
+		curveID tedwards.ID
+		hashID  hash.Hash
+		// coordinates returns the coordinates of the public key and of R
+		coordinates func(pub signature.PublicKey, sig []byte) [4][]byte
+	}{
+		{tedwards.BW6_761, hash.MIMC_BW6_761, func(pub signature.PublicKey, sig []byte) [4][]byte {
+			var s eddsabw6761.Signature
+			if _, err := s.SetBytes(sig); err != nil {
+				t.Fatal(err)
+			}
+			a := pub.(*eddsabw6761.PublicKey).A
+			ax, ay, rx, ry := a.X.Bytes(), a.Y.Bytes(), s.R.X.Bytes(), s.R.Y.Bytes()
+			return [4][]byte{ax[:], ay[:], rx[:], ry[:]}
+		}},
+		{tedwards.BW6_633, hash.MIMC_BW6_633, func(pub signature.PublicKey, sig []byte) [4][]byte {
+			var s eddsabw6633.Signature
+			if _, err := s.SetBytes(sig); err != nil {
+				t.Fatal(err)
+			}
+			a := pub.(*eddsabw6633.PublicKey).A
+			ax, ay, rx, ry := a.X.Bytes(), a.Y.Bytes(), s.R.X.Bytes(), s.R.Y.Bytes()
+			return [4][]byte{ax[:], ay[:], rx[:], ry[:]}
+		}},
+	} {
+		assert := require.New(t)
+		pub, sig, _ := sign(t, tc.curveID, tc.hashID)
+		expected := tc.coordinates(pub, sig)
+
+		var pk PublicKey
+		pk.Assign(tc.curveID, pub.Bytes())
+		var s Signature
+		s.Assign(tc.curveID, sig)
+		assert.Equal(expected, [4][]byte{pk.A.X.([]byte), pk.A.Y.([]byte), s.R.X.([]byte), s.R.Y.([]byte)}, "curve %d", tc.curveID)
+		assert.Equal(sig[len(sig)/2:], s.S, "curve %d", tc.curveID)
+	}
+
+	// the EdDSA of gnark-crypto on Bandersnatch signs with points of Jubjub, so
+	// the points are made with the curve package
+	assert := require.New(t)
+	var p bandersnatch.PointAffine
+	base := bandersnatch.GetEdwardsCurve().Base
+	p.ScalarMultiplication(&base, big.NewInt(42))
+	buf := p.Bytes()
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	var pk PublicKey
+	pk.Assign(tedwards.BLS12_381_BANDERSNATCH, buf[:])
+	assert.Equal(x[:], pk.A.X)
+	assert.Equal(y[:], pk.A.Y)
+	var sig Signature
+	sig.Assign(tedwards.BLS12_381_BANDERSNATCH, append(buf[:], x[:]...))
+	assert.Equal(x[:], sig.R.X)
+	assert.Equal(y[:], sig.R.Y)
+	assert.Equal(x[:], sig.S)
+}
+
+func TestParseErrors(t *testing.T) {
+	assert := require.New(t)
+	pub, sig, _ := sign(t, tedwards.BN254, hash.MIMC_BN254)
+
+	_, _, err := parsePoint(tedwards.BN254, pub.Bytes()[:31])
+	assert.Error(err)
+	_, _, err = parsePoint(tedwards.BN254, append(pub.Bytes(), 0))
+	assert.Error(err)
+	_, _, _, err = parseSignature(tedwards.BN254, sig[:63])
+	assert.Error(err)
+	_, _, err = parsePoint(tedwards.UNKNOWN, pub.Bytes())
+	assert.Error(err)
+
+	// the y coordinates of half of the points aren't on the curve
+	nbErrors := 0
+	for y := 2; y < 34; y++ {
+		buf := make([]byte, 32)
+		buf[0] = byte(y)
+		if _, _, err := parsePoint(tedwards.BN254, buf); err != nil {
+			nbErrors++
+		}
+	}
+	assert.NotZero(nbErrors)
+
+	assert.Panics(func() {
+		var pk PublicKey
+		pk.Assign(tedwards.BN254, nil)
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Package rsa implements the verification of RSA signatures with the PKCS #1 v1.5
+encoding of SHA-256 digests (RSASSA-PKCS1-v1_5 of RFC 8017), for the public
+exponent 65537.
+
+The modulus of the public key is a circuit variable: the integers are emulated
+by a field of parameters such as [emulated.Mod1e2048], whose width is the size
+of the modulus, and reduced with [emulated.Field.ModMul]. The exponentiation by
+65537 costs 17 modular multiplications; a verification for a 2048-bit modulus
+is about 56k constraints in R1CS.
+*/
+package rsa
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Signature represents the signature for some message, the integer s.
+type Signature[T emulated.FieldParams] struct {
+	S emulated.Element[T]
+}
+
+// PublicKey represents the public key to verify the signature for, of exponent
+// 65537. The modulus N has as many bits as the modulus of T.
+type PublicKey[T emulated.FieldParams] struct {
+	N emulated.Element[T]
+}
+
+// sha256Prefix is the DER encoding of the DigestInfo of SHA-256 (RFC 8017,
+// section 9.2), up to the digest.
+var sha256Prefix = []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+
+// Verify asserts that the signature sig verifies for the SHA-256 digest of the
+// message and public key pk: sig.S^65537 mod pk.N is the PKCS #1 v1.5 encoding
+// of the digest, given as 32 big-endian bytes. The bytes are range checked.
+func (pk PublicKey[T]) Verify(api frontend.API, digest []frontend.Variable, sig *Signature[T]) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		panic(err)
+	}
+	if len(digest) != 32 {
+		panic("SHA-256 digests are 32 bytes long")
+	}
+
+	// S^65537 = S^(2^16)·S
+	res := &sig.S
+	for i := 0; i < 16; i++ {
+		res = f.ModMul(res, res, &pk.N)
+	}
+	res = f.ModMul(res, &sig.S, &pk.N)
+
+	// EM = 0x00 || 0x01 || 0xff... || 0x00 || DigestInfo, as long as N
+	var fp T
+	em := make([]frontend.Variable, (fp.Modulus().BitLen()+7)/8)
+	psEnd := len(em) - len(sha256Prefix) - len(digest) - 1
+	em[0], em[1], em[psEnd] = 0x00, 0x01, 0x00
+	for i := 2; i < psEnd; i++ {
+		em[i] = 0xff
+	}
+	for i, b := range sha256Prefix {
+		em[psEnd+1+i] = b
+	}
+	copy(em[len(em)-len(digest):], digest)
+	f.AssertLimbsEquality(res, f.FromBytes(em, bits.WithEndianness(bits.BigEndian)))
+}
@@ -0,0 +1,50 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	cryptorsa "crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type verifyCircuit[T emulated.FieldParams] struct {
+	PublicKey PublicKey[T] `gnark:",public"`
+	Digest    [32]frontend.Variable
+	Signature Signature[T]
+}
+
+func (c *verifyCircuit[T]) Define(api frontend.API) error {
+	c.PublicKey.Verify(api, c.Digest[:], &c.Signature)
+	return nil
+}
+
+func TestVerify(t *testing.T) {
+	assert := require.New(t)
+
+	sk, err := cryptorsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	digest := sha256.Sum256([]byte("gnark"))
+	sig, err := cryptorsa.SignPKCS1v15(rand.Reader, sk, crypto.SHA256, digest[:])
+	assert.NoError(err)
+
+	witness := &verifyCircuit[emulated.Mod1e2048]{
+		PublicKey: PublicKey[emulated.Mod1e2048]{N: emulated.ValueOf[emulated.Mod1e2048](sk.N)},
+		Signature: Signature[emulated.Mod1e2048]{S: emulated.ValueOf[emulated.Mod1e2048](new(big.Int).SetBytes(sig))},
+	}
+	for i := range digest {
+		witness.Digest[i] = digest[i]
+	}
+	assert.NoError(test.IsSolved(&verifyCircuit[emulated.Mod1e2048]{}, witness, ecc.BN254.ScalarField()))
+
+	// the signature of another message
+	witness.Digest[0] = digest[0] ^ 1
+	assert.Error(test.IsSolved(&verifyCircuit[emulated.Mod1e2048]{}, witness, ecc.BN254.ScalarField()))
+}
@@ -0,0 +1,504 @@
+/*
+Package x509 implements the verification of X.509 certificate chains signed
+with ECDSA or RSA and SHA-256, up to a pinned root key.
+
+The certificates are given in circuit by their DER encoded TBSCertificate, as
+bytes, and their signature. As parsing DER in circuit would be too expensive,
+the layout of each certificate, that is the length of its TBSCertificate and
+the offsets of its fields, is fixed when the circuit is compiled: all the
+certificates of an issuer profile share it, see [NewLayout]. The circuit
+asserts the bytes the layout relies on: the DER headers of the TBSCertificate,
+of its fields and of its extensions, which pin the subject public key to the
+parsed structure, the algorithm identifiers and the encoding of the subject
+public key, and the basicConstraints and keyUsage extensions. Every certificate
+of a chain but the last one must be a CA, with cA TRUE in its basicConstraints.
+
+The certificates of a chain all use the same algorithm: ECDSA with the keys
+over the curve of the chain in the uncompressed encoding (see [VerifyChain]),
+or RSA PKCS #1 v1.5 with the keys of exponent 65537 (see [VerifyRSAChain]).
+The other hash functions and the checks of validity and names are not
+supported.
+*/
+package x509
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/consensys/gnark/std/signature/rsa"
+)
+
+// Algorithm is the algorithm of the signature of a certificate, which is also
+// the algorithm of its subject public key.
+type Algorithm int
+
+const (
+	// ECDSAWithSHA256 is ECDSA with SHA-256, for the elliptic curve keys.
+	ECDSAWithSHA256 Algorithm = iota
+	// SHA256WithRSA is RSA PKCS #1 v1.5 with SHA-256, for the RSA keys of
+	// exponent 65537.
+	SHA256WithRSA
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case ECDSAWithSHA256:
+		return "ECDSA-SHA256"
+	case SHA256WithRSA:
+		return "SHA256-RSA"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+}
+
+// Span is a string of bytes at an offset of a TBSCertificate.
+type Span struct {
+	Offset int
+	Bytes  []byte
+}
+
+// Layout is the layout of the DER encoded TBSCertificate of a certificate.
+type Layout struct {
+	// TBSLength is the length of the TBSCertificate, in bytes.
+	TBSLength int
+	// Algorithm is the algorithm of the signature and of the subject public
+	// key.
+	Algorithm Algorithm
+	// Fixed are the bytes of the TBSCertificate which the layout relies on,
+	// shared by the certificates of the profile: the DER headers of the
+	// TBSCertificate, of its fields and of its extensions, the version, the
+	// algorithm identifiers, the encoding of the subject public key up to the
+	// key, and the basicConstraints and keyUsage extensions.
+	Fixed []Span
+	// PublicKeyOffset is the offset in the TBSCertificate of the subject public
+	// key: the big-endian coordinates X and Y of an elliptic curve point, or
+	// the big-endian modulus of an RSA key.
+	PublicKeyOffset int
+	// PublicKeyLength is the length of the subject public key, in bytes.
+	PublicKeyLength int
+	// CA is set when Fixed holds a basicConstraints extension with cA TRUE.
+	CA bool
+}
+
+// Certificate is a certificate signed with ECDSA in circuit: the bytes of its
+// TBSCertificate, and its signature.
+type Certificate[Base, Scalar emulated.FieldParams] struct {
+	TBS       []frontend.Variable
+	Signature ecdsa.Signature[Scalar]
+}
+
+// NewCertificate returns a certificate of layout l, to be used as the
+// definition of a circuit.
+func NewCertificate[Base, Scalar emulated.FieldParams](l Layout) Certificate[Base, Scalar] {
+	return Certificate[Base, Scalar]{TBS: make([]frontend.Variable, l.TBSLength)}
+}
+
+// RSACertificate is a certificate signed with RSA in circuit: the bytes of its
+// TBSCertificate, and its signature.
+type RSACertificate[T emulated.FieldParams] struct {
+	TBS       []frontend.Variable
+	Signature rsa.Signature[T]
+}
+
+// NewRSACertificate returns a certificate of layout l, to be used as the
+// definition of a circuit.
+func NewRSACertificate[T emulated.FieldParams](l Layout) RSACertificate[T] {
+	return RSACertificate[T]{TBS: make([]frontend.Variable, l.TBSLength)}
+}
+
+// VerifyChain asserts that the first certificate of chain is signed by the
+// root key, and each other one by the subject key of the previous one, and
+// returns the subject key of the last one. The certificates are signed with
+// ECDSA over the curve of params and SHA-256, and layouts are their layouts.
+//
+// The subject keys are not asserted to be on the curve: they are trusted as
+// much as their issuer.
+func VerifyChain[Base, Scalar emulated.FieldParams](api frontend.API, params sw_emulated.CurveParams, root *ecdsa.PublicKey[Base, Scalar], chain []Certificate[Base, Scalar], layouts []Layout) (*ecdsa.PublicKey[Base, Scalar], error) {
+	if err := checkLayouts(len(chain), layouts, ECDSAWithSHA256); err != nil {
+		return nil, err
+	}
+	baseApi, err := emulated.NewField[Base](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	var fp Base
+	size := (fp.Modulus().BitLen() + 7) / 8
+	be := bits.WithEndianness(bits.BigEndian)
+	issuer := root
+	for i := range chain {
+		key, err := subjectPublicKey(api, chain[i].TBS, layouts[i], 2*size)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+		// the digest range checks the bytes of the TBSCertificate
+		digest := sha2.Sum256(api, chain[i].TBS)
+		issuer.Verify(api, params, ecdsa.HashToScalar[Scalar](api, digest), &chain[i].Signature)
+		issuer = &ecdsa.PublicKey[Base, Scalar]{
+			X: *baseApi.FromBytes(key[:size], be, bits.WithUnchecked()),
+			Y: *baseApi.FromBytes(key[size:], be, bits.WithUnchecked()),
+		}
+	}
+	return issuer, nil
+}
+
+// VerifyRSAChain is VerifyChain for the certificates signed with RSA PKCS #1
+// v1.5 and SHA-256, by keys whose modulus has the size of the modulus of T.
+func VerifyRSAChain[T emulated.FieldParams](api frontend.API, root *rsa.PublicKey[T], chain []RSACertificate[T], layouts []Layout) (*rsa.PublicKey[T], error) {
+	if err := checkLayouts(len(chain), layouts, SHA256WithRSA); err != nil {
+		return nil, err
+	}
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	var fp T
+	size := (fp.Modulus().BitLen() + 7) / 8
+	issuer := root
+	for i := range chain {
+		key, err := subjectPublicKey(api, chain[i].TBS, layouts[i], size)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", i, err)
+		}
+		// the digest range checks the bytes of the TBSCertificate
+		digest := sha2.Sum256(api, chain[i].TBS)
+		issuer.Verify(api, digest, &chain[i].Signature)
+		issuer = &rsa.PublicKey[T]{
+			N: *f.FromBytes(key, bits.WithEndianness(bits.BigEndian), bits.WithUnchecked()),
+		}
+	}
+	return issuer, nil
+}
+
+// checkLayouts checks that the layouts of a chain of n certificates are of
+// algorithm alg, and of CAs but for the last one.
+func checkLayouts(n int, layouts []Layout, alg Algorithm) error {
+	if n != len(layouts) {
+		return fmt.Errorf("%d certificates but %d layouts", n, len(layouts))
+	}
+	for i, l := range layouts {
+		if l.Algorithm != alg {
+			return fmt.Errorf("certificate %d: algorithm %s, expected %s", i, l.Algorithm, alg)
+		}
+		if i < n-1 && !l.CA {
+			return fmt.Errorf("certificate %d: not a CA but issues certificate %d", i, i+1)
+		}
+	}
+	return nil
+}
+
+// subjectPublicKey asserts the bytes of the TBSCertificate tbs which its layout
+// relies on, and returns the bytes of its subject public key, of the given
+// length. The bytes are not range checked.
+func subjectPublicKey(api frontend.API, tbs []frontend.Variable, l Layout, length int) ([]frontend.Variable, error) {
+	if len(tbs) != l.TBSLength {
+		return nil, fmt.Errorf("TBSCertificate of %d bytes, expected %d", len(tbs), l.TBSLength)
+	}
+	if l.PublicKeyLength != length {
+		return nil, fmt.Errorf("subject public key of %d bytes, expected %d", l.PublicKeyLength, length)
+	}
+	if l.PublicKeyOffset < 0 || l.PublicKeyOffset+length > l.TBSLength {
+		return nil, errors.New("subject public key out of the TBSCertificate")
+	}
+	for _, span := range l.Fixed {
+		if span.Offset < 0 || span.Offset+len(span.Bytes) > l.TBSLength {
+			return nil, errors.New("fixed bytes out of the TBSCertificate")
+		}
+		for i, b := range span.Bytes {
+			api.AssertIsEqual(tbs[span.Offset+i], b)
+		}
+	}
+	return tbs[l.PublicKeyOffset : l.PublicKeyOffset+length], nil
+}
+
+type certificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm asn1.RawValue
+	SignatureValue     asn1.BitString
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type extension struct {
+	ID       asn1.ObjectIdentifier
+	Critical bool `asn1:"optional"`
+	Value    []byte
+}
+
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+var (
+	oidECDSAWithSHA256   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidSHA256WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECPublicKey       = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidRSAEncryption     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidKeyUsage          = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidBasicConstraints  = asn1.ObjectIdentifier{2, 5, 29, 19}
+	rsaExponent65537     = []byte{0x01, 0x00, 0x01}
+	errUnsupportedLayout = errors.New("unsupported TBSCertificate")
+)
+
+// element is a DER element at an offset.
+type element struct {
+	asn1.RawValue
+	offset int
+}
+
+// header returns the bytes of the header of e.
+func (e element) header() []byte {
+	return e.FullBytes[:len(e.FullBytes)-len(e.Bytes)]
+}
+
+// content returns the elements of the content of e.
+func (e element) content() ([]element, error) {
+	var res []element
+	offset := e.offset + len(e.header())
+	for rest := e.Bytes; len(rest) > 0; {
+		var v asn1.RawValue
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &v); err != nil {
+			return nil, err
+		}
+		res = append(res, element{RawValue: v, offset: offset})
+		offset += len(v.FullBytes)
+	}
+	return res, nil
+}
+
+// layoutBuilder collects the fixed bytes of a layout.
+type layoutBuilder struct {
+	Layout
+}
+
+func (b *layoutBuilder) fix(offset int, bytes []byte) {
+	b.Fixed = append(b.Fixed, Span{Offset: offset, Bytes: bytes})
+}
+
+func (b *layoutBuilder) fixHeader(e element) {
+	b.fix(e.offset, e.header())
+}
+
+func (b *layoutBuilder) fixAll(e element) {
+	b.fix(e.offset, e.FullBytes)
+}
+
+// NewLayout returns the layout of the DER encoded certificate der, signed with
+// ECDSA or RSA and SHA-256, whose subject public key is an uncompressed
+// elliptic curve point or an RSA key of exponent 65537 respectively. The
+// certificates of a profile share the layout of their TBSCertificate as long as
+// the lengths of their fields, serial number and validity included, are the
+// same, as well as their algorithms and their basicConstraints and keyUsage
+// extensions.
+func NewLayout(der []byte) (Layout, error) {
+	var cert certificate
+	if rest, err := asn1.Unmarshal(der, &cert); err != nil {
+		return Layout{}, fmt.Errorf("parse certificate: %w", err)
+	} else if len(rest) != 0 {
+		return Layout{}, errors.New("trailing data after the certificate")
+	}
+	tbs := element{RawValue: cert.TBSCertificate}
+	if tbs.Class != asn1.ClassUniversal || tbs.Tag != asn1.TagSequence {
+		return Layout{}, errors.New("parse TBSCertificate: not a SEQUENCE")
+	}
+	fields, err := tbs.content()
+	if err != nil {
+		return Layout{}, fmt.Errorf("parse TBSCertificate: %w", err)
+	}
+	b := &layoutBuilder{Layout: Layout{TBSLength: len(tbs.FullBytes)}}
+	b.fixHeader(tbs)
+
+	// version, serialNumber, signature, issuer, validity, subject and
+	// subjectPublicKeyInfo, then the optional unique identifiers and extensions
+	if len(fields) > 0 && fields[0].Class == asn1.ClassContextSpecific && fields[0].Tag == 0 {
+		b.fixAll(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) < 6 {
+		return Layout{}, errors.New("parse TBSCertificate: missing fields")
+	}
+	for _, f := range []element{fields[0], fields[2], fields[3], fields[4]} {
+		b.fixHeader(f)
+	}
+	b.fixAll(fields[1])
+	var sigAlg algorithmIdentifier
+	if _, err := asn1.Unmarshal(fields[1].FullBytes, &sigAlg); err != nil {
+		return Layout{}, fmt.Errorf("parse signature algorithm: %w", err)
+	}
+	switch {
+	case sigAlg.Algorithm.Equal(oidECDSAWithSHA256):
+		b.Algorithm = ECDSAWithSHA256
+	case sigAlg.Algorithm.Equal(oidSHA256WithRSA):
+		b.Algorithm = SHA256WithRSA
+	default:
+		return Layout{}, fmt.Errorf("%w: signature algorithm %s", errUnsupportedLayout, sigAlg.Algorithm)
+	}
+	if err := b.subjectPublicKeyInfo(fields[5]); err != nil {
+		return Layout{}, fmt.Errorf("subject public key: %w", err)
+	}
+	for _, f := range fields[6:] {
+		b.fixHeader(f)
+		if f.Class == asn1.ClassContextSpecific && f.Tag == 3 {
+			if err := b.extensions(f); err != nil {
+				return Layout{}, fmt.Errorf("extensions: %w", err)
+			}
+		}
+	}
+	return b.Layout, nil
+}
+
+// subjectPublicKeyInfo fixes the encoding of the subject public key info spki
+// up to the key, and locates the key.
+func (b *layoutBuilder) subjectPublicKeyInfo(spki element) error {
+	fields, err := spki.content()
+	if err != nil {
+		return err
+	}
+	if len(fields) != 2 || fields[1].Tag != asn1.TagBitString || len(fields[1].Bytes) < 2 || fields[1].Bytes[0] != 0 {
+		return errors.New("invalid subject public key info")
+	}
+	var alg algorithmIdentifier
+	if _, err := asn1.Unmarshal(fields[0].FullBytes, &alg); err != nil {
+		return err
+	}
+	b.fixHeader(spki)
+	b.fixAll(fields[0])
+	bitString := fields[1]
+	// the BIT STRING header and its unused bits
+	keyOffset := bitString.offset + len(bitString.header()) + 1
+	key := bitString.Bytes[1:]
+
+	switch b.Algorithm {
+	case ECDSAWithSHA256:
+		if !alg.Algorithm.Equal(oidECPublicKey) {
+			return fmt.Errorf("%w: ECDSA signature of a %s key", errUnsupportedLayout, alg.Algorithm)
+		}
+		if len(key)%2 != 1 || key[0] != 0x04 {
+			return fmt.Errorf("%w: not an uncompressed elliptic curve point", errUnsupportedLayout)
+		}
+		b.PublicKeyOffset, b.PublicKeyLength = keyOffset+1, len(key)-1
+	case SHA256WithRSA:
+		if !alg.Algorithm.Equal(oidRSAEncryption) {
+			return fmt.Errorf("%w: RSA signature of a %s key", errUnsupportedLayout, alg.Algorithm)
+		}
+		rsaKey := element{offset: keyOffset}
+		if _, err := asn1.Unmarshal(key, &rsaKey.RawValue); err != nil {
+			return err
+		}
+		integers, err := rsaKey.content()
+		if err != nil {
+			return err
+		}
+		if len(integers) != 2 || integers[0].Tag != asn1.TagInteger || len(integers[0].Bytes) < 2 || integers[1].Tag != asn1.TagInteger {
+			return errors.New("invalid RSA public key")
+		}
+		if string(integers[1].Bytes) != string(rsaExponent65537) {
+			return fmt.Errorf("%w: RSA public exponent other than 65537", errUnsupportedLayout)
+		}
+		modulus := integers[0]
+		b.PublicKeyOffset = modulus.offset + len(modulus.header())
+		b.PublicKeyLength = len(modulus.Bytes)
+		if modulus.Bytes[0] == 0 {
+			// the sign byte of a modulus whose most significant bit is set
+			b.PublicKeyOffset++
+			b.PublicKeyLength--
+		}
+		b.fixAll(integers[1])
+	}
+	// the encoding up to the key, headers and fixed prefixes included
+	b.fix(bitString.offset, bitString.FullBytes[:b.PublicKeyOffset-bitString.offset])
+	return nil
+}
+
+// extensions fixes the headers of the extensions, and the basicConstraints and
+// keyUsage extensions in full.
+func (b *layoutBuilder) extensions(explicit element) error {
+	outer, err := explicit.content()
+	if err != nil {
+		return err
+	}
+	if len(outer) != 1 || outer[0].Tag != asn1.TagSequence {
+		return errors.New("invalid extensions")
+	}
+	b.fixHeader(outer[0])
+	exts, err := outer[0].content()
+	if err != nil {
+		return err
+	}
+	for _, e := range exts {
+		var ext extension
+		if _, err := asn1.Unmarshal(e.FullBytes, &ext); err != nil {
+			return err
+		}
+		switch {
+		case ext.ID.Equal(oidBasicConstraints):
+			var bc basicConstraints
+			if _, err := asn1.Unmarshal(ext.Value, &bc); err != nil {
+				return fmt.Errorf("basicConstraints: %w", err)
+			}
+			b.CA = bc.IsCA
+			b.fixAll(e)
+		case ext.ID.Equal(oidKeyUsage):
+			b.fixAll(e)
+		default:
+			b.fixHeader(e)
+		}
+	}
+	return nil
+}
+
+// ValueOf returns the assignment of the DER encoded certificate der, signed
+// with ECDSA.
+func ValueOf[Base, Scalar emulated.FieldParams](der []byte) (Certificate[Base, Scalar], error) {
+	var cert certificate
+	if _, err := asn1.Unmarshal(der, &cert); err != nil {
+		return Certificate[Base, Scalar]{}, fmt.Errorf("parse certificate: %w", err)
+	}
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(cert.SignatureValue.RightAlign(), &sig); err != nil {
+		return Certificate[Base, Scalar]{}, fmt.Errorf("parse signature: %w", err)
+	}
+	return Certificate[Base, Scalar]{
+		TBS: tbsValue(&cert),
+		Signature: ecdsa.Signature[Scalar]{
+			R: emulated.ValueOf[Scalar](sig.R),
+			S: emulated.ValueOf[Scalar](sig.S),
+		},
+	}, nil
+}
+
+// RSAValueOf returns the assignment of the DER encoded certificate der, signed
+// with RSA.
+func RSAValueOf[T emulated.FieldParams](der []byte) (RSACertificate[T], error) {
+	var cert certificate
+	if _, err := asn1.Unmarshal(der, &cert); err != nil {
+		return RSACertificate[T]{}, fmt.Errorf("parse certificate: %w", err)
+	}
+	return RSACertificate[T]{
+		TBS: tbsValue(&cert),
+		Signature: rsa.Signature[T]{
+			S: emulated.ValueOf[T](new(big.Int).SetBytes(cert.SignatureValue.RightAlign())),
+		},
+	}, nil
+}
+
+// tbsValue returns the assignment of the bytes of the TBSCertificate of cert.
+func tbsValue(cert *certificate) []frontend.Variable {
+	tbs := make([]frontend.Variable, len(cert.TBSCertificate.FullBytes))
+	for i, b := range cert.TBSCertificate.FullBytes {
+		tbs[i] = b
+	}
+	return tbs
+}
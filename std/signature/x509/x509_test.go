@@ -0,0 +1,264 @@
+package x509
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	cryptorsa "crypto/rsa"
+	"crypto/sha256"
+	cryptox509 "crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	stdecdsa "github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/consensys/gnark/std/signature/rsa"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type chainCircuit[B, S emulated.FieldParams] struct {
+	Root    stdecdsa.PublicKey[B, S] `gnark:",public"`
+	Chain   []Certificate[B, S]
+	Leaf    stdecdsa.PublicKey[B, S] `gnark:",public"`
+	layouts []Layout
+}
+
+func (c *chainCircuit[B, S]) Define(api frontend.API) error {
+	leaf, err := VerifyChain(api, sw_emulated.GetCurveParams[B](), &c.Root, c.Chain, c.layouts)
+	if err != nil {
+		return err
+	}
+	baseApi, err := emulated.NewField[B](api)
+	if err != nil {
+		return err
+	}
+	baseApi.AssertIsEqual(&leaf.X, &c.Leaf.X)
+	baseApi.AssertIsEqual(&leaf.Y, &c.Leaf.Y)
+	return nil
+}
+
+// issue returns the DER encoding of a certificate of the key subject, signed
+// by issuer with ECDSA or RSA and SHA-256.
+func issue(assert *require.Assertions, subject crypto.PublicKey, issuer crypto.Signer, parent *cryptox509.Certificate, serial int64, name string, isCA bool) (*cryptox509.Certificate, []byte) {
+	template := &cryptox509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              cryptox509.KeyUsageDigitalSignature,
+		SignatureAlgorithm:    cryptox509.ECDSAWithSHA256,
+	}
+	if isCA {
+		template.KeyUsage |= cryptox509.KeyUsageCertSign
+	}
+	if _, ok := issuer.Public().(*cryptorsa.PublicKey); ok {
+		template.SignatureAlgorithm = cryptox509.SHA256WithRSA
+	}
+	if parent == nil {
+		parent = template
+	}
+	der, err := cryptox509.CreateCertificate(rand.Reader, template, parent, subject, issuer)
+	assert.NoError(err)
+	cert, err := cryptox509.ParseCertificate(der)
+	assert.NoError(err)
+	return cert, der
+}
+
+func publicKey(pk *ecdsa.PublicKey) stdecdsa.PublicKey[emulated.P384Fp, emulated.P384Fr] {
+	return stdecdsa.PublicKey[emulated.P384Fp, emulated.P384Fr]{
+		X: emulated.ValueOf[emulated.P384Fp](pk.X),
+		Y: emulated.ValueOf[emulated.P384Fp](pk.Y),
+	}
+}
+
+// assertFixed asserts that the fixed bytes of l are the bytes of the
+// TBSCertificate of cert.
+func assertFixed(assert *require.Assertions, l Layout, cert *cryptox509.Certificate) {
+	assert.Equal(len(cert.RawTBSCertificate), l.TBSLength)
+	for _, span := range l.Fixed {
+		assert.Equal(cert.RawTBSCertificate[span.Offset:span.Offset+len(span.Bytes)], span.Bytes)
+	}
+	assert.Equal(cert.IsCA, l.CA)
+}
+
+// ecdsaChain returns a chain root -> intermediate -> leaf of ECDSA keys over
+// P-384, and the circuit and witness of the chain below the root.
+func ecdsaChain(assert *require.Assertions) ([3]*ecdsa.PrivateKey, [3]*cryptox509.Certificate, *chainCircuit[emulated.P384Fp, emulated.P384Fr], *chainCircuit[emulated.P384Fp, emulated.P384Fr]) {
+	var keys [3]*ecdsa.PrivateKey
+	for i := range keys {
+		var err error
+		keys[i], err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		assert.NoError(err)
+	}
+	var certs [3]*cryptox509.Certificate
+	var ders [3][]byte
+	certs[0], ders[0] = issue(assert, &keys[0].PublicKey, keys[0], nil, 1, "root", true)
+	certs[1], ders[1] = issue(assert, &keys[1].PublicKey, keys[0], certs[0], 2, "intermediate", true)
+	certs[2], ders[2] = issue(assert, &keys[2].PublicKey, keys[1], certs[1], 3, "leaf", false)
+	assert.NoError(certs[2].CheckSignatureFrom(certs[1]))
+
+	circuit := &chainCircuit[emulated.P384Fp, emulated.P384Fr]{}
+	witness := &chainCircuit[emulated.P384Fp, emulated.P384Fr]{
+		Root: publicKey(&keys[0].PublicKey),
+		Leaf: publicKey(&keys[2].PublicKey),
+	}
+	for i := 1; i < len(certs); i++ {
+		l, err := NewLayout(ders[i])
+		assert.NoError(err)
+		assert.Equal(ECDSAWithSHA256, l.Algorithm)
+		assertFixed(assert, l, certs[i])
+		// the layout locates the subject public key
+		pk := keys[i].PublicKey
+		point := append(pk.X.FillBytes(make([]byte, 48)), pk.Y.FillBytes(make([]byte, 48))...)
+		assert.Equal(point, certs[i].RawTBSCertificate[l.PublicKeyOffset:l.PublicKeyOffset+l.PublicKeyLength])
+
+		cert, err := ValueOf[emulated.P384Fp, emulated.P384Fr](ders[i])
+		assert.NoError(err)
+		circuit.Chain = append(circuit.Chain, NewCertificate[emulated.P384Fp, emulated.P384Fr](l))
+		circuit.layouts = append(circuit.layouts, l)
+		witness.Chain = append(witness.Chain, cert)
+	}
+	return keys, certs, circuit, witness
+}
+
+func TestVerifyChain(t *testing.T) {
+	assert := require.New(t)
+
+	_, certs, circuit, witness := ecdsaChain(assert)
+	assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+
+	// an altered leaf certificate isn't signed by the intermediate key
+	leaf := certs[2].RawTBSCertificate
+	last := len(leaf) - 1
+	witness.Chain[1].TBS[last] = leaf[last] ^ 1
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
+
+func TestVerifyChainCA(t *testing.T) {
+	assert := require.New(t)
+
+	keys, certs, circuit, witness := ecdsaChain(assert)
+
+	// the leaf can't issue certificates
+	circuit.layouts[0], circuit.layouts[1] = circuit.layouts[1], circuit.layouts[0]
+	_, err := VerifyChain(nil, sw_emulated.GetCurveParams[emulated.P384Fp](), &witness.Root, witness.Chain, circuit.layouts)
+	assert.ErrorContains(err, "not a CA")
+	circuit.layouts[0], circuit.layouts[1] = circuit.layouts[1], circuit.layouts[0]
+
+	// an intermediate certificate of the same layout but cA FALSE, signed by
+	// the root key
+	tbs := append([]byte{}, certs[1].RawTBSCertificate...)
+	ca := bytes.Index(tbs, []byte{0x06, 0x03, 0x55, 0x1d, 0x13, 0x01, 0x01, 0xff, 0x04, 0x05, 0x30, 0x03, 0x01, 0x01, 0xff})
+	assert.NotEqual(-1, ca)
+	tbs[ca+14] = 0x00
+	digest := sha256.Sum256(tbs)
+	r, s, err := ecdsa.Sign(rand.Reader, keys[0], digest[:])
+	assert.NoError(err)
+	for i := range tbs {
+		witness.Chain[0].TBS[i] = tbs[i]
+	}
+	witness.Chain[0].Signature = stdecdsa.Signature[emulated.P384Fr]{
+		R: emulated.ValueOf[emulated.P384Fr](r),
+		S: emulated.ValueOf[emulated.P384Fr](s),
+	}
+	assert.Error(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()))
+}
+
+type rsaChainCircuit[T emulated.FieldParams] struct {
+	Root    rsa.PublicKey[T] `gnark:",public"`
+	Chain   []RSACertificate[T]
+	Leaf    rsa.PublicKey[T] `gnark:",public"`
+	layouts []Layout
+}
+
+func (c *rsaChainCircuit[T]) Define(api frontend.API) error {
+	leaf, err := VerifyRSAChain(api, &c.Root, c.Chain, c.layouts)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(&leaf.N, &c.Leaf.N)
+	return nil
+}
+
+func TestVerifyRSAChain(t *testing.T) {
+	assert := require.New(t)
+
+	// root -> intermediate -> leaf
+	var keys [3]*cryptorsa.PrivateKey
+	for i := range keys {
+		var err error
+		keys[i], err = cryptorsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(err)
+	}
+	root, _ := issue(assert, &keys[0].PublicKey, keys[0], nil, 1, "root", true)
+	intermediate, intermediateDER := issue(assert, &keys[1].PublicKey, keys[0], root, 2, "intermediate", true)
+	leaf, leafDER := issue(assert, &keys[2].PublicKey, keys[1], intermediate, 3, "leaf", false)
+	assert.NoError(leaf.CheckSignatureFrom(intermediate))
+
+	circuit := rsaChainCircuit[emulated.Mod1e2048]{}
+	witness := rsaChainCircuit[emulated.Mod1e2048]{
+		Root: rsa.PublicKey[emulated.Mod1e2048]{N: emulated.ValueOf[emulated.Mod1e2048](keys[0].N)},
+		Leaf: rsa.PublicKey[emulated.Mod1e2048]{N: emulated.ValueOf[emulated.Mod1e2048](keys[2].N)},
+	}
+	for i, c := range []struct {
+		cert *cryptox509.Certificate
+		der  []byte
+	}{{intermediate, intermediateDER}, {leaf, leafDER}} {
+		l, err := NewLayout(c.der)
+		assert.NoError(err)
+		assert.Equal(SHA256WithRSA, l.Algorithm)
+		assertFixed(assert, l, c.cert)
+		assert.Equal(keys[i+1].N.Bytes(), c.cert.RawTBSCertificate[l.PublicKeyOffset:l.PublicKeyOffset+l.PublicKeyLength])
+
+		cert, err := RSAValueOf[emulated.Mod1e2048](c.der)
+		assert.NoError(err)
+		circuit.Chain = append(circuit.Chain, NewRSACertificate[emulated.Mod1e2048](l))
+		circuit.layouts = append(circuit.layouts, l)
+		witness.Chain = append(witness.Chain, cert)
+	}
+	assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+	// the leaf key isn't the key of the intermediate certificate
+	witness.Leaf = witness.Root
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+func TestNewLayoutUnsupported(t *testing.T) {
+	assert := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(err)
+	template := &cryptox509.Certificate{
+		SerialNumber:       big.NewInt(1),
+		NotBefore:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:           time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC),
+		SignatureAlgorithm: cryptox509.ECDSAWithSHA384,
+	}
+	der, err := cryptox509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(err)
+	_, err = NewLayout(der)
+	assert.ErrorIs(err, errUnsupportedLayout)
+
+	// an RSA key of exponent 3
+	rsaKey, err := cryptorsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	template.SignatureAlgorithm = cryptox509.SHA256WithRSA
+	der, err = cryptox509.CreateCertificate(rand.Reader, template, template, &cryptorsa.PublicKey{N: rsaKey.N, E: 3}, rsaKey)
+	assert.NoError(err)
+	_, err = NewLayout(der)
+	assert.ErrorIs(err, errUnsupportedLayout)
+}
@@ -0,0 +1,127 @@
+// Package slices provides circuit helpers operating on slices of variables.
+//
+// The comparisons of slices ([IsZero], [IsEqual]) are amortized with a random
+// linear combination: the slice is checked through the single value
+// Σᵢ rⁱ·vᵢ, where r is derived from the commitment of the circuit to the
+// variables of all the slices compared (see std/multicommit). A comparison
+// costs about len(v)+5 constraints, instead of about 4 per element when
+// comparing the elements one by one, and is unsound only with negligible
+// probability (len(v)/|F|).
+package slices
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+var isZero = solver.NewHint("slices_is_zero", isZeroHint)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{isZero}
+}
+
+// IsZero returns 1 if all the elements of v are zero, 0 otherwise.
+func IsZero(api frontend.API, v []frontend.Variable) frontend.Variable {
+	// the constants are checked at compile time
+	vars := make([]frontend.Variable, 0, len(v))
+	for i := range v {
+		if c, ok := api.Compiler().ConstantValue(v[i]); ok {
+			if c.Sign() != 0 {
+				return 0
+			}
+			continue
+		}
+		vars = append(vars, v[i])
+	}
+
+	switch len(vars) {
+	case 0:
+		return 1
+	case 1:
+		return api.IsZero(vars[0])
+	}
+
+	res, err := api.Compiler().NewHint(isZero, 1, vars...)
+	if err != nil {
+		panic(fmt.Sprintf("is zero: %v", err))
+	}
+	// the result is checked through Σᵢ rⁱ·vᵢ, r being the challenge derived from the
+	// commitment to the variables
+	multicommit.WithCommitment(api, func(api frontend.API, r frontend.Variable) error {
+		// Horner's method, one multiplication per element
+		var s frontend.Variable = 0
+		for i := len(vars) - 1; i >= 0; i-- {
+			s = api.Add(api.Mul(s, r), vars[i])
+		}
+		api.AssertIsEqual(api.IsZero(s), res[0])
+		return nil
+	}, vars...)
+	return res[0]
+}
+
+// IsEqual returns 1 if a and b are equal element-wise, 0 otherwise. It panics if
+// they don't have the same length.
+func IsEqual(api frontend.API, a, b []frontend.Variable) frontend.Variable {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("slices of different lengths %d and %d", len(a), len(b)))
+	}
+	diff := make([]frontend.Variable, len(a))
+	for i := range a {
+		diff[i] = api.Sub(a[i], b[i])
+	}
+	return IsZero(api, diff)
+}
+
+// AssertEqual asserts that a and b are equal element-wise. It panics if they
+// don't have the same length.
+//
+// An equality is already a single constraint, a random linear combination
+// wouldn't save any: AssertEqual asserts the equality of each element.
+func AssertEqual(api frontend.API, a, b []frontend.Variable) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("slices of different lengths %d and %d", len(a), len(b)))
+	}
+	for i := range a {
+		api.AssertIsEqual(a[i], b[i])
+	}
+}
+
+// Select returns a if cond is 1 and b if cond is 0, element-wise. It panics if a
+// and b don't have the same length.
+//
+// cond is constrained to be boolean once, each element then costs a single
+// constraint.
+func Select(api frontend.API, cond frontend.Variable, a, b []frontend.Variable) []frontend.Variable {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("slices of different lengths %d and %d", len(a), len(b)))
+	}
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = api.Select(cond, a[i], b[i])
+	}
+	return res
+}
+
+func isZeroHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(outputs) != 1 {
+		return fmt.Errorf("expected 1 output, got %d", len(outputs))
+	}
+	outputs[0].SetUint64(1)
+	for i := range inputs {
+		if inputs[i].Sign() != 0 {
+			outputs[0].SetUint64(0)
+			break
+		}
+	}
+	return nil
+}
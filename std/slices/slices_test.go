@@ -0,0 +1,82 @@
+package slices
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type compareCircuit struct {
+	A, B           [4]frontend.Variable
+	Equal, AllZero frontend.Variable
+}
+
+func (c *compareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(IsEqual(api, c.A[:], c.B[:]), c.Equal)
+	api.AssertIsEqual(IsZero(api, c.A[:]), c.AllZero)
+	// constants are folded
+	api.AssertIsEqual(IsZero(api, []frontend.Variable{0, c.A[0], 0}), api.IsZero(c.A[0]))
+	api.AssertIsEqual(IsZero(api, []frontend.Variable{c.A[0], 1}), 0)
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	assert := test.NewAssert(t)
+	// plonkFRI doesn't support commitments
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+	assert.ProverSucceeded(&compareCircuit{}, &compareCircuit{
+		A:       [4]frontend.Variable{1, 2, 3, 4},
+		B:       [4]frontend.Variable{1, 2, 3, 4},
+		Equal:   1,
+		AllZero: 0,
+	}, opts...)
+	assert.ProverSucceeded(&compareCircuit{}, &compareCircuit{
+		A:       [4]frontend.Variable{0, 0, 0, 0},
+		B:       [4]frontend.Variable{0, 0, 5, 0},
+		Equal:   0,
+		AllZero: 1,
+	}, opts...)
+	assert.ProverFailed(&compareCircuit{}, &compareCircuit{
+		A:       [4]frontend.Variable{1, 2, 3, 4},
+		B:       [4]frontend.Variable{1, 2, 3, 5},
+		Equal:   1,
+		AllZero: 0,
+	}, opts...)
+	assert.ProverFailed(&compareCircuit{}, &compareCircuit{
+		A:       [4]frontend.Variable{0, 0, 0, 1},
+		B:       [4]frontend.Variable{0, 0, 0, 1},
+		Equal:   1,
+		AllZero: 1,
+	}, opts...)
+}
+
+type selectCircuit struct {
+	Cond    frontend.Variable
+	A, B, C [3]frontend.Variable
+}
+
+func (c *selectCircuit) Define(api frontend.API) error {
+	AssertEqual(api, Select(api, c.Cond, c.A[:], c.B[:]), c.C[:])
+	return nil
+}
+
+func TestSelect(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&selectCircuit{}, &selectCircuit{
+		Cond: 1,
+		A:    [3]frontend.Variable{1, 2, 3},
+		B:    [3]frontend.Variable{4, 5, 6},
+		C:    [3]frontend.Variable{1, 2, 3},
+	}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&selectCircuit{}, &selectCircuit{
+		Cond: 0,
+		A:    [3]frontend.Variable{1, 2, 3},
+		B:    [3]frontend.Variable{4, 5, 6},
+		C:    [3]frontend.Variable{1, 2, 3},
+	}, test.WithCurves(ecc.BN254))
+}
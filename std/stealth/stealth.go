@@ -0,0 +1,88 @@
+// Package stealth provides ZKP-circuit functions to derive one-time (stealth)
+// addresses on a twisted Edwards curve defined over the native field, such as
+// Baby Jubjub on BN254.
+//
+// A recipient publishes a master public key made of a spending key K = k·G and
+// a viewing key V = v·G. To issue an address to the recipient, the sender draws
+// an ephemeral scalar r and derives
+//
+//	R = r·G                 (ephemeral public key, published)
+//	t = H(r·V)              (tweak, from the Diffie-Hellman secret)
+//	P = K + t·G             (one-time public key)
+//	A = H(P)                (one-time address)
+//
+// where H hashes the coordinates of the points. The recipient recognizes the
+// address from R with its viewing key, since r·V = v·R, and spends it with the
+// one-time private key k + t. The address is unlinkable to the master public key
+// without the viewing key.
+//
+// Derive and AssertDerivation prove that an address was derived correctly, for
+// instance to issue anonymous credentials verifiably; AssertOwnership proves the
+// knowledge of the private key of an address.
+package stealth
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// MasterPublicKey is the public key of a recipient (to be used in gnark
+// circuit). Spend and View may be the same point, when the recipient doesn't
+// delegate the scanning of its addresses.
+type MasterPublicKey struct {
+	Spend twistededwards.Point
+	View  twistededwards.Point
+}
+
+// Address is a one-time address of a recipient (to be used in gnark circuit).
+type Address struct {
+	// Ephemeral is the ephemeral public key r·G of the sender.
+	Ephemeral twistededwards.Point
+	// PublicKey is the one-time public key K + H(r·V)·G.
+	PublicKey twistededwards.Point
+	// Hash is the hash of PublicKey, the address itself.
+	Hash frontend.Variable
+}
+
+// Derive returns the one-time address of the recipient of the master public key
+// for the ephemeral scalar, computed with the hash function h.
+func Derive(curve twistededwards.Curve, h hash.Hash, master MasterPublicKey, ephemeral frontend.Variable) Address {
+	curve.AssertIsOnCurve(master.Spend)
+	curve.AssertIsOnCurve(master.View)
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+
+	var res Address
+	res.Ephemeral = curve.ScalarMul(base, ephemeral)
+	tweak := hashPoint(h, curve.ScalarMul(master.View, ephemeral))
+	res.PublicKey = curve.Add(master.Spend, curve.ScalarMul(base, tweak))
+	res.Hash = hashPoint(h, res.PublicKey)
+	return res
+}
+
+// AssertDerivation asserts that address is the one-time address of the
+// recipient of the master public key for the ephemeral scalar, computed with the
+// hash function h.
+func AssertDerivation(curve twistededwards.Curve, h hash.Hash, master MasterPublicKey, ephemeral frontend.Variable, address Address) {
+	api := curve.API()
+	expected := Derive(curve, h, master, ephemeral)
+	api.AssertIsEqual(address.Ephemeral.X, expected.Ephemeral.X)
+	api.AssertIsEqual(address.Ephemeral.Y, expected.Ephemeral.Y)
+	api.AssertIsEqual(address.PublicKey.X, expected.PublicKey.X)
+	api.AssertIsEqual(address.PublicKey.Y, expected.PublicKey.Y)
+	api.AssertIsEqual(address.Hash, expected.Hash)
+}
+
+// AssertOwnership asserts that privateKey is the one-time private key of the
+// address, that is that H(privateKey·G) = address.
+func AssertOwnership(curve twistededwards.Curve, h hash.Hash, address, privateKey frontend.Variable) {
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+	curve.API().AssertIsEqual(hashPoint(h, curve.ScalarMul(base, privateKey)), address)
+}
+
+// hashPoint returns H(p.X, p.Y).
+func hashPoint(h hash.Hash, p twistededwards.Point) frontend.Variable {
+	h.Reset()
+	h.Write(p.X, p.Y)
+	return h.Sum()
+}
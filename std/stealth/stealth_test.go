@@ -0,0 +1,115 @@
+package stealth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	bjj "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type derivationCircuit struct {
+	Master    MasterPublicKey `gnark:",public"`
+	Ephemeral frontend.Variable
+	Address   Address `gnark:",public"`
+}
+
+func (c *derivationCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	AssertDerivation(curve, &h, c.Master, c.Ephemeral, c.Address)
+	return nil
+}
+
+type ownershipCircuit struct {
+	Address    frontend.Variable `gnark:",public"`
+	PrivateKey frontend.Variable
+}
+
+func (c *ownershipCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	AssertOwnership(curve, &h, c.Address, c.PrivateKey)
+	return nil
+}
+
+// hashPointNative returns H(p) with the native MiMC.
+func hashPointNative(p *bjj.PointAffine) *big.Int {
+	h := mimc.NewMiMC()
+	bx, by := p.X.Bytes(), p.Y.Bytes()
+	h.Write(bx[:])
+	h.Write(by[:])
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func point(p *bjj.PointAffine) twistededwards.Point {
+	return twistededwards.Point{X: p.X, Y: p.Y}
+}
+
+func TestStealthAddress(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bjj.GetEdwardsCurve()
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK)}
+
+	// the recipient's master keys
+	spend, view := big.NewInt(123456789), big.NewInt(987654321)
+	var spendKey, viewKey bjj.PointAffine
+	spendKey.ScalarMultiplication(&params.Base, spend)
+	viewKey.ScalarMultiplication(&params.Base, view)
+
+	// the sender derives the address
+	ephemeral := big.NewInt(42424242)
+	var ephemeralKey, shared, tweakPoint, oneTime bjj.PointAffine
+	ephemeralKey.ScalarMultiplication(&params.Base, ephemeral)
+	shared.ScalarMultiplication(&viewKey, ephemeral)
+	tweak := hashPointNative(&shared)
+	tweakPoint.ScalarMultiplication(&params.Base, tweak)
+	oneTime.Add(&spendKey, &tweakPoint)
+	address := hashPointNative(&oneTime)
+
+	// the recipient recognizes it with the viewing key, and derives the one-time
+	// private key
+	var scanned bjj.PointAffine
+	scanned.ScalarMultiplication(&ephemeralKey, view)
+	assert.Equal(tweak, hashPointNative(&scanned))
+	privateKey := new(big.Int).Add(spend, tweak)
+	privateKey.Mod(privateKey, &params.Order)
+
+	witness := &derivationCircuit{
+		Master:    MasterPublicKey{Spend: point(&spendKey), View: point(&viewKey)},
+		Ephemeral: ephemeral,
+		Address: Address{
+			Ephemeral: point(&ephemeralKey),
+			PublicKey: point(&oneTime),
+			Hash:      address,
+		},
+	}
+	assert.ProverSucceeded(&derivationCircuit{}, witness, opts...)
+	assert.ProverSucceeded(&ownershipCircuit{}, &ownershipCircuit{Address: address, PrivateKey: privateKey}, opts...)
+
+	// an address derived for another recipient
+	witness.Master.View = point(&spendKey)
+	assert.ProverFailed(&derivationCircuit{}, witness, opts...)
+
+	// the master private key doesn't own the address
+	assert.ProverFailed(&ownershipCircuit{}, &ownershipCircuit{Address: address, PrivateKey: spend}, opts...)
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+var bytesHint = solver.NewHint("to_bytes", BytesHint)
+
+func init() {
+	solver.RegisterHint(bytesHint)
+}
+
+// ToBytes decomposes v into range checked bytes. It takes the options of
+// std/math/bits: [bits.WithWidth] sets the number of bytes, by default enough
+// to hold any field element, and [bits.WithEndianness] their order, by default
+// little-endian. The bytes are always checked, [bits.WithUnchecked] is
+// rejected.
+//
+// The decomposition is unique if the bytes hold fewer bits than the modulus of
+// the field. It fails if v doesn't fit in the bytes.
+func (t *API) ToBytes(v frontend.Variable, opts ...bits.BaseConversionOption) []U8 {
+	cfg := t.bytesConfig(opts)
+	nbBytes := cfg.NbDigits
+	if nbBytes == 0 {
+		nbBytes = (t.api.Compiler().FieldBitLen() + 7) / 8
+	}
+	res, err := t.api.Compiler().NewHint(bytesHint, nbBytes, v)
+	if err != nil {
+		panic(err)
+	}
+	bs := make([]U8, nbBytes)
+	for i := range res {
+		bs[i] = t.U8(res[i])
+	}
+	t.api.AssertIsEqual(t.fromBytes(bs), v)
+	return order(bs, cfg.Endianness)
+}
+
+// FromBytes returns the integer of the bytes, in the order set by
+// [bits.WithEndianness] (little-endian by default). It adds no constraint.
+func (t *API) FromBytes(bs []U8, opts ...bits.BaseConversionOption) frontend.Variable {
+	cfg := t.bytesConfig(opts)
+	return t.fromBytes(order(bs, cfg.Endianness))
+}
+
+// U64ToBytes returns the bytes of a, 8 unless set by [bits.WithWidth], in the
+// order set by [bits.WithEndianness]. It fails if a doesn't fit in the bytes.
+func (t *API) U64ToBytes(a U64, opts ...bits.BaseConversionOption) []U8 {
+	cfg := t.bytesConfig(opts)
+	if cfg.NbDigits > 8 {
+		panic(fmt.Sprintf("a U64 has 8 bytes, not %d", cfg.NbDigits))
+	}
+	if cfg.NbDigits == 0 {
+		opts = append(opts, bits.WithWidth(8))
+	}
+	return t.ToBytes(a.v, opts...)
+}
+
+// BytesToU64 returns the U64 of at most 8 bytes, in the order set by
+// [bits.WithEndianness]. It adds no constraint.
+func (t *API) BytesToU64(bs []U8, opts ...bits.BaseConversionOption) U64 {
+	if len(bs) > 8 {
+		panic(fmt.Sprintf("a U64 has 8 bytes, not %d", len(bs)))
+	}
+	return U64{t.FromBytes(bs, opts...)}
+}
+
+func (t *API) bytesConfig(opts []bits.BaseConversionOption) *bits.BaseConversionConfig {
+	cfg, err := bits.NewBaseConversionConfig(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Unchecked {
+		panic(errors.New("the bytes of the typed API are always checked"))
+	}
+	return cfg
+}
+
+// fromBytes returns Σ 256ⁱ·bs[i].
+func (t *API) fromBytes(bs []U8) frontend.Variable {
+	var res frontend.Variable = 0
+	c := big.NewInt(1)
+	for i := range bs {
+		res = t.api.Add(res, t.api.Mul(bs[i].v, c))
+		c = new(big.Int).Lsh(c, 8)
+	}
+	return res
+}
+
+// order converts little-endian bytes to the endianness e, and back.
+func order(bs []U8, e bits.Endianness) []U8 {
+	if e != bits.BigEndian {
+		return bs
+	}
+	res := make([]U8, len(bs))
+	for i := range bs {
+		res[len(bs)-1-i] = bs[i]
+	}
+	return res
+}
+
+// BytesHint returns the little-endian bytes of its input, as many as outputs.
+func BytesHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 {
+		return fmt.Errorf("expected 1 input, got %d", len(inputs))
+	}
+	v := new(big.Int).Set(inputs[0])
+	mask := big.NewInt(0xff)
+	for i := range outputs {
+		outputs[i].And(v, mask)
+		v.Rsh(v, 8)
+	}
+	return nil
+}
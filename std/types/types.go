@@ -0,0 +1,232 @@
+// Package types provides typed wrappers around [frontend.Variable] for common
+// quantities: booleans, 8 and 64 bits unsigned integers and tagged field
+// elements.
+//
+// All the operations go through an [API] instance, which range checks the
+// values entering the typed layer and asserts that the arithmetic on the
+// integers does not overflow. Mixing incompatible quantities (adding a U8 to a
+// U64, or field elements with different tags) is then a compile-time error
+// instead of silent field arithmetic. ToBytes and FromBytes convert between
+// variables and bytes, with the options of std/math/bits.
+//
+//	t := types.New(api)
+//	amount := t.U64(c.Amount)                  // range checked
+//	total := t.AddU64(amount, t.ConstU64(10)) // fails if the sum overflows
+//	t.AssertTrue(t.IsLessU64(total, t.U64(c.Balance)))
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Bool is a variable constrained to be 0 or 1.
+type Bool struct{ v frontend.Variable }
+
+// U8 is a variable constrained to be in [0, 2⁸).
+type U8 struct{ v frontend.Variable }
+
+// U64 is a variable constrained to be in [0, 2⁶⁴).
+type U64 struct{ v frontend.Variable }
+
+// FieldElement is a native field element tagged with the type T. T is only
+// used to tell quantities apart and is typically an empty struct:
+//
+//	type Balance struct{}
+//	var b types.FieldElement[Balance]
+type FieldElement[T any] struct{ v frontend.Variable }
+
+// Variable returns the underlying variable.
+func (b Bool) Variable() frontend.Variable { return b.v }
+
+// Variable returns the underlying variable.
+func (u U8) Variable() frontend.Variable { return u.v }
+
+// Variable returns the underlying variable.
+func (u U64) Variable() frontend.Variable { return u.v }
+
+// Variable returns the underlying variable.
+func (e FieldElement[T]) Variable() frontend.Variable { return e.v }
+
+// API performs the checked conversions and the operations on the typed
+// variables.
+type API struct {
+	api frontend.API
+	rc  frontend.Rangechecker
+}
+
+// New returns a new API. The range checks are done with
+// [rangecheck.New].
+func New(api frontend.API) *API {
+	return &API{api: api, rc: rangecheck.New(api)}
+}
+
+// ---------------------------------------------------------------------------------------------
+// Bool
+
+// Bool returns v as a Bool, asserting that it is 0 or 1.
+func (t *API) Bool(v frontend.Variable) Bool {
+	t.api.AssertIsBoolean(v)
+	return Bool{v}
+}
+
+// ConstBool returns the constant b.
+func (t *API) ConstBool(b bool) Bool {
+	if b {
+		return Bool{1}
+	}
+	return Bool{0}
+}
+
+// And returns a ∧ b.
+func (t *API) And(a, b Bool) Bool { return Bool{t.api.And(a.v, b.v)} }
+
+// Or returns a ∨ b.
+func (t *API) Or(a, b Bool) Bool { return Bool{t.api.Or(a.v, b.v)} }
+
+// Xor returns a ⊕ b.
+func (t *API) Xor(a, b Bool) Bool { return Bool{t.api.Xor(a.v, b.v)} }
+
+// Not returns ¬a.
+func (t *API) Not(a Bool) Bool { return Bool{t.api.Sub(1, a.v)} }
+
+// AssertTrue fails if a is false.
+func (t *API) AssertTrue(a Bool) { t.api.AssertIsEqual(a.v, 1) }
+
+// AssertFalse fails if a is true.
+func (t *API) AssertFalse(a Bool) { t.api.AssertIsEqual(a.v, 0) }
+
+// ---------------------------------------------------------------------------------------------
+// U8
+
+// U8 returns v as a U8, asserting that it fits in 8 bits.
+func (t *API) U8(v frontend.Variable) U8 {
+	t.rc.Check(v, 8)
+	return U8{v}
+}
+
+// ConstU8 returns the constant c.
+func (t *API) ConstU8(c uint8) U8 { return U8{c} }
+
+// AddU8 returns a + b, failing if the sum overflows.
+func (t *API) AddU8(a, b U8) U8 { return t.U8(t.api.Add(a.v, b.v)) }
+
+// SubU8 returns a - b, failing if a < b.
+func (t *API) SubU8(a, b U8) U8 { return t.U8(t.api.Sub(a.v, b.v)) }
+
+// MulU8 returns a * b, failing if the product overflows.
+func (t *API) MulU8(a, b U8) U8 { return t.U8(t.api.Mul(a.v, b.v)) }
+
+// IsEqualU8 returns a == b.
+func (t *API) IsEqualU8(a, b U8) Bool { return t.isEqual(a.v, b.v) }
+
+// IsLessU8 returns a < b.
+func (t *API) IsLessU8(a, b U8) Bool { return t.isLess(a.v, b.v, 8) }
+
+// SelectU8 returns a if c is true, b otherwise.
+func (t *API) SelectU8(c Bool, a, b U8) U8 { return U8{t.api.Select(c.v, a.v, b.v)} }
+
+// AssertIsEqualU8 fails if a != b.
+func (t *API) AssertIsEqualU8(a, b U8) { t.api.AssertIsEqual(a.v, b.v) }
+
+// U8ToU64 widens a. It adds no constraint.
+func (t *API) U8ToU64(a U8) U64 { return U64(a) }
+
+// BoolToU8 returns 1 if a is true, 0 otherwise. It adds no constraint.
+func (t *API) BoolToU8(a Bool) U8 { return U8(a) }
+
+// ---------------------------------------------------------------------------------------------
+// U64
+
+// U64 returns v as a U64, asserting that it fits in 64 bits.
+func (t *API) U64(v frontend.Variable) U64 {
+	t.rc.Check(v, 64)
+	return U64{v}
+}
+
+// ConstU64 returns the constant c.
+func (t *API) ConstU64(c uint64) U64 { return U64{c} }
+
+// AddU64 returns a + b, failing if the sum overflows.
+func (t *API) AddU64(a, b U64) U64 { return t.U64(t.api.Add(a.v, b.v)) }
+
+// SubU64 returns a - b, failing if a < b.
+func (t *API) SubU64(a, b U64) U64 { return t.U64(t.api.Sub(a.v, b.v)) }
+
+// MulU64 returns a * b, failing if the product overflows. The native field
+// must hold 128 bits products.
+func (t *API) MulU64(a, b U64) U64 {
+	if t.api.Compiler().FieldBitLen() <= 128 {
+		panic(fmt.Sprintf("MulU64 requires a field larger than 128 bits, got %d bits", t.api.Compiler().FieldBitLen()))
+	}
+	return t.U64(t.api.Mul(a.v, b.v))
+}
+
+// IsEqualU64 returns a == b.
+func (t *API) IsEqualU64(a, b U64) Bool { return t.isEqual(a.v, b.v) }
+
+// IsLessU64 returns a < b.
+func (t *API) IsLessU64(a, b U64) Bool { return t.isLess(a.v, b.v, 64) }
+
+// SelectU64 returns a if c is true, b otherwise.
+func (t *API) SelectU64(c Bool, a, b U64) U64 { return U64{t.api.Select(c.v, a.v, b.v)} }
+
+// AssertIsEqualU64 fails if a != b.
+func (t *API) AssertIsEqualU64(a, b U64) { t.api.AssertIsEqual(a.v, b.v) }
+
+// U64ToU8 narrows a, failing if it doesn't fit in 8 bits.
+func (t *API) U64ToU8(a U64) U8 { return t.U8(a.v) }
+
+// ---------------------------------------------------------------------------------------------
+// FieldElement
+
+// Field returns v as a field element tagged with T. Any variable is a field
+// element, no constraint is added.
+func Field[T any](v frontend.Variable) FieldElement[T] { return FieldElement[T]{v} }
+
+// U64ToField returns a as a field element tagged with T. It adds no constraint.
+func U64ToField[T any](a U64) FieldElement[T] { return FieldElement[T](a) }
+
+// Add returns a + b.
+func Add[T any](t *API, a, b FieldElement[T]) FieldElement[T] {
+	return FieldElement[T]{t.api.Add(a.v, b.v)}
+}
+
+// Sub returns a - b.
+func Sub[T any](t *API, a, b FieldElement[T]) FieldElement[T] {
+	return FieldElement[T]{t.api.Sub(a.v, b.v)}
+}
+
+// Mul returns a * b.
+func Mul[T any](t *API, a, b FieldElement[T]) FieldElement[T] {
+	return FieldElement[T]{t.api.Mul(a.v, b.v)}
+}
+
+// Div returns a / b, failing if b is zero.
+func Div[T any](t *API, a, b FieldElement[T]) FieldElement[T] {
+	return FieldElement[T]{t.api.Div(a.v, b.v)}
+}
+
+// IsEqual returns a == b.
+func IsEqual[T any](t *API, a, b FieldElement[T]) Bool { return t.isEqual(a.v, b.v) }
+
+// Select returns a if c is true, b otherwise.
+func Select[T any](t *API, c Bool, a, b FieldElement[T]) FieldElement[T] {
+	return FieldElement[T]{t.api.Select(c.v, a.v, b.v)}
+}
+
+// AssertIsEqual fails if a != b.
+func AssertIsEqual[T any](t *API, a, b FieldElement[T]) { t.api.AssertIsEqual(a.v, b.v) }
+
+func (t *API) isEqual(a, b frontend.Variable) Bool {
+	return Bool{t.api.IsZero(t.api.Sub(a, b))}
+}
+
+// isLess returns a < b for a, b < 2ⁿ: 2ⁿ + a - b fits in n bits iff a < b.
+func (t *API) isLess(a, b frontend.Variable, n int) Bool {
+	bits := t.api.ToBinary(t.api.Add(t.api.Sub(a, b), new(big.Int).Lsh(big.NewInt(1), uint(n))), n+1)
+	return Bool{t.api.Sub(1, bits[n])}
+}
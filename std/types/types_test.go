@@ -0,0 +1,132 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/types"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type balance struct{}
+
+type transferCircuit struct {
+	Balance, Amount, Fee frontend.Variable
+	Flag                 frontend.Variable
+	Byte                 frontend.Variable
+	Remaining            frontend.Variable `gnark:",public"`
+}
+
+func (c *transferCircuit) Define(api frontend.API) error {
+	t := types.New(api)
+
+	amount := t.U64(c.Amount)
+	total := t.AddU64(amount, t.U8ToU64(t.U8(c.Fee)))
+	bal := t.U64(c.Balance)
+	t.AssertFalse(t.IsLessU64(bal, total))
+	t.AssertIsEqualU64(t.SubU64(bal, total), t.U64(c.Remaining))
+
+	flag := t.Bool(c.Flag)
+	b := t.U8(c.Byte)
+	t.AssertIsEqualU8(t.SelectU8(flag, t.MulU8(b, t.ConstU8(2)), b), t.U64ToU8(t.MulU64(t.U8ToU64(b), t.U8ToU64(t.AddU8(t.BoolToU8(flag), t.ConstU8(1))))))
+
+	r := types.U64ToField[balance](t.U64(c.Remaining))
+	types.AssertIsEqual(t, types.Sub(t, types.U64ToField[balance](bal), r), types.U64ToField[balance](total))
+	t.AssertTrue(types.IsEqual(t, r, types.Field[balance](c.Remaining)))
+	return nil
+}
+
+func TestTypes(t *testing.T) {
+	assert := require.New(t)
+
+	valid := &transferCircuit{Balance: uint64(1) << 63, Amount: 1 << 40, Fee: 255, Flag: 1, Byte: 100, Remaining: uint64(1)<<63 - 1<<40 - 255}
+	for _, tc := range []struct {
+		name    string
+		witness *transferCircuit
+		ok      bool
+	}{
+		{"valid", valid, true},
+		{"flag unset", &transferCircuit{Balance: 10, Amount: 3, Fee: 2, Flag: 0, Byte: 100, Remaining: 5}, true},
+		{"insufficient balance", &transferCircuit{Balance: 10, Amount: 9, Fee: 2, Flag: 0, Byte: 1, Remaining: -1}, false},
+		{"fee overflow", &transferCircuit{Balance: 1000, Amount: 3, Fee: 256, Flag: 0, Byte: 1, Remaining: 741}, false},
+		{"U8 overflow", &transferCircuit{Balance: 10, Amount: 3, Fee: 2, Flag: 1, Byte: 128, Remaining: 5}, false},
+		{"not boolean", &transferCircuit{Balance: 10, Amount: 3, Fee: 2, Flag: 2, Byte: 1, Remaining: 5}, false},
+	} {
+		err := test.IsSolved(&transferCircuit{}, tc.witness, ecc.BN254.ScalarField())
+		if tc.ok {
+			assert.NoError(err, tc.name)
+		} else {
+			assert.Error(err, tc.name)
+		}
+	}
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &transferCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(valid, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w))
+	}
+}
+
+type bytesCircuit struct {
+	V      frontend.Variable
+	Amount frontend.Variable
+	// Bytes are the big-endian bytes of V
+	Bytes [4]frontend.Variable
+}
+
+func (c *bytesCircuit) Define(api frontend.API) error {
+	t := types.New(api)
+
+	be := bits.WithEndianness(bits.BigEndian)
+	bs := t.ToBytes(c.V, bits.WithWidth(4), be)
+	for i := range bs {
+		api.AssertIsEqual(bs[i].Variable(), c.Bytes[i])
+	}
+	api.AssertIsEqual(t.FromBytes(bs, be), c.V)
+	le := t.ToBytes(c.V, bits.WithWidth(4))
+	api.AssertIsEqual(le[0].Variable(), c.Bytes[3])
+
+	// the bits in both orders
+	bitsLE := bits.ToBinary(api, c.V, bits.WithWidth(32))
+	bitsBE := bits.ToBinary(api, c.V, bits.WithWidth(32), be)
+	for i := range bitsLE {
+		api.AssertIsEqual(bitsLE[i], bitsBE[31-i])
+	}
+	api.AssertIsEqual(bits.FromBinary(api, bitsBE, be), c.V)
+
+	amount := t.U64(c.Amount)
+	t.AssertIsEqualU64(t.BytesToU64(t.U64ToBytes(amount, be), be), amount)
+	return nil
+}
+
+func TestBytes(t *testing.T) {
+	assert := require.New(t)
+
+	valid := &bytesCircuit{V: 0x01020304, Amount: uint64(1)<<63 + 5, Bytes: [4]frontend.Variable{1, 2, 3, 4}}
+	assert.NoError(test.IsSolved(&bytesCircuit{}, valid, ecc.BN254.ScalarField()))
+
+	// little-endian bytes
+	wrong := *valid
+	wrong.Bytes = [4]frontend.Variable{4, 3, 2, 1}
+	assert.Error(test.IsSolved(&bytesCircuit{}, &wrong, ecc.BN254.ScalarField()))
+
+	// V doesn't fit in 4 bytes
+	wrong = *valid
+	wrong.V = 0x0101020304
+	assert.Error(test.IsSolved(&bytesCircuit{}, &wrong, ecc.BN254.ScalarField()))
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &bytesCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(valid, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		assert.NoError(ccs.IsSolved(w))
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrArithmetizationsDiffer is returned by CheckArithmetizations when the R1CS
+// and the SparseR1CS of a circuit disagree on a witness.
+var ErrArithmetizationsDiffer = errors.New("arithmetizations differ")
+
+// CheckArithmetizations checks that the R1CS r1cs and the SparseR1CS spr,
+// compiled from the same circuit, agree on the witness w: either both are
+// solved by it or neither, and so for w with each of its public values
+// incremented, so that the public outputs of the circuit (the public values it
+// computes and asserts) are constrained alike. It returns an error wrapping
+// ErrArithmetizationsDiffer otherwise.
+//
+// The commitments of the circuit (see frontend.Committer) are specific to each
+// arithmetization, hence only the outcomes of the solvers are compared.
+func CheckArithmetizations(r1cs, spr constraint.ConstraintSystem, w witness.Witness, opts ...solver.Option) error {
+	if _, ok := r1cs.(constraint.R1CS); !ok {
+		return fmt.Errorf("expected a R1CS, got %T", r1cs)
+	}
+	if _, ok := spr.(constraint.SparseR1CS); !ok {
+		return fmt.Errorf("expected a SparseR1CS, got %T", spr)
+	}
+	if r1cs.Field().Cmp(spr.Field()) != 0 {
+		return errors.New("constraint systems are defined over different fields")
+	}
+	nbPublic, nbSecret := nbInputs(r1cs)
+	if nbPublicSpr, nbSecretSpr := nbInputs(spr); nbPublic != nbPublicSpr || nbSecret != nbSecretSpr {
+		return fmt.Errorf("constraint systems have different inputs: %d public, %d secret vs %d public, %d secret", nbPublic, nbSecret, nbPublicSpr, nbSecretSpr)
+	}
+	ins, err := witness.Inspect(w)
+	if err != nil {
+		return err
+	}
+	if ins.NbPublic() != nbPublic || ins.NbSecret() != nbSecret {
+		return fmt.Errorf("witness has %d public and %d secret values, expected %d and %d", ins.NbPublic(), ins.NbSecret(), nbPublic, nbSecret)
+	}
+
+	check := func(w witness.Witness, desc string) error {
+		errR1CS, errSpr := r1cs.IsSolved(w, opts...), spr.IsSolved(w, opts...)
+		if (errR1CS == nil) != (errSpr == nil) {
+			return fmt.Errorf("%w: %s solves one arithmetization only (R1CS: %v, SparseR1CS: %v)", ErrArithmetizationsDiffer, desc, errR1CS, errSpr)
+		}
+		return nil
+	}
+	if err := check(w, "witness"); err != nil {
+		return err
+	}
+
+	values := make([]*big.Int, 0, nbPublic+nbSecret)
+	ins.Iterate(func(_ int, v *big.Int) bool {
+		values = append(values, new(big.Int).Set(v))
+		return true
+	})
+	field := r1cs.Field()
+	for i := 0; i < nbPublic; i++ {
+		mutated := make([]*big.Int, len(values))
+		copy(mutated, values)
+		mutated[i] = new(big.Int).Add(values[i], big.NewInt(1))
+		mutated[i].Mod(mutated[i], field)
+		mw, err := newWitness(field, nbPublic, nbSecret, mutated)
+		if err != nil {
+			return err
+		}
+		if err := check(mw, fmt.Sprintf("witness with public value %d incremented", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArithmetizationsAgree checks with CheckArithmetizations that the R1CS and
+// the SparseR1CS of circuit agree on assignment, and that they agree with the
+// test engine, for the curves of the options; the backends of the options are
+// ignored. It catches the gadgets behaving differently per arithmetization,
+// for instance when they branch on the type of the builder.
+func (assert *Assert) ArithmetizationsAgree(circuit, assignment frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+	for _, curve := range opt.curves {
+		curve := curve
+		assert.Run(func(assert *Assert) {
+			r1cs, err := assert.compile(circuit, curve, backend.GROTH16, opt.compileOpts)
+			assert.NoError(err)
+			spr, err := assert.compile(circuit, curve, backend.PLONK, opt.compileOpts)
+			assert.NoError(err)
+			w, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err, "can't parse assignment")
+
+			assert.NoError(CheckArithmetizations(r1cs, spr, w, opt.solverOpts...))
+
+			errEngine := IsSolved(circuit, assignment, curve.ScalarField())
+			errR1CS := r1cs.IsSolved(w, opt.solverOpts...)
+			if (errEngine == nil) != (errR1CS == nil) {
+				assert.Fail("the test engine and the arithmetizations disagree", "test engine: %v, constraint systems: %v", errEngine, errR1CS)
+			}
+		}, curve.String(), "arithmetizations")
+	}
+}
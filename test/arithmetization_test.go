@@ -0,0 +1,50 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// sparseBugCircuit forgets to constrain its output with the SparseR1CS builder.
+type sparseBugCircuit cubeCircuit
+
+func (c *sparseBugCircuit) Define(api frontend.API) error {
+	y := api.Mul(c.X, c.X, c.X)
+	if !strings.HasSuffix(reflect.TypeOf(api).Elem().PkgPath(), "/scs") {
+		api.AssertIsEqual(y, c.Y)
+	}
+	return nil
+}
+
+func TestArithmetizationsAgree(t *testing.T) {
+	assert := NewAssert(t)
+	assert.ArithmetizationsAgree(&cubeCircuit{}, &cubeCircuit{X: 3, Y: 27}, WithCurves(ecc.BN254))
+	assert.ArithmetizationsAgree(&cubeCircuit{}, &cubeCircuit{X: 3, Y: 28}, WithCurves(ecc.BN254))
+}
+
+func TestCheckArithmetizations(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	ccsR1CS, err := frontend.Compile(field, r1cs.NewBuilder, &sparseBugCircuit{})
+	assert.NoError(err)
+	ccsSpr, err := frontend.Compile(field, scs.NewBuilder, &sparseBugCircuit{}, frontend.IgnoreUnconstrainedInputs())
+	assert.NoError(err)
+
+	// both accept the valid witness, the SparseR1CS accepts any output
+	w, err := frontend.NewWitness(&sparseBugCircuit{X: 3, Y: 27}, field)
+	assert.NoError(err)
+	err = CheckArithmetizations(ccsR1CS, ccsSpr, w)
+	assert.True(errors.Is(err, ErrArithmetizationsDiffer), err)
+
+	// the constraint systems must be given in order
+	assert.Error(CheckArithmetizations(ccsSpr, ccsR1CS, w))
+}
@@ -57,6 +57,10 @@ type Assert struct {
 //
 // the first call to assert.ProverSucceeded/Failed will compile the circuit for n curves, m backends
 // and subsequent calls will re-use the result of the compilation, if available.
+//
+// The keys of the Setup run by ProverSucceeded are cached for the whole test binary, per
+// constraint system (see constraint.ConstraintSystem.Digest), and the setups of the different
+// curves and backends run in parallel.
 func NewAssert(t *testing.T) *Assert {
 	return &Assert{t: t, Assertions: require.New(t), compiled: make(map[string]constraint.ConstraintSystem)}
 }
@@ -147,7 +151,7 @@ func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, validAssignment
 
 				switch b {
 				case backend.GROTH16:
-					pk, vk, err := groth16.Setup(ccs)
+					pk, vk, err := setupGroth16(ccs)
 					checkError(err)
 
 					// ensure prove / verify works well with valid witnesses
@@ -159,10 +163,7 @@ func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, validAssignment
 					checkError(err)
 
 				case backend.PLONK:
-					srs, err := NewKZGSRS(ccs)
-					checkError(err)
-
-					pk, vk, err := plonk.Setup(ccs, srs)
+					pk, vk, err := setupPlonk(ccs)
 					checkError(err)
 
 					correctProof, err := plonk.Prove(ccs, pk, validWitness, opt.proverOpts...)
@@ -172,7 +173,7 @@ func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, validAssignment
 					checkError(err)
 
 				case backend.PLONKFRI:
-					pk, vk, err := plonkfri.Setup(ccs)
+					pk, vk, err := setupPlonkFRI(ccs)
 					checkError(err)
 
 					correctProof, err := plonkfri.Prove(ccs, pk, validWitness, opt.proverOpts...)
@@ -240,6 +241,45 @@ func (assert *Assert) ProverFailed(circuit frontend.Circuit, invalidAssignment f
 	}
 }
 
+// ProverFailedWith is ProverFailed checking that the constraint systems reject
+// invalidAssignment for the expected reason: their errors must contain wantErr,
+// typically the scope of the failing constraint (see frontend.Scope), or the
+// name of the function of the gadget ("bits.toBinary") or the file and line of
+// the assertion, which are in the stack of the debug information. Otherwise a
+// circuit rejecting the assignment for another reason, say an unrelated
+// assertion or a missing hint, would pass the test. The test engine must fail
+// as well, for any reason, as it doesn't track the scopes.
+func (assert *Assert) ProverFailedWith(circuit frontend.Circuit, invalidAssignment frontend.Circuit, wantErr string, opts ...TestingOption) {
+	opt := assert.options(opts...)
+
+	for _, curve := range opt.curves {
+		invalidWitness, err := frontend.NewWitness(invalidAssignment, curve.ScalarField())
+		assert.NoError(err, "can't parse invalid assignment")
+
+		for _, b := range opt.backends {
+			curve := curve
+			b := b
+			assert.Run(func(assert *Assert) {
+				checkError := func(err error) { assert.checkError(err, b, curve, invalidWitness, lazySchema(circuit)) }
+				mustError := func(err error) { assert.mustError(err, b, curve, invalidWitness, lazySchema(circuit)) }
+
+				ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				checkError(err)
+
+				err = IsSolved(circuit, invalidAssignment, curve.ScalarField())
+				mustError(err)
+
+				assert.t.Parallel()
+				err = ccs.IsSolved(invalidWitness, opt.solverOpts...)
+				mustError(err)
+				if !strings.Contains(err.Error(), wantErr) {
+					assert.Fail("the constraint system failed for another reason", "expected an error containing %q, got: %v", wantErr, err)
+				}
+			}, curve.String(), b.String())
+		}
+	}
+}
+
 func (assert *Assert) SolvingSucceeded(circuit frontend.Circuit, validWitness frontend.Circuit, opts ...TestingOption) {
 
 	opt := assert.options(opts...)
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+type scopedCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *scopedCircuit) Define(api frontend.API) error {
+	pop := frontend.Scope(api, "range")
+	api.AssertIsLessOrEqual(c.X, 10)
+	pop()
+	pop = frontend.Scope(api, "square")
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	pop()
+	return nil
+}
+
+func TestProverFailedWith(t *testing.T) {
+	assert := NewAssert(t)
+	opts := []TestingOption{WithCurves(ecc.BN254), WithBackends(backend.GROTH16, backend.PLONK)}
+	assert.ProverFailedWith(&scopedCircuit{}, &scopedCircuit{X: 11, Y: 121}, "range", opts...)
+	assert.ProverFailedWith(&scopedCircuit{}, &scopedCircuit{X: 3, Y: 10}, "square", opts...)
+}
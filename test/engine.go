@@ -140,12 +140,17 @@ func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEng
 }
 
 func callDeferred(builder *engine) error {
-	for i, cb := range circuitdefer.GetAll[func(frontend.API) error](builder) {
-		if err := cb(builder); err != nil {
+	// deferred callbacks may themselves defer callbacks, which are run after
+	// the ones already registered
+	for i := 0; ; i++ {
+		deferred := circuitdefer.GetAll[func(frontend.API) error](builder)
+		if i >= len(deferred) {
+			return nil
+		}
+		if err := deferred[i](builder); err != nil {
 			return fmt.Errorf("defer fn %d: %w", i, err)
 		}
 	}
-	return nil
 }
 
 var cptAdd, cptMul, cptSub, cptToBinary, cptFromBinary, cptAssertIsEqual uint64
@@ -429,6 +434,34 @@ func (e *engine) Println(a ...frontend.Variable) {
 	fmt.Println(sbb.String())
 }
 
+// Log implements [frontend.Logger]. The line is printed like Println, unless
+// its condition is zero.
+func (e *engine) Log(cfg frontend.LogConfig, a ...frontend.Variable) {
+	if cfg.Condition != nil && e.toBigInt(cfg.Condition).Sign() == 0 {
+		return
+	}
+	var sbb strings.Builder
+	sbb.WriteString("(test.engine) ")
+	if cfg.Caller != "" {
+		sbb.WriteString(cfg.Caller)
+		sbb.WriteByte(' ')
+	}
+	sbb.WriteString(cfg.Level.String())
+	sbb.WriteByte(' ')
+
+	for i := 0; i < len(a); i++ {
+		e.print(&sbb, a[i])
+		sbb.WriteByte(' ')
+	}
+	for i, key := range cfg.Keys {
+		sbb.WriteString(key)
+		sbb.WriteByte('=')
+		e.print(&sbb, cfg.Values[i])
+		sbb.WriteByte(' ')
+	}
+	fmt.Println(sbb.String())
+}
+
 func (e *engine) print(sbb *strings.Builder, x interface{}) {
 	switch v := x.(type) {
 	case string:
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrNotEquivalent is returned by CheckEquivalence when a witness is accepted by
+// one constraint system and rejected by the other.
+var ErrNotEquivalent = errors.New("constraint systems are not equivalent")
+
+// CheckEquivalence checks that the constraint systems a and b, for example a
+// circuit before and after a refactoring, accept the same witnesses. It returns
+// an error wrapping ErrNotEquivalent if one of the witnesses is accepted by one
+// constraint system and rejected by the other, and an error if they don't have
+// the same field or number of public and secret inputs.
+//
+// The witnesses checked are the given ones, which should include valid ones,
+// and nbRandom random witnesses for each of them: with one of the values of the
+// witness (public or secret) replaced by an "interesting" value (see Fuzz) or
+// a random one. If no witness is given, the random witnesses are drawn from
+// scratch, which rarely satisfies either constraint system. The random witnesses
+// are derived deterministically, so that failures are reproducible.
+//
+// The check is probabilistic: equivalent constraint systems always pass, but
+// non-equivalent ones may pass as well when no checked witness separates them.
+func CheckEquivalence(a, b constraint.ConstraintSystem, nbRandom int, witnesses ...witness.Witness) error {
+	if a.Field().Cmp(b.Field()) != 0 {
+		return errors.New("constraint systems are defined over different fields")
+	}
+	nbPublicA, nbSecretA := nbInputs(a)
+	nbPublicB, nbSecretB := nbInputs(b)
+	if nbPublicA != nbPublicB || nbSecretA != nbSecretB {
+		return fmt.Errorf("constraint systems have different inputs: %d public, %d secret vs %d public, %d secret", nbPublicA, nbSecretA, nbPublicB, nbSecretB)
+	}
+
+	field := a.Field()
+	r := mrand.New(mrand.NewSource(int64(nbPublicA + nbSecretA))) //#nosec G404 weak rng is fine here
+	randomValue := func() *big.Int {
+		if i := r.Intn(len(seedCorpus) * 2); i < len(seedCorpus) {
+			return new(big.Int).Mod(seedCorpus[i], field)
+		}
+		return new(big.Int).Rand(r, field)
+	}
+
+	check := func(values []*big.Int, desc string) error {
+		w, err := newWitness(field, nbPublicA, nbSecretA, values)
+		if err != nil {
+			return err
+		}
+		errA, errB := a.IsSolved(w), b.IsSolved(w)
+		if (errA == nil) != (errB == nil) {
+			return fmt.Errorf("%w: %s %v solves one constraint system only (first: %v, second: %v)", ErrNotEquivalent, desc, values, errA, errB)
+		}
+		return nil
+	}
+
+	if len(witnesses) == 0 {
+		for i := 0; i < nbRandom; i++ {
+			values := make([]*big.Int, nbPublicA+nbSecretA)
+			for j := range values {
+				values[j] = randomValue()
+			}
+			if err := check(values, "random witness"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for k, w := range witnesses {
+		ins, err := witness.Inspect(w)
+		if err != nil {
+			return fmt.Errorf("witness %d: %w", k, err)
+		}
+		if ins.NbPublic() != nbPublicA || ins.NbSecret() != nbSecretA {
+			return fmt.Errorf("witness %d has %d public and %d secret values, expected %d and %d", k, ins.NbPublic(), ins.NbSecret(), nbPublicA, nbSecretA)
+		}
+		values := make([]*big.Int, 0, nbPublicA+nbSecretA)
+		ins.Iterate(func(_ int, v *big.Int) bool {
+			values = append(values, new(big.Int).Set(v))
+			return true
+		})
+		if err := check(values, fmt.Sprintf("witness %d", k)); err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			continue
+		}
+		for i := 0; i < nbRandom; i++ {
+			mutated := make([]*big.Int, len(values))
+			copy(mutated, values)
+			j := r.Intn(len(values))
+			mutated[j] = randomValue()
+			if err := check(mutated, fmt.Sprintf("witness %d with value %d mutated", k, j)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nbInputs returns the number of public and secret values of the witnesses of
+// ccs, which doesn't include the constant wire of R1CS.
+func nbInputs(ccs constraint.ConstraintSystem) (nbPublic, nbSecret int) {
+	nbPublic = ccs.GetNbPublicVariables()
+	if _, ok := ccs.(constraint.R1CS); ok {
+		nbPublic--
+	}
+	return nbPublic, ccs.GetNbSecretVariables()
+}
+
+func newWitness(field *big.Int, nbPublic, nbSecret int, values []*big.Int) (witness.Witness, error) {
+	w, err := witness.New(field)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan any, len(values))
+	for _, v := range values {
+		ch <- v
+	}
+	close(ch)
+	if err := w.Fill(nbPublic, nbSecret, ch); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Equivalent checks with CheckEquivalence that the circuits a and b accept the
+// same witnesses, for the curves and backends of the options. The assignments
+// should include valid ones; the checked witnesses are the assignments and 64
+// random mutations of each of them.
+func (assert *Assert) Equivalent(a, b frontend.Circuit, assignments []frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+	for _, curve := range opt.curves {
+		for _, backendID := range opt.backends {
+			curve, backendID := curve, backendID
+			assert.Run(func(assert *Assert) {
+				ccsA, err := assert.compile(a, curve, backendID, opt.compileOpts)
+				assert.NoError(err)
+				ccsB, err := assert.compile(b, curve, backendID, opt.compileOpts)
+				assert.NoError(err)
+				witnesses := make([]witness.Witness, len(assignments))
+				for i := range assignments {
+					witnesses[i], err = frontend.NewWitness(assignments[i], curve.ScalarField())
+					assert.NoError(err)
+				}
+				assert.NoError(CheckEquivalence(ccsA, ccsB, 64, witnesses...))
+			}, curve.String(), backendID.String())
+		}
+	}
+}
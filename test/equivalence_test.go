@@ -0,0 +1,67 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+type cubeRefactoredCircuit cubeCircuit
+
+func (c *cubeRefactoredCircuit) Define(api frontend.API) error {
+	x2 := api.Mul(c.X, c.X)
+	api.AssertIsEqual(api.Mul(x2, c.X), c.Y)
+	return nil
+}
+
+type squareCircuit cubeCircuit
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	// accepts the valid witnesses of cubeCircuit with X ∈ {0, 1} only
+	api.AssertIsBoolean(c.X)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestEquivalent(t *testing.T) {
+	assert := NewAssert(t)
+	opts := []TestingOption{WithCurves(ecc.BN254), WithBackends(backend.GROTH16, backend.PLONK)}
+	assert.Equivalent(&cubeCircuit{}, &cubeRefactoredCircuit{}, []frontend.Circuit{
+		&cubeCircuit{X: 3, Y: 27},
+		&cubeCircuit{X: 1, Y: 1},
+	}, opts...)
+}
+
+func TestCheckEquivalence(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	a, err := frontend.Compile(field, r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+	b, err := frontend.Compile(field, r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+
+	valid, err := frontend.NewWitness(&cubeCircuit{X: 1, Y: 1}, field)
+	assert.NoError(err)
+	assert.NoError(CheckEquivalence(a, b, 0, valid))
+
+	separating, err := frontend.NewWitness(&cubeCircuit{X: 2, Y: 8}, field)
+	assert.NoError(err)
+	err = CheckEquivalence(a, b, 0, separating)
+	assert.True(errors.Is(err, ErrNotEquivalent))
+}
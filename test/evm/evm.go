@@ -0,0 +1,90 @@
+// Package evm runs the Solidity verifiers exported by gnark in an embedded EVM
+// (the one of go-ethereum), to check them against the Go verifiers before
+// deployment. Compiling the verifiers requires the solc compiler in the PATH.
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+// ErrSolcNotFound is returned by Compile when solc isn't in the PATH.
+var ErrSolcNotFound = errors.New("solc not found in the PATH")
+
+// Compile compiles the Solidity source with solc, with the optimizer enabled,
+// and returns the creation bytecode of the contract name.
+func Compile(source []byte, name string) ([]byte, error) {
+	solc, err := exec.LookPath("solc")
+	if err != nil {
+		return nil, ErrSolcNotFound
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(solc, "--optimize", "--combined-json", "bin", "-")
+	cmd.Stdin = bytes.NewReader(source)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("solc: %w: %s", err, stderr.String())
+	}
+
+	var output struct {
+		Contracts map[string]struct {
+			Bin string `json:"bin"`
+		} `json:"contracts"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("solc output: %w", err)
+	}
+	for id, contract := range output.Contracts {
+		// contracts are identified by source:name
+		if strings.HasSuffix(id, ":"+name) {
+			return hex.DecodeString(contract.Bin)
+		}
+	}
+	return nil, fmt.Errorf("contract %s not found in the solc output", name)
+}
+
+// Contract is a contract deployed in an embedded EVM, with all the forks of
+// the main network enabled.
+type Contract struct {
+	cfg     *runtime.Config
+	address common.Address
+}
+
+// Deploy compiles the Solidity source (see Compile) and deploys the contract
+// name in a new embedded EVM.
+func Deploy(source []byte, name string) (*Contract, error) {
+	bin, err := Compile(source, name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &runtime.Config{}
+	_, address, _, err := runtime.Create(bin, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("deploy %s: %w", name, err)
+	}
+	return &Contract{cfg: cfg, address: address}, nil
+}
+
+// Call calls the contract with calldata, and returns the output of the call
+// and the gas it used, excluding the intrinsic gas of the transaction. The
+// error is vm.ErrExecutionReverted if the call reverted.
+func (c *Contract) Call(calldata []byte) (output []byte, gasUsed uint64, err error) {
+	output, leftOverGas, err := runtime.Call(c.address, calldata, c.cfg)
+	return output, c.cfg.GasLimit - leftOverGas, err
+}
+
+// Accepts calls a function of the contract returning a bool with calldata,
+// and reports whether the call succeeded and returned true: reverts, and other
+// execution errors, are rejections.
+func (c *Contract) Accepts(calldata []byte) bool {
+	output, _, err := c.Call(calldata)
+	return err == nil && len(output) == 32 && output[31] == 1
+}
@@ -0,0 +1,122 @@
+package evm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// AssertGroth16Verifier asserts that the Solidity verifier exported with
+// vk.ExportSolidity and the options opts accepts exactly the calldata accepted
+// by groth16.Verify: the calldata of proof and publicWitness (see
+// groth16.EncodeCalldata), and variants of it with one word tampered with. It
+// skips the test if solc isn't in the PATH.
+//
+// The verifiers exported as a library, or hashing their public inputs, have a
+// different calldata and aren't supported.
+func AssertGroth16Verifier(t testing.TB, vk groth16.VerifyingKey, proof groth16.Proof, publicWitness witness.Witness, opts ...solidity.ExportOption) {
+	t.Helper()
+	calldata, err := groth16.EncodeCalldata(proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := func(calldata []byte) bool {
+		proof, publicWitness, err := groth16.DecodeCalldata(vk.CurveID(), calldata)
+		return err == nil && groth16.Verify(proof, vk, publicWitness) == nil
+	}
+	assertVerifier(t, vk.ExportSolidity, "Verifier", calldata, vk.CurveID().ScalarField(), verify, opts)
+}
+
+// AssertPlonkVerifier is the PLONK version of AssertGroth16Verifier, checking
+// the Solidity verifier exported with vk.ExportSolidity against plonk.Verify.
+func AssertPlonkVerifier(t testing.TB, vk plonk.VerifyingKey, proof plonk.Proof, publicWitness witness.Witness, opts ...solidity.ExportOption) {
+	t.Helper()
+	calldata, err := plonk.EncodeCalldata(proof, publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins, err := witness.Inspect(publicWitness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve := utils.FieldToCurve(ins.Field())
+	verify := func(calldata []byte) bool {
+		proof, publicWitness, err := plonk.DecodeCalldata(curve, calldata)
+		return err == nil && plonk.Verify(proof, vk, publicWitness) == nil
+	}
+	assertVerifier(t, vk.ExportSolidity, "KeyedPlonkVerifier", calldata, ins.Field(), verify, opts)
+}
+
+// assertVerifier deploys the verifier written by export, and checks that it
+// accepts the variants of calldata (see calldataVariants) accepted by verify.
+func assertVerifier(t testing.TB, export func(io.Writer, ...solidity.ExportOption) error, defaultName string, calldata []byte, field *big.Int, verify func([]byte) bool, opts []solidity.ExportOption) {
+	t.Helper()
+	cfg, err := solidity.NewExportConfig(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Library || cfg.PublicInputsHasher != nil {
+		t.Fatal("the verifiers exported as a library or hashing their public inputs aren't supported")
+	}
+	name := cfg.ContractName
+	if name == "" {
+		name = defaultName
+	}
+
+	var source bytes.Buffer
+	if err := export(&source, opts...); err != nil {
+		t.Fatal(err)
+	}
+	contract, err := Deploy(source.Bytes(), name)
+	if errors.Is(err, ErrSolcNotFound) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range calldataVariants(calldata, field) {
+		expected, actual := verify(v.calldata), contract.Accepts(v.calldata)
+		if expected != actual {
+			t.Errorf("%s: accepted by the Go verifier: %t, by the Solidity verifier: %t", v.name, expected, actual)
+		}
+	}
+}
+
+type calldataVariant struct {
+	name     string
+	calldata []byte
+}
+
+// calldataVariants returns calldata, and for each of its 32 bytes words after
+// the function selector, the calldata with the last bit of the word flipped
+// and, if it fits, with the field modulus added to the word (a non-canonical
+// encoding of a field element the verifiers must reject).
+func calldataVariants(calldata []byte, field *big.Int) []calldataVariant {
+	const wordSize = 32
+	variants := []calldataVariant{{name: "calldata", calldata: calldata}}
+	for i := 4; i+wordSize <= len(calldata); i += wordSize {
+		word := (i - 4) / wordSize
+
+		flipped := append([]byte{}, calldata...)
+		flipped[i+wordSize-1] ^= 1
+		variants = append(variants, calldataVariant{fmt.Sprintf("calldata with the last bit of word %d flipped", word), flipped})
+
+		v := new(big.Int).SetBytes(calldata[i : i+wordSize])
+		if v.Add(v, field).BitLen() <= 8*wordSize {
+			shifted := append([]byte{}, calldata...)
+			v.FillBytes(shifted[i : i+wordSize])
+			variants = append(variants, calldataVariant{fmt.Sprintf("calldata with the field modulus added to word %d", word), shifted})
+		}
+	}
+	return variants
+}
@@ -0,0 +1,99 @@
+package evm_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/test/evm"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X    frontend.Variable
+	Y, Z frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsDifferent(c.X, c.Z)
+	return nil
+}
+
+func TestGroth16Verifier(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	evm.AssertGroth16Verifier(t, vk, proof, public)
+	evm.AssertGroth16Verifier(t, vk, proof, public, solidity.WithContractName("MyVerifier"), solidity.WithCustomErrors())
+}
+
+func TestPlonkVerifier(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	evm.AssertPlonkVerifier(t, vk, proof, public)
+}
+
+func TestEstimateGas(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&circuit{X: 3, Y: 9, Z: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	var source bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&source))
+	contract, err := evm.Deploy(source.Bytes(), "Verifier")
+	if errors.Is(err, evm.ErrSolcNotFound) {
+		t.Skip(err)
+	}
+	assert.NoError(err)
+	calldata, err := groth16.EncodeCalldata(proof, public)
+	assert.NoError(err)
+	_, gasUsed, err := contract.Call(calldata)
+	assert.NoError(err)
+
+	// the estimate leaves out the interpreted code
+	estimate, err := vk.EstimateGas()
+	assert.NoError(err)
+	assert.Greater(gasUsed, estimate.ECAdd+estimate.ECMul+estimate.Pairing)
+	assert.LessOrEqual(solidity.CalldataGas(calldata), estimate.Calldata)
+}
@@ -0,0 +1,268 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// files of the fixtures of a curve and a backend, in the directory
+// <dir>/<curve>/<backend>
+const (
+	fixtureCCS           = "ccs.bin"
+	fixtureProvingKey    = "pk.bin"
+	fixtureVerifyingKey  = "vk.bin"
+	fixtureProof         = "proof.bin"
+	fixtureWitness       = "witness.bin"
+	fixturePublicWitness = "public_witness.bin"
+	fixtureSRS           = "srs.bin" // PLONK only, the keys are serialized without it
+)
+
+// Fixtures are the artifacts of a circuit for a curve and a backend, written
+// by Assert.WriteFixtures and read by LoadFixtures, so that the tests of the
+// services using the circuit don't have to compile it and run the setup.
+type Fixtures struct {
+	Curve   ecc.ID
+	Backend backend.ID
+
+	CCS constraint.ConstraintSystem
+
+	// ProvingKey, VerifyingKey and Proof are the groth16 or plonk objects,
+	// depending on Backend.
+	ProvingKey   io.WriterTo
+	VerifyingKey io.WriterTo
+	Proof        io.WriterTo
+
+	// Witness is the valid witness of Proof, PublicWitness its public part.
+	Witness       witness.Witness
+	PublicWitness witness.Witness
+}
+
+// Verify verifies f.Proof with f.VerifyingKey and f.PublicWitness.
+func (f *Fixtures) Verify() error {
+	switch f.Backend {
+	case backend.GROTH16:
+		return groth16.Verify(f.Proof.(groth16.Proof), f.VerifyingKey.(groth16.VerifyingKey), f.PublicWitness)
+	case backend.PLONK:
+		return plonk.Verify(f.Proof.(plonk.Proof), f.VerifyingKey.(plonk.VerifyingKey), f.PublicWitness)
+	default:
+		return fmt.Errorf("fixtures of backend %s are not supported", f.Backend)
+	}
+}
+
+// WriteFixtures compiles circuit, runs the setup and proves validAssignment
+// for the curves and the backends of the options, and writes the artifacts to
+// the directory <dir>/<curve>/<backend> (see Fixtures). PLONKFRI, whose
+// objects aren't serializable, is skipped.
+func (assert *Assert) WriteFixtures(dir string, circuit, validAssignment frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+	for _, curve := range opt.curves {
+		for _, b := range opt.backends {
+			if b == backend.PLONKFRI {
+				continue
+			}
+			curve, b := curve, b
+			assert.Run(func(assert *Assert) {
+				ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				assert.NoError(err)
+				w, err := frontend.NewWitness(validAssignment, curve.ScalarField())
+				assert.NoError(err, "can't parse valid assignment")
+				f, srs, err := newFixtures(ccs, w, opt.proverOpts...)
+				assert.NoError(err)
+				assert.NoError(f.Verify())
+				assert.NoError(f.write(filepath.Join(dir, curve.String(), b.String()), srs))
+			}, curve.String(), b.String(), "fixtures")
+		}
+	}
+}
+
+// newFixtures runs the setup of the backend of ccs and proves w. It returns
+// the KZG SRS of the keys for PLONK.
+func newFixtures(ccs constraint.ConstraintSystem, w witness.Witness, opts ...backend.ProverOption) (*Fixtures, kzg.SRS, error) {
+	publicWitness, err := w.Public()
+	if err != nil {
+		return nil, nil, err
+	}
+	f := &Fixtures{CCS: ccs, Witness: w, PublicWitness: publicWitness}
+	var srs kzg.SRS
+	switch ccs.(type) {
+	case constraint.R1CS:
+		f.Backend = backend.GROTH16
+		pk, vk, err := groth16.Setup(ccs)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.ProvingKey, f.VerifyingKey = pk, vk
+		if f.Proof, err = groth16.Prove(ccs, pk, w, opts...); err != nil {
+			return nil, nil, err
+		}
+	case constraint.SparseR1CS:
+		f.Backend = backend.PLONK
+		if srs, err = NewKZGSRS(ccs); err != nil {
+			return nil, nil, err
+		}
+		pk, vk, err := plonk.Setup(ccs, srs)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.ProvingKey, f.VerifyingKey = pk, vk
+		if f.Proof, err = plonk.Prove(ccs, pk, w, opts...); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported constraint system %T", ccs)
+	}
+	f.Curve = utils.FieldToCurve(ccs.Field())
+	return f, srs, nil
+}
+
+func (f *Fixtures) write(dir string, srs kzg.SRS) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	files := map[string]io.WriterTo{
+		fixtureCCS:           f.CCS,
+		fixtureProvingKey:    f.ProvingKey,
+		fixtureVerifyingKey:  f.VerifyingKey,
+		fixtureProof:         f.Proof,
+		fixtureWitness:       f.Witness,
+		fixturePublicWitness: f.PublicWitness,
+	}
+	if srs != nil {
+		files[fixtureSRS] = srs
+	}
+	for name, v := range files {
+		var buf bytes.Buffer
+		if _, err := v.WriteTo(&buf); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFixtures reads the fixtures written by Assert.WriteFixtures in dir, for
+// all the curves and backends found, ordered by curve then backend.
+func LoadFixtures(dir string) ([]*Fixtures, error) {
+	var res []*Fixtures
+	for _, curve := range gnark.Curves() {
+		for _, b := range []backend.ID{backend.GROTH16, backend.PLONK} {
+			path := filepath.Join(dir, curve.String(), b.String())
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			f, err := loadFixtures(path, curve, b)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", curve, b, err)
+			}
+			res = append(res, f)
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s", dir)
+	}
+	return res, nil
+}
+
+func loadFixtures(dir string, curve ecc.ID, b backend.ID) (*Fixtures, error) {
+	f := &Fixtures{Curve: curve, Backend: b}
+	var err error
+	if f.Witness, err = witness.New(curve.ScalarField()); err != nil {
+		return nil, err
+	}
+	if f.PublicWitness, err = witness.New(curve.ScalarField()); err != nil {
+		return nil, err
+	}
+	files := map[string]io.ReaderFrom{
+		fixtureWitness:       f.Witness,
+		fixturePublicWitness: f.PublicWitness,
+	}
+	switch b {
+	case backend.GROTH16:
+		if f.CCS, err = groth16.NewCSE(curve); err != nil {
+			return nil, err
+		}
+		pk, err := groth16.NewProvingKeyE(curve)
+		if err != nil {
+			return nil, err
+		}
+		vk, err := groth16.NewVerifyingKeyE(curve)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := groth16.NewProofE(curve)
+		if err != nil {
+			return nil, err
+		}
+		f.ProvingKey, f.VerifyingKey, f.Proof = pk, vk, proof
+		files[fixtureProvingKey], files[fixtureVerifyingKey], files[fixtureProof] = pk, vk, proof
+	case backend.PLONK:
+		if f.CCS, err = plonk.NewCSE(curve); err != nil {
+			return nil, err
+		}
+		pk, err := plonk.NewProvingKeyE(curve)
+		if err != nil {
+			return nil, err
+		}
+		vk, err := plonk.NewVerifyingKeyE(curve)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := plonk.NewProofE(curve)
+		if err != nil {
+			return nil, err
+		}
+		f.ProvingKey, f.VerifyingKey, f.Proof = pk, vk, proof
+		files[fixtureProvingKey], files[fixtureVerifyingKey], files[fixtureProof] = pk, vk, proof
+	default:
+		return nil, fmt.Errorf("fixtures of backend %s are not supported", b)
+	}
+	files[fixtureCCS] = f.CCS
+
+	for name, v := range files {
+		if err := readFixture(filepath.Join(dir, name), v); err != nil {
+			return nil, err
+		}
+	}
+
+	if b == backend.PLONK {
+		srs := kzg.NewSRS(curve)
+		if err := readFixture(filepath.Join(dir, fixtureSRS), srs); err != nil {
+			return nil, err
+		}
+		if err := f.ProvingKey.(plonk.ProvingKey).InitKZG(srs); err != nil {
+			return nil, err
+		}
+		if err := f.VerifyingKey.(plonk.VerifyingKey).InitKZG(srs); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func readFixture(path string, v io.ReaderFrom) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := v.ReadFrom(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
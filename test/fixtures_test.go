@@ -0,0 +1,33 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func TestFixtures(t *testing.T) {
+	assert := NewAssert(t)
+	dir := t.TempDir()
+
+	_, err := LoadFixtures(dir)
+	assert.Error(err)
+
+	assert.WriteFixtures(dir, &cubeCircuit{}, &cubeCircuit{X: 3, Y: 27}, WithCurves(ecc.BN254))
+
+	fixtures, err := LoadFixtures(dir)
+	assert.NoError(err)
+	assert.Len(fixtures, 2)
+	for i, b := range []backend.ID{backend.GROTH16, backend.PLONK} {
+		f := fixtures[i]
+		assert.Equal(ecc.BN254, f.Curve)
+		assert.Equal(b, f.Backend)
+		assert.NoError(f.Verify())
+		assert.NoError(f.CCS.IsSolved(f.Witness))
+		public, err := witness.Inspect(f.PublicWitness)
+		assert.NoError(err)
+		assert.Equal(1, public.NbPublic())
+	}
+}
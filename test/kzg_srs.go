@@ -17,63 +17,215 @@ limitations under the License.
 package test
 
 import (
+	"bufio"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/internal/utils"
 
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
 	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
 	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
 	kzg_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/kzg"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
 	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
 )
 
+// srsCachedSize is the minimum size of the cached srs: the srs of all the circuits
+// smaller than it are derived from a single srs. Larger circuits grow the cache.
 const srsCachedSize = (1 << 14) + 3
 
-// NewKZGSRS uses ccs nb variables and nb constraints to initialize a kzg srs
-// for sizes < 2¹⁵, returns a pre-computed cached SRS
+// NewKZGSRS uses ccs nb variables and nb constraints to initialize a kzg srs.
+//
+// The srs are derived from a srs cached per curve (see KZGSRSSubset), of size at
+// least 2¹⁴+3, which is only regenerated when a larger srs is needed.
 //
 // /!\ warning /!\: this method is here for convenience only: in production, a SRS generated through MPC should be used.
 func NewKZGSRS(ccs constraint.ConstraintSystem) (kzg.SRS, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	return getCachedSRS(utils.FieldToCurve(ccs.Field()), kzgSize(ccs))
+}
 
-	nbConstraints := ccs.GetNbConstraints()
-	sizeSystem := nbConstraints + ccs.GetNbPublicVariables()
-	kzgSize := ecc.NextPowerOfTwo(uint64(sizeSystem)) + 3
+// NewKZGSRSPair returns the kzg srs of ccs (see NewKZGSRS), and the srs in Lagrange
+// basis of the size of ccs derived from it, to be given to plonk.Setup with
+// backend.WithLagrangeSRS. The Lagrange srs derived from the cached srs are cached
+// per size.
+//
+// /!\ warning /!\: this method is here for convenience only: in production, a SRS generated through MPC should be used.
+func NewKZGSRSPair(ccs constraint.ConstraintSystem) (canonical, lagrange kzg.SRS, err error) {
+	lock.Lock()
+	defer lock.Unlock()
 
-	if kzgSize <= srsCachedSize {
-		return getCachedSRS(ccs)
+	curveID := utils.FieldToCurve(ccs.Field())
+	if canonical, err = getCachedSRS(curveID, kzgSize(ccs)); err != nil {
+		return nil, nil, err
+	}
+
+	size, err := plonk.DomainSizeE(ccs)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := lagrangeKey{curve: curveID, size: size}
+	if lagrange, ok := lagrangeCache[key]; ok {
+		return canonical, lagrange, nil
 	}
+	if lagrange, err = plonk.NewLagrangeSRS(ccs, canonical); err != nil {
+		return nil, nil, err
+	}
+	lagrangeCache[key] = lagrange
+	return canonical, lagrange, nil
+}
 
-	return newKZGSRS(utils.FieldToCurve(ccs.Field()), kzgSize)
+// KZGSRSSubset returns the srs made of the first size points of srs, which is a
+// srs for polynomials of degree less than size. The points are shared with srs.
+func KZGSRSSubset(srs kzg.SRS, size uint64) (kzg.SRS, error) {
+	if n := srsSize(srs); n < size {
+		return nil, fmt.Errorf("srs of size %d, expected at least %d", n, size)
+	}
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		return &kzg_bn254.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls12381.SRS:
+		return &kzg_bls12381.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls12377.SRS:
+		return &kzg_bls12377.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls24317.SRS:
+		return &kzg_bls24317.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	case *kzg_bls24315.SRS:
+		return &kzg_bls24315.SRS{G1: s.G1[:size], G2: s.G2}, nil
+	default:
+		panic("unrecognized srs type")
+	}
+}
+
+// ExportKZGSRSCache writes the cached srs to dir, one file per curve, for
+// ImportKZGSRSCache to load them in another run. The points are written
+// uncompressed, to be read back quickly.
+//
+// Test suites can then share the srs across runs from TestMain:
+//
+//	test.ImportKZGSRSCache(dir)
+//	code := m.Run()
+//	test.ExportKZGSRSCache(dir)
+func ExportKZGSRSCache(dir string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for curveID, srs := range srsCache {
+		if err := writeFileAtomic(srsCacheFile(dir, curveID), func(w io.Writer) error {
+			bw := bufio.NewWriter(w)
+			if err := writeRawSRS(bw, srs); err != nil {
+				return err
+			}
+			return bw.Flush()
+		}); err != nil {
+			return fmt.Errorf("export %s srs: %w", curveID, err)
+		}
+	}
+	return nil
+}
+
+// ImportKZGSRSCache loads the srs written to dir by ExportKZGSRSCache in the
+// cache, unless the cache already holds a larger srs for the curve. The files
+// missing in dir are ignored.
+//
+// The points are not checked: dir must only be written by ExportKZGSRSCache.
+func ImportKZGSRSCache(dir string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BLS24_317, ecc.BLS24_315} {
+		f, err := os.Open(srsCacheFile(dir, curveID))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		srs := kzg.NewSRS(curveID)
+		err = readRawSRS(bufio.NewReader(f), srs)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("import %s srs: %w", curveID, err)
+		}
+		if cached, ok := srsCache[curveID]; !ok || srsSize(cached) < srsSize(srs) {
+			setCachedSRS(curveID, srs)
+		}
+	}
+	return nil
+}
+
+func kzgSize(ccs constraint.ConstraintSystem) uint64 {
+	nbConstraints := ccs.GetNbConstraints()
+	sizeSystem := nbConstraints + ccs.GetNbPublicVariables()
+	return ecc.NextPowerOfTwo(uint64(sizeSystem)) + 3
+}
 
+type lagrangeKey struct {
+	curve ecc.ID
+	size  uint64
 }
 
 var srsCache map[ecc.ID]kzg.SRS
+var lagrangeCache map[lagrangeKey]kzg.SRS
 var lock sync.Mutex
 
 func init() {
 	srsCache = make(map[ecc.ID]kzg.SRS)
+	lagrangeCache = make(map[lagrangeKey]kzg.SRS)
 }
-func getCachedSRS(ccs constraint.ConstraintSystem) (kzg.SRS, error) {
-	lock.Lock()
-	defer lock.Unlock()
 
-	curveID := utils.FieldToCurve(ccs.Field())
+// getCachedSRS returns a srs of the given size, or of srsCachedSize if larger,
+// derived from the cached srs of the curve, growing the cache if needed. The
+// caller must hold lock.
+func getCachedSRS(curveID ecc.ID, size uint64) (kzg.SRS, error) {
+	// kzgSize doesn't account for the placeholders of the constraint system, the
+	// srs of the small circuits are all of the minimum size
+	size = max(size, srsCachedSize)
+	srs, ok := srsCache[curveID]
+	if !ok || srsSize(srs) < size {
+		var err error
+		if srs, err = newKZGSRS(curveID, size); err != nil {
+			return nil, err
+		}
+		setCachedSRS(curveID, srs)
+	}
+	return KZGSRSSubset(srs, size)
+}
 
-	if srs, ok := srsCache[curveID]; ok {
-		return srs, nil
+// setCachedSRS replaces the cached srs of the curve, and drops the Lagrange srs
+// derived from the previous one. The caller must hold lock.
+func setCachedSRS(curveID ecc.ID, srs kzg.SRS) {
+	srsCache[curveID] = srs
+	for key := range lagrangeCache {
+		if key.curve == curveID {
+			delete(lagrangeCache, key)
+		}
 	}
+}
 
-	srs, err := newKZGSRS(curveID, srsCachedSize)
-	if err != nil {
-		return nil, err
+func max(a, b uint64) uint64 {
+	if a > b {
+		return a
 	}
-	srsCache[curveID] = srs
-	return srs, nil
+	return b
 }
 
 func newKZGSRS(curve ecc.ID, kzgSize uint64) (kzg.SRS, error) {
@@ -98,3 +250,106 @@ func newKZGSRS(curve ecc.ID, kzgSize uint64) (kzg.SRS, error) {
 		panic("unrecognized R1CS curve type")
 	}
 }
+
+// srsSize returns the number of G1 points of srs.
+func srsSize(srs kzg.SRS) uint64 {
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		return uint64(len(s.G1))
+	case *kzg_bls12381.SRS:
+		return uint64(len(s.G1))
+	case *kzg_bls12377.SRS:
+		return uint64(len(s.G1))
+	case *kzg_bls24317.SRS:
+		return uint64(len(s.G1))
+	case *kzg_bls24315.SRS:
+		return uint64(len(s.G1))
+	default:
+		panic("unrecognized srs type")
+	}
+}
+
+type encoder interface {
+	Encode(v interface{}) error
+}
+
+type decoder interface {
+	Decode(v interface{}) error
+}
+
+// writeRawSRS writes srs as its WriteTo method, with uncompressed points.
+func writeRawSRS(w io.Writer, srs kzg.SRS) error {
+	var enc encoder
+	var toEncode []interface{}
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		enc, toEncode = bn254.NewEncoder(w, bn254.RawEncoding()), []interface{}{&s.G2[0], &s.G2[1], s.G1}
+	case *kzg_bls12381.SRS:
+		enc, toEncode = bls12381.NewEncoder(w, bls12381.RawEncoding()), []interface{}{&s.G2[0], &s.G2[1], s.G1}
+	case *kzg_bls12377.SRS:
+		enc, toEncode = bls12377.NewEncoder(w, bls12377.RawEncoding()), []interface{}{&s.G2[0], &s.G2[1], s.G1}
+	case *kzg_bls24317.SRS:
+		enc, toEncode = bls24317.NewEncoder(w, bls24317.RawEncoding()), []interface{}{&s.G2[0], &s.G2[1], s.G1}
+	case *kzg_bls24315.SRS:
+		enc, toEncode = bls24315.NewEncoder(w, bls24315.RawEncoding()), []interface{}{&s.G2[0], &s.G2[1], s.G1}
+	default:
+		panic("unrecognized srs type")
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRawSRS reads srs written by writeRawSRS, without checking the points.
+func readRawSRS(r io.Reader, srs kzg.SRS) error {
+	var dec decoder
+	var toDecode []interface{}
+	switch s := srs.(type) {
+	case *kzg_bn254.SRS:
+		dec, toDecode = bn254.NewDecoder(r, bn254.NoSubgroupChecks()), []interface{}{&s.G2[0], &s.G2[1], &s.G1}
+	case *kzg_bls12381.SRS:
+		dec, toDecode = bls12381.NewDecoder(r, bls12381.NoSubgroupChecks()), []interface{}{&s.G2[0], &s.G2[1], &s.G1}
+	case *kzg_bls12377.SRS:
+		dec, toDecode = bls12377.NewDecoder(r, bls12377.NoSubgroupChecks()), []interface{}{&s.G2[0], &s.G2[1], &s.G1}
+	case *kzg_bls24317.SRS:
+		dec, toDecode = bls24317.NewDecoder(r, bls24317.NoSubgroupChecks()), []interface{}{&s.G2[0], &s.G2[1], &s.G1}
+	case *kzg_bls24315.SRS:
+		dec, toDecode = bls24315.NewDecoder(r, bls24315.NoSubgroupChecks()), []interface{}{&s.G2[0], &s.G2[1], &s.G1}
+	default:
+		panic("unrecognized srs type")
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return err
+		}
+	}
+	if srsSize(srs) == 0 {
+		return errors.New("empty srs")
+	}
+	return nil
+}
+
+func srsCacheFile(dir string, curveID ecc.ID) string {
+	return filepath.Join(dir, "kzg_srs_"+curveID.String()+".bin")
+}
+
+// writeFileAtomic writes the file name with write, through a temporary file
+// renamed once complete, so that concurrent runs never read a partial file.
+func writeFileAtomic(name string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKZGSRSSubset(t *testing.T) {
+	assert := require.New(t)
+
+	lock.Lock()
+	srs, err := getCachedSRS(ecc.BN254, 1<<15+3)
+	lock.Unlock()
+	assert.NoError(err)
+	full := srs.(*kzg_bn254.SRS)
+	assert.Len(full.G1, 1<<15+3)
+
+	// smaller sizes are derived from the cached srs
+	lock.Lock()
+	small, err := getCachedSRS(ecc.BN254, 1<<4+3)
+	lock.Unlock()
+	assert.NoError(err)
+	assert.Len(small.(*kzg_bn254.SRS).G1, srsCachedSize)
+	assert.Equal(full.G1[:srsCachedSize], small.(*kzg_bn254.SRS).G1)
+	assert.Equal(full.G2, small.(*kzg_bn254.SRS).G2)
+
+	tiny, err := KZGSRSSubset(small, 1<<4+3)
+	assert.NoError(err)
+	assert.Equal(full.G1[:1<<4+3], tiny.(*kzg_bn254.SRS).G1)
+	_, err = KZGSRSSubset(tiny, 1<<5)
+	assert.Error(err)
+}
+
+func TestKZGSRSCacheExport(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+
+	lock.Lock()
+	srs, err := getCachedSRS(ecc.BN254, srsCachedSize)
+	lock.Unlock()
+	assert.NoError(err)
+	assert.NoError(ExportKZGSRSCache(dir))
+
+	// a fresh cache is loaded from the files
+	lock.Lock()
+	saved := srsCache
+	srsCache = make(map[ecc.ID]kzg.SRS)
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		srsCache = saved
+		lock.Unlock()
+	}()
+	assert.NoError(ImportKZGSRSCache(dir))
+	lock.Lock()
+	imported, err := getCachedSRS(ecc.BN254, srsCachedSize)
+	lock.Unlock()
+	assert.NoError(err)
+	assert.Equal(srs, imported)
+
+	// missing files are ignored
+	assert.NoError(ImportKZGSRSCache(t.TempDir()))
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	mrand "math/rand"
+	"reflect"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// mutation is a witness derived from a valid one by changing a value or two.
+type mutation struct {
+	desc   string
+	values []*big.Int
+}
+
+// mutations returns the mutations of the values of a valid witness, named
+// after names: for each value, the value with its lowest bit and a random bit
+// flipped, the value plus and minus one, and the value swapped with the next
+// one and a random one. The mutations leaving the witness unchanged are
+// omitted. r makes the random choices.
+func mutations(values []*big.Int, names []string, field *big.Int, r *mrand.Rand) []mutation {
+	var res []mutation
+	add := func(desc string, i int, v *big.Int) {
+		v.Mod(v, field)
+		if v.Cmp(values[i]) == 0 {
+			return
+		}
+		mutated := make([]*big.Int, len(values))
+		copy(mutated, values)
+		mutated[i] = v
+		res = append(res, mutation{desc: desc, values: mutated})
+	}
+	swap := func(i, j int) {
+		if values[i].Cmp(values[j]) == 0 {
+			return
+		}
+		mutated := make([]*big.Int, len(values))
+		copy(mutated, values)
+		mutated[i], mutated[j] = values[j], values[i]
+		res = append(res, mutation{desc: fmt.Sprintf("%s and %s swapped", names[i], names[j]), values: mutated})
+	}
+
+	for i, v := range values {
+		bit := r.Intn(field.BitLen())
+		add(fmt.Sprintf("%s with bit 0 flipped", names[i]), i, new(big.Int).Xor(v, big.NewInt(1)))
+		add(fmt.Sprintf("%s with bit %d flipped", names[i], bit), i, new(big.Int).Xor(v, new(big.Int).Lsh(big.NewInt(1), uint(bit))))
+		add(fmt.Sprintf("%s + 1", names[i]), i, new(big.Int).Add(v, big.NewInt(1)))
+		add(fmt.Sprintf("%s - 1", names[i]), i, new(big.Int).Sub(v, big.NewInt(1)))
+		if i+1 < len(values) {
+			swap(i, i+1)
+		}
+		if len(values) > 2 {
+			swap(i, r.Intn(len(values)))
+		}
+	}
+	return res
+}
+
+// witnessNames returns the full names of the leaves of assignment in the order
+// of the witness: public ones first, then secret ones.
+func witnessNames(assignment frontend.Circuit) ([]string, error) {
+	var public, secret []string
+	_, err := schema.Walk(assignment, tVariable, func(f schema.LeafInfo, _ reflect.Value) error {
+		switch f.Visibility {
+		case schema.Public:
+			public = append(public, f.FullName())
+		case schema.Secret:
+			secret = append(secret, f.FullName())
+		}
+		return nil
+	})
+	return append(public, secret...), err
+}
+
+// FuzzMutations is a soundness smoke test of circuit: it mutates the values of
+// validAssignment one by one (see below) and checks that the constraint
+// systems of the curves and backends of the options reject each mutation, so
+// that no proof can be generated for it. A mutation accepted reveals an
+// under-constrained value, or a value which the circuit legitimately ignores.
+//
+// The mutations of a value are: its lowest bit and a random bit flipped, plus
+// and minus one, and the value swapped with the next one and a random one;
+// those leaving the witness unchanged (swapping equal values, say) are skipped.
+// The random choices are deterministic, so that failures are reproducible.
+func (assert *Assert) FuzzMutations(circuit, validAssignment frontend.Circuit, opts ...TestingOption) {
+	opt := assert.options(opts...)
+	names, err := witnessNames(validAssignment)
+	assert.NoError(err)
+
+	for _, curve := range opt.curves {
+		for _, b := range opt.backends {
+			curve, b := curve, b
+			assert.Run(func(assert *Assert) {
+				ccs, err := assert.compile(circuit, curve, b, opt.compileOpts)
+				assert.NoError(err)
+				w, err := frontend.NewWitness(validAssignment, curve.ScalarField())
+				assert.NoError(err, "can't parse valid assignment")
+				assert.NoError(ccs.IsSolved(w, opt.solverOpts...), "the valid assignment doesn't solve the constraint system")
+
+				ins, err := witness.Inspect(w)
+				assert.NoError(err)
+				values := make([]*big.Int, 0, len(names))
+				ins.Iterate(func(_ int, v *big.Int) bool {
+					values = append(values, new(big.Int).Set(v))
+					return true
+				})
+				assert.Len(values, len(names))
+				nbPublic, nbSecret := nbInputs(ccs)
+
+				r := mrand.New(mrand.NewSource(int64(len(values)))) //#nosec G404 weak rng is fine here
+				for _, m := range mutations(values, names, curve.ScalarField(), r) {
+					mw, err := newWitness(curve.ScalarField(), nbPublic, nbSecret, m.values)
+					assert.NoError(err)
+					if ccs.IsSolved(mw, opt.solverOpts...) == nil {
+						assert.Fail("mutated witness solves the constraint system", "%s is accepted", m.desc)
+					}
+				}
+			}, curve.String(), b.String(), "mutations")
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutations(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	values := []*big.Int{big.NewInt(2), big.NewInt(2), big.NewInt(0)}
+	ms := mutations(values, []string{"A", "B", "C"}, field, mrand.New(mrand.NewSource(0)))
+	descs := make(map[string]bool)
+	for _, m := range ms {
+		descs[m.desc] = true
+		assert.Len(m.values, len(values))
+		assert.NotEqual(values, m.values, m.desc)
+	}
+	assert.True(descs["A with bit 0 flipped"])
+	assert.True(descs["C - 1"])
+	assert.True(descs["B and C swapped"])
+	// equal values aren't swapped
+	assert.False(descs["A and B swapped"])
+
+	// the original values are left untouched
+	assert.Equal([]*big.Int{big.NewInt(2), big.NewInt(2), big.NewInt(0)}, values)
+
+	names, err := witnessNames(&cubeCircuit{X: 3, Y: 27})
+	assert.NoError(err)
+	assert.Equal([]string{"Y", "X"}, names)
+}
+
+func TestFuzzMutations(t *testing.T) {
+	assert := NewAssert(t)
+	assert.FuzzMutations(&cubeCircuit{}, &cubeCircuit{X: 3, Y: 27}, WithCurves(ecc.BN254), WithBackends(backend.GROTH16, backend.PLONK))
+	assert.FuzzMutations(&scopedCircuit{}, &scopedCircuit{X: 3, Y: 9}, WithCurves(ecc.BN254), WithBackends(backend.GROTH16))
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/plonkfri"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// setupCache holds the keys of the constraint systems set up by the tests of
+// the test binary, see cachedSetup.
+var setupCache = struct {
+	sync.Mutex
+	entries map[setupKey]*setupEntry
+}{entries: make(map[setupKey]*setupEntry)}
+
+type setupKey struct {
+	backend backend.ID
+	curve   ecc.ID
+	digest  constraint.Digest
+}
+
+type setupEntry struct {
+	once   sync.Once
+	pk, vk interface{}
+	err    error
+}
+
+// cachedSetup returns the keys returned by setup for ccs and the backend b, and
+// runs it once per constraint system (identified by its digest) in the test
+// binary, so that the calls to ProverSucceeded compiling the same circuit
+// share the keys. The concurrent calls for a constraint system wait for the
+// first one, while the ones for other constraint systems, typically of other
+// curves in parallel subtests, run in parallel.
+func cachedSetup[PK, VK any](ccs constraint.ConstraintSystem, b backend.ID, setup func() (PK, VK, error)) (pk PK, vk VK, err error) {
+	key := setupKey{backend: b, curve: utils.FieldToCurve(ccs.Field()), digest: ccs.Digest()}
+	setupCache.Lock()
+	e, ok := setupCache.entries[key]
+	if !ok {
+		e = new(setupEntry)
+		setupCache.entries[key] = e
+	}
+	setupCache.Unlock()
+
+	e.once.Do(func() {
+		e.pk, e.vk, e.err = setup()
+	})
+	if e.err != nil {
+		return pk, vk, e.err
+	}
+	return e.pk.(PK), e.vk.(VK), nil
+}
+
+func setupGroth16(ccs constraint.ConstraintSystem) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	return cachedSetup(ccs, backend.GROTH16, func() (groth16.ProvingKey, groth16.VerifyingKey, error) {
+		return groth16.Setup(ccs)
+	})
+}
+
+func setupPlonk(ccs constraint.ConstraintSystem) (plonk.ProvingKey, plonk.VerifyingKey, error) {
+	return cachedSetup(ccs, backend.PLONK, func() (plonk.ProvingKey, plonk.VerifyingKey, error) {
+		srs, err := NewKZGSRS(ccs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return plonk.Setup(ccs, srs)
+	})
+}
+
+func setupPlonkFRI(ccs constraint.ConstraintSystem) (plonkfri.ProvingKey, plonkfri.VerifyingKey, error) {
+	return cachedSetup(ccs, backend.PLONKFRI, func() (plonkfri.ProvingKey, plonkfri.VerifyingKey, error) {
+		return plonkfri.Setup(ccs)
+	})
+}
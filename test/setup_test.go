@@ -0,0 +1,50 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedSetup(t *testing.T) {
+	assert := require.New(t)
+
+	ccs1, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+	ccs2, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+
+	// the concurrent calls run the setup once
+	var mu sync.Mutex
+	nbSetups := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pk, vk, err := cachedSetup(ccs1, backend.UNKNOWN, func() (int, int, error) {
+				mu.Lock()
+				nbSetups++
+				mu.Unlock()
+				return 1, 2, nil
+			})
+			assert.NoError(err)
+			assert.Equal(1, pk)
+			assert.Equal(2, vk)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(1, nbSetups)
+
+	// two compilations of a circuit share the keys
+	pk1, vk1, err := setupGroth16(ccs1)
+	assert.NoError(err)
+	pk2, vk2, err := setupGroth16(ccs2)
+	assert.NoError(err)
+	assert.True(pk1 == pk2 && vk1 == vk2)
+}
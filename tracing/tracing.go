@@ -0,0 +1,59 @@
+// Package tracing instruments the major phases of gnark (compile, setup, solve,
+// FFT, MSM, pairing check) with OpenTelemetry spans.
+//
+// Tracing is disabled by default. It is enabled globally with SetTracerProvider,
+// or per call with backend.WithTracing and frontend.WithTracing, which also set the
+// context holding the parent span.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracers created by gnark.
+const InstrumentationName = "github.com/consensys/gnark"
+
+var provider trace.TracerProvider = trace.NewNoopTracerProvider()
+
+// SetTracerProvider sets the tracer provider used when none is given in the
+// options of a call.
+func SetTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		tp = trace.NewNoopTracerProvider()
+	}
+	provider = tp
+}
+
+// Disable disables tracing, unless a tracer provider is given in the options of a call.
+func Disable() {
+	provider = trace.NewNoopTracerProvider()
+}
+
+// Tracer returns a tracer from tp, or from the global tracer provider if tp is nil.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = provider
+	}
+	return tp.Tracer(InstrumentationName)
+}
+
+// Start starts a span named name, child of the span in ctx (if any). ctx may be nil.
+func Start(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, recording err if it isn't nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
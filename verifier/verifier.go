@@ -0,0 +1,90 @@
+// Package verifier provides a stripped API to verify BN254 Groth16 and PLONK
+// proofs from their binary encodings, for the programs which only verify
+// proofs.
+//
+// Built with the gnark_verifier_only tag, the curve packages of the backends
+// only compile their verification code paths: the provers, the setups and the
+// constraint systems they depend on are left out of the binary, which keeps it
+// small enough for embedded targets:
+//
+//	go build -tags gnark_verifier_only -trimpath -ldflags="-s -w" ./cmd/myverifier
+//
+// With this tag, only this package and the verification part of the backends
+// (backend/groth16/bn254 and backend/plonk/bn254) can be used; the frontend and
+// the curve-agnostic backend packages don't build.
+package verifier
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	groth16 "github.com/consensys/gnark/backend/groth16/bn254"
+	plonk "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// Groth16 verifies the BN254 Groth16 proof with the verifying key and the public
+// witness, all in their binary encoding.
+func Groth16(vk, proof, publicWitness []byte) error {
+	var _vk groth16.VerifyingKey
+	if _, err := _vk.ReadFrom(bytes.NewReader(vk)); err != nil {
+		return err
+	}
+	var _proof groth16.Proof
+	if _, err := _proof.ReadFrom(bytes.NewReader(proof)); err != nil {
+		return err
+	}
+	w, err := readPublicWitness(publicWitness)
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(&_proof, &_vk, w)
+}
+
+// PLONK verifies the BN254 PLONK proof with the verifying key and the public
+// witness, all in their binary encoding. The KZG SRS of the verifying key isn't
+// serialized and is given in srs; only its first G1 point and its G2 points are
+// used, so it can be trimmed to a single G1 point.
+func PLONK(vk, proof, publicWitness, srs []byte) error {
+	var _vk plonk.VerifyingKey
+	if _, err := _vk.ReadFrom(bytes.NewReader(vk)); err != nil {
+		return err
+	}
+	var _srs kzg.SRS
+	if _, err := _srs.ReadFrom(bytes.NewReader(srs)); err != nil {
+		return err
+	}
+	if len(_srs.G1) == 0 {
+		return errors.New("empty kzg srs")
+	}
+	// vk.InitKZG requires the full SRS, which isn't needed to verify
+	_vk.KZGSRS = &_srs
+
+	var _proof plonk.Proof
+	if _, err := _proof.ReadFrom(bytes.NewReader(proof)); err != nil {
+		return err
+	}
+	w, err := readPublicWitness(publicWitness)
+	if err != nil {
+		return err
+	}
+	return plonk.Verify(&_proof, &_vk, w)
+}
+
+func readPublicWitness(publicWitness []byte) (fr.Vector, error) {
+	w, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(publicWitness); err != nil {
+		return nil, err
+	}
+	v, ok := w.Vector().(fr.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	return v, nil
+}
@@ -0,0 +1,34 @@
+package verifier_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifierOnlyDependencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go list")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	assert := require.New(t)
+
+	out, err := exec.Command(goBin, "list", "-tags", "gnark_verifier_only", "-deps", "github.com/consensys/gnark/verifier").CombinedOutput()
+	assert.NoError(err, string(out))
+	deps := strings.Fields(string(out))
+	assert.Contains(deps, "github.com/consensys/gnark/backend/groth16/bn254")
+
+	// the provers, the setups and the compiler aren't built
+	for _, pkg := range []string{
+		"github.com/consensys/gnark/constraint/bn254",
+		"github.com/consensys/gnark/frontend",
+		"github.com/consensys/gnark/metrics",
+	} {
+		assert.NotContains(deps, pkg)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build js && wasm
+
+// Command verifier is the WebAssembly binary registering the verifiers of
+// package wasm in the JavaScript global scope.
+package main
+
+import "github.com/consensys/gnark/wasm"
+
+func main() {
+	wasm.Register()
+	// keep the functions available
+	select {}
+}
@@ -0,0 +1,11 @@
+// Package wasm exposes the verification of gnark proofs to JavaScript, so that
+// browsers can verify proofs client-side.
+//
+// The functions of this package only use the BN254 verifiers and their
+// serialization, without the prover nor the other curves, to keep the
+// WebAssembly binary small (see package verifier and its gnark_verifier_only
+// build tag). Compiled with GOOS=js GOARCH=wasm, Register sets them in the
+// JavaScript global scope; see wasm/cmd/verifier for a ready to build binary:
+//
+//	GOOS=js GOARCH=wasm go build -tags gnark_verifier_only -trimpath -ldflags="-s -w" -o verifier.wasm ./wasm/cmd/verifier
+package wasm
@@ -0,0 +1,57 @@
+//go:build js && wasm
+
+package wasm
+
+import (
+	"syscall/js"
+)
+
+// Register sets the following functions in the JavaScript global scope, the
+// byte arrays being Uint8Array:
+//
+//	gnarkVerifyGroth16(vk, proof, publicWitness) -> {valid: bool, error?: string}
+//	gnarkVerifyPLONK(vk, proof, publicWitness, srs) -> {valid: bool, error?: string}
+//	gnarkParseWitness(schemaJSON, witnessJSON: string) -> {witness?: Uint8Array, error?: string}
+//
+// See VerifyGroth16, VerifyPLONK and ParseWitness.
+func Register() {
+	global := js.Global()
+	global.Set("gnarkVerifyGroth16", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 3 {
+			return verifyResult(errNbArguments)
+		}
+		return verifyResult(VerifyGroth16(bytesOf(args[0]), bytesOf(args[1]), bytesOf(args[2])))
+	}))
+	global.Set("gnarkVerifyPLONK", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 4 {
+			return verifyResult(errNbArguments)
+		}
+		return verifyResult(VerifyPLONK(bytesOf(args[0]), bytesOf(args[1]), bytesOf(args[2]), bytesOf(args[3])))
+	}))
+	global.Set("gnarkParseWitness", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 2 {
+			return map[string]any{"error": errNbArguments.Error()}
+		}
+		w, err := ParseWitness([]byte(args[0].String()), []byte(args[1].String()))
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		res := js.Global().Get("Uint8Array").New(len(w))
+		js.CopyBytesToJS(res, w)
+		return map[string]any{"witness": res}
+	}))
+}
+
+// bytesOf copies the Uint8Array v.
+func bytesOf(v js.Value) []byte {
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
+
+func verifyResult(err error) map[string]any {
+	if err != nil {
+		return map[string]any{"valid": false, "error": err.Error()}
+	}
+	return map[string]any{"valid": true}
+}
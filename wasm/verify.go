@@ -0,0 +1,44 @@
+package wasm
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/verifier"
+)
+
+var errNbArguments = errors.New("invalid number of arguments")
+
+// VerifyGroth16 verifies the BN254 Groth16 proof with the verifying key and
+// the public witness, all in their binary encoding, see verifier.Groth16.
+func VerifyGroth16(vk, proof, publicWitness []byte) error {
+	return verifier.Groth16(vk, proof, publicWitness)
+}
+
+// VerifyPLONK verifies the BN254 PLONK proof with the verifying key and the
+// public witness, all in their binary encoding. The KZG SRS of the verifying
+// key isn't serialized and is given in srs, see verifier.PLONK.
+func VerifyPLONK(vk, proof, publicWitness, srs []byte) error {
+	return verifier.PLONK(vk, proof, publicWitness, srs)
+}
+
+// ParseWitness returns the binary encoding of the BN254 witness in JSON
+// following the JSON encoded schema of the circuit. If the secret values are
+// missing, the returned witness is the public one.
+func ParseWitness(schemaJSON, witnessJSON []byte) ([]byte, error) {
+	var s schema.Schema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		return nil, err
+	}
+	w, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.FromJSON(&s, witnessJSON); err != nil {
+		return nil, err
+	}
+	return w.MarshalBinary()
+}
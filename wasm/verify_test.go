@@ -0,0 +1,98 @@
+package wasm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/wasm"
+	"github.com/stretchr/testify/require"
+)
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestVerify(t *testing.T) {
+	assert := require.New(t)
+
+	// the public witness is parsed from JSON, as in a browser
+	s, err := frontend.NewSchema(&squareCircuit{})
+	assert.NoError(err)
+	bSchema, err := json.Marshal(s)
+	assert.NoError(err)
+	bPublic, err := wasm.ParseWitness(bSchema, []byte(`{"Y":9}`))
+	assert.NoError(err)
+	bWrongPublic, err := wasm.ParseWitness(bSchema, []byte(`{"Y":10}`))
+	assert.NoError(err)
+	_, err = wasm.ParseWitness(bSchema, []byte(`{"X":3}`))
+	assert.Error(err)
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	public, err := w.Public()
+	assert.NoError(err)
+	expected, err := public.MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(expected, bPublic)
+
+	t.Run("groth16", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		proof, err := groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+
+		var bVK, bProof bytes.Buffer
+		_, err = vk.WriteTo(&bVK)
+		assert.NoError(err)
+		_, err = proof.WriteTo(&bProof)
+		assert.NoError(err)
+
+		assert.NoError(wasm.VerifyGroth16(bVK.Bytes(), bProof.Bytes(), bPublic))
+		assert.Error(wasm.VerifyGroth16(bVK.Bytes(), bProof.Bytes(), bWrongPublic))
+	})
+
+	t.Run("plonk", func(t *testing.T) {
+		assert := require.New(t)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &squareCircuit{})
+		assert.NoError(err)
+		srs, err := test.NewKZGSRS(ccs)
+		assert.NoError(err)
+		pk, vk, err := plonk.Setup(ccs, srs)
+		assert.NoError(err)
+		proof, err := plonk.Prove(ccs, pk, w)
+		assert.NoError(err)
+
+		// only the first G1 point of the SRS is needed
+		trimmed := *srs.(*kzg.SRS)
+		trimmed.G1 = trimmed.G1[:1]
+
+		var bVK, bProof, bSRS bytes.Buffer
+		_, err = vk.WriteTo(&bVK)
+		assert.NoError(err)
+		_, err = proof.WriteTo(&bProof)
+		assert.NoError(err)
+		_, err = trimmed.WriteTo(&bSRS)
+		assert.NoError(err)
+
+		assert.NoError(wasm.VerifyPLONK(bVK.Bytes(), bProof.Bytes(), bPublic, bSRS.Bytes()))
+		assert.Error(wasm.VerifyPLONK(bVK.Bytes(), bProof.Bytes(), bWrongPublic, bSRS.Bytes()))
+	})
+}